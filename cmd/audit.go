@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os/user"
+	"time"
+
+	"github.com/terradrift-watcher/internal/state"
+)
+
+// errReasonRequired is returned by mutating commands run without --reason
+var errReasonRequired = fmt.Errorf("--reason is required, it is recorded in the audit log")
+
+// principal returns the current OS user, who is acting on the command line
+func principal() string {
+	if current, err := user.Current(); err == nil {
+		return current.Username
+	}
+	return "unknown"
+}
+
+// actor returns the name shown for who is acting: the name given with --user, or the
+// current OS user
+func actor(given string) string {
+	if given != "" {
+		return given
+	}
+	return principal()
+}
+
+// recordAudit appends an action taken on the command line to the audit log, by the current
+// OS user. A name given with --user is unverified and only recorded as ClaimedBy. The action
+// has already been taken, so failing to record it is reported but not an error.
+func recordAudit(storage state.Storage, record state.AuditRecord) {
+	record.Time = time.Now()
+	record.Principal = principal()
+	record.Via = state.AuditViaCLI
+	if err := state.AppendAudit(storage, record); err != nil {
+		log.Printf("WARNING: Failed to record '%s' in the audit log: %v", record.Action, err)
+	}
+}
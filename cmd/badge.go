@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/report"
+)
+
+var badgeOutput string
+var badgeTag string
+
+// badgeCmd represents the badge command
+var badgeCmd = &cobra.Command{
+	Use:   "badge",
+	Short: "Render a badge showing the share of projects that are drift-free",
+	Long: `Badge renders a status badge with the percentage of enabled projects whose
+latest scan in the last 30 days was clean, for READMEs and dashboards. A .json
+output is written as a shields.io endpoint badge, anything else as SVG. Set
+badge_file in the configuration to refresh a badge after every run instead.
+
+Example:
+  terradrift-watcher badge --config config.yml --output drift-free.svg
+  terradrift-watcher badge --config config.yml --tag prod --output prod.json`,
+	RunE: runBadge,
+}
+
+func init() {
+	// Add the badge command to the root command
+	rootCmd.AddCommand(badgeCmd)
+
+	badgeCmd.Flags().StringVarP(&badgeOutput, "output", "o", "", "Write the badge to this file instead of stdout (SVG, or JSON for .json)")
+	badgeCmd.Flags().StringVar(&badgeTag, "tag", "", "Only count projects with this tag")
+}
+
+// runBadge is the main execution function for the badge command
+func runBadge(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	summary, err := fleetSummary(cfg)
+	if err != nil {
+		return err
+	}
+
+	health := summary.Health
+	if badgeTag != "" {
+		health = report.Health{Name: badgeTag}
+		for _, h := range summary.HealthByTag {
+			if h.Name == badgeTag {
+				health = h
+			}
+		}
+	}
+
+	if badgeOutput == "" {
+		_, err := os.Stdout.Write(report.BadgeSVG(health))
+		return err
+	}
+	if err := writeBadge(cfg, badgeOutput, health); err != nil {
+		return err
+	}
+	fmt.Printf("Wrote %s: %s\n", badgeOutput, formatPercent(health))
+	return nil
+}
+
+// writeBadge writes the badge of a fleet or tag, as shields.io endpoint JSON for a .json path
+// and as SVG otherwise
+func writeBadge(cfg *config.Config, path string, health report.Health) error {
+	data := report.BadgeSVG(health)
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		var err error
+		if data, err = report.BadgeJSON(health); err != nil {
+			return err
+		}
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write badge: %w", err)
+	}
+	return signOutputs(cfg, path)
+}
+
+// formatPercent describes the drift-free share of a fleet or tag
+func formatPercent(health report.Health) string {
+	if health.Projects == 0 {
+		return "no scanned projects"
+	}
+	return fmt.Sprintf("%.0f%% drift-free (%d of %d projects)", health.Percent(), health.DriftFree, health.Projects)
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/detector"
+	"github.com/terradrift-watcher/internal/lock"
+)
+
+var benchRuns int
+var benchProject string
+var benchTarget time.Duration
+
+// benchCmd represents the bench command
+var benchCmd = &cobra.Command{
+	Use:   "bench",
+	Short: "Measure terraform durations and recommend concurrency and interval",
+	Long: `Bench runs terraform init and plan for each enabled project several times,
+one project after another, and reports the mean init and plan durations. From
+them it recommends the concurrency needed to scan every project within the
+target run time and a check interval that leaves room for slow runs.
+
+No notifications are sent and state is not touched.
+
+Example:
+  terradrift-watcher bench --config config.yml
+  terradrift-watcher bench --config config.yml --runs 5 --target 20m
+  terradrift-watcher bench --config config.yml --project aws-prod-vpc`,
+	RunE: runBench,
+}
+
+func init() {
+	// Add the bench command to the root command
+	rootCmd.AddCommand(benchCmd)
+
+	benchCmd.Flags().IntVar(&benchRuns, "runs", 3, "Number of times to plan each project")
+	benchCmd.Flags().StringVarP(&benchProject, "project", "p", "", "Only benchmark this project")
+	benchCmd.Flags().DurationVar(&benchTarget, "target", 30*time.Minute, "Run time the recommended concurrency should stay within")
+}
+
+// runBench is the main execution function for the bench command
+func runBench(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	// Benchmarks run terraform in the project directories, so they must not overlap a run
+	fileLock := lock.NewFileLock("")
+	if err := fileLock.Acquire(); err != nil {
+		return fmt.Errorf("failed to acquire lock: %w", err)
+	}
+	defer func() {
+		if err := fileLock.Release(); err != nil {
+			log.Printf("WARNING: Failed to release lock: %v", err)
+		}
+	}()
+
+	results, err := detector.Bench(cfg, benchRuns, benchProject)
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 {
+		fmt.Println("No enabled projects to benchmark.")
+		return nil
+	}
+
+	measured := 0
+	for _, result := range results {
+		measured += result.Runs
+	}
+	if measured == 0 {
+		return fmt.Errorf("every benchmark run failed")
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PROJECT\tRUNS\tFAILED\tINIT\tPLAN\tTOTAL\tSLOWEST")
+	for _, result := range results {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%s\t%s\t%s\t%s\n", result.Project, result.Runs, result.Failures,
+			result.InitMean.Round(time.Second), result.PlanMean.Round(time.Second),
+			result.Total().Round(time.Second), result.Slowest.Round(time.Second))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	rec := detector.RecommendSettings(cfg, results, benchTarget)
+	fmt.Printf("\nScanning all %d project(s) one after another takes about %s.\n", len(results), rec.Sweep.Round(time.Second))
+	fmt.Printf("Recommended settings for runs within %s:\n", benchTarget)
+	fmt.Printf("  concurrency: %d        # estimated run time %s\n", rec.Concurrency, rec.EstimatedRun.Round(time.Second))
+	fmt.Printf("  check_interval: \"%s\"\n", rec.CheckIntervalSetting())
+	if !rec.WithinTarget {
+		fmt.Println("The target cannot be met with the current auth profile max_concurrency limits or the slowest project.")
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/control"
+	"github.com/terradrift-watcher/internal/redact"
+	"github.com/terradrift-watcher/internal/textdiff"
+)
+
+// configDiffContext is the number of unchanged lines shown around each change
+const configDiffContext = 3
+
+// configDiffCmd represents the config-diff command
+var configDiffCmd = &cobra.Command{
+	Use:   "config-diff",
+	Short: "Show changes to the configuration file the running daemon has not loaded yet",
+	Long: `Config-diff compares the configuration the running daemon last loaded, at its
+last run or reload, with the configuration file on disk and prints the changes
+as a unified diff. The daemon picks them up at its next run; use 'reload' to
+apply a changed check_interval or schedule right away.
+
+Known secrets are masked in the output, so a changed credential shows as no
+difference.
+
+Example:
+  terradrift-watcher config-diff --config config.yml`,
+	RunE: runConfigDiff,
+}
+
+func init() {
+	// Add the config-diff command to the root command
+	rootCmd.AddCommand(configDiffCmd)
+}
+
+// runConfigDiff is the main execution function for the config-diff command
+func runConfigDiff(cmd *cobra.Command, args []string) error {
+	onDisk, err := os.ReadFile(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to read configuration: %w", err)
+	}
+
+	// An invalid file is a pending change too, which the daemon's next run would skip on
+	socketPath := control.DefaultSocketPath()
+	if cfg, err := config.LoadConfig(configFile); err != nil {
+		log.Printf("WARNING: The configuration on disk does not load, the daemon's next run will be skipped: %v", err)
+	} else {
+		socketPath = controlSocketPath(cfg)
+	}
+
+	resp, err := control.Send(socketPath, control.Request{Command: control.CommandConfig})
+	if err != nil {
+		return err
+	}
+	if !resp.OK || resp.Config == nil {
+		return fmt.Errorf("daemon refused the request: %s", resp.Message)
+	}
+	loaded := resp.Config
+	if loaded.Path != configFile {
+		log.Printf("WARNING: The daemon runs with configuration %s, comparing it with %s", loaded.Path, configFile)
+	}
+
+	diff := textdiff.Unified(
+		fmt.Sprintf("%s (loaded %s)", loaded.Path, loaded.LoadedAt.Format(time.RFC3339)),
+		fmt.Sprintf("%s (on disk)", configFile),
+		loaded.Content, string(onDisk), configDiffContext)
+	if diff == "" {
+		fmt.Printf("The daemon runs with the configuration on disk, loaded %s\n", loaded.LoadedAt.Format(time.RFC3339))
+		return nil
+	}
+	fmt.Print(redact.String(diff))
+	return nil
+}
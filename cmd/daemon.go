@@ -0,0 +1,541 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/control"
+	"github.com/terradrift-watcher/internal/detector"
+	"github.com/terradrift-watcher/internal/lock"
+	"github.com/terradrift-watcher/internal/redact"
+	"github.com/terradrift-watcher/internal/state"
+	"github.com/terradrift-watcher/internal/systemd"
+	"github.com/terradrift-watcher/internal/winsvc"
+)
+
+var daemonLogFile string
+var installUnit bool
+var unitFile string
+var unitUser string
+
+// daemonCmd represents the daemon command
+var daemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Run drift detection continuously every check_interval or on a schedule",
+	Long: `Daemon runs drift detection like 'run', then waits check_interval (default 1h)
+and runs again until it is stopped. With a cron schedule set instead, it runs at
+the scheduled times only, in the configured timezone. The configuration is
+reloaded before every run, so edits take effect without a restart. The run lock
+is only held while scanning, so other commands can be used between runs.
+
+The daemon listens on a local control socket. Use 'trigger' to scan projects
+right away, 'reload' to apply a changed check_interval or schedule immediately
+and 'status' to see what the daemon is doing. Use 'pause' and 'resume' to hold scanning
+without stopping the daemon.
+
+Under systemd the daemon reports readiness and its status to a Type=notify
+service and answers the watchdog. --install-systemd-unit writes such a unit for
+the current binary and configuration instead of starting the daemon. On Windows
+use 'service install' to run it as a Windows service.
+
+With --shard i/n the daemon only scans its share of the projects, so n daemons
+split one configuration between them. With --max-duration each run stops starting
+new scans once its budget is spent, and the skipped projects are scanned first
+next run.
+
+With adaptive_scheduling enabled the daemon also wakes up whenever a project
+becomes due, so a min_interval shorter than check_interval is honored.
+
+Example:
+  terradrift-watcher daemon --config config.yml
+  terradrift-watcher daemon --config config.yml --verbose
+  terradrift-watcher daemon --config config.yml --shard 2/5
+  terradrift-watcher daemon --config config.yml --max-duration 45m
+  sudo terradrift-watcher daemon --config /etc/terradrift/config.yml --install-systemd-unit --unit-user terradrift`,
+	RunE: runDaemon,
+}
+
+func init() {
+	// Add the daemon command to the root command
+	rootCmd.AddCommand(daemonCmd)
+
+	daemonCmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Show full terraform plan output")
+	daemonCmd.Flags().StringVar(&daemonLogFile, "log-file", "", "Append log output to this file instead of stderr")
+	daemonCmd.Flags().BoolVar(&installUnit, "install-systemd-unit", false, "Write a systemd unit running this daemon and exit")
+	daemonCmd.Flags().StringVar(&unitFile, "unit-file", systemd.DefaultUnitPath, "Where --install-systemd-unit writes the unit (- for stdout)")
+	daemonCmd.Flags().StringVar(&unitUser, "unit-user", "", "User the generated unit runs the daemon as (default the current user)")
+	daemonCmd.Flags().StringVar(&shardSpec, "shard", "", "Only scan this daemon's share of the projects, e.g. 2/5 for the second of five daemons")
+	daemonCmd.Flags().DurationVar(&maxDuration, "max-duration", 0,
+		"Time budget for each run; remaining projects are skipped and scanned first next run (e.g. 45m)")
+}
+
+// daemon is the state of a running daemon shared with the control socket handler
+type daemon struct {
+	// Scans requested with 'trigger' or due on the schedule, and schedules applied by 'reload'
+	queue   *control.ScanQueue
+	reloads chan runSchedule
+
+	mu     sync.Mutex
+	status control.Status
+	loaded control.LoadedConfig // The configuration of the last run or reload
+}
+
+// runDaemon is the main execution function for the daemon command
+func runDaemon(cmd *cobra.Command, args []string) error {
+	if installUnit {
+		return installSystemdUnit()
+	}
+	if daemonLogFile != "" {
+		file, err := os.OpenFile(daemonLogFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		if err != nil {
+			return fmt.Errorf("failed to open log file: %w", err)
+		}
+		defer file.Close()
+		log.SetOutput(redact.Writer(file))
+	}
+
+	// Started by the Windows service control manager, which also stops it
+	if isService, err := winsvc.IsService(); err != nil {
+		return fmt.Errorf("failed to detect the Windows service environment: %w", err)
+	} else if isService {
+		return winsvc.Run(winsvc.DefaultName, serveDaemon)
+	}
+	return serveDaemon(nil)
+}
+
+// runSchedule returns when the daemon runs next after a run at last
+type runSchedule func(last time.Time) time.Time
+
+// daemonSchedule returns the run schedule of the configuration and a description of it
+func daemonSchedule(cfg *config.Config) (runSchedule, string, error) {
+	var next runSchedule
+	var description string
+	if schedule, err := cfg.RunSchedule(); err != nil {
+		return nil, "", fmt.Errorf("invalid schedule: %w", err)
+	} else if schedule != nil {
+		next, description = schedule.Next, fmt.Sprintf("on schedule %s", schedule)
+	} else {
+		interval, err := cfg.Interval()
+		if err != nil {
+			return nil, "", err
+		}
+		next = func(last time.Time) time.Time { return last.Add(interval) }
+		description = fmt.Sprintf("every %v", interval)
+	}
+	if cfg.AdaptiveScheduling == nil || !cfg.AdaptiveScheduling.Enabled {
+		return next, description, nil
+	}
+
+	// Wake up early when adaptive scheduling makes a project due before the next run
+	return func(last time.Time) time.Time {
+		scheduled := next(last)
+		due, err := detector.NextDue(cfg, last)
+		if err != nil {
+			log.Printf("WARNING: Failed to read when projects are due: %v", err)
+			return scheduled
+		}
+		if !due.IsZero() && due.Before(scheduled) {
+			return due
+		}
+		return scheduled
+	}, description + " and whenever a project is due", nil
+}
+
+// serveDaemon runs drift detection every check_interval until it is signaled or shutdown is
+// closed
+func serveDaemon(shutdown <-chan struct{}) error {
+	if verbose {
+		os.Setenv("TERRADRIFT_VERBOSE", "true")
+		log.Println("INFO: Verbose mode enabled - will show full plan output")
+	}
+
+	if _, err := selectedShard(); err != nil {
+		return err
+	}
+	// Refuse to start with a broken configuration; later reload errors only skip a run
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if shardSpec != "" {
+		log.Printf("INFO: Scanning shard %s of the projects", shardSpec)
+	}
+	for _, warning := range detector.RuntimeWarnings(cfg, lock.NewFileLock("").Dir()) {
+		log.Printf("WARNING: %s", warning)
+	}
+
+	d := &daemon{
+		queue:   control.NewScanQueue(),
+		reloads: make(chan runSchedule, 1),
+		status:  control.Status{PID: os.Getpid(), StartedAt: time.Now(), Config: configFile},
+	}
+	d.setLoaded(cfg)
+	var group string
+	if cfg.ControlSocket != nil {
+		group = cfg.ControlSocket.Group
+	}
+	listener, err := control.Listen(controlSocketPath(cfg), group, d.handle)
+	if err != nil {
+		return err
+	}
+	defer listener.Close()
+
+	// Stop between runs; a signal during a run ends the process from the run's own handler
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(stop)
+
+	stopWatchdog, err := systemd.StartWatchdog()
+	if err != nil {
+		log.Printf("WARNING: systemd watchdog disabled: %v", err)
+	}
+	defer stopWatchdog()
+	if _, err := systemd.Notify(systemd.Ready, systemd.Status("Starting first run")); err != nil {
+		log.Printf("WARNING: %v", err)
+	}
+
+	schedule, description, err := daemonSchedule(cfg)
+	if err != nil {
+		return err
+	}
+	// On a cron schedule the first run waits for its time too, as it may be outside office hours
+	next := time.Now()
+	var lastRun time.Time
+	if cfg.Schedule != "" {
+		lastRun = next
+		next = schedule(lastRun)
+	}
+	log.Printf("INFO: Starting daemon with configuration %s, control socket %s, running %s", configFile, listener.Addr(), description)
+	for {
+		d.setNextRun(next)
+		select {
+		case <-time.After(time.Until(next)):
+			d.queue.Schedule()
+		case <-d.queue.Ready():
+		case sig := <-stop:
+			log.Printf("INFO: Received signal %v, stopping daemon", sig)
+			systemd.Notify(systemd.Stopping)
+			return nil
+		case <-shutdown:
+			log.Printf("INFO: Service stopped, stopping daemon")
+			return nil
+		case schedule = <-d.reloads:
+			if !lastRun.IsZero() {
+				next = schedule(lastRun)
+				log.Printf("INFO: Configuration reloaded, next run at %s", next.Format(time.RFC3339))
+			}
+			continue
+		}
+		// Triggered scans run first; a due scheduled run waits behind them
+		projects, scheduled := d.queue.Next()
+		if projects == nil && !scheduled {
+			continue
+		}
+		if projects != nil {
+			log.Printf("INFO: Running triggered scan of %s", strings.Join(projects, ", "))
+		}
+		d.setScanning(time.Now())
+		systemd.Notify(systemd.Status("Scanning"))
+		runSchedule, err := d.run(projects)
+		d.setScanning(time.Time{})
+		if err != nil {
+			log.Printf("ERROR: %v", err)
+		}
+		if runSchedule != nil {
+			schedule = runSchedule
+		}
+		if scheduled {
+			lastRun = time.Now()
+			next = schedule(lastRun)
+			log.Printf("INFO: Next run at %s", next.Format(time.RFC3339))
+		}
+		systemd.Notify(systemd.Status("Idle, next run at %s", next.Format(time.RFC3339)))
+	}
+}
+
+// installSystemdUnit writes a unit running the daemon with this binary and configuration
+func installSystemdUnit() error {
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate the terradrift-watcher binary: %w", err)
+	}
+	// Validate the configuration now rather than on the first start
+	if _, err := loadShardConfig(); err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	absConfig, err := filepath.Abs(configFile)
+	if err != nil {
+		return err
+	}
+	if unitUser == "" {
+		if current, err := user.Current(); err == nil {
+			unitUser = current.Username
+		}
+	}
+	if unitUser == "root" {
+		unitUser = ""
+	}
+
+	unit := systemd.Unit{
+		Executable: executable,
+		ConfigFile: absConfig,
+		WorkingDir: filepath.Dir(absConfig),
+		User:       unitUser,
+		Watchdog:   systemd.DefaultWatchdog,
+		Shard:      shardSpec,
+
+		MaxDuration: maxDuration,
+	}.Render()
+	if unitFile == "-" {
+		fmt.Print(unit)
+		return nil
+	}
+	if err := os.WriteFile(unitFile, []byte(unit), 0644); err != nil {
+		return fmt.Errorf("failed to write unit: %w", err)
+	}
+
+	name := filepath.Base(unitFile)
+	fmt.Printf("Wrote %s. Enable and start it with:\n", unitFile)
+	fmt.Printf("  systemctl daemon-reload\n  systemctl enable --now %s\n", name)
+	return nil
+}
+
+// setNextRun records when the next scheduled run starts
+func (d *daemon) setNextRun(next time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.status.NextRun = next
+}
+
+// setLoaded records the configuration the daemon runs with
+func (d *daemon) setLoaded(cfg *config.Config) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.loaded = control.LoadedConfig{Path: configFile, LoadedAt: time.Now(), Content: string(cfg.Source)}
+}
+
+// setScanning records the start of a run, or its end when startedAt is zero
+func (d *daemon) setScanning(startedAt time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.status.Scanning = !startedAt.IsZero()
+	d.status.RunStartedAt = startedAt
+}
+
+// handle answers a control socket request
+func (d *daemon) handle(req control.Request) control.Response {
+	// Check against the configuration the next run will load
+	cfg, err := config.LoadConfig(configFile)
+	shard, _ := selectedShard()
+	if err != nil && req.Command != control.CommandStatus && req.Command != control.CommandConfig {
+		return control.Response{Message: fmt.Sprintf("failed to load configuration: %v", err)}
+	}
+
+	// Mutating commands are recorded in the audit log with who sent them and why
+	switch req.Command {
+	case control.CommandTrigger, control.CommandPause, control.CommandResume:
+		if req.Reason == "" {
+			return control.Response{Message: "a reason is required, it is recorded in the audit log"}
+		}
+		if req.Principal == "" {
+			return control.Response{Message: "the client could not be identified for the audit log"}
+		}
+	}
+
+	switch req.Command {
+	case control.CommandStatus:
+		d.mu.Lock()
+		status := d.status
+		d.mu.Unlock()
+		status.Queued = d.queue.Queued()
+		if cfg != nil {
+			if storage, err := state.Open(cfg); err == nil {
+				if pause, err := state.LoadPause(storage); err == nil && pause.Active(time.Now()) {
+					status.Paused = pause.String()
+				}
+			}
+			if summary, err := fleetSummary(cfg); err == nil {
+				status.Health = make(map[string]int, len(summary.Projects))
+				for _, stats := range summary.Projects {
+					status.Health[stats.Project] = stats.Score
+				}
+			}
+		}
+		return control.Response{OK: true, Status: &status}
+
+	case control.CommandTrigger:
+		if len(req.Projects) == 0 {
+			return control.Response{Message: "no projects to scan"}
+		}
+		for _, name := range req.Projects {
+			var project *config.Project
+			for i := range cfg.Projects {
+				if cfg.Projects[i].Name == name {
+					project = &cfg.Projects[i]
+				}
+			}
+			if project == nil {
+				return control.Response{Message: fmt.Sprintf("project '%s' not found in configuration", name)}
+			}
+			if shard != nil && !shard.Contains(*project) {
+				return control.Response{Message: fmt.Sprintf("project '%s' is not in this daemon's shard %s", name, shard)}
+			}
+		}
+		queued, duplicates := d.queue.Trigger(req.Projects)
+		auditRequest(cfg, req, state.AuditTrigger, "")
+		var parts []string
+		if len(queued) > 0 {
+			parts = append(parts, "scan queued for "+strings.Join(queued, ", "))
+		}
+		if len(duplicates) > 0 {
+			parts = append(parts, "already queued: "+strings.Join(duplicates, ", "))
+		}
+		return control.Response{OK: true, Message: strings.Join(parts, "; ")}
+
+	case control.CommandPause, control.CommandResume:
+		storage, err := state.Open(cfg)
+		if err != nil {
+			return control.Response{Message: err.Error()}
+		}
+		if req.Command == control.CommandResume {
+			if err := state.ClearPause(storage); err != nil {
+				return control.Response{Message: err.Error()}
+			}
+			log.Printf("INFO: Scanning resumed over the control socket")
+			auditRequest(cfg, req, state.AuditResume, "")
+			return control.Response{OK: true, Message: "scanning resumed"}
+		}
+		pause := state.Pause{Since: time.Now(), Reason: req.Reason}
+		if req.For != "" {
+			duration, err := config.ParseDuration(req.For)
+			if err != nil || duration <= 0 {
+				return control.Response{Message: fmt.Sprintf("invalid pause duration: %s", req.For)}
+			}
+			pause.Until = pause.Since.Add(duration)
+		}
+		if err := state.SavePause(storage, pause); err != nil {
+			return control.Response{Message: err.Error()}
+		}
+		log.Printf("INFO: Scanning %s", pause.String())
+		auditRequest(cfg, req, state.AuditPause, pauseSpan(pause))
+		return control.Response{OK: true, Message: "scanning " + pause.String()}
+
+	case control.CommandReload:
+		schedule, description, err := daemonSchedule(cfg)
+		if err != nil {
+			return control.Response{Message: err.Error()}
+		}
+		// Only the latest schedule matters, so replace one not yet applied
+		select {
+		case <-d.reloads:
+		default:
+		}
+		d.reloads <- schedule
+		d.setLoaded(cfg)
+		return control.Response{OK: true, Message: "configuration is valid, running " + description}
+
+	case control.CommandConfig:
+		d.mu.Lock()
+		loaded := d.loaded
+		d.mu.Unlock()
+		return control.Response{OK: true, Config: &loaded}
+
+	case control.CommandIncidents:
+		incidents, err := loadIncidents(cfg, req.Since, req.Projects)
+		if err != nil {
+			return control.Response{Message: err.Error()}
+		}
+		return control.Response{OK: true, Incidents: incidents}
+
+	default:
+		return control.Response{Message: fmt.Sprintf("unknown command: %s", req.Command)}
+	}
+}
+
+// auditRequest records a mutating control socket request in the audit log, by the OS user of
+// the client with whoever the client claims to act for as a note
+func auditRequest(cfg *config.Config, req control.Request, action, detail string) {
+	storage, err := state.Open(cfg)
+	if err == nil {
+		err = state.AppendAudit(storage, state.AuditRecord{
+			Time:      time.Now(),
+			Principal: req.Principal,
+			ClaimedBy: req.ClaimedBy,
+			Action:    action,
+			Projects:  req.Projects,
+			Reason:    req.Reason,
+			Detail:    detail,
+			Via:       state.AuditViaSocket,
+		})
+	}
+	if err != nil {
+		log.Printf("WARNING: Failed to record '%s' in the audit log: %v", action, err)
+	}
+}
+
+// controlSocketPath returns where the daemon of the configuration listens
+func controlSocketPath(cfg *config.Config) string {
+	if cfg.ControlSocket != nil && cfg.ControlSocket.Path != "" {
+		return cfg.ControlSocket.Path
+	}
+	return control.DefaultSocketPath()
+}
+
+// sendControl sends a request to the daemon of the configuration and fails when it is refused
+func sendControl(req control.Request) (control.Response, error) {
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return control.Response{}, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	resp, err := control.Send(controlSocketPath(cfg), req)
+	if err != nil {
+		return resp, err
+	}
+	if !resp.OK {
+		return resp, fmt.Errorf("daemon refused the request: %s", resp.Message)
+	}
+	return resp, nil
+}
+
+// run reloads the configuration and runs drift detection once, for the given projects or all
+// of them, returning the configured run schedule
+func (d *daemon) run(projects []string) (runSchedule, error) {
+	cfg, err := loadShardConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration, skipping run: %w", err)
+	}
+	d.setLoaded(cfg)
+	schedule, _, err := daemonSchedule(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	fileLock := lock.NewFileLock("")
+	if err := fileLock.Acquire(); err != nil {
+		return schedule, fmt.Errorf("failed to acquire lock, skipping run: %w", err)
+	}
+	defer func() {
+		if err := fileLock.Release(); err != nil {
+			log.Printf("WARNING: Failed to release lock: %v", err)
+		}
+	}()
+
+	report, err := detector.RunWithOptions(cfg, detector.Options{Projects: projects, MaxDuration: maxDuration})
+	if report != nil && report.Paused == nil {
+		writeRunOutputs(cfg, report)
+	}
+	if err != nil {
+		return schedule, fmt.Errorf("drift detection failed: %w", err)
+	}
+	return schedule, nil
+}
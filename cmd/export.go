@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/detector"
+	"github.com/terradrift-watcher/internal/export"
+	"github.com/terradrift-watcher/internal/state"
+)
+
+var exportFormat string
+var exportOutput string
+var exportPublish bool
+
+// exportCmd represents the export command
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the current drift state of every project for policy engines",
+	Long: `Export writes whether each configured project is currently drifted, as a JSON
+document or as an Open Policy Agent bundle, so admission controllers and other
+policy systems can refuse to deploy to a drifted stack. Bundles hold the document
+as data.terradrift. Set drift_export in the configuration to publish it after
+every run instead, or use --publish to send it to that destination now.
+
+Example:
+  terradrift-watcher export --config config.yml
+  terradrift-watcher export --config config.yml --format bundle --output drift.tar.gz
+  terradrift-watcher export --config config.yml --publish`,
+	RunE: runExport,
+}
+
+func init() {
+	// Add the export command to the root command
+	rootCmd.AddCommand(exportCmd)
+
+	exportCmd.Flags().StringVar(&exportFormat, "format", config.ExportJSON, "Output format: json or bundle")
+	exportCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "Write the export to this file instead of stdout")
+	exportCmd.Flags().BoolVar(&exportPublish, "publish", false, "Publish to the drift_export destination of the configuration")
+}
+
+// runExport is the main execution function for the export command
+func runExport(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if exportPublish {
+		if cfg.DriftExport == nil {
+			return fmt.Errorf("--publish requires drift_export in the configuration")
+		}
+		if err := publishDriftExport(cfg); err != nil {
+			return err
+		}
+		fmt.Printf("Published drift export to %s\n", export.Describe(cfg.DriftExport.Destination))
+		return nil
+	}
+	if exportFormat != config.ExportJSON && exportFormat != config.ExportBundle {
+		return fmt.Errorf("unknown format %s (supported: %s, %s)", exportFormat, config.ExportJSON, config.ExportBundle)
+	}
+
+	data, err := encodeDriftExport(cfg, exportFormat)
+	if err != nil {
+		return err
+	}
+	if exportOutput == "" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(exportOutput, data, 0644); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	return signOutputs(cfg, exportOutput)
+}
+
+// publishDriftExport sends the current drift state to the configured destination
+func publishDriftExport(cfg *config.Config) error {
+	data, err := encodeDriftExport(cfg, cfg.DriftExport.Format)
+	if err != nil {
+		return err
+	}
+	e := cfg.DriftExport
+	client, err := detector.HTTPClient(cfg, e.TLSCertFile, e.TLSKeyFile, e.TLSCAFile)
+	if err != nil {
+		return fmt.Errorf("drift_export: %w", err)
+	}
+	client.Timeout = export.UploadTimeout
+	if err := export.Publish(cfg, client, data); err != nil {
+		return err
+	}
+	if export.IsLocal(cfg.DriftExport.Destination) {
+		if err := signOutputs(cfg, cfg.DriftExport.Destination); err != nil {
+			return err
+		}
+	}
+	log.Printf("INFO: Published drift export to %s", export.Describe(cfg.DriftExport.Destination))
+	return nil
+}
+
+// encodeDriftExport renders the stored drift state of the configured projects
+func encodeDriftExport(cfg *config.Config, format string) ([]byte, error) {
+	storage, err := state.Open(cfg)
+	if err != nil {
+		return nil, err
+	}
+	store, err := state.Load(storage)
+	if err != nil {
+		return nil, err
+	}
+	return export.Encode(export.BuildDriftData(cfg, store, time.Now()), format)
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/detector"
+)
+
+var fixtureProject string
+var fixtureExpect string
+
+// fixtureReplayCmd represents the fixture-replay command
+var fixtureReplayCmd = &cobra.Command{
+	Use:   "fixture-replay <fixtures-dir>",
+	Short: "Replay recorded plan results through the drift analysis",
+	Long: `Fixture-replay reads plan results recorded with 'run --record' and prints the
+summary, changelog, fingerprint and owners each drifted project would be
+reported with, as JSON. Terraform is not run, no notifications are sent and
+state is not touched, so fixtures can be attached to bug reports and checked
+in CI.
+
+With --expect the output is compared to a previously saved result and the
+command fails when they differ.
+
+Example:
+  terradrift-watcher run --config config.yml --record ./fixtures
+  terradrift-watcher fixture-replay --config config.yml ./fixtures > expected.json
+  terradrift-watcher fixture-replay --config config.yml ./fixtures --expect expected.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runFixtureReplay,
+}
+
+func init() {
+	// Add the fixture-replay command to the root command
+	rootCmd.AddCommand(fixtureReplayCmd)
+
+	fixtureReplayCmd.Flags().StringVarP(&fixtureProject, "project", "p", "", "Only replay the fixture of this project")
+	fixtureReplayCmd.Flags().StringVar(&fixtureExpect, "expect", "", "Fail unless the output matches this file")
+}
+
+// runFixtureReplay is the main execution function for the fixture-replay command
+func runFixtureReplay(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	results, err := detector.ReplayFixtures(cfg, args[0], fixtureProject)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode results: %w", err)
+	}
+	data = append(data, '\n')
+
+	if fixtureExpect == "" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+
+	expected, err := os.ReadFile(fixtureExpect)
+	if err != nil {
+		return fmt.Errorf("failed to read expected results: %w", err)
+	}
+	// Saved results may have been checked out with Windows line endings
+	expected = bytes.ReplaceAll(expected, []byte("\r\n"), []byte("\n"))
+	if !bytes.Equal(bytes.TrimSpace(expected), bytes.TrimSpace(data)) {
+		os.Stdout.Write(data)
+		return fmt.Errorf("replayed results differ from %s", fixtureExpect)
+	}
+	fmt.Printf("Replayed %d project(s), results match %s\n", len(results), fixtureExpect)
+	return nil
+}
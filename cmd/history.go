@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/report"
+	"github.com/terradrift-watcher/internal/state"
+)
+
+var historyProject string
+var historySince string
+var historyChanges bool
+var historyAudit bool
+
+// historyCmd represents the history command
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show recorded scan history and remediation times",
+	Long: `History lists the scans recorded by previous runs, including when drift
+first appeared and how long it took to remediate, followed by the mean
+time to remediation for the listed period.
+
+With --audit it lists the audit log instead: who triaged, paused, resumed,
+disabled or enabled projects, forced the run lock, triggered scans or applied
+remediations, when, and why.
+
+Example:
+  terradrift-watcher history --config config.yml
+  terradrift-watcher history --config config.yml --project aws-prod-vpc --since 30d
+  terradrift-watcher history --config config.yml --changes
+  terradrift-watcher history --config config.yml --audit --since 90d`,
+	RunE: runHistory,
+}
+
+func init() {
+	// Add the history command to the root command
+	rootCmd.AddCommand(historyCmd)
+
+	historyCmd.Flags().StringVarP(&historyProject, "project", "p", "", "Only show history for this project")
+	historyCmd.Flags().StringVar(&historySince, "since", "7d", "How far back to show history (e.g. 24h, 30d)")
+	historyCmd.Flags().BoolVar(&historyChanges, "changes", false, "Show the changelog of out-of-band changes for drifted scans")
+	historyCmd.Flags().BoolVar(&historyAudit, "audit", false, "Show the audit log of operators' actions instead of scans")
+}
+
+// runHistory is the main execution function for the history command
+func runHistory(cmd *cobra.Command, args []string) error {
+	window, err := config.ParseDuration(historySince)
+	if err != nil {
+		return fmt.Errorf("invalid --since: %w", err)
+	}
+
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	from := time.Now().Add(-window)
+	storage, err := state.Open(cfg)
+	if err != nil {
+		return err
+	}
+	if historyAudit {
+		return printAudit(storage, from, cfg.ProjectAliases())
+	}
+	records, err := state.LoadHistory(storage, from)
+	if err != nil {
+		return err
+	}
+	aliases := cfg.ProjectAliases()
+	state.RenameHistory(records, aliases)
+	if name, ok := aliases[historyProject]; ok {
+		historyProject = name
+	}
+
+	var shown []state.HistoryRecord
+	for _, record := range records {
+		if historyProject == "" || record.Project == historyProject {
+			shown = append(shown, record)
+		}
+	}
+
+	if len(shown) == 0 {
+		fmt.Println("No scan history recorded for this period.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TIME\tPROJECT\tSTATUS\tDRIFT SINCE\tREMEDIATED AFTER\tFINGERPRINT")
+	for _, record := range shown {
+		driftSince := "-"
+		if record.DriftSince != nil {
+			driftSince = record.DriftSince.Local().Format(time.RFC3339)
+		}
+		remediated := "-"
+		if record.TimeToRemediate > 0 {
+			remediated = record.TimeToRemediate.Round(time.Minute).String()
+		}
+		fingerprint := "-"
+		if record.Fingerprint != "" {
+			fingerprint = record.Fingerprint
+		}
+		status := record.Status
+		if record.StateVersion != "" {
+			status += fmt.Sprintf(" (state %s, local %s)", record.StateVersion, record.LocalVersion)
+		} else if record.TimeoutPhase != "" {
+			status += " (" + record.ErrorCategory + " in " + record.TimeoutPhase + ")"
+		} else if record.ErrorCategory != "" {
+			status += " (" + record.ErrorCategory + ")"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			record.Time.Local().Format(time.RFC3339), record.Project, status, driftSince, remediated, fingerprint)
+	}
+	w.Flush()
+
+	if historyChanges {
+		printHistoryChanges(shown)
+	}
+
+	summary := report.Build(shown, from, time.Now())
+	fmt.Printf("\nDrifts remediated: %d, mean time to remediation: %s\n",
+		summary.ResolvedDrifts, formatMTTR(summary.MTTR))
+
+	return nil
+}
+
+// printAudit prints the audit records since from, of --project if given under its current or
+// an earlier name
+func printAudit(storage state.Storage, from time.Time, aliases map[string]string) error {
+	records, err := state.LoadAudit(storage, from)
+	if err != nil {
+		return err
+	}
+	var shown []state.AuditRecord
+	for _, record := range records {
+		if historyProject == "" || containsProject(record.Projects, historyProject, aliases) {
+			shown = append(shown, record)
+		}
+	}
+	if len(shown) == 0 {
+		fmt.Println("No audited actions recorded for this period.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TIME\tPRINCIPAL\tACTION\tPROJECTS\tVIA\tREASON\tDETAIL")
+	for _, record := range shown {
+		projects, detail := "-", "-"
+		if len(record.Projects) > 0 {
+			projects = strings.Join(record.Projects, ",")
+		}
+		if record.Detail != "" {
+			detail = record.Detail
+		}
+		who := record.Principal
+		if record.ClaimedBy != "" {
+			who += " (for " + record.ClaimedBy + ")"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", record.Time.Local().Format(time.RFC3339),
+			who, record.Action, projects, record.Via, record.Reason, detail)
+	}
+	return w.Flush()
+}
+
+// printHistoryChanges prints the changelog recorded by each drifted scan
+func printHistoryChanges(records []state.HistoryRecord) {
+	for _, record := range records {
+		if len(record.Changes) == 0 {
+			continue
+		}
+		fmt.Printf("\nChanges in '%s' at %s:\n", record.Project, record.Time.Local().Format(time.RFC3339))
+		for _, change := range record.Changes {
+			if change.Attribute == "" {
+				fmt.Printf("  %s (%s)\n", change.Address, change.Action)
+				continue
+			}
+			fmt.Printf("  %s.%s: %s -> %s\n", change.Address, change.Attribute, change.Before, change.After)
+			if change.Description != "" {
+				fmt.Printf("      (%s) %s\n", change.Type, change.Description)
+			}
+		}
+	}
+}
+
+// formatMTTR renders a mean time to remediation for the terminal
+func formatMTTR(d time.Duration) string {
+	if d == 0 {
+		return "n/a"
+	}
+	return d.Round(time.Minute).String()
+}
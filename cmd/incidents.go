@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/state"
+)
+
+var incidentsProject string
+var incidentsSince string
+var incidentsOpen bool
+var incidentsJSON bool
+
+// defaultIncidentWindow is how far back incidents are listed by default
+const defaultIncidentWindow = "30d"
+
+// incidentsCmd represents the incidents command
+var incidentsCmd = &cobra.Command{
+	Use:   "incidents",
+	Short: "List drift incidents: open ones and those resolved recently",
+	Long: `Incidents lists the drift incidents of the configured projects. Each continuous
+drift of a project is one incident with a stable ID, from the scan that confirmed
+the drift to the clean scan that resolved it. Every alert, escalation and
+resolution message about the drift names the incident. Open incidents are listed
+first, then resolved ones, most recently opened first.
+
+Example:
+  terradrift-watcher incidents --config config.yml
+  terradrift-watcher incidents --config config.yml --open
+  terradrift-watcher incidents --config config.yml --project aws-prod-vpc --since 90d --json`,
+	RunE: runIncidents,
+}
+
+func init() {
+	// Add the incidents command to the root command
+	rootCmd.AddCommand(incidentsCmd)
+
+	incidentsCmd.Flags().StringVarP(&incidentsProject, "project", "p", "", "Only list incidents of this project")
+	incidentsCmd.Flags().StringVar(&incidentsSince, "since", defaultIncidentWindow, "How far back to list incidents (e.g. 7d)")
+	incidentsCmd.Flags().BoolVar(&incidentsOpen, "open", false, "Only list open incidents")
+	incidentsCmd.Flags().BoolVar(&incidentsJSON, "json", false, "Print the incidents as JSON")
+}
+
+// runIncidents is the main execution function for the incidents command
+func runIncidents(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	var projects []string
+	if incidentsProject != "" {
+		projects = []string{incidentsProject}
+	}
+	incidents, err := loadIncidents(cfg, incidentsSince, projects)
+	if err != nil {
+		return err
+	}
+	if incidentsOpen {
+		var open []state.Incident
+		for _, incident := range incidents {
+			if incident.State == state.IncidentOpen {
+				open = append(open, incident)
+			}
+		}
+		incidents = open
+	}
+
+	if incidentsJSON {
+		if incidents == nil {
+			incidents = []state.Incident{}
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(incidents)
+	}
+	if len(incidents) == 0 {
+		fmt.Println("No drift incidents in this period.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "INCIDENT\tPROJECT\tSTATE\tOPENED\tLAST SEEN\tRESOLVED\tSCANS\tESCALATIONS")
+	for _, incident := range incidents {
+		resolved := "-"
+		if incident.ResolvedAt != nil {
+			resolved = formatStatusTime(*incident.ResolvedAt)
+			if incident.Verification != "" {
+				resolved += " (verified)"
+			}
+		}
+		escalations := "-"
+		if len(incident.Escalations) > 0 {
+			escalations = fmt.Sprint(len(incident.Escalations))
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%d\t%s\n", incident.ID, incident.Project, incident.State,
+			formatStatusTime(incident.OpenedAt), formatStatusTime(incident.LastSeen), resolved, incident.Scans, escalations)
+	}
+	return w.Flush()
+}
+
+// loadIncidents reconstructs the incidents of the history since the given duration ago, of the
+// given projects or all of them
+func loadIncidents(cfg *config.Config, since string, projects []string) ([]state.Incident, error) {
+	if since == "" {
+		since = defaultIncidentWindow
+	}
+	window, err := config.ParseDuration(since)
+	if err != nil {
+		return nil, fmt.Errorf("invalid since: %w", err)
+	}
+	storage, err := state.Open(cfg)
+	if err != nil {
+		return nil, err
+	}
+	store, err := state.Load(storage)
+	if err != nil {
+		return nil, err
+	}
+	records, err := state.LoadHistory(storage, time.Now().Add(-window))
+	if err != nil {
+		return nil, err
+	}
+	aliases := cfg.ProjectAliases()
+	state.RenameHistory(records, aliases)
+
+	var incidents []state.Incident
+	for _, incident := range state.Incidents(records, store) {
+		if len(projects) == 0 || containsProject(projects, incident.Project, aliases) {
+			incidents = append(incidents, incident)
+		}
+	}
+	return incidents, nil
+}
+
+// containsProject reports whether the project is among the names, which may be aliases
+func containsProject(names []string, project string, aliases map[string]string) bool {
+	for _, name := range names {
+		if name == project || aliases[name] == project {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/terradrift-watcher/internal/config"
+)
+
+var lintFailOn string
+
+// lintCmd represents the lint command
+var lintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Check the configuration for security issues",
+	Long: `Lint validates the configuration and flags security issues: credentials
+written in plaintext instead of ${VAR} references, auth profile settings that
+pass risky environment variables to terraform, risky SSH runner options,
+notifier endpoints over plain http://, and config or key files other users
+can read or write.
+
+Findings are reported as info, warning or error. Lint exits non-zero when a
+finding is at least as severe as --fail-on.
+
+Example:
+  terradrift-watcher lint --config config.yml
+  terradrift-watcher lint --config config.yml --fail-on warning`,
+	RunE: runLint,
+}
+
+func init() {
+	// Add the lint command to the root command
+	rootCmd.AddCommand(lintCmd)
+
+	lintCmd.Flags().StringVar(&lintFailOn, "fail-on", config.SeverityError,
+		fmt.Sprintf("Lowest severity that fails the check (%s, or none)", strings.Join(config.Severities, ", ")))
+}
+
+// runLint is the main execution function for the lint command
+func runLint(cmd *cobra.Command, args []string) error {
+	if lintFailOn != "none" && config.SeverityRank(lintFailOn) < 0 {
+		return fmt.Errorf("invalid --fail-on '%s' (expected %s, or none)", lintFailOn, strings.Join(config.Severities, ", "))
+	}
+
+	findings, err := config.Lint(configFile)
+	if err != nil {
+		return err
+	}
+
+	if len(findings) == 0 {
+		fmt.Println("No security issues found.")
+		return nil
+	}
+
+	failing := 0
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SEVERITY\tLOCATION\tISSUE")
+	for _, finding := range findings {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", strings.ToUpper(finding.Severity), finding.Location, finding.Message)
+		if lintFailOn != "none" && config.SeverityRank(finding.Severity) >= config.SeverityRank(lintFailOn) {
+			failing++
+		}
+	}
+	w.Flush()
+
+	if failing > 0 {
+		return fmt.Errorf("%d finding(s) at or above '%s'", failing, lintFailOn)
+	}
+	return nil
+}
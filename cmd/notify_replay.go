@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/detector"
+	"github.com/terradrift-watcher/internal/lock"
+	"github.com/terradrift-watcher/internal/state"
+)
+
+var replayProject string
+var replayList bool
+
+// notifyReplayCmd represents the notify-replay command
+var notifyReplayCmd = &cobra.Command{
+	Use:   "notify-replay",
+	Short: "Re-send notifications that failed to deliver",
+	Long: `Notify-replay re-sends drift notifications that could not be delivered by
+previous runs, for example during a Slack outage. Failed notifications are
+kept in the state directory until they are delivered. Only the latest
+notification per notifier and project is kept, and drift alerts of projects
+that are drift-free by now are dropped instead of sent.
+
+Example:
+  terradrift-watcher notify-replay --config config.yml --list
+  terradrift-watcher notify-replay --config config.yml
+  terradrift-watcher notify-replay --config config.yml --project aws-prod-vpc`,
+	RunE: runNotifyReplay,
+}
+
+func init() {
+	// Add the notify-replay command to the root command
+	rootCmd.AddCommand(notifyReplayCmd)
+
+	notifyReplayCmd.Flags().StringVarP(&replayProject, "project", "p", "", "Only replay notifications for this project")
+	notifyReplayCmd.Flags().BoolVar(&replayList, "list", false, "List undelivered notifications without sending them")
+}
+
+// runNotifyReplay is the main execution function for the notify-replay command
+func runNotifyReplay(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	if replayList {
+		storage, err := state.Open(cfg)
+		if err != nil {
+			return err
+		}
+		entries, err := state.LoadOutbox(storage)
+		if err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			fmt.Println("No undelivered notifications.")
+			return nil
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "TIME\tPROJECT\tNOTIFIER\tATTEMPTS\tLAST ERROR")
+		for _, entry := range entries {
+			if replayProject != "" && entry.Alert.Project != replayProject {
+				continue
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\n", entry.Time.Local().Format(time.RFC3339),
+				entry.Alert.Project, entry.Notifier, entry.Attempts, entry.Error)
+		}
+		return w.Flush()
+	}
+
+	// Share the run lock so a concurrent run cannot append to the outbox while it is rewritten
+	fileLock := lock.NewFileLock("")
+	if err := fileLock.Acquire(); err != nil {
+		return fmt.Errorf("failed to acquire lock: %w", err)
+	}
+	defer func() {
+		if err := fileLock.Release(); err != nil {
+			log.Printf("WARNING: Failed to release lock: %v", err)
+		}
+	}()
+
+	result, err := detector.ReplayNotifications(cfg, replayProject)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Replayed %d notification(s), %d failed, %d dropped as the drift is gone, %d left in the outbox\n",
+		result.Sent, result.Failed, result.Dropped, result.Remaining)
+	if result.Failed > 0 {
+		return fmt.Errorf("%d notification(s) could not be delivered", result.Failed)
+	}
+	return nil
+}
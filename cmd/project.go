@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/state"
+)
+
+var projectUntil string
+var projectReason string
+
+// projectCmd groups the commands that change projects locally
+var projectCmd = &cobra.Command{
+	Use:   "project",
+	Short: "Disable or enable projects without editing the configuration",
+}
+
+// projectDisableCmd represents the project disable command
+var projectDisableCmd = &cobra.Command{
+	Use:   "disable <name>",
+	Short: "Stop scanning a project, indefinitely or until a time",
+	Long: `Disable stops scanning a project by writing an override to the local overrides
+file (overrides_file, by default config.overrides.yml next to config.yml), which
+is merged over the configuration whenever it is loaded. The shared configuration
+is left untouched. With --until the project is scanned again from that time on;
+a date means midnight in the configured timezone.
+
+Example:
+  terradrift-watcher project disable aws-prod-vpc --config config.yml --until 2025-01-10 --reason "Decommissioning"
+  terradrift-watcher project disable aws-prod-vpc --config config.yml --until 7d --reason "VPC migration"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runProjectDisable,
+}
+
+// projectEnableCmd represents the project enable command
+var projectEnableCmd = &cobra.Command{
+	Use:   "enable <name>",
+	Short: "Scan a project again",
+	Long: `Enable removes a project's override from the local overrides file. A project
+disabled in the configuration itself is enabled by an override instead, until
+--until if given.
+
+Example:
+  terradrift-watcher project enable aws-prod-vpc --config config.yml --reason "Migration done"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runProjectEnable,
+}
+
+func init() {
+	// Add the project commands to the root command
+	rootCmd.AddCommand(projectCmd)
+	projectCmd.AddCommand(projectDisableCmd, projectEnableCmd)
+
+	for _, c := range []*cobra.Command{projectDisableCmd, projectEnableCmd} {
+		c.Flags().StringVar(&projectUntil, "until", "",
+			"When the override ends: a date (2025-01-10), an RFC 3339 time or a duration (7d) (default until changed)")
+		c.Flags().StringVar(&projectReason, "reason", "", "Why, shown in status and recorded in the audit log (required)")
+	}
+}
+
+// runProjectDisable is the main execution function for the project disable command
+func runProjectDisable(cmd *cobra.Command, args []string) error {
+	return setProjectOverride(args[0], false)
+}
+
+// runProjectEnable is the main execution function for the project enable command
+func runProjectEnable(cmd *cobra.Command, args []string) error {
+	return setProjectOverride(args[0], true)
+}
+
+// setProjectOverride disables or enables a project in the overrides file
+func setProjectOverride(name string, enabled bool) error {
+	if projectReason == "" {
+		return errReasonRequired
+	}
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	var project *config.Project
+	for i := range cfg.Projects {
+		if cfg.Projects[i].Name == name {
+			project = &cfg.Projects[i]
+		}
+	}
+	if project == nil {
+		return fmt.Errorf("project '%s' not found in configuration", name)
+	}
+
+	now := time.Now()
+	override := config.ProjectOverride{Enabled: enabled, Since: now, Reason: projectReason}
+	if projectUntil != "" {
+		location, err := cfg.Location()
+		if err != nil {
+			return err
+		}
+		if override.Until, err = config.ParseUntil(projectUntil, location, now); err != nil {
+			return fmt.Errorf("--until: %w", err)
+		}
+		if !override.Until.After(now) {
+			return fmt.Errorf("--until %s is in the past", projectUntil)
+		}
+	}
+
+	overrides, err := config.LoadOverrides(cfg.OverridesFile)
+	if err != nil {
+		return err
+	}
+	// Overrides of earlier names would otherwise still apply
+	for _, alias := range project.Aliases {
+		delete(overrides.Projects, alias)
+	}
+	delete(overrides.Projects, project.Name)
+
+	if !enabled {
+		overrides.Projects[project.Name] = override
+	}
+	if err := config.SaveOverrides(cfg.OverridesFile, overrides, now); err != nil {
+		return err
+	}
+
+	// Enabling a project the configuration enables only needs the override gone
+	if enabled && !configEnables(project.Name) {
+		overrides.Projects[project.Name] = override
+		if err := config.SaveOverrides(cfg.OverridesFile, overrides, now); err != nil {
+			return err
+		}
+	}
+
+	action := state.AuditProjectDisable
+	if enabled {
+		action = state.AuditProjectEnable
+	}
+	var detail string
+	if !override.Until.IsZero() {
+		detail = "until " + override.Until.Format(time.RFC3339)
+	}
+	if storage, err := state.Open(cfg); err != nil {
+		log.Printf("WARNING: Failed to record '%s' in the audit log: %v", action, err)
+	} else {
+		recordAudit(storage, state.AuditRecord{Action: action, Projects: []string{project.Name}, Reason: projectReason, Detail: detail})
+	}
+
+	if _, ok := overrides.Projects[project.Name]; ok {
+		fmt.Printf("Project '%s' %s, written to %s\n", project.Name, override.String(), cfg.OverridesFile)
+	} else {
+		fmt.Printf("Project '%s' enabled, override removed from %s\n", project.Name, cfg.OverridesFile)
+	}
+	return nil
+}
+
+// configEnables reports whether the configuration, with the overrides saved so far, enables
+// the project
+func configEnables(name string) bool {
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return false
+	}
+	for _, project := range cfg.Projects {
+		if project.Name == name {
+			return project.Enabled == nil || *project.Enabled
+		}
+	}
+	return false
+}
@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/terradrift-watcher/internal/control"
+)
+
+// reloadCmd represents the reload command
+var reloadCmd = &cobra.Command{
+	Use:   "reload",
+	Short: "Ask the running daemon to reload its configuration",
+	Long: `Reload has the daemon validate its configuration and reschedule the next run
+from a changed check_interval right away. Other settings are picked up by the
+next run anyway. The control socket's own settings need a restart.
+
+Example:
+  terradrift-watcher reload --config config.yml`,
+	RunE: runReload,
+}
+
+func init() {
+	// Add the reload command to the root command
+	rootCmd.AddCommand(reloadCmd)
+}
+
+// runReload is the main execution function for the reload command
+func runReload(cmd *cobra.Command, args []string) error {
+	resp, err := sendControl(control.Request{Command: control.CommandReload})
+	if err != nil {
+		return err
+	}
+	fmt.Println(resp.Message)
+	return nil
+}
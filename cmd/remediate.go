@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/detector"
+	"github.com/terradrift-watcher/internal/lock"
+	"github.com/terradrift-watcher/internal/state"
+)
+
+var remediateApproved bool
+var remediateProject string
+var remediateYes bool
+var remediateReason string
+
+// remediateCmd represents the remediate command
+var remediateCmd = &cobra.Command{
+	Use:   "remediate",
+	Short: "Apply the kept plans of drift approved for remediation in triage",
+	Long: `Remediate reverts drift by applying the plan kept from the project's latest
+drifted scan, for the projects whose current drift was marked for remediation in
+'triage'. Plans are only kept when the remediation block is configured.
+
+Without --approved it lists the approved drift and whether a current plan is kept.
+With --approved it asks for confirmation before applying each plan (skip with
+--yes), saves terraform's output in the state storage, and then scans the
+applied projects again to verify their drift is gone. Terraform refuses a plan
+whose state changed since it was made, so only the reviewed changes are applied.
+Each apply is recorded in the audit log with --reason, which --approved requires.
+
+Example:
+  terradrift-watcher remediate --config config.yml
+  terradrift-watcher remediate --config config.yml --approved --reason "Revert console edits"
+  terradrift-watcher remediate --config config.yml --approved --project aws-prod-vpc --yes --reason "INC-1234"`,
+	RunE: runRemediate,
+}
+
+func init() {
+	// Add the remediate command to the root command
+	rootCmd.AddCommand(remediateCmd)
+
+	remediateCmd.Flags().BoolVar(&remediateApproved, "approved", false, "Apply the plans of the approved drift")
+	remediateCmd.Flags().StringVarP(&remediateProject, "project", "p", "", "Only remediate this project")
+	remediateCmd.Flags().BoolVarP(&remediateYes, "yes", "y", false, "Apply without asking for confirmation")
+	remediateCmd.Flags().StringVar(&remediateReason, "reason", "", "Why the plans are applied, recorded in the audit log (required with --approved)")
+}
+
+// remediation is approved drift with the plan kept for it
+type remediation struct {
+	project  config.Project
+	decision *state.TriageDecision
+	plan     *detector.RemediationPlan // nil when no plan of the current drift is kept
+}
+
+// runRemediate is the main execution function for the remediate command
+func runRemediate(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if cfg.Remediation == nil {
+		return fmt.Errorf("no plans are kept for remediation; configure the remediation block first")
+	}
+	if remediateApproved && remediateReason == "" {
+		return errReasonRequired
+	}
+	storage, err := state.Open(cfg)
+	if err != nil {
+		return err
+	}
+
+	remediations, err := approvedRemediations(cfg, storage, remediateProject)
+	if err != nil {
+		return err
+	}
+	if len(remediations) == 0 {
+		fmt.Println("No drift is approved for remediation. Mark drift with 'remediate' in 'terradrift-watcher triage'.")
+		return nil
+	}
+
+	if !remediateApproved {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "PROJECT\tAPPROVED BY\tPLANNED\tPLAN")
+		for _, r := range remediations {
+			planned, summary := "-", "none kept for the current drift, scan again"
+			if r.plan != nil {
+				planned = formatStatusTime(r.plan.PlannedAt)
+				summary = firstLine(r.plan.Summary)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", r.project.Name, r.decision.By, planned, summary)
+		}
+		w.Flush()
+		fmt.Println("\nApply the plans with --approved.")
+		return nil
+	}
+
+	// Plans must not be applied while a run is planning the same projects
+	fileLock := lock.NewFileLock("")
+	if err := fileLock.Acquire(); err != nil {
+		return fmt.Errorf("failed to acquire lock: %w", err)
+	}
+	defer func() {
+		if err := fileLock.Release(); err != nil {
+			log.Printf("WARNING: Failed to release lock: %v", err)
+		}
+	}()
+
+	in := bufio.NewReader(os.Stdin)
+	var applied []string
+	failed := 0
+	for _, r := range remediations {
+		if r.plan == nil {
+			fmt.Printf("Skipping '%s': no plan is kept for its current drift; scan it again first.\n", r.project.Name)
+			continue
+		}
+		fmt.Printf("\n%s (approved by %s, planned %s):\n%s\n", r.project.Name, r.decision.By,
+			formatStatusTime(r.plan.PlannedAt), r.plan.Summary)
+		if !remediateYes && !confirm(in, fmt.Sprintf("Apply the plan of '%s'?", r.project.Name)) {
+			fmt.Printf("Skipped '%s'.\n", r.project.Name)
+			continue
+		}
+
+		output, logKey, err := detector.ApplyRemediation(cfg, storage, r.project)
+		fmt.Println(output)
+		if logKey != "" {
+			fmt.Printf("Apply output of '%s' saved as %s in the state storage\n", r.project.Name, logKey)
+		}
+		detail := fmt.Sprintf("%s approved by %s, applied", r.decision.Incident, r.decision.By)
+		if err != nil {
+			detail = fmt.Sprintf("%s approved by %s, failed: %v", r.decision.Incident, r.decision.By, err)
+		}
+		recordAudit(storage, state.AuditRecord{Action: state.AuditRemediate, Projects: []string{r.project.Name},
+			Reason: remediateReason, Detail: detail})
+		if err != nil {
+			fmt.Printf("Remediation of '%s' failed: %v\n", r.project.Name, err)
+			failed++
+			continue
+		}
+		applied = append(applied, r.project.Name)
+	}
+	if len(applied) == 0 {
+		if failed > 0 {
+			return fmt.Errorf("%d remediation(s) failed", failed)
+		}
+		return nil
+	}
+
+	// The scan records the outcome and closes the incidents, or alerts the drift left
+	failed += verifyRemediation(cfg, applied, "remediate --approved")
+	if failed > 0 {
+		return fmt.Errorf("%d remediation(s) failed or were not verified", failed)
+	}
+	return nil
+}
+
+// approvedRemediations returns the drift approved for remediation in triage, of the given
+// project or all of them, with the plans kept for it
+func approvedRemediations(cfg *config.Config, storage state.Storage, only string) ([]remediation, error) {
+	store, err := state.Load(storage)
+	if err != nil {
+		return nil, err
+	}
+	triage, err := state.LoadTriage(storage)
+	if err != nil {
+		return nil, err
+	}
+
+	var remediations []remediation
+	for _, project := range cfg.Projects {
+		if only != "" && project.Name != only {
+			continue
+		}
+		decision := triage.Current(project.Name, store.Projects[project.Name])
+		if decision == nil || decision.Action != state.TriageRemediate {
+			continue
+		}
+		r := remediation{project: project, decision: decision}
+		plan, err := detector.LoadRemediationPlan(storage, project.Name)
+		if err != nil {
+			return nil, err
+		}
+		// A plan of other drift would not apply what was reviewed
+		if plan != nil && plan.Fingerprint == decision.Fingerprint {
+			r.plan = plan
+		}
+		remediations = append(remediations, r)
+	}
+	return remediations, nil
+}
+
+// confirm asks a yes/no question on the terminal, defaulting to no
+func confirm(in *bufio.Reader, question string) bool {
+	fmt.Printf("%s [y/N] ", question)
+	answer, _ := in.ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// firstLine returns the first line of a text
+func firstLine(text string) string {
+	line, _, _ := strings.Cut(text, "\n")
+	return line
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/report"
+	"github.com/terradrift-watcher/internal/state"
+)
+
+var reportPeriod string
+var reportFormat string
+var reportOutput string
+var reportSort string
+
+// reportCmd represents the report command
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Generate a periodic drift summary report from scan history",
+	Long: `Report aggregates the scan history recorded by previous runs into a summary
+for engineering reviews: most-drifting projects, mean time to remediation,
+the share of projects drift-free, and drift broken down by team and tag. Each
+project gets a health score from 0 to 100; --sort health lists the least healthy
+projects first to focus remediation effort.
+
+Example:
+  terradrift-watcher report --config config.yml --period weekly
+  terradrift-watcher report --config config.yml --period monthly --format html --output drift.html
+  terradrift-watcher report --config config.yml --period 14d
+  terradrift-watcher report --config config.yml --sort health`,
+	RunE: runReport,
+}
+
+func init() {
+	// Add the report command to the root command
+	rootCmd.AddCommand(reportCmd)
+
+	reportCmd.Flags().StringVar(&reportPeriod, "period", "weekly", "Reporting period: weekly, monthly, or a duration such as 14d")
+	reportCmd.Flags().StringVar(&reportFormat, "format", "markdown", "Output format: markdown or html")
+	reportCmd.Flags().StringVarP(&reportOutput, "output", "o", "", "Write the report to this file instead of stdout")
+	reportCmd.Flags().StringVar(&reportSort, "sort", report.SortDrift, "Order of the project health table: drift or health (least healthy first)")
+}
+
+// runReport is the main execution function for the report command
+func runReport(cmd *cobra.Command, args []string) error {
+	period, err := parseReportPeriod(reportPeriod)
+	if err != nil {
+		return err
+	}
+	if reportSort != report.SortDrift && reportSort != report.SortHealth {
+		return fmt.Errorf("unknown sort '%s' (supported: %s, %s)", reportSort, report.SortDrift, report.SortHealth)
+	}
+
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	to := time.Now()
+	from := to.Add(-period)
+
+	storage, err := state.Open(cfg)
+	if err != nil {
+		return err
+	}
+	records, err := state.LoadHistory(storage, from)
+	if err != nil {
+		return err
+	}
+	state.RenameHistory(records, cfg.ProjectAliases())
+	summary := report.Build(records, from, to)
+	summary.ComputeHealth(fleetProjects(cfg))
+	summary.MarkFlapping(cfg.FlapThreshold())
+	summary.ComputeSLOs(cfg.SLOs)
+	summary.Metadata = cfg.Metadata
+	summary.Sort = reportSort
+
+	var render func(io.Writer, *report.Summary) error
+	switch reportFormat {
+	case "markdown", "md":
+		render = report.RenderMarkdown
+	case "html":
+		render = report.RenderHTML
+	default:
+		return fmt.Errorf("unknown report format '%s' (supported: markdown, html)", reportFormat)
+	}
+
+	if reportOutput == "" {
+		return render(os.Stdout, summary)
+	}
+	file, err := os.Create(reportOutput)
+	if err != nil {
+		return fmt.Errorf("failed to create report file: %w", err)
+	}
+	if err := render(file, summary); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write report file: %w", err)
+	}
+	return signOutputs(cfg, reportOutput)
+}
+
+// parseReportPeriod converts a named period or duration into a duration
+func parseReportPeriod(period string) (time.Duration, error) {
+	switch period {
+	case "weekly":
+		return 7 * 24 * time.Hour, nil
+	case "monthly":
+		return 30 * 24 * time.Hour, nil
+	}
+
+	d, err := config.ParseDuration(period)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid period '%s' (use weekly, monthly, or a duration such as 14d)", period)
+	}
+	return d, nil
+}
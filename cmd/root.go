@@ -2,15 +2,18 @@ package cmd
 
 import (
 	"fmt"
+	"log"
 	"os"
 
 	"github.com/spf13/cobra"
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/redact"
 )
 
 var (
 	// configFile holds the path to the configuration file
 	configFile string
-	
+
 	// version information (can be set during build)
 	version = "dev"
 	commit  = "unknown"
@@ -33,18 +36,28 @@ notifications via Slack, Microsoft Teams, or email when drift is detected.`,
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
+	// Redact known secrets from everything written to stderr, including terraform errors
+	// echoed in log lines
+	stderr := redact.Writer(os.Stderr)
+	log.SetOutput(stderr)
+	rootCmd.SetErr(stderr)
+
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		fmt.Fprintf(stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 }
 
 func init() {
 	// Define persistent flags that will be available to all subcommands
-	rootCmd.PersistentFlags().StringVarP(&configFile, "config", "c", "config.yml", 
+	rootCmd.PersistentFlags().StringVarP(&configFile, "config", "c", "config.yml",
 		"Path to the configuration file")
-	
+	rootCmd.PersistentFlags().BoolVar(&config.StrictSecurity, "strict-security", false,
+		"Refuse configurations with credentials written inline instead of warning about them")
+	rootCmd.PersistentFlags().StringToStringVar(&config.MetadataFlags, "metadata", nil,
+		"Metadata identifying this watcher, e.g. --metadata cluster=eu-1 (repeatable, overrides the config)")
+
 	// Add version template
 	rootCmd.SetVersionTemplate(`{{with .Name}}{{printf "%s " .}}{{end}}{{printf "version %s" .Version}}
 `)
-} 
\ No newline at end of file
+}
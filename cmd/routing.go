@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/terradrift-watcher/internal/detector"
+	"github.com/terradrift-watcher/internal/notifier"
+	"github.com/terradrift-watcher/internal/state"
+)
+
+// runExplainRouting prints the notification routing of every enabled project's latest
+// recorded drift, or of a sample drift for projects that never drifted
+func runExplainRouting() error {
+	cfg, err := loadShardConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	storage, err := state.Open(cfg)
+	if err != nil {
+		return err
+	}
+	store, err := state.Load(storage)
+	if err != nil {
+		return err
+	}
+	store.AdoptAliases(cfg.ProjectAliases())
+	records, err := state.LoadHistory(storage, time.Time{})
+	if err != nil {
+		return err
+	}
+	state.RenameHistory(records, cfg.ProjectAliases())
+
+	latest := make(map[string]state.HistoryRecord)
+	for _, record := range records {
+		if record.Status == detector.StatusDrifted {
+			latest[record.Project] = record
+		}
+	}
+
+	now := time.Now()
+	for _, project := range cfg.Projects {
+		if project.Enabled != nil && !*project.Enabled {
+			continue
+		}
+
+		var alert notifier.DriftAlert
+		if record, ok := latest[project.Name]; ok {
+			alert = recordAlert(record)
+			fmt.Printf("Project '%s' (drift recorded at %s)\n", project.Name, record.Time.Local().Format(time.RFC3339))
+		} else {
+			alert = sampleAlert()
+			alert.Project = project.Name
+			alert.Owners = nil
+			alert.Tags = project.Tags
+			alert.Description = project.Description
+			alert.RunbookURL = project.RunbookURL
+			alert.Outputs = store.Project(project.Name).Outputs
+			alert.DriftSince = now
+			fmt.Printf("Project '%s' (sample drift, none recorded)\n", project.Name)
+		}
+
+		var projectState *state.ProjectState
+		if ps, ok := store.Projects[project.Name]; ok {
+			projectState = ps
+		}
+		printRouting(detector.ExplainRouting(cfg, project, alert, projectState, now))
+		fmt.Println()
+	}
+	return nil
+}
+
+// printRouting prints the routing decisions of a project
+func printRouting(routing detector.Routing) {
+	if routing.Window != "" {
+		fmt.Printf("  Suppression window '%s' holds back all alerts until %s\n",
+			routing.Window, routing.Until.Local().Format(time.RFC3339))
+	}
+	if routing.Correlated {
+		fmt.Println("  Drift alerts wait for fleet correlation and may be collapsed into one fleet alert")
+	}
+	if len(routing.Decisions) == 0 {
+		fmt.Println("  No notifiers: drift is only logged")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "  ALERT\tNOTIFIER\tSOURCE\tOUTCOME\tREASON")
+	for _, decision := range routing.Decisions {
+		reason := decision.Reason
+		if reason == "" {
+			reason = "-"
+		}
+		fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\n", decision.Alert, decision.Notifier, decision.Source, decision.Outcome, reason)
+	}
+	w.Flush()
+
+	if notified := routing.Notified(); len(notified) > 0 {
+		fmt.Printf("  Drift alert now goes to: %s\n", strings.Join(notified, ", "))
+	} else {
+		fmt.Println("  Drift alert now goes to: nobody")
+	}
+}
@@ -4,16 +4,31 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/terradrift-watcher/internal/config"
 	"github.com/terradrift-watcher/internal/detector"
 	"github.com/terradrift-watcher/internal/lock"
+	"github.com/terradrift-watcher/internal/metrics"
+	"github.com/terradrift-watcher/internal/report"
+	"github.com/terradrift-watcher/internal/state"
+	"github.com/terradrift-watcher/internal/terraform"
 )
 
 var verbose bool
 var failOnDrift bool
 var forceLock bool
+var forceReason string
+var maxDuration time.Duration
+var concurrency int
+var simulateDir string
+var recordDir string
+var changedSince string
+var ignorePause bool
+var explainRouting bool
+var watchPath string
+var notifySlack string
 
 // runCmd represents the run command
 var runCmd = &cobra.Command{
@@ -27,9 +42,19 @@ It will:
 3. Run 'terraform plan' to detect drift
 4. Send notifications if drift is detected
 
+With --path it watches a single directory without a config file, alerting the Slack
+webhook given with --notify-slack, e.g. to try the tool or in a one-off CI job.
+
 Example:
   terradrift-watcher run --config config.yml
-  terradrift-watcher run --config config.yml --verbose`,
+  terradrift-watcher run --config config.yml --verbose
+  terradrift-watcher run --config config.yml --max-duration 45m
+  terradrift-watcher run --config config.yml --changed-since origin/main
+  terradrift-watcher run --config config.yml --simulate ./fixtures
+  terradrift-watcher run --config config.yml --record ./fixtures
+  terradrift-watcher run --config config.yml --shard 2/5
+  terradrift-watcher run --config config.yml --explain-routing
+  terradrift-watcher run --path ./stacks/prod --notify-slack "$SLACK_WEBHOOK_URL"`,
 	RunE: runDriftDetection,
 }
 
@@ -45,10 +70,75 @@ func init() {
 
 	// Add force flag
 	runCmd.Flags().BoolVar(&forceLock, "force", false, "Force release any existing lock and proceed")
+	runCmd.Flags().StringVar(&forceReason, "reason", "", "Why the lock is forced, recorded in the audit log (required with --force)")
+
+	// Add max-duration flag
+	runCmd.Flags().DurationVar(&maxDuration, "max-duration", 0,
+		"Time budget for the whole run; remaining projects are skipped and scanned first next run (e.g. 45m)")
+
+	// Add concurrency flag
+	runCmd.Flags().IntVar(&concurrency, "concurrency", 0, "Number of projects to scan in parallel (overrides config)")
+
+	// Add changed-since flag
+	runCmd.Flags().StringVar(&changedSince, "changed-since", "",
+		"Only scan projects whose files or local modules changed since the branch point with this git ref")
+
+	// Add simulate flag
+	runCmd.Flags().StringVar(&simulateDir, "simulate", "",
+		"Return canned plan results from this fixtures directory instead of running terraform (or set "+terraform.FakeEnvVar+")")
+
+	// Add ignore-pause flag
+	runCmd.Flags().BoolVar(&ignorePause, "ignore-pause", false, "Scan even while scanning is paused")
+
+	// Add record flag
+	runCmd.Flags().StringVar(&recordDir, "record", "",
+		"Save sanitized plan results of every project to this fixtures directory for --simulate and fixture-replay")
+
+	// Add explain-routing flag
+	runCmd.Flags().BoolVar(&explainRouting, "explain-routing", false,
+		"Print which notifiers each project's latest (or a sample) drift would reach and why, without scanning")
+
+	// Add shard flag
+	runCmd.Flags().StringVar(&shardSpec, "shard", "",
+		"Only scan this instance's share of the projects, e.g. 2/5 for the second of five instances")
+
+	// Add path flag
+	runCmd.Flags().StringVar(&watchPath, "path", "",
+		"Watch this directory as the only project, without a config file")
+
+	// Add notify-slack flag
+	runCmd.Flags().StringVar(&notifySlack, "notify-slack", "",
+		"Slack incoming webhook URL alerted of drift found with --path")
 }
 
 // runDriftDetection is the main execution function for the run command
 func runDriftDetection(cmd *cobra.Command, args []string) error {
+	if simulateDir != "" && recordDir != "" {
+		return fmt.Errorf("--record and --simulate cannot be combined")
+	}
+	if _, err := selectedShard(); err != nil {
+		return err
+	}
+	if watchPath != "" {
+		if cmd.Flags().Changed("config") {
+			return fmt.Errorf("--path and --config cannot be combined")
+		}
+		if shardSpec != "" || explainRouting {
+			return fmt.Errorf("--path cannot be combined with --shard or --explain-routing")
+		}
+	} else if notifySlack != "" {
+		return fmt.Errorf("--notify-slack requires --path")
+	}
+	if explainRouting {
+		return runExplainRouting()
+	}
+	if forceLock && forceReason == "" {
+		return errReasonRequired
+	}
+	if forceReason != "" && !forceLock {
+		return fmt.Errorf("--reason requires --force")
+	}
+
 	// Create and acquire lock
 	fileLock := lock.NewFileLock("")
 
@@ -69,7 +159,11 @@ func runDriftDetection(cmd *cobra.Command, args []string) error {
 		}
 	}()
 
-	log.Printf("INFO: Loading configuration from %s", configFile)
+	if watchPath != "" {
+		log.Printf("INFO: Watching %s without a config file", watchPath)
+	} else {
+		log.Printf("INFO: Loading configuration from %s", configFile)
+	}
 
 	// Set verbose mode in environment for detector to use
 	if verbose {
@@ -78,22 +172,50 @@ func runDriftDetection(cmd *cobra.Command, args []string) error {
 	}
 
 	// Load the configuration
-	cfg, err := config.LoadConfig(configFile)
+	cfg, err := loadRunConfig()
 	if err != nil {
 		return fmt.Errorf("failed to load configuration: %w", err)
 	}
 
 	log.Printf("INFO: Configuration loaded successfully")
+	if forceLock {
+		if storage, err := state.Open(cfg); err != nil {
+			log.Printf("WARNING: Failed to record '%s' in the audit log: %v", state.AuditForceUnlock, err)
+		} else {
+			recordAudit(storage, state.AuditRecord{Action: state.AuditForceUnlock, Reason: forceReason, Detail: "run lock in " + fileLock.Dir()})
+		}
+	}
+	if shardSpec != "" {
+		log.Printf("INFO: Scanning shard %s of the projects", shardSpec)
+	}
 	log.Printf("INFO: Found %d projects, %d auth profiles, and %d notifiers",
 		len(cfg.Projects), len(cfg.AuthProfiles), len(cfg.Notifiers))
 
+	for _, warning := range detector.RuntimeWarnings(cfg, fileLock.Dir()) {
+		log.Printf("WARNING: %s", warning)
+	}
+
 	// Run the drift detection process
-	driftFound, runErr := detector.RunWithResult(cfg)
+	report, runErr := detector.RunWithOptions(cfg, detector.Options{
+		MaxDuration:  maxDuration,
+		Concurrency:  concurrency,
+		Simulate:     simulateDir,
+		Record:       recordDir,
+		ChangedSince: changedSince,
+		IgnorePause:  ignorePause,
+	})
+
+	// Export metrics even when some projects failed, since failures are part of the picture.
+	// A paused run scanned nothing, so the last exported results stay.
+	if report != nil && report.Paused == nil {
+		writeRunOutputs(cfg, report)
+	}
+
 	if runErr != nil {
 		return fmt.Errorf("drift detection failed: %w", runErr)
 	}
 
-	if driftFound && failOnDrift {
+	if report.DriftFound() && failOnDrift {
 		// Return an error that preserves exit code 2 via Cobra
 		// Cobra will print this error; keep it concise
 		return fmt.Errorf("drift detected (exiting with code 2)")
@@ -101,3 +223,71 @@ func runDriftDetection(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// loadRunConfig loads the configuration file, or synthesizes the configuration of --path
+func loadRunConfig() (*config.Config, error) {
+	if watchPath != "" {
+		return config.AdHocConfig(watchPath, notifySlack)
+	}
+	return loadShardConfig()
+}
+
+// metricsHistoryWindow is how much history feeds the remediation metrics and fleet health
+const metricsHistoryWindow = 30 * 24 * time.Hour
+
+// writeRunOutputs writes the Prometheus textfile, the fleet health badge and the drift export
+// for a finished run
+func writeRunOutputs(cfg *config.Config, runReport *detector.Report) {
+	if cfg.DriftExport != nil {
+		if err := publishDriftExport(cfg); err != nil {
+			log.Printf("WARNING: Failed to publish drift export: %v", err)
+		}
+	}
+	if cfg.MetricsFile == "" && cfg.BadgeFile == "" {
+		return
+	}
+	summary, err := fleetSummary(cfg)
+	if err != nil {
+		log.Printf("WARNING: Failed to load history for metrics: %v", err)
+	}
+
+	if cfg.MetricsFile != "" {
+		if err := metrics.WriteTextfile(cfg.MetricsFile, metrics.WithLabels(metrics.RunFamilies(runReport, summary), cfg.Metadata)); err != nil {
+			log.Printf("WARNING: Failed to write metrics: %v", err)
+		}
+	}
+	if cfg.BadgeFile != "" {
+		if err := writeBadge(cfg, cfg.BadgeFile, summary.Health); err != nil {
+			log.Printf("WARNING: Failed to write badge: %v", err)
+		}
+	}
+}
+
+// fleetSummary summarizes the recent history of the configured projects. On error the summary
+// is still returned, built from no history.
+func fleetSummary(cfg *config.Config) (*report.Summary, error) {
+	to := time.Now()
+	from := to.Add(-metricsHistoryWindow)
+	var records []state.HistoryRecord
+	storage, err := state.Open(cfg)
+	if err == nil {
+		records, err = state.LoadHistory(storage, from)
+		state.RenameHistory(records, cfg.ProjectAliases())
+	}
+	summary := report.Build(records, from, to)
+	summary.ComputeHealth(fleetProjects(cfg))
+	summary.MarkFlapping(cfg.FlapThreshold())
+	summary.ComputeSLOs(cfg.SLOs)
+	return summary, err
+}
+
+// fleetProjects maps the enabled projects to their tags, which is what fleet health covers
+func fleetProjects(cfg *config.Config) map[string][]string {
+	projects := make(map[string][]string)
+	for _, project := range cfg.Projects {
+		if project.Enabled == nil || *project.Enabled {
+			projects[project.Name] = project.Tags
+		}
+	}
+	return projects
+}
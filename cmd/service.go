@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/winsvc"
+)
+
+var serviceName string
+var serviceLogFile string
+
+// serviceCmd represents the service command
+var serviceCmd = &cobra.Command{
+	Use:   "service",
+	Short: "Run the daemon as a Windows service",
+	Long: `Service installs, starts, stops and uninstalls a native Windows service running
+'daemon' with the given configuration. The service starts with Windows and is
+restarted when it fails. Run these commands from an elevated prompt.
+
+Example:
+  terradrift-watcher service install --config C:\terradrift\config.yml
+  terradrift-watcher service start
+  terradrift-watcher service stop
+  terradrift-watcher service uninstall`,
+}
+
+// serviceInstallCmd represents the service install command
+var serviceInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Install the Windows service",
+	Long: `Install registers a Windows service running 'daemon' with this binary and the
+given configuration. Log output goes to --log-file, by default
+terradrift-watcher.log next to the configuration.
+
+Example:
+  terradrift-watcher service install --config C:\terradrift\config.yml`,
+	RunE: runServiceInstall,
+}
+
+// serviceUninstallCmd represents the service uninstall command
+var serviceUninstallCmd = &cobra.Command{
+	Use:   "uninstall",
+	Short: "Remove the Windows service",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := winsvc.Uninstall(serviceName); err != nil {
+			return err
+		}
+		fmt.Printf("Service %s removed.\n", serviceName)
+		return nil
+	},
+}
+
+// serviceStartCmd represents the service start command
+var serviceStartCmd = &cobra.Command{
+	Use:   "start",
+	Short: "Start the Windows service",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := winsvc.Start(serviceName); err != nil {
+			return err
+		}
+		fmt.Printf("Service %s started.\n", serviceName)
+		return nil
+	},
+}
+
+// serviceStopCmd represents the service stop command
+var serviceStopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop the Windows service",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := winsvc.Stop(serviceName); err != nil {
+			return err
+		}
+		fmt.Printf("Service %s stopped.\n", serviceName)
+		return nil
+	},
+}
+
+func init() {
+	// Add the service command and its subcommands to the root command
+	rootCmd.AddCommand(serviceCmd)
+	serviceCmd.AddCommand(serviceInstallCmd, serviceUninstallCmd, serviceStartCmd, serviceStopCmd)
+
+	serviceCmd.PersistentFlags().StringVar(&serviceName, "name", winsvc.DefaultName, "Name of the Windows service")
+	serviceInstallCmd.Flags().StringVar(&serviceLogFile, "log-file", "", "Log file of the service (default terradrift-watcher.log next to the configuration)")
+}
+
+// runServiceInstall is the main execution function for the service install command
+func runServiceInstall(cmd *cobra.Command, args []string) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate the terradrift-watcher binary: %w", err)
+	}
+	// Validate the configuration now rather than on the first start
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	// Services start in the system directory, so every path must be absolute
+	absConfig, err := filepath.Abs(configFile)
+	if err != nil {
+		return err
+	}
+	logFile := serviceLogFile
+	if logFile == "" {
+		logFile = filepath.Join(filepath.Dir(absConfig), "terradrift-watcher.log")
+	}
+	if logFile, err = filepath.Abs(logFile); err != nil {
+		return err
+	}
+
+	err = winsvc.Install(winsvc.Service{
+		Name:        serviceName,
+		DisplayName: "TerraDrift Watcher",
+		Description: "Detects configuration drift in Terraform projects",
+		Executable:  executable,
+		Args:        []string{"daemon", "--config", absConfig, "--log-file", logFile},
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Service %s installed, logging to %s. Start it with:\n", serviceName, logFile)
+	fmt.Printf("  terradrift-watcher service start --name %s\n", serviceName)
+	if cfg.ControlSocket == nil || cfg.ControlSocket.Path == "" {
+		fmt.Println("Set control_socket.path so 'trigger' and 'status' can reach the service, whose temp directory differs from yours.")
+	}
+	return nil
+}
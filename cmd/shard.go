@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/terradrift-watcher/internal/config"
+)
+
+// shardSpec is the --shard of run and daemon, such as 2/5
+var shardSpec string
+
+// selectedShard returns the shard given with --shard, or nil when every project is scanned
+func selectedShard() (*config.Shard, error) {
+	if shardSpec == "" {
+		return nil, nil
+	}
+	shard, err := config.ParseShard(shardSpec)
+	if err != nil {
+		return nil, fmt.Errorf("--shard: %w", err)
+	}
+	return &shard, nil
+}
+
+// loadShardConfig loads the configuration, keeping only the projects of the selected shard
+func loadShardConfig() (*config.Config, error) {
+	shard, err := selectedShard()
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return nil, err
+	}
+	if shard != nil {
+		cfg.ApplyShard(*shard)
+	}
+	return cfg, nil
+}
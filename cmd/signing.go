@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/signing"
+)
+
+var signingKeyOutput string
+var signingPublicKey string
+
+// signingCmd groups the commands that manage and check signatures of written files
+var signingCmd = &cobra.Command{
+	Use:   "signing",
+	Short: "Create signing keys and verify signed reports and artifacts",
+}
+
+// signingKeygenCmd represents the signing keygen command
+var signingKeygenCmd = &cobra.Command{
+	Use:   "keygen",
+	Short: "Generate an Ed25519 key pair for signing",
+	Long: `Keygen writes a new Ed25519 private key, readable only by the user, and its
+public key next to it with a .pub suffix. Point signing.key_file at the private
+key and give the public key to whoever verifies the signed files.
+
+Example:
+  terradrift-watcher signing keygen --output /etc/terradrift/signing.key`,
+	RunE: runSigningKeygen,
+}
+
+// signingVerifyCmd represents the signing verify command
+var signingVerifyCmd = &cobra.Command{
+	Use:   "verify <file>...",
+	Short: "Check files against their detached signatures",
+	Long: `Verify checks each file against the signature written next to it (FILE.sig)
+and fails if any file is unsigned or was changed after signing. The public key
+defaults to the one of the configured signing key.
+
+Example:
+  terradrift-watcher signing verify --public-key signing.key.pub drift.html
+  terradrift-watcher signing verify --config config.yml fixtures/network/plan.txt`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runSigningVerify,
+}
+
+func init() {
+	// Add the signing commands to the root command
+	rootCmd.AddCommand(signingCmd)
+	signingCmd.AddCommand(signingKeygenCmd, signingVerifyCmd)
+
+	signingKeygenCmd.Flags().StringVarP(&signingKeyOutput, "output", "o", "", "Write the private key to this file (required)")
+	signingKeygenCmd.MarkFlagRequired("output")
+	signingVerifyCmd.Flags().StringVar(&signingPublicKey, "public-key", "", "PEM-encoded Ed25519 public key (default the configured signing key)")
+}
+
+// runSigningKeygen is the main execution function for the signing keygen command
+func runSigningKeygen(cmd *cobra.Command, args []string) error {
+	privatePEM, publicPEM, err := signing.GenerateKey()
+	if err != nil {
+		return fmt.Errorf("failed to generate key: %w", err)
+	}
+	// Never overwrite a key, as files signed with it could no longer be verified
+	file, err := os.OpenFile(signingKeyOutput, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create private key: %w", err)
+	}
+	if _, err := file.Write(privatePEM); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write private key: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write private key: %w", err)
+	}
+	if err := os.WriteFile(signingKeyOutput+".pub", publicPEM, 0644); err != nil {
+		return fmt.Errorf("failed to write public key: %w", err)
+	}
+	fmt.Printf("Wrote private key %s and public key %s.pub\n", signingKeyOutput, signingKeyOutput)
+	return nil
+}
+
+// runSigningVerify is the main execution function for the signing verify command
+func runSigningVerify(cmd *cobra.Command, args []string) error {
+	keyFile := signingPublicKey
+	if keyFile == "" {
+		cfg, err := config.LoadConfig(configFile)
+		if err != nil {
+			return fmt.Errorf("failed to load configuration: %w", err)
+		}
+		if cfg.Signing == nil {
+			return fmt.Errorf("no --public-key given and signing is not configured")
+		}
+		keyFile = cfg.Signing.KeyFile
+	}
+	key, err := signing.LoadPublicKey(keyFile)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, path := range args {
+		if err := signing.VerifyFile(key, path); err != nil {
+			if errors.Is(err, signing.ErrInvalidSignature) {
+				err = fmt.Errorf("modified after signing or signed with another key")
+			}
+			fmt.Printf("FAILED  %s: %v\n", path, err)
+			failed++
+			continue
+		}
+		fmt.Printf("OK      %s\n", path)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d file(s) failed verification", failed, len(args))
+	}
+	return nil
+}
+
+// signOutputs signs files the watcher wrote when signing is configured
+func signOutputs(cfg *config.Config, paths ...string) error {
+	if cfg.Signing == nil {
+		return nil
+	}
+	if err := signing.SignFiles(cfg.Signing.KeyFile, paths...); err != nil {
+		return fmt.Errorf("failed to sign output: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/lock"
+	"github.com/terradrift-watcher/internal/state"
+)
+
+var stateOutput string
+var stateHistory bool
+var stateForce bool
+
+// stateCmd groups the commands that move watcher state between hosts or read it
+var stateCmd = &cobra.Command{
+	Use:   "state",
+	Short: "Export, import or read the watcher's state",
+}
+
+// stateExportCmd represents the state export command
+var stateExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Write the watcher's state to a portable JSON bundle",
+	Long: `Export writes the state kept between runs (drift start times, fingerprints,
+fired escalations, scheduling and digest times) and undelivered notifications
+to a JSON bundle, so a deployment can move to another host or storage backend
+without re-alerting on known drift. The bundle is not encrypted.
+
+Example:
+  terradrift-watcher state export --config config.yml --output state-bundle.json
+  terradrift-watcher state export --config config.yml --history > state-bundle.json`,
+	RunE: runStateExport,
+}
+
+// stateImportCmd represents the state import command
+var stateImportCmd = &cobra.Command{
+	Use:   "import <bundle>",
+	Short: "Restore the watcher's state from a bundle",
+	Long: `Import replaces the state and undelivered notifications with those of a bundle
+written by 'state export'. The history is replaced only if the bundle contains
+one. Existing state is not overwritten unless --force is given.
+
+Example:
+  terradrift-watcher state import --config config.yml state-bundle.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runStateImport,
+}
+
+// stateCatCmd represents the state cat command
+var stateCatCmd = &cobra.Command{
+	Use:   "cat <namespace>/<key>",
+	Short: "Print an object kept in the state storage",
+	Long: `Cat prints an object of the state storage, decrypted when an encryption key is
+configured, such as the output of a remediation apply that 'remediate' saved.
+
+Example:
+  terradrift-watcher state cat --config config.yml apply-logs/aws-prod-vpc-20240301T120000Z.log`,
+	Args: cobra.ExactArgs(1),
+	RunE: runStateCat,
+}
+
+func init() {
+	// Add the state commands to the root command
+	rootCmd.AddCommand(stateCmd)
+	stateCmd.AddCommand(stateExportCmd, stateImportCmd, stateCatCmd)
+
+	stateExportCmd.Flags().StringVarP(&stateOutput, "output", "o", "", "Write the bundle to this file instead of stdout")
+	stateExportCmd.Flags().BoolVar(&stateHistory, "history", false, "Include the scan history")
+	stateImportCmd.Flags().BoolVar(&stateForce, "force", false, "Replace existing state")
+}
+
+// runStateExport is the main execution function for the state export command
+func runStateExport(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	storage, err := state.Open(cfg)
+	if err != nil {
+		return err
+	}
+
+	bundle, err := state.Export(storage, stateHistory)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode state bundle: %w", err)
+	}
+	data = append(data, '\n')
+
+	if stateOutput == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	// The bundle holds plan details, so keep it private to the user
+	if err := os.WriteFile(stateOutput, data, 0600); err != nil {
+		return fmt.Errorf("failed to write state bundle: %w", err)
+	}
+	if err := signOutputs(cfg, stateOutput); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Exported state of %d project(s) to %s\n", len(bundle.State.Projects), stateOutput)
+	return nil
+}
+
+// runStateImport is the main execution function for the state import command
+func runStateImport(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to read state bundle: %w", err)
+	}
+	var bundle state.Bundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return fmt.Errorf("failed to parse state bundle: %w", err)
+	}
+
+	// Hold the run lock so a concurrent run cannot write state while it is replaced
+	fileLock := lock.NewFileLock("")
+	if err := fileLock.Acquire(); err != nil {
+		return fmt.Errorf("failed to acquire lock: %w", err)
+	}
+	defer func() {
+		if err := fileLock.Release(); err != nil {
+			log.Printf("WARNING: Failed to release lock: %v", err)
+		}
+	}()
+
+	storage, err := state.Open(cfg)
+	if err != nil {
+		return err
+	}
+	existing, err := state.Load(storage)
+	if err != nil {
+		return err
+	}
+	if len(existing.Projects) > 0 && !stateForce {
+		return fmt.Errorf("state already exists for %d project(s); use --force to replace it", len(existing.Projects))
+	}
+
+	if err := state.Import(storage, &bundle); err != nil {
+		return err
+	}
+
+	fmt.Printf("Imported state of %d project(s), %d undelivered notification(s) and %d history record(s)\n",
+		len(bundle.State.Projects), len(bundle.Outbox), len(bundle.History))
+	return nil
+}
+
+// runStateCat is the main execution function for the state cat command
+func runStateCat(cmd *cobra.Command, args []string) error {
+	namespace, key, ok := strings.Cut(args[0], "/")
+	if !ok || namespace == "" || key == "" {
+		return fmt.Errorf("expected <namespace>/<key>, got %q", args[0])
+	}
+	storage, err := openStorage()
+	if err != nil {
+		return err
+	}
+	data, err := storage.Get(namespace, key)
+	if errors.Is(err, state.ErrNotFound) {
+		return fmt.Errorf("no object %s in the state storage", args[0])
+	}
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(data)
+	return err
+}
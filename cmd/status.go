@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/control"
+	"github.com/terradrift-watcher/internal/report"
+	"github.com/terradrift-watcher/internal/state"
+)
+
+// statusCmd represents the status command
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the daemon, whether scanning is paused and the last result of each project",
+	Long: `Status shows whether a daemon is running and what it is doing, whether scanning
+is paused and, for each configured project, the result of its last scan, since
+when it has been drifted, its health score over the last 30 days and when
+adaptive scheduling scans it next.
+
+Example:
+  terradrift-watcher status --config config.yml`,
+	RunE: runStatus,
+}
+
+func init() {
+	// Add the status command to the root command
+	rootCmd.AddCommand(statusCmd)
+}
+
+// runStatus is the main execution function for the status command
+func runStatus(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	storage, err := state.Open(cfg)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Daemon: %s\n", daemonStatus(cfg))
+
+	pause, err := state.LoadPause(storage)
+	if err != nil {
+		return err
+	}
+	if pause.Active(time.Now()) {
+		fmt.Printf("Scanning: %s\n\n", pause.String())
+	} else {
+		fmt.Printf("Scanning: active\n\n")
+	}
+
+	store, err := state.Load(storage)
+	if err != nil {
+		return err
+	}
+
+	summary, err := fleetSummary(cfg)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PROJECT\tLAST STATUS\tHEALTH\tLAST SCANNED\tDRIFT SINCE\tNEXT SCAN")
+	for _, project := range cfg.Projects {
+		ps := store.Project(project.Name)
+		status := ps.LastStatus
+		if project.Override != nil && !project.Override.Enabled {
+			status = project.Override.String()
+		} else if project.Enabled != nil && !*project.Enabled {
+			status = "disabled"
+		} else if status == "" {
+			status = "never scanned"
+		} else if ps.Flapping {
+			status = report.StatusFlapping
+		}
+		health := "-"
+		if stats := summary.Lookup(project.Name); stats != nil {
+			health = strconv.Itoa(stats.Score)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", project.Name, status, health, formatStatusTime(ps.LastScanned),
+			formatStatusTime(ps.DriftSince), formatStatusTime(ps.NextScan))
+	}
+	return w.Flush()
+}
+
+// daemonStatus describes the daemon listening on the configuration's control socket
+func daemonStatus(cfg *config.Config) string {
+	resp, err := control.Send(controlSocketPath(cfg), control.Request{Command: control.CommandStatus})
+	if err != nil || resp.Status == nil {
+		return "not running"
+	}
+	status := resp.Status
+	s := fmt.Sprintf("running since %s (pid %d)", formatStatusTime(status.StartedAt), status.PID)
+	if status.Scanning {
+		s += ", scanning since " + formatStatusTime(status.RunStartedAt)
+	} else {
+		s += ", next run " + formatStatusTime(status.NextRun)
+	}
+	if len(status.Queued) > 0 {
+		s += ", queued " + strings.Join(status.Queued, ", ")
+	}
+	return s
+}
+
+// formatStatusTime renders a state timestamp, or "-" when it is unset
+func formatStatusTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
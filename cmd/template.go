@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/detector"
+	"github.com/terradrift-watcher/internal/notifier"
+	"github.com/terradrift-watcher/internal/state"
+	"github.com/terradrift-watcher/internal/terraform"
+	"github.com/terradrift-watcher/pkg/event"
+)
+
+var templateNotifier string
+var templateSample string
+var templateProject string
+
+// templateCmd groups the commands for working with notification templates
+var templateCmd = &cobra.Command{
+	Use:   "template",
+	Short: "Work with notification templates",
+}
+
+// templateRenderCmd represents the template render command
+var templateRenderCmd = &cobra.Command{
+	Use:   "render",
+	Short: "Render a notifier's payload for a sample drift event",
+	Long: `Render prints what a notifier would send for a drift event without sending
+anything, so custom templates can be checked offline. The event is read from
+a DriftEvent JSON file (as sent to webhook and stdout notifiers; for JSON
+lines the last event is used), taken from the latest drifted scan of a
+project in the history, or a built-in sample is used.
+
+Example:
+  terradrift-watcher template render --config config.yml --notifier ops-webhook
+  terradrift-watcher template render --config config.yml --notifier ops-webhook --sample drift.json
+  terradrift-watcher template render --config config.yml --notifier team-slack --project aws-prod-vpc`,
+	RunE: runTemplateRender,
+}
+
+func init() {
+	// Add the template command to the root command
+	rootCmd.AddCommand(templateCmd)
+	templateCmd.AddCommand(templateRenderCmd)
+
+	templateRenderCmd.Flags().StringVarP(&templateNotifier, "notifier", "n", "", "Name of the notifier to render")
+	templateRenderCmd.Flags().StringVar(&templateSample, "sample", "", "DriftEvent JSON file to render")
+	templateRenderCmd.Flags().StringVarP(&templateProject, "project", "p", "", "Render the latest drifted scan of this project from the history")
+	templateRenderCmd.MarkFlagRequired("notifier")
+}
+
+// runTemplateRender is the main execution function for the template render command
+func runTemplateRender(cmd *cobra.Command, args []string) error {
+	if templateSample != "" && templateProject != "" {
+		return fmt.Errorf("--sample and --project cannot be used together")
+	}
+
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	var alert notifier.DriftAlert
+	switch {
+	case templateSample != "":
+		alert, err = loadSampleAlert(templateSample)
+	case templateProject != "":
+		alert, err = latestDriftAlert(cfg, templateProject)
+	default:
+		alert = sampleAlert()
+	}
+	if err != nil {
+		return err
+	}
+
+	payload, err := detector.RenderNotification(cfg, templateNotifier, alert)
+	if err != nil {
+		return err
+	}
+
+	os.Stdout.Write(payload)
+	if !bytes.HasSuffix(payload, []byte("\n")) {
+		fmt.Println()
+	}
+	return nil
+}
+
+// loadSampleAlert reads a DriftEvent file; for JSON lines, the last event is used
+func loadSampleAlert(path string) (notifier.DriftAlert, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return notifier.DriftAlert{}, fmt.Errorf("failed to read sample: %w", err)
+	}
+
+	var ev event.DriftEvent
+	if err := json.Unmarshal(data, &ev); err != nil {
+		found := false
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+		for scanner.Scan() {
+			line := bytes.TrimSpace(scanner.Bytes())
+			if len(line) == 0 {
+				continue
+			}
+			if err := json.Unmarshal(line, &ev); err != nil {
+				return notifier.DriftAlert{}, fmt.Errorf("failed to parse sample %s: %w", path, err)
+			}
+			found = true
+		}
+		if !found {
+			return notifier.DriftAlert{}, fmt.Errorf("sample %s contains no drift event", path)
+		}
+	}
+	return notifier.AlertFromEvent(ev), nil
+}
+
+// latestDriftAlert rebuilds the alert of the latest drifted scan of a project
+func latestDriftAlert(cfg *config.Config, project string) (notifier.DriftAlert, error) {
+	storage, err := state.Open(cfg)
+	if err != nil {
+		return notifier.DriftAlert{}, err
+	}
+	records, err := state.LoadHistory(storage, time.Time{})
+	if err != nil {
+		return notifier.DriftAlert{}, err
+	}
+	state.RenameHistory(records, cfg.ProjectAliases())
+
+	for i := len(records) - 1; i >= 0; i-- {
+		record := records[i]
+		if record.Project == project && record.Status == detector.StatusDrifted {
+			return recordAlert(record), nil
+		}
+	}
+	return notifier.DriftAlert{}, fmt.Errorf("no drifted scan of project '%s' found in the history", project)
+}
+
+// recordAlert rebuilds the alert of a drifted scan from its history record
+func recordAlert(record state.HistoryRecord) notifier.DriftAlert {
+	alert := notifier.DriftAlert{
+		Project:     record.Project,
+		Summary:     record.Summary,
+		Owners:      record.Owners,
+		Tags:        record.Tags,
+		Changes:     record.Changes,
+		Fingerprint: record.Fingerprint,
+		Description: record.Description,
+		RunbookURL:  record.RunbookURL,
+		Outputs:     record.Outputs,
+	}
+	if record.DriftSince != nil {
+		alert.DriftSince = *record.DriftSince
+	}
+	return alert
+}
+
+// sampleAlert is rendered when neither a sample file nor a project is given
+func sampleAlert() notifier.DriftAlert {
+	return notifier.DriftAlert{
+		Project:     "example-project",
+		Summary:     "Plan: 0 to add, 1 to change, 0 to destroy.",
+		PlanOutput:  "  # aws_s3_bucket.logs will be updated in-place\n  ~ resource \"aws_s3_bucket\" \"logs\" {\n      ~ tags = {\n          ~ \"Owner\" = \"alice\" -> \"bob\"\n        }\n    }\n\nPlan: 0 to add, 1 to change, 0 to destroy.\n",
+		Owners:      []string{"platform-team"},
+		Description: "Example project used to preview notifications",
+		RunbookURL:  "https://example.com/runbooks/drift",
+		Outputs:     map[string]string{"environment": "staging"},
+		DriftSince:  time.Now().Add(-3 * time.Hour),
+		Changes: []terraform.AttributeChange{
+			{Address: "aws_s3_bucket.logs", Action: "update", Attribute: "tags.Owner", Before: "alice", After: "bob"},
+		},
+	}
+}
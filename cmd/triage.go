@@ -0,0 +1,425 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/detector"
+	"github.com/terradrift-watcher/internal/notifier"
+	"github.com/terradrift-watcher/internal/state"
+)
+
+var triageUser string
+var triageReason string
+
+// triageCmd represents the triage command
+var triageCmd = &cobra.Command{
+	Use:   "triage",
+	Short: "Step through drifted projects and record what to do about each",
+	Long: `Triage loads the current drift of every project and lets an operator step through
+it interactively: view each drift and its changelog, then acknowledge it, snooze
+it, open or record a ticket for it, or mark it for remediation. Decisions are
+written to the state storage and apply to the current drift only; once it is
+resolved or changes, the project is alerted as usual again.
+
+Acknowledged drift and drift with a ticket are not alerted again, snoozed drift
+is not alerted until the snooze ends, and drift marked for remediation is still
+alerted. Escalations and SLO breaches fire regardless. Commands are read from
+standard input, so decisions can also be scripted.
+
+Every decision needs a reason, recorded with it and in the audit log: the note
+given with the command, or else --reason.
+
+Example:
+  terradrift-watcher triage --config config.yml
+  terradrift-watcher triage --config config.yml --reason "Weekly drift review"
+  printf 'show aws-prod-vpc\nsnooze 4h waiting for the vendor fix\n' | terradrift-watcher triage --config config.yml`,
+	RunE: runTriage,
+}
+
+func init() {
+	// Add the triage command to the root command
+	rootCmd.AddCommand(triageCmd)
+
+	triageCmd.Flags().StringVar(&triageUser, "user", "", "Who makes the decisions (default the current user)")
+	triageCmd.Flags().StringVar(&triageReason, "reason", "", "Reason of the decisions made without a note")
+}
+
+// triageHelp lists the commands of the triage prompt
+const triageHelp = `Commands:
+  list, l                      List the drifted projects
+  show, s [N|project]          Show a drift (default the current one)
+  next, n / prev, p            Move to the next or previous drift
+  diff, d                      Show the changelog of the current drift
+  ack, a [note]                Acknowledge the drift; it is not alerted again
+  snooze, z <duration> [note]  Hold the drift's alerts, e.g. snooze 4h or snooze 2d
+  ticket, t [reference] [note] Record the drift's ticket, or open one via the ticket notifiers
+  remediate, r [note]          Approve the drift for 'remediate --approved'
+  clear, c [note]              Remove the decision about the drift
+Notes give the reason of a decision; without one, --reason is used.
+  help, ?                      Show this help
+  quit, q                      Leave triage`
+
+// triageItem is a drifted project being triaged
+type triageItem struct {
+	project config.Project
+	state   *state.ProjectState
+	latest  *state.HistoryRecord // The latest drifted scan, nil when pruned from the history
+}
+
+// triageSession is the state of an interactive triage
+type triageSession struct {
+	cfg     *config.Config
+	storage state.Storage
+	store   *state.Store
+	triage  *state.Triage
+	items   []triageItem
+	current int
+	user    string // Shown as who made the decisions
+	claimed string // The unverified --user, recorded next to the OS user in the audit log
+	reason  string // Reason of decisions made without a note
+	out     io.Writer
+}
+
+// runTriage is the main execution function for the triage command
+func runTriage(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	storage, err := state.Open(cfg)
+	if err != nil {
+		return err
+	}
+	session, err := newTriageSession(cfg, storage)
+	if err != nil {
+		return err
+	}
+	session.out = os.Stdout
+	session.user = actor(triageUser)
+	session.claimed = triageUser
+	session.reason = triageReason
+
+	if len(session.items) == 0 {
+		fmt.Println("No drifted projects to triage.")
+		return nil
+	}
+	session.list()
+	fmt.Println()
+	session.show()
+	return session.run(os.Stdin)
+}
+
+// newTriageSession loads the current drift of every enabled project, oldest first
+func newTriageSession(cfg *config.Config, storage state.Storage) (*triageSession, error) {
+	store, err := state.Load(storage)
+	if err != nil {
+		return nil, err
+	}
+	triage, err := state.LoadTriage(storage)
+	if err != nil {
+		return nil, err
+	}
+	session := &triageSession{cfg: cfg, storage: storage, store: store, triage: triage}
+
+	oldest := time.Now()
+	for _, project := range cfg.Projects {
+		if project.Enabled != nil && !*project.Enabled {
+			continue
+		}
+		ps, ok := store.Projects[project.Name]
+		if !ok || ps.DriftSince.IsZero() || ps.Fingerprint == "" {
+			continue
+		}
+		session.items = append(session.items, triageItem{project: project, state: ps})
+		if ps.DriftSince.Before(oldest) {
+			oldest = ps.DriftSince
+		}
+	}
+	sort.SliceStable(session.items, func(i, j int) bool {
+		return session.items[i].state.DriftSince.Before(session.items[j].state.DriftSince)
+	})
+
+	records, err := state.LoadHistory(storage, oldest)
+	if err != nil {
+		return nil, err
+	}
+	state.RenameHistory(records, cfg.ProjectAliases())
+	for i := range records {
+		if records[i].Status != detector.StatusDrifted {
+			continue
+		}
+		for j := range session.items {
+			if session.items[j].project.Name == records[i].Project {
+				session.items[j].latest = &records[i]
+			}
+		}
+	}
+	return session, nil
+}
+
+// run reads and executes commands until quit or the end of the input
+func (s *triageSession) run(in io.Reader) error {
+	scanner := bufio.NewScanner(in)
+	for {
+		fmt.Fprintf(s.out, "triage [%d/%d %s]> ", s.current+1, len(s.items), s.items[s.current].project.Name)
+		if !scanner.Scan() {
+			fmt.Fprintln(s.out)
+			return scanner.Err()
+		}
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		command, args := fields[0], fields[1:]
+		if command == "quit" || command == "q" || command == "exit" {
+			return nil
+		}
+		if err := s.execute(command, args); err != nil {
+			fmt.Fprintf(s.out, "Error: %v\n", err)
+		}
+	}
+}
+
+// execute runs one command of the prompt
+func (s *triageSession) execute(command string, args []string) error {
+	switch command {
+	case "list", "l":
+		s.list()
+	case "show", "s":
+		if len(args) > 0 {
+			if err := s.seek(args[0]); err != nil {
+				return err
+			}
+		}
+		s.show()
+	case "next", "n":
+		s.current = (s.current + 1) % len(s.items)
+		s.show()
+	case "prev", "p":
+		s.current = (s.current + len(s.items) - 1) % len(s.items)
+		s.show()
+	case "diff", "d":
+		s.diff()
+	case "ack", "a":
+		return s.decide(state.TriageDecision{Action: state.TriageAck, Note: strings.Join(args, " ")})
+	case "snooze", "z":
+		if len(args) == 0 {
+			return fmt.Errorf("snooze needs a duration, e.g. snooze 4h")
+		}
+		duration, err := config.ParseDuration(args[0])
+		if err != nil || duration <= 0 {
+			return fmt.Errorf("invalid snooze duration: %s", args[0])
+		}
+		return s.decide(state.TriageDecision{Action: state.TriageSnooze, Until: time.Now().Add(duration), Note: strings.Join(args[1:], " ")})
+	case "ticket", "t":
+		return s.ticket(args)
+	case "remediate", "r":
+		return s.decide(state.TriageDecision{Action: state.TriageRemediate, Note: strings.Join(args, " ")})
+	case "clear", "c":
+		return s.clear(strings.Join(args, " "))
+	case "help", "?", "h":
+		fmt.Fprintln(s.out, triageHelp)
+	default:
+		return fmt.Errorf("unknown command %q, type 'help' for the commands", command)
+	}
+	return nil
+}
+
+// seek makes the drift with the given number or project name the current one
+func (s *triageSession) seek(target string) error {
+	if n, err := strconv.Atoi(target); err == nil {
+		if n < 1 || n > len(s.items) {
+			return fmt.Errorf("no drift number %d", n)
+		}
+		s.current = n - 1
+		return nil
+	}
+	for i, item := range s.items {
+		if item.project.Name == target {
+			s.current = i
+			return nil
+		}
+	}
+	return fmt.Errorf("project '%s' is not drifted", target)
+}
+
+// list prints the drifted projects with their decisions
+func (s *triageSession) list() {
+	w := tabwriter.NewWriter(s.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "#\tPROJECT\tDRIFT SINCE\tINCIDENT\tDECISION")
+	for i, item := range s.items {
+		incident := item.state.Incident
+		if incident == "" {
+			incident = "unconfirmed"
+		}
+		decision := "-"
+		if current := s.triage.Current(item.project.Name, item.state); current != nil {
+			decision = current.String()
+		}
+		marker := " "
+		if i == s.current {
+			marker = "*"
+		}
+		fmt.Fprintf(w, "%s%d\t%s\t%s\t%s\t%s\n", marker, i+1, item.project.Name,
+			formatStatusTime(item.state.DriftSince), incident, decision)
+	}
+	w.Flush()
+}
+
+// show prints the current drift
+func (s *triageSession) show() {
+	item := s.items[s.current]
+	fmt.Fprintf(s.out, "Project:     %s\n", item.project.Name)
+	if item.project.Description != "" {
+		fmt.Fprintf(s.out, "Description: %s\n", item.project.Description)
+	}
+	fmt.Fprintf(s.out, "Drifted:     since %s (%v)\n", formatStatusTime(item.state.DriftSince),
+		time.Since(item.state.DriftSince).Round(time.Minute))
+	if item.state.Incident != "" {
+		fmt.Fprintf(s.out, "Incident:    %s\n", item.state.Incident)
+	}
+	fmt.Fprintf(s.out, "Fingerprint: %s\n", item.state.Fingerprint)
+	if len(item.state.Escalations) > 0 {
+		fmt.Fprintf(s.out, "Escalated:   %s\n", strings.Join(item.state.Escalations, ", "))
+	}
+	if item.project.RunbookURL != "" {
+		fmt.Fprintf(s.out, "Runbook:     %s\n", item.project.RunbookURL)
+	}
+	if decision := s.triage.Current(item.project.Name, item.state); decision != nil {
+		fmt.Fprintf(s.out, "Decision:    %s\n", decision)
+	}
+	if item.latest != nil {
+		if len(item.latest.Owners) > 0 {
+			fmt.Fprintf(s.out, "Owners:      %s\n", strings.Join(item.latest.Owners, ", "))
+		}
+		fmt.Fprintf(s.out, "\n%s\n", item.latest.Summary)
+	}
+}
+
+// diff prints the changelog of the current drift
+func (s *triageSession) diff() {
+	item := s.items[s.current]
+	if item.latest == nil || len(item.latest.Changes) == 0 {
+		fmt.Fprintln(s.out, "No changelog recorded for this drift; it needs terraform's saved plan.")
+		return
+	}
+	printHistoryChanges([]state.HistoryRecord{*item.latest})
+}
+
+// ticket records the ticket of the current drift, opening one via the ticket notifiers when
+// no reference is given
+func (s *triageSession) ticket(args []string) error {
+	decision := state.TriageDecision{Action: state.TriageTicket}
+	if len(args) > 0 {
+		decision.Ticket = args[0]
+		decision.Note = strings.Join(args[1:], " ")
+		return s.decide(decision)
+	}
+	// Nothing is sent to the ticket notifiers for a decision that cannot be recorded
+	if s.reason == "" {
+		return errNoTriageReason
+	}
+
+	item := s.items[s.current]
+	alert := notifier.DriftAlert{
+		Project:     item.project.Name,
+		Tags:        item.project.Tags,
+		Fingerprint: item.state.Fingerprint,
+		DriftSince:  item.state.DriftSince,
+		Incident:    item.state.Incident,
+		Description: item.project.Description,
+		RunbookURL:  item.project.RunbookURL,
+	}
+	if item.latest != nil {
+		alert.Summary = item.latest.Summary
+		alert.Owners = item.latest.Owners
+		alert.Changes = item.latest.Changes
+	}
+	sent, err := detector.OpenTicket(s.cfg, alert, s.user)
+	if err != nil {
+		return err
+	}
+	decision.Ticket = "requested via " + strings.Join(sent, ", ")
+	return s.decide(decision)
+}
+
+// errNoTriageReason is returned for decisions made without a note when triage has no --reason
+var errNoTriageReason = fmt.Errorf("a reason is required: add a note, e.g. 'ack looking into it', or start triage with --reason")
+
+// decide records a decision about the current drift
+func (s *triageSession) decide(decision state.TriageDecision) error {
+	item := s.items[s.current]
+	if decision.Note == "" {
+		decision.Note = s.reason
+	}
+	if decision.Note == "" {
+		return errNoTriageReason
+	}
+	decision.Incident = item.state.Incident
+	decision.Fingerprint = item.state.Fingerprint
+	decision.By = s.user
+	decision.At = time.Now()
+	err := s.update(func(triage *state.Triage) {
+		triage.Decisions[item.project.Name] = &decision
+	}, fmt.Sprintf("'%s' triaged as %s", item.project.Name, decision.String()))
+	if err != nil {
+		return err
+	}
+
+	detail := decision.Incident
+	switch decision.Action {
+	case state.TriageSnooze:
+		detail += ", until " + decision.Until.Format(time.RFC3339)
+	case state.TriageTicket:
+		detail += ", ticket " + decision.Ticket
+	}
+	recordAudit(s.storage, state.AuditRecord{ClaimedBy: s.claimed, Action: state.AuditTriagePrefix + decision.Action,
+		Projects: []string{item.project.Name}, Reason: decision.Note, Detail: detail})
+	return nil
+}
+
+// clear removes the decision about the current drift
+func (s *triageSession) clear(note string) error {
+	item := s.items[s.current]
+	if note == "" {
+		note = s.reason
+	}
+	if note == "" {
+		return errNoTriageReason
+	}
+	err := s.update(func(triage *state.Triage) {
+		delete(triage.Decisions, item.project.Name)
+	}, fmt.Sprintf("Decision about '%s' removed", item.project.Name))
+	if err != nil {
+		return err
+	}
+	recordAudit(s.storage, state.AuditRecord{ClaimedBy: s.claimed, Action: state.AuditTriagePrefix + "clear",
+		Projects: []string{item.project.Name}, Reason: note, Detail: item.state.Incident})
+	return nil
+}
+
+// update applies a change to the latest recorded decisions and saves them, so decisions made
+// meanwhile by other operators are kept
+func (s *triageSession) update(change func(*state.Triage), message string) error {
+	triage, err := state.LoadTriage(s.storage)
+	if err != nil {
+		return err
+	}
+	change(triage)
+	if err := state.SaveTriage(s.storage, triage, s.store); err != nil {
+		return err
+	}
+	s.triage = triage
+	fmt.Fprintln(s.out, message)
+	return nil
+}
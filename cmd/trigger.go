@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/terradrift-watcher/internal/control"
+)
+
+var triggerProjects []string
+var triggerReason string
+
+// triggerCmd represents the trigger command
+var triggerCmd = &cobra.Command{
+	Use:   "trigger",
+	Short: "Ask the running daemon to scan projects now",
+	Long: `Trigger asks the daemon to scan the given projects immediately, whether or not
+they are due, instead of starting a second run that would fight the daemon over
+the run lock. A scan requested while the daemon is busy runs right after the
+current one, ahead of a scheduled run that is due. A project already waiting
+for a triggered scan is not queued again, so repeated triggers, e.g. from a
+webhook storm, scan it once. The daemon's schedule is not changed. The daemon
+records the trigger, who sent it and --reason in the audit log.
+
+Example:
+  terradrift-watcher trigger --config config.yml --project aws-prod-vpc --reason "Applied PR #812"
+  terradrift-watcher trigger --config config.yml -p aws-prod-vpc -p azure-prod --reason "Provider upgrade"`,
+	RunE: runTrigger,
+}
+
+func init() {
+	// Add the trigger command to the root command
+	rootCmd.AddCommand(triggerCmd)
+
+	triggerCmd.Flags().StringSliceVarP(&triggerProjects, "project", "p", nil, "Project to scan (repeatable)")
+	triggerCmd.Flags().StringVar(&triggerReason, "reason", "", "Why the scan is needed, recorded in the audit log (required)")
+	triggerCmd.MarkFlagRequired("project")
+}
+
+// runTrigger is the main execution function for the trigger command
+func runTrigger(cmd *cobra.Command, args []string) error {
+	if triggerReason == "" {
+		return errReasonRequired
+	}
+	resp, err := sendControl(control.Request{Command: control.CommandTrigger, Projects: triggerProjects,
+		Reason: triggerReason})
+	if err != nil {
+		return err
+	}
+	fmt.Println(resp.Message)
+	return nil
+}
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/detector"
+	"github.com/terradrift-watcher/internal/lock"
+	"github.com/terradrift-watcher/internal/state"
+)
+
+var verifyProjects []string
+var verifyApply string
+var verifyUser string
+
+// verifyCmd represents the verify command
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Scan projects after an apply and close their incidents with the outcome",
+	Long: `Verify scans projects right after their drift was remediated outside the
+watcher, e.g. by a manual or CI 'terraform apply', and tells the projects'
+notifiers the outcome. When no drift is left the incident is closed with a
+resolution; drift that remains is alerted again, whatever held its alerts.
+Tickets requested in triage for the incident are sent the outcome too, and the
+triage decisions about the remediated drift are closed.
+
+'remediate --approved' verifies the plans it applies the same way.
+
+Example:
+  terradrift-watcher verify --config config.yml --project aws-prod-vpc
+  terradrift-watcher verify --config config.yml -p aws-prod-vpc -p aws-prod-dns --apply "CI pipeline #4521"`,
+	RunE: runVerify,
+}
+
+func init() {
+	// Add the verify command to the root command
+	rootCmd.AddCommand(verifyCmd)
+
+	verifyCmd.Flags().StringSliceVarP(&verifyProjects, "project", "p", nil, "Project to verify (repeatable)")
+	verifyCmd.Flags().StringVar(&verifyApply, "apply", "manual apply", "What remediated the drift, shown in the notifications")
+	verifyCmd.Flags().StringVar(&verifyUser, "user", "", "Who applied (default the current user)")
+	verifyCmd.MarkFlagRequired("project")
+}
+
+// runVerify is the main execution function for the verify command
+func runVerify(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	var names []string
+	for _, project := range cfg.Projects {
+		names = append(names, project.Name)
+	}
+	for _, name := range verifyProjects {
+		if !containsProject(names, name, nil) {
+			return fmt.Errorf("project '%s' not found in configuration", name)
+		}
+	}
+
+	fileLock := lock.NewFileLock("")
+	if err := fileLock.Acquire(); err != nil {
+		return fmt.Errorf("failed to acquire lock: %w", err)
+	}
+	defer func() {
+		if err := fileLock.Release(); err != nil {
+			log.Printf("WARNING: Failed to release lock: %v", err)
+		}
+	}()
+
+	verification := verifyApply + " by " + actor(verifyUser)
+	failed := verifyRemediation(cfg, verifyProjects, verification)
+
+	detail := verification + ", verified"
+	if failed > 0 {
+		detail = fmt.Sprintf("%s, %d project(s) not verified", verification, failed)
+	}
+	if storage, err := state.Open(cfg); err != nil {
+		log.Printf("WARNING: Failed to record the verification in the audit log: %v", err)
+	} else {
+		recordAudit(storage, state.AuditRecord{ClaimedBy: verifyUser, Action: state.AuditVerify, Projects: verifyProjects,
+			Detail: detail})
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d project(s) not verified", failed)
+	}
+	return nil
+}
+
+// verifyRemediation scans the projects after an apply, closing their incidents or alerting
+// the drift left, and returns how many were not verified
+func verifyRemediation(cfg *config.Config, projects []string, verification string) int {
+	fmt.Printf("\nScanning %s to verify the remediation...\n", strings.Join(projects, ", "))
+	runReport, err := detector.RunWithOptions(cfg, detector.Options{Projects: projects, IgnorePause: true, Verification: verification})
+	if runReport == nil {
+		fmt.Printf("Verification scan failed: %v\n", err)
+		return len(projects)
+	}
+	// Failed scans are counted below; the error says the scan did not complete cleanly
+	if err != nil {
+		fmt.Printf("Verification scan: %v\n", err)
+	}
+	writeRunOutputs(cfg, runReport)
+
+	failed := 0
+	scanned := make(map[string]bool)
+	for _, result := range runReport.Results {
+		scanned[result.Project] = true
+		if detector.DriftFree(result.Status) {
+			fmt.Printf("Verified: '%s' no longer drifts.\n", result.Project)
+			continue
+		}
+		fmt.Printf("Not verified: '%s' is %s after the apply.\n", result.Project, result.Status)
+		failed++
+	}
+	for _, project := range projects {
+		if !scanned[project] {
+			fmt.Printf("Not verified: '%s' was not scanned, e.g. because it is disabled.\n", project)
+			failed++
+		}
+	}
+	return failed
+}
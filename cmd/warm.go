@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/detector"
+	"github.com/terradrift-watcher/internal/lock"
+)
+
+var warmProject string
+
+// warmCmd represents the warm command
+var warmCmd = &cobra.Command{
+	Use:   "warm",
+	Short: "Run terraform init for every project without planning",
+	Long: `Warm runs terraform init for each enabled project, one project after another,
+without planning. Providers and modules are downloaded into each project's
+.terraform directory, and into the plugin cache when TF_PLUGIN_CACHE_DIR is set,
+so the first scan of the day is not dominated by downloads. Projects that fail
+to initialize are reported, making warm a health check for backends and
+provider constraints as well.
+
+No notifications are sent and state is not touched. The command exits with an
+error when any project fails to initialize.
+
+Example:
+  terradrift-watcher warm --config config.yml
+  terradrift-watcher warm --config config.yml --project aws-prod-vpc`,
+	RunE: runWarm,
+}
+
+func init() {
+	// Add the warm command to the root command
+	rootCmd.AddCommand(warmCmd)
+
+	warmCmd.Flags().StringVarP(&warmProject, "project", "p", "", "Only initialize this project")
+}
+
+// runWarm is the main execution function for the warm command
+func runWarm(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	// Init writes to the project directories, so it must not overlap a run
+	fileLock := lock.NewFileLock("")
+	if err := fileLock.Acquire(); err != nil {
+		return fmt.Errorf("failed to acquire lock: %w", err)
+	}
+	defer func() {
+		if err := fileLock.Release(); err != nil {
+			log.Printf("WARNING: Failed to release lock: %v", err)
+		}
+	}()
+
+	results, err := detector.Warm(cfg, warmProject)
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 {
+		fmt.Println("No enabled projects to initialize.")
+		return nil
+	}
+
+	failed := 0
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PROJECT\tSTATUS\tDURATION")
+	for _, result := range results {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", result.Project, result.Status, result.Duration.Round(time.Second))
+		if result.Status == detector.WarmFailed {
+			failed++
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d project(s) failed to initialize", failed, len(results))
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/terradrift-watcher/internal/redact"
+)
+
+// AdHocNotifier names the Slack notifier of an ad-hoc configuration
+const AdHocNotifier = "slack"
+
+// AdHocConfig synthesizes the configuration of a single project watched without a config
+// file, named after its directory and alerting the Slack webhook when one is given. The
+// project type is detected from cdktf.json or Pulumi.yaml in the directory.
+func AdHocConfig(path string, slackWebhookURL string) (*Config, error) {
+	dir, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", path)
+	}
+
+	enabled := true
+	project := Project{Name: filepath.Base(dir), Path: dir, Enabled: &enabled}
+	switch {
+	case fileExists(filepath.Join(dir, "cdktf.json")):
+		project.Type = ProjectTypeCDKTF
+	case fileExists(filepath.Join(dir, "Pulumi.yaml")):
+		project.Type = ProjectTypePulumi
+	}
+
+	var config Config
+	if slackWebhookURL != "" {
+		notifierEnabled := true
+		config.Notifiers = []Notifier{{
+			Name:    AdHocNotifier,
+			Type:    "slack",
+			Config:  map[string]string{SlackWebhookURL: slackWebhookURL},
+			Enabled: &notifierEnabled,
+		}}
+		project.Notifiers = []string{AdHocNotifier}
+	}
+	config.Projects = []Project{project}
+	applyMetadataFlags(&config)
+
+	if err := validateConfig(&config); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	redact.Add(config.Secrets()...)
+	redact.AddEnvironment()
+	return &config, nil
+}
+
+// fileExists reports whether path is an existing regular file
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAdHocConfig(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "prod")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := AdHocConfig(dir, "https://hooks.slack.com/services/T000/B000/XXXX")
+	if err != nil {
+		t.Fatalf("AdHocConfig failed: %v", err)
+	}
+	if len(cfg.Projects) != 1 || cfg.Projects[0].Name != "prod" || cfg.Projects[0].Path != dir {
+		t.Fatalf("Expected a single project named after the directory, got %+v", cfg.Projects)
+	}
+	project := cfg.Projects[0]
+	if project.Type != "" || !*project.Enabled {
+		t.Errorf("Expected an enabled terraform project, got type %q enabled %v", project.Type, *project.Enabled)
+	}
+	if len(project.Notifiers) != 1 || len(cfg.Notifiers) != 1 || cfg.Notifiers[0].Type != "slack" {
+		t.Errorf("Expected the project routed to a slack notifier, got %v and %+v", project.Notifiers, cfg.Notifiers)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "Pulumi.yaml"), []byte("name: prod\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err = AdHocConfig(dir, "")
+	if err != nil {
+		t.Fatalf("AdHocConfig failed: %v", err)
+	}
+	if cfg.Projects[0].Type != ProjectTypePulumi || len(cfg.Notifiers) != 0 {
+		t.Errorf("Expected a pulumi project without notifiers, got type %q and %d notifiers", cfg.Projects[0].Type, len(cfg.Notifiers))
+	}
+
+	if _, err := AdHocConfig(dir, "https://example.com/hook"); err == nil {
+		t.Error("Expected an error for a webhook that is not Slack's")
+	}
+	if _, err := AdHocConfig(filepath.Join(dir, "missing"), ""); err == nil {
+		t.Error("Expected an error for a missing directory")
+	}
+}
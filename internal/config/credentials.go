@@ -0,0 +1,159 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// StrictSecurity makes LoadConfig refuse configurations with credentials written inline
+// instead of warning about them. It is set by --strict-security.
+var StrictSecurity bool
+
+// credentialPatterns recognize long-lived credentials by their value
+var credentialPatterns = []struct {
+	kind    string
+	pattern *regexp.Regexp
+}{
+	{"an AWS access key ID", regexp.MustCompile(`\b(AKIA|ABIA|ACCA)[A-Z0-9]{16}\b`)},
+	{"an Azure client secret", regexp.MustCompile(`^[A-Za-z0-9_~.\-]{3}\dQ~[A-Za-z0-9_~.\-]{31,34}$`)},
+	{"a GitHub token", regexp.MustCompile(`\b(gh[pousr]_[A-Za-z0-9]{36}|github_pat_[A-Za-z0-9_]{60,})\b`)},
+	{"a Slack token", regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}`)},
+	{"a Slack webhook URL", regexp.MustCompile(`hooks\.slack(-gov)?\.com/services/T[A-Z0-9]+/B[A-Z0-9]+/[A-Za-z0-9]+`)},
+	{"a Terraform Cloud token", regexp.MustCompile(`\b[A-Za-z0-9]{14}\.atlasv1\.[A-Za-z0-9_\-]{60,}`)},
+	{"a private key", regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----`)},
+}
+
+// credentialKeyWords mark settings whose values are credentials whatever they look like
+var credentialKeyWords = []string{"secret", "password", "passwd", "token", "api_key", "apikey", "private_key"}
+
+// minInlineCredentialLength keeps placeholders such as "changeme" from being reported
+const minInlineCredentialLength = 12
+
+// InlineCredentials finds values in the raw config file that look like long-lived
+// credentials: known token formats anywhere, and long values of settings named like secrets.
+// Values using ${VAR} are not inline.
+func InlineCredentials(data []byte) []Finding {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil
+	}
+	var findings []Finding
+	walkScalars(&root, "", func(location, key, value string) {
+		if value == "" || strings.Contains(value, "$") {
+			return
+		}
+		for _, p := range credentialPatterns {
+			if p.pattern.MatchString(value) {
+				findings = append(findings, Finding{Severity: SeverityWarning, Location: location,
+					Message: fmt.Sprintf("looks like %s written inline; reference it with ${VAR}", p.kind)})
+				return
+			}
+		}
+		if credentialKey(key) && len(value) >= minInlineCredentialLength && !strings.ContainsAny(value, "/\\ ") {
+			findings = append(findings, Finding{Severity: SeverityWarning, Location: location,
+				Message: "looks like a credential written inline; reference it with ${VAR}"})
+		}
+	})
+	return findings
+}
+
+// credentialKey reports whether a setting name suggests a credential. Names of files and
+// identifiers, such as signing_secret_file or client_id, are not credentials themselves.
+func credentialKey(key string) bool {
+	key = strings.ToLower(key)
+	if strings.HasSuffix(key, "_file") || strings.HasSuffix(key, "_id") || strings.HasSuffix(key, "_path") {
+		return false
+	}
+	for _, word := range credentialKeyWords {
+		if strings.Contains(key, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// walkScalars calls fn with the location, key and value of every scalar in the document.
+// Sequence items with a name are located by it, e.g. auth_profiles[prod].config.client_secret.
+func walkScalars(node *yaml.Node, location string, fn func(location, key, value string)) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			walkScalars(child, location, fn)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i].Value, node.Content[i+1]
+			child := key
+			if location != "" {
+				child = location + "." + key
+			}
+			if value.Kind == yaml.ScalarNode {
+				fn(child, key, value.Value)
+				continue
+			}
+			walkScalars(value, child, fn)
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			index := fmt.Sprint(i)
+			if item.Kind == yaml.MappingNode {
+				for j := 0; j+1 < len(item.Content); j += 2 {
+					if item.Content[j].Value == "name" && item.Content[j+1].Value != "" {
+						index = item.Content[j+1].Value
+					}
+				}
+			}
+			if item.Kind == yaml.ScalarNode {
+				fn(fmt.Sprintf("%s[%s]", location, index), "", item.Value)
+				continue
+			}
+			walkScalars(item, fmt.Sprintf("%s[%s]", location, index), fn)
+		}
+	}
+}
+
+var (
+	warnedMu sync.Mutex
+	warned   = make(map[string]bool) // Inline credentials already warned about, by file and location
+)
+
+// checkInlineCredentials warns about credentials written inline in the config file, once per
+// location and process, or fails under StrictSecurity
+func checkInlineCredentials(path string, data []byte) error {
+	findings := InlineCredentials(data)
+	if len(findings) == 0 {
+		return nil
+	}
+	if StrictSecurity {
+		locations := make([]string, len(findings))
+		for i, f := range findings {
+			locations[i] = f.Location
+		}
+		return fmt.Errorf("credentials written inline in %s (refused by --strict-security): %s; reference them with ${VAR}",
+			path, strings.Join(locations, ", "))
+	}
+
+	warnedMu.Lock()
+	defer warnedMu.Unlock()
+	var fresh []Finding
+	for _, f := range findings {
+		if !warned[path+"\x00"+f.Location] {
+			warned[path+"\x00"+f.Location] = true
+			fresh = append(fresh, f)
+		}
+	}
+	if len(fresh) == 0 {
+		return nil
+	}
+	log.Printf("WARNING: ==== %d credential(s) written inline in %s ====", len(fresh), path)
+	for _, f := range fresh {
+		log.Printf("WARNING:   %s %s", f.Location, f.Message)
+	}
+	log.Printf("WARNING: Anyone who can read the file or its backups can use them. Move them to environment variables, or run with --strict-security to refuse such configurations.")
+	return nil
+}
@@ -0,0 +1,140 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// ProjectContext describes the project an alert is about to templated notifier config values,
+// e.g. `channel: "#drift-{{ .Project.Name }}"`
+type ProjectContext struct {
+	Name        string
+	Tags        []string
+	Owners      []string
+	Description string
+	RunbookURL  string
+	Outputs     map[string]string // Captured terraform outputs
+}
+
+// Tag returns the value of the project's first "key:value" or "key=value" tag, or "" when it
+// has none, e.g. {{ .Project.Tag "team" }}
+func (p ProjectContext) Tag(key string) string {
+	for _, tag := range p.Tags {
+		for _, sep := range []string{":", "="} {
+			if value, ok := strings.CutPrefix(tag, key+sep); ok {
+				return value
+			}
+		}
+	}
+	return ""
+}
+
+// Output returns the value of a captured terraform output, or "" when it was not captured, e.g.
+// {{ .Project.Output "environment" }}
+func (p ProjectContext) Output(name string) string {
+	return p.Outputs[name]
+}
+
+// notifierTemplateData is what templated notifier config values are rendered with
+type notifierTemplateData struct {
+	Project ProjectContext
+}
+
+// interpolateFuncs are available in templated notifier config values
+var interpolateFuncs = template.FuncMap{
+	"join":  strings.Join,
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+	"replace": func(old, new, s string) string {
+		return strings.ReplaceAll(s, old, new)
+	},
+	"default": func(def, s string) string {
+		if s == "" {
+			return def
+		}
+		return s
+	},
+}
+
+// notifierFileKeys are config values naming files, which are resolved at load time and so
+// cannot be templated
+var notifierFileKeys = []string{NotifierTLSCertFile, NotifierTLSKeyFile, NotifierTLSCAFile, NotifierTemplate}
+
+// isTemplated reports whether a config value is rendered per project
+func isTemplated(value string) bool {
+	return strings.Contains(value, "{{")
+}
+
+// parseValue parses a templated notifier config value
+func parseValue(key string, value string) (*template.Template, error) {
+	tmpl, err := template.New(key).Funcs(interpolateFuncs).Option("missingkey=error").Parse(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template in %s: %w", key, err)
+	}
+	return tmpl, nil
+}
+
+// sampleProject checks that templates only reference known project fields
+var sampleProject = ProjectContext{
+	Name:        "example",
+	Tags:        []string{"example"},
+	Owners:      []string{"example"},
+	Description: "example",
+	RunbookURL:  "https://example.com",
+	Outputs:     map[string]string{"example": "example"},
+}
+
+// validateTemplates checks that the templated config values of a notifier parse and render
+// for a sample project
+func (n *Notifier) validateTemplates() error {
+	for key, value := range n.Config {
+		if !isTemplated(value) {
+			continue
+		}
+		if containsValue(notifierFileKeys, key) {
+			return fmt.Errorf("%s cannot be templated", key)
+		}
+		tmpl, err := parseValue(key, value)
+		if err != nil {
+			return err
+		}
+		if err := tmpl.Execute(&strings.Builder{}, notifierTemplateData{Project: sampleProject}); err != nil {
+			return fmt.Errorf("invalid template in %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// ForProject returns the notifier with its templated config values rendered for a project.
+// A notifier without templated values is returned as is.
+func (n *Notifier) ForProject(project ProjectContext) (*Notifier, error) {
+	var rendered map[string]string
+	for key, value := range n.Config {
+		if !isTemplated(value) {
+			continue
+		}
+		if rendered == nil {
+			rendered = make(map[string]string, len(n.Config))
+			for k, v := range n.Config {
+				rendered[k] = v
+			}
+		}
+		tmpl, err := parseValue(key, value)
+		if err != nil {
+			return nil, err
+		}
+		var b strings.Builder
+		if err := tmpl.Execute(&b, notifierTemplateData{Project: project}); err != nil {
+			return nil, fmt.Errorf("failed to render %s of notifier %s: %w", key, n.Name, err)
+		}
+		rendered[key] = b.String()
+	}
+	if rendered == nil {
+		return n, nil
+	}
+
+	copied := *n
+	copied.Config = rendered
+	return &copied, nil
+}
@@ -0,0 +1,56 @@
+package config
+
+import "testing"
+
+func TestNotifierForProject(t *testing.T) {
+	n := &Notifier{Name: "slack", Type: "slack", Config: map[string]string{
+		SlackWebhookURL: "https://hooks.slack.com/services/T000/B000/XXX",
+		SlackChannel:    `#drift-{{ .Project.Tag "team" | default "platform" }}`,
+		NotifierLocale:  "en",
+	}}
+	if err := n.validateTemplates(); err != nil {
+		t.Fatal(err)
+	}
+
+	rendered, err := n.ForProject(ProjectContext{Name: "payments-db", Tags: []string{"prod", "team:payments"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := rendered.Config[SlackChannel]; got != "#drift-payments" {
+		t.Errorf("Expected #drift-payments, got %q", got)
+	}
+	if rendered.Config[SlackWebhookURL] != n.Config[SlackWebhookURL] {
+		t.Errorf("Expected plain values to be kept, got %q", rendered.Config[SlackWebhookURL])
+	}
+	if n.Config[SlackChannel] == "#drift-payments" {
+		t.Error("Expected the configured notifier to be left unchanged")
+	}
+
+	rendered, err = n.ForProject(ProjectContext{Name: "legacy"})
+	if err != nil || rendered.Config[SlackChannel] != "#drift-platform" {
+		t.Errorf("Expected the default channel, got %q, %v", rendered.Config[SlackChannel], err)
+	}
+
+	// Captured terraform outputs route alerts too, and are empty until captured
+	n.Config[SlackChannel] = `#drift-{{ .Project.Output "environment" | default "unknown" }}`
+	rendered, err = n.ForProject(ProjectContext{Name: "eks", Outputs: map[string]string{"environment": "staging"}})
+	if err != nil || rendered.Config[SlackChannel] != "#drift-staging" {
+		t.Errorf("Expected the channel of the environment output, got %q, %v", rendered.Config[SlackChannel], err)
+	}
+	rendered, err = n.ForProject(ProjectContext{Name: "eks"})
+	if err != nil || rendered.Config[SlackChannel] != "#drift-unknown" {
+		t.Errorf("Expected the default channel without outputs, got %q, %v", rendered.Config[SlackChannel], err)
+	}
+
+	// Unknown fields and templated file paths are caught at load time
+	for key, value := range map[string]string{
+		SlackChannel:     "#drift-{{ .Project.Team }}",
+		ZulipTopic:       "{{ .Project.Name ",
+		NotifierTemplate: "templates/{{ .Project.Name }}.tmpl",
+	} {
+		bad := &Notifier{Name: "bad", Config: map[string]string{key: value}}
+		if err := bad.validateTemplates(); err == nil {
+			t.Errorf("Expected an error for %s: %q", key, value)
+		}
+	}
+}
@@ -0,0 +1,263 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Lint finding severities, from least to most severe
+const (
+	SeverityInfo    = "info"
+	SeverityWarning = "warning"
+	SeverityError   = "error"
+)
+
+// Severities lists the lint severities from least to most severe
+var Severities = []string{SeverityInfo, SeverityWarning, SeverityError}
+
+// SeverityRank orders severities; unknown severities rank below info
+func SeverityRank(severity string) int {
+	for i, s := range Severities {
+		if s == severity {
+			return i
+		}
+	}
+	return -1
+}
+
+// Finding is a security issue found by Lint
+type Finding struct {
+	Severity string
+	Location string // e.g. "notifiers[ops-slack].webhook_url"
+	Message  string
+}
+
+// secretNotifierKeys are notifier settings that hold credentials
+var secretNotifierKeys = []string{EmailPassword, ZulipAPIKey, NotifierSigningSecret}
+
+// secretAuthKeys are auth profile settings that hold credentials
+var secretAuthKeys = []string{"secret_access_key", "session_token", "client_secret", AWSSecretAccessKey, AWSSessionToken, AzureClientSecret}
+
+// dangerousEnv are environment variables that change what terraform runs or loads when an
+// auth profile passes them through
+var dangerousEnv = []string{"PATH", "HOME", "LD_PRELOAD", "LD_LIBRARY_PATH", "DYLD_INSERT_LIBRARIES",
+	"DYLD_LIBRARY_PATH", "TF_CLI_CONFIG_FILE", "TF_CLI_ARGS", "TF_PLUGIN_CACHE_DIR", "TF_DATA_DIR"}
+
+// notifierURLKeys are notifier settings holding the endpoint that alerts are sent to
+var notifierURLKeys = []string{SlackWebhookURL, WebhookURL, ZulipSite}
+
+// Lint checks the config file at path for security issues: plaintext credentials, risky
+// environment passthrough and SSH options, plain HTTP notifier endpoints, and file
+// permissions. The configuration must be valid.
+func Lint(path string) ([]Finding, error) {
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Credentials are only plaintext if they are written in the file, so look at the
+	// configuration before environment variables are substituted
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+	var raw Config
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	var findings []Finding
+	add := func(severity, location, format string, args ...interface{}) {
+		findings = append(findings, Finding{Severity: severity, Location: location, Message: fmt.Sprintf(format, args...)})
+	}
+
+	for _, profile := range raw.AuthProfiles {
+		for _, key := range sortedKeys(profile.Config) {
+			location := fmt.Sprintf("auth_profiles[%s].%s", profile.Name, key)
+			if containsValue(secretAuthKeys, key) && isPlaintext(profile.Config[key]) {
+				add(SeverityError, location, "credential is written in plaintext; use ${VAR} or the provider's default credential chain")
+			}
+			if containsValue(dangerousEnv, strings.ToUpper(key)) || strings.HasPrefix(strings.ToUpper(key), "TF_CLI_ARGS") {
+				add(SeverityWarning, location, "passes %s to terraform, which changes what it executes or loads", strings.ToUpper(key))
+			}
+		}
+	}
+
+	for _, runner := range raw.Runners {
+		for _, option := range runner.SSHOptions {
+			name, value, _ := strings.Cut(option, "=")
+			location := fmt.Sprintf("runners[%s].ssh_options", runner.Name)
+			switch {
+			case strings.EqualFold(name, "SendEnv") && strings.Contains(value, "*"):
+				add(SeverityWarning, location, "%s forwards every matching environment variable, including credentials, to the runner", option)
+			case strings.EqualFold(name, "StrictHostKeyChecking") && strings.EqualFold(value, "no"):
+				add(SeverityWarning, location, "%s accepts any host key, allowing the runner to be impersonated", option)
+			case strings.EqualFold(name, "ForwardAgent") && strings.EqualFold(value, "yes"):
+				add(SeverityWarning, location, "%s lets the runner use your SSH keys", option)
+			}
+		}
+	}
+
+	for _, n := range raw.Notifiers {
+		for _, key := range sortedKeys(n.Config) {
+			value := n.Config[key]
+			location := fmt.Sprintf("notifiers[%s].%s", n.Name, key)
+			switch {
+			case containsValue(secretNotifierKeys, key) && isPlaintext(value):
+				add(SeverityError, location, "secret is written in plaintext; use ${VAR}")
+			case strings.HasPrefix(key, NotifierHeaderPrefix) && isSensitiveHeader(strings.TrimPrefix(key, NotifierHeaderPrefix)) && isPlaintext(value):
+				add(SeverityError, location, "credential header is written in plaintext; use ${VAR}")
+			case containsValue(notifierURLKeys, key) && isPlaintext(value) && hasURLCredentials(value):
+				add(SeverityWarning, location, "URL embeds a token in plaintext; use ${VAR}")
+			}
+		}
+	}
+
+	for i, hook := range raw.RunWebhooks {
+		location := fmt.Sprintf("run_webhooks[%d]", i)
+		if isPlaintext(hook.SigningSecret) {
+			add(SeverityError, location+".signing_secret", "secret is written in plaintext; use ${VAR}")
+		}
+		for _, name := range sortedKeys(hook.Headers) {
+			if isSensitiveHeader(name) && isPlaintext(hook.Headers[name]) {
+				add(SeverityError, location+".headers."+name, "credential header is written in plaintext; use ${VAR}")
+			}
+		}
+		if isPlaintext(hook.URL) && hasURLCredentials(hook.URL) {
+			add(SeverityWarning, location+".url", "URL embeds a token in plaintext; use ${VAR}")
+		}
+	}
+
+	if raw.Storage != nil && isPlaintext(raw.Storage.EncryptionKey) {
+		add(SeverityError, "storage.encryption_key", "encryption key is written in plaintext next to the data it protects; use ${VAR} or encryption_key_file")
+	}
+
+	// Endpoints may come from the environment, so check the substituted configuration
+	for _, n := range cfg.Notifiers {
+		for _, key := range notifierURLKeys {
+			if u, err := url.Parse(n.Config[key]); err == nil && u.Scheme == "http" {
+				location := fmt.Sprintf("notifiers[%s].%s", n.Name, key)
+				if isLoopback(u.Hostname()) {
+					add(SeverityInfo, location, "uses plain http:// to a local endpoint")
+				} else {
+					add(SeverityWarning, location, "sends drift details over plain http://; use https://")
+				}
+			}
+		}
+	}
+	for i, hook := range cfg.RunWebhooks {
+		if u, err := url.Parse(hook.URL); err == nil && u.Scheme == "http" && !isLoopback(u.Hostname()) {
+			add(SeverityWarning, fmt.Sprintf("run_webhooks[%d].url", i), "sends run reports over plain http://; use https://")
+		}
+	}
+
+	// Credentials recognized by their value, wherever they are written
+	reported := make(map[string]bool)
+	for _, f := range findings {
+		reported[f.Location] = true
+	}
+	for _, f := range InlineCredentials(data) {
+		if !reported[f.Location] {
+			add(SeverityError, f.Location, "%s", f.Message)
+		}
+	}
+
+	findings = append(findings, lintPermissions(path, "config file", true)...)
+	if cfg.Storage != nil && cfg.Storage.EncryptionKeyFile != "" {
+		findings = append(findings, lintPermissions(cfg.Storage.EncryptionKeyFile, "storage.encryption_key_file", false)...)
+	}
+	if cfg.Signing != nil {
+		findings = append(findings, lintPermissions(cfg.Signing.KeyFile, "signing.key_file", false)...)
+	}
+	for _, n := range cfg.Notifiers {
+		if keyFile := n.Config[NotifierTLSKeyFile]; keyFile != "" {
+			findings = append(findings, lintPermissions(keyFile, fmt.Sprintf("notifiers[%s].%s", n.Name, NotifierTLSKeyFile), false)...)
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		return SeverityRank(findings[i].Severity) > SeverityRank(findings[j].Severity)
+	})
+	return findings, nil
+}
+
+// lintPermissions flags files that other users can read or write. Configs may be readable
+// as long as they keep secrets in the environment; keys never should be.
+func lintPermissions(path string, location string, readableOK bool) []Finding {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil
+	}
+	mode := info.Mode().Perm()
+	name := filepath.Base(path)
+
+	switch {
+	case mode&0002 != 0:
+		return []Finding{{Severity: SeverityError, Location: location, Message: fmt.Sprintf("%s is world-writable (%04o); anyone can change it", name, mode)}}
+	case mode&0004 != 0 && readableOK:
+		return []Finding{{Severity: SeverityWarning, Location: location, Message: fmt.Sprintf("%s is world-readable (%04o); restrict it with chmod 640 or 600", name, mode)}}
+	case mode&0004 != 0:
+		return []Finding{{Severity: SeverityError, Location: location, Message: fmt.Sprintf("%s is world-readable (%04o); restrict it with chmod 600", name, mode)}}
+	}
+	return nil
+}
+
+// isPlaintext reports whether a raw config value is set without environment substitution
+func isPlaintext(value string) bool {
+	return value != "" && !strings.Contains(value, "$")
+}
+
+// isSensitiveHeader reports whether a header name usually carries a credential
+func isSensitiveHeader(name string) bool {
+	name = strings.ToLower(name)
+	for _, word := range []string{"authorization", "token", "key", "secret", "cookie"} {
+		if strings.Contains(name, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasURLCredentials reports whether a URL embeds a secret: user info, a token-like query
+// parameter, or a Slack-style webhook path
+func hasURLCredentials(value string) bool {
+	u, err := url.Parse(value)
+	if err != nil {
+		return false
+	}
+	if u.User != nil {
+		return true
+	}
+	for key := range u.Query() {
+		if isSensitiveHeader(key) || strings.EqualFold(key, "sig") {
+			return true
+		}
+	}
+	return strings.Contains(u.Path, "/services/") || strings.Contains(u.Path, "/webhookb2/")
+}
+
+// isLoopback reports whether host is localhost or a loopback address
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// sortedKeys returns the keys of a map in a stable order
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
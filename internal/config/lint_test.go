@@ -0,0 +1,74 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "network"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("LINT_WEBHOOK", "http://hooks.example.com/drift")
+
+	path := filepath.Join(dir, "config.yml")
+	content := `
+auth_profiles:
+  - name: prod
+    provider: azure
+    config:
+      client_secret: hunter2
+      client_id: ${ARM_CLIENT_ID}
+      PATH: /opt/evil
+notifiers:
+  - name: hook
+    type: webhook
+    config:
+      url: ${LINT_WEBHOOK}
+      signing_secret: ${SIGNING_SECRET}
+projects:
+  - name: network
+    path: ./network
+    auth_profile: prod
+    notifiers: [hook]
+`
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	findings, err := Lint(path)
+	if err != nil {
+		t.Fatalf("Lint failed: %v", err)
+	}
+
+	expected := map[string]string{
+		"auth_profiles[prod].client_secret": SeverityError,
+		"auth_profiles[prod].PATH":          SeverityWarning,
+		"notifiers[hook].url":               SeverityWarning,
+	}
+	if len(findings) != len(expected) {
+		t.Fatalf("Expected %d findings, got %+v", len(expected), findings)
+	}
+	for _, finding := range findings {
+		if expected[finding.Location] != finding.Severity {
+			t.Errorf("Unexpected finding %+v", finding)
+		}
+	}
+	if findings[0].Severity != SeverityError {
+		t.Errorf("Expected the most severe finding first, got %+v", findings[0])
+	}
+
+	if err := os.Chmod(path, 0666); err != nil {
+		t.Fatal(err)
+	}
+	findings, _ = Lint(path)
+	found := false
+	for _, finding := range findings {
+		found = found || (finding.Location == "config file" && finding.Severity == SeverityError)
+	}
+	if !found {
+		t.Errorf("Expected a world-writable config to be an error, got %+v", findings)
+	}
+}
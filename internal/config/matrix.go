@@ -0,0 +1,216 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RegionAxis is the matrix variable holding the region, set by regions or listed in a matrix
+const RegionAxis = "region"
+
+// DefaultRegionVar is the terraform variable set to the region of a multi-region project copy
+const DefaultRegionVar = "region"
+
+// regionEnv are the environment variables set to the region of a multi-region project copy
+var regionEnv = []string{"AWS_REGION", "AWS_DEFAULT_REGION"}
+
+// matrixNamePattern matches valid matrix variable names
+var matrixNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Matrix expands a project into one copy per combination of the values of its variables,
+// like a CI matrix build. Variables keep the order they are listed in.
+type Matrix []MatrixAxis
+
+// MatrixAxis is one variable of a matrix and the values it takes
+type MatrixAxis struct {
+	Name   string
+	Values []string
+}
+
+// UnmarshalYAML reads a matrix from a mapping of variable names to lists of values
+func (m *Matrix) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: matrix must map variable names to lists of values", node.Line)
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		var values []string
+		if err := node.Content[i+1].Decode(&values); err != nil {
+			return fmt.Errorf("matrix variable %s: %w", node.Content[i].Value, err)
+		}
+		*m = append(*m, MatrixAxis{Name: node.Content[i].Value, Values: values})
+	}
+	return nil
+}
+
+// has reports whether the matrix has the named variable
+func (m Matrix) has(name string) bool {
+	for _, axis := range m {
+		if axis.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// validate checks that every variable is named, unique and has distinct, non-empty values
+func (m Matrix) validate() error {
+	names := make(map[string]bool)
+	for _, axis := range m {
+		if !matrixNamePattern.MatchString(axis.Name) || names[axis.Name] {
+			return fmt.Errorf("matrix variable %q is invalid or listed twice", axis.Name)
+		}
+		names[axis.Name] = true
+		if len(axis.Values) == 0 {
+			return fmt.Errorf("matrix variable %s has no values", axis.Name)
+		}
+		seen := make(map[string]bool)
+		for _, value := range axis.Values {
+			if value == "" || strings.ContainsAny(value, " /\\{}") || seen[value] {
+				return fmt.Errorf("matrix variable %s: value %q is empty, invalid or listed twice", axis.Name, value)
+			}
+			seen[value] = true
+		}
+	}
+	return nil
+}
+
+// combinations returns every combination of values in variable order, the last variable
+// changing fastest
+func (m Matrix) combinations() [][]string {
+	combinations := [][]string{nil}
+	for _, axis := range m {
+		var next [][]string
+		for _, combination := range combinations {
+			for _, value := range axis.Values {
+				next = append(next, append(append([]string{}, combination...), value))
+			}
+		}
+		combinations = next
+	}
+	return combinations
+}
+
+// expandMatrix replaces each project with a matrix, or regions, by one copy per combination of
+// values. "{name}" in the copy's name, aliases, path, description, tags, vars, var files, env
+// values and workspace is replaced by the value of the variable name; a name without
+// placeholders gets the values appended, as in "<name>-<value>-<value>". Each copy is tagged
+// "<variable>:<value>". A copy with a region also has it set in AWS_REGION,
+// AWS_DEFAULT_REGION and TF_VAR_<region_var>, taking precedence over the project's own env.
+func expandMatrix(config *Config) error {
+	var projects []Project
+	for _, project := range config.Projects {
+		matrix := project.Matrix
+		if len(project.Regions) > 0 {
+			if matrix.has(RegionAxis) {
+				return fmt.Errorf("project %s: regions cannot be combined with a region matrix variable", project.Name)
+			}
+			matrix = append(append(Matrix{}, matrix...), MatrixAxis{Name: RegionAxis, Values: project.Regions})
+		}
+		if len(matrix) == 0 {
+			projects = append(projects, project)
+			continue
+		}
+		if err := matrix.validate(); err != nil {
+			return fmt.Errorf("project %s: %w", project.Name, err)
+		}
+
+		regionVar := project.RegionVar
+		if regionVar == "" {
+			regionVar = DefaultRegionVar
+		}
+		if strings.ContainsAny(regionVar, "= \t") {
+			return fmt.Errorf("project %s: invalid region_var %q", project.Name, regionVar)
+		}
+
+		for _, values := range matrix.combinations() {
+			instance, err := project.instance(matrix, values, regionVar)
+			if err != nil {
+				return fmt.Errorf("project %s: %w", project.Name, err)
+			}
+			projects = append(projects, instance)
+		}
+	}
+
+	// A copy must not take the name of another project, as they would share state
+	names := make(map[string]int)
+	for _, project := range projects {
+		names[project.Name]++
+	}
+	for _, project := range projects {
+		if project.MatrixValues != nil && names[project.Name] > 1 {
+			return fmt.Errorf("project %s expanded from a matrix clashes with another project of that name", project.Name)
+		}
+	}
+	config.Projects = projects
+	return nil
+}
+
+// instance returns the copy of a matrix project for one combination of values
+func (p Project) instance(matrix Matrix, values []string, regionVar string) (Project, error) {
+	replace := func(s string) string {
+		for i, axis := range matrix {
+			s = strings.ReplaceAll(s, "{"+axis.Name+"}", values[i])
+		}
+		return s
+	}
+	name := func(s string) string {
+		if strings.Contains(s, "{") {
+			return replace(s)
+		}
+		return s + "-" + strings.Join(values, "-")
+	}
+
+	expanded := p
+	expanded.Matrix = nil
+	expanded.Regions = nil
+	expanded.Name = name(p.Name)
+	if strings.ContainsAny(expanded.Name, "{}") {
+		return Project{}, fmt.Errorf("name %q uses a variable the matrix does not have", p.Name)
+	}
+	expanded.Path = replace(p.Path)
+	expanded.Description = replace(p.Description)
+	expanded.Workspace = replace(p.Workspace)
+
+	expanded.Aliases = nil
+	for _, alias := range p.Aliases {
+		expanded.Aliases = append(expanded.Aliases, name(alias))
+	}
+	expanded.Tags = nil
+	for _, tag := range p.Tags {
+		expanded.Tags = append(expanded.Tags, replace(tag))
+	}
+	expanded.VarFiles = nil
+	for _, file := range p.VarFiles {
+		expanded.VarFiles = append(expanded.VarFiles, replace(file))
+	}
+	if p.Vars != nil {
+		expanded.Vars = make(map[string]interface{}, len(p.Vars))
+		for key, value := range p.Vars {
+			if s, ok := value.(string); ok {
+				value = replace(s)
+			}
+			expanded.Vars[key] = value
+		}
+	}
+	expanded.Env = make(map[string]string, len(p.Env))
+	for key, value := range p.Env {
+		expanded.Env[key] = replace(value)
+	}
+
+	expanded.MatrixValues = make(map[string]string, len(matrix))
+	for i, axis := range matrix {
+		expanded.MatrixValues[axis.Name] = values[i]
+		expanded.Tags = append(expanded.Tags, axis.Name+":"+values[i])
+	}
+	if region, ok := expanded.MatrixValues[RegionAxis]; ok {
+		expanded.Region = region
+		for _, key := range regionEnv {
+			expanded.Env[key] = region
+		}
+		expanded.Env["TF_VAR_"+regionVar] = region
+	}
+	return expanded, nil
+}
@@ -0,0 +1,95 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestExpandMatrix(t *testing.T) {
+	var project Project
+	err := yaml.Unmarshal([]byte(`name: app-{env}-{region}
+path: ./app
+workspace: "{env}"
+matrix:
+  env: [staging, prod]
+  region: [us-east-1, eu-west-1]
+vars:
+  environment: "{env}"
+  replicas: 3
+var_files: ["envs/{env}.tfvars"]
+tags: [team:web]
+`), &project)
+	if err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	cfg := &Config{Projects: []Project{project, {Name: "core", Path: "./core"}}}
+	if err := expandMatrix(cfg); err != nil {
+		t.Fatalf("expandMatrix failed: %v", err)
+	}
+
+	var names []string
+	for _, p := range cfg.Projects {
+		names = append(names, p.Name)
+	}
+	want := []string{"app-staging-us-east-1", "app-staging-eu-west-1", "app-prod-us-east-1", "app-prod-eu-west-1", "core"}
+	if !reflect.DeepEqual(names, want) {
+		t.Fatalf("Expected %v, got %v", want, names)
+	}
+
+	prod := cfg.Projects[3]
+	if prod.Workspace != "prod" || prod.Vars["environment"] != "prod" || prod.Vars["replicas"] != 3 || prod.VarFiles[0] != "envs/prod.tfvars" {
+		t.Errorf("Expected prod values, got %+v", prod)
+	}
+	if !reflect.DeepEqual(prod.Tags, []string{"team:web", "env:prod", "region:eu-west-1"}) {
+		t.Errorf("Expected matrix tags, got %v", prod.Tags)
+	}
+	if prod.Region != "eu-west-1" || prod.Env["AWS_REGION"] != "eu-west-1" || prod.Env["TF_VAR_region"] != "eu-west-1" {
+		t.Errorf("Expected the region in the environment, got %q and %v", prod.Region, prod.Env)
+	}
+	if cfg.Projects[0].Vars["environment"] != "staging" {
+		t.Errorf("Expected copies not to share vars, got %v", cfg.Projects[0].Vars)
+	}
+
+	for name, projects := range map[string][]Project{
+		"unknown variable": {{Name: "app-{stage}", Matrix: Matrix{{Name: "env", Values: []string{"prod"}}}}},
+		"duplicate value":  {{Name: "app", Matrix: Matrix{{Name: "env", Values: []string{"prod", "prod"}}}}},
+		"no values":        {{Name: "app", Matrix: Matrix{{Name: "env"}}}},
+		"regions and axis": {{Name: "app", Regions: []string{"us-east-1"}, Matrix: Matrix{{Name: "region", Values: []string{"eu-west-1"}}}}},
+		"name clash":       {{Name: "app", Matrix: Matrix{{Name: "env", Values: []string{"prod"}}}}, {Name: "app-prod"}},
+		"invalid variable": {{Name: "app", Matrix: Matrix{{Name: "my-env", Values: []string{"prod"}}}}},
+	} {
+		if err := expandMatrix(&Config{Projects: projects}); err == nil {
+			t.Errorf("Expected an error for %s", name)
+		}
+	}
+}
+
+func TestExpandRegions(t *testing.T) {
+	cfg := &Config{Projects: []Project{
+		{Name: "edge", Path: "./edge", Regions: []string{"us-east-1", "eu-west-1"}, RegionVar: "aws_region",
+			Tags: []string{"team:net"}, Aliases: []string{"cdn"}, Env: map[string]string{"AWS_REGION": "us-west-2", "TF_DATA_DIR": ".terraform-{region}"}},
+	}}
+	if err := expandMatrix(cfg); err != nil {
+		t.Fatalf("expandMatrix failed: %v", err)
+	}
+	if len(cfg.Projects) != 2 {
+		t.Fatalf("Expected 2 projects, got %+v", cfg.Projects)
+	}
+
+	eu := cfg.Projects[1]
+	if eu.Name != "edge-eu-west-1" || eu.Region != "eu-west-1" || eu.Path != "./edge" {
+		t.Errorf("Unexpected copy %+v", eu)
+	}
+	if !reflect.DeepEqual(eu.Tags, []string{"team:net", "region:eu-west-1"}) || !reflect.DeepEqual(eu.Aliases, []string{"cdn-eu-west-1"}) {
+		t.Errorf("Expected the region in tags and aliases, got %v and %v", eu.Tags, eu.Aliases)
+	}
+	want := map[string]string{"AWS_REGION": "eu-west-1", "AWS_DEFAULT_REGION": "eu-west-1", "TF_VAR_aws_region": "eu-west-1", "TF_DATA_DIR": ".terraform-eu-west-1"}
+	if !reflect.DeepEqual(eu.Env, want) {
+		t.Errorf("Expected env %v, got %v", want, eu.Env)
+	}
+	if cfg.Projects[0].Env["AWS_REGION"] != "us-east-1" {
+		t.Errorf("Expected each copy to have its own env, got %v", cfg.Projects[0].Env)
+	}
+}
@@ -0,0 +1,37 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// MetadataFlags holds metadata given on the command line. It is merged over the config's
+// metadata when LoadConfig loads it, so one config file can serve several deployments.
+var MetadataFlags map[string]string
+
+// metadataKeyPattern matches keys usable as Prometheus label names
+var metadataKeyPattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// applyMetadataFlags merges the command line metadata over the config's
+func applyMetadataFlags(config *Config) {
+	if len(MetadataFlags) == 0 {
+		return
+	}
+	if config.Metadata == nil {
+		config.Metadata = make(map[string]string)
+	}
+	for key, value := range MetadataFlags {
+		config.Metadata[key] = value
+	}
+}
+
+// validateMetadata checks that metadata keys can label metrics
+func validateMetadata(metadata map[string]string) error {
+	for key := range metadata {
+		if !metadataKeyPattern.MatchString(key) || strings.HasPrefix(key, "__") {
+			return fmt.Errorf("metadata: invalid key %q (use letters, digits and underscores, not starting with a digit or __)", key)
+		}
+	}
+	return nil
+}
@@ -1,20 +1,871 @@
 package config
 
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/terradrift-watcher/internal/cron"
+	"github.com/terradrift-watcher/pkg/event"
+)
+
 // Config represents the root configuration structure
 type Config struct {
 	Projects      []Project     `yaml:"projects"`
 	AuthProfiles  []AuthProfile `yaml:"auth_profiles"`
 	Notifiers     []Notifier    `yaml:"notifiers"`
+	Runners       []Runner      `yaml:"runners,omitempty"`
 	CheckInterval string        `yaml:"check_interval,omitempty"`
+	StateDir      string        `yaml:"state_dir,omitempty"`    // Where state is kept between runs
+	Concurrency   int           `yaml:"concurrency,omitempty"`  // Projects scanned in parallel (default 1)
+	MetricsFile   string        `yaml:"metrics_file,omitempty"` // Prometheus textfile written after each run
+	BadgeFile     string        `yaml:"badge_file,omitempty"`   // Fleet health badge written after each run
+
+	// DescribeAttributes annotates changed attributes with their type and description from
+	// `terraform providers schema -json`
+	DescribeAttributes bool `yaml:"describe_attributes,omitempty"`
+
+	AdaptiveScheduling *AdaptiveScheduling `yaml:"adaptive_scheduling,omitempty"`
+
+	// ScanCache skips plans whose outcome is known from a recent clean plan
+	ScanCache *ScanCache `yaml:"scan_cache,omitempty"`
+
+	// NoisePlans decides how plans that only read data sources or change outputs are
+	// treated: "ignore" (default) records them as noise without alerting, "drift" alerts
+	NoisePlans string `yaml:"noise_plans,omitempty"`
+
+	// ConfirmAfter holds back alerts about new drift until it persisted for this many
+	// consecutive scans, e.g. "2", or for a duration, e.g. "30m", so drift reported by
+	// eventually consistent cloud APIs that disappears again is not alerted. Projects can
+	// override it.
+	ConfirmAfter string `yaml:"confirm_after,omitempty"`
+
+	// NotifyResolved tells a project's notifiers when the drift of an alerted incident is resolved
+	NotifyResolved bool `yaml:"notify_resolved,omitempty"`
+
+	// Remediation keeps the plans of drifted projects, so drift approved for remediation in
+	// triage can be reverted with `remediate --approved`
+	Remediation *Remediation `yaml:"remediation,omitempty"`
+
+	// Triage configures the interactive triage of drift with `triage`
+	Triage *Triage `yaml:"triage,omitempty"`
+
+	// Flapping limits the drift alerts of projects alternating between drifted and clean
+	Flapping *Flapping `yaml:"flapping,omitempty"`
+
+	// ResourceLinks maps drifted resources to the pages linked from alerts and reports, such as
+	// cloud consoles or an internal CMDB
+	ResourceLinks *ResourceLinks `yaml:"resource_links,omitempty"`
+
+	// Owners maps drifted resources to the teams that own them. Rules can also
+	// be kept in a separate OwnershipFile, whose rules are appended to these.
+	Owners        []OwnerRule `yaml:"owners,omitempty"`
+	OwnershipFile string      `yaml:"ownership_file,omitempty"`
+
+	// VarsDir holds shared project variables: group_vars/<group> and project_vars/<project>
+	// files merged into the vars of each project at load time
+	VarsDir string `yaml:"vars_dir,omitempty"`
+
+	// OverridesFile holds the local project overrides written by `project disable` and
+	// `project enable` (default <config>.overrides.yml next to the configuration)
+	OverridesFile string `yaml:"overrides_file,omitempty"`
+
+	// Escalations page further notifiers when drift stays unresolved
+	Escalations []Escalation `yaml:"escalations,omitempty"`
+
+	// SLOs set how quickly drift must be resolved, per project tag. Breaches are alerted and
+	// compliance is shown in reports.
+	SLOs []SLO `yaml:"slos,omitempty"`
+
+	// ErrorRoutes alert notifiers about failed scans by the category of the failure
+	ErrorRoutes []ErrorRoute `yaml:"error_routes,omitempty"`
+
+	// Warnings alerts notifiers when a scan finds terraform warnings it has not seen before
+	Warnings *WarningAlerts `yaml:"warnings,omitempty"`
+
+	// Policy runs static analysis tools such as tflint and checkov over each scanned project
+	Policy *Policy `yaml:"policy,omitempty"`
+
+	// ReadOnlyCheck verifies before scanning that the credentials of auth profiles cannot write
+	ReadOnlyCheck *ReadOnlyCheck `yaml:"read_only_check,omitempty"`
+
+	// RunWebhooks are told when runs start and finish, separately from drift notifiers
+	RunWebhooks []RunWebhook `yaml:"run_webhooks,omitempty"`
+
+	// Correlation collapses the same drift in many projects into one fleet-level alert
+	Correlation *Correlation `yaml:"correlation,omitempty"`
+
+	// NotifyRetryBudget caps notification retries across all notifiers in one run
+	// (default 20, negative for unlimited)
+	NotifyRetryBudget *int `yaml:"notify_retry_budget,omitempty"`
+
+	// HTTPClient tunes the connections made by notifiers
+	HTTPClient *HTTPClient `yaml:"http_client,omitempty"`
+
+	// Storage keeps state, history and the outbox somewhere other than StateDir
+	Storage *Storage `yaml:"storage,omitempty"`
+
+	// Retention prunes the scan history after each run (default keep everything)
+	Retention *Retention `yaml:"retention,omitempty"`
+
+	// ControlSocket is where the daemon listens for commands such as trigger and reload
+	ControlSocket *ControlSocket `yaml:"control_socket,omitempty"`
+
+	// Timezone is the IANA time zone of schedules without a CRON_TZ= prefix and of digest
+	// times (default the zone of the watcher)
+	Timezone string `yaml:"timezone,omitempty"`
+
+	// Schedule runs the daemon at the times of a cron expression instead of every check_interval
+	Schedule string `yaml:"schedule,omitempty"`
+
+	// SuppressionWindows hold back drift alerts during maintenance windows
+	SuppressionWindows []SuppressionWindow `yaml:"suppression_windows,omitempty"`
+
+	// VerboseLog bounds the plan output logged in verbose mode
+	VerboseLog *VerboseLog `yaml:"verbose_log,omitempty"`
+
+	// HTMLDiffs writes a color-coded HTML page of each drift, linked from alerts when published
+	HTMLDiffs *HTMLDiffs `yaml:"html_diffs,omitempty"`
+
+	// DriftExport publishes the current drift state of every project after each run, e.g. as
+	// an OPA bundle that admission controllers consult before deploying to a drifted stack
+	DriftExport *DriftExport `yaml:"drift_export,omitempty"`
+
+	// Metadata identifies this watcher instance, e.g. environment, cluster or operator. It is
+	// attached to every event, notification, metric and report.
+	Metadata map[string]string `yaml:"metadata,omitempty"`
+
+	// Signing signs the reports, badges, state bundles and recorded plan fixtures the watcher
+	// writes, so they can be verified later
+	Signing *Signing `yaml:"signing,omitempty"`
+
+	// Source is the configuration file as it was read, before environment variables are
+	// expanded. A running daemon reports it to `config-diff`.
+	Source []byte `yaml:"-"`
+}
+
+// VerboseLog limits the full plan output logged with --verbose, so huge plans do not overwhelm
+// log ingestion such as journald or CloudWatch Logs. Plans are logged in chunks with a pause
+// between them; lines past MaxLines are saved in the state storage instead.
+type VerboseLog struct {
+	MaxLines      int    `yaml:"max_lines,omitempty"`      // Lines logged per plan (default 2000)
+	ChunkLines    int    `yaml:"chunk_lines,omitempty"`    // Lines per chunk (default 500)
+	ChunkInterval string `yaml:"chunk_interval,omitempty"` // Pause between chunks, e.g. "1s" (default 1s)
+}
+
+// Defaults of verbose plan logging
+const (
+	DefaultVerboseMaxLines      = 2000
+	DefaultVerboseChunkLines    = 500
+	DefaultVerboseChunkInterval = time.Second
+)
+
+// Limits returns the lines logged per plan and per chunk and the pause between chunks,
+// using the defaults for settings left out
+func (v *VerboseLog) Limits() (maxLines int, chunkLines int, interval time.Duration, err error) {
+	maxLines, chunkLines, interval = DefaultVerboseMaxLines, DefaultVerboseChunkLines, DefaultVerboseChunkInterval
+	if v == nil {
+		return maxLines, chunkLines, interval, nil
+	}
+	if v.MaxLines < 0 || v.ChunkLines < 0 {
+		return 0, 0, 0, fmt.Errorf("max_lines and chunk_lines must not be negative")
+	}
+	if v.MaxLines > 0 {
+		maxLines = v.MaxLines
+	}
+	if v.ChunkLines > 0 {
+		chunkLines = v.ChunkLines
+	}
+	if v.ChunkInterval != "" {
+		if interval, err = time.ParseDuration(v.ChunkInterval); err != nil || interval < 0 {
+			return 0, 0, 0, fmt.Errorf("invalid chunk_interval '%s'", v.ChunkInterval)
+		}
+	}
+	return maxLines, chunkLines, interval, nil
+}
+
+// HTMLDiffs renders each drifted project's plan as a standalone HTML page in Dir, named after
+// the project and drift fingerprint. When Dir is served at BaseURL, alerts link to the page.
+type HTMLDiffs struct {
+	Dir     string `yaml:"dir,omitempty"`      // Default html-diffs in the state directory
+	BaseURL string `yaml:"base_url,omitempty"` // e.g. https://drift.example.com/diffs
+}
+
+// DriftExport writes the drift state document to a file, uploads it with an HTTP PUT or stores
+// it in S3, depending on the destination
+type DriftExport struct {
+	Destination string            `yaml:"destination"`       // File path, http(s):// URL or s3://bucket/key
+	Format      string            `yaml:"format,omitempty"`  // "json" (default) or "bundle"
+	Headers     map[string]string `yaml:"headers,omitempty"` // Sent with HTTP uploads, e.g. an Authorization token
+
+	// S3 destinations: default those of storage when it is the same bucket, then AWS_REGION
+	Region   string `yaml:"region,omitempty"`
+	Endpoint string `yaml:"endpoint,omitempty"` // S3-compatible endpoint URL, e.g. MinIO
+
+	// HTTP uploads: a TLS client certificate (mutual TLS) and an extra CA bundle. The
+	// connections also use the http_client settings.
+	TLSCertFile string `yaml:"tls_cert_file,omitempty"`
+	TLSKeyFile  string `yaml:"tls_key_file,omitempty"`
+	TLSCAFile   string `yaml:"tls_ca_file,omitempty"`
+}
+
+// S3Storage returns the S3 settings for writing to bucket: the export's region and endpoint,
+// falling back to those of the state storage when it uses the same bucket
+func (e *DriftExport) S3Storage(bucket string, storage *Storage) Storage {
+	s3 := Storage{Type: StorageS3, Bucket: bucket, Region: e.Region, Endpoint: e.Endpoint}
+	if storage != nil && storage.Type == StorageS3 && storage.Bucket == bucket {
+		if s3.Region == "" {
+			s3.Region = storage.Region
+		}
+		if s3.Endpoint == "" {
+			s3.Endpoint = storage.Endpoint
+		}
+	}
+	return s3
+}
+
+// Drift export formats: a plain JSON document, or an OPA bundle (a gzipped tarball with the
+// document as data.json under the terradrift root)
+const (
+	ExportJSON   = "json"
+	ExportBundle = "bundle"
+)
+
+// Signing writes a detached Ed25519 signature next to each file the watcher writes
+type Signing struct {
+	KeyFile string `yaml:"key_file"` // PEM-encoded PKCS #8 Ed25519 private key
+}
+
+// Retention bounds the scan history. Any combination of limits may be set; the oldest
+// records are removed first. MaxAge and MaxLogs also bound the saved plan and apply logs.
+type Retention struct {
+	MaxAge     string `yaml:"max_age,omitempty"`     // e.g. "90d"
+	MaxRecords int    `yaml:"max_records,omitempty"` // Records kept across all projects
+	MaxSize    string `yaml:"max_size,omitempty"`    // e.g. "50MB"
+	MaxLogs    int    `yaml:"max_logs,omitempty"`    // Plan and apply logs kept per project
+}
+
+// Limits returns the parsed retention limits; zero means unlimited
+func (r *Retention) Limits() (maxAge time.Duration, maxRecords int, maxBytes int64, err error) {
+	if r.MaxAge != "" {
+		if maxAge, err = ParseDuration(r.MaxAge); err != nil {
+			return 0, 0, 0, fmt.Errorf("invalid max_age: %w", err)
+		}
+	}
+	if r.MaxRecords < 0 {
+		return 0, 0, 0, fmt.Errorf("max_records cannot be negative")
+	}
+	if r.MaxLogs < 0 {
+		return 0, 0, 0, fmt.Errorf("max_logs cannot be negative")
+	}
+	if r.MaxSize != "" {
+		if maxBytes, err = ParseSize(r.MaxSize); err != nil {
+			return 0, 0, 0, fmt.Errorf("invalid max_size: %w", err)
+		}
+	}
+	return maxAge, r.MaxRecords, maxBytes, nil
+}
+
+// Treatments of noise plans
+const (
+	NoiseIgnore = "ignore"
+	NoiseDrift  = "drift"
+)
+
+// Storage backends
+const (
+	StorageFS = "fs"
+	StorageS3 = "s3"
+)
+
+// Storage selects the backend for state, history and the outbox. Ephemeral containers
+// should use a remote backend so nothing is lost between runs.
+type Storage struct {
+	Type     string `yaml:"type"`               // "fs" (default, in state_dir) or "s3"
+	Bucket   string `yaml:"bucket,omitempty"`   // S3 bucket
+	Prefix   string `yaml:"prefix,omitempty"`   // Key prefix inside the bucket
+	Region   string `yaml:"region,omitempty"`   // Default AWS_REGION, then us-east-1
+	Endpoint string `yaml:"endpoint,omitempty"` // S3-compatible endpoint URL, e.g. MinIO
+
+	// EncryptionKey (base64, 32 bytes) or EncryptionKeyFile encrypts every stored object
+	// with AES-256-GCM
+	EncryptionKey     string `yaml:"encryption_key,omitempty"`
+	EncryptionKeyFile string `yaml:"encryption_key_file,omitempty"`
+}
+
+// Key returns the encryption key, or nil when stored objects are not encrypted
+func (s *Storage) Key() ([]byte, error) {
+	encoded := s.EncryptionKey
+	if s.EncryptionKeyFile != "" {
+		if encoded != "" {
+			return nil, fmt.Errorf("encryption_key and encryption_key_file cannot both be set")
+		}
+		data, err := os.ReadFile(s.EncryptionKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read encryption key: %w", err)
+		}
+		encoded = strings.TrimSpace(string(data))
+	}
+	if encoded == "" {
+		return nil, nil
+	}
+
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("encryption key must be base64: %w", err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("encryption key must be 32 bytes, got %d (generate one with 'openssl rand -base64 32')", len(key))
+	}
+	return key, nil
+}
+
+// HTTPClient holds network settings for outgoing HTTP requests, for locked-down
+// networks with split-horizon DNS or a single IP family
+type HTTPClient struct {
+	DialTimeout string `yaml:"dial_timeout,omitempty"` // e.g. "5s" (default 30s)
+	Resolver    string `yaml:"resolver,omitempty"`     // DNS server as host:port, instead of the system resolver
+	IPVersion   string `yaml:"ip_version,omitempty"`   // "4" or "6" to force an IP family
+}
+
+// Network returns the dial network for the configured IP version
+func (h *HTTPClient) Network() (string, error) {
+	switch h.IPVersion {
+	case "":
+		return "tcp", nil
+	case "4":
+		return "tcp4", nil
+	case "6":
+		return "tcp6", nil
+	default:
+		return "", fmt.Errorf("ip_version must be 4 or 6, got %q", h.IPVersion)
+	}
 }
 
+// Escalation sends drift that has persisted for longer than After to additional notifiers
+type Escalation struct {
+	Name      string   `yaml:"name"`
+	After     string   `yaml:"after"` // e.g. "72h" or "3d"
+	Notifiers []string `yaml:"notifiers"`
+	Projects  []string `yaml:"projects,omitempty"` // Limit the rule to these projects (default all)
+}
+
+// SLO is a drift response objective: drift in the projects with one of Tags must be resolved
+// within ResolveWithin, for at least Target percent of drift episodes
+type SLO struct {
+	Name          string   `yaml:"name"`
+	Tags          []string `yaml:"tags,omitempty"`      // Projects with any of these tags (default all)
+	ResolveWithin string   `yaml:"resolve_within"`      // e.g. "24h" or "3d"
+	Target        float64  `yaml:"target,omitempty"`    // Percent of drift episodes (default 100)
+	Notifiers     []string `yaml:"notifiers,omitempty"` // Alerted when drift breaches the SLO
+}
+
+// DefaultSLOTarget is the share of drift episodes that must meet an SLO when no target is set
+const DefaultSLOTarget = 100.0
+
+// Applies reports whether the SLO covers a project with the tags
+func (s SLO) Applies(tags []string) bool {
+	if len(s.Tags) == 0 {
+		return true
+	}
+	for _, tag := range tags {
+		if containsValue(s.Tags, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// Objective returns the parsed resolution time and the target percentage
+func (s SLO) Objective() (time.Duration, float64) {
+	within, _ := ParseDuration(s.ResolveWithin)
+	target := s.Target
+	if target == 0 {
+		target = DefaultSLOTarget
+	}
+	return within, target
+}
+
+// ErrorRoute alerts notifiers when a scan fails with one of the categories
+type ErrorRoute struct {
+	Categories []string `yaml:"categories,omitempty"` // e.g. [auth, backend] (default all)
+	Notifiers  []string `yaml:"notifiers"`
+	Projects   []string `yaml:"projects,omitempty"` // Limit the route to these projects (default all)
+}
+
+// Matches reports whether a failure of the project with the category takes the route
+func (r ErrorRoute) Matches(project string, category string) bool {
+	return (len(r.Categories) == 0 || containsValue(r.Categories, category)) &&
+		(len(r.Projects) == 0 || containsValue(r.Projects, project))
+}
+
+// WarningAlerts sends the terraform warnings new to a project, such as deprecated arguments,
+// to notifiers. Warnings are recorded for reports whether or not they are sent.
+type WarningAlerts struct {
+	Notifiers []string `yaml:"notifiers"`
+}
+
+// Policy runs static analysis stages over the configuration of each terraform project after its
+// plan. Findings are recorded for reports; those new to a project at or above NotifySeverity
+// are sent to Notifiers.
+type Policy struct {
+	Stages         []PolicyStage `yaml:"stages"`
+	Notifiers      []string      `yaml:"notifiers,omitempty"`
+	NotifySeverity string        `yaml:"notify_severity,omitempty"` // low, medium, high or critical (default high)
+}
+
+// DefaultPolicyNotifySeverity is the lowest severity of policy findings alerted by default
+const DefaultPolicyNotifySeverity = "high"
+
+// Severity returns the lowest severity of the findings alerted
+func (p *Policy) Severity() string {
+	if p.NotifySeverity == "" {
+		return DefaultPolicyNotifySeverity
+	}
+	return p.NotifySeverity
+}
+
+// PolicyStage is one static analysis tool run over each project
+type PolicyStage struct {
+	Tool    string   `yaml:"tool"`              // tflint, checkov or trivy (trivy config)
+	Args    []string `yaml:"args,omitempty"`    // Extra arguments, e.g. --config or --skip-check
+	Timeout string   `yaml:"timeout,omitempty"` // Kill the tool after this long, e.g. "5m" (default unlimited)
+}
+
+// Flapping defaults
+const (
+	DefaultFlapThreshold   = 4
+	DefaultFlapNotifyEvery = 24 * time.Hour
+)
+
+// Flapping marks a project as flapping once it changed between drifted and clean Threshold
+// times over its latest scans, and then sends its drift alerts at most once per NotifyEvery
+type Flapping struct {
+	Threshold   int    `yaml:"threshold,omitempty"`    // State changes over the latest 10 scans (default 4)
+	NotifyEvery string `yaml:"notify_every,omitempty"` // e.g. "12h" (default 24h)
+}
+
+// Every returns how often a flapping project's drift is alerted at most
+func (f *Flapping) Every() (time.Duration, error) {
+	if f.NotifyEvery == "" {
+		return DefaultFlapNotifyEvery, nil
+	}
+	every, err := ParseDuration(f.NotifyEvery)
+	if err != nil {
+		return 0, fmt.Errorf("invalid notify_every: %w", err)
+	}
+	if every <= 0 {
+		return 0, fmt.Errorf("notify_every must be positive")
+	}
+	return every, nil
+}
+
+// FlapThreshold returns how many state changes over a project's latest scans make it flapping
+func (c *Config) FlapThreshold() int {
+	if c.Flapping != nil && c.Flapping.Threshold > 0 {
+		return c.Flapping.Threshold
+	}
+	return DefaultFlapThreshold
+}
+
+// Confirmation is how long new drift must persist before it is alerted: a number of
+// consecutive drifted scans or a time since it was first seen. The zero value alerts at once.
+type Confirmation struct {
+	Scans    int
+	Duration time.Duration
+}
+
+// ParseConfirmation parses a confirm_after value, a number of scans or a duration
+func ParseConfirmation(value string) (Confirmation, error) {
+	if value == "" {
+		return Confirmation{}, nil
+	}
+	if scans, err := strconv.Atoi(value); err == nil {
+		if scans < 0 {
+			return Confirmation{}, fmt.Errorf("confirm_after must not be negative")
+		}
+		return Confirmation{Scans: scans}, nil
+	}
+	d, err := ParseDuration(value)
+	if err != nil || d < 0 {
+		return Confirmation{}, fmt.Errorf("confirm_after must be a number of scans or a duration, got '%s'", value)
+	}
+	return Confirmation{Duration: d}, nil
+}
+
+// Confirmed reports whether drift seen in scans consecutive scans since the given time is confirmed
+func (c Confirmation) Confirmed(scans int, since, now time.Time) bool {
+	if c.Duration > 0 {
+		return now.Sub(since) >= c.Duration
+	}
+	return scans >= c.Scans
+}
+
+// String describes the confirmation, e.g. "2 scans" or "30m0s"
+func (c Confirmation) String() string {
+	if c.Duration > 0 {
+		return c.Duration.String()
+	}
+	return fmt.Sprintf("%d scans", c.Scans)
+}
+
+// DriftConfirmation returns how long new drift in the project must persist before it is alerted
+func (c *Config) DriftConfirmation(project Project) (Confirmation, error) {
+	if project.ConfirmAfter != "" {
+		return ParseConfirmation(project.ConfirmAfter)
+	}
+	return ParseConfirmation(c.ConfirmAfter)
+}
+
+// DefaultReadOnlyCheckInterval is how often the credentials of an auth profile are checked by default
+const DefaultReadOnlyCheckInterval = 24 * time.Hour
+
+// ReadOnlyCheck verifies that the credentials of each auth profile used by a run can only read
+// and plan, alerting Notifiers when they can write. Profiles are checked again once Interval
+// has passed since their last check.
+type ReadOnlyCheck struct {
+	Allow     []string `yaml:"allow,omitempty"`     // Accepted AWS actions or Azure and GCP roles, e.g. for state backends
+	Interval  string   `yaml:"interval,omitempty"`  // e.g. "12h" (default 24h)
+	Enforce   bool     `yaml:"enforce,omitempty"`   // Skip projects whose credentials can write
+	Notifiers []string `yaml:"notifiers,omitempty"` // Alerted when a profile gains write access
+}
+
+// Every returns how long a check of an auth profile's credentials stays valid
+func (r *ReadOnlyCheck) Every() (time.Duration, error) {
+	if r.Interval == "" {
+		return DefaultReadOnlyCheckInterval, nil
+	}
+	interval, err := ParseDuration(r.Interval)
+	if err != nil {
+		return 0, fmt.Errorf("invalid interval: %w", err)
+	}
+	if interval <= 0 {
+		return 0, fmt.Errorf("interval must be positive")
+	}
+	return interval, nil
+}
+
+// RunWebhook receives run lifecycle events, e.g. for a scheduler chaining jobs on the watcher
+type RunWebhook struct {
+	URL           string            `yaml:"url"`
+	Events        []string          `yaml:"events,omitempty"`         // run.started, run.finished (default both)
+	Headers       map[string]string `yaml:"headers,omitempty"`        // e.g. an Authorization token
+	SigningSecret string            `yaml:"signing_secret,omitempty"` // Signs requests like webhook notifiers
+}
+
+// RunWebhookEvents lists the event types run webhooks can subscribe to
+var RunWebhookEvents = []string{event.TypeRunStarted, event.TypeRunFinished}
+
+// Wants reports whether the webhook subscribes to the event type
+func (w RunWebhook) Wants(eventType string) bool {
+	return len(w.Events) == 0 || containsValue(w.Events, eventType)
+}
+
+// DefaultCheckInterval is the time between runs in daemon mode when check_interval is not set
+const DefaultCheckInterval = time.Hour
+
+// Interval returns the time between runs in daemon mode
+func (c *Config) Interval() (time.Duration, error) {
+	if c.CheckInterval == "" {
+		return DefaultCheckInterval, nil
+	}
+	interval, err := ParseDuration(c.CheckInterval)
+	if err != nil {
+		return 0, fmt.Errorf("invalid check_interval: %w", err)
+	}
+	if interval <= 0 {
+		return 0, fmt.Errorf("check_interval must be positive")
+	}
+	return interval, nil
+}
+
+// ControlSocket configures the daemon's local control socket. Access is controlled by the
+// socket file's permissions: only the daemon's user may connect, plus members of Group.
+type ControlSocket struct {
+	Path  string `yaml:"path,omitempty"`  // Default terradrift-watcher.sock in the temp directory
+	Group string `yaml:"group,omitempty"` // Unix group also allowed to connect
+}
+
+// DefaultScanCacheMaxAge is how long a clean plan is reused by default
+const DefaultScanCacheMaxAge = 6 * time.Hour
+
+// ScanCache skips the plan of a project whose backend state serial and terraform code are
+// unchanged since its last clean plan, for up to MaxAge after that plan. Changes made outside
+// terraform are only noticed once the cached result has expired.
+type ScanCache struct {
+	MaxAge string `yaml:"max_age,omitempty"` // e.g. "6h" (default 6h)
+}
+
+// Window returns how long after a clean plan its result is reused
+func (s *ScanCache) Window() (time.Duration, error) {
+	if s.MaxAge == "" {
+		return DefaultScanCacheMaxAge, nil
+	}
+	maxAge, err := ParseDuration(s.MaxAge)
+	if err != nil {
+		return 0, fmt.Errorf("invalid max_age: %w", err)
+	}
+	if maxAge <= 0 {
+		return 0, fmt.Errorf("max_age must be positive")
+	}
+	return maxAge, nil
+}
+
+// DefaultCorrelationMinProjects is how many projects must share a drift pattern by default
+// before their alerts are collapsed
+const DefaultCorrelationMinProjects = 3
+
+// Correlation collapses projects drifting with the same resource type and attribute pattern,
+// such as an org-wide tag policy change, into one fleet-level alert
+type Correlation struct {
+	MinProjects int      `yaml:"min_projects,omitempty"` // Projects sharing a pattern (default 3)
+	Notifiers   []string `yaml:"notifiers,omitempty"`    // Default: the notifiers of the affected projects
+}
+
+// Threshold returns the number of projects that must share a drift pattern
+func (c *Correlation) Threshold() int {
+	if c.MinProjects > 0 {
+		return c.MinProjects
+	}
+	return DefaultCorrelationMinProjects
+}
+
+// OwnerRule assigns an owner to resources whose address matches one of the patterns
+type OwnerRule struct {
+	Owner     string   `yaml:"owner"`
+	Resources []string `yaml:"resources"`           // Address patterns, '*' matches any characters
+	Projects  []string `yaml:"projects,omitempty"`  // Limit the rule to these projects (default all)
+	Notifiers []string `yaml:"notifiers,omitempty"` // Notifiers paged in addition to the project's own
+}
+
+// Remediation keeps plans for `remediate` in the state storage and bounds how long applying
+// them may take
+type Remediation struct {
+	ApplyTimeout string `yaml:"apply_timeout,omitempty"` // Default 30m
+}
+
+// DefaultApplyTimeout bounds terraform apply when remediation sets no apply_timeout
+const DefaultApplyTimeout = 30 * time.Minute
+
+// Timeout returns how long terraform apply may run
+func (r *Remediation) Timeout() (time.Duration, error) {
+	if r.ApplyTimeout == "" {
+		return DefaultApplyTimeout, nil
+	}
+	return ParseDuration(r.ApplyTimeout)
+}
+
+// Triage configures the `triage` command
+type Triage struct {
+	// TicketNotifiers are sent a drift when an operator opens a ticket for it, e.g. a webhook
+	// that creates issues in the tracker
+	TicketNotifiers []string `yaml:"ticket_notifiers,omitempty"`
+}
+
+// ResourceLinks configures how drifted resources are linked. Resolvers are tried in order
+// before the built-in cloud console links.
+type ResourceLinks struct {
+	// Builtin links AWS resources to the AWS console and Azure resources to the Azure portal
+	// (default true)
+	Builtin   *bool          `yaml:"builtin,omitempty"`
+	Resolvers []LinkResolver `yaml:"resolvers,omitempty"`
+}
+
+// BuiltinEnabled reports whether the built-in cloud console links are used
+func (r *ResourceLinks) BuiltinEnabled() bool {
+	return r == nil || r.Builtin == nil || *r.Builtin
+}
+
+// LinkResolver links the resources matching its patterns to a URL template, e.g.
+// "https://cmdb.example.com/ci?id={attributes.id}"
+type LinkResolver struct {
+	Name      string   `yaml:"name"`
+	Types     []string `yaml:"types,omitempty"`     // Resource type patterns, '*' matches any characters (default all)
+	Resources []string `yaml:"resources,omitempty"` // Address patterns (default all)
+	Projects  []string `yaml:"projects,omitempty"`  // Limit the resolver to these projects (default all)
+	URL       string   `yaml:"url"`                 // Placeholders: {address}, {type}, {attributes.<name>}
+}
+
+// OwnershipFile is the format of the file referenced by ownership_file
+type OwnershipFile struct {
+	Owners []OwnerRule `yaml:"owners"`
+}
+
+// AdaptiveScheduling scans drift-prone projects more often than stable ones,
+// choosing each project's interval between MinInterval and MaxInterval
+type AdaptiveScheduling struct {
+	Enabled     bool   `yaml:"enabled"`
+	MinInterval string `yaml:"min_interval"` // e.g. "1h"
+	MaxInterval string `yaml:"max_interval"` // e.g. "24h"
+}
+
+// ParseDuration parses a duration like time.ParseDuration, additionally accepting whole days ("7d")
+func ParseDuration(value string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid duration '%s'", value)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(value)
+}
+
+// ParseSize parses a size such as "512KB", "50MB" or "1GB" (powers of 1024) or a plain number of bytes
+func ParseSize(value string) (int64, error) {
+	units := []struct {
+		suffix string
+		size   int64
+	}{{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"B", 1}}
+
+	number, multiplier := strings.TrimSpace(strings.ToUpper(value)), int64(1)
+	for _, unit := range units {
+		if n, ok := strings.CutSuffix(number, unit.suffix); ok {
+			number, multiplier = strings.TrimSpace(n), unit.size
+			break
+		}
+	}
+	n, err := strconv.ParseInt(number, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size '%s'", value)
+	}
+	return n * multiplier, nil
+}
+
+// Intervals returns the parsed minimum and maximum scan intervals
+func (a *AdaptiveScheduling) Intervals() (time.Duration, time.Duration, error) {
+	minInterval, err := time.ParseDuration(a.MinInterval)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid min_interval '%s': %w", a.MinInterval, err)
+	}
+	maxInterval, err := time.ParseDuration(a.MaxInterval)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid max_interval '%s': %w", a.MaxInterval, err)
+	}
+	if minInterval > maxInterval {
+		return 0, 0, fmt.Errorf("min_interval %v is greater than max_interval %v", minInterval, maxInterval)
+	}
+	return minInterval, maxInterval, nil
+}
+
+// Project types
+const (
+	ProjectTypeTerraform = "terraform"
+	ProjectTypeCDKTF     = "cdktf"  // CDK for Terraform, synthesized before planning
+	ProjectTypePulumi    = "pulumi" // Experimental: previewed with pulumi instead of planned
+)
+
 // Project represents a Terraform project to monitor
 type Project struct {
 	Name        string   `yaml:"name"`
 	Path        string   `yaml:"path"`
+	Type        string   `yaml:"type,omitempty"` // terraform (default), cdktf or pulumi
 	AuthProfile string   `yaml:"auth_profile"`
 	Notifiers   []string `yaml:"notifiers"`
 	Enabled     *bool    `yaml:"enabled,omitempty"`
+	Runner      string   `yaml:"runner,omitempty"`      // Remote runner used to execute terraform
+	Tags        []string `yaml:"tags,omitempty"`        // Free-form labels used to group projects in reports
+	GitRef      string   `yaml:"git_ref,omitempty"`     // Plan from this tag or commit instead of the working tree
+	Description string   `yaml:"description,omitempty"` // What the project deploys, shown in alerts and reports
+	RunbookURL  string   `yaml:"runbook_url,omitempty"` // Remediation instructions linked from alerts and reports
+
+	// Aliases are earlier names of the project, oldest first. Its state, history and drift
+	// fingerprints carry over from them, so a rename does not re-alert on known drift.
+	Aliases []string `yaml:"aliases,omitempty"`
+
+	// Groups are the var groups of the vars_dir whose variables the project uses, in
+	// increasing precedence. Vars and VarFiles set here take precedence over all of them.
+	Groups   []string               `yaml:"groups,omitempty"`
+	Vars     map[string]interface{} `yaml:"vars,omitempty"`      // Terraform input variables passed with -var
+	VarFiles []string               `yaml:"var_files,omitempty"` // Passed with -var-file, relative to the project path
+
+	// Env holds environment variables set only for the project's terraform commands, e.g.
+	// TF_VAR_region or HTTPS_PROXY. They take precedence over the auth profile's variables.
+	Env map[string]string `yaml:"env,omitempty"`
+
+	// Timeouts bound how long terraform init and plan may run, as registry outages hang init
+	// and API throttling hangs plan. A command running longer is killed and the scan fails.
+	Timeouts *ProjectTimeouts `yaml:"timeouts,omitempty"`
+
+	// Stacks limits a cdktf project to these synthesized stacks (default all). For a pulumi
+	// project they are the stacks to preview (default the selected stack).
+	Stacks []string `yaml:"stacks,omitempty"`
+
+	// RequireCleanWorktree skips the project when its .tf files have uncommitted changes,
+	// since the plan would mix local edits with real drift
+	RequireCleanWorktree bool `yaml:"require_clean_worktree,omitempty"`
+
+	// Outputs names the root module outputs read with `terraform output -json` after each
+	// clean plan, e.g. a cluster endpoint, and shown in alerts and reports of later drift
+	Outputs []string `yaml:"outputs,omitempty"`
+
+	// ConfirmAfter overrides the configured confirm_after for the project, "0" alerting at once
+	ConfirmAfter string `yaml:"confirm_after,omitempty"`
+
+	// Workspace is the terraform workspace planned, selected with TF_WORKSPACE
+	Workspace string `yaml:"workspace,omitempty"`
+
+	// Matrix expands the project into one copy per combination of its variables' values, e.g.
+	// environment × region, with "{variable}" in the name, vars, var files, env and workspace
+	// replaced by the copy's values. Regions is shorthand for a matrix of the region alone.
+	// A copy with a region plans with it in AWS_REGION, AWS_DEFAULT_REGION and
+	// TF_VAR_<region_var>.
+	Matrix    Matrix   `yaml:"matrix,omitempty"`
+	Regions   []string `yaml:"regions,omitempty"`
+	RegionVar string   `yaml:"region_var,omitempty"` // Terraform variable set to the region (default "region")
+
+	// MatrixValues and Region are the values of a copy expanded from Matrix or Regions
+	MatrixValues map[string]string `yaml:"-"`
+	Region       string            `yaml:"-"`
+
+	// Override is the active local override that set Enabled, if any
+	Override *ProjectOverride `yaml:"-"`
+}
+
+// OriginalName returns the name the project was first configured with. Drift fingerprints are
+// derived from it so they stay the same across renames.
+func (p Project) OriginalName() string {
+	if len(p.Aliases) > 0 {
+		return p.Aliases[0]
+	}
+	return p.Name
+}
+
+// ProjectTimeouts limits the phases of a project's scan, e.g. "5m" (default unlimited)
+type ProjectTimeouts struct {
+	Init string `yaml:"init,omitempty"`
+	Plan string `yaml:"plan,omitempty"` // Also bounds pulumi preview
+}
+
+// Durations returns the parsed init and plan timeouts, zero when unset
+func (t *ProjectTimeouts) Durations() (time.Duration, time.Duration, error) {
+	if t == nil {
+		return 0, 0, nil
+	}
+	initTimeout, err := parseTimeout("init", t.Init)
+	if err != nil {
+		return 0, 0, err
+	}
+	planTimeout, err := parseTimeout("plan", t.Plan)
+	if err != nil {
+		return 0, 0, err
+	}
+	return initTimeout, planTimeout, nil
+}
+
+// parseTimeout parses the timeout of a phase, which must be positive when set
+func parseTimeout(phase, value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	d, err := ParseDuration(value)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid %s timeout '%s'", phase, value)
+	}
+	return d, nil
 }
 
 // AuthProfile represents authentication credentials for cloud providers
@@ -22,16 +873,83 @@ type AuthProfile struct {
 	Name     string            `yaml:"name"`
 	Provider string            `yaml:"provider"` // aws, azure, gcp
 	Config   map[string]string `yaml:"config"`   // Provider-specific config
+
+	// MaxConcurrency limits parallel plans using this profile to avoid API throttling (0 = unlimited)
+	MaxConcurrency int `yaml:"max_concurrency,omitempty"`
+}
+
+// Runner represents a remote host that executes terraform on behalf of the watcher,
+// used when only a bastion or runner host can reach the state backends
+type Runner struct {
+	Name         string   `yaml:"name"`
+	Type         string   `yaml:"type"` // ssh
+	Host         string   `yaml:"host"`
+	User         string   `yaml:"user,omitempty"`
+	Port         int      `yaml:"port,omitempty"`
+	IdentityFile string   `yaml:"identity_file,omitempty"`
+	WorkDir      string   `yaml:"work_dir,omitempty"`    // Base directory for relative project paths
+	SSHOptions   []string `yaml:"ssh_options,omitempty"` // Extra -o options, e.g. StrictHostKeyChecking=yes
 }
 
 // Notifier represents a notification channel configuration
 type Notifier struct {
 	Name    string            `yaml:"name"`
-	Type    string            `yaml:"type"` // slack, teams, email
+	Type    string            `yaml:"type"` // slack, teams, email, zulip, webhook, stdout
 	Config  map[string]string `yaml:"config"`
 	Enabled *bool             `yaml:"enabled,omitempty"`
+
+	// Digests schedules summaries of all open drift (email notifiers only),
+	// sent in addition to or instead of immediate alerts
+	Digests []Digest `yaml:"digests,omitempty"`
+
+	// BusinessHours limits alerts to working hours. Outside them alerts go to the OutsideHours
+	// notifiers instead, or wait for the first run within the hours when there are none.
+	BusinessHours *BusinessHours `yaml:"business_hours,omitempty"`
+	OutsideHours  []string       `yaml:"outside_hours,omitempty"`
+}
+
+// Digest sends a daily summary of open drift to its recipients at a local time of day
+type Digest struct {
+	To []string `yaml:"to"`
+	At string   `yaml:"at,omitempty"` // "HH:MM" in local time (default "09:00")
+
+	// Timezone is the IANA time zone of At (default the configured timezone)
+	Timezone string `yaml:"timezone,omitempty"`
 }
 
+// DefaultDigestTime is when digests are sent unless configured otherwise
+const DefaultDigestTime = "09:00"
+
+// DueSince returns the most recent time at or before now when the digest was scheduled
+func (d Digest) DueSince(now time.Time) (time.Time, error) {
+	at := d.At
+	if at == "" {
+		at = DefaultDigestTime
+	}
+	clock, err := time.Parse("15:04", at)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid digest time '%s', expected HH:MM", at)
+	}
+	if d.Timezone != "" {
+		location, err := cron.LoadLocation(d.Timezone)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid digest timezone: %w", err)
+		}
+		now = now.In(location)
+	}
+	due := time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), 0, 0, now.Location())
+	if due.After(now) {
+		due = due.AddDate(0, 0, -1)
+	}
+	return due, nil
+}
+
+// SlackPayloadFormats lists the supported values of payload_format
+var SlackPayloadFormats = []string{"attachments", "text", "workflow", "chatbot"}
+
+// Locales lists the languages with built-in notification text
+var Locales = []string{"en", "de", "fr", "es"}
+
 // AWS-specific auth config keys
 const (
 	AWSAccessKeyID     = "AWS_ACCESS_KEY_ID"
@@ -51,9 +969,29 @@ const (
 // Notification config keys
 const (
 	SlackWebhookURL = "webhook_url"
+	SlackChannel    = "channel" // Overrides the webhook's channel (legacy incoming webhooks)
+	WebhookURL      = "url"
 	TeamsWebhookURL = "webhook_url"
 	EmailSMTPHost   = "smtp_host"
 	EmailSMTPPort   = "smtp_port"
 	EmailFrom       = "from"
-	EmailTo         = "to"
+	EmailTo         = "to" // Comma-separated; leave empty for a digest-only notifier
+	EmailUsername   = "username"
+	EmailPassword   = "password"
+	ZulipSite       = "site"
+	ZulipBotEmail   = "bot_email"
+	ZulipAPIKey     = "api_key"
+	ZulipStream     = "stream"
+	ZulipTopic      = "topic" // "{project}" is replaced by the project name
+
+	// Keys shared by webhook-based notifiers
+	NotifierUserAgent     = "user_agent"
+	NotifierSigningSecret = "signing_secret"
+	NotifierHeaderPrefix  = "header." // e.g. "header.Authorization: Bearer ..."
+	NotifierLocale        = "locale"  // Language of built-in message text, see Locales
+	SlackPayloadFormat    = "payload_format"
+	NotifierTemplate      = "template" // Custom text/template file for the message body
+	NotifierTLSCertFile   = "tls_cert_file"
+	NotifierTLSKeyFile    = "tls_key_file"
+	NotifierTLSCAFile     = "tls_ca_file"
 )
@@ -0,0 +1,98 @@
+package config
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// NotifierTypes are the supported notifier types
+var NotifierTypes = []string{"slack", "teams", "email", "zulip", "webhook", "stdout"}
+
+// slackWebhookHosts serve Slack incoming webhooks, commercial and GovSlack
+var slackWebhookHosts = []string{"hooks.slack.com", "hooks.slack-gov.com"}
+
+// validateKeys checks the config keys required by the notifier's type, so a misconfigured
+// notifier fails at load instead of when drift is first detected. Disabled notifiers only
+// need a known type, as they are often kept as placeholders.
+func (n Notifier) validateKeys() error {
+	if !containsValue(NotifierTypes, n.Type) {
+		return fmt.Errorf("unknown type %s (supported: %s)", n.Type, strings.Join(NotifierTypes, ", "))
+	}
+	if n.Enabled != nil && !*n.Enabled {
+		return nil
+	}
+
+	switch n.Type {
+	case "slack":
+		if err := n.requireURL(SlackWebhookURL, "https"); err != nil {
+			return err
+		}
+		u, _ := url.Parse(n.Config[SlackWebhookURL])
+		if !containsValue(slackWebhookHosts, u.Hostname()) || !strings.HasPrefix(u.Path, "/") || u.Path == "/" {
+			return fmt.Errorf("%s must be a Slack incoming webhook such as https://hooks.slack.com/services/...", SlackWebhookURL)
+		}
+
+	case "teams":
+		return n.requireURL(TeamsWebhookURL, "https")
+
+	case "webhook":
+		return n.requireURL(WebhookURL, "http", "https")
+
+	case "zulip":
+		if err := n.requireURL(ZulipSite, "https"); err != nil {
+			return err
+		}
+		return n.require(ZulipBotEmail, ZulipAPIKey, ZulipStream)
+
+	case "email":
+		if err := n.require(EmailSMTPHost, EmailFrom); err != nil {
+			return err
+		}
+		if port := n.Config[EmailSMTPPort]; port != "" {
+			if p, err := strconv.Atoi(port); err != nil || p < 1 || p > 65535 {
+				return fmt.Errorf("%s must be a port number, got %q", EmailSMTPPort, port)
+			}
+		}
+		if _, err := mail.ParseAddress(n.Config[EmailFrom]); err != nil {
+			return fmt.Errorf("%s is not an email address: %q", EmailFrom, n.Config[EmailFrom])
+		}
+		// Notifiers without recipients only send digests
+		to := n.Config[EmailTo]
+		if strings.TrimSpace(to) == "" {
+			if len(n.Digests) == 0 {
+				return fmt.Errorf("%s is required unless the notifier only sends digests", EmailTo)
+			}
+			return nil
+		}
+		if _, err := mail.ParseAddressList(to); err != nil {
+			return fmt.Errorf("%s is not a comma-separated list of email addresses: %q", EmailTo, to)
+		}
+	}
+	return nil
+}
+
+// require checks that the config keys are set. An empty value usually means the environment
+// variable it references is not set.
+func (n Notifier) require(keys ...string) error {
+	for _, key := range keys {
+		if strings.TrimSpace(n.Config[key]) == "" {
+			return fmt.Errorf("%s is required", key)
+		}
+	}
+	return nil
+}
+
+// requireURL checks that the config key is set to an absolute URL with one of the schemes
+func (n Notifier) requireURL(key string, schemes ...string) error {
+	if err := n.require(key); err != nil {
+		return err
+	}
+	u, err := url.Parse(n.Config[key])
+	if err != nil || u.Host == "" || !containsValue(schemes, u.Scheme) {
+		return fmt.Errorf("%s must be a %s:// URL", key, strings.Join(schemes, ":// or "))
+	}
+	return nil
+}
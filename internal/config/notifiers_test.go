@@ -0,0 +1,44 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNotifierValidateKeys(t *testing.T) {
+	disabled := false
+	tests := []struct {
+		name     string
+		notifier Notifier
+		err      string
+	}{
+		{"slack", Notifier{Type: "slack", Config: map[string]string{"webhook_url": "https://hooks.slack.com/services/T0/B0/x"}}, ""},
+		{"slack without url", Notifier{Type: "slack", Config: map[string]string{"webhook_url": ""}}, "webhook_url is required"},
+		{"slack elsewhere", Notifier{Type: "slack", Config: map[string]string{"webhook_url": "https://example.com/services/x"}}, "Slack incoming webhook"},
+		{"slack over http", Notifier{Type: "slack", Config: map[string]string{"webhook_url": "http://hooks.slack.com/services/x"}}, "https://"},
+		{"webhook", Notifier{Type: "webhook", Config: map[string]string{"url": "http://collector:8080/drift"}}, ""},
+		{"webhook without scheme", Notifier{Type: "webhook", Config: map[string]string{"url": "collector:8080"}}, "url must be"},
+		{"zulip", Notifier{Type: "zulip", Config: map[string]string{"site": "https://acme.zulipchat.com", "bot_email": "bot@acme.com", "api_key": "k", "stream": "infra"}}, ""},
+		{"zulip without stream", Notifier{Type: "zulip", Config: map[string]string{"site": "https://acme.zulipchat.com", "bot_email": "bot@acme.com", "api_key": "k"}}, "stream is required"},
+		{"email", Notifier{Type: "email", Config: map[string]string{"smtp_host": "smtp.acme.com", "smtp_port": "587", "from": "drift@acme.com", "to": "ops@acme.com, sre@acme.com"}}, ""},
+		{"email without host", Notifier{Type: "email", Config: map[string]string{"from": "drift@acme.com", "to": "ops@acme.com"}}, "smtp_host is required"},
+		{"email bad port", Notifier{Type: "email", Config: map[string]string{"smtp_host": "smtp.acme.com", "smtp_port": "smtp", "from": "drift@acme.com", "to": "ops@acme.com"}}, "port number"},
+		{"email without recipients", Notifier{Type: "email", Config: map[string]string{"smtp_host": "smtp.acme.com", "from": "drift@acme.com"}}, "to is required"},
+		{"digest-only email", Notifier{Type: "email", Config: map[string]string{"smtp_host": "smtp.acme.com", "from": "drift@acme.com"}, Digests: []Digest{{To: []string{"lead@acme.com"}}}}, ""},
+		{"stdout", Notifier{Type: "stdout"}, ""},
+		{"disabled placeholder", Notifier{Type: "teams", Enabled: &disabled}, ""},
+		{"unknown type", Notifier{Type: "pagerduty"}, "unknown type pagerduty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.notifier.validateKeys()
+			switch {
+			case tt.err == "" && err != nil:
+				t.Errorf("Expected no error, got %v", err)
+			case tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)):
+				t.Errorf("Expected an error containing %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Overrides are local changes to the configuration made with `project disable` and `project
+// enable`. They live in their own file, so operators can silence a project without editing
+// (and committing) a shared configuration.
+type Overrides struct {
+	Projects map[string]ProjectOverride `yaml:"projects,omitempty"`
+}
+
+// ProjectOverride replaces the enabled setting of a project, until a time or indefinitely
+type ProjectOverride struct {
+	Enabled bool      `yaml:"enabled"`
+	Since   time.Time `yaml:"since"`
+	Until   time.Time `yaml:"until,omitempty"` // Zero until removed
+	Reason  string    `yaml:"reason,omitempty"`
+}
+
+// Active reports whether the override still applies at now
+func (o ProjectOverride) Active(now time.Time) bool {
+	return o.Until.IsZero() || now.Before(o.Until)
+}
+
+// String describes the override, e.g. "disabled until 2025-01-10 00:00 (migration)"
+func (o ProjectOverride) String() string {
+	s := "disabled"
+	if o.Enabled {
+		s = "enabled"
+	}
+	if !o.Until.IsZero() {
+		s += " until " + o.Until.Local().Format("2006-01-02 15:04")
+	}
+	if o.Reason != "" {
+		s += " (" + o.Reason + ")"
+	}
+	return s
+}
+
+// DefaultOverridesPath returns the overrides file of a configuration file without
+// overrides_file: config.yml keeps its overrides in config.overrides.yml next to it
+func DefaultOverridesPath(configPath string) string {
+	ext := filepath.Ext(configPath)
+	return strings.TrimSuffix(configPath, ext) + ".overrides" + ext
+}
+
+// LoadOverrides reads an overrides file. A missing file has no overrides.
+func LoadOverrides(path string) (*Overrides, error) {
+	overrides := &Overrides{Projects: make(map[string]ProjectOverride)}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return overrides, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read overrides file %s: %w", path, err)
+	}
+	if err := yaml.Unmarshal(data, overrides); err != nil {
+		return nil, fmt.Errorf("failed to parse overrides file %s: %w", path, err)
+	}
+	if overrides.Projects == nil {
+		overrides.Projects = make(map[string]ProjectOverride)
+	}
+	return overrides, nil
+}
+
+// SaveOverrides replaces the overrides file. Expired overrides are dropped.
+func SaveOverrides(path string, overrides *Overrides, now time.Time) error {
+	for name, override := range overrides.Projects {
+		if !override.Active(now) {
+			delete(overrides.Projects, name)
+		}
+	}
+	data, err := yaml.Marshal(overrides)
+	if err != nil {
+		return fmt.Errorf("failed to encode overrides: %w", err)
+	}
+	data = append([]byte("# Written by terradrift-watcher project disable/enable; merged over the configuration at load\n"), data...)
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write overrides file: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to replace overrides file: %w", err)
+	}
+	return nil
+}
+
+// ParseUntil parses when an override ends: a date such as 2025-01-10 (midnight in location),
+// an RFC 3339 time, or a duration from now such as 4h or 7d
+func ParseUntil(value string, location *time.Location, now time.Time) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", value, location); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if d, err := ParseDuration(value); err == nil && d > 0 {
+		return now.Add(d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time '%s': expected a date (2025-01-10), an RFC 3339 time or a duration (7d)", value)
+}
+
+// applyOverrides sets the enabled setting of every project with an active override, found by
+// its name or an earlier one
+func (c *Config) applyOverrides(overrides *Overrides, now time.Time) {
+	for i := range c.Projects {
+		project := &c.Projects[i]
+		for _, name := range append([]string{project.Name}, project.Aliases...) {
+			override, ok := overrides.Projects[name]
+			if !ok || !override.Active(now) {
+				continue
+			}
+			enabled := override.Enabled
+			project.Enabled = &enabled
+			project.Override = &override
+			break
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadConfig_Overrides(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "test-config.yml")
+
+	configContent := `
+projects:
+  - name: network
+    path: .
+    aliases: [vpc]
+  - name: db
+    path: .
+    enabled: false
+  - name: dns
+    path: .
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config file: %v", err)
+	}
+	overridesPath := filepath.Join(tempDir, "test-config.overrides.yml")
+	if got := DefaultOverridesPath(configPath); got != overridesPath {
+		t.Fatalf("Expected overrides next to the config, got %s", got)
+	}
+
+	now := time.Now()
+	overrides := &Overrides{Projects: map[string]ProjectOverride{
+		"vpc": {Enabled: false, Since: now, Until: now.Add(time.Hour), Reason: "migration"},
+		"db":  {Enabled: true, Since: now},
+		"dns": {Enabled: false, Since: now.Add(-2 * time.Hour), Until: now.Add(-time.Hour)},
+	}}
+	if err := SaveOverrides(overridesPath, overrides, now); err != nil {
+		t.Fatalf("Failed to save overrides: %v", err)
+	}
+
+	config, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if config.OverridesFile != overridesPath {
+		t.Errorf("Expected the default overrides file, got %s", config.OverridesFile)
+	}
+	network, db, dns := config.Projects[0], config.Projects[1], config.Projects[2]
+	if *network.Enabled || network.Override == nil || network.Override.Reason != "migration" {
+		t.Errorf("Expected network disabled by the override of its earlier name, got %v, %+v", *network.Enabled, network.Override)
+	}
+	if !*db.Enabled {
+		t.Error("Expected db enabled by its override")
+	}
+	if !*dns.Enabled || dns.Override != nil {
+		t.Error("Expected the expired override of dns to be ignored")
+	}
+
+	// Saving drops expired overrides
+	saved, err := LoadOverrides(overridesPath)
+	if err != nil {
+		t.Fatalf("Failed to load overrides: %v", err)
+	}
+	if _, ok := saved.Projects["dns"]; ok || len(saved.Projects) != 2 {
+		t.Errorf("Expected the expired override to be dropped, got %v", saved.Projects)
+	}
+}
+
+func TestParseUntil(t *testing.T) {
+	location := time.FixedZone("UTC+2", 2*60*60)
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{"2025-01-10", time.Date(2025, 1, 10, 0, 0, 0, 0, location)},
+		{"2025-01-10T08:00:00Z", time.Date(2025, 1, 10, 8, 0, 0, 0, time.UTC)},
+		{"4h", now.Add(4 * time.Hour)},
+		{"7d", now.Add(7 * 24 * time.Hour)},
+	}
+	for _, tt := range tests {
+		got, err := ParseUntil(tt.value, location, now)
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("ParseUntil(%q) = %v, %v, want %v", tt.value, got, err, tt.want)
+		}
+	}
+	for _, invalid := range []string{"", "tomorrow", "2025-13-01", "0h"} {
+		if _, err := ParseUntil(invalid, location, now); err == nil {
+			t.Errorf("Expected an error for %q", invalid)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/terradrift-watcher/internal/cron"
+)
+
+// SuppressionWindow holds back drift alerts and escalations while it is open, such as during
+// a recurring maintenance window. Drift is still scanned and recorded, and alerted on the
+// first run after the window closes.
+type SuppressionWindow struct {
+	Name     string   `yaml:"name"`
+	Schedule string   `yaml:"schedule"`           // Cron expression of when the window opens, e.g. "CRON_TZ=Asia/Tokyo 0 1 * * sat"
+	Duration string   `yaml:"duration"`           // How long it stays open, e.g. "4h"
+	Projects []string `yaml:"projects,omitempty"` // Limit the window to these projects (default all)
+	Tags     []string `yaml:"tags,omitempty"`     // Or to projects with any of these tags
+}
+
+// Location returns the time zone of schedules and digest times that do not set their own
+func (c *Config) Location() (*time.Location, error) {
+	location, err := cron.LoadLocation(c.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("timezone: %w", err)
+	}
+	return location, nil
+}
+
+// RunSchedule returns the cron schedule of daemon runs, or nil when the daemon runs every
+// check_interval
+func (c *Config) RunSchedule() (*cron.Schedule, error) {
+	if c.Schedule == "" {
+		return nil, nil
+	}
+	location, err := c.Location()
+	if err != nil {
+		return nil, err
+	}
+	return cron.Parse(c.Schedule, location)
+}
+
+// Open returns when the window closes if it is open at t
+func (w SuppressionWindow) Open(c *Config, t time.Time) (time.Time, bool, error) {
+	location, err := c.Location()
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	schedule, err := cron.Parse(w.Schedule, location)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	duration, err := ParseDuration(w.Duration)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid duration: %w", err)
+	}
+	if duration <= 0 {
+		return time.Time{}, false, fmt.Errorf("duration must be positive")
+	}
+	until, open := schedule.Active(t, duration)
+	return until, open, nil
+}
+
+// Covers reports whether the window applies to the project
+func (w SuppressionWindow) Covers(project Project) bool {
+	if len(w.Projects) == 0 && len(w.Tags) == 0 {
+		return true
+	}
+	if containsValue(w.Projects, project.Name) {
+		return true
+	}
+	for _, tag := range project.Tags {
+		if containsValue(w.Tags, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// SuppressedBy returns the open suppression window covering the project at t, if any, and
+// when it closes
+func (c *Config) SuppressedBy(project Project, t time.Time) (*SuppressionWindow, time.Time) {
+	for i, window := range c.SuppressionWindows {
+		if !window.Covers(project) {
+			continue
+		}
+		// Windows are validated at load time
+		if until, open, err := window.Open(c, t); err == nil && open {
+			return &c.SuppressionWindows[i], until
+		}
+	}
+	return nil, time.Time{}
+}
+
+// DefaultBusinessDays are the days of BusinessHours unless configured otherwise
+const DefaultBusinessDays = "mon-fri"
+
+// BusinessHours are the working hours of a notifier's audience. A range with To before From
+// runs overnight into the next day.
+type BusinessHours struct {
+	Days     string `yaml:"days,omitempty"`     // Day of week expression, e.g. "mon-fri" (default) or "sun-thu"
+	From     string `yaml:"from"`               // "HH:MM"
+	To       string `yaml:"to"`                 // "HH:MM"
+	Timezone string `yaml:"timezone,omitempty"` // Default the configured timezone
+}
+
+// Contains reports whether t falls within the business hours
+func (b *BusinessHours) Contains(t time.Time) (bool, error) {
+	expr := b.Days
+	if expr == "" {
+		expr = DefaultBusinessDays
+	}
+	days, err := cron.ParseDays(expr)
+	if err != nil {
+		return false, fmt.Errorf("invalid days: %w", err)
+	}
+	from, err := clockMinutes(b.From)
+	if err != nil {
+		return false, fmt.Errorf("invalid from: %w", err)
+	}
+	to, err := clockMinutes(b.To)
+	if err != nil {
+		return false, fmt.Errorf("invalid to: %w", err)
+	}
+	if from == to {
+		return false, fmt.Errorf("from and to must differ")
+	}
+	location, err := cron.LoadLocation(b.Timezone)
+	if err != nil {
+		return false, err
+	}
+
+	local := t.In(location)
+	now := local.Hour()*60 + local.Minute()
+	today, yesterday := local.Weekday(), (local.Weekday()+6)%7
+	if from < to {
+		return days[today] && now >= from && now < to, nil
+	}
+	// Overnight hours belong to the day they start on
+	return (days[today] && now >= from) || (days[yesterday] && now < to), nil
+}
+
+// clockMinutes parses an "HH:MM" time of day into minutes since midnight
+func clockMinutes(clock string) (int, error) {
+	parsed, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, fmt.Errorf("'%s' is not a time of day, expected HH:MM", clock)
+	}
+	return parsed.Hour()*60 + parsed.Minute(), nil
+}
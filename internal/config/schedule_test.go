@@ -0,0 +1,99 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestBusinessHoursContains(t *testing.T) {
+	office := &BusinessHours{From: "09:00", To: "18:00", Timezone: "Europe/London"}
+	night := &BusinessHours{Days: "mon-thu", From: "22:00", To: "06:00", Timezone: "UTC"}
+
+	tests := []struct {
+		hours    *BusinessHours
+		at       time.Time
+		expected bool
+	}{
+		// Monday 08:30 UTC is 09:30 in London in summer
+		{office, time.Date(2024, 6, 3, 8, 30, 0, 0, time.UTC), true},
+		{office, time.Date(2024, 6, 3, 17, 0, 0, 0, time.UTC), false},
+		// Saturday
+		{office, time.Date(2024, 6, 8, 10, 0, 0, 0, time.UTC), false},
+		// Thursday night continues into Friday morning, Friday night does not start
+		{night, time.Date(2024, 6, 6, 23, 0, 0, 0, time.UTC), true},
+		{night, time.Date(2024, 6, 7, 5, 59, 0, 0, time.UTC), true},
+		{night, time.Date(2024, 6, 7, 23, 0, 0, 0, time.UTC), false},
+		{night, time.Date(2024, 6, 3, 5, 0, 0, 0, time.UTC), false},
+	}
+	for _, tt := range tests {
+		got, err := tt.hours.Contains(tt.at)
+		if err != nil {
+			t.Fatalf("Contains returned error: %v", err)
+		}
+		if got != tt.expected {
+			t.Errorf("Contains(%v) for %s-%s %s = %v, expected %v", tt.at, tt.hours.From, tt.hours.To, tt.hours.Days, got, tt.expected)
+		}
+	}
+
+	for _, invalid := range []*BusinessHours{
+		{From: "9am", To: "18:00"},
+		{From: "09:00", To: "09:00"},
+		{Days: "weekdays", From: "09:00", To: "18:00"},
+		{From: "09:00", To: "18:00", Timezone: "Nowhere/City"},
+	} {
+		if _, err := invalid.Contains(time.Now()); err == nil {
+			t.Errorf("Expected an error for %+v", invalid)
+		}
+	}
+}
+
+func TestLoadConfig_BusinessHours(t *testing.T) {
+	tempDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tempDir, "app"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	base := `
+timezone: Asia/Tokyo
+projects:
+  - name: app
+    path: ./app
+notifiers:
+  - name: pager
+    type: stdout
+`
+	configPath := filepath.Join(tempDir, "config.yml")
+	write := func(extra string) (*Config, error) {
+		if err := os.WriteFile(configPath, []byte(base+extra), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return LoadConfig(configPath)
+	}
+
+	cfg, err := write(`
+  - name: team
+    type: stdout
+    business_hours:
+      from: "09:00"
+      to: "18:00"
+    outside_hours: [pager]
+`)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if hours := cfg.Notifiers[1].BusinessHours; hours.Timezone != "Asia/Tokyo" {
+		t.Errorf("Expected business hours in the configured timezone, got %q", hours.Timezone)
+	}
+
+	for _, invalid := range []string{
+		"  - name: team\n    type: stdout\n    outside_hours: [pager]\n",
+		"  - name: team\n    type: stdout\n    business_hours: {from: \"09:00\", to: \"18:00\"}\n    outside_hours: [missing]\n",
+		"  - name: team\n    type: stdout\n    business_hours: {from: \"09:00\", to: \"18:00\"}\n    outside_hours: [team]\n",
+		"  - name: team\n    type: stdout\n    business_hours: {from: \"09:00\"}\n",
+	} {
+		if _, err := write(invalid); err == nil {
+			t.Errorf("Expected an error for %q", invalid)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"strings"
+
+	"github.com/terradrift-watcher/internal/redact"
+)
+
+// Secrets returns the credential values of the configuration after environment variables
+// are substituted, so they can be redacted from logs and notifications
+func (c *Config) Secrets() []string {
+	var secrets []string
+
+	for _, profile := range c.AuthProfiles {
+		for key, value := range profile.Config {
+			if containsValue(secretAuthKeys, key) || isSensitiveHeader(key) || strings.Contains(strings.ToLower(key), "password") {
+				secrets = append(secrets, value)
+			}
+		}
+	}
+
+	for _, project := range c.Projects {
+		for name, value := range project.Env {
+			if redact.SensitiveEnvName(name) {
+				secrets = append(secrets, value)
+			}
+		}
+	}
+
+	for _, n := range c.Notifiers {
+		for key, value := range n.Config {
+			switch {
+			case containsValue(secretNotifierKeys, key):
+				secrets = append(secrets, value)
+			case strings.HasPrefix(key, NotifierHeaderPrefix) && isSensitiveHeader(strings.TrimPrefix(key, NotifierHeaderPrefix)):
+				secrets = append(secrets, value)
+				// "Bearer <token>" is also redacted when the token appears on its own
+				if _, token, ok := strings.Cut(value, " "); ok {
+					secrets = append(secrets, token)
+				}
+			case containsValue(notifierURLKeys, key) && hasURLCredentials(value):
+				secrets = append(secrets, value)
+			}
+		}
+	}
+
+	for _, hook := range c.RunWebhooks {
+		if hook.SigningSecret != "" {
+			secrets = append(secrets, hook.SigningSecret)
+		}
+		for name, value := range hook.Headers {
+			if isSensitiveHeader(name) {
+				secrets = append(secrets, value)
+				if _, token, ok := strings.Cut(value, " "); ok {
+					secrets = append(secrets, token)
+				}
+			}
+		}
+		if hasURLCredentials(hook.URL) {
+			secrets = append(secrets, hook.URL)
+		}
+	}
+
+	if c.Storage != nil && c.Storage.EncryptionKey != "" {
+		secrets = append(secrets, c.Storage.EncryptionKey)
+	}
+	return secrets
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+)
+
+// Shard is one of Count slices of the projects, numbered from 1, so several watchers can
+// split one configuration without coordinating
+type Shard struct {
+	Index int
+	Count int
+}
+
+// ParseShard parses a shard like "2/5"
+func ParseShard(spec string) (Shard, error) {
+	index, count, ok := strings.Cut(strings.TrimSpace(spec), "/")
+	if !ok {
+		return Shard{}, fmt.Errorf("invalid shard %q: expected <index>/<count>, e.g. 2/5", spec)
+	}
+	var shard Shard
+	var err error
+	if shard.Index, err = strconv.Atoi(index); err != nil {
+		return Shard{}, fmt.Errorf("invalid shard index %q", index)
+	}
+	if shard.Count, err = strconv.Atoi(count); err != nil || shard.Count < 1 {
+		return Shard{}, fmt.Errorf("invalid shard count %q", count)
+	}
+	if shard.Index < 1 || shard.Index > shard.Count {
+		return Shard{}, fmt.Errorf("invalid shard %q: index must be between 1 and %d", spec, shard.Count)
+	}
+	return shard, nil
+}
+
+func (s Shard) String() string {
+	return fmt.Sprintf("%d/%d", s.Index, s.Count)
+}
+
+// Contains reports whether the project belongs to the shard. Projects are assigned by a hash
+// of their original name, so a rename does not move a project to another shard.
+func (s Shard) Contains(project Project) bool {
+	h := fnv.New32a()
+	h.Write([]byte(project.OriginalName()))
+	return int(h.Sum32()%uint32(s.Count)) == s.Index-1
+}
+
+// ApplyShard drops the projects outside the shard from the configuration
+func (c *Config) ApplyShard(shard Shard) {
+	projects := make([]Project, 0, len(c.Projects)/shard.Count+1)
+	for _, project := range c.Projects {
+		if shard.Contains(project) {
+			projects = append(projects, project)
+		}
+	}
+	c.Projects = projects
+}
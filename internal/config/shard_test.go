@@ -0,0 +1,53 @@
+package config
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestParseShard(t *testing.T) {
+	shard, err := ParseShard("2/5")
+	if err != nil || shard.Index != 2 || shard.Count != 5 || shard.String() != "2/5" {
+		t.Fatalf("Expected shard 2 of 5, got %+v, %v", shard, err)
+	}
+	for _, invalid := range []string{"", "2", "0/5", "6/5", "1/0", "a/5", "2/b"} {
+		if _, err := ParseShard(invalid); err == nil {
+			t.Errorf("Expected an error for %q", invalid)
+		}
+	}
+}
+
+func TestApplyShard(t *testing.T) {
+	var projects []Project
+	for i := 0; i < 100; i++ {
+		projects = append(projects, Project{Name: fmt.Sprintf("project-%d", i)})
+	}
+
+	// Every project lands in exactly one shard
+	seen := make(map[string]int)
+	for index := 1; index <= 3; index++ {
+		cfg := &Config{Projects: projects}
+		cfg.ApplyShard(Shard{Index: index, Count: 3})
+		if len(cfg.Projects) == 0 {
+			t.Errorf("Expected shard %d/3 to get projects", index)
+		}
+		for _, project := range cfg.Projects {
+			seen[project.Name]++
+		}
+	}
+	if len(seen) != len(projects) {
+		t.Errorf("Expected all %d projects to be sharded, got %d", len(projects), len(seen))
+	}
+	for name, count := range seen {
+		if count != 1 {
+			t.Errorf("Expected '%s' in one shard, found in %d", name, count)
+		}
+	}
+
+	// A renamed project stays in the shard of its original name
+	shard := Shard{Index: 1, Count: 3}
+	renamed := Project{Name: "renamed", Aliases: []string{"project-7"}}
+	if shard.Contains(renamed) != shard.Contains(Project{Name: "project-7"}) {
+		t.Error("Expected a rename to keep the project's shard")
+	}
+}
@@ -0,0 +1,177 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Directories of the vars_dir hierarchy
+const (
+	// GroupVarsDir holds one vars file or directory per group; AllGroup applies to every project
+	GroupVarsDir = "group_vars"
+
+	// ProjectVarsDir holds one vars file or directory per project, named after the project
+	ProjectVarsDir = "project_vars"
+
+	// AllGroup is the group every project belongs to
+	AllGroup = "all"
+)
+
+// VarGroup is a set of terraform input variables and var files kept in the vars_dir
+type VarGroup struct {
+	Vars     map[string]interface{} `yaml:"vars,omitempty"`
+	VarFiles []string               `yaml:"var_files,omitempty"`
+}
+
+// merge applies other on top of g: its vars replace those of the same name and its var files
+// are appended, so they take precedence in terraform
+func (g *VarGroup) merge(other VarGroup) {
+	if len(other.Vars) > 0 && g.Vars == nil {
+		g.Vars = make(map[string]interface{})
+	}
+	for name, value := range other.Vars {
+		g.Vars[name] = value
+	}
+	for _, file := range other.VarFiles {
+		// A file listed again moves to its later position
+		for i, existing := range g.VarFiles {
+			if existing == file {
+				g.VarFiles = append(g.VarFiles[:i], g.VarFiles[i+1:]...)
+				break
+			}
+		}
+		g.VarFiles = append(g.VarFiles, file)
+	}
+}
+
+// loadVarGroup reads the vars of one group or project from dir. Either name.yaml (or .yml)
+// or a name directory whose YAML files are merged in lexical order may be used. The second
+// result reports whether anything was found.
+func loadVarGroup(dir string, name string) (VarGroup, bool, error) {
+	var files []string
+	for _, ext := range []string{".yaml", ".yml"} {
+		file := filepath.Join(dir, name+ext)
+		if _, err := os.Stat(file); err == nil {
+			files = append(files, file)
+		}
+	}
+	if info, err := os.Stat(filepath.Join(dir, name)); err == nil && info.IsDir() {
+		entries, err := os.ReadDir(filepath.Join(dir, name))
+		if err != nil {
+			return VarGroup{}, false, fmt.Errorf("failed to read vars directory: %w", err)
+		}
+		var nested []string
+		for _, entry := range entries {
+			if !entry.IsDir() && (strings.HasSuffix(entry.Name(), ".yaml") || strings.HasSuffix(entry.Name(), ".yml")) {
+				nested = append(nested, filepath.Join(dir, name, entry.Name()))
+			}
+		}
+		sort.Strings(nested)
+		files = append(files, nested...)
+	}
+
+	var group VarGroup
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return VarGroup{}, false, fmt.Errorf("failed to read vars file %s: %w", file, err)
+		}
+		var fileGroup VarGroup
+		if err := yaml.Unmarshal([]byte(os.ExpandEnv(string(data))), &fileGroup); err != nil {
+			return VarGroup{}, false, fmt.Errorf("failed to parse vars file %s: %w", file, err)
+		}
+		group.merge(fileGroup)
+	}
+	return group, len(files) > 0, nil
+}
+
+// mergeProjectVars resolves the variables of every project from the vars_dir. Sources are
+// applied in increasing precedence: group_vars/all, the project's groups in the order listed,
+// project_vars/<project> and finally the vars and var_files set on the project itself.
+func mergeProjectVars(config *Config) error {
+	groupsDir := filepath.Join(config.VarsDir, GroupVarsDir)
+	groups := make(map[string]VarGroup)
+	loadGroup := func(name string, required bool) (VarGroup, error) {
+		if group, ok := groups[name]; ok {
+			return group, nil
+		}
+		group, found, err := loadVarGroup(groupsDir, name)
+		if err != nil {
+			return VarGroup{}, err
+		}
+		if !found && required {
+			return VarGroup{}, fmt.Errorf("no vars found for group %s in %s", name, groupsDir)
+		}
+		groups[name] = group
+		return group, nil
+	}
+
+	all, err := loadGroup(AllGroup, false)
+	if err != nil {
+		return err
+	}
+	for i := range config.Projects {
+		project := &config.Projects[i]
+		// Pulumi projects take their inputs from pulumi config instead
+		if project.Type == ProjectTypePulumi {
+			continue
+		}
+
+		var merged VarGroup
+		merged.merge(all)
+		for _, name := range project.Groups {
+			if name == AllGroup {
+				continue
+			}
+			group, err := loadGroup(name, true)
+			if err != nil {
+				return fmt.Errorf("project %s: %w", project.Name, err)
+			}
+			merged.merge(group)
+		}
+		own, _, err := loadVarGroup(filepath.Join(config.VarsDir, ProjectVarsDir), project.Name)
+		if err != nil {
+			return fmt.Errorf("project %s: %w", project.Name, err)
+		}
+		merged.merge(own)
+		merged.merge(VarGroup{Vars: project.Vars, VarFiles: project.VarFiles})
+
+		project.Vars = merged.Vars
+		project.VarFiles = merged.VarFiles
+	}
+	return nil
+}
+
+// VarValues returns the project's variables formatted for `terraform plan -var`. Strings are
+// passed as they are; lists and maps are encoded as JSON, which terraform accepts for
+// variables of complex types.
+func (p Project) VarValues() map[string]string {
+	if len(p.Vars) == 0 {
+		return nil
+	}
+	values := make(map[string]string, len(p.Vars))
+	for name, value := range p.Vars {
+		switch v := value.(type) {
+		case string:
+			values[name] = v
+		case nil:
+			values[name] = "null"
+		case map[string]interface{}, []interface{}:
+			data, err := json.Marshal(v)
+			if err != nil {
+				values[name] = fmt.Sprint(v)
+			} else {
+				values[name] = string(data)
+			}
+		default:
+			values[name] = fmt.Sprint(v)
+		}
+	}
+	return values
+}
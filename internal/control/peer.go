@@ -0,0 +1,38 @@
+package control
+
+import (
+	"fmt"
+	"net"
+	"os/user"
+	"strconv"
+)
+
+// peerUser returns the OS user of the process on the other end of a connection, from the
+// socket's peer credentials, so a client cannot act under another user's name
+func peerUser(conn net.Conn) (string, error) {
+	unixConn, ok := conn.(*net.UnixConn)
+	if !ok {
+		return "", fmt.Errorf("not a unix socket connection")
+	}
+	raw, err := unixConn.SyscallConn()
+	if err != nil {
+		return "", err
+	}
+	var uid uint32
+	var credErr error
+	if err := raw.Control(func(fd uintptr) {
+		uid, credErr = peerUID(int(fd))
+	}); err != nil {
+		return "", err
+	}
+	if credErr != nil {
+		return "", fmt.Errorf("failed to read peer credentials: %w", credErr)
+	}
+
+	id := strconv.FormatUint(uint64(uid), 10)
+	if u, err := user.LookupId(id); err == nil {
+		return u.Username, nil
+	}
+	// A uid without a user entry, e.g. in a container, is still who connected
+	return "uid " + id, nil
+}
@@ -0,0 +1,10 @@
+//go:build !linux && !darwin && !freebsd
+
+package control
+
+import "errors"
+
+// peerUID is not supported here, so requests that are audited are refused
+func peerUID(fd int) (uint32, error) {
+	return 0, errors.New("peer credentials are not supported on this platform")
+}
@@ -0,0 +1,96 @@
+package control
+
+import (
+	"sync"
+)
+
+// ScanQueue holds the scans waiting for the daemon. Triggered scans take priority over the
+// scheduled run, so a manual request never waits for a full scan of the fleet. Each project is
+// queued at most once however often it is triggered, and the scheduled run at most once, so a
+// burst of triggers costs one scan per project.
+type ScanQueue struct {
+	mu        sync.Mutex
+	triggered []string // In the order they were first requested
+	scheduled bool
+	ready     chan struct{}
+}
+
+// NewScanQueue returns an empty queue
+func NewScanQueue() *ScanQueue {
+	return &ScanQueue{ready: make(chan struct{}, 1)}
+}
+
+// Trigger queues scans of the projects. It returns the projects that were newly queued and
+// those that already were.
+func (q *ScanQueue) Trigger(projects []string) (queued []string, duplicates []string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, project := range projects {
+		if containsProject(q.triggered, project) || containsProject(queued, project) {
+			duplicates = append(duplicates, project)
+			continue
+		}
+		queued = append(queued, project)
+	}
+	q.triggered = append(q.triggered, queued...)
+	if len(queued) > 0 {
+		q.signal()
+	}
+	return queued, duplicates
+}
+
+// Schedule queues the scheduled run of every due project, unless it is already waiting
+func (q *ScanQueue) Schedule() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if !q.scheduled {
+		q.scheduled = true
+		q.signal()
+	}
+}
+
+// Next removes and returns the highest priority work: every triggered project, or when none
+// are queued, the scheduled run. It returns no projects and false when the queue is empty.
+func (q *ScanQueue) Next() ([]string, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.triggered) > 0 {
+		projects := q.triggered
+		q.triggered = nil
+		return projects, false
+	}
+	scheduled := q.scheduled
+	q.scheduled = false
+	return nil, scheduled
+}
+
+// Queued returns the triggered projects waiting to be scanned
+func (q *ScanQueue) Queued() []string {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return append([]string(nil), q.triggered...)
+}
+
+// Ready receives a value after work is queued. It may fire once more than there is work, so
+// Next can return nothing.
+func (q *ScanQueue) Ready() <-chan struct{} {
+	return q.ready
+}
+
+// signal wakes up a waiting Ready receiver without blocking
+func (q *ScanQueue) signal() {
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+}
+
+// containsProject reports whether the project is in the list
+func containsProject(projects []string, project string) bool {
+	for _, p := range projects {
+		if p == project {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,50 @@
+package control
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestScanQueuePriorityAndDedup(t *testing.T) {
+	q := NewScanQueue()
+	if projects, scheduled := q.Next(); projects != nil || scheduled {
+		t.Fatalf("Expected an empty queue, got %v, %v", projects, scheduled)
+	}
+
+	q.Schedule()
+	q.Schedule()
+	queued, duplicates := q.Trigger([]string{"network", "database", "network"})
+	if !reflect.DeepEqual(queued, []string{"network", "database"}) || !reflect.DeepEqual(duplicates, []string{"network"}) {
+		t.Errorf("Expected network and database queued once, got %v and duplicates %v", queued, duplicates)
+	}
+	// A webhook storm triggering the same projects again queues nothing new
+	for i := 0; i < 10; i++ {
+		if queued, _ := q.Trigger([]string{"database"}); len(queued) != 0 {
+			t.Fatalf("Expected database to be queued once, got %v", queued)
+		}
+	}
+	if got := q.Queued(); !reflect.DeepEqual(got, []string{"network", "database"}) {
+		t.Errorf("Expected the triggered projects in request order, got %v", got)
+	}
+
+	// Triggered scans go before the scheduled run, which is queued only once
+	if projects, scheduled := q.Next(); !reflect.DeepEqual(projects, []string{"network", "database"}) || scheduled {
+		t.Errorf("Expected the triggered projects first, got %v, %v", projects, scheduled)
+	}
+	if projects, scheduled := q.Next(); projects != nil || !scheduled {
+		t.Errorf("Expected the scheduled run next, got %v, %v", projects, scheduled)
+	}
+	if _, scheduled := q.Next(); scheduled {
+		t.Error("Expected the scheduled run to be queued once")
+	}
+
+	// A project scanned already can be triggered again
+	if queued, _ := q.Trigger([]string{"network"}); len(queued) != 1 {
+		t.Errorf("Expected network to be queued again after its scan, got %v", queued)
+	}
+	select {
+	case <-q.Ready():
+	default:
+		t.Error("Expected the queue to signal new work")
+	}
+}
@@ -0,0 +1,231 @@
+package control
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/terradrift-watcher/internal/state"
+)
+
+// Commands understood by a running daemon
+const (
+	// CommandStatus reports what the daemon is doing
+	CommandStatus = "status"
+
+	// CommandTrigger queues an immediate scan of the request's projects
+	CommandTrigger = "trigger"
+
+	// CommandPause pauses scanning for the request's duration, or until resumed
+	CommandPause = "pause"
+
+	// CommandResume lifts a pause
+	CommandResume = "resume"
+
+	// CommandReload reloads the configuration and reschedules the next run
+	CommandReload = "reload"
+
+	// CommandConfig reports the configuration the daemon last loaded
+	CommandConfig = "config"
+
+	// CommandIncidents lists the drift incidents of the request's projects, or all projects
+	CommandIncidents = "incidents"
+)
+
+// SocketFileName is the name of the daemon's control socket in the temp directory
+const SocketFileName = "terradrift-watcher.sock"
+
+// dialTimeout bounds how long a client waits for the daemon to accept and answer
+const dialTimeout = 10 * time.Second
+
+// Request is a command sent to a running daemon
+type Request struct {
+	Command  string   `json:"command"`
+	Projects []string `json:"projects,omitempty"` // trigger, incidents
+	For      string   `json:"for,omitempty"`      // pause, e.g. "4h"
+	Reason   string   `json:"reason,omitempty"`   // trigger, pause, resume; recorded in the audit log
+	Since    string   `json:"since,omitempty"`    // incidents, e.g. "7d"
+
+	// Principal is the OS user of the connecting process, read from the socket's peer
+	// credentials by the daemon rather than sent by the client. It is empty when the
+	// credentials could not be read.
+	Principal string `json:"-"`
+
+	// ClaimedBy is who the client says it acts for, e.g. a CI job. It is not verified and is
+	// only recorded next to the principal in the audit log.
+	ClaimedBy string `json:"principal,omitempty"`
+}
+
+// Response is the daemon's answer to a request
+type Response struct {
+	OK        bool             `json:"ok"`
+	Message   string           `json:"message,omitempty"`
+	Status    *Status          `json:"status,omitempty"`    // status
+	Config    *LoadedConfig    `json:"config,omitempty"`    // config
+	Incidents []state.Incident `json:"incidents,omitempty"` // incidents
+}
+
+// LoadedConfig is the configuration file a daemon last loaded
+type LoadedConfig struct {
+	Path     string    `json:"path"`
+	LoadedAt time.Time `json:"loaded_at"`
+	Content  string    `json:"content"` // As read, before environment variables are expanded
+}
+
+// Status describes a running daemon
+type Status struct {
+	PID          int       `json:"pid"`
+	StartedAt    time.Time `json:"started_at"`
+	Config       string    `json:"config"`
+	Scanning     bool      `json:"scanning"`
+	RunStartedAt time.Time `json:"run_started_at,omitempty"` // Set while scanning
+	NextRun      time.Time `json:"next_run"`
+	Queued       []string  `json:"queued,omitempty"` // Projects triggered but not yet scanned
+	Paused       string    `json:"paused,omitempty"` // Describes an active pause
+
+	// Health is the health score from 0 to 100 of each project scanned in the last 30 days
+	Health map[string]int `json:"health,omitempty"`
+}
+
+// Handler answers the requests received on the control socket
+type Handler func(Request) Response
+
+// DefaultSocketPath returns where the daemon listens when no path is configured: in the
+// default state directory, which only its user can write to
+func DefaultSocketPath() string {
+	return filepath.Join(state.DefaultDir(), SocketFileName)
+}
+
+// Listen serves requests on a unix socket at path, one JSON request and response per
+// connection, until the returned listener is closed. A socket left behind by a daemon that
+// did not shut down cleanly is replaced; one a daemon still listens on is an error.
+//
+// Connecting requires write permission on the socket, which is the only access control: only
+// the daemon's user can connect, or also members of group when it is set.
+func Listen(path string, group string, handler Handler) (net.Listener, error) {
+	if filepath.Dir(path) == state.DefaultDir() {
+		if err := state.PrepareDefaultDir(); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := os.Stat(path); err == nil {
+		if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("another daemon is already listening on %s", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("failed to remove stale control socket: %w", err)
+		}
+	}
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on control socket: %w", err)
+	}
+	if err := restrict(path, group); err != nil {
+		listener.Close()
+		return nil, err
+	}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			if err != nil {
+				log.Printf("WARNING: Control socket accept failed: %v", err)
+				continue
+			}
+			go serve(conn, handler)
+		}
+	}()
+	return listener, nil
+}
+
+// restrict limits access to the socket to its owner and, if set, the members of group
+func restrict(path string, group string) error {
+	mode := os.FileMode(0600)
+	if group != "" {
+		g, err := user.LookupGroup(group)
+		if err != nil {
+			return fmt.Errorf("control socket group: %w", err)
+		}
+		gid, err := strconv.Atoi(g.Gid)
+		if err != nil {
+			return fmt.Errorf("control socket group %s has no numeric id", group)
+		}
+		if err := os.Chown(path, -1, gid); err != nil {
+			return fmt.Errorf("failed to hand the control socket to group %s: %w", group, err)
+		}
+		mode = 0660
+	}
+	if err := os.Chmod(path, mode); err != nil {
+		return fmt.Errorf("failed to restrict control socket permissions: %w", err)
+	}
+	return nil
+}
+
+// serve answers the request of one connection
+func serve(conn net.Conn, handler Handler) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(dialTimeout))
+
+	var req Request
+	var resp Response
+	line, err := bufio.NewReader(conn).ReadBytes('\n')
+	if err == nil {
+		err = json.Unmarshal(line, &req)
+	}
+	if err != nil {
+		resp = Response{Message: fmt.Sprintf("invalid request: %v", err)}
+	} else {
+		if req.Principal, err = peerUser(conn); err != nil {
+			log.Printf("WARNING: Could not identify the control socket client: %v", err)
+		}
+		resp = handler(req)
+	}
+
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return
+	}
+	conn.Write(append(data, '\n'))
+}
+
+// Send sends a request to the daemon listening on path and returns its response
+func Send(path string, req Request) (Response, error) {
+	conn, err := net.DialTimeout("unix", path, dialTimeout)
+	if err != nil {
+		return Response{}, fmt.Errorf("no daemon is listening on %s: %w", path, err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(dialTimeout))
+
+	data, err := json.Marshal(req)
+	if err != nil {
+		return Response{}, err
+	}
+	if _, err := conn.Write(append(data, '\n')); err != nil {
+		return Response{}, fmt.Errorf("failed to send request: %w", err)
+	}
+
+	var resp Response
+	line, err := bufio.NewReader(conn).ReadBytes('\n')
+	if err != nil {
+		return Response{}, fmt.Errorf("failed to read response: %w", err)
+	}
+	if err := json.Unmarshal(line, &resp); err != nil {
+		return Response{}, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return resp, nil
+}
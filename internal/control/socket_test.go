@@ -0,0 +1,86 @@
+package control
+
+import (
+	"os"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestSocketRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "control.sock")
+	received := make(chan Request, 1)
+	listener, err := Listen(path, "", func(req Request) Response {
+		received <- req
+		return Response{OK: true, Message: "queued"}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	resp, err := Send(path, Request{Command: CommandTrigger, Projects: []string{"aws-prod-vpc"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.OK || resp.Message != "queued" {
+		t.Errorf("Expected the handler's response, got %+v", resp)
+	}
+	if req := <-received; req.Command != CommandTrigger || len(req.Projects) != 1 || req.Projects[0] != "aws-prod-vpc" {
+		t.Errorf("Expected the trigger request, got %+v", req)
+	}
+
+	// The principal comes from the peer credentials, whatever the client claims
+	if _, err := Send(path, Request{Command: CommandTrigger, ClaimedBy: "someone-else"}); err != nil {
+		t.Fatal(err)
+	}
+	req := <-received
+	if req.ClaimedBy != "someone-else" {
+		t.Errorf("Expected the claimed principal to be kept as a note, got %+v", req)
+	}
+	if runtime.GOOS == "linux" || runtime.GOOS == "darwin" || runtime.GOOS == "freebsd" {
+		current, err := user.Current()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if req.Principal != current.Username {
+			t.Errorf("Expected the principal %s from the peer credentials, got %q", current.Username, req.Principal)
+		}
+	}
+
+	// A second daemon must not take over a live socket
+	if _, err := Listen(path, "", nil); err == nil {
+		t.Error("Expected an error for a socket in use")
+	}
+}
+
+func TestSocketPermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("unix file modes do not apply")
+	}
+	path := filepath.Join(t.TempDir(), "control.sock")
+	listener, err := Listen(path, "", func(Request) Response { return Response{OK: true} })
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != 0600 {
+		t.Errorf("Expected the socket to be private to its owner, got mode %o", mode)
+	}
+
+	if _, err := Listen(filepath.Join(t.TempDir(), "other.sock"), "no-such-group-for-terradrift", nil); err == nil {
+		t.Error("Expected an error for an unknown group")
+	}
+}
+
+func TestSendWithoutDaemon(t *testing.T) {
+	if _, err := Send(filepath.Join(t.TempDir(), "missing.sock"), Request{Command: CommandTrigger}); err == nil {
+		t.Error("Expected an error without a daemon")
+	}
+}
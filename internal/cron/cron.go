@@ -0,0 +1,245 @@
+// Package cron parses cron expressions and evaluates them in a time zone
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	// Embed the zone database so schedules work in containers without one
+	_ "time/tzdata"
+)
+
+// Prefixes that set the time zone of an expression, e.g. "CRON_TZ=Europe/Berlin 0 22 * * 5"
+var zonePrefixes = []string{"CRON_TZ=", "TZ="}
+
+// descriptors are the supported shorthands for common expressions
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// field describes the range and names of one field of an expression
+type field struct {
+	name     string
+	min, max int
+	names    []string // Names of the values from min, if any
+}
+
+var fields = []field{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}},
+	// 7 is accepted as Sunday as well
+	{name: "day of week", min: 0, max: 7, names: []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}},
+}
+
+// maxSearch bounds how far ahead Next looks for a matching time
+const maxSearch = 5 * 366 * 24 * time.Hour
+
+// Schedule is a parsed cron expression of minute, hour, day of month, month and day of week
+type Schedule struct {
+	spec     string
+	location *time.Location
+	bits     [5]uint64 // Matching values of each field
+
+	// Days match the day of month or the day of week when both are restricted, as in cron
+	anyDay bool
+}
+
+// LoadLocation returns the named IANA time zone; "" and "Local" are the zone of the watcher
+func LoadLocation(name string) (*time.Location, error) {
+	if name == "" || name == "Local" {
+		return time.Local, nil
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone '%s'", name)
+	}
+	return location, nil
+}
+
+// Parse parses a five-field cron expression or @descriptor, optionally prefixed with
+// CRON_TZ=<zone> or TZ=<zone>. Expressions without a zone are evaluated in location.
+func Parse(spec string, location *time.Location) (*Schedule, error) {
+	s := &Schedule{spec: strings.TrimSpace(spec), location: location}
+	expr := s.spec
+	for _, prefix := range zonePrefixes {
+		if strings.HasPrefix(expr, prefix) {
+			zone := strings.TrimPrefix(expr, prefix)
+			if i := strings.IndexAny(zone, " \t"); i >= 0 {
+				zone, expr = zone[:i], zone[i:]
+			} else {
+				expr = ""
+			}
+			var err error
+			if s.location, err = LoadLocation(zone); err != nil {
+				return nil, fmt.Errorf("invalid schedule '%s': %w", spec, err)
+			}
+			break
+		}
+	}
+	if s.location == nil {
+		s.location = time.Local
+	}
+
+	expr = strings.TrimSpace(expr)
+	if descriptor, ok := descriptors[strings.ToLower(expr)]; ok {
+		expr = descriptor
+	}
+	parts := strings.Fields(expr)
+	if len(parts) != len(fields) {
+		return nil, fmt.Errorf("invalid schedule '%s': expected 5 fields (minute hour day-of-month month day-of-week), got %d", spec, len(parts))
+	}
+	for i, part := range parts {
+		bits, err := fields[i].parse(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule '%s': %w", spec, err)
+		}
+		s.bits[i] = bits
+	}
+	// Sunday may be written as 0 or 7
+	if s.bits[4]&(1<<7) != 0 {
+		s.bits[4] |= 1
+	}
+	s.anyDay = strings.HasPrefix(parts[2], "*") || strings.HasPrefix(parts[4], "*")
+	return s, nil
+}
+
+// parse returns the values matched by one field as a bit set
+func (f field) parse(expr string) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(expr, ",") {
+		rangeExpr, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			var err error
+			rangeExpr = part[:i]
+			if step, err = strconv.Atoi(part[i+1:]); err != nil || step < 1 {
+				return 0, fmt.Errorf("invalid step in %s field '%s'", f.name, part)
+			}
+		}
+
+		low, high := f.min, f.max
+		if rangeExpr != "*" {
+			bounds := strings.SplitN(rangeExpr, "-", 2)
+			var err error
+			if low, err = f.value(bounds[0]); err != nil {
+				return 0, err
+			}
+			high = low
+			if len(bounds) == 2 {
+				if high, err = f.value(bounds[1]); err != nil {
+					return 0, err
+				}
+			} else if step > 1 {
+				// "5/15" means from 5 to the end in steps of 15
+				high = f.max
+			}
+			if high < low {
+				return 0, fmt.Errorf("invalid range in %s field '%s'", f.name, part)
+			}
+		}
+		for v := low; v <= high; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// value parses a single number or name of the field
+func (f field) value(s string) (int, error) {
+	for i, name := range f.names {
+		if strings.EqualFold(s, name) {
+			return f.min + i, nil
+		}
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("invalid %s '%s' (expected %d-%d)", f.name, s, f.min, f.max)
+	}
+	return v, nil
+}
+
+// ParseDays parses a day of week field such as "mon-fri" or "sat,sun", returning which days
+// it matches indexed by time.Weekday
+func ParseDays(expr string) ([7]bool, error) {
+	var days [7]bool
+	bits, err := fields[4].parse(expr)
+	if err != nil {
+		return days, err
+	}
+	for day := range days {
+		days[day] = bits&(1<<uint(day)) != 0
+	}
+	days[time.Sunday] = days[time.Sunday] || bits&(1<<7) != 0
+	return days, nil
+}
+
+// String returns the expression as written
+func (s *Schedule) String() string {
+	return s.spec
+}
+
+// Location returns the time zone the schedule is evaluated in
+func (s *Schedule) Location() *time.Location {
+	return s.location
+}
+
+// Next returns the first matching time after t, or the zero time if there is none within
+// five years (such as on February 30th)
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.In(s.location)
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, s.location).Add(time.Minute)
+	limit := t.Add(maxSearch)
+
+	for t.Before(limit) {
+		switch {
+		case !s.matches(3, int(t.Month())):
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, s.location)
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, s.location)
+		case !s.matches(1, t.Hour()):
+			// Step in absolute time, which stays monotonic across daylight saving changes
+			t = t.Add(time.Duration(60-t.Minute()) * time.Minute)
+		case !s.matches(0, t.Minute()):
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// Active reports whether t falls within duration of a matching time, and if so until when
+func (s *Schedule) Active(t time.Time, duration time.Duration) (time.Time, bool) {
+	start := s.Next(t.Add(-duration))
+	if start.IsZero() || start.After(t) {
+		return time.Time{}, false
+	}
+	// With overlapping windows the latest one lasts longest
+	for next := s.Next(start); !next.IsZero() && !next.After(t); next = s.Next(next) {
+		start = next
+	}
+	return start.Add(duration), true
+}
+
+// matches reports whether the value is matched by the field at index i
+func (s *Schedule) matches(i int, value int) bool {
+	return s.bits[i]&(1<<uint(value)) != 0
+}
+
+// dayMatches checks the day of month and day of week of t
+func (s *Schedule) dayMatches(t time.Time) bool {
+	dom, dow := s.matches(2, t.Day()), s.matches(4, int(t.Weekday()))
+	if s.anyDay {
+		return dom && dow
+	}
+	return dom || dow
+}
@@ -0,0 +1,78 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNext(t *testing.T) {
+	berlin, err := LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		spec     string
+		after    time.Time
+		expected time.Time
+	}{
+		{"*/15 * * * *", time.Date(2024, 1, 1, 10, 7, 30, 0, time.UTC), time.Date(2024, 1, 1, 10, 15, 0, 0, time.UTC)},
+		{"0 9 * * mon-fri", time.Date(2024, 1, 5, 9, 0, 0, 0, berlin), time.Date(2024, 1, 8, 9, 0, 0, 0, berlin)},
+		{"@monthly", time.Date(2024, 1, 15, 0, 0, 0, 0, berlin), time.Date(2024, 2, 1, 0, 0, 0, 0, berlin)},
+		// Day of month or day of week when both are restricted
+		{"0 0 13 * 5", time.Date(2024, 9, 1, 0, 0, 0, 0, berlin), time.Date(2024, 9, 6, 0, 0, 0, 0, berlin)},
+		{"0 0 * * 7", time.Date(2024, 1, 1, 0, 0, 0, 0, berlin), time.Date(2024, 1, 7, 0, 0, 0, 0, berlin)},
+		// The prefix zone wins over the default location; 22:00 in Berlin is 20:00 UTC in summer
+		{"CRON_TZ=Europe/Berlin 0 22 * * *", time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC), time.Date(2024, 7, 1, 20, 0, 0, 0, time.UTC)},
+		{"TZ=UTC 0 22 * * *", time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC), time.Date(2024, 7, 1, 22, 0, 0, 0, time.UTC)},
+		// 02:30 does not exist in Berlin on the day clocks go forward
+		{"30 2 * * *", time.Date(2024, 3, 30, 12, 0, 0, 0, berlin), time.Date(2024, 4, 1, 2, 30, 0, 0, berlin)},
+	}
+	for _, tt := range tests {
+		schedule, err := Parse(tt.spec, berlin)
+		if err != nil {
+			t.Errorf("Parse(%q) failed: %v", tt.spec, err)
+			continue
+		}
+		if got := schedule.Next(tt.after); !got.Equal(tt.expected) {
+			t.Errorf("Next(%q, %v) = %v, expected %v", tt.spec, tt.after, got, tt.expected)
+		}
+	}
+
+	if schedule, _ := Parse("0 0 30 2 *", time.UTC); !schedule.Next(time.Now()).IsZero() {
+		t.Errorf("Expected no next time for February 30th")
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* * * 13 *", "5-1 * * * *", "*/0 * * * *", "CRON_TZ=Nowhere/City * * * * *", "@often"} {
+		if _, err := Parse(spec, time.UTC); err == nil {
+			t.Errorf("Expected an error for %q", spec)
+		}
+	}
+}
+
+func TestActive(t *testing.T) {
+	schedule, err := Parse("CRON_TZ=America/New_York 0 22 * * fri", time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	friday := time.Date(2024, 1, 5, 22, 0, 0, 0, schedule.Location())
+
+	if until, ok := schedule.Active(friday.Add(3*time.Hour), 8*time.Hour); !ok || !until.Equal(friday.Add(8*time.Hour)) {
+		t.Errorf("Expected the window to be open until %v, got %v, %v", friday.Add(8*time.Hour), until, ok)
+	}
+	if _, ok := schedule.Active(friday.Add(-time.Minute), 8*time.Hour); ok {
+		t.Errorf("Expected the window to be closed before it opens")
+	}
+	if _, ok := schedule.Active(friday.Add(8*time.Hour), 8*time.Hour); ok {
+		t.Errorf("Expected the window to be closed once its duration has passed")
+	}
+
+	// Overlapping windows last until the latest one closes
+	hourly, _ := Parse("0 * * * *", time.UTC)
+	at := time.Date(2024, 1, 1, 10, 30, 0, 0, time.UTC)
+	if until, ok := hourly.Active(at, 2*time.Hour); !ok || !until.Equal(at.Add(90*time.Minute)) {
+		t.Errorf("Expected the window to be open until 12:00, got %v, %v", until, ok)
+	}
+}
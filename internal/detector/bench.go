@@ -0,0 +1,168 @@
+package detector
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/terraform"
+)
+
+// benchIntervalStep rounds recommended check intervals up to a readable value
+const benchIntervalStep = 15 * time.Minute
+
+// BenchResult holds the measured terraform durations of one project
+type BenchResult struct {
+	Project  string
+	Runs     int // Successful runs the means are taken over
+	Failures int
+	InitMean time.Duration
+	PlanMean time.Duration
+	Slowest  time.Duration // Longest init plus plan of a single run
+}
+
+// Total returns the mean time one scan of the project takes
+func (r BenchResult) Total() time.Duration {
+	return r.InitMean + r.PlanMean
+}
+
+// BenchRecommendation sizes the watcher for the measured projects
+type BenchRecommendation struct {
+	Sweep         time.Duration // Time to scan every project one after another
+	Concurrency   int
+	EstimatedRun  time.Duration // Expected run time at the recommended concurrency
+	CheckInterval time.Duration
+
+	// WithinTarget is false when auth profile max_concurrency limits or the slowest project
+	// keep runs longer than the target at any concurrency
+	WithinTarget bool
+}
+
+// CheckIntervalSetting renders the recommended interval the way check_interval is usually
+// written, e.g. 1h30m
+func (r BenchRecommendation) CheckIntervalSetting() string {
+	s := strings.TrimSuffix(r.CheckInterval.String(), "0s")
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// Bench plans each enabled project the given number of times, one after another, and measures
+// how long terraform init and plan take. Nothing is notified and state is not touched.
+func Bench(cfg *config.Config, runs int, project string) ([]BenchResult, error) {
+	if runs < 1 {
+		return nil, fmt.Errorf("runs must be at least 1")
+	}
+
+	var results []BenchResult
+	for _, p := range cfg.Projects {
+		if project != "" && p.Name != project {
+			continue
+		}
+		if project == "" && p.Enabled != nil && !*p.Enabled {
+			continue
+		}
+
+		opts, err := projectOptions(cfg, p)
+		if err != nil {
+			return nil, fmt.Errorf("project '%s': %w", p.Name, err)
+		}
+
+		result := BenchResult{Project: p.Name}
+		var initTotal, planTotal time.Duration
+		for i := 0; i < runs; i++ {
+			timings := &terraform.Timings{}
+			opts.Timings = timings
+			log.Printf("INFO: Benchmarking '%s' (run %d of %d)...", p.Name, i+1, runs)
+			_, exitCode, err := terraform.CheckDriftWithOptions(p.Path, opts)
+			if exitCode == 2 {
+				if err := terraform.RemovePlanFile(p.Path, opts); err != nil {
+					log.Printf("WARNING: Failed to clean up saved plan for '%s': %v", p.Name, err)
+				}
+			}
+			if err != nil {
+				log.Printf("ERROR: Benchmark run of '%s' failed: %v", p.Name, err)
+				result.Failures++
+				continue
+			}
+
+			result.Runs++
+			initTotal += timings.Init
+			planTotal += timings.Plan
+			if total := timings.Init + timings.Plan; total > result.Slowest {
+				result.Slowest = total
+			}
+		}
+		closeStream(opts)
+		if result.Runs > 0 {
+			result.InitMean = initTotal / time.Duration(result.Runs)
+			result.PlanMean = planTotal / time.Duration(result.Runs)
+		}
+		results = append(results, result)
+	}
+
+	if project != "" && len(results) == 0 {
+		return nil, fmt.Errorf("project '%s' not found in configuration", project)
+	}
+	return results, nil
+}
+
+// RecommendSettings picks the lowest concurrency that scans the measured projects within the
+// target run time, taking auth profile max_concurrency limits into account, and a check interval
+// that leaves room for runs slower than measured
+func RecommendSettings(cfg *config.Config, results []BenchResult, target time.Duration) BenchRecommendation {
+	var rec BenchRecommendation
+	var longest time.Duration
+	profiles := make(map[string]string)
+	for _, p := range cfg.Projects {
+		profiles[p.Name] = p.AuthProfile
+	}
+
+	// A throttled auth profile bounds the run time however many projects run in parallel
+	profileTime := make(map[string]time.Duration)
+	for _, result := range results {
+		rec.Sweep += result.Total()
+		if result.Total() > longest {
+			longest = result.Total()
+		}
+		profileTime[profiles[result.Project]] += result.Total()
+	}
+	var floor time.Duration
+	for _, profile := range cfg.AuthProfiles {
+		if profile.MaxConcurrency > 0 {
+			if t := profileTime[profile.Name] / time.Duration(profile.MaxConcurrency); t > floor {
+				floor = t
+			}
+		}
+	}
+	if longest > floor {
+		floor = longest
+	}
+
+	rec.Concurrency = 1
+	if target > 0 {
+		rec.Concurrency = int((rec.Sweep + target - 1) / target)
+	}
+	if rec.Concurrency > len(results) {
+		rec.Concurrency = len(results)
+	}
+	if rec.Concurrency < 1 {
+		rec.Concurrency = 1
+	}
+
+	rec.EstimatedRun = rec.Sweep / time.Duration(rec.Concurrency)
+	if floor > rec.EstimatedRun {
+		rec.EstimatedRun = floor
+	}
+	rec.WithinTarget = target <= 0 || rec.EstimatedRun <= target
+
+	// Twice the estimated run keeps a slow run from overlapping the next one
+	rec.CheckInterval = (2*rec.EstimatedRun + benchIntervalStep - 1) / benchIntervalStep * benchIntervalStep
+	if rec.CheckInterval < benchIntervalStep {
+		rec.CheckInterval = benchIntervalStep
+	}
+	return rec
+}
@@ -0,0 +1,72 @@
+package detector
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/terradrift-watcher/internal/config"
+)
+
+// benchResults returns synthetic measurements of projects p0, p1, ... taking the given times
+func benchResults(totals ...time.Duration) []BenchResult {
+	var results []BenchResult
+	for i, total := range totals {
+		results = append(results, BenchResult{Project: fmt.Sprintf("p%d", i), Runs: 3, InitMean: total / 5, PlanMean: total - total/5})
+	}
+	return results
+}
+
+func TestRecommendSettings(t *testing.T) {
+	m := time.Minute
+	tests := []struct {
+		name         string
+		totals       []time.Duration
+		limit        int // max_concurrency of the auth profile every project uses, 0 for none
+		target       time.Duration
+		concurrency  int
+		estimatedRun time.Duration
+		interval     string
+		withinTarget bool
+	}{
+		{"parallel within target", []time.Duration{5 * m, 5 * m, 5 * m, 5 * m}, 0, 10 * m, 2, 10 * m, "30m", true},
+		{"throttled auth profile", []time.Duration{5 * m, 5 * m, 5 * m, 5 * m}, 1, 10 * m, 2, 20 * m, "45m", false},
+		{"slowest project", []time.Duration{40 * m, m, m, m}, 0, 30 * m, 2, 40 * m, "1h30m", false},
+		{"more parallel than projects", []time.Duration{30 * m, 30 * m}, 0, m, 2, 30 * m, "1h", false},
+		{"no target", []time.Duration{5 * m, 5 * m}, 0, 0, 1, 10 * m, "30m", true},
+		{"minimum interval", []time.Duration{m}, 0, 30 * m, 1, m, "15m", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := benchResults(tt.totals...)
+			cfg := &config.Config{}
+			if tt.limit > 0 {
+				cfg.AuthProfiles = []config.AuthProfile{{Name: "prod", MaxConcurrency: tt.limit}}
+			}
+			for _, result := range results {
+				cfg.Projects = append(cfg.Projects, config.Project{Name: result.Project, AuthProfile: "prod"})
+			}
+
+			rec := RecommendSettings(cfg, results, tt.target)
+			var sweep time.Duration
+			for _, total := range tt.totals {
+				sweep += total
+			}
+			if rec.Sweep != sweep {
+				t.Errorf("Expected a sweep of %v, got %v", sweep, rec.Sweep)
+			}
+			if rec.Concurrency != tt.concurrency {
+				t.Errorf("Expected concurrency %d, got %d", tt.concurrency, rec.Concurrency)
+			}
+			if rec.EstimatedRun != tt.estimatedRun {
+				t.Errorf("Expected an estimated run of %v, got %v", tt.estimatedRun, rec.EstimatedRun)
+			}
+			if got := rec.CheckIntervalSetting(); got != tt.interval {
+				t.Errorf("Expected check_interval %q, got %q", tt.interval, got)
+			}
+			if rec.WithinTarget != tt.withinTarget {
+				t.Errorf("Expected within target %v, got %v", tt.withinTarget, rec.WithinTarget)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package detector
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/terraform"
+)
+
+// projectCodeFiles are the files in a project directory that change what terraform plans
+var projectCodeFiles = []string{"*.tf", "*.tf.json", "*.tfvars", "*.tfvars.json"}
+
+// scanCacheKey identifies the inputs of a project's plan that the watcher can observe: the
+// serial of its state in the backend and a hash of its code, local modules and variables.
+// Plans with the same key only differ by changes made outside terraform.
+func scanCacheKey(project config.Project, opts terraform.Options) (string, error) {
+	code, err := codeHash(project)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash code: %w", err)
+	}
+	serial, err := terraform.StateSerial(project.Path, opts)
+	if err != nil {
+		return "", fmt.Errorf("failed to read state serial: %w", err)
+	}
+	return serial + "@" + code, nil
+}
+
+// codeHash hashes the terraform files of a project, the local modules it uses, its variables and
+// its environment
+func codeHash(project config.Project) (string, error) {
+	hash := sha256.New()
+
+	var files []string
+	for _, pattern := range projectCodeFiles {
+		matches, err := filepath.Glob(filepath.Join(project.Path, pattern))
+		if err != nil {
+			return "", err
+		}
+		files = append(files, matches...)
+	}
+	// Var files may live outside the project directory
+	for _, file := range project.VarFiles {
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(project.Path, file)
+		}
+		files = append(files, file)
+	}
+	for _, name := range files {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(hash, "%s\x00%d\x00", name, len(data))
+		hash.Write(data)
+	}
+
+	modules, err := terraform.LocalModuleDirs(project.Path)
+	if err != nil {
+		return "", err
+	}
+	for _, dir := range modules {
+		moduleHash, err := terraform.ModuleHash(dir)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(hash, "module\x00%s\x00%s\x00", dir, moduleHash)
+	}
+
+	vars := project.VarValues()
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(hash, "var\x00%s\x00%s\x00", name, vars[name])
+	}
+
+	names = names[:0]
+	for name := range project.Env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(hash, "env\x00%s\x00%s\x00", name, project.Env[name])
+	}
+	if project.Workspace != "" {
+		fmt.Fprintf(hash, "workspace\x00%s\x00", project.Workspace)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))[:16], nil
+}
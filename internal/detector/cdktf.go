@@ -0,0 +1,50 @@
+package detector
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/terraform"
+)
+
+// planCDKTF synthesizes a CDK for Terraform project and plans each of its stacks like a
+// terraform project. Outputs are joined under a header per stack. A failed stack fails the
+// check; otherwise the project is drifted when any stack is, with the drifted stacks' plans merged.
+func planCDKTF(project config.Project, opts terraform.Options) (string, int, *terraform.Plan, error) {
+	log.Printf("INFO: Synthesizing cdktf project '%s'", project.Name)
+	if output, err := terraform.Synthesize(project.Path, opts); err != nil {
+		return output, 1, nil, err
+	}
+	stacks, err := terraform.SynthesizedStacks(project.Path, project.Stacks)
+	if err != nil {
+		return "", 1, nil, err
+	}
+
+	var output strings.Builder
+	exitCode := 0
+	var merged *terraform.Plan
+	for _, stack := range stacks {
+		log.Printf("INFO: Planning stack '%s' of '%s'", stack.Name, project.Name)
+		planOutput, stackExitCode, err := terraform.CheckDriftWithOptions(stack.Dir, opts)
+		fmt.Fprintf(&output, "=== Stack: %s ===\n%s\n", stack.Name, planOutput)
+		if err != nil {
+			return output.String(), 1, nil, fmt.Errorf("stack %s: %w", stack.Name, err)
+		}
+		if stackExitCode != 2 {
+			continue
+		}
+
+		exitCode = 2
+		stackProject := project
+		stackProject.Path = stack.Dir
+		if plan := savedPlan(nil, stackProject, opts); plan != nil {
+			if merged == nil {
+				merged = &terraform.Plan{}
+			}
+			merged.ResourceChanges = append(merged.ResourceChanges, plan.ResourceChanges...)
+		}
+	}
+	return output.String(), exitCode, merged, nil
+}
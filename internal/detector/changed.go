@@ -0,0 +1,72 @@
+package detector
+
+import (
+	"log"
+	"path/filepath"
+	"strings"
+
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/gitutil"
+	"github.com/terradrift-watcher/internal/terraform"
+)
+
+// changedProjects returns the projects whose directory or local modules contain a file changed
+// since the branch point with ref, each with the changed modules. Projects whose changes cannot
+// be determined are included, so an unreadable repository never hides drift.
+func changedProjects(cfg *config.Config, ref string) map[string][]string {
+	changed := make(map[string][]string)
+	filesByRepo := make(map[string][]string)
+
+	for _, project := range cfg.Projects {
+		top, err := gitutil.TopLevel(project.Path)
+		if err != nil {
+			log.Printf("WARNING: Cannot tell whether '%s' changed since %s (%v); scanning it", project.Name, ref, err)
+			changed[project.Name] = nil
+			continue
+		}
+		files, ok := filesByRepo[top]
+		if !ok {
+			_, files, err = gitutil.ChangedFiles(project.Path, ref)
+			if err != nil {
+				log.Printf("WARNING: Cannot tell whether '%s' changed since %s (%v); scanning it", project.Name, ref, err)
+				changed[project.Name] = nil
+				continue
+			}
+			filesByRepo[top] = files
+		}
+
+		modules, err := terraform.LocalModuleDirs(project.Path)
+		if err != nil {
+			log.Printf("WARNING: Failed to read the modules of '%s': %v", project.Name, err)
+		}
+		var changedModules []string
+		for _, dir := range modules {
+			if containsChangedFile(dir, files) {
+				changedModules = append(changedModules, dir)
+			}
+		}
+		if len(changedModules) > 0 || containsChangedFile(project.Path, files) {
+			changed[project.Name] = changedModules
+		}
+	}
+	return changed
+}
+
+// containsChangedFile reports whether any of the files is inside dir
+func containsChangedFile(dir string, files []string) bool {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return false
+	}
+	// git reports paths under the resolved repository root
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		abs = resolved
+	}
+	prefix := abs + string(filepath.Separator)
+	for _, file := range files {
+		if strings.HasPrefix(file, prefix) {
+			return true
+		}
+	}
+	return false
+}
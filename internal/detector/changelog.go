@@ -0,0 +1,57 @@
+package detector
+
+import (
+	"log"
+
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/state"
+	"github.com/terradrift-watcher/internal/terraform"
+)
+
+// maxLoggedChanges bounds the changelog printed to the console
+const maxLoggedChanges = 20
+
+// savedPlan reads the saved plan of a drifted project for its changelog, keeping a copy for
+// remediation in keep when it is set. A failure only loses the changelog, so it is logged and
+// nil is returned rather than failing the scan.
+func savedPlan(keep state.Storage, project config.Project, opts terraform.Options) *terraform.Plan {
+	defer func() {
+		if keep != nil {
+			keepPlan(keep, project)
+		}
+		if err := terraform.RemovePlanFile(project.Path, opts); err != nil {
+			log.Printf("WARNING: Failed to clean up saved plan for '%s': %v", project.Name, err)
+		}
+	}()
+
+	plan, err := terraform.ShowPlan(project.Path, opts)
+	if err != nil {
+		log.Printf("WARNING: Could not build changelog for '%s': %v", project.Name, err)
+		return nil
+	}
+	return plan
+}
+
+// logChangelog prints the out-of-band changes of a project
+func logChangelog(projectName string, changes []terraform.AttributeChange) {
+	if len(changes) == 0 {
+		return
+	}
+
+	log.Printf("CHANGELOG for '%s':", projectName)
+	for i, change := range changes {
+		if i == maxLoggedChanges {
+			log.Printf("  ... (%d more changes, see 'terradrift-watcher history --changes')", len(changes)-maxLoggedChanges)
+			break
+		}
+		log.Printf("  %s", formatChange(change))
+	}
+}
+
+// formatChange renders a single changelog entry on one line
+func formatChange(change terraform.AttributeChange) string {
+	if change.Attribute == "" {
+		return change.Address + " (" + change.Action + ")"
+	}
+	return change.Address + "." + change.Attribute + ": " + change.Before + " -> " + change.After
+}
@@ -0,0 +1,218 @@
+package detector
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/notifier"
+	"github.com/terradrift-watcher/internal/terraform"
+)
+
+// FleetProject names the project of fleet-level alerts
+const FleetProject = "fleet"
+
+// correlateDrift collapses projects whose drift is entirely shared with enough other projects
+// into one fleet-level alert per group, and sends the held-back alerts of every other project
+func correlateDrift(cfg *config.Config, report *Report) {
+	threshold := cfg.Correlation.Threshold()
+
+	// Count the projects drifting with each pattern
+	patterns := make(map[int][]string)
+	counts := make(map[string]int)
+	for i, result := range report.Results {
+		if result.pending == nil {
+			continue
+		}
+		patterns[i] = driftPatterns(result.pending.alert)
+		for _, pattern := range patterns[i] {
+			counts[pattern]++
+		}
+	}
+
+	// Projects whose every pattern is common are grouped with those they share a pattern with
+	groups := newUnionFind()
+	owner := make(map[string]int)
+	for i, projectPatterns := range patterns {
+		if len(projectPatterns) == 0 || !allCommon(projectPatterns, counts, threshold) {
+			continue
+		}
+		groups.add(i)
+		for _, pattern := range projectPatterns {
+			if first, ok := owner[pattern]; ok {
+				groups.union(first, i)
+			} else {
+				owner[pattern] = i
+			}
+		}
+	}
+
+	for _, members := range groups.sets() {
+		if len(members) < threshold {
+			continue
+		}
+		sendFleetAlert(cfg, report, members, patterns, counts)
+	}
+
+	for i := range report.Results {
+		if result := &report.Results[i]; result.pending != nil {
+			notifyDrift(cfg, result.pending.alert, result.pending.notifiers, result)
+			result.pending = nil
+		}
+	}
+}
+
+// sendFleetAlert sends one alert for a group of projects sharing the same drift. Delivery
+// failures are recorded on the first project so the alert can be replayed.
+func sendFleetAlert(cfg *config.Config, report *Report, members []int, patterns map[int][]string, counts map[string]int) {
+	var projects, notifiers, owners, tags []string
+	var changes []terraform.AttributeChange
+	var driftSince time.Time
+	shared := make(map[string]bool)
+	for _, i := range members {
+		result := &report.Results[i]
+		projects = append(projects, result.Project)
+		notifiers = mergeUnique(notifiers, result.pending.notifiers)
+		owners = mergeUnique(owners, result.pending.alert.Owners)
+		tags = mergeUnique(tags, result.pending.alert.Tags)
+		for _, change := range result.pending.alert.Changes {
+			change.Address = result.Project + ": " + change.Address
+			changes = append(changes, change)
+		}
+		for _, pattern := range patterns[i] {
+			shared[pattern] = true
+		}
+		if since := result.pending.alert.DriftSince; driftSince.IsZero() || since.Before(driftSince) {
+			driftSince = since
+		}
+		result.Correlated = true
+		result.pending = nil
+	}
+	if len(cfg.Correlation.Notifiers) > 0 {
+		notifiers = cfg.Correlation.Notifiers
+	}
+
+	common := make([]string, 0, len(shared))
+	for pattern := range shared {
+		common = append(common, pattern)
+	}
+	sort.Slice(common, func(a, b int) bool {
+		if counts[common[a]] != counts[common[b]] {
+			return counts[common[a]] > counts[common[b]]
+		}
+		return common[a] < common[b]
+	})
+
+	var summary strings.Builder
+	fmt.Fprintf(&summary, "%d projects drifted with the same pattern, likely from one common cause:", len(projects))
+	for _, pattern := range common {
+		fmt.Fprintf(&summary, "\n  %s (%d projects)", pattern, counts[pattern])
+	}
+	fmt.Fprintf(&summary, "\nProjects: %s", strings.Join(projects, ", "))
+
+	alert := notifier.DriftAlert{
+		Project:     FleetProject,
+		Summary:     summary.String(),
+		Owners:      owners,
+		Tags:        tags,
+		Changes:     changes,
+		Fingerprint: terraform.Fingerprint(FleetProject, changes, ""),
+		DriftSince:  driftSince,
+	}
+
+	log.Printf("ALERT: %d projects drifted with the same pattern (%s); sending one fleet alert",
+		len(projects), strings.Join(common, ", "))
+	notifyDrift(cfg, alert, notifiers, &report.Results[members[0]])
+}
+
+// driftPatterns returns the resource type and attribute of every change in an alert, with list
+// indexes generalized, e.g. aws_security_group.ingress.*.cidr_blocks
+func driftPatterns(alert notifier.DriftAlert) []string {
+	seen := make(map[string]bool)
+	var patterns []string
+	add := func(pattern string) {
+		if !seen[pattern] {
+			seen[pattern] = true
+			patterns = append(patterns, pattern)
+		}
+	}
+
+	for _, change := range alert.Changes {
+		resourceType := terraform.ResourceType(change.Address)
+		if change.Attribute == "" {
+			add(resourceType + " (" + change.Action + ")")
+			continue
+		}
+		parts := strings.Split(change.Attribute, ".")
+		for i, part := range parts {
+			if strings.Trim(part, "0123456789") == "" {
+				parts[i] = "*"
+			}
+		}
+		add(resourceType + "." + strings.Join(parts, "."))
+	}
+
+	// Without a changelog, fall back to the changed resource types in the plan output
+	if len(alert.Changes) == 0 {
+		for _, change := range terraform.ParseResourceChanges(alert.PlanOutput) {
+			add(change.Type + " (" + change.Action + ")")
+		}
+	}
+	return patterns
+}
+
+// allCommon reports whether every pattern is shared by at least threshold projects
+func allCommon(patterns []string, counts map[string]int, threshold int) bool {
+	for _, pattern := range patterns {
+		if counts[pattern] < threshold {
+			return false
+		}
+	}
+	return true
+}
+
+// unionFind groups result indexes into disjoint sets
+type unionFind struct {
+	parent map[int]int
+}
+
+func newUnionFind() *unionFind {
+	return &unionFind{parent: make(map[int]int)}
+}
+
+func (u *unionFind) add(i int) {
+	if _, ok := u.parent[i]; !ok {
+		u.parent[i] = i
+	}
+}
+
+func (u *unionFind) find(i int) int {
+	for u.parent[i] != i {
+		u.parent[i] = u.parent[u.parent[i]]
+		i = u.parent[i]
+	}
+	return i
+}
+
+func (u *unionFind) union(a, b int) {
+	u.parent[u.find(a)] = u.find(b)
+}
+
+// sets returns the members of each set in ascending order, ordered by their first member
+func (u *unionFind) sets() [][]int {
+	byRoot := make(map[int][]int)
+	for i := range u.parent {
+		root := u.find(i)
+		byRoot[root] = append(byRoot[root], i)
+	}
+	sets := make([][]int, 0, len(byRoot))
+	for _, members := range byRoot {
+		sort.Ints(members)
+		sets = append(sets, members)
+	}
+	sort.Slice(sets, func(a, b int) bool { return sets[a][0] < sets[b][0] })
+	return sets
+}
@@ -0,0 +1,88 @@
+package detector
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/terradrift-watcher/internal/notifier"
+	"github.com/terradrift-watcher/internal/terraform"
+)
+
+func TestDriftPatterns(t *testing.T) {
+	alert := notifier.DriftAlert{Changes: []terraform.AttributeChange{
+		{Address: "aws_security_group.web", Attribute: "ingress.0.cidr_blocks"},
+		{Address: "aws_security_group.db", Attribute: "ingress.3.cidr_blocks"},
+		{Address: "aws_instance.bastion", Action: "delete"},
+	}}
+
+	// List indexes are generalized so the same change to different elements matches
+	got := driftPatterns(alert)
+	for _, expected := range []string{"aws_security_group.ingress.*.cidr_blocks", "aws_instance (delete)"} {
+		found := false
+		for _, pattern := range got {
+			found = found || pattern == expected
+		}
+		if !found {
+			t.Errorf("Expected pattern %q, got %v", expected, got)
+		}
+	}
+	if again := driftPatterns(alert); !reflect.DeepEqual(got, again) {
+		t.Errorf("Expected stable patterns, got %v and %v", got, again)
+	}
+}
+
+func TestScanCorrelatesFleetDrift(t *testing.T) {
+	projects := []string{"app-a", "app-b", "app-c", "network"}
+	run := newTestRun(t, "correlation: {}\n", projects...)
+	for _, project := range projects[:3] {
+		run.plan(project, driftPlan("public-read"))
+	}
+	run.plan("network", strings.ReplaceAll(driftPlan("public-read"), "aws_s3_bucket", "aws_iam_role"))
+
+	// Three projects sharing the pattern reach the default threshold and are alerted as one
+	results := run.scan(Options{})
+	var fleet, single []string
+	for _, ev := range run.sent("oncall") {
+		if ev.Project == FleetProject {
+			fleet = append(fleet, ev.Summary)
+		} else {
+			single = append(single, ev.Project)
+		}
+	}
+	if len(fleet) != 1 || !strings.Contains(fleet[0], "3 projects drifted with the same pattern") {
+		t.Fatalf("Expected one fleet alert, got %v", fleet)
+	}
+	// The alert held back for the project with its own drift is still sent
+	if !reflect.DeepEqual(single, []string{"network"}) {
+		t.Errorf("Expected only network alerted on its own, got %v", single)
+	}
+	for _, project := range projects[:3] {
+		if !results[project].Correlated {
+			t.Errorf("Expected %s to be correlated", project)
+		}
+	}
+	if results["network"].Correlated {
+		t.Error("Expected network not to be correlated")
+	}
+}
+
+func TestScanCorrelationThreshold(t *testing.T) {
+	projects := []string{"app-a", "app-b", "app-c"}
+	run := newTestRun(t, "correlation:\n  min_projects: 4\n", projects...)
+	for _, project := range projects {
+		run.plan(project, driftPlan("public-read"))
+	}
+
+	// One project short of the threshold, each drift is alerted on its own
+	results := run.scan(Options{})
+	sent := run.sent("oncall")
+	if len(sent) != len(projects) {
+		t.Fatalf("Expected %d separate alerts, got %+v", len(projects), sent)
+	}
+	for _, ev := range sent {
+		if ev.Project == FleetProject || results[ev.Project].Correlated {
+			t.Errorf("Expected no correlation below the threshold, got %+v", ev)
+		}
+	}
+}
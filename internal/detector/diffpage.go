@@ -0,0 +1,128 @@
+package detector
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/notifier"
+	"github.com/terradrift-watcher/internal/signing"
+	"github.com/terradrift-watcher/internal/state"
+)
+
+// diffPageDir returns the directory of the HTML diff pages and the permissions of the directory
+// and its pages. Only a configured dir is published, so pages kept next to the state by default
+// are owner-only like the state.
+func diffPageDir(cfg *config.Config) (string, os.FileMode, os.FileMode) {
+	if cfg.HTMLDiffs.Dir != "" {
+		return cfg.HTMLDiffs.Dir, 0755, 0644
+	}
+	return stateSubdir(cfg, "html-diffs"), 0700, 0600
+}
+
+// diffPageName returns the file name of the page of a project's drift
+func diffPageName(project, fingerprint string) string {
+	return project + "-" + fingerprint + ".html"
+}
+
+// writeDiffPage writes the color-coded HTML page of a drift alert and returns its path and,
+// when the pages are published, its URL. Pages are named after the project and fingerprint,
+// so the same drift found again replaces its page and keeps its link.
+func writeDiffPage(cfg *config.Config, alert notifier.DriftAlert) (string, string, error) {
+	dir, dirMode, fileMode := diffPageDir(cfg)
+	id := alert.Fingerprint
+	if id == "" {
+		id = time.Now().UTC().Format("20060102T150405Z")
+	}
+	name := diffPageName(alert.Project, id)
+
+	page, err := notifier.RenderDiffPage(alert, time.Now())
+	if err != nil {
+		return "", "", fmt.Errorf("failed to render HTML diff: %w", err)
+	}
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), dirMode); err != nil {
+		return "", "", fmt.Errorf("failed to create HTML diff directory: %w", err)
+	}
+	if err := os.WriteFile(path, page, fileMode); err != nil {
+		return "", "", fmt.Errorf("failed to write HTML diff: %w", err)
+	}
+	if cfg.Signing != nil {
+		if err := signing.SignFiles(cfg.Signing.KeyFile, path); err != nil {
+			return path, "", fmt.Errorf("failed to sign HTML diff: %w", err)
+		}
+	}
+
+	if cfg.HTMLDiffs.BaseURL == "" {
+		return path, "", nil
+	}
+	link := strings.TrimSuffix(cfg.HTMLDiffs.BaseURL, "/") + "/" + url.PathEscape(name)
+	return path, link, nil
+}
+
+// pruneDiffPages removes the pages of drift that was resolved or superseded by a new
+// fingerprint when retention is configured: once they are older than max_age, or right away
+// without one. Pages of projects missing from the configuration are left alone.
+func pruneDiffPages(cfg *config.Config, store *state.Store, now time.Time) {
+	if cfg.HTMLDiffs == nil || cfg.Retention == nil {
+		return
+	}
+	// An invalid retention was already reported when pruning the history
+	maxAge, _, _, err := cfg.Retention.Limits()
+	if err != nil {
+		return
+	}
+	dir, _, _ := diffPageDir(cfg)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			log.Printf("WARNING: Failed to prune HTML diffs: %v", err)
+		}
+		return
+	}
+
+	current := make(map[string]bool)
+	for _, project := range cfg.Projects {
+		if ps, ok := store.Projects[project.Name]; ok && ps.Fingerprint != "" {
+			current[diffPageName(project.Name, ps.Fingerprint)] = true
+		}
+	}
+	removed := 0
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".html") || current[name] || !configuredPage(cfg, name) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || (maxAge > 0 && now.Sub(info.ModTime()) < maxAge) {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		if err := os.Remove(path); err != nil {
+			log.Printf("WARNING: Failed to prune HTML diff %s: %v", path, err)
+			continue
+		}
+		os.Remove(path + signing.SignatureExt)
+		removed++
+	}
+	if removed > 0 {
+		log.Printf("INFO: Pruned %d HTML diff(s) of resolved or superseded drift", removed)
+	}
+}
+
+// configuredPage reports whether a page is named after a configured project
+func configuredPage(cfg *config.Config, name string) bool {
+	for _, project := range cfg.Projects {
+		if strings.HasPrefix(name, project.Name+"-") {
+			return true
+		}
+	}
+	return false
+}
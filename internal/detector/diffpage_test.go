@@ -0,0 +1,103 @@
+package detector
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/terradrift-watcher/internal/state"
+)
+
+// diffPages returns the names of the HTML diff pages in a directory
+func diffPages(t *testing.T, dir string) []string {
+	t.Helper()
+	matches, err := filepath.Glob(filepath.Join(dir, "*.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, match := range matches {
+		names = append(names, filepath.Base(match))
+	}
+	return names
+}
+
+func TestDiffPagePermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file modes are not enforced on Windows")
+	}
+	published := filepath.Join(t.TempDir(), "diffs")
+	for _, tt := range []struct {
+		extra    string
+		dir      func(*testRun) string
+		dirMode  os.FileMode
+		fileMode os.FileMode
+	}{
+		// Pages kept next to the state are as private as the state
+		{"html_diffs: {}\n", func(run *testRun) string { return stateSubdir(run.cfg, "html-diffs") }, 0700, 0600},
+		// A configured dir is published, e.g. by a web server running as another user
+		{"html_diffs:\n  dir: " + published + "\n", func(*testRun) string { return published }, 0755, 0644},
+	} {
+		run := newTestRun(t, tt.extra, "network")
+		run.plan("network", driftPlan("private"))
+		run.scan(Options{})
+
+		dir := tt.dir(run)
+		pages := diffPages(t, dir)
+		if len(pages) != 1 {
+			t.Fatalf("Expected one page in %s, got %v", dir, pages)
+		}
+		if info, err := os.Stat(filepath.Join(dir, pages[0])); err != nil || info.Mode().Perm() != tt.fileMode {
+			t.Errorf("Expected page mode %v, got %v (%v)", tt.fileMode, info.Mode().Perm(), err)
+		}
+		if info, err := os.Stat(dir); err != nil || info.Mode().Perm() != tt.dirMode {
+			t.Errorf("Expected directory mode %v, got %v (%v)", tt.dirMode, info.Mode().Perm(), err)
+		}
+	}
+}
+
+func TestPruneDiffPages(t *testing.T) {
+	run := newTestRun(t, "html_diffs: {}\nretention:\n  max_records: 1000\n", "network", "storage")
+	dir := stateSubdir(run.cfg, "html-diffs")
+	run.plan("network", driftPlan("private"))
+	run.plan("storage", driftPlan("private"))
+	run.scan(Options{})
+	if pages := diffPages(t, dir); len(pages) != 2 {
+		t.Fatalf("Expected a page per drifted project, got %v", pages)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "elsewhere-abc.html"), nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// New drift supersedes the network page, and the storage drift is resolved
+	run.plan("network", driftPlan("public-read"))
+	run.plan("storage", cleanPlan)
+	run.scan(Options{})
+
+	expected := []string{"elsewhere-abc.html", "network-" + run.projectState("network").Fingerprint + ".html"}
+	pages := diffPages(t, dir)
+	if len(pages) != len(expected) || pages[0] != expected[0] || pages[1] != expected[1] {
+		t.Errorf("Expected only the current page and the unknown project's page, %v, got %v", expected, pages)
+	}
+}
+
+func TestPruneDiffPagesMaxAge(t *testing.T) {
+	run := newTestRun(t, "html_diffs: {}\nretention:\n  max_age: 1h\n", "network")
+	dir := stateSubdir(run.cfg, "html-diffs")
+	run.plan("network", driftPlan("private"))
+	run.scan(Options{})
+	run.plan("network", cleanPlan)
+	run.scan(Options{})
+
+	// Pages of resolved drift stay until they are older than max_age
+	if pages := diffPages(t, dir); len(pages) != 1 {
+		t.Fatalf("Expected the resolved page to be kept for max_age, got %v", pages)
+	}
+	store := &state.Store{Projects: map[string]*state.ProjectState{}}
+	pruneDiffPages(run.cfg, store, time.Now().Add(2*time.Hour))
+	if pages := diffPages(t, dir); len(pages) != 0 {
+		t.Errorf("Expected the resolved page to be pruned after max_age, got %v", pages)
+	}
+}
@@ -0,0 +1,101 @@
+package detector
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/notifier"
+	"github.com/terradrift-watcher/internal/state"
+)
+
+// digestHistoryWindow bounds how far back summaries of open drift are looked up
+const digestHistoryWindow = 90 * 24 * time.Hour
+
+// sendDigests emails a summary of all open drift for every digest schedule that has come due
+// since it was last sent. Runs are not aligned with digest times, so a digest goes out with the
+// first run at or after its scheduled time.
+func sendDigests(cfg *config.Config, store *state.Store, report *Report, now time.Time) {
+	var drifts []notifier.OpenDrift
+	loaded := false
+
+	for _, n := range cfg.Notifiers {
+		if len(n.Digests) == 0 || (n.Enabled != nil && !*n.Enabled) {
+			continue
+		}
+		for _, digest := range n.Digests {
+			due, err := digest.DueSince(now)
+			if err != nil {
+				continue
+			}
+			key := digestKey(n.Name, digest)
+			if !store.DigestsSent[key].Before(due) {
+				continue
+			}
+
+			if !loaded {
+				drifts = openDrift(cfg, store, report, now)
+				loaded = true
+			}
+
+			if err := notifier.SendEmailDigest(emailConfig(&n), digest.To, drifts); err != nil {
+				log.Printf("ERROR: Failed to send digest via '%s' to %s: %v", n.Name, strings.Join(digest.To, ", "), err)
+				continue
+			}
+			log.Printf("INFO: Drift digest sent via '%s' to %s (%d open)", n.Name, strings.Join(digest.To, ", "), len(drifts))
+			if store.DigestsSent == nil {
+				store.DigestsSent = make(map[string]time.Time)
+			}
+			store.DigestsSent[key] = now
+		}
+	}
+}
+
+// digestKey identifies a digest schedule in the state store
+func digestKey(notifierName string, digest config.Digest) string {
+	at := digest.At
+	if at == "" {
+		at = config.DefaultDigestTime
+	}
+	return fmt.Sprintf("%s/%s@%s", notifierName, strings.Join(digest.To, ","), at)
+}
+
+// openDrift lists the enabled projects that were drifted at their last scan. Summaries come from
+// this run where the project was scanned, otherwise from its latest drifted history record.
+func openDrift(cfg *config.Config, store *state.Store, report *Report, now time.Time) []notifier.OpenDrift {
+	latest := make(map[string]state.HistoryRecord)
+	if records, err := state.LoadHistory(store.Storage(), now.Add(-digestHistoryWindow)); err == nil {
+		state.RenameHistory(records, cfg.ProjectAliases())
+		for _, record := range records {
+			if record.Status == StatusDrifted {
+				latest[record.Project] = record
+			}
+		}
+	}
+	for _, result := range report.Results {
+		if result.Status == StatusDrifted {
+			latest[result.Project] = state.HistoryRecord{Summary: result.Summary, Owners: result.Owners}
+		}
+	}
+
+	var drifts []notifier.OpenDrift
+	for _, project := range cfg.Projects {
+		if project.Enabled != nil && !*project.Enabled {
+			continue
+		}
+		ps, ok := store.Projects[project.Name]
+		if !ok || ps.LastStatus != StatusDrifted {
+			continue
+		}
+		drifts = append(drifts, notifier.OpenDrift{
+			Project:    project.Name,
+			Summary:    latest[project.Name].Summary,
+			Owners:     latest[project.Name].Owners,
+			DriftSince: ps.DriftSince,
+			RunbookURL: project.RunbookURL,
+		})
+	}
+	return drifts
+}
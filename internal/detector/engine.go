@@ -2,17 +2,71 @@ package detector
 
 import (
 	"fmt"
+	"io"
 	"log"
+	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 	"syscall"
+	"time"
 
 	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/gitutil"
 	"github.com/terradrift-watcher/internal/notifier"
+	"github.com/terradrift-watcher/internal/redact"
+	"github.com/terradrift-watcher/internal/state"
 	"github.com/terradrift-watcher/internal/terraform"
+	"github.com/terradrift-watcher/internal/textutil"
 )
 
+// Options controls a single drift detection run
+type Options struct {
+	// MaxDuration bounds the whole run; projects not started within it are reported as not scanned
+	MaxDuration time.Duration
+
+	// Concurrency overrides the configured number of projects scanned in parallel
+	Concurrency int
+
+	// ChangedSince limits the run to projects whose directory or local modules changed since
+	// the branch point with this git ref, ignoring the adaptive schedule
+	ChangedSince string
+
+	// Record is a fixtures directory where each project's sanitized plan results are saved
+	// for replaying with Simulate
+	Record string
+
+	// Simulate is a fixtures directory with one subdirectory of canned plan results per project.
+	// Terraform is not run; notifications, escalation and state behave as in a real run.
+	Simulate string
+
+	// Projects limits the run to these projects, scanned whether or not they are due
+	Projects []string
+
+	// IgnorePause scans even while scanning is paused
+	IgnorePause bool
+
+	// Verification describes the apply the run verifies, e.g. "remediate --approved by alice".
+	// Each project's incident is closed, or its drift alerted again, with the outcome.
+	Verification string
+
+	// progress tracks the phase of each project for progress signals
+	progress *progress
+
+	// triage holds the operators' decisions about current drift, some of which hold its alerts
+	triage *state.Triage
+
+	// planLock is held while the project is planned when it shares its state backend with
+	// other projects of the run
+	planLock sync.Locker
+
+	// storage is the state storage of the run, which also keeps plans for remediation
+	storage state.Storage
+}
+
 // Run executes the drift detection process for all configured projects
 func Run(cfg *config.Config) error {
 	_, err := RunWithResult(cfg)
@@ -21,9 +75,29 @@ func Run(cfg *config.Config) error {
 
 // RunWithResult executes the drift detection process and returns whether any drift was found
 func RunWithResult(cfg *config.Config) (bool, error) {
+	report, err := RunWithOptions(cfg, Options{})
+	if report == nil {
+		return false, err
+	}
+	return report.DriftFound(), err
+}
+
+// RunWithOptions executes the drift detection process and returns a report of every project.
+// Run webhooks are told when it starts and finishes.
+func RunWithOptions(cfg *config.Config, opts Options) (*Report, error) {
+	startedAt := time.Now()
+	sendRunEvent(cfg, runStartedEvent(startedAt, opts))
+	report, err := runDetection(cfg, opts)
+	sendRunEvent(cfg, runFinishedEvent(startedAt, report, err))
+	return report, err
+}
+
+// runDetection scans the projects and alerts on drift
+func runDetection(cfg *config.Config, opts Options) (*Report, error) {
 	// Set up signal handling for graceful shutdown
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
 
 	// Create a done channel to signal when we're finished
 	done := make(chan struct{})
@@ -33,9 +107,8 @@ func RunWithResult(cfg *config.Config) (bool, error) {
 		select {
 		case sig := <-sigChan:
 			log.Printf("INFO: Received signal %v, initiating graceful shutdown...", sig)
-			// Clear any auth environment variables before exiting
-			clearAuthEnvironment()
-			log.Printf("INFO: Cleaned up authentication environment variables")
+			// Credentials only live in the environment of terraform child processes,
+			// so there is nothing to clean up in this process
 			os.Exit(130) // Exit code 130 is standard for SIGINT
 		case <-done:
 			// Normal completion
@@ -46,219 +119,1044 @@ func RunWithResult(cfg *config.Config) (bool, error) {
 	// Ensure we signal completion when function returns
 	defer close(done)
 
-	// First, validate that Terraform is installed
-	if err := terraform.ValidateTerraformInstallation(); err != nil {
-		return false, fmt.Errorf("terraform validation failed: %w", err)
+	// Log progress on demand, e.g. with `kill -USR1 <pid>`, to see what a long run is doing
+	opts.progress = newProgress(time.Now())
+	defer watchProgress(opts.progress)()
+
+	if opts.Simulate == "" {
+		opts.Simulate = os.Getenv(terraform.FakeEnvVar)
+	}
+	if opts.Simulate != "" {
+		log.Printf("WARNING: Simulating terraform from fixtures in %s; results are recorded in state and notifications are sent", opts.Simulate)
+	}
+
+	storage, err := state.Open(cfg)
+	if err != nil {
+		return nil, err
+	}
+	opts.storage = storage
+
+	// While scanning is paused a run does nothing, leaving state and notifications untouched
+	pause, err := state.LoadPause(storage)
+	if err != nil {
+		return nil, err
+	}
+	if pause.Active(time.Now()) {
+		if !opts.IgnorePause {
+			log.Printf("INFO: Scanning is %s; not scanning (resume with 'terradrift-watcher resume')", pause)
+			now := time.Now()
+			return &Report{StartedAt: now, FinishedAt: now, Paused: pause}, nil
+		}
+		log.Printf("WARNING: Scanning is %s; scanning anyway", pause)
+	}
+
+	// First, validate that Terraform is installed (remote projects use the runner's terraform)
+	if opts.Simulate == "" && hasLocalProjects(cfg) {
+		if err := terraform.ValidateTerraformInstallation(); err != nil {
+			return nil, fmt.Errorf("terraform validation failed: %w", err)
+		}
+	}
+
+	// All notifiers share one retry budget per run
+	setRetryBudget(cfg)
+
+	// Load the persisted state from previous runs
+	store, err := state.Load(storage)
+	if err != nil {
+		return nil, err
+	}
+	aliases := cfg.ProjectAliases()
+	for _, alias := range store.AdoptAliases(aliases) {
+		log.Printf("INFO: Project '%s' was renamed to '%s', keeping its state", alias, aliases[alias])
+	}
+	if opts.triage, err = state.LoadTriage(storage); err != nil {
+		return nil, err
+	}
+
+	schedule, err := newAdaptiveSchedule(cfg.AdaptiveScheduling)
+	if err != nil {
+		return nil, err
 	}
 
 	log.Println("INFO: Starting drift detection process...")
 
-	// Track if any errors occurred and if any drift was detected
-	var hasErrors bool
-	var driftFound bool
+	report := &Report{StartedAt: time.Now()}
 
-	// Iterate through each project
-	for _, project := range cfg.Projects {
+	// Modules changed since a project's last scan make it due and explain its drift
+	graph := buildModuleGraph(cfg)
+	moduleChanges := make(map[string][]string)
+
+	var changed map[string][]string
+	if opts.ChangedSince != "" {
+		changed = changedProjects(cfg, opts.ChangedSince)
+		log.Printf("INFO: %d project(s) changed since %s", len(changed), opts.ChangedSince)
+	}
+
+	// Decide which projects to scan, starting with those skipped by the previous run
+	var queue []scanJob
+	for _, project := range scanOrder(cfg.Projects, store) {
 		// Skip disabled projects (nil means default true)
 		if project.Enabled != nil && (*project.Enabled) == false {
-			log.Printf("INFO: Skipping disabled project '%s'", project.Name)
+			if project.Override != nil {
+				log.Printf("INFO: Skipping project '%s', %s by a local override", project.Name, project.Override)
+			} else {
+				log.Printf("INFO: Skipping disabled project '%s'", project.Name)
+			}
 			continue
 		}
 
-		log.Printf("INFO: Checking for drift in '%s'...", project.Name)
+		if len(opts.Projects) > 0 && !containsString(opts.Projects, project.Name) {
+			continue
+		}
+
+		changedModules, isChanged := changed[project.Name]
+		if changed != nil && !isChanged {
+			log.Printf("INFO: Skipping '%s': unchanged since %s", project.Name, opts.ChangedSince)
+			continue
+		}
+
+		projectState := store.Project(project.Name)
 
-		// Set authentication environment variables if auth profile is specified
-		if project.AuthProfile != "" {
-			if err := setAuthEnvironment(cfg, project.AuthProfile); err != nil {
-				log.Printf("ERROR: Failed to set auth environment for project '%s': %v", project.Name, err)
-				hasErrors = true
-				continue
+		for _, dir := range graph.changedModules(project.Name, projectState) {
+			if !containsString(changedModules, dir) {
+				changedModules = append(changedModules, dir)
 			}
-			// Ensure cleanup happens even if we continue or an error occurs
-			defer clearAuthEnvironment()
+		}
+		if len(changedModules) > 0 {
+			moduleChanges[project.Name] = changedModules
+			names := make([]string, len(changedModules))
+			for i, dir := range changedModules {
+				names[i] = displayPath(dir)
+			}
+			log.Printf("INFO: Scanning '%s': module(s) changed since its last scan: %s", project.Name, strings.Join(names, ", "))
 		}
 
-		// Run Terraform drift check
-		planOutput, exitCode, err := terraform.CheckDrift(project.Path)
+		// Under adaptive scheduling, stable projects are only scanned once their interval has passed
+		if schedule != nil && changed == nil && len(opts.Projects) == 0 && len(changedModules) == 0 && !schedule.isDue(projectState, time.Now()) {
+			log.Printf("INFO: Project '%s' not due until %s", project.Name, projectState.NextScan.Format(time.RFC3339))
+			report.Results = append(report.Results, ProjectResult{Project: project.Name, Status: StatusNotDue})
+			continue
+		}
 
-		// Handle the results based on exit code
-		switch exitCode {
-		case 0:
-			// No drift detected
-			log.Printf("INFO: No drift detected in '%s'", project.Name)
+		queue = append(queue, scanJob{project: project, state: projectState})
+	}
 
-		case 2:
-			// Drift detected - send notifications
-			driftFound = true
-			log.Printf("ALERT: Drift detected in '%s'! Sending notifications...", project.Name)
+	// Plans should only ever run with credentials that cannot change what they inspect
+	var undelivered []state.OutboxEntry
+	if cfg.ReadOnlyCheck != nil && opts.Simulate == "" {
+		var writable map[string][]string
+		writable, undelivered = checkCredentials(cfg, store, queue, time.Now())
+		if cfg.ReadOnlyCheck.Enforce && len(writable) > 0 {
+			var allowed []scanJob
+			for _, job := range queue {
+				if _, ok := writable[job.project.AuthProfile]; !ok {
+					allowed = append(allowed, job)
+					continue
+				}
+				log.Printf("WARNING: Skipping '%s': auth profile '%s' is not read-only", job.project.Name, job.project.AuthProfile)
+				report.Results = append(report.Results, ProjectResult{
+					Project: job.project.Name,
+					Status:  StatusSkipped,
+					Summary: fmt.Sprintf("auth profile '%s' is not read-only", job.project.AuthProfile),
+				})
+			}
+			queue = allowed
+		}
+	}
+
+	names := make([]string, len(queue))
+	for i, job := range queue {
+		names[i] = job.project.Name
+	}
+	opts.progress.track(names)
 
-			// Extract a summary from the plan output
-			summary := terraform.ExtractPlanSummary(planOutput)
+	for _, result := range scanProjects(cfg, queue, opts, report.StartedAt) {
+		for _, dir := range moduleChanges[result.Project] {
+			result.Modules = append(result.Modules, displayPath(dir))
+		}
+		report.Results = append(report.Results, result)
 
-			// Always print the drift summary to console
-			log.Printf("DRIFT SUMMARY for '%s':", project.Name)
-			log.Printf("  %s", strings.ReplaceAll(summary, "\n", "\n  "))
+		projectState := store.Project(result.Project)
+		if result.Status == StatusNotScanned {
+			projectState.Pending = true
+			continue
+		}
 
-			// Check if verbose mode is enabled
-			isVerbose := os.Getenv("TERRADRIFT_VERBOSE") == "true"
+		// Failed scans keep the old module contents so the change is still noticed next run
+		if DriftFree(result.Status) || result.Status == StatusDrifted {
+			graph.record(result.Project, projectState)
+		}
 
-			if isVerbose {
-				// In verbose mode, show the full plan output
-				log.Println("FULL TERRAFORM PLAN OUTPUT:")
-				log.Println("=" + strings.Repeat("=", 79))
-				for _, line := range strings.Split(planOutput, "\n") {
-					log.Println(line)
-				}
-				log.Println("=" + strings.Repeat("=", 79))
+		projectState.RecordScan(result.Status, time.Now())
+		if flapping := Flapping(cfg, projectState.RecentStatuses); flapping != projectState.Flapping {
+			projectState.Flapping = flapping
+			if flapping {
+				log.Printf("WARNING: '%s' is flapping: it changed between drifted and clean %d times in its last %d scans",
+					result.Project, StateChanges(projectState.RecentStatuses), len(projectState.RecentStatuses))
 			} else {
-				// In normal mode, show a sample of the actual plan output
-				planLines := strings.Split(planOutput, "\n")
-				relevantLines := []string{}
-				for _, line := range planLines {
-					// Skip empty lines and certain terraform boilerplate
-					trimmed := strings.TrimSpace(line)
-					if trimmed != "" && !strings.HasPrefix(trimmed, "Refreshing") &&
-						!strings.HasPrefix(trimmed, "Reading...") &&
-						!strings.HasPrefix(trimmed, "Read complete") {
-						relevantLines = append(relevantLines, line)
-						if len(relevantLines) >= 10 {
-							break
-						}
-					}
-				}
+				log.Printf("INFO: '%s' is no longer flapping", result.Project)
+				projectState.FlapAlertedAt = time.Time{}
+			}
+		}
+		if schedule != nil {
+			projectState.NextScan = projectState.LastScanned.Add(schedule.interval(projectState))
+		}
+	}
 
-				if len(relevantLines) > 0 {
-					log.Println("DRIFT DETAILS (first 10 relevant lines):")
-					for _, line := range relevantLines {
-						log.Printf("  %s", line)
-					}
-					log.Println("  ... (use --verbose flag or run terraform plan manually for full details)")
-				}
+	// Collapse the same drift across many projects into fleet-level alerts
+	if cfg.Correlation != nil {
+		correlateDrift(cfg, report)
+	}
+
+	report.FinishedAt = time.Now()
+	report.ModuleChanges = graph.changes(moduleChanges)
+
+	// Send scheduled digests of open drift before saving so their send times are persisted
+	sendDigests(cfg, store, report, report.FinishedAt)
+
+	if err := store.Save(); err != nil {
+		log.Printf("WARNING: Failed to save state: %v", err)
+	}
+	if opts.Verification != "" {
+		closeTriage(storage, store)
+	}
+	if err := state.AppendHistory(storage, historyRecords(cfg, report)); err != nil {
+		log.Printf("WARNING: Failed to record history: %v", err)
+	}
+	pruneHistory(cfg, storage, report.FinishedAt)
+	pruneDiffPages(cfg, store, report.FinishedAt)
+
+	// Keep undelivered notifications so they can be re-sent with notify-replay
+	for _, result := range report.Results {
+		undelivered = append(undelivered, result.Undelivered...)
+	}
+	if err := state.AppendOutbox(storage, undelivered); err != nil {
+		log.Printf("WARNING: Failed to save undelivered notifications: %v", err)
+	} else if len(undelivered) > 0 {
+		log.Printf("WARNING: %d notification(s) could not be delivered; re-send them with 'terradrift-watcher notify-replay'", len(undelivered))
+	}
+
+	log.Println("INFO: Drift detection process completed")
+	report.logSummary()
+
+	if report.HasErrors() {
+		return report, fmt.Errorf("drift detection completed with errors")
+	}
+
+	return report, nil
+}
+
+// pruneHistory applies the configured retention to the scan history and the saved logs
+func pruneHistory(cfg *config.Config, storage state.Storage, now time.Time) {
+	if cfg.Retention == nil {
+		return
+	}
+	maxAge, maxRecords, maxBytes, err := cfg.Retention.Limits()
+	if err != nil {
+		log.Printf("WARNING: Invalid retention: %v", err)
+		return
+	}
+	removed, err := state.PruneHistory(storage, maxAge, maxRecords, maxBytes, now)
+	if err != nil {
+		log.Printf("WARNING: Failed to prune history: %v", err)
+	} else if removed > 0 {
+		log.Printf("INFO: Pruned %d history record(s) past the retention limits", removed)
+	}
+
+	// Saved logs are named after their project and time, so each is pruned by age and count
+	for _, namespace := range []string{state.NamespacePlanLogs, state.NamespaceApplyLogs} {
+		removed, err := state.PruneLogs(storage, namespace, maxAge, cfg.Retention.MaxLogs, now)
+		if err != nil {
+			log.Printf("WARNING: Failed to prune %s: %v", namespace, err)
+		} else if removed > 0 {
+			log.Printf("INFO: Pruned %d saved %s past the retention limits", removed, namespace)
+		}
+	}
+}
+
+// historyRecords converts the scanned projects of a report into history records
+func historyRecords(cfg *config.Config, report *Report) []state.HistoryRecord {
+	projects := make(map[string]config.Project)
+	for _, project := range cfg.Projects {
+		projects[project.Name] = project
+	}
+
+	var records []state.HistoryRecord
+	for _, result := range report.Results {
+		if result.Status == StatusNotScanned || result.Status == StatusNotDue {
+			continue
+		}
+		record := state.HistoryRecord{
+			Time:            report.FinishedAt,
+			Project:         result.Project,
+			Status:          result.Status,
+			Summary:         result.Summary,
+			Owners:          result.Owners,
+			Tags:            projects[result.Project].Tags,
+			Duration:        result.Duration,
+			Description:     projects[result.Project].Description,
+			RunbookURL:      projects[result.Project].RunbookURL,
+			TimeToRemediate: result.Remediated,
+			Changes:         result.Changes,
+			Fingerprint:     result.Fingerprint,
+			Incident:        result.Incident,
+			Verification:    result.Verification,
+			DirtyFiles:      result.DirtyFiles,
+			ChangedModules:  result.Modules,
+			ErrorCategory:   result.ErrCategory,
+			TimeoutPhase:    result.TimeoutPhase,
+			StateVersion:    result.StateVersion,
+			LocalVersion:    result.LocalVersion,
+			Outputs:         result.Outputs,
+			Warnings:        result.Warnings,
+			PolicyFindings:  result.PolicyFindings,
+		}
+		if !result.DriftSince.IsZero() {
+			driftSince := result.DriftSince
+			record.DriftSince = &driftSince
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// scanOrder returns the projects with those marked pending by a previous run first, the
+// longest unscanned of them first, so runs cut short by their budget take turns
+func scanOrder(projects []config.Project, store *state.Store) []config.Project {
+	ordered := make([]config.Project, 0, len(projects))
+	for _, project := range projects {
+		if ps, ok := store.Projects[project.Name]; ok && ps.Pending {
+			ordered = append(ordered, project)
+		}
+	}
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return store.Projects[ordered[i].Name].LastScanned.Before(store.Projects[ordered[j].Name].LastScanned)
+	})
+	for _, project := range projects {
+		if ps, ok := store.Projects[project.Name]; !ok || !ps.Pending {
+			ordered = append(ordered, project)
+		}
+	}
+	return ordered
+}
+
+// checkProject runs the drift check for a single project and sends notifications on drift.
+// The project's state is updated with drift aging and escalation progress. With a fixtures
+// directory the plan results are read from the project's fixture instead of running terraform.
+func checkProject(cfg *config.Config, project config.Project, projectState *state.ProjectState, runOpts Options) (result ProjectResult) {
+	start := time.Now()
+	result = ProjectResult{Project: project.Name, Outputs: projectState.Outputs, Incident: projectState.Incident,
+		Verification: runOpts.Verification}
+	defer func() {
+		result.Duration = time.Since(start)
+	}()
+
+	log.Printf("INFO: Checking for drift in '%s'...", project.Name)
+	runOpts.progress.setPhase(project.Name, phaseStarting)
+
+	// fail records a failed scan and alerts the error routes matching its category
+	fail := func(err error, output string) ProjectResult {
+		result = result.failed(err)
+		notifyFailure(cfg, project, output, &result)
+		return result
+	}
+
+	// Credentials are passed to each terraform command rather than set process-wide,
+	// so projects using different auth profiles can run in parallel
+	opts, err := projectOptions(cfg, project)
+	if err != nil {
+		log.Printf("ERROR: Failed to set auth environment for project '%s': %v", project.Name, err)
+		result.ErrCategory = terraform.ErrorAuth
+		return fail(err, "")
+	}
+	defer closeStream(opts)
+	opts.PlanLock = runOpts.planLock
+	opts.Phase = func(phase string) {
+		runOpts.progress.setPhase(project.Name, phase)
+	}
+	if runOpts.Simulate != "" {
+		opts.Fixture = filepath.Join(runOpts.Simulate, project.Name)
+		log.Printf("INFO: Simulating terraform for '%s' from %s", project.Name, opts.Fixture)
+	} else if project.Runner != "" {
+		log.Printf("INFO: Running terraform for '%s' on runner '%s'", project.Name, project.Runner)
+	}
+
+	// Uncommitted .tf edits would show up as drift, so flag (or skip) dirty working trees
+	if project.GitRef == "" && project.Runner == "" && opts.Fixture == "" {
+		dirty, err := gitutil.UncommittedTerraformFiles(project.Path)
+		if err != nil {
+			log.Printf("WARNING: Could not check git status for '%s': %v", project.Name, err)
+		}
+		if len(dirty) > 0 {
+			result.DirtyFiles = dirty
+			if project.RequireCleanWorktree {
+				log.Printf("WARNING: Skipping '%s': %d uncommitted terraform file(s) in the working tree", project.Name, len(dirty))
+				result.Status = StatusSkipped
+				return result
 			}
+			log.Printf("WARNING: '%s' has %d uncommitted terraform file(s); plan output may include local edits:", project.Name, len(dirty))
+			for _, line := range dirty {
+				log.Printf("WARNING:   %s", line)
+			}
+		}
+	}
 
-			// Send notifications to all configured notifiers for this project
-			notificationsSent := 0
-			for _, notifierName := range project.Notifiers {
-				if err := sendNotification(cfg, notifierName, project.Name, summary, planOutput); err != nil {
-					log.Printf("ERROR: Failed to send notification via '%s' for project '%s': %v",
-						notifierName, project.Name, err)
-					hasErrors = true
-				} else {
-					log.Printf("INFO: Notification sent via '%s' for project '%s'", notifierName, project.Name)
-					notificationsSent++
-				}
+	// Measure drift against what was last deployed rather than a possibly-ahead working tree
+	if project.GitRef != "" && opts.Fixture == "" {
+		refPath, cleanup, err := gitutil.CheckoutRef(project.Path, project.GitRef)
+		if err != nil {
+			log.Printf("ERROR: Failed to check out git ref '%s' for project '%s': %v", project.GitRef, project.Name, err)
+			return fail(err, "")
+		}
+		defer cleanup()
+		log.Printf("INFO: Planning '%s' from git ref '%s'", project.Name, project.GitRef)
+		project.Path = refPath
+	}
+
+	// Reuse a recent clean plan while neither the state nor the code has changed since
+	var cacheKey string
+	if cfg.ScanCache != nil && opts.Fixture == "" && project.Runner == "" && (project.Type == "" || project.Type == config.ProjectTypeTerraform) {
+		maxAge, _ := cfg.ScanCache.Window()
+		if cacheKey, err = scanCacheKey(project, opts); err != nil {
+			log.Printf("WARNING: Not using the scan cache for '%s': %v", project.Name, err)
+		} else if projectState.CachedClean(cacheKey, maxAge, time.Now()) {
+			log.Printf("INFO: No drift assumed in '%s': state and code unchanged since the clean plan at %s",
+				project.Name, projectState.CleanAt.Format(time.RFC3339))
+			result.Status = StatusClean
+			result.Cached = true
+			return result
+		}
+	}
+	// Only a clean plan is reused, so drift or a failure is planned again next time
+	projectState.CleanKey = ""
+
+	// Run Terraform drift check
+	var planOutput string
+	var exitCode int
+	var plan *terraform.Plan
+	switch {
+	case project.Type == config.ProjectTypeCDKTF && opts.Fixture == "":
+		planOutput, exitCode, plan, err = planCDKTF(project, opts)
+	case project.Type == config.ProjectTypePulumi && opts.Fixture == "":
+		// Pulumi previews have no JSON plan, so drift is reported without a changelog
+		planOutput, exitCode, err = planPulumi(project, opts)
+	default:
+		planOutput, exitCode, err = terraform.CheckDriftWithOptions(project.Path, opts)
+
+		// Read the saved plan of a drifted project for the changelog
+		if exitCode == 2 {
+			var keep state.Storage
+			if keepsPlan(cfg, project, opts) {
+				keep = runOpts.storage
 			}
+			plan = savedPlan(keep, project, opts)
+		}
+	}
 
-			// If no notifications were sent successfully, ensure the user knows about the drift
-			if notificationsSent == 0 && len(project.Notifiers) > 0 {
-				log.Printf("WARNING: Drift detected but no notifications were sent successfully!")
+	// Providers and wrappers can print invalid UTF-8 or terminal escapes, which would break
+	// summaries and notification payloads
+	planOutput = textutil.Normalize(planOutput)
+
+	// Keep a sanitized copy of the results for replaying with --simulate
+	if runOpts.Record != "" {
+		dir := filepath.Join(runOpts.Record, project.Name)
+		if recordErr := terraform.RecordFixture(dir, planOutput, exitCode, plan); recordErr != nil {
+			log.Printf("WARNING: Failed to record fixture for '%s': %v", project.Name, recordErr)
+		} else {
+			log.Printf("INFO: Recorded plan results for '%s' in %s", project.Name, dir)
+			if cfg.Signing != nil {
+				signFixture(cfg, project.Name, dir, plan != nil)
 			}
+		}
+	}
 
-		default:
-			// Error occurred
+	// Warnings are recorded for reports, and those new since the last plan can be alerted on
+	if exitCode == 0 || exitCode == 2 {
+		recordWarnings(cfg, project, projectState, planOutput, &result)
+	}
+
+	// Static analysis of the configuration is independent of whether the plan succeeded
+	if cfg.Policy != nil && opts.Fixture == "" && (project.Type == "" || project.Type == config.ProjectTypeTerraform) {
+		runOpts.progress.setPhase(project.Name, phasePolicy)
+		runPolicyStages(cfg, project, opts, projectState, &result)
+	}
+
+	// A plan that only reads data sources or changes outputs would modify nothing real
+	noise := exitCode == 2 && cfg.NoisePlans != config.NoiseDrift && project.Type != config.ProjectTypePulumi &&
+		terraform.NoisePlan(planOutput, plan)
+
+	// Handle the results based on exit code
+	switch {
+	case exitCode == 0 || noise:
+		// No drift detected
+		if noise {
+			log.Printf("INFO: Only data source reads or output changes planned in '%s', classified as noise", project.Name)
+			result.Status = StatusNoise
+		} else {
+			log.Printf("INFO: No drift detected in '%s'", project.Name)
+			result.Status = StatusClean
+		}
+
+		// A clean scan after drift means the drift was remediated, unless it cleared before it
+		// was confirmed and alerted
+		if projectState.Unconfirmed() {
+			log.Printf("INFO: Unconfirmed drift in '%s' cleared after %d scan(s) without being alerted",
+				project.Name, projectState.DriftScans)
+		} else if !projectState.DriftSince.IsZero() {
+			result.DriftSince = projectState.DriftSince
+			result.Remediated = time.Since(projectState.DriftSince)
+			log.Printf("INFO: Drift in '%s' remediated after %v", project.Name, result.Remediated.Round(time.Minute))
+			switch {
+			case runOpts.Verification != "" && projectState.Incident != "":
+				notifyVerified(cfg, project, projectState, runOpts.triage, runOpts.Verification, &result)
+			case cfg.NotifyResolved && projectState.Incident != "":
+				notifyResolved(cfg, project, projectState, &result)
+			}
+		}
+		projectState.ResolveDrift()
+		if cfg.Remediation != nil {
+			discardPlan(runOpts.storage, project.Name)
+		}
+		if cacheKey != "" && !noise {
+			projectState.CleanKey = cacheKey
+			projectState.CleanAt = time.Now()
+		}
+
+		// Outputs read now describe the deployed infrastructure for later drift alerts
+		if len(project.Outputs) > 0 && opts.Fixture == "" {
+			outputs, err := terraform.Outputs(project.Path, opts, project.Outputs)
 			if err != nil {
-				log.Printf("ERROR: Failed to check drift for project '%s': %v", project.Name, err)
-				log.Printf("ERROR: Terraform output: %s", planOutput)
+				log.Printf("WARNING: Failed to capture outputs of '%s', keeping the previous ones: %v", project.Name, err)
+			} else {
+				projectState.Outputs = outputs
+				result.Outputs = outputs
+			}
+		}
+
+	case exitCode == 2:
+		// Drift detected - send notifications
+		result.Status = StatusDrifted
+		log.Printf("ALERT: Drift detected in '%s'! Sending notifications...", project.Name)
+
+		// Track how long the project has been continuously drifted
+		driftAge := projectState.MarkDrifted(time.Now())
+		result.DriftSince = projectState.DriftSince
+		if driftAge > 0 {
+			log.Printf("INFO: Project '%s' has been drifted for %v", project.Name, driftAge.Round(time.Minute))
+		}
+
+		// New drift is only alerted once it persisted, as eventually consistent cloud APIs can
+		// report drift that is gone by the next scan. Drift left after an apply is confirmed by
+		// the verification itself.
+		if !projectState.DriftConfirmed {
+			confirmation, _ := cfg.DriftConfirmation(project)
+			if runOpts.Verification != "" || confirmation.Confirmed(projectState.DriftScans, projectState.DriftSince, time.Now()) {
+				projectState.DriftConfirmed = true
+			} else {
+				result.Unconfirmed = true
+			}
+		}
+		if !result.Unconfirmed {
+			openIncident(project, projectState)
+			result.Incident = projectState.Incident
+		}
+
+		// A flapping project's drift is alerted at most once per notify_every, as alerting every
+		// flip trains readers to ignore it
+		var flapHeld bool
+		if cfg.Flapping != nil && !result.Unconfirmed && Flapping(cfg, append(append([]string(nil), projectState.RecentStatuses...), StatusDrifted)) {
+			every, _ := cfg.Flapping.Every()
+			if time.Since(projectState.FlapAlertedAt) < every {
+				flapHeld = true
+			} else {
+				projectState.FlapAlertedAt = time.Now()
+			}
+		}
+
+		runOpts.progress.setPhase(project.Name, phaseAnalyzing)
+		analysis := analyzeDrift(cfg, project, planOutput, plan)
+		// The provider schemas of cdktf projects live in their stack directories, not the project
+		if cfg.DescribeAttributes && len(analysis.Changes) > 0 && opts.Fixture == "" && project.Type != config.ProjectTypeCDKTF {
+			describeChanges(project, opts, analysis.Changes)
+		}
+		result.Summary = analysis.Summary
+		result.Changes = analysis.Changes
+		result.Resources = analysis.Resources
+		result.Fingerprint = analysis.Fingerprint
+		projectState.Fingerprint = analysis.Fingerprint
+		owners := analysis.Owners
+		result.Owners = owners
+
+		// Always print the drift summary to console
+		log.Printf("DRIFT SUMMARY for '%s':", project.Name)
+		log.Printf("  %s", strings.ReplaceAll(analysis.Summary, "\n", "\n  "))
+
+		logPlanDetails(cfg, runOpts.storage, project.Name, planOutput)
+		logChangelog(project.Name, result.Changes)
+		log.Printf("INFO: Drift fingerprint for '%s': %s", project.Name, result.Fingerprint)
+		if len(owners) > 0 {
+			log.Printf("INFO: Drifted resources in '%s' are owned by: %s", project.Name, strings.Join(owners, ", "))
+		}
+
+		alert := notifier.DriftAlert{
+			Project:     project.Name,
+			Summary:     analysis.Summary,
+			PlanOutput:  planOutput,
+			Owners:      owners,
+			Tags:        project.Tags,
+			Changes:     result.Changes,
+			Fingerprint: result.Fingerprint,
+			DriftSince:  projectState.DriftSince,
+			Incident:    projectState.Incident,
+			Description: project.Description,
+			RunbookURL:  project.RunbookURL,
+			Outputs:     result.Outputs,
+		}.Redacted()
+
+		// Keep the history as free of secrets as the notifications
+		result.Summary = alert.Summary
+		result.Changes = alert.Changes
+		if keepsPlan(cfg, project, opts) {
+			recordPlan(runOpts.storage, project, result)
+		}
+
+		// Render the drift for readers without the terraform CLI, linked from the alerts
+		if cfg.HTMLDiffs != nil {
+			if path, link, err := writeDiffPage(cfg, alert); err != nil {
+				log.Printf("WARNING: Failed to write the HTML diff of '%s': %v", project.Name, err)
 			} else {
-				log.Printf("ERROR: Unexpected exit code %d for project '%s'", exitCode, project.Name)
+				log.Printf("INFO: HTML diff of '%s' written to %s", project.Name, path)
+				alert.DiffURL = link
 			}
-			hasErrors = true
 		}
+
+		// Send notifications to all configured notifiers for this project and its owners. Under
+		// correlation they wait until every project is scanned, as they may be collapsed.
+		notifiers := mergeUnique(project.Notifiers, analysis.OwnerNotifiers)
+		window, until := cfg.SuppressedBy(project, time.Now())
+		decision := runOpts.triage.Current(project.Name, projectState)
+		switch {
+		case runOpts.Verification != "":
+			// Drift left after an apply is alerted whatever held it, as someone is waiting for it
+			alert.Escalation = "Remediation not verified after " + runOpts.Verification
+			runOpts.progress.setPhase(project.Name, phaseNotifying)
+			notifyDrift(cfg, alert, closureNotifiers(cfg, runOpts.triage, project.Name, result.Incident, notifiers), &result)
+		case result.Unconfirmed:
+			confirmation, _ := cfg.DriftConfirmation(project)
+			log.Printf("INFO: Holding alerts for new drift in '%s' until it persists for %s (seen in %d scan(s))",
+				project.Name, confirmation, projectState.DriftScans)
+		case flapHeld:
+			every, _ := cfg.Flapping.Every()
+			log.Printf("INFO: '%s' is flapping; holding alerts until %s", project.Name,
+				projectState.FlapAlertedAt.Add(every).Format(time.RFC3339))
+		case decision != nil && decision.HoldsAlerts(time.Now()):
+			log.Printf("INFO: Holding alerts for '%s', triaged as %s", project.Name, decision)
+		case window != nil:
+			log.Printf("INFO: Alerts for '%s' suppressed by window '%s' until %s",
+				project.Name, window.Name, until.Format(time.RFC3339))
+			result.Suppressed = window.Name
+		case cfg.Correlation != nil:
+			result.pending = &pendingAlert{alert: alert, notifiers: notifiers}
+		default:
+			runOpts.progress.setPhase(project.Name, phaseNotifying)
+			notifyDrift(cfg, alert, notifiers, &result)
+		}
+
+		// Escalate drift that has persisted past the configured thresholds and SLOs. Escalations
+		// held back by a window fire on the first run after it closes.
+		if window == nil && !result.Unconfirmed && !flapHeld {
+			escalate(cfg, project, projectState, driftAge, alert, &result)
+			checkSLOs(cfg, project, projectState, driftAge, alert, &result)
+		}
+
+	default:
+		// Error occurred
+		if err != nil {
+			log.Printf("ERROR: Failed to check drift for project '%s': %v", project.Name, err)
+			log.Printf("ERROR: Terraform output: %s", planOutput)
+		} else {
+			err = fmt.Errorf("unexpected exit code %d", exitCode)
+			log.Printf("ERROR: Unexpected exit code %d for project '%s'", exitCode, project.Name)
+		}
+		result.ErrCategory = terraform.ClassifyError(planOutput, err)
+		if phase := terraform.TimeoutPhase(err); phase != "" {
+			result.ErrCategory = terraform.ErrorTimeout
+			result.TimeoutPhase = phase
+		}
+
+		// State written by a newer Terraform fails init or plan with a backend error, or none
+		// terraform names at all, so it is also looked for in the state's metadata
+		switch result.ErrCategory {
+		case terraform.ErrorVersion, terraform.ErrorBackend, terraform.ErrorUnknown:
+			if mismatch := terraform.DetectVersionMismatch(project.Path, opts, planOutput); mismatch != nil {
+				log.Printf("ERROR: State of '%s' was written by Terraform %s, newer than the local Terraform %s",
+					project.Name, mismatch.StateVersion, mismatch.LocalVersion)
+				result.ErrCategory = terraform.ErrorVersion
+				result.StateVersion = mismatch.StateVersion
+				result.LocalVersion = mismatch.LocalVersion
+				result = fail(mismatch, planOutput)
+				result.Status = StatusVersionMismatch
+				return result
+			}
+		}
+		log.Printf("ERROR: Failure in '%s' classified as %s", project.Name, result.ErrCategory)
+		return fail(err, planOutput)
 	}
 
-	log.Println("INFO: Drift detection process completed")
+	return result
+}
+
+// notifyDrift sends a drift alert to the given notifiers, routed by their business hours,
+// recording failures in the result
+func notifyDrift(cfg *config.Config, alert notifier.DriftAlert, notifiers []string, result *ProjectResult) {
+	notifiers = routeByHours(cfg, notifiers, time.Now())
+	notificationsSent := 0
+	for _, notifierName := range notifiers {
+		if err := deliver(cfg, notifierName, alert, result); err != nil {
+			log.Printf("ERROR: Failed to send notification via '%s' for project '%s': %v",
+				notifierName, alert.Project, err)
+		} else {
+			log.Printf("INFO: Notification sent via '%s' for project '%s'", notifierName, alert.Project)
+			notificationsSent++
+		}
+	}
+
+	// If no notifications were sent successfully, ensure the user knows about the drift
+	if notificationsSent == 0 && len(notifiers) > 0 {
+		log.Printf("WARNING: Drift detected but no notifications were sent successfully!")
+	}
+}
+
+// driftAnalysis is what the watcher derives from the plan of a drifted project
+type driftAnalysis struct {
+	Summary        string
+	Changes        []terraform.AttributeChange
+	Resources      []terraform.ResourceChange
+	Fingerprint    string
+	Owners         []string
+	OwnerNotifiers []string
+}
+
+// analyzeDrift summarizes the plan of a drifted project, builds its changelog from the saved
+// plan (which may be nil), fingerprints the drift and works out who owns the drifted resources
+func analyzeDrift(cfg *config.Config, project config.Project, planOutput string, plan *terraform.Plan) driftAnalysis {
+	var analysis driftAnalysis
+	analysis.Summary = terraform.ExtractPlanSummary(planOutput)
+	if plan != nil {
+		analysis.Changes = plan.AttributeChanges(linkResolver(cfg, project))
+	}
+
+	// Fingerprint the drift so repeated reports of it can be correlated downstream, also
+	// across renames of the project
+	analysis.Fingerprint = terraform.Fingerprint(project.OriginalName(), analysis.Changes, planOutput)
+
+	// Owners' teams get paged directly
+	resources := terraform.ParseResourceChanges(planOutput)
+	analysis.Owners, analysis.OwnerNotifiers = resolveOwners(cfg, project.Name, resources)
+
+	// Data source reads are not drift
+	for _, resource := range resources {
+		if resource.Action != terraform.ActionRead {
+			analysis.Resources = append(analysis.Resources, resource)
+		}
+	}
+	return analysis
+}
+
+// describeChanges annotates changed attributes from the provider schemas. Descriptions are a
+// convenience, so a failure is only logged.
+func describeChanges(project config.Project, opts terraform.Options, changes []terraform.AttributeChange) {
+	schemas, err := terraform.ShowProviderSchemas(project.Path, opts)
+	if err != nil {
+		log.Printf("WARNING: Could not describe changed attributes of '%s': %v", project.Name, err)
+		return
+	}
+	schemas.Annotate(changes)
+}
+
+// logPlanDetails prints the plan output for a drifted project, in full in verbose mode
+func logPlanDetails(cfg *config.Config, storage state.Storage, project string, planOutput string) {
+	// Check if verbose mode is enabled
+	isVerbose := os.Getenv("TERRADRIFT_VERBOSE") == "true"
+
+	if isVerbose {
+		// In verbose mode, show the full plan output, within the configured bounds
+		logFullPlan(cfg, storage, project, planOutput)
+		return
+	}
 
-	if hasErrors {
-		return driftFound, fmt.Errorf("drift detection completed with errors")
+	// In normal mode, show a sample of the actual plan output
+	planLines := strings.Split(planOutput, "\n")
+	relevantLines := []string{}
+	for _, line := range planLines {
+		// Skip empty lines and certain terraform boilerplate
+		trimmed := strings.TrimSpace(line)
+		if trimmed != "" && !strings.HasPrefix(trimmed, "Refreshing") &&
+			!strings.HasPrefix(trimmed, "Reading...") &&
+			!strings.HasPrefix(trimmed, "Read complete") {
+			relevantLines = append(relevantLines, line)
+			if len(relevantLines) >= 10 {
+				break
+			}
+		}
 	}
 
-	return driftFound, nil
+	if len(relevantLines) > 0 {
+		log.Println("DRIFT DETAILS (first 10 relevant lines):")
+		for _, line := range relevantLines {
+			log.Printf("  %s", line)
+		}
+		log.Println("  ... (use --verbose flag or run terraform plan manually for full details)")
+	}
 }
 
-// setAuthEnvironment sets the environment variables for the specified auth profile
-func setAuthEnvironment(cfg *config.Config, profileName string) error {
+// authEnvironment returns the environment variables for the specified auth profile
+func authEnvironment(cfg *config.Config, profileName string) (map[string]string, error) {
 	profile, err := cfg.GetAuthProfile(profileName)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// Set environment variables based on provider type
+	env := make(map[string]string)
+
+	// Map environment variables based on provider type
 	switch profile.Provider {
 	case "aws":
-		// Set AWS environment variables
+		// Map AWS environment variables
 		for key, value := range profile.Config {
 			switch key {
 			case "access_key_id":
-				os.Setenv(config.AWSAccessKeyID, value)
+				env[config.AWSAccessKeyID] = value
 			case "secret_access_key":
-				os.Setenv(config.AWSSecretAccessKey, value)
+				env[config.AWSSecretAccessKey] = value
 			case "session_token":
-				os.Setenv(config.AWSSessionToken, value)
+				env[config.AWSSessionToken] = value
 			case "region":
-				os.Setenv(config.AWSRegion, value)
+				env[config.AWSRegion] = value
 			default:
-				// Set any additional AWS environment variables
-				os.Setenv(key, value)
+				// Pass through any additional AWS environment variables
+				env[key] = value
 			}
 		}
 
 	case "azure":
-		// Set Azure environment variables
+		// Map Azure environment variables
 		for key, value := range profile.Config {
 			switch key {
 			case "client_id":
-				os.Setenv(config.AzureClientID, value)
+				env[config.AzureClientID] = value
 			case "client_secret":
-				os.Setenv(config.AzureClientSecret, value)
+				env[config.AzureClientSecret] = value
 			case "subscription_id":
-				os.Setenv(config.AzureSubscriptionID, value)
+				env[config.AzureSubscriptionID] = value
 			case "tenant_id":
-				os.Setenv(config.AzureTenantID, value)
+				env[config.AzureTenantID] = value
 			default:
-				// Set any additional Azure environment variables
-				os.Setenv(key, value)
+				// Pass through any additional Azure environment variables
+				env[key] = value
 			}
 		}
 
 	case "gcp":
-		// Set GCP environment variables
+		// GCP typically uses GOOGLE_APPLICATION_CREDENTIALS pointing to a service account key file
 		for key, value := range profile.Config {
-			// GCP typically uses GOOGLE_APPLICATION_CREDENTIALS pointing to a service account key file
-			os.Setenv(key, value)
+			env[key] = value
 		}
 
 	default:
-		// For unknown providers, just set the config values as-is
+		// For unknown providers, just pass the config values as-is
 		for key, value := range profile.Config {
-			os.Setenv(key, value)
+			env[key] = value
 		}
 	}
 
-	return nil
+	return env, nil
 }
 
-// clearAuthEnvironment clears authentication-related environment variables
-func clearAuthEnvironment() {
-	// Clear AWS variables
-	os.Unsetenv(config.AWSAccessKeyID)
-	os.Unsetenv(config.AWSSecretAccessKey)
-	os.Unsetenv(config.AWSSessionToken)
-	os.Unsetenv(config.AWSRegion)
-
-	// Clear Azure variables
-	os.Unsetenv(config.AzureClientID)
-	os.Unsetenv(config.AzureClientSecret)
-	os.Unsetenv(config.AzureSubscriptionID)
-	os.Unsetenv(config.AzureTenantID)
-
-	// Clear GCP variables
-	os.Unsetenv("GOOGLE_APPLICATION_CREDENTIALS")
-	os.Unsetenv("GOOGLE_CLOUD_PROJECT")
+// projectOptions builds the terraform execution options for a project
+func projectOptions(cfg *config.Config, project config.Project) (terraform.Options, error) {
+	var opts terraform.Options
+
+	if project.Runner != "" {
+		runner, err := cfg.GetRunner(project.Runner)
+		if err != nil {
+			return terraform.Options{}, err
+		}
+		opts.Remote = &terraform.RemoteHost{
+			Host:         runner.Host,
+			User:         runner.User,
+			Port:         runner.Port,
+			IdentityFile: runner.IdentityFile,
+			WorkDir:      runner.WorkDir,
+			SSHOptions:   runner.SSHOptions,
+		}
+	}
+
+	if project.AuthProfile != "" {
+		env, err := authEnvironment(cfg, project.AuthProfile)
+		if err != nil {
+			return terraform.Options{}, err
+		}
+		opts.Env = env
+	}
+	if len(project.Env) > 0 {
+		if opts.Env == nil {
+			opts.Env = make(map[string]string)
+		}
+		for name, value := range project.Env {
+			opts.Env[name] = value
+		}
+	}
+	if project.Workspace != "" {
+		if opts.Env == nil {
+			opts.Env = make(map[string]string)
+		}
+		opts.Env["TF_WORKSPACE"] = project.Workspace
+	}
+
+	opts.Vars = project.VarValues()
+	opts.VarFiles = project.VarFiles
+
+	initTimeout, planTimeout, err := project.Timeouts.Durations()
+	if err != nil {
+		return terraform.Options{}, err
+	}
+	opts.InitTimeout = initTimeout
+	opts.PlanTimeout = planTimeout
+
+	// Stream remote output in verbose mode so long-running plans show progress
+	if opts.Remote != nil && os.Getenv("TERRADRIFT_VERBOSE") == "true" {
+		opts.Stream = redact.LineWriter(os.Stdout)
+	}
+
+	return opts, nil
 }
 
-// sendNotification sends a notification using the specified notifier
-func sendNotification(cfg *config.Config, notifierName string, projectName string, summary string, planOutput string) error {
+// closeStream writes out the last line of a project's streamed output once its commands are done
+func closeStream(opts terraform.Options) {
+	if closer, ok := opts.Stream.(io.Closer); ok {
+		closer.Close()
+	}
+}
+
+// hasLocalProjects reports whether any enabled project runs terraform on this host
+func hasLocalProjects(cfg *config.Config) bool {
+	for _, project := range cfg.Projects {
+		if project.Enabled != nil && !*project.Enabled {
+			continue
+		}
+		if project.Runner == "" && project.Type != config.ProjectTypePulumi {
+			return true
+		}
+	}
+	return false
+}
+
+// httpOptions reads the user agent, headers, signing and TLS settings of a webhook notifier
+// along with the global HTTP client settings
+func httpOptions(cfg *config.Config, notifierCfg *config.Notifier) notifier.HTTPOptions {
+	opts := notifier.HTTPOptions{
+		UserAgent:     notifierCfg.Config[config.NotifierUserAgent],
+		SigningSecret: notifierCfg.Config[config.NotifierSigningSecret],
+		Locale:        notifierCfg.Config[config.NotifierLocale],
+		PayloadFormat: notifierCfg.Config[config.SlackPayloadFormat],
+		Channel:       notifierCfg.Config[config.SlackChannel],
+		Template:      notifierCfg.Config[config.NotifierTemplate],
+		CertFile:      notifierCfg.Config[config.NotifierTLSCertFile],
+		KeyFile:       notifierCfg.Config[config.NotifierTLSKeyFile],
+		CAFile:        notifierCfg.Config[config.NotifierTLSCAFile],
+		Headers:       make(map[string]string),
+	}
+	for key, value := range notifierCfg.Config {
+		if name := strings.TrimPrefix(key, config.NotifierHeaderPrefix); name != key && name != "" {
+			opts.Headers[name] = value
+		}
+	}
+	applyHTTPClient(cfg, &opts)
+	return opts
+}
+
+// applyHTTPClient applies the configured connection settings to webhook request options
+func applyHTTPClient(cfg *config.Config, opts *notifier.HTTPOptions) {
+	if client := cfg.HTTPClient; client != nil {
+		opts.Resolver = client.Resolver
+		opts.DialTimeout, _ = config.ParseDuration(client.DialTimeout)
+		if network, _ := client.Network(); network != "tcp" {
+			opts.Network = network
+		}
+	}
+}
+
+// HTTPClient builds a client for outgoing HTTP calls other than notifications, such as drift
+// export uploads, with the given TLS files and the configured connection settings
+func HTTPClient(cfg *config.Config, certFile, keyFile, caFile string) (*http.Client, error) {
+	opts := notifier.HTTPOptions{CertFile: certFile, KeyFile: keyFile, CAFile: caFile}
+	applyHTTPClient(cfg, &opts)
+	return notifier.NewHTTPClient(opts)
+}
+
+// setRetryBudget resets the notification retry budget shared by all notifiers
+func setRetryBudget(cfg *config.Config) {
+	retryBudget := notifier.DefaultRetryBudget
+	if cfg.NotifyRetryBudget != nil {
+		retryBudget = *cfg.NotifyRetryBudget
+	}
+	notifier.SetRetryBudget(retryBudget)
+}
+
+// deliver sends an alert via one notifier, recording a failed delivery in the result
+func deliver(cfg *config.Config, notifierName string, alert notifier.DriftAlert, result *ProjectResult) error {
+	// Stamped here so alerts kept in the outbox still name the instance that raised them
+	if alert.Metadata == nil {
+		alert.Metadata = cfg.Metadata
+	}
+	err := sendNotification(cfg, notifierName, alert)
+	if err != nil {
+		result.NotifyErrors++
+		result.Undelivered = append(result.Undelivered, state.OutboxEntry{
+			Time:     time.Now(),
+			Notifier: notifierName,
+			Alert:    alert,
+			Error:    err.Error(),
+			Attempts: 1,
+		})
+	}
+	return err
+}
+
+// emailConfig reads the SMTP settings of an email notifier
+func emailConfig(notifierCfg *config.Notifier) notifier.EmailConfig {
+	return notifier.EmailConfig{
+		Host:     notifierCfg.Config[config.EmailSMTPHost],
+		Port:     notifierCfg.Config[config.EmailSMTPPort],
+		From:     notifierCfg.Config[config.EmailFrom],
+		To:       notifier.ParseRecipients(notifierCfg.Config[config.EmailTo]),
+		Username: notifierCfg.Config[config.EmailUsername],
+		Password: notifierCfg.Config[config.EmailPassword],
+		Locale:   notifierCfg.Config[config.NotifierLocale],
+	}
+}
+
+// alertNotifier returns the named notifier with its templated config values rendered for the
+// alert's project
+func alertNotifier(cfg *config.Config, notifierName string, alert notifier.DriftAlert) (*config.Notifier, error) {
 	notifierCfg, err := cfg.GetNotifier(notifierName)
+	if err != nil {
+		return nil, err
+	}
+	return notifierCfg.ForProject(config.ProjectContext{
+		Name:        alert.Project,
+		Tags:        alert.Tags,
+		Owners:      alert.Owners,
+		Description: alert.Description,
+		RunbookURL:  alert.RunbookURL,
+		Outputs:     alert.Outputs,
+	})
+}
+
+// sendNotification sends a notification using the specified notifier
+func sendNotification(cfg *config.Config, notifierName string, alert notifier.DriftAlert) error {
+	notifierCfg, err := alertNotifier(cfg, notifierName, alert)
 	if err != nil {
 		return err
 	}
@@ -278,7 +1176,28 @@ func sendNotification(cfg *config.Config, notifierName string, projectName strin
 		}
 
 		// Use the rich notification format for better visibility with retry logic (3 retries)
-		return notifier.SendSlackRichNotificationWithRetry(webhookURL, projectName, summary, planOutput, 3)
+		return notifier.SendSlackAlertWithRetry(webhookURL, alert, httpOptions(cfg, notifierCfg), 3)
+
+	case "zulip":
+		zulipCfg := notifier.ZulipConfig{
+			Site:     notifierCfg.Config[config.ZulipSite],
+			BotEmail: notifierCfg.Config[config.ZulipBotEmail],
+			APIKey:   notifierCfg.Config[config.ZulipAPIKey],
+			Stream:   notifierCfg.Config[config.ZulipStream],
+			Topic:    notifierCfg.Config[config.ZulipTopic],
+		}
+		return notifier.SendZulipAlertWithRetry(zulipCfg, alert, httpOptions(cfg, notifierCfg), 3)
+
+	case "webhook":
+		url, ok := notifierCfg.Config[config.WebhookURL]
+		if !ok {
+			return fmt.Errorf("webhook url not configured for notifier '%s'", notifierName)
+		}
+		return notifier.SendWebhookEventWithRetry(url, alert, httpOptions(cfg, notifierCfg), 3)
+
+	case "stdout":
+		// Emit one DriftEvent JSON line per alert for log shippers and wrapping scripts
+		return notifier.WriteEvent(os.Stdout, alert)
 
 	case "teams":
 		// TODO: Implement Teams notification
@@ -287,10 +1206,13 @@ func sendNotification(cfg *config.Config, notifierName string, projectName strin
 		return nil
 
 	case "email":
-		// TODO: Implement email notification
-		// For now, we'll just log that email is not yet implemented
-		log.Printf("WARNING: Email notifications not yet implemented for notifier '%s'", notifierName)
-		return nil
+		emailCfg := emailConfig(notifierCfg)
+		if len(emailCfg.To) == 0 {
+			// Digest-only notifier
+			log.Printf("INFO: Notifier '%s' only sends digests, skipping immediate alert", notifierName)
+			return nil
+		}
+		return notifier.SendEmailAlertWithRetry(emailCfg, alert, 3)
 
 	default:
 		return fmt.Errorf("unknown notifier type '%s' for notifier '%s'", notifierCfg.Type, notifierName)
@@ -0,0 +1,291 @@
+package detector
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/state"
+	"github.com/terradrift-watcher/internal/terraform"
+	"github.com/terradrift-watcher/pkg/event"
+)
+
+// cleanPlan is the plan output of a project without drift
+const cleanPlan = "No changes. Your infrastructure matches the configuration.\n"
+
+// driftPlan returns the plan output of a project whose bucket drifted to the given ACL
+func driftPlan(acl string) string {
+	return fmt.Sprintf(`Terraform will perform the following actions:
+
+  # aws_s3_bucket.logs will be updated in-place
+  ~ resource "aws_s3_bucket" "logs" {
+      ~ acl = "%s" -> "private"
+    }
+
+Plan: 0 to add, 1 to change, 0 to destroy.
+`, acl)
+}
+
+// testRun runs drift detection against simulated projects, keeping state in a temporary
+// directory and capturing every notification with a webhook
+type testRun struct {
+	t        *testing.T
+	cfg      *config.Config
+	fixtures string
+
+	mu     sync.Mutex
+	events map[string][]event.DriftEvent // By notifier
+	report *Report                       // Of the last scan
+
+	// deliver, when set, is called with every notification before it is recorded
+	deliver func(event.DriftEvent)
+}
+
+// newTestRun configures the named projects, each alerting the "oncall" webhook notifier, with
+// extra appended to the configuration. Besides "oncall", a "tickets" webhook notifier is
+// configured for extra to refer to.
+func newTestRun(t *testing.T, extra string, projects ...string) *testRun {
+	t.Helper()
+	run := &testRun{t: t, fixtures: t.TempDir(), events: make(map[string][]event.DriftEvent)}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var ev event.DriftEvent
+		if err := json.NewDecoder(r.Body).Decode(&ev); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if run.deliver != nil {
+			run.deliver(ev)
+		}
+		run.mu.Lock()
+		name := strings.TrimPrefix(r.URL.Path, "/")
+		run.events[name] = append(run.events[name], ev)
+		run.mu.Unlock()
+	}))
+	t.Cleanup(server.Close)
+
+	dir := t.TempDir()
+	var content strings.Builder
+	content.WriteString("state_dir: ./state\nprojects:\n")
+	for _, project := range projects {
+		if err := os.MkdirAll(filepath.Join(dir, project), 0755); err != nil {
+			t.Fatal(err)
+		}
+		fmt.Fprintf(&content, "  - name: %s\n    path: ./%s\n    notifiers: [oncall]\n", project, project)
+	}
+	content.WriteString("notifiers:\n")
+	for _, name := range []string{"oncall", "tickets"} {
+		fmt.Fprintf(&content, "  - name: %s\n    type: webhook\n    config:\n      url: %s/%s\n", name, server.URL, name)
+	}
+	content.WriteString(extra)
+
+	path := filepath.Join(dir, "config.yml")
+	if err := os.WriteFile(path, []byte(content.String()), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v\n%s", err, content.String())
+	}
+	run.cfg = cfg
+	return run
+}
+
+// plan sets the plan output the next scans of a project simulate
+func (r *testRun) plan(project, output string) {
+	r.t.Helper()
+	dir := filepath.Join(r.fixtures, project)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		r.t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, terraform.FixtureOutputFile), []byte(output), 0644); err != nil {
+		r.t.Fatal(err)
+	}
+}
+
+// scan runs drift detection once and returns the results by project, forgetting the
+// notifications of earlier scans
+func (r *testRun) scan(opts Options) map[string]ProjectResult {
+	r.t.Helper()
+	r.mu.Lock()
+	r.events = make(map[string][]event.DriftEvent)
+	r.mu.Unlock()
+
+	opts.Simulate = r.fixtures
+	report, err := RunWithOptions(r.cfg, opts)
+	if report == nil {
+		r.t.Fatalf("Run failed: %v", err)
+	}
+	r.report = report
+	results := make(map[string]ProjectResult)
+	for _, result := range report.Results {
+		results[result.Project] = result
+	}
+	return results
+}
+
+// sent returns the notifications of the last scan sent to a notifier
+func (r *testRun) sent(notifier string) []event.DriftEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.events[notifier]
+}
+
+// storage opens the run's state storage
+func (r *testRun) storage() state.Storage {
+	r.t.Helper()
+	storage, err := state.Open(r.cfg)
+	if err != nil {
+		r.t.Fatal(err)
+	}
+	return storage
+}
+
+// projectState returns the state of a project saved by the last scan
+func (r *testRun) projectState(project string) *state.ProjectState {
+	r.t.Helper()
+	store, err := state.Load(r.storage())
+	if err != nil {
+		r.t.Fatal(err)
+	}
+	return store.Project(project)
+}
+
+func TestScanAlertsDriftOnce(t *testing.T) {
+	run := newTestRun(t, "", "network")
+	run.plan("network", driftPlan("public-read"))
+
+	results := run.scan(Options{})
+	if results["network"].Status != StatusDrifted {
+		t.Fatalf("Expected drift, got %+v", results["network"])
+	}
+	sent := run.sent("oncall")
+	if len(sent) != 1 || sent[0].Type != event.TypeDriftDetected || sent[0].Incident == "" {
+		t.Fatalf("Expected one drift alert naming the incident, got %+v", sent)
+	}
+	if incident := run.projectState("network").Incident; incident != sent[0].Incident {
+		t.Errorf("Expected the incident %s in state, got %s", sent[0].Incident, incident)
+	}
+
+	run.plan("network", cleanPlan)
+	if results := run.scan(Options{}); results["network"].Status != StatusClean {
+		t.Errorf("Expected a clean scan, got %+v", results["network"])
+	}
+	if ps := run.projectState("network"); ps.Incident != "" || !ps.DriftSince.IsZero() {
+		t.Errorf("Expected the drift to be resolved, got %+v", ps)
+	}
+}
+
+func TestScanRecordsTimeToRemediate(t *testing.T) {
+	run := newTestRun(t, "", "network")
+	run.plan("network", driftPlan("public-read"))
+	run.scan(Options{})
+	run.backdateDrift("network", 3*time.Hour)
+
+	// The first clean scan after drift closes it and records how long it lasted
+	run.plan("network", cleanPlan)
+	result := run.scan(Options{})["network"]
+	if result.Remediated < 3*time.Hour || result.Remediated > 3*time.Hour+time.Minute {
+		t.Errorf("Expected a time to remediate of 3h, got %v", result.Remediated)
+	}
+	records, err := state.LoadHistory(run.storage(), time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := records[len(records)-1]
+	if last.Status != StatusClean || last.TimeToRemediate != result.Remediated || last.DriftSince == nil {
+		t.Errorf("Expected the remediation in history, got %+v", last)
+	}
+
+	// Later clean scans have nothing left to remediate
+	if result := run.scan(Options{})["network"]; result.Remediated != 0 || !result.DriftSince.IsZero() {
+		t.Errorf("Expected no remediation on a second clean scan, got %+v", result)
+	}
+}
+
+func TestScanAlertsCarryRunbook(t *testing.T) {
+	run := newTestRun(t, "", "network")
+	run.cfg.Projects[0].Description = "Shared VPC of the production accounts"
+	run.cfg.Projects[0].RunbookURL = "https://wiki.example.com/runbooks/network"
+	run.plan("network", driftPlan("public-read"))
+
+	run.scan(Options{})
+	sent := run.sent("oncall")
+	if len(sent) != 1 || sent[0].Description != "Shared VPC of the production accounts" || sent[0].RunbookURL != "https://wiki.example.com/runbooks/network" {
+		t.Fatalf("Expected the alert to carry the description and runbook, got %+v", sent)
+	}
+
+	// Reports built from history link the runbook too
+	records, err := state.LoadHistory(run.storage(), time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].RunbookURL != "https://wiki.example.com/runbooks/network" || records[0].Description == "" {
+		t.Errorf("Expected the runbook in history, got %+v", records)
+	}
+}
+
+func TestScanCountsDriftedResources(t *testing.T) {
+	run := newTestRun(t, "", "network")
+	run.plan("network", `Terraform will perform the following actions:
+
+  # data.aws_iam_policy_document.assume will be read during apply
+ <= data "aws_iam_policy_document" "assume" {
+    }
+
+  # aws_instance.bastion must be replaced
+-/+ resource "aws_instance" "bastion" {
+    }
+
+  # aws_security_group.web will be updated in-place
+  ~ resource "aws_security_group" "web" {
+    }
+
+Plan: 1 to add, 1 to change, 1 to destroy.
+`)
+
+	// Data source reads are not drift, so only the managed resources are counted
+	result := run.scan(Options{})["network"]
+	var got []string
+	for _, resource := range result.Resources {
+		got = append(got, resource.Type+" "+resource.Action)
+	}
+	if expected := []string{"aws_instance replace", "aws_security_group update"}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
+
+func TestScanOrder(t *testing.T) {
+	now := time.Now()
+	store := &state.Store{Projects: map[string]*state.ProjectState{
+		"network":  {LastScanned: now.Add(-time.Hour)},
+		"storage":  {LastScanned: now.Add(-2 * time.Hour), Pending: true},
+		"dns":      {LastScanned: now.Add(-5 * time.Hour), Pending: true},
+		"database": {LastScanned: now.Add(-9 * time.Hour)},
+	}}
+	var projects []config.Project
+	for _, name := range []string{"network", "storage", "dns", "database", "queue"} {
+		projects = append(projects, config.Project{Name: name})
+	}
+
+	// Projects skipped by the previous run go first, the longest unscanned of them first; the
+	// rest keep their configured order
+	expected := []string{"dns", "storage", "network", "database", "queue"}
+	ordered := scanOrder(projects, store)
+	if len(ordered) != len(expected) {
+		t.Fatalf("Expected %d projects, got %d", len(expected), len(ordered))
+	}
+	for i, project := range ordered {
+		if project.Name != expected[i] {
+			t.Errorf("Expected '%s' at position %d, got '%s'", expected[i], i, project.Name)
+		}
+	}
+}
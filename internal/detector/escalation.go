@@ -0,0 +1,82 @@
+package detector
+
+import (
+	"log"
+	"time"
+
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/notifier"
+	"github.com/terradrift-watcher/internal/state"
+)
+
+// escalate fires every escalation rule whose threshold the drift age has passed and that has not
+// fired yet for the current drift. Failed notifications are recorded in the result.
+func escalate(cfg *config.Config, project config.Project, projectState *state.ProjectState, driftAge time.Duration, alert notifier.DriftAlert, result *ProjectResult) {
+	for _, escalation := range cfg.Escalations {
+		if len(escalation.Projects) > 0 && !containsString(escalation.Projects, project.Name) {
+			continue
+		}
+		if projectState.HasEscalated(escalation.Name) {
+			continue
+		}
+
+		after, err := config.ParseDuration(escalation.After)
+		if err != nil || driftAge < after {
+			continue
+		}
+
+		log.Printf("ALERT: Escalating drift in '%s' via '%s' (drifted for %v)",
+			project.Name, escalation.Name, driftAge.Round(time.Minute))
+
+		escalated := alert
+		escalated.Escalation = escalation.Name
+
+		sent := false
+		for _, notifierName := range routeByHours(cfg, escalation.Notifiers, time.Now()) {
+			if err := deliver(cfg, notifierName, escalated, result); err != nil {
+				log.Printf("ERROR: Failed to send escalation via '%s' for project '%s': %v",
+					notifierName, project.Name, err)
+			} else {
+				sent = true
+			}
+		}
+
+		// Only remember the escalation once someone was actually paged, so it is retried otherwise
+		if sent {
+			projectState.Escalations = append(projectState.Escalations, escalation.Name)
+		}
+	}
+}
+
+// sloEscalationPrefix marks SLO breaches among the escalations fired for the current drift
+const sloEscalationPrefix = "slo:"
+
+// checkSLOs alerts the notifiers of every SLO covering the project once the drift age passes its
+// resolution time. Each breach is reported once per drift, like an escalation.
+func checkSLOs(cfg *config.Config, project config.Project, projectState *state.ProjectState, driftAge time.Duration, alert notifier.DriftAlert, result *ProjectResult) {
+	for _, slo := range cfg.SLOs {
+		within, _ := slo.Objective()
+		if !slo.Applies(project.Tags) || driftAge < within || projectState.HasEscalated(sloEscalationPrefix+slo.Name) {
+			continue
+		}
+
+		log.Printf("ALERT: Drift in '%s' breached SLO '%s': unresolved for %v, the objective is %v",
+			project.Name, slo.Name, driftAge.Round(time.Second), within)
+
+		breach := alert
+		breach.Escalation = "SLO " + slo.Name + " breached"
+
+		sent := len(slo.Notifiers) == 0
+		for _, notifierName := range routeByHours(cfg, slo.Notifiers, time.Now()) {
+			if err := deliver(cfg, notifierName, breach, result); err != nil {
+				log.Printf("ERROR: Failed to send SLO breach via '%s' for project '%s': %v",
+					notifierName, project.Name, err)
+			} else {
+				sent = true
+			}
+		}
+		if sent {
+			projectState.Escalations = append(projectState.Escalations, sloEscalationPrefix+slo.Name)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package detector
+
+import (
+	"testing"
+	"time"
+
+	"github.com/terradrift-watcher/internal/notifier"
+	"github.com/terradrift-watcher/internal/state"
+	"github.com/terradrift-watcher/pkg/event"
+)
+
+// escalationConfig pages "tickets" after two hours of drift and on breaching a four hour SLO
+const escalationConfig = `escalations:
+  - name: page
+    after: 2h
+    notifiers: [tickets]
+slos:
+  - name: fix-fast
+    resolve_within: 4h
+    notifiers: [tickets]
+`
+
+// backdateDrift moves the start of a project's current drift back by d
+func (r *testRun) backdateDrift(project string, d time.Duration) {
+	r.t.Helper()
+	store, err := state.Load(r.storage())
+	if err != nil {
+		r.t.Fatal(err)
+	}
+	ps := store.Project(project)
+	ps.DriftSince = ps.DriftSince.Add(-d)
+	if err := store.Save(); err != nil {
+		r.t.Fatal(err)
+	}
+}
+
+// escalations returns the escalations of the last scan sent to a notifier
+func (r *testRun) escalations(notifier string) []string {
+	var names []string
+	for _, ev := range r.sent(notifier) {
+		if ev.Type == event.TypeDriftEscalated {
+			names = append(names, ev.Escalation)
+		}
+	}
+	return names
+}
+
+func TestEscalateThreshold(t *testing.T) {
+	run := newTestRun(t, escalationConfig, "network")
+	project := run.cfg.Projects[0]
+	alert := notifier.DriftAlert{Project: "network", Summary: "1 to change"}
+
+	// A drift exactly as old as the threshold is escalated, a moment younger is not
+	ps := &state.ProjectState{}
+	escalate(run.cfg, project, ps, 2*time.Hour-time.Nanosecond, alert, &ProjectResult{})
+	if len(ps.Escalations) != 0 || len(run.sent("tickets")) != 0 {
+		t.Fatalf("Expected no escalation before the threshold, got %v", ps.Escalations)
+	}
+	escalate(run.cfg, project, ps, 2*time.Hour, alert, &ProjectResult{})
+	if len(ps.Escalations) != 1 || ps.Escalations[0] != "page" || len(run.sent("tickets")) != 1 {
+		t.Fatalf("Expected the escalation at the threshold, got %v", ps.Escalations)
+	}
+
+	// Each rule pages once per drift
+	escalate(run.cfg, project, ps, 3*time.Hour, alert, &ProjectResult{})
+	if len(run.sent("tickets")) != 1 {
+		t.Errorf("Expected the escalation once, got %d", len(run.sent("tickets")))
+	}
+}
+
+func TestCheckSLOsThreshold(t *testing.T) {
+	run := newTestRun(t, escalationConfig, "network")
+	project := run.cfg.Projects[0]
+	alert := notifier.DriftAlert{Project: "network", Summary: "1 to change"}
+
+	ps := &state.ProjectState{}
+	checkSLOs(run.cfg, project, ps, 4*time.Hour-time.Nanosecond, alert, &ProjectResult{})
+	if len(ps.Escalations) != 0 {
+		t.Fatalf("Expected no breach before the objective, got %v", ps.Escalations)
+	}
+	checkSLOs(run.cfg, project, ps, 4*time.Hour, alert, &ProjectResult{})
+	checkSLOs(run.cfg, project, ps, 5*time.Hour, alert, &ProjectResult{})
+	if len(ps.Escalations) != 1 || ps.Escalations[0] != sloEscalationPrefix+"fix-fast" {
+		t.Errorf("Expected one breach of fix-fast, got %v", ps.Escalations)
+	}
+	sent := run.sent("tickets")
+	if len(sent) != 1 || sent[0].Escalation != "SLO fix-fast breached" {
+		t.Errorf("Expected one breach alert, got %+v", sent)
+	}
+
+	// SLOs limited to other tags do not cover the project
+	run.cfg.SLOs[0].Tags = []string{"prod"}
+	ps = &state.ProjectState{}
+	checkSLOs(run.cfg, project, ps, 5*time.Hour, alert, &ProjectResult{})
+	if len(ps.Escalations) != 0 {
+		t.Errorf("Expected an untagged project outside the SLO, got %v", ps.Escalations)
+	}
+}
+
+func TestScanEscalatesAgingDrift(t *testing.T) {
+	run := newTestRun(t, escalationConfig, "network")
+	run.plan("network", driftPlan("public-read"))
+
+	run.scan(Options{})
+	if escalations := run.escalations("tickets"); len(escalations) != 0 {
+		t.Fatalf("Expected no escalation of new drift, got %v", escalations)
+	}
+
+	run.backdateDrift("network", 2*time.Hour+time.Minute)
+	run.scan(Options{})
+	if escalations := run.escalations("tickets"); len(escalations) != 1 || escalations[0] != "page" {
+		t.Fatalf("Expected the drift to be escalated after two hours, got %v", escalations)
+	}
+
+	run.backdateDrift("network", 2*time.Hour)
+	run.scan(Options{})
+	if escalations := run.escalations("tickets"); len(escalations) != 1 || escalations[0] != "SLO fix-fast breached" {
+		t.Errorf("Expected only the SLO breach after four hours, got %v", escalations)
+	}
+}
+
+func TestScanHoldsEscalationsInWindow(t *testing.T) {
+	run := newTestRun(t, escalationConfig+`suppression_windows:
+  - name: maintenance
+    schedule: "* * * * *"
+    duration: 2m
+`, "network")
+	run.plan("network", driftPlan("public-read"))
+
+	run.scan(Options{})
+	run.backdateDrift("network", 3*time.Hour)
+	results := run.scan(Options{})
+	if results["network"].Suppressed != "maintenance" || len(run.sent("tickets")) != 0 || len(run.sent("oncall")) != 0 {
+		t.Fatalf("Expected the window to hold back alerts and escalations, got %+v", run.sent("tickets"))
+	}
+
+	// The held-back escalation fires on the first run after the window closes
+	run.cfg.SuppressionWindows = nil
+	run.scan(Options{})
+	if escalations := run.escalations("tickets"); len(escalations) != 1 || escalations[0] != "page" {
+		t.Errorf("Expected the escalation once the window closed, got %v", escalations)
+	}
+}
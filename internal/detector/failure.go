@@ -0,0 +1,48 @@
+package detector
+
+import (
+	"log"
+	"time"
+
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/notifier"
+)
+
+// notifyFailure alerts the notifiers of the error routes matching a failed scan's project and
+// error category. Like drift alerts they respect suppression windows and business hours.
+func notifyFailure(cfg *config.Config, project config.Project, output string, result *ProjectResult) {
+	var notifiers []string
+	for _, route := range cfg.ErrorRoutes {
+		if route.Matches(project.Name, result.ErrCategory) {
+			notifiers = mergeUnique(notifiers, route.Notifiers)
+		}
+	}
+	if len(notifiers) == 0 {
+		return
+	}
+	if window, until := cfg.SuppressedBy(project, time.Now()); window != nil {
+		log.Printf("INFO: Failure alerts for '%s' suppressed by window '%s' until %s",
+			project.Name, window.Name, until.Format(time.RFC3339))
+		result.Suppressed = window.Name
+		return
+	}
+
+	alert := notifier.DriftAlert{
+		Project:       project.Name,
+		Summary:       result.Err.Error(),
+		PlanOutput:    output,
+		Tags:          project.Tags,
+		Description:   project.Description,
+		RunbookURL:    project.RunbookURL,
+		Outputs:       result.Outputs,
+		ErrorCategory: result.ErrCategory,
+	}.Redacted()
+
+	for _, notifierName := range routeByHours(cfg, notifiers, time.Now()) {
+		if err := deliver(cfg, notifierName, alert, result); err != nil {
+			log.Printf("ERROR: Failed to send failure alert via '%s' for project '%s': %v", notifierName, project.Name, err)
+		} else {
+			log.Printf("INFO: Failure alert (%s) sent via '%s' for project '%s'", result.ErrCategory, notifierName, project.Name)
+		}
+	}
+}
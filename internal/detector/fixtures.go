@@ -0,0 +1,91 @@
+package detector
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/signing"
+	"github.com/terradrift-watcher/internal/terraform"
+	"github.com/terradrift-watcher/internal/textutil"
+)
+
+// FixtureResult is the outcome of replaying the recorded plan results of one project
+type FixtureResult struct {
+	Project     string                      `json:"project"`
+	Status      string                      `json:"status"`
+	Summary     string                      `json:"summary,omitempty"`
+	Changes     []terraform.AttributeChange `json:"changes,omitempty"`
+	Fingerprint string                      `json:"fingerprint,omitempty"`
+	Owners      []string                    `json:"owners,omitempty"`
+	Error       string                      `json:"error,omitempty"`
+}
+
+// ReplayFixtures runs recorded plan results through the same analysis as a scan, without running
+// terraform, sending notifications or touching state. Projects without a fixture are skipped
+// unless one project is requested.
+func ReplayFixtures(cfg *config.Config, fixtures string, project string) ([]FixtureResult, error) {
+	var results []FixtureResult
+	for _, p := range cfg.Projects {
+		if project != "" && p.Name != project {
+			continue
+		}
+
+		opts := terraform.Options{Fixture: filepath.Join(fixtures, p.Name)}
+		if _, err := os.Stat(opts.Fixture); os.IsNotExist(err) && project == "" {
+			continue
+		}
+
+		result := FixtureResult{Project: p.Name}
+		planOutput, exitCode, err := terraform.CheckDriftWithOptions(p.Path, opts)
+		planOutput = textutil.Normalize(planOutput)
+		switch {
+		case err != nil:
+			result.Status = StatusError
+			result.Error = err.Error()
+		case exitCode == 0:
+			result.Status = StatusClean
+		default:
+			plan, err := terraform.ShowPlan(p.Path, opts)
+			if err != nil {
+				result.Error = err.Error()
+			}
+			if cfg.NoisePlans != config.NoiseDrift && p.Type != config.ProjectTypePulumi && terraform.NoisePlan(planOutput, plan) {
+				result.Status = StatusNoise
+				break
+			}
+			analysis := analyzeDrift(cfg, p, planOutput, plan)
+			result.Status = StatusDrifted
+			result.Summary = analysis.Summary
+			result.Changes = analysis.Changes
+			result.Fingerprint = analysis.Fingerprint
+			result.Owners = analysis.Owners
+		}
+		results = append(results, result)
+	}
+
+	if project != "" && len(results) == 0 {
+		return nil, fmt.Errorf("project '%s' not found in configuration", project)
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("no fixtures for configured projects found in %s", fixtures)
+	}
+	return results, nil
+}
+
+// signFixture signs the files of a recorded fixture, so replays can be shown to use the plan
+// results of a real run
+func signFixture(cfg *config.Config, project string, dir string, hasPlan bool) {
+	files := []string{
+		filepath.Join(dir, terraform.FixtureOutputFile),
+		filepath.Join(dir, terraform.FixtureExitCodeFile),
+	}
+	if hasPlan {
+		files = append(files, filepath.Join(dir, terraform.FixturePlanFile))
+	}
+	if err := signing.SignFiles(cfg.Signing.KeyFile, files...); err != nil {
+		log.Printf("WARNING: Failed to sign fixture for '%s': %v", project, err)
+	}
+}
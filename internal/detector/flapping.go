@@ -0,0 +1,41 @@
+package detector
+
+import (
+	"github.com/terradrift-watcher/internal/config"
+)
+
+// FlapWindow is how many of a project's latest scans flapping is judged over
+const FlapWindow = 10
+
+// StateChanges counts how often a project went from drifted to drift-free or back over the
+// latest FlapWindow of the given statuses, oldest first. Failed and skipped scans say nothing
+// about drift and are passed over.
+func StateChanges(statuses []string) int {
+	if len(statuses) > FlapWindow {
+		statuses = statuses[len(statuses)-FlapWindow:]
+	}
+	changes := 0
+	previous := ""
+	for _, status := range statuses {
+		var current string
+		switch {
+		case status == StatusDrifted:
+			current = StatusDrifted
+		case DriftFree(status):
+			current = StatusClean
+		default:
+			continue
+		}
+		if previous != "" && current != previous {
+			changes++
+		}
+		previous = current
+	}
+	return changes
+}
+
+// Flapping reports whether a project alternates between drifted and clean often enough over
+// its latest scans to be flapping
+func Flapping(cfg *config.Config, statuses []string) bool {
+	return StateChanges(statuses) >= cfg.FlapThreshold()
+}
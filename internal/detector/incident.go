@@ -0,0 +1,52 @@
+package detector
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/notifier"
+	"github.com/terradrift-watcher/internal/state"
+)
+
+// openIncident gives confirmed drift the ID of its incident, unless it already has one
+func openIncident(project config.Project, projectState *state.ProjectState) {
+	if projectState.Incident != "" {
+		return
+	}
+	projectState.Incident = state.IncidentID(project.Name, projectState.DriftSince)
+	log.Printf("INFO: Opened incident %s for the drift in '%s'", projectState.Incident, project.Name)
+}
+
+// notifyResolved tells the project's notifiers that the drift of its incident was resolved.
+// It runs before the drift is cleared from the project's state. Resolutions during a
+// suppression window are not sent, as the window may have held back the drift alerts too.
+func notifyResolved(cfg *config.Config, project config.Project, projectState *state.ProjectState, result *ProjectResult) {
+	if window, _ := cfg.SuppressedBy(project, time.Now()); window != nil {
+		log.Printf("INFO: Resolution of incident %s in '%s' not sent, alerts are suppressed by window '%s'",
+			projectState.Incident, project.Name, window.Name)
+		return
+	}
+
+	alert := notifier.DriftAlert{
+		Project:     project.Name,
+		Summary:     fmt.Sprintf("Drift resolved after %v.", result.Remediated.Round(time.Minute)),
+		Tags:        project.Tags,
+		Fingerprint: projectState.Fingerprint,
+		DriftSince:  projectState.DriftSince,
+		Incident:    projectState.Incident,
+		Resolved:    true,
+		Description: project.Description,
+		Outputs:     result.Outputs,
+	}
+	for _, notifierName := range routeByHours(cfg, project.Notifiers, time.Now()) {
+		if err := deliver(cfg, notifierName, alert, result); err != nil {
+			log.Printf("ERROR: Failed to send the resolution of incident %s via '%s': %v",
+				projectState.Incident, notifierName, err)
+		} else {
+			log.Printf("INFO: Resolution of incident %s sent via '%s' for project '%s'",
+				projectState.Incident, notifierName, project.Name)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package detector
+
+import (
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/terraform"
+)
+
+// projectLinkResolver links the resources of a project with the resolvers configured for it,
+// and the matching ones only
+type projectLinkResolver struct {
+	rule     config.LinkResolver
+	template terraform.LinkTemplate
+}
+
+// ResolveLink fills the resolver's template for resources matching its patterns
+func (r projectLinkResolver) ResolveLink(resource terraform.LinkedResource) string {
+	if len(r.rule.Types) > 0 && !matchAnyPattern(r.rule.Types, resource.Type) {
+		return ""
+	}
+	if len(r.rule.Resources) > 0 && !matchAnyPattern(r.rule.Resources, resource.Address) {
+		return ""
+	}
+	return r.template.ResolveLink(resource)
+}
+
+// linkResolver returns the resolvers that link the drifted resources of a project, custom ones
+// before the built-in cloud console links
+func linkResolver(cfg *config.Config, project config.Project) terraform.LinkResolver {
+	resolvers := terraform.LinkResolvers{}
+	if cfg.ResourceLinks != nil {
+		for _, rule := range cfg.ResourceLinks.Resolvers {
+			if len(rule.Projects) > 0 && !containsString(rule.Projects, project.Name) {
+				continue
+			}
+			resolvers = append(resolvers, projectLinkResolver{rule: rule, template: terraform.LinkTemplate(rule.URL)})
+		}
+	}
+	if cfg.ResourceLinks.BuiltinEnabled() {
+		resolvers = append(resolvers, terraform.BuiltinLinkResolvers...)
+	}
+	return resolvers
+}
+
+// matchAnyPattern reports whether the value matches one of the patterns
+func matchAnyPattern(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if matchPattern(pattern, value) {
+			return true
+		}
+	}
+	return false
+}
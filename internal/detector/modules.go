@@ -0,0 +1,126 @@
+package detector
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/state"
+	"github.com/terradrift-watcher/internal/terraform"
+)
+
+// moduleGraph maps projects to the local modules they use, directly or through other modules
+type moduleGraph struct {
+	projectModules map[string][]string // Project name to absolute module directories
+	hashes         map[string]string   // Module directory to the hash of its current contents
+}
+
+// buildModuleGraph reads the local module sources of every enabled project that runs terraform
+// on this host; the directories of remote projects live on their runner
+func buildModuleGraph(cfg *config.Config) *moduleGraph {
+	graph := &moduleGraph{
+		projectModules: make(map[string][]string),
+		hashes:         make(map[string]string),
+	}
+	for _, project := range cfg.Projects {
+		if (project.Enabled != nil && !*project.Enabled) || project.Runner != "" {
+			continue
+		}
+		modules, err := terraform.LocalModuleDirs(project.Path)
+		if err != nil {
+			log.Printf("WARNING: Failed to read the modules of '%s': %v", project.Name, err)
+			continue
+		}
+		for _, dir := range modules {
+			if _, ok := graph.hashes[dir]; ok {
+				graph.projectModules[project.Name] = append(graph.projectModules[project.Name], dir)
+				continue
+			}
+			hash, err := terraform.ModuleHash(dir)
+			if err != nil {
+				log.Printf("WARNING: Failed to read module %s: %v", displayPath(dir), err)
+				continue
+			}
+			graph.hashes[dir] = hash
+			graph.projectModules[project.Name] = append(graph.projectModules[project.Name], dir)
+		}
+	}
+	return graph
+}
+
+// changedModules returns the modules of a project whose contents differ from its last scan.
+// Modules the project did not use at its last scan are not reported.
+func (g *moduleGraph) changedModules(project string, projectState *state.ProjectState) []string {
+	var changed []string
+	for _, dir := range g.projectModules[project] {
+		if previous, ok := projectState.ModuleHashes[dir]; ok && previous != g.hashes[dir] {
+			changed = append(changed, dir)
+		}
+	}
+	return changed
+}
+
+// record stores the current module contents of a scanned project
+func (g *moduleGraph) record(project string, projectState *state.ProjectState) {
+	modules := g.projectModules[project]
+	if len(modules) == 0 {
+		projectState.ModuleHashes = nil
+		return
+	}
+	projectState.ModuleHashes = make(map[string]string, len(modules))
+	for _, dir := range modules {
+		projectState.ModuleHashes[dir] = g.hashes[dir]
+	}
+}
+
+// consumers returns the projects using a module, sorted by name
+func (g *moduleGraph) consumers(dir string) []string {
+	var projects []string
+	for project, modules := range g.projectModules {
+		for _, module := range modules {
+			if module == dir {
+				projects = append(projects, project)
+				break
+			}
+		}
+	}
+	sort.Strings(projects)
+	return projects
+}
+
+// changes lists every changed module once, with all projects using it
+func (g *moduleGraph) changes(moduleChanges map[string][]string) []ModuleChange {
+	seen := make(map[string]bool)
+	var dirs []string
+	for _, modules := range moduleChanges {
+		for _, dir := range modules {
+			if !seen[dir] {
+				seen[dir] = true
+				dirs = append(dirs, dir)
+			}
+		}
+	}
+	sort.Strings(dirs)
+
+	changes := make([]ModuleChange, 0, len(dirs))
+	for _, dir := range dirs {
+		changes = append(changes, ModuleChange{Module: displayPath(dir), Projects: g.consumers(dir)})
+	}
+	return changes
+}
+
+// displayPath shortens a module directory to a path relative to the working directory
+func displayPath(dir string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return dir
+	}
+	rel, err := filepath.Rel(wd, dir)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return dir
+	}
+	return rel
+}
@@ -0,0 +1,67 @@
+package detector
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestScanFlagsSharedModuleChanges(t *testing.T) {
+	run := newTestRun(t, "adaptive_scheduling:\n  enabled: true\n  min_interval: 1h\n  max_interval: 1h\n",
+		"app-a", "app-b", "network")
+	module := filepath.Join(filepath.Dir(run.cfg.Projects[0].Path), "modules", "shared")
+	files := map[string]string{
+		filepath.Join(module, "main.tf"):                   `resource "null_resource" "shared" {}`,
+		filepath.Join(run.cfg.Projects[0].Path, "main.tf"): "module \"shared\" {\n  source = \"../modules/shared\"\n}\n",
+		filepath.Join(run.cfg.Projects[1].Path, "main.tf"): "module \"shared\" {\n  source = \"../modules/shared\"\n}\n",
+		filepath.Join(run.cfg.Projects[2].Path, "main.tf"): `resource "null_resource" "own" {}`,
+	}
+	for path, content := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, project := range []string{"app-a", "app-b", "network"} {
+		run.plan(project, cleanPlan)
+	}
+
+	// The first scan records the module contents; nothing changed before it
+	run.scan(Options{})
+	if len(run.report.ModuleChanges) != 0 {
+		t.Fatalf("Expected no module changes on the first scan, got %+v", run.report.ModuleChanges)
+	}
+
+	// A change to the shared module makes its consumers due before their interval and explains
+	// their drift
+	if err := os.WriteFile(filepath.Join(module, "main.tf"), []byte(`resource "null_resource" "changed" {}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	run.plan("app-a", driftPlan("public-read"))
+	run.plan("app-b", driftPlan("public-read"))
+	results := run.scan(Options{})
+	if results["network"].Status != StatusNotDue {
+		t.Errorf("Expected network to wait for its interval, got %s", results["network"].Status)
+	}
+	for _, project := range []string{"app-a", "app-b"} {
+		if results[project].Status != StatusDrifted || len(results[project].Modules) != 1 {
+			t.Errorf("Expected %s to be scanned for the module change, got %+v", project, results[project])
+		}
+	}
+	changes := run.report.ModuleChanges
+	if len(changes) != 1 || !reflect.DeepEqual(changes[0].Projects, []string{"app-a", "app-b"}) {
+		t.Fatalf("Expected one shared module change, got %+v", changes)
+	}
+	if changes[0].Module != results["app-a"].Modules[0] {
+		t.Errorf("Expected the change to name the module of the results, got %s", changes[0].Module)
+	}
+
+	// The new contents are recorded, so the same change is not reported twice
+	run.cfg.AdaptiveScheduling = nil
+	if run.scan(Options{}); len(run.report.ModuleChanges) != 0 {
+		t.Errorf("Expected the module change to be recorded, got %+v", run.report.ModuleChanges)
+	}
+}
@@ -0,0 +1,82 @@
+package detector
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/terraform"
+)
+
+// resolveOwners returns the owners of the changed resources and the extra notifiers
+// their rules page, both without duplicates and in rule order
+func resolveOwners(cfg *config.Config, projectName string, changes []terraform.ResourceChange) ([]string, []string) {
+	var owners, notifiers []string
+	seenOwner := make(map[string]bool)
+	seenNotifier := make(map[string]bool)
+
+	for _, rule := range cfg.Owners {
+		if len(rule.Projects) > 0 && !containsString(rule.Projects, projectName) {
+			continue
+		}
+		if !ruleMatchesAny(rule, changes) {
+			continue
+		}
+
+		if !seenOwner[rule.Owner] {
+			seenOwner[rule.Owner] = true
+			owners = append(owners, rule.Owner)
+		}
+		for _, notifierName := range rule.Notifiers {
+			if !seenNotifier[notifierName] {
+				seenNotifier[notifierName] = true
+				notifiers = append(notifiers, notifierName)
+			}
+		}
+	}
+
+	return owners, notifiers
+}
+
+// ruleMatchesAny reports whether any changed resource matches one of the rule's patterns
+func ruleMatchesAny(rule config.OwnerRule, changes []terraform.ResourceChange) bool {
+	for _, change := range changes {
+		for _, pattern := range rule.Resources {
+			if matchPattern(pattern, change.Address) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchPattern matches a value against a pattern where '*' matches any sequence of characters
+func matchPattern(pattern string, value string) bool {
+	parts := strings.Split(pattern, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	matched, _ := regexp.MatchString("^"+strings.Join(parts, ".*")+"$", value)
+	return matched
+}
+
+// containsString reports whether the slice contains the value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeUnique appends the values of extra missing from base, e.g. owner notifiers to a project's list
+func mergeUnique(base []string, extra []string) []string {
+	merged := append([]string{}, base...)
+	for _, name := range extra {
+		if !containsString(merged, name) {
+			merged = append(merged, name)
+		}
+	}
+	return merged
+}
@@ -0,0 +1,169 @@
+package detector
+
+import (
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/state"
+	"github.com/terradrift-watcher/internal/terraform"
+)
+
+// scanJob is a project queued for scanning together with its persisted state
+type scanJob struct {
+	project config.Project
+	state   *state.ProjectState
+}
+
+// scanProjects checks the queued projects with up to the configured concurrency, never running
+// more plans against one auth profile than its max_concurrency allows. Results are returned in
+// queue order. Queue order is also scheduling priority: a project only waits behind earlier
+// ones while its auth profile is saturated. Projects sharing a state backend are initialized
+// in parallel but planned one at a time, and projects sharing a directory, such as the region
+// copies of a project, are scanned one at a time.
+func scanProjects(cfg *config.Config, queue []scanJob, opts Options, startedAt time.Time) []ProjectResult {
+	results := make([]ProjectResult, len(queue))
+
+	workers := cfg.Concurrency
+	if opts.Concurrency > 0 {
+		workers = opts.Concurrency
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(queue) {
+		workers = len(queue)
+	}
+
+	limits := make(map[string]int)
+	for _, profile := range cfg.AuthProfiles {
+		if profile.MaxConcurrency > 0 {
+			limits[profile.Name] = profile.MaxConcurrency
+		}
+	}
+
+	// Plans of projects sharing a state would otherwise fail on each other's backend lock
+	planLocks := make([]sync.Locker, len(queue))
+	if workers > 1 {
+		planLocks = sharedBackendLocks(queue, opts)
+	}
+
+	var mu sync.Mutex
+	cond := sync.NewCond(&mu)
+	pending := make([]int, len(queue))
+	for i := range queue {
+		pending[i] = i
+	}
+	running := make(map[string]int)
+	busy := make(map[string]bool)
+
+	// next removes and returns the first pending project whose auth profile has capacity,
+	// blocking while every pending project is throttled
+	next := func() (int, bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		for {
+			if len(pending) == 0 {
+				return 0, false
+			}
+			for pos, i := range pending {
+				profile := queue[i].project.AuthProfile
+				if limit, ok := limits[profile]; ok && running[profile] >= limit {
+					continue
+				}
+				if busy[projectDir(queue[i].project)] {
+					continue
+				}
+				running[profile]++
+				busy[projectDir(queue[i].project)] = true
+				pending = append(pending[:pos], pending[pos+1:]...)
+				return i, true
+			}
+			cond.Wait()
+		}
+	}
+
+	done := func(i int) {
+		mu.Lock()
+		running[queue[i].project.AuthProfile]--
+		delete(busy, projectDir(queue[i].project))
+		mu.Unlock()
+		cond.Broadcast()
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				i, ok := next()
+				if !ok {
+					return
+				}
+				project := queue[i].project
+
+				// Once the run budget is exhausted the remaining projects wait for the next run
+				if opts.MaxDuration > 0 && time.Since(startedAt) >= opts.MaxDuration {
+					log.Printf("WARNING: Run budget of %v exhausted, not scanning '%s'", opts.MaxDuration, project.Name)
+					results[i] = ProjectResult{Project: project.Name, Status: StatusNotScanned}
+				} else {
+					jobOpts := opts
+					jobOpts.planLock = planLocks[i]
+					results[i] = checkProject(cfg, project, queue[i].state, jobOpts)
+				}
+				opts.progress.finish(project.Name, results[i].Status)
+				done(i)
+			}
+		}()
+	}
+	wg.Wait()
+
+	return results
+}
+
+// projectDir identifies the directory a project is planned in, on its runner if it has one
+func projectDir(project config.Project) string {
+	return project.Runner + ":" + project.Path
+}
+
+// sharedBackendLocks returns a lock for each queued project that shares its state backend with
+// another queued project, shared by all of them, and nil for the rest. Backends are read from
+// the project's terraform files, so projects on a remote runner, cdktf and pulumi projects and
+// simulated runs are not matched.
+func sharedBackendLocks(queue []scanJob, opts Options) []sync.Locker {
+	keys := make([]string, len(queue))
+	projects := make(map[string][]string)
+	for i, job := range queue {
+		project := job.project
+		if opts.Simulate != "" || project.Runner != "" || (project.Type != "" && project.Type != config.ProjectTypeTerraform) {
+			continue
+		}
+		key, err := terraform.BackendKey(project.Path)
+		if err != nil {
+			log.Printf("WARNING: Failed to read the backend of '%s': %v", project.Name, err)
+			continue
+		}
+		if key != "" {
+			keys[i] = key
+			projects[key] = append(projects[key], project.Name)
+		}
+	}
+
+	locks := make([]sync.Locker, len(queue))
+	byKey := make(map[string]*sync.Mutex)
+	for i, key := range keys {
+		names := projects[key]
+		if len(names) < 2 {
+			continue
+		}
+		if byKey[key] == nil {
+			byKey[key] = &sync.Mutex{}
+			log.Printf("INFO: Projects %s share a state backend and are planned one at a time", strings.Join(names, ", "))
+		}
+		locks[i] = byKey[key]
+	}
+	return locks
+}
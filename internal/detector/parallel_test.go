@@ -0,0 +1,102 @@
+package detector
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/pkg/event"
+)
+
+func TestScanLimitsPlansPerAuthProfile(t *testing.T) {
+	projects := []string{"prod-a", "prod-b", "prod-c", "prod-d", "dev-a", "dev-b"}
+	run := newTestRun(t, "", projects...)
+	run.cfg.AuthProfiles = []config.AuthProfile{{Name: "prod", Provider: "aws", MaxConcurrency: 2}}
+	profiles := make(map[string]string)
+	for i := range run.cfg.Projects {
+		if i < 4 {
+			run.cfg.Projects[i].AuthProfile = "prod"
+		}
+		profiles[run.cfg.Projects[i].Name] = run.cfg.Projects[i].AuthProfile
+		run.plan(projects[i], driftPlan("public-read"))
+	}
+
+	// Alerts are sent while a project holds its slot, so slow deliveries show how many
+	// projects of each profile are scanned at once
+	var mu sync.Mutex
+	running := make(map[string]int)
+	peak := make(map[string]int)
+	total, peakTotal := 0, 0
+	run.deliver = func(ev event.DriftEvent) {
+		profile := profiles[ev.Project]
+		mu.Lock()
+		running[profile]++
+		total++
+		peak[profile] = max(peak[profile], running[profile])
+		peakTotal = max(peakTotal, total)
+		mu.Unlock()
+
+		time.Sleep(50 * time.Millisecond)
+
+		mu.Lock()
+		running[profile]--
+		total--
+		mu.Unlock()
+	}
+
+	results := run.scan(Options{Concurrency: 4})
+	for _, project := range projects {
+		if results[project].Status != StatusDrifted {
+			t.Errorf("Expected %s to be scanned, got %+v", project, results[project])
+		}
+	}
+	if peak["prod"] != 2 {
+		t.Errorf("Expected at most 2 prod projects at once, and both slots used, got %d", peak["prod"])
+	}
+	// Projects of other profiles go ahead while prod is saturated
+	if peakTotal < 3 {
+		t.Errorf("Expected unlimited projects to run beside prod, got %d at once", peakTotal)
+	}
+}
+
+func TestScanBudget(t *testing.T) {
+	run := newTestRun(t, "", "network", "storage", "dns")
+	run.plan("network", driftPlan("private"))
+	run.plan("storage", cleanPlan)
+	run.plan("dns", cleanPlan)
+
+	// The first scan outlasts the budget, so no further scans start
+	run.deliver = func(event.DriftEvent) { time.Sleep(100 * time.Millisecond) }
+	results := run.scan(Options{MaxDuration: 50 * time.Millisecond, Concurrency: 1})
+	if results["network"].Status != StatusDrifted {
+		t.Errorf("Expected the first project scanned, got %s", results["network"].Status)
+	}
+	for _, project := range []string{"storage", "dns"} {
+		if results[project].Status != StatusNotScanned {
+			t.Errorf("Expected '%s' not scanned once the budget ran out, got %s", project, results[project].Status)
+		}
+		if !run.projectState(project).Pending {
+			t.Errorf("Expected '%s' to be carried over to the next run", project)
+		}
+	}
+	if run.projectState("network").Pending {
+		t.Error("Expected the scanned project not to be pending")
+	}
+
+	// The next run scans the carried over projects first and clears them
+	run.deliver = nil
+	run.scan(Options{Concurrency: 1})
+	var order []string
+	for _, result := range run.report.Results {
+		order = append(order, result.Project)
+	}
+	if len(order) != 3 || order[0] != "storage" || order[1] != "dns" || order[2] != "network" {
+		t.Errorf("Expected the pending projects scanned first, got %v", order)
+	}
+	for _, project := range []string{"storage", "dns"} {
+		if ps := run.projectState(project); ps.Pending || ps.LastStatus != StatusClean {
+			t.Errorf("Expected '%s' scanned and no longer pending, got %+v", project, ps)
+		}
+	}
+}
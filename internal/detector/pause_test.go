@@ -0,0 +1,37 @@
+package detector
+
+import (
+	"testing"
+	"time"
+
+	"github.com/terradrift-watcher/internal/state"
+)
+
+func TestScanPaused(t *testing.T) {
+	run := newTestRun(t, "", "network")
+	run.plan("network", driftPlan("public-read"))
+	if err := state.SavePause(run.storage(), state.Pause{Since: time.Now(), Reason: "provider outage"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A paused run scans nothing and leaves state and notifications alone
+	results := run.scan(Options{})
+	if run.report.Paused == nil || run.report.Paused.Reason != "provider outage" || len(results) != 0 {
+		t.Fatalf("Expected a paused run, got %+v", run.report)
+	}
+	if len(run.sent("oncall")) != 0 || !run.projectState("network").LastScanned.IsZero() {
+		t.Error("Expected nothing to be scanned or alerted while paused")
+	}
+
+	if results := run.scan(Options{IgnorePause: true}); results["network"].Status != StatusDrifted {
+		t.Errorf("Expected --ignore-pause to scan, got %+v", results)
+	}
+
+	// An expired pause no longer holds scanning
+	if err := state.SavePause(run.storage(), state.Pause{Since: time.Now().Add(-2 * time.Hour), Until: time.Now().Add(-time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+	if results := run.scan(Options{}); run.report.Paused != nil || results["network"].Status != StatusDrifted {
+		t.Errorf("Expected an expired pause to be ignored, got %+v", run.report)
+	}
+}
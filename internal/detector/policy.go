@@ -0,0 +1,98 @@
+package detector
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/notifier"
+	"github.com/terradrift-watcher/internal/redact"
+	"github.com/terradrift-watcher/internal/state"
+	"github.com/terradrift-watcher/internal/terraform"
+)
+
+// runPolicyStages runs the configured static analysis tools over the project and keeps their
+// findings in the result and the project's state. The policy notifiers are alerted about
+// findings the previous scan did not have, at or above the notify severity. A stage that
+// fails is logged and skipped, as it does not affect drift detection.
+func runPolicyStages(cfg *config.Config, project config.Project, opts terraform.Options, projectState *state.ProjectState, result *ProjectResult) {
+	var findings []terraform.PolicyFinding
+	for _, stage := range cfg.Policy.Stages {
+		timeout, _ := config.ParseDuration(stage.Timeout)
+		stageFindings, err := terraform.RunPolicyTool(project.Path, opts, stage.Tool, stage.Args, timeout)
+		if err != nil {
+			log.Printf("WARNING: Policy stage %s failed for '%s': %v", stage.Tool, project.Name, err)
+			// Keep the stage's previous findings, so they are not alerted as new once it recovers
+			for _, f := range projectState.PolicyFindings {
+				if f.Tool == stage.Tool {
+					findings = append(findings, f)
+				}
+			}
+			continue
+		}
+		findings = append(findings, stageFindings...)
+	}
+	for i := range findings {
+		findings[i].Message = redact.String(findings[i].Message)
+	}
+
+	known := make(map[string]bool)
+	for _, f := range projectState.PolicyFindings {
+		known[f.Key()] = true
+	}
+	minRank := terraform.PolicySeverityRank(cfg.Policy.Severity())
+	var fresh []terraform.PolicyFinding
+	for _, f := range findings {
+		if !known[f.Key()] && terraform.PolicySeverityRank(f.Severity) >= minRank {
+			fresh = append(fresh, f)
+		}
+	}
+	result.PolicyFindings = findings
+	projectState.PolicyFindings = findings
+
+	if len(findings) > 0 {
+		log.Printf("INFO: Policy stages found %d issue(s) in '%s', %d new at %s severity or above",
+			len(findings), project.Name, len(fresh), cfg.Policy.Severity())
+	}
+	for _, f := range fresh {
+		log.Printf("WARNING: New policy finding in '%s': %s", project.Name, f)
+	}
+	if len(fresh) > 0 && len(cfg.Policy.Notifiers) > 0 {
+		notifyPolicyFindings(cfg, project, fresh, result)
+	}
+}
+
+// notifyPolicyFindings alerts the policy notifiers about new findings. Like drift alerts they
+// respect suppression windows and business hours.
+func notifyPolicyFindings(cfg *config.Config, project config.Project, findings []terraform.PolicyFinding, result *ProjectResult) {
+	if window, until := cfg.SuppressedBy(project, time.Now()); window != nil {
+		log.Printf("INFO: Policy alerts for '%s' suppressed by window '%s' until %s",
+			project.Name, window.Name, until.Format(time.RFC3339))
+		result.Suppressed = window.Name
+		return
+	}
+
+	lines := make([]string, len(findings))
+	for i, f := range findings {
+		lines[i] = "- " + f.String()
+	}
+	alert := notifier.DriftAlert{
+		Project:        project.Name,
+		Summary:        fmt.Sprintf("%d new policy finding(s):\n%s", len(findings), strings.Join(lines, "\n")),
+		Tags:           project.Tags,
+		Description:    project.Description,
+		RunbookURL:     project.RunbookURL,
+		Outputs:        result.Outputs,
+		PolicyFindings: findings,
+	}.Redacted()
+
+	for _, notifierName := range routeByHours(cfg, cfg.Policy.Notifiers, time.Now()) {
+		if err := deliver(cfg, notifierName, alert, result); err != nil {
+			log.Printf("ERROR: Failed to send policy alert via '%s' for project '%s': %v", notifierName, project.Name, err)
+		} else {
+			log.Printf("INFO: Policy alert sent via '%s' for project '%s'", notifierName, project.Name)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package detector
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/state"
+)
+
+// RuntimeWarnings checks the process and the directories it writes to for setups that
+// expose state to other users. Each returned message describes one problem and how to fix it.
+func RuntimeWarnings(cfg *config.Config, lockDir string) []string {
+	// Windows has neither a root user nor Unix permission bits to check
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+
+	var warnings []string
+	if os.Geteuid() == 0 {
+		warnings = append(warnings, "Running as root; the watcher only needs read access to projects and write access "+
+			"to its state directory, so run it as an unprivileged user")
+	}
+
+	if cfg.Storage == nil || cfg.Storage.Type == "" || cfg.Storage.Type == config.StorageFS {
+		dir := state.NewFileStorage(cfg.StateDir).Dir
+		if warning := stateDirWarning(dir); warning != "" {
+			warnings = append(warnings, warning)
+		}
+	}
+
+	if warning := lockDirWarning(lockDir); warning != "" {
+		warnings = append(warnings, warning)
+	}
+	return warnings
+}
+
+// stateDirWarning flags a state directory other users can read or write. History and the
+// outbox keep plan details, which may include resource attributes and secrets.
+func stateDirWarning(dir string) string {
+	info, err := os.Stat(dir)
+	if err != nil {
+		// Not created yet; it will be created private on first write
+		return ""
+	}
+	mode := info.Mode().Perm()
+
+	switch {
+	case mode&0022 != 0:
+		return fmt.Sprintf("State directory %s is writable by other users (%04o); anyone can alter history "+
+			"or suppress alerts. Restrict it with chmod 700", dir, mode)
+	case mode&0044 != 0:
+		return fmt.Sprintf("State directory %s is readable by other users (%04o) and holds plan details. "+
+			"Restrict it with chmod 700", dir, mode)
+	}
+	return ""
+}
+
+// lockDirWarning flags a lock directory where other users could remove or replace the lock
+// file. A world-writable directory is fine when the sticky bit is set, as on /tmp.
+func lockDirWarning(dir string) string {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return ""
+	}
+	mode := info.Mode()
+
+	if mode.Perm()&0002 != 0 && mode&os.ModeSticky == 0 {
+		return fmt.Sprintf("Lock directory %s is world-writable without the sticky bit (%04o); other users "+
+			"can delete the lock and start overlapping runs", dir, mode.Perm())
+	}
+	return ""
+}
@@ -0,0 +1,40 @@
+package detector
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/notifier"
+)
+
+// RenderNotification renders what the named notifier would send for an alert without sending
+// it: the request body for webhook-based notifiers, the message text for Zulip and the full
+// MIME message for email.
+func RenderNotification(cfg *config.Config, notifierName string, alert notifier.DriftAlert) ([]byte, error) {
+	notifierCfg, err := alertNotifier(cfg, notifierName, alert)
+	if err != nil {
+		return nil, err
+	}
+
+	switch notifierCfg.Type {
+	case "slack":
+		return notifier.SlackAlertPayload(alert, httpOptions(cfg, notifierCfg))
+
+	case "webhook":
+		return notifier.WebhookPayload(alert, httpOptions(cfg, notifierCfg))
+
+	case "zulip":
+		content, err := notifier.ZulipContent(alert, httpOptions(cfg, notifierCfg))
+		return []byte(content), err
+
+	case "stdout":
+		return json.Marshal(notifier.NewDriftEvent(alert))
+
+	case "email":
+		return notifier.EmailAlertMessage(emailConfig(notifierCfg), alert)
+
+	default:
+		return nil, fmt.Errorf("notifier type '%s' of notifier '%s' cannot be rendered", notifierCfg.Type, notifierName)
+	}
+}
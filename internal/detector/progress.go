@@ -0,0 +1,149 @@
+package detector
+
+import (
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Progress phases of a project besides the terraform commands reported by terraform.Options.Phase
+const (
+	phasePending   = "pending"
+	phaseStarting  = "starting"
+	phaseAnalyzing = "analyzing"
+	phasePolicy    = "policy"
+	phaseNotifying = "notifying"
+	phaseDone      = "done"
+)
+
+// progress tracks what each queued project of a run is doing, so a long run can be inspected
+// without interrupting it. A nil progress ignores all updates.
+type progress struct {
+	mu        sync.Mutex
+	startedAt time.Time
+	order     []string
+	projects  map[string]*projectProgress
+}
+
+// projectProgress is the current phase of one project
+type projectProgress struct {
+	phase        string
+	started      time.Time // When the project was picked up
+	phaseStarted time.Time
+	status       string // Result status once done
+}
+
+// newProgress starts tracking a run; its projects are added with track once they are queued
+func newProgress(startedAt time.Time) *progress {
+	return &progress{startedAt: startedAt, projects: make(map[string]*projectProgress)}
+}
+
+// track adds the queued projects of the run, all pending
+func (p *progress) track(projects []string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, name := range projects {
+		p.order = append(p.order, name)
+		p.projects[name] = &projectProgress{phase: phasePending}
+	}
+}
+
+// watchProgress logs the progress of a run whenever the process receives a progress signal
+// and returns a function that stops watching. Without it the signal would end the process.
+func watchProgress(p *progress) func() {
+	if len(progressSignals) == 0 {
+		return func() {}
+	}
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, progressSignals...)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-sigChan:
+				p.log()
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(sigChan)
+		close(done)
+	}
+}
+
+// setPhase records that a project entered a phase
+func (p *progress) setPhase(project string, phase string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	entry, ok := p.projects[project]
+	if !ok {
+		return
+	}
+	now := time.Now()
+	if entry.started.IsZero() {
+		entry.started = now
+	}
+	entry.phase = phase
+	entry.phaseStarted = now
+}
+
+// finish records that a project is done with the given status
+func (p *progress) finish(project string, status string) {
+	if p == nil {
+		return
+	}
+	p.setPhase(project, phaseDone)
+	p.mu.Lock()
+	p.projects[project].status = status
+	p.mu.Unlock()
+}
+
+// log prints which projects are done, running and pending, with the phase and elapsed times
+// of the running ones
+func (p *progress) log() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	var done, running, pending []string
+	for _, name := range p.order {
+		entry := p.projects[name]
+		switch entry.phase {
+		case phaseDone:
+			done = append(done, name+" ("+entry.status+")")
+		case phasePending:
+			pending = append(pending, name)
+		default:
+			running = append(running, name)
+		}
+	}
+
+	if len(p.order) == 0 {
+		log.Printf("INFO: Progress after %v: preparing the scan", now.Sub(p.startedAt).Round(time.Second))
+		return
+	}
+	log.Printf("INFO: Progress after %v: %d done, %d running, %d pending",
+		now.Sub(p.startedAt).Round(time.Second), len(done), len(running), len(pending))
+	for _, name := range running {
+		entry := p.projects[name]
+		log.Printf("INFO:   running: '%s' %s for %v (project started %v ago)", name, entry.phase,
+			now.Sub(entry.phaseStarted).Round(time.Second), now.Sub(entry.started).Round(time.Second))
+	}
+	if len(done) > 0 {
+		log.Printf("INFO:   done: %s", strings.Join(done, ", "))
+	}
+	if len(pending) > 0 {
+		log.Printf("INFO:   pending: %s", strings.Join(pending, ", "))
+	}
+}
@@ -0,0 +1,70 @@
+package detector
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// syncBuffer is a buffer the log package and a test can share
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// captureLog collects the log output until the test ends
+func captureLog(t *testing.T) *syncBuffer {
+	buf := &syncBuffer{}
+	log.SetOutput(buf)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+	return buf
+}
+
+func TestProgressLog(t *testing.T) {
+	buf := captureLog(t)
+	p := newProgress(time.Now().Add(-time.Minute))
+	p.log()
+	if !strings.Contains(buf.String(), "preparing the scan") {
+		t.Errorf("Expected a run without queued projects to be preparing, got:\n%s", buf)
+	}
+
+	p.track([]string{"network", "database", "dns"})
+	p.setPhase("network", phaseStarting)
+	p.finish("network", StatusDrifted)
+	p.setPhase("database", "plan")
+	p.log()
+
+	for _, expected := range []string{
+		"1 done, 1 running, 1 pending",
+		"running: 'database' plan for",
+		"done: network (drifted)",
+		"pending: dns",
+	} {
+		if !strings.Contains(buf.String(), expected) {
+			t.Errorf("Expected %q in the progress, got:\n%s", expected, buf)
+		}
+	}
+
+	// Untracked projects and a nil progress are ignored
+	p.setPhase("unknown", "plan")
+	var none *progress
+	none.setPhase("network", "plan")
+	none.finish("network", StatusClean)
+	none.log()
+}
@@ -0,0 +1,11 @@
+//go:build !windows
+
+package detector
+
+import (
+	"os"
+	"syscall"
+)
+
+// progressSignals make a running scan log its progress
+var progressSignals = []os.Signal{syscall.SIGUSR1}
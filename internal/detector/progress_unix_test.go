@@ -0,0 +1,41 @@
+//go:build !windows
+
+package detector
+
+import (
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/terradrift-watcher/pkg/event"
+)
+
+func TestScanLogsProgressOnSignal(t *testing.T) {
+	run := newTestRun(t, "", "network", "database")
+	run.plan("network", driftPlan("public-read"))
+	run.plan("database", cleanPlan)
+	buf := captureLog(t)
+
+	// Signal the process while the first project's alert is being sent
+	run.deliver = func(event.DriftEvent) {
+		if err := syscall.Kill(syscall.Getpid(), syscall.SIGUSR1); err != nil {
+			t.Error(err)
+		}
+		for start := time.Now(); time.Since(start) < 2*time.Second; time.Sleep(10 * time.Millisecond) {
+			if strings.Contains(buf.String(), "Progress after") {
+				return
+			}
+		}
+	}
+
+	results := run.scan(Options{Concurrency: 1})
+	if results["network"].Status != StatusDrifted || results["database"].Status != StatusClean {
+		t.Fatalf("Expected the run to go on after the signal, got %+v", results)
+	}
+	for _, expected := range []string{"0 done, 1 running, 1 pending", "running: 'network' notifying", "pending: database"} {
+		if !strings.Contains(buf.String(), expected) {
+			t.Errorf("Expected %q in the progress, got:\n%s", expected, buf)
+		}
+	}
+}
@@ -0,0 +1,8 @@
+//go:build windows
+
+package detector
+
+import "os"
+
+// progressSignals make a running scan log its progress; Windows has no user signals
+var progressSignals []os.Signal
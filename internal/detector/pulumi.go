@@ -0,0 +1,34 @@
+package detector
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/terraform"
+)
+
+// planPulumi previews each stack of a Pulumi project, or its selected stack when none are
+// configured. Outputs are joined under a header per stack like planCDKTF. A failed stack fails
+// the check; otherwise the project is drifted when any stack is.
+func planPulumi(project config.Project, opts terraform.Options) (string, int, error) {
+	if len(project.Stacks) == 0 {
+		return terraform.CheckPulumiDrift(project.Path, "", opts)
+	}
+
+	var output strings.Builder
+	exitCode := 0
+	for _, stack := range project.Stacks {
+		log.Printf("INFO: Previewing stack '%s' of '%s'", stack, project.Name)
+		previewOutput, stackExitCode, err := terraform.CheckPulumiDrift(project.Path, stack, opts)
+		fmt.Fprintf(&output, "=== Stack: %s ===\n%s\n", stack, previewOutput)
+		if err != nil {
+			return output.String(), 1, fmt.Errorf("stack %s: %w", stack, err)
+		}
+		if stackExitCode == 2 {
+			exitCode = 2
+		}
+	}
+	return output.String(), exitCode, nil
+}
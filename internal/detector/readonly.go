@@ -0,0 +1,104 @@
+package detector
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/notifier"
+	"github.com/terradrift-watcher/internal/state"
+	"github.com/terradrift-watcher/internal/terraform"
+)
+
+// checkCredentials verifies that the credentials of the auth profiles used by the queued
+// projects cannot write, returning the write permissions of each profile that can. Profiles
+// checked within the interval reuse their last result, as does a profile whose check fails.
+// The read-only check notifiers are alerted when a profile gains write permissions; alerts
+// that could not be delivered are returned for the outbox.
+func checkCredentials(cfg *config.Config, store *state.Store, queue []scanJob, now time.Time) (map[string][]string, []state.OutboxEntry) {
+	users := make(map[string][]string)
+	for _, job := range queue {
+		if job.project.AuthProfile != "" {
+			users[job.project.AuthProfile] = append(users[job.project.AuthProfile], job.project.Name)
+		}
+	}
+	profiles := make([]string, 0, len(users))
+	for name := range users {
+		profiles = append(profiles, name)
+	}
+	sort.Strings(profiles)
+
+	if store.CredentialChecks == nil {
+		store.CredentialChecks = make(map[string]*state.CredentialCheck)
+	}
+	interval, _ := cfg.ReadOnlyCheck.Every()
+	writable := make(map[string][]string)
+	var result ProjectResult
+	for _, name := range profiles {
+		previous := store.CredentialChecks[name]
+		if previous != nil && now.Sub(previous.CheckedAt) < interval {
+			if len(previous.Writable) > 0 {
+				writable[name] = previous.Writable
+			}
+			continue
+		}
+
+		profile, err := cfg.GetAuthProfile(name)
+		if err != nil {
+			continue
+		}
+		env, err := authEnvironment(cfg, name)
+		if err != nil {
+			continue
+		}
+		permissions, err := terraform.WriteAccess(profile.Provider, env, cfg.ReadOnlyCheck.Allow)
+		if err != nil {
+			log.Printf("WARNING: Could not check that auth profile '%s' is read-only: %v", name, err)
+			if previous != nil && len(previous.Writable) > 0 {
+				writable[name] = previous.Writable
+			}
+			continue
+		}
+		store.CredentialChecks[name] = &state.CredentialCheck{CheckedAt: now, Writable: permissions}
+		if len(permissions) == 0 {
+			log.Printf("INFO: Auth profile '%s' is read-only", name)
+			continue
+		}
+		writable[name] = permissions
+
+		var gained []string
+		for _, permission := range permissions {
+			if previous == nil || !containsString(previous.Writable, permission) {
+				gained = append(gained, permission)
+			}
+		}
+		log.Printf("WARNING: Auth profile '%s' is not read-only; its credentials can: %s", name, strings.Join(permissions, ", "))
+		if len(gained) > 0 && len(cfg.ReadOnlyCheck.Notifiers) > 0 {
+			notifyWritableProfile(cfg, name, users[name], gained, &result)
+		}
+	}
+	return writable, result.Undelivered
+}
+
+// notifyWritableProfile alerts the read-only check notifiers that a profile gained write
+// permissions. The alert concerns every project using the profile, so it is sent as a
+// fleet-level authentication failure.
+func notifyWritableProfile(cfg *config.Config, profile string, projects []string, permissions []string, result *ProjectResult) {
+	alert := notifier.DriftAlert{
+		Project: FleetProject,
+		Summary: fmt.Sprintf("Auth profile '%s' is not read-only. Its credentials, used by %s, can: %s",
+			profile, strings.Join(projects, ", "), strings.Join(permissions, ", ")),
+		ErrorCategory: terraform.ErrorAuth,
+	}.Redacted()
+
+	for _, notifierName := range routeByHours(cfg, cfg.ReadOnlyCheck.Notifiers, time.Now()) {
+		if err := deliver(cfg, notifierName, alert, result); err != nil {
+			log.Printf("ERROR: Failed to send read-only alert via '%s' for auth profile '%s': %v", notifierName, profile, err)
+		} else {
+			log.Printf("INFO: Read-only alert sent via '%s' for auth profile '%s'", notifierName, profile)
+		}
+	}
+}
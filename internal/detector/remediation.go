@@ -0,0 +1,164 @@
+package detector
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/redact"
+	"github.com/terradrift-watcher/internal/state"
+	"github.com/terradrift-watcher/internal/terraform"
+)
+
+// remediationPlanFile is the name a kept plan is applied under in the project directory, apart
+// from the plan file of drift checks
+const remediationPlanFile = "terradrift-remediate.tfplan"
+
+// RemediationPlan describes the plan kept from the latest drifted scan of a project
+type RemediationPlan struct {
+	Project     string    `json:"project"`
+	Fingerprint string    `json:"fingerprint"`
+	Incident    string    `json:"incident,omitempty"`
+	Summary     string    `json:"summary"`
+	PlannedAt   time.Time `json:"planned_at"`
+}
+
+// remediationKeys returns the keys of the plan of a project and its description in the
+// remediation namespace of the state storage
+func remediationKeys(project string) (string, string) {
+	return project + ".tfplan", project + ".json"
+}
+
+// keepsPlan reports whether the plans of a project are kept for remediation. Only plans made
+// in the project's own directory can be applied there later.
+func keepsPlan(cfg *config.Config, project config.Project, opts terraform.Options) bool {
+	return cfg.Remediation != nil && opts.Fixture == "" && project.Runner == "" && project.GitRef == "" &&
+		(project.Type == "" || project.Type == config.ProjectTypeTerraform)
+}
+
+// keepPlan copies the saved plan of a drifted project into the state storage before it is
+// removed, so it is encrypted and kept like the rest of the state. Its description is written
+// once the drift is analyzed, so a plan without one is never applied.
+func keepPlan(storage state.Storage, project config.Project) {
+	planKey, metaKey := remediationKeys(project.Name)
+	if err := storage.Delete(state.NamespaceRemediation, metaKey); err != nil {
+		log.Printf("WARNING: Failed to remove the kept plan of '%s': %v", project.Name, err)
+		return
+	}
+	data, err := os.ReadFile(filepath.Join(project.Path, terraform.PlanFileName))
+	if err == nil {
+		err = storage.Put(state.NamespaceRemediation, planKey, data)
+	}
+	if err != nil {
+		log.Printf("WARNING: Failed to keep the plan of '%s' for remediation: %v", project.Name, err)
+	}
+}
+
+// recordPlan describes the plan kept for the drift of a project
+func recordPlan(storage state.Storage, project config.Project, result ProjectResult) {
+	planKey, metaKey := remediationKeys(project.Name)
+	if _, err := storage.Get(state.NamespaceRemediation, planKey); err != nil {
+		return
+	}
+	data, err := json.MarshalIndent(RemediationPlan{
+		Project:     project.Name,
+		Fingerprint: result.Fingerprint,
+		Incident:    result.Incident,
+		Summary:     result.Summary,
+		PlannedAt:   time.Now(),
+	}, "", "  ")
+	if err == nil {
+		err = storage.Put(state.NamespaceRemediation, metaKey, data)
+	}
+	if err != nil {
+		log.Printf("WARNING: Failed to record the kept plan of '%s': %v", project.Name, err)
+	}
+}
+
+// discardPlan removes the plan kept for a project whose drift is gone
+func discardPlan(storage state.Storage, project string) {
+	planKey, metaKey := remediationKeys(project)
+	for _, key := range []string{metaKey, planKey} {
+		if err := storage.Delete(state.NamespaceRemediation, key); err != nil {
+			log.Printf("WARNING: Failed to remove the kept plan of '%s': %v", project, err)
+		}
+	}
+}
+
+// LoadRemediationPlan returns the plan kept from the latest drifted scan of a project, or nil
+// when there is none
+func LoadRemediationPlan(storage state.Storage, project string) (*RemediationPlan, error) {
+	_, metaKey := remediationKeys(project)
+	data, err := storage.Get(state.NamespaceRemediation, metaKey)
+	if errors.Is(err, state.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the kept plan of '%s': %w", project, err)
+	}
+	var plan RemediationPlan
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return nil, fmt.Errorf("failed to parse the kept plan of '%s': %w", project, err)
+	}
+	return &plan, nil
+}
+
+// ApplyRemediation applies the plan kept for a project with its credentials and variables. The
+// plan is only written out of the state storage into the project for terraform apply. The
+// redacted output is saved in the storage, under the key returned with the output. The plan is
+// discarded afterwards, as an applied or failed plan cannot be applied again.
+func ApplyRemediation(cfg *config.Config, storage state.Storage, project config.Project) (string, string, error) {
+	planKey, _ := remediationKeys(project.Name)
+	data, err := storage.Get(state.NamespaceRemediation, planKey)
+	if err != nil {
+		return "", "", fmt.Errorf("no plan kept for '%s': %w", project.Name, err)
+	}
+	opts, err := projectOptions(cfg, project)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to set auth environment: %w", err)
+	}
+	defer closeStream(opts)
+	timeout, _ := cfg.Remediation.Timeout()
+
+	target := filepath.Join(project.Path, remediationPlanFile)
+	if err := os.WriteFile(target, data, 0600); err != nil {
+		return "", "", fmt.Errorf("failed to copy the plan into the project: %w", err)
+	}
+	defer os.Remove(target)
+
+	log.Printf("INFO: Applying the kept plan of '%s'...", project.Name)
+	output, applyErr := terraform.ApplyPlan(project.Path, opts, remediationPlanFile, timeout)
+	output = redact.String(output)
+	discardPlan(storage, project.Name)
+
+	logKey, err := saveApplyLog(cfg, storage, project.Name, output)
+	if err != nil {
+		log.Printf("WARNING: Failed to save the apply output of '%s': %v", project.Name, err)
+	}
+	if applyErr != nil {
+		return output, logKey, fmt.Errorf("terraform apply failed: %w", applyErr)
+	}
+	log.Printf("INFO: Applied the kept plan of '%s'", project.Name)
+	return output, logKey, nil
+}
+
+// saveApplyLog saves the output of a remediation apply in the state storage, with its signature
+// when signing is configured, and returns its namespace and key
+func saveApplyLog(cfg *config.Config, storage state.Storage, project string, output string) (string, error) {
+	key := state.LogKey(project, time.Now())
+	name := state.NamespaceApplyLogs + "/" + key
+	if err := storage.Put(state.NamespaceApplyLogs, key, []byte(output)); err != nil {
+		return "", err
+	}
+	if cfg.Signing != nil {
+		if err := signObject(cfg, storage, state.NamespaceApplyLogs, key, []byte(output)); err != nil {
+			return name, fmt.Errorf("failed to sign the apply log: %w", err)
+		}
+	}
+	return name, nil
+}
@@ -0,0 +1,55 @@
+package detector
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/state"
+	"github.com/terradrift-watcher/internal/terraform"
+)
+
+func TestKeptPlansLiveInEncryptedStorage(t *testing.T) {
+	stateDir := t.TempDir()
+	storage, err := state.NewEncryptedStorage(state.NewFileStorage(stateDir), bytes.Repeat([]byte{7}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	project := config.Project{Name: "network", Path: t.TempDir()}
+	secret := "db_password = hunter2"
+	if err := os.WriteFile(filepath.Join(project.Path, terraform.PlanFileName), []byte(secret), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	keepPlan(storage, project)
+	if plan, err := LoadRemediationPlan(storage, project.Name); err != nil || plan != nil {
+		t.Fatalf("Expected no plan before its drift is recorded, got %+v, %v", plan, err)
+	}
+	recordPlan(storage, project, ProjectResult{Fingerprint: "abc123", Incident: "INC-1", Summary: "1 to change"})
+
+	plan, err := LoadRemediationPlan(storage, project.Name)
+	if err != nil || plan == nil || plan.Fingerprint != "abc123" || plan.Incident != "INC-1" {
+		t.Fatalf("Expected the recorded plan, got %+v, %v", plan, err)
+	}
+
+	// Nothing of the plan is written in plaintext, in the state directory or elsewhere
+	filepath.Walk(stateDir, func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			if data, _ := os.ReadFile(path); strings.Contains(string(data), "hunter2") {
+				t.Errorf("Expected %s to be encrypted", path)
+			}
+		}
+		return nil
+	})
+
+	discardPlan(storage, project.Name)
+	if plan, err := LoadRemediationPlan(storage, project.Name); err != nil || plan != nil {
+		t.Errorf("Expected the plan to be discarded, got %+v, %v", plan, err)
+	}
+	if keys, err := storage.List(state.NamespaceRemediation); err != nil || len(keys) != 0 {
+		t.Errorf("Expected no kept objects, got %v, %v", keys, err)
+	}
+}
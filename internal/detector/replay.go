@@ -0,0 +1,81 @@
+package detector
+
+import (
+	"log"
+
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/notifier"
+	"github.com/terradrift-watcher/internal/state"
+)
+
+// ReplayResult counts the outcome of re-sending undelivered notifications
+type ReplayResult struct {
+	Sent      int
+	Failed    int
+	Dropped   int // Drift alerts of projects that are drift-free by now
+	Remaining int // Entries left in the outbox, including those filtered out
+}
+
+// ReplayNotifications re-sends the notifications in the outbox, optionally only those of one
+// project. Delivered entries are removed; failed ones stay for the next replay. Drift and scan
+// error alerts of projects whose last scan found them drift-free are dropped unsent, as their
+// resolution supersedes them.
+func ReplayNotifications(cfg *config.Config, project string) (*ReplayResult, error) {
+	storage, err := state.Open(cfg)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := state.LoadOutbox(storage)
+	if err != nil {
+		return nil, err
+	}
+	store, err := state.Load(storage)
+	if err != nil {
+		return nil, err
+	}
+
+	setRetryBudget(cfg)
+
+	result := &ReplayResult{}
+	var remaining []state.OutboxEntry
+	for _, entry := range state.CoalesceOutbox(entries) {
+		if project != "" && entry.Alert.Project != project {
+			remaining = append(remaining, entry)
+			continue
+		}
+		if stale(entry.Alert, store) {
+			log.Printf("INFO: Dropped notification via '%s' for project '%s': it is %s now",
+				entry.Notifier, entry.Alert.Project, store.Projects[entry.Alert.Project].LastStatus)
+			result.Dropped++
+			continue
+		}
+
+		entry.Attempts++
+		if err := sendNotification(cfg, entry.Notifier, entry.Alert); err != nil {
+			log.Printf("ERROR: Replay via '%s' for project '%s' failed: %v", entry.Notifier, entry.Alert.Project, err)
+			entry.Error = err.Error()
+			remaining = append(remaining, entry)
+			result.Failed++
+			continue
+		}
+		log.Printf("INFO: Replayed notification via '%s' for project '%s' (detected %s)",
+			entry.Notifier, entry.Alert.Project, entry.Time.Format("2006-01-02 15:04"))
+		result.Sent++
+	}
+
+	result.Remaining = len(remaining)
+	if err := state.SaveOutbox(storage, remaining); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// stale reports whether an undelivered alert is about drift or a failed scan of a project
+// that was found drift-free since. Resolutions, warnings and policy findings stay current.
+func stale(alert notifier.DriftAlert, store *state.Store) bool {
+	if alert.Resolved || len(alert.Warnings) > 0 || len(alert.PolicyFindings) > 0 {
+		return false
+	}
+	ps, ok := store.Projects[alert.Project]
+	return ok && DriftFree(ps.LastStatus)
+}
@@ -0,0 +1,54 @@
+package detector
+
+import (
+	"testing"
+	"time"
+
+	"github.com/terradrift-watcher/internal/notifier"
+	"github.com/terradrift-watcher/internal/state"
+)
+
+func TestReplayNotifications(t *testing.T) {
+	run := newTestRun(t, "", "network", "storage")
+	run.plan("network", driftPlan("private"))
+	run.plan("storage", cleanPlan)
+	run.scan(Options{})
+	scanned := len(run.sent("oncall"))
+
+	// Notifications left undelivered by earlier runs, e.g. during an outage
+	now := time.Now()
+	if err := state.AppendOutbox(run.storage(), []state.OutboxEntry{
+		{Time: now, Notifier: "oncall", Attempts: 1, Alert: notifier.DriftAlert{Project: "network", Fingerprint: "old"}},
+		{Time: now, Notifier: "oncall", Attempts: 1, Alert: notifier.DriftAlert{Project: "storage", Fingerprint: "gone"}},
+		{Time: now, Notifier: "tickets", Attempts: 1, Alert: notifier.DriftAlert{Project: "storage", Resolved: true}},
+		{Time: now.Add(time.Minute), Notifier: "oncall", Attempts: 1, Alert: notifier.DriftAlert{Project: "network", Fingerprint: "new"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := ReplayNotifications(run.cfg, "network")
+	if err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+	if result.Sent != 1 || result.Dropped != 0 || result.Remaining != 2 {
+		t.Errorf("Expected the latest network alert sent and the storage entries kept, got %+v", result)
+	}
+	if sent := run.sent("oncall")[scanned:]; len(sent) != 1 || sent[0].Fingerprint != "new" {
+		t.Errorf("Expected only the latest network drift replayed, got %+v", sent)
+	}
+
+	// The storage drift was resolved since, so only its resolution is still worth sending
+	result, err = ReplayNotifications(run.cfg, "")
+	if err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+	if result.Sent != 1 || result.Dropped != 1 || result.Remaining != 0 {
+		t.Errorf("Expected the resolution sent and the stale drift dropped, got %+v", result)
+	}
+	if sent := run.sent("tickets"); len(sent) != 1 || sent[0].Project != "storage" {
+		t.Errorf("Expected the storage resolution replayed, got %+v", sent)
+	}
+	if entries, err := state.LoadOutbox(run.storage()); err != nil || len(entries) != 0 {
+		t.Errorf("Expected an empty outbox, got %+v, %v", entries, err)
+	}
+}
@@ -0,0 +1,193 @@
+package detector
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"github.com/terradrift-watcher/internal/notifier"
+	"github.com/terradrift-watcher/internal/state"
+	"github.com/terradrift-watcher/internal/terraform"
+)
+
+// Project scan statuses
+const (
+	StatusClean      = "clean"
+	StatusDrifted    = "drifted"
+	StatusError      = "error"
+	StatusNotScanned = "not_scanned"
+	StatusNotDue     = "not_due"
+	StatusSkipped    = "skipped"
+
+	// StatusVersionMismatch is a failed scan of state written by a newer Terraform
+	StatusVersionMismatch = "version_mismatch"
+
+	// StatusNoise is a plan that only reads data sources or changes outputs
+	StatusNoise = "noise"
+)
+
+// DriftFree reports whether a scan status means the infrastructure matches the code
+func DriftFree(status string) bool {
+	return status == StatusClean || status == StatusNoise
+}
+
+// ProjectResult holds the outcome of checking a single project
+type ProjectResult struct {
+	Project      string
+	Status       string
+	Summary      string
+	Owners       []string
+	DriftSince   time.Time     // When the current or just-remediated drift first appeared
+	Remediated   time.Duration // Time to remediation, set on the first clean scan after drift
+	Changes      []terraform.AttributeChange
+	Resources    []terraform.ResourceChange // Drifted resources with their planned action
+	Fingerprint  string                     // Identifies the drift across scans and notifiers
+	Incident     string                     // ID of the incident the scan belongs to, see state.IncidentID
+	Verification string                     // Apply the scan verified, see Options.Verification
+	DirtyFiles   []string                   // Uncommitted terraform files in the project's working tree
+	Modules      []string                   // Local modules changed since the previous scan, the probable cause of drift
+	Duration     time.Duration
+	Err          error
+	ErrCategory  string // Why the scan failed, one of terraform.ErrorCategories
+	TimeoutPhase string // Phase killed by its timeout, e.g. init or plan
+	StateVersion string // Terraform version that wrote the state, on a version mismatch
+	LocalVersion string // Terraform version that ran the scan, on a version mismatch
+	NotifyErrors int
+	Undelivered  []state.OutboxEntry // Failed notifications, kept for notify-replay
+	Correlated   bool                // Reported as part of a fleet-level alert
+	Cached       bool                // Clean result reused from an earlier plan with the same state and code
+	Suppressed   string              // Suppression window that held back the alerts
+	Unconfirmed  bool                // New drift whose alerts are held until it persists
+	Outputs      map[string]string   // Terraform outputs captured at the last clean plan
+	Warnings     []terraform.Warning // Warnings terraform printed during the plan
+
+	// PolicyFindings are the static analysis findings of the policy stages
+	PolicyFindings []terraform.PolicyFinding
+
+	pending *pendingAlert // Alert held back until drift across projects is correlated
+}
+
+// pendingAlert is a drift alert waiting to be sent
+type pendingAlert struct {
+	alert     notifier.DriftAlert
+	notifiers []string
+}
+
+// failed marks the result as an error and returns it, classifying the error unless the
+// category is already known
+func (r ProjectResult) failed(err error) ProjectResult {
+	r.Status = StatusError
+	r.Err = err
+	if r.ErrCategory == "" {
+		r.ErrCategory = terraform.ClassifyError("", err)
+	}
+	return r
+}
+
+// Failed reports whether the scan failed, including on a Terraform version mismatch
+func (r ProjectResult) Failed() bool {
+	return r.Status == StatusError || r.Status == StatusVersionMismatch
+}
+
+// Report holds the results of a drift detection run
+type Report struct {
+	StartedAt  time.Time
+	FinishedAt time.Time
+	Results    []ProjectResult
+
+	// Paused is set when nothing was scanned because scanning is paused
+	Paused *state.Pause
+
+	// ModuleChanges lists the shared modules that changed since their consumers were last scanned
+	ModuleChanges []ModuleChange
+}
+
+// ModuleChange is a changed local module together with every project using it
+type ModuleChange struct {
+	Module   string
+	Projects []string
+}
+
+// DriftFound reports whether any project drifted
+func (r *Report) DriftFound() bool {
+	for _, result := range r.Results {
+		if result.Status == StatusDrifted {
+			return true
+		}
+	}
+	return false
+}
+
+// HasErrors reports whether any project failed or any notification could not be sent
+func (r *Report) HasErrors() bool {
+	for _, result := range r.Results {
+		if result.Failed() || result.NotifyErrors > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Count returns the number of projects with the given status
+func (r *Report) Count(status string) int {
+	count := 0
+	for _, result := range r.Results {
+		if result.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+// logSummary prints a per-status overview of the run
+func (r *Report) logSummary() {
+	log.Printf("INFO: Run summary: %d clean, %d noise, %d drifted, %d errors, %d version mismatches, %d skipped, %d not scanned, %d not due (took %v)",
+		r.Count(StatusClean), r.Count(StatusNoise), r.Count(StatusDrifted), r.Count(StatusError), r.Count(StatusVersionMismatch), r.Count(StatusSkipped),
+		r.Count(StatusNotScanned), r.Count(StatusNotDue),
+		r.FinishedAt.Sub(r.StartedAt).Round(time.Second))
+
+	for _, result := range r.Results {
+		if result.Status == StatusNotScanned {
+			log.Printf("INFO:   not scanned: '%s' (will be scanned first next run)", result.Project)
+		}
+		if result.Cached {
+			log.Printf("INFO:   cached: '%s' (plan skipped, state and code unchanged)", result.Project)
+		}
+		if result.Status == StatusError {
+			if result.TimeoutPhase != "" {
+				log.Printf("INFO:   failed: '%s' (%s in %s)", result.Project, result.ErrCategory, result.TimeoutPhase)
+			} else {
+				log.Printf("INFO:   failed: '%s' (%s)", result.Project, result.ErrCategory)
+			}
+		}
+		if result.Status == StatusVersionMismatch {
+			log.Printf("INFO:   version mismatch: '%s' (state written by Terraform %s, local %s)",
+				result.Project, result.StateVersion, result.LocalVersion)
+		}
+		if result.Suppressed != "" {
+			log.Printf("INFO:   suppressed: '%s' (alerts held back by window '%s')", result.Project, result.Suppressed)
+		}
+		if result.Unconfirmed {
+			log.Printf("INFO:   unconfirmed: '%s' (alerts held back until the drift persists)", result.Project)
+		}
+		if len(result.DirtyFiles) > 0 {
+			log.Printf("INFO:   uncommitted changes: '%s' (%s): %s", result.Project, result.Status,
+				strings.Join(result.DirtyFiles, ", "))
+		}
+	}
+
+	// A shared module change drifting several projects is one root cause, not many
+	for _, change := range r.ModuleChanges {
+		var drifted []string
+		for _, result := range r.Results {
+			if result.Status == StatusDrifted && containsString(result.Modules, change.Module) {
+				drifted = append(drifted, result.Project)
+			}
+		}
+		log.Printf("INFO:   module changed: %s, used by %s; drifted: %d (%s)", change.Module,
+			strings.Join(change.Projects, ", "), len(drifted), strings.Join(drifted, ", "))
+		if len(drifted) > 1 {
+			log.Printf("INFO:     the drift in %s likely has a common root cause in %s", strings.Join(drifted, ", "), change.Module)
+		}
+	}
+}
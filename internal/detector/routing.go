@@ -0,0 +1,228 @@
+package detector
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/notifier"
+	"github.com/terradrift-watcher/internal/state"
+	"github.com/terradrift-watcher/internal/terraform"
+)
+
+// routeByHours replaces notifiers that are outside their business hours at now with their
+// outside_hours notifiers. Notifiers without replacements are left out, so their alerts wait
+// for the first run within the hours. Replacements are not routed again.
+func routeByHours(cfg *config.Config, notifiers []string, now time.Time) []string {
+	var routed []string
+	for _, notifierName := range notifiers {
+		targets, outside := hoursTargets(cfg, notifierName, now)
+		switch {
+		case !outside:
+		case len(targets) == 0:
+			log.Printf("INFO: Outside the business hours of '%s', holding its alert until they start", notifierName)
+		default:
+			log.Printf("INFO: Outside the business hours of '%s', routing its alert to %s",
+				notifierName, strings.Join(targets, ", "))
+		}
+		routed = mergeUnique(routed, targets)
+	}
+	return routed
+}
+
+// hoursTargets returns where an alert for the notifier goes at now: the notifier itself within
+// its business hours or without any, otherwise its outside_hours notifiers, which may be none.
+// It also reports whether now is outside the notifier's hours.
+func hoursTargets(cfg *config.Config, notifierName string, now time.Time) ([]string, bool) {
+	notifierCfg, err := cfg.GetNotifier(notifierName)
+	if err != nil || notifierCfg.BusinessHours == nil {
+		// Unknown notifiers fail on delivery as before
+		return []string{notifierName}, false
+	}
+	// Business hours are validated at load time
+	if open, err := notifierCfg.BusinessHours.Contains(now); err != nil || open {
+		return []string{notifierName}, false
+	}
+	return notifierCfg.OutsideHours, true
+}
+
+// Outcomes of a routing decision
+const (
+	RouteSends    = "sends"
+	RouteRerouted = "rerouted"
+	RouteHeld     = "held"
+	RouteWaits    = "waits"
+	RouteSkipped  = "skipped"
+	RouteFails    = "fails"
+)
+
+// Alerts a routing decision is about
+const (
+	AlertDrift      = "drift"
+	AlertEscalation = "escalation"
+	AlertFailure    = "failure"
+	AlertWarning    = "warning"
+)
+
+// RouteDecision explains what an alert would do at one notifier
+type RouteDecision struct {
+	Notifier string
+	Alert    string // AlertDrift, AlertEscalation, AlertFailure or AlertWarning
+	Source   string // Why the notifier gets the alert, e.g. "project" or "owner platform-team"
+	Outcome  string
+	Reason   string
+}
+
+// Routing is a dry run of the notification routing of a project's drift
+type Routing struct {
+	Project string
+
+	// Window is the open suppression window holding back every alert, until Until
+	Window string
+	Until  time.Time
+
+	// Correlated is set when drift alerts wait for the fleet correlation, which may collapse
+	// them into one fleet alert
+	Correlated bool
+
+	Decisions []RouteDecision
+}
+
+// Notified returns the notifiers the drift alert itself would be sent to
+func (r Routing) Notified() []string {
+	var notified []string
+	for _, decision := range r.Decisions {
+		if decision.Alert == AlertDrift && decision.Outcome == RouteSends {
+			notified = mergeUnique(notified, []string{decision.Notifier})
+		}
+	}
+	return notified
+}
+
+// ExplainRouting works out which notifiers the drift alert of a project would reach at now and
+// why, without sending anything: the project's and owners' notifiers, its escalations and the
+// error routes a failed scan would take. Escalations that already fired are read from
+// projectState, which may be nil.
+func ExplainRouting(cfg *config.Config, project config.Project, alert notifier.DriftAlert, projectState *state.ProjectState, now time.Time) Routing {
+	routing := Routing{Project: project.Name, Correlated: cfg.Correlation != nil}
+	if window, until := cfg.SuppressedBy(project, now); window != nil {
+		routing.Window = window.Name
+		routing.Until = until
+	}
+
+	// Owner rules match the changed resources of the plan or, for recorded drift, the changelog
+	changes := terraform.ParseResourceChanges(alert.PlanOutput)
+	for _, change := range alert.Changes {
+		changes = append(changes, terraform.ResourceChange{Address: change.Address})
+	}
+
+	var routed []string
+	route := func(notifiers []string, source string) {
+		for _, notifierName := range notifiers {
+			if containsString(routed, notifierName) {
+				continue
+			}
+			routed = append(routed, notifierName)
+			routing.Decisions = append(routing.Decisions, explainHours(cfg, notifierName, AlertDrift, source, now)...)
+		}
+	}
+	route(project.Notifiers, "project")
+	for _, rule := range cfg.Owners {
+		if len(rule.Projects) > 0 && !containsString(rule.Projects, project.Name) {
+			continue
+		}
+		if ruleMatchesAny(rule, changes) {
+			route(rule.Notifiers, "owner "+rule.Owner)
+		}
+	}
+
+	for _, escalation := range cfg.Escalations {
+		if len(escalation.Projects) > 0 && !containsString(escalation.Projects, project.Name) {
+			continue
+		}
+		source := fmt.Sprintf("escalation %s (after %s)", escalation.Name, escalation.After)
+		after, _ := config.ParseDuration(escalation.After)
+		for _, notifierName := range escalation.Notifiers {
+			switch {
+			case projectState != nil && projectState.HasEscalated(escalation.Name):
+				routing.Decisions = append(routing.Decisions, RouteDecision{Notifier: notifierName, Alert: AlertEscalation,
+					Source: source, Outcome: RouteSkipped, Reason: "already escalated for this drift"})
+			case alert.DriftSince.IsZero() || now.Sub(alert.DriftSince) < after:
+				routing.Decisions = append(routing.Decisions, RouteDecision{Notifier: notifierName, Alert: AlertEscalation,
+					Source: source, Outcome: RouteWaits, Reason: fmt.Sprintf("drifted for %v", now.Sub(alert.DriftSince).Round(time.Minute))})
+			default:
+				routing.Decisions = append(routing.Decisions, explainHours(cfg, notifierName, AlertEscalation, source, now)...)
+			}
+		}
+	}
+
+	for _, errorRoute := range cfg.ErrorRoutes {
+		if len(errorRoute.Projects) > 0 && !containsString(errorRoute.Projects, project.Name) {
+			continue
+		}
+		categories := "any"
+		if len(errorRoute.Categories) > 0 {
+			categories = strings.Join(errorRoute.Categories, ", ")
+		}
+		for _, notifierName := range errorRoute.Notifiers {
+			routing.Decisions = append(routing.Decisions, explainHours(cfg, notifierName, AlertFailure, "error route: "+categories, now)...)
+		}
+	}
+
+	if cfg.Warnings != nil {
+		for _, notifierName := range cfg.Warnings.Notifiers {
+			routing.Decisions = append(routing.Decisions, explainHours(cfg, notifierName, AlertWarning, "new warnings", now)...)
+		}
+	}
+
+	// An open window holds back everything, whatever the business hours
+	if routing.Window != "" {
+		for i := range routing.Decisions {
+			decision := &routing.Decisions[i]
+			if decision.Outcome == RouteSends || decision.Outcome == RouteRerouted {
+				decision.Outcome = RouteHeld
+				decision.Reason = fmt.Sprintf("suppression window '%s' until %s", routing.Window, routing.Until.Format(time.RFC3339))
+			}
+		}
+	}
+	return routing
+}
+
+// explainHours explains the business-hours routing of an alert for a notifier at now, adding
+// the decisions of its outside_hours replacements
+func explainHours(cfg *config.Config, notifierName string, alert string, source string, now time.Time) []RouteDecision {
+	decision := RouteDecision{Notifier: notifierName, Alert: alert, Source: source, Outcome: RouteSends}
+	notifierCfg, err := cfg.GetNotifier(notifierName)
+	if err != nil {
+		decision.Outcome = RouteFails
+		decision.Reason = "unknown notifier"
+		return []RouteDecision{decision}
+	}
+	if notifierCfg.Enabled != nil && !*notifierCfg.Enabled {
+		decision.Outcome = RouteSkipped
+		decision.Reason = "notifier disabled"
+		return []RouteDecision{decision}
+	}
+	if notifierCfg.BusinessHours != nil {
+		decision.Reason = "within business hours"
+	}
+
+	targets, outside := hoursTargets(cfg, notifierName, now)
+	if !outside {
+		return []RouteDecision{decision}
+	}
+	if len(targets) == 0 {
+		decision.Outcome = RouteHeld
+		decision.Reason = "outside business hours, held until they start"
+		return []RouteDecision{decision}
+	}
+	decision.Outcome = RouteRerouted
+	decision.Reason = "outside business hours, sent to " + strings.Join(targets, ", ")
+	decisions := []RouteDecision{decision}
+	for _, target := range targets {
+		decisions = append(decisions, RouteDecision{Notifier: target, Alert: alert, Source: "outside hours of " + notifierName, Outcome: RouteSends})
+	}
+	return decisions
+}
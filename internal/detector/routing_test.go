@@ -0,0 +1,114 @@
+package detector
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/notifier"
+	"github.com/terradrift-watcher/internal/state"
+)
+
+// routingConfig sends drift in buckets to "tickets" as well, and escalates after two hours
+const routingConfig = `owners:
+  - owner: storage-team
+    resources: ["aws_s3_bucket.*"]
+    notifiers: [tickets]
+escalations:
+  - name: page
+    after: 2h
+    notifiers: [tickets]
+`
+
+// decisionFor returns the routing decision of an alert at a notifier
+func decisionFor(t *testing.T, routing Routing, alert, notifierName string) RouteDecision {
+	t.Helper()
+	for _, decision := range routing.Decisions {
+		if decision.Alert == alert && decision.Notifier == notifierName {
+			return decision
+		}
+	}
+	t.Fatalf("No %s decision for %s in %+v", alert, notifierName, routing.Decisions)
+	return RouteDecision{}
+}
+
+func TestExplainRouting(t *testing.T) {
+	run := newTestRun(t, routingConfig, "network")
+	project := run.cfg.Projects[0]
+	wednesday := time.Date(2026, 10, 14, 10, 0, 0, 0, time.UTC)
+	alert := notifier.DriftAlert{Project: "network", PlanOutput: driftPlan("public-read"), DriftSince: wednesday.Add(-time.Hour)}
+
+	routing := ExplainRouting(run.cfg, project, alert, nil, wednesday)
+	if notified := routing.Notified(); !reflect.DeepEqual(notified, []string{"oncall", "tickets"}) {
+		t.Errorf("Expected the project and owner notifiers, got %v", notified)
+	}
+	if decision := decisionFor(t, routing, AlertDrift, "tickets"); decision.Source != "owner storage-team" {
+		t.Errorf("Expected tickets to be notified for the owner, got %+v", decision)
+	}
+	if decision := decisionFor(t, routing, AlertEscalation, "tickets"); decision.Outcome != RouteWaits {
+		t.Errorf("Expected the escalation to wait for two hours of drift, got %+v", decision)
+	}
+
+	// Once the threshold passes the escalation fires, but only once per drift
+	alert.DriftSince = wednesday.Add(-3 * time.Hour)
+	if decision := decisionFor(t, ExplainRouting(run.cfg, project, alert, nil, wednesday), AlertEscalation, "tickets"); decision.Outcome != RouteSends {
+		t.Errorf("Expected the escalation to fire, got %+v", decision)
+	}
+	escalated := &state.ProjectState{Escalations: []string{"page"}}
+	if decision := decisionFor(t, ExplainRouting(run.cfg, project, alert, escalated, wednesday), AlertEscalation, "tickets"); decision.Outcome != RouteSkipped {
+		t.Errorf("Expected a fired escalation to be skipped, got %+v", decision)
+	}
+}
+
+func TestExplainRoutingBusinessHours(t *testing.T) {
+	run := newTestRun(t, routingConfig, "network")
+	for i := range run.cfg.Notifiers {
+		if run.cfg.Notifiers[i].Name == "tickets" {
+			run.cfg.Notifiers[i].BusinessHours = &config.BusinessHours{From: "09:00", To: "17:00", Timezone: "UTC"}
+		}
+	}
+	project := run.cfg.Projects[0]
+	saturday := time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC)
+	alert := notifier.DriftAlert{Project: "network", PlanOutput: driftPlan("public-read"), DriftSince: saturday}
+
+	// Without outside_hours notifiers the alert waits for Monday
+	routing := ExplainRouting(run.cfg, project, alert, nil, saturday)
+	if decision := decisionFor(t, routing, AlertDrift, "tickets"); decision.Outcome != RouteHeld {
+		t.Errorf("Expected tickets to be held outside business hours, got %+v", decision)
+	}
+
+	for i := range run.cfg.Notifiers {
+		if run.cfg.Notifiers[i].Name == "tickets" {
+			run.cfg.Notifiers[i].OutsideHours = []string{"oncall"}
+		}
+	}
+	routing = ExplainRouting(run.cfg, project, alert, nil, saturday)
+	if decision := decisionFor(t, routing, AlertDrift, "tickets"); decision.Outcome != RouteRerouted {
+		t.Errorf("Expected tickets to be rerouted outside business hours, got %+v", decision)
+	}
+	if notified := routing.Notified(); !reflect.DeepEqual(notified, []string{"oncall"}) {
+		t.Errorf("Expected only oncall to be notified, got %v", notified)
+	}
+}
+
+func TestExplainRoutingWindow(t *testing.T) {
+	run := newTestRun(t, routingConfig+`suppression_windows:
+  - name: maintenance
+    schedule: "* * * * *"
+    duration: 2m
+`, "network")
+	now := time.Now()
+	alert := notifier.DriftAlert{Project: "network", PlanOutput: driftPlan("public-read"), DriftSince: now.Add(-3 * time.Hour)}
+
+	// An open window holds back the drift alert and the escalation alike
+	routing := ExplainRouting(run.cfg, run.cfg.Projects[0], alert, nil, now)
+	if routing.Window != "maintenance" || len(routing.Notified()) != 0 {
+		t.Fatalf("Expected the window to hold back every alert, got %+v", routing)
+	}
+	for _, decision := range routing.Decisions {
+		if decision.Outcome != RouteHeld {
+			t.Errorf("Expected %s to be held, got %+v", decision.Notifier, decision)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package detector
+
+import (
+	"log"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/notifier"
+	"github.com/terradrift-watcher/pkg/event"
+)
+
+// runWebhookRetries is how often a failed run event is retried
+const runWebhookRetries = 2
+
+// sendRunEvent posts a run lifecycle event to the run webhooks subscribed to it. Failures are
+// only logged: an unreachable scheduler must not fail the run.
+func sendRunEvent(cfg *config.Config, ev event.RunEvent) {
+	ev.Metadata = cfg.Metadata
+	for _, hook := range cfg.RunWebhooks {
+		if !hook.Wants(ev.Type) {
+			continue
+		}
+		opts := notifier.HTTPOptions{SigningSecret: hook.SigningSecret, Headers: hook.Headers}
+		applyHTTPClient(cfg, &opts)
+		host := hook.URL
+		if u, err := url.Parse(hook.URL); err == nil {
+			host = u.Host
+		}
+		if err := notifier.SendRunEventWithRetry(hook.URL, ev, opts, runWebhookRetries); err != nil {
+			log.Printf("WARNING: Failed to send %s to run webhook %s: %v", ev.Type, host, err)
+		} else {
+			log.Printf("INFO: Sent %s to run webhook %s", ev.Type, host)
+		}
+	}
+}
+
+// runStartedEvent reports the start of a run
+func runStartedEvent(startedAt time.Time, opts Options) event.RunEvent {
+	return event.RunEvent{
+		SchemaVersion: event.SchemaVersion,
+		Type:          event.TypeRunStarted,
+		Time:          startedAt,
+		StartedAt:     startedAt,
+		Requested:     opts.Projects,
+	}
+}
+
+// runFinishedEvent reports the end of a run with its report, which is nil when the run could
+// not start, and its error
+func runFinishedEvent(startedAt time.Time, report *Report, err error) event.RunEvent {
+	finishedAt := time.Now()
+	ev := event.RunEvent{
+		SchemaVersion:   event.SchemaVersion,
+		Type:            event.TypeRunFinished,
+		Time:            finishedAt,
+		StartedAt:       startedAt,
+		FinishedAt:      &finishedAt,
+		DurationSeconds: finishedAt.Sub(startedAt).Seconds(),
+		Outcome:         event.RunSucceeded,
+	}
+	if err != nil {
+		ev.Outcome = event.RunFailed
+		ev.Error = err.Error()
+	}
+	if report == nil {
+		return ev
+	}
+	if report.Paused != nil {
+		ev.Outcome = event.RunPaused
+	}
+
+	ev.DriftFound = report.DriftFound()
+	ev.Counts = make(map[string]int)
+	for _, result := range report.Results {
+		ev.Counts[result.Status]++
+		ev.Projects = append(ev.Projects, event.RunProject{
+			Project:         result.Project,
+			Status:          result.Status,
+			Summary:         result.Summary,
+			Fingerprint:     result.Fingerprint,
+			ErrorCategory:   result.ErrCategory,
+			TimeoutPhase:    result.TimeoutPhase,
+			DurationSeconds: result.Duration.Seconds(),
+		})
+	}
+	sort.Slice(ev.Projects, func(i, j int) bool { return ev.Projects[i].Project < ev.Projects[j].Project })
+	return ev
+}
@@ -0,0 +1,88 @@
+package detector
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/state"
+)
+
+// adaptiveSchedule picks per-project scan intervals from their drift history
+type adaptiveSchedule struct {
+	min time.Duration
+	max time.Duration
+}
+
+// newAdaptiveSchedule returns the schedule for the config, or nil when adaptive scheduling is off
+func newAdaptiveSchedule(cfg *config.AdaptiveScheduling) (*adaptiveSchedule, error) {
+	if cfg == nil || !cfg.Enabled {
+		return nil, nil
+	}
+
+	minInterval, maxInterval, err := cfg.Intervals()
+	if err != nil {
+		return nil, fmt.Errorf("adaptive_scheduling: %w", err)
+	}
+	return &adaptiveSchedule{min: minInterval, max: maxInterval}, nil
+}
+
+// isDue reports whether the project should be scanned now
+func (s *adaptiveSchedule) isDue(ps *state.ProjectState, now time.Time) bool {
+	// Projects skipped by a previous run and never-scanned projects are always due
+	if ps.Pending || ps.NextScan.IsZero() {
+		return true
+	}
+	return !now.Before(ps.NextScan)
+}
+
+// interval returns the time until the next scan of a project. Projects that drifted
+// or failed on their last scan get the minimum interval; otherwise the interval
+// shrinks from the maximum in proportion to how often recent scans found drift.
+func (s *adaptiveSchedule) interval(ps *state.ProjectState) time.Duration {
+	if !DriftFree(ps.LastStatus) || len(ps.RecentStatuses) == 0 {
+		return s.min
+	}
+
+	drifted := 0
+	for _, status := range ps.RecentStatuses {
+		if status == StatusDrifted {
+			drifted++
+		}
+	}
+
+	ratio := float64(drifted) / float64(len(ps.RecentStatuses))
+	return s.max - time.Duration(ratio*float64(s.max-s.min))
+}
+
+// NextDue returns the earliest time after the given one at which adaptive scheduling makes a
+// configured project due, or the zero time when adaptive scheduling is off or none is planned.
+// The daemon wakes for it, as min_interval may be shorter than its own check_interval.
+func NextDue(cfg *config.Config, after time.Time) (time.Time, error) {
+	if cfg.AdaptiveScheduling == nil || !cfg.AdaptiveScheduling.Enabled {
+		return time.Time{}, nil
+	}
+	storage, err := state.Open(cfg)
+	if err != nil {
+		return time.Time{}, err
+	}
+	store, err := state.Load(storage)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var next time.Time
+	for _, project := range cfg.Projects {
+		if project.Enabled != nil && !*project.Enabled {
+			continue
+		}
+		ps, ok := store.Projects[project.Name]
+		if !ok || !ps.NextScan.After(after) {
+			continue
+		}
+		if next.IsZero() || ps.NextScan.Before(next) {
+			next = ps.NextScan
+		}
+	}
+	return next, nil
+}
@@ -0,0 +1,76 @@
+package detector
+
+import (
+	"testing"
+	"time"
+
+	"github.com/terradrift-watcher/internal/state"
+)
+
+func TestAdaptiveScheduleInterval(t *testing.T) {
+	schedule := &adaptiveSchedule{min: time.Hour, max: 9 * time.Hour}
+
+	// Only scans that found the infrastructure matching the code earn a longer interval
+	tests := []struct {
+		status   string
+		expected time.Duration
+	}{
+		{StatusClean, 9 * time.Hour},
+		{StatusNoise, 9 * time.Hour},
+		{StatusDrifted, time.Hour},
+		{StatusError, time.Hour},
+		{StatusVersionMismatch, time.Hour},
+		{StatusSkipped, time.Hour},
+		{StatusNotScanned, time.Hour},
+		{StatusNotDue, time.Hour},
+		{"", time.Hour},
+	}
+	for _, tt := range tests {
+		ps := &state.ProjectState{LastStatus: tt.status, RecentStatuses: []string{tt.status}}
+		if got := schedule.interval(ps); got != tt.expected {
+			t.Errorf("Expected %v after a %q scan, got %v", tt.expected, tt.status, got)
+		}
+	}
+
+	// Drift in recent scans shortens the interval of a project that is clean now
+	ps := &state.ProjectState{LastStatus: StatusClean, RecentStatuses: []string{StatusDrifted, StatusClean, StatusNoise, StatusClean}}
+	if got := schedule.interval(ps); got != 7*time.Hour {
+		t.Errorf("Expected 7h with one drifted scan in four, got %v", got)
+	}
+
+	// Without history there is nothing to go on
+	if got := schedule.interval(&state.ProjectState{LastStatus: StatusClean}); got != time.Hour {
+		t.Errorf("Expected the minimum interval without recent scans, got %v", got)
+	}
+}
+
+func TestNextDue(t *testing.T) {
+	run := newTestRun(t, "adaptive_scheduling:\n  enabled: true\n  min_interval: 10m\n  max_interval: 24h\n", "network", "storage")
+	run.plan("network", driftPlan("private"))
+	run.plan("storage", cleanPlan)
+	start := time.Now()
+	run.scan(Options{})
+
+	// The drifted project is due again after min_interval, well before the clean one
+	due, err := NextDue(run.cfg, start)
+	if err != nil {
+		t.Fatalf("NextDue failed: %v", err)
+	}
+	if expected := run.projectState("network").NextScan; !due.Equal(expected) {
+		t.Errorf("Expected the drifted project's next scan %v, got %v", expected, due)
+	}
+
+	// Scans planned before the given time are left to the regular schedule
+	due, err = NextDue(run.cfg, run.projectState("network").NextScan)
+	if err != nil {
+		t.Fatalf("NextDue failed: %v", err)
+	}
+	if expected := run.projectState("storage").NextScan; !due.Equal(expected) {
+		t.Errorf("Expected the clean project's next scan %v, got %v", expected, due)
+	}
+
+	run.cfg.AdaptiveScheduling.Enabled = false
+	if due, err := NextDue(run.cfg, start); err != nil || !due.IsZero() {
+		t.Errorf("Expected no adaptive scan without adaptive scheduling, got %v, %v", due, err)
+	}
+}
@@ -0,0 +1,35 @@
+package detector
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/notifier"
+)
+
+// OpenTicket sends a drift to the configured ticket notifiers, e.g. a webhook that creates an
+// issue in the tracker. It returns the notifiers that accepted it.
+func OpenTicket(cfg *config.Config, alert notifier.DriftAlert, by string) ([]string, error) {
+	if cfg.Triage == nil || len(cfg.Triage.TicketNotifiers) == 0 {
+		return nil, fmt.Errorf("no ticket notifiers configured (set triage.ticket_notifiers)")
+	}
+	alert.Escalation = "Ticket requested"
+	if by != "" {
+		alert.Escalation += " by " + by
+	}
+
+	var sent []string
+	var result ProjectResult
+	for _, notifierName := range cfg.Triage.TicketNotifiers {
+		if err := deliver(cfg, notifierName, alert, &result); err != nil {
+			log.Printf("ERROR: Failed to open a ticket via '%s' for project '%s': %v", notifierName, alert.Project, err)
+			continue
+		}
+		sent = append(sent, notifierName)
+	}
+	if len(sent) == 0 {
+		return nil, fmt.Errorf("no ticket notifier accepted the drift of '%s'", alert.Project)
+	}
+	return sent, nil
+}
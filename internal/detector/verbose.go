@@ -0,0 +1,103 @@
+package detector
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/redact"
+	"github.com/terradrift-watcher/internal/signing"
+	"github.com/terradrift-watcher/internal/state"
+	"github.com/terradrift-watcher/internal/textutil"
+)
+
+// maxVerboseLineLength bounds each logged plan line, as log shippers reject huge events
+const maxVerboseLineLength = 4096
+
+// logFullPlan logs the whole plan output in verbose mode. Lines are logged in chunks with a
+// pause between them so log ingestion is not flooded, and lines past the configured bound are
+// saved in the state storage instead.
+func logFullPlan(cfg *config.Config, storage state.Storage, project string, planOutput string) {
+	maxLines, chunkLines, interval, err := cfg.VerboseLog.Limits()
+	if err != nil {
+		maxLines, chunkLines, interval = config.DefaultVerboseMaxLines, config.DefaultVerboseChunkLines, config.DefaultVerboseChunkInterval
+	}
+
+	lines := strings.Split(planOutput, "\n")
+	logged := lines
+	if len(logged) > maxLines {
+		logged = logged[:maxLines]
+	}
+
+	log.Printf("FULL TERRAFORM PLAN OUTPUT for '%s' (%d lines):", project, len(lines))
+	log.Println("=" + strings.Repeat("=", 79))
+	for start := 0; start < len(logged); start += chunkLines {
+		if start > 0 && interval > 0 {
+			time.Sleep(interval)
+		}
+		end := start + chunkLines
+		if end > len(logged) {
+			end = len(logged)
+		}
+		for _, line := range logged[start:end] {
+			if len(line) > maxVerboseLineLength {
+				line = textutil.Truncate(line, maxVerboseLineLength) + textutil.Ellipsis
+			}
+			log.Println(line)
+		}
+	}
+	log.Println("=" + strings.Repeat("=", 79))
+
+	if len(lines) <= maxLines {
+		return
+	}
+	rest := lines[maxLines:]
+	name, err := savePlanLog(cfg, storage, project, strings.Join(rest, "\n"))
+	if err != nil {
+		log.Printf("WARNING: %d more plan lines of '%s' not logged, and they could not be saved: %v", len(rest), project, err)
+		return
+	}
+	log.Printf("INFO: %d more plan lines of '%s' not logged, saved as %s in the state storage (print with 'terradrift-watcher state cat %s')",
+		len(rest), project, name, name)
+}
+
+// savePlanLog saves the plan lines that were not logged in the state storage, so they are
+// encrypted and kept like the plans themselves, signing them when signing is configured. It
+// returns the namespace and key they were saved under.
+func savePlanLog(cfg *config.Config, storage state.Storage, project string, content string) (string, error) {
+	key := state.LogKey(project, time.Now())
+	name := state.NamespacePlanLogs + "/" + key
+	// The log is redacted as it is written, so the saved lines must be too
+	data := []byte(redact.String(content) + "\n")
+	if err := storage.Put(state.NamespacePlanLogs, key, data); err != nil {
+		return "", fmt.Errorf("failed to save plan log: %w", err)
+	}
+	if cfg.Signing != nil {
+		if err := signObject(cfg, storage, state.NamespacePlanLogs, key, data); err != nil {
+			return name, fmt.Errorf("failed to sign plan log: %w", err)
+		}
+	}
+	return name, nil
+}
+
+// stateSubdir returns the default directory of artifacts kept next to the local state
+func stateSubdir(cfg *config.Config, name string) string {
+	stateDir := cfg.StateDir
+	if stateDir == "" {
+		stateDir = state.DefaultDir()
+	}
+	return filepath.Join(stateDir, name)
+}
+
+// signObject stores the detached signature of an object of the state storage next to it, under
+// its key with the signature extension
+func signObject(cfg *config.Config, storage state.Storage, namespace, key string, data []byte) error {
+	signature, err := signing.Sign(cfg.Signing.KeyFile, data)
+	if err != nil {
+		return err
+	}
+	return storage.Put(namespace, key+signing.SignatureExt, signature)
+}
@@ -0,0 +1,64 @@
+package detector
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/state"
+)
+
+func TestFullPlanRemainderIsEncrypted(t *testing.T) {
+	stateDir := t.TempDir()
+	storage, err := state.NewEncryptedStorage(state.NewFileStorage(stateDir), bytes.Repeat([]byte{7}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{VerboseLog: &config.VerboseLog{MaxLines: 2, ChunkInterval: "0s"}}
+
+	logFullPlan(cfg, storage, "network", "line 1\nline 2\n  ~ user_data = \"bootstrap-token-42\"")
+
+	keys, err := storage.List(state.NamespacePlanLogs)
+	if err != nil || len(keys) != 1 || !strings.HasPrefix(keys[0], "network-") {
+		t.Fatalf("Expected one saved plan log, got %v, %v", keys, err)
+	}
+	data, err := storage.Get(state.NamespacePlanLogs, keys[0])
+	if err != nil || !strings.Contains(string(data), "bootstrap-token-42") || strings.Contains(string(data), "line 1") {
+		t.Errorf("Expected the lines that were not logged, got %q, %v", data, err)
+	}
+	raw, err := os.ReadFile(filepath.Join(stateDir, state.NamespacePlanLogs, keys[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(raw), "bootstrap-token-42") {
+		t.Error("Expected the saved plan lines to be encrypted")
+	}
+}
+
+func TestScanPrunesSavedLogs(t *testing.T) {
+	run := newTestRun(t, "retention:\n  max_age: 30d\n", "network")
+	run.plan("network", cleanPlan)
+	storage := run.storage()
+	old, recent := state.LogKey("network", time.Now().AddDate(0, 0, -60)), state.LogKey("network", time.Now().AddDate(0, 0, -1))
+	for _, namespace := range []string{state.NamespacePlanLogs, state.NamespaceApplyLogs} {
+		for _, key := range []string{old, recent} {
+			if err := storage.Put(namespace, key, []byte("output")); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	run.scan(Options{})
+
+	// Saved plan and apply logs are pruned with the history after each run
+	for _, namespace := range []string{state.NamespacePlanLogs, state.NamespaceApplyLogs} {
+		keys, err := storage.List(namespace)
+		if err != nil || len(keys) != 1 || keys[0] != recent {
+			t.Errorf("Expected only %s kept in %s, got %v, %v", recent, namespace, keys, err)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package detector
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/notifier"
+	"github.com/terradrift-watcher/internal/state"
+)
+
+// closureNotifiers returns the notifiers told how the verification of an apply ended: the given
+// ones, plus the ticket notifiers when a ticket was requested for the incident so the tracker
+// can close or reopen it
+func closureNotifiers(cfg *config.Config, triage *state.Triage, project, incident string, notifiers []string) []string {
+	if cfg.Triage == nil || triage == nil || incident == "" {
+		return notifiers
+	}
+	if decision := triage.Decisions[project]; decision != nil && decision.Action == state.TriageTicket && decision.Incident == incident {
+		return mergeUnique(notifiers, cfg.Triage.TicketNotifiers)
+	}
+	return notifiers
+}
+
+// notifyVerified closes the incident of drift an apply remediated, telling the project's
+// notifiers the verification scan found no drift left. Unlike notifyResolved it is sent
+// whatever notify_resolved and the suppression windows say, as someone is waiting for it. It
+// runs before the drift is cleared from the project's state.
+func notifyVerified(cfg *config.Config, project config.Project, projectState *state.ProjectState, triage *state.Triage, verification string, result *ProjectResult) {
+	alert := notifier.DriftAlert{
+		Project: project.Name,
+		Summary: fmt.Sprintf("Remediation verified after %s: no drift left. Drift resolved after %v.",
+			verification, result.Remediated.Round(time.Minute)),
+		Tags:        project.Tags,
+		Fingerprint: projectState.Fingerprint,
+		DriftSince:  projectState.DriftSince,
+		Incident:    projectState.Incident,
+		Resolved:    true,
+		Description: project.Description,
+		Outputs:     result.Outputs,
+	}
+	notifiers := closureNotifiers(cfg, triage, project.Name, projectState.Incident, project.Notifiers)
+	for _, notifierName := range routeByHours(cfg, notifiers, time.Now()) {
+		if err := deliver(cfg, notifierName, alert, result); err != nil {
+			log.Printf("ERROR: Failed to send the closure of incident %s via '%s': %v",
+				projectState.Incident, notifierName, err)
+		} else {
+			log.Printf("INFO: Closure of incident %s sent via '%s' for project '%s'",
+				projectState.Incident, notifierName, project.Name)
+		}
+	}
+}
+
+// closeTriage drops the triage decisions settled by a verification scan, i.e. those about
+// drift that was resolved or changed. The decisions are read again so any made during the
+// scan are kept.
+func closeTriage(storage state.Storage, store *state.Store) {
+	triage, err := state.LoadTriage(storage)
+	if err != nil {
+		log.Printf("WARNING: Failed to close the verified triage decisions: %v", err)
+		return
+	}
+	for project, decision := range triage.Decisions {
+		if !decision.Applies(store.Projects[project]) {
+			log.Printf("INFO: Triage decision about '%s' (%s) closed by the verification", project, decision)
+		}
+	}
+	if err := state.SaveTriage(storage, triage, store); err != nil {
+		log.Printf("WARNING: Failed to close the verified triage decisions: %v", err)
+	}
+}
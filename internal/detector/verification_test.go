@@ -0,0 +1,134 @@
+package detector
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/state"
+	"github.com/terradrift-watcher/pkg/event"
+)
+
+// triageDrift records a triage decision about the current drift of a project
+func triageDrift(t *testing.T, run *testRun, project, action string) {
+	t.Helper()
+	storage := run.storage()
+	store, err := state.Load(storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ps := store.Project(project)
+	triage := &state.Triage{Decisions: map[string]*state.TriageDecision{
+		project: {Action: action, Incident: ps.Incident, Fingerprint: ps.Fingerprint, By: "bob", At: time.Now()},
+	}}
+	if err := state.SaveTriage(storage, triage, store); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestClosureNotifiers(t *testing.T) {
+	cfg := &config.Config{Triage: &config.Triage{TicketNotifiers: []string{"jira"}}}
+	triage := &state.Triage{Decisions: map[string]*state.TriageDecision{
+		"network": {Action: state.TriageTicket, Incident: "INC-1"},
+		"dns":     {Action: state.TriageAck, Incident: "INC-2"},
+	}}
+	tests := []struct {
+		name     string
+		cfg      *config.Config
+		project  string
+		incident string
+		want     []string
+	}{
+		{"ticket of the incident", cfg, "network", "INC-1", []string{"oncall", "jira"}},
+		{"ticket of an earlier incident", cfg, "network", "INC-0", []string{"oncall"}},
+		{"acknowledged", cfg, "dns", "INC-2", []string{"oncall"}},
+		{"no incident", cfg, "network", "", []string{"oncall"}},
+		{"no ticket notifiers", &config.Config{}, "network", "INC-1", []string{"oncall"}},
+	}
+	for _, tt := range tests {
+		got := closureNotifiers(tt.cfg, triage, tt.project, tt.incident, []string{"oncall"})
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestVerificationClosesIncident(t *testing.T) {
+	run := newTestRun(t, "triage:\n  ticket_notifiers: [tickets]\n", "network")
+	run.plan("network", driftPlan("public-read"))
+	run.scan(Options{})
+	incident := run.projectState("network").Incident
+	triageDrift(t, run, "network", state.TriageTicket)
+
+	// Without notify_resolved a clean scan is not announced, but a verified one is
+	run.plan("network", cleanPlan)
+	results := run.scan(Options{Verification: "remediate --approved by alice"})
+	if results["network"].Status != StatusClean {
+		t.Fatalf("Expected a clean scan, got %+v", results["network"])
+	}
+	for _, notifier := range []string{"oncall", "tickets"} {
+		sent := run.sent(notifier)
+		if len(sent) != 1 || sent[0].Type != event.TypeDriftResolved || sent[0].Incident != incident ||
+			!strings.Contains(sent[0].Summary, "Remediation verified after remediate --approved by alice") {
+			t.Errorf("Expected '%s' to be sent the closure of %s, got %+v", notifier, incident, sent)
+		}
+	}
+
+	if ps := run.projectState("network"); ps.Incident != "" {
+		t.Errorf("Expected the incident to be closed, got %s", ps.Incident)
+	}
+	triage, err := state.LoadTriage(run.storage())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(triage.Decisions) != 0 {
+		t.Errorf("Expected the ticket decision to be closed, got %+v", triage.Decisions)
+	}
+}
+
+func TestVerificationAlertsDriftLeft(t *testing.T) {
+	t.Run("held by an ack", func(t *testing.T) {
+		run := newTestRun(t, "triage:\n  ticket_notifiers: [tickets]\n", "network")
+		run.plan("network", driftPlan("public-read"))
+		run.scan(Options{})
+		triageDrift(t, run, "network", state.TriageAck)
+
+		// The ack holds the alerts of an ordinary scan
+		if run.scan(Options{}); len(run.sent("oncall")) != 0 {
+			t.Fatalf("Expected the ack to hold alerts, got %+v", run.sent("oncall"))
+		}
+
+		results := run.scan(Options{Verification: "CI pipeline #4521 by alice"})
+		if results["network"].Status != StatusDrifted {
+			t.Fatalf("Expected drift left, got %+v", results["network"])
+		}
+		sent := run.sent("oncall")
+		if len(sent) != 1 || sent[0].Type != event.TypeDriftEscalated ||
+			sent[0].Escalation != "Remediation not verified after CI pipeline #4521 by alice" {
+			t.Errorf("Expected the drift left to be alerted, got %+v", sent)
+		}
+		// Only a ticket of the incident is told the outcome
+		if sent := run.sent("tickets"); len(sent) != 0 {
+			t.Errorf("Expected no ticket notification without a ticket, got %+v", sent)
+		}
+	})
+
+	t.Run("unconfirmed new drift", func(t *testing.T) {
+		run := newTestRun(t, "confirm_after: \"3\"\n", "network")
+		run.plan("network", cleanPlan)
+		run.scan(Options{})
+
+		// The apply left different drift than before, which would wait for confirmation
+		run.plan("network", driftPlan("public-read-write"))
+		results := run.scan(Options{Verification: "manual apply by alice"})
+		if results["network"].Unconfirmed {
+			t.Errorf("Expected drift left after an apply to be confirmed, got %+v", results["network"])
+		}
+		sent := run.sent("oncall")
+		if len(sent) != 1 || sent[0].Type != event.TypeDriftEscalated || sent[0].Incident == "" {
+			t.Errorf("Expected the drift left to be alerted with an incident, got %+v", sent)
+		}
+	})
+}
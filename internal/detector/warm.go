@@ -0,0 +1,91 @@
+package detector
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/terraform"
+)
+
+// Warm-up outcomes
+const (
+	WarmOK      = "ok"
+	WarmFailed  = "failed"
+	WarmSkipped = "skipped"
+)
+
+// WarmResult holds the outcome of initializing one project
+type WarmResult struct {
+	Project  string
+	Status   string
+	Duration time.Duration
+	Err      error
+}
+
+// Warm runs terraform init for each enabled project without planning, one after another, so
+// providers and modules are downloaded ahead of the first scan and broken backends or provider
+// constraints show up early. cdktf projects are synthesized and each stack is initialized;
+// pulumi projects have nothing to initialize. Nothing is notified and state is not touched.
+func Warm(cfg *config.Config, project string) ([]WarmResult, error) {
+	var results []WarmResult
+	for _, p := range cfg.Projects {
+		if project != "" && p.Name != project {
+			continue
+		}
+		if project == "" && p.Enabled != nil && !*p.Enabled {
+			continue
+		}
+
+		result := WarmResult{Project: p.Name, Status: WarmOK}
+		if p.Type == config.ProjectTypePulumi {
+			result.Status = WarmSkipped
+			results = append(results, result)
+			continue
+		}
+
+		opts, err := projectOptions(cfg, p)
+		if err != nil {
+			return nil, fmt.Errorf("project '%s': %w", p.Name, err)
+		}
+
+		log.Printf("INFO: Initializing '%s'...", p.Name)
+		start := time.Now()
+		if p.Type == config.ProjectTypeCDKTF {
+			err = warmCDKTF(p, opts)
+		} else {
+			_, err = terraform.Init(p.Path, opts)
+		}
+		closeStream(opts)
+		result.Duration = time.Since(start)
+		if err != nil {
+			log.Printf("ERROR: Failed to initialize '%s': %v", p.Name, err)
+			result.Status = WarmFailed
+			result.Err = err
+		}
+		results = append(results, result)
+	}
+
+	if project != "" && len(results) == 0 {
+		return nil, fmt.Errorf("project '%s' not found in configuration", project)
+	}
+	return results, nil
+}
+
+// warmCDKTF synthesizes a cdktf project and initializes each of its stacks
+func warmCDKTF(project config.Project, opts terraform.Options) error {
+	if _, err := terraform.Synthesize(project.Path, opts); err != nil {
+		return err
+	}
+	stacks, err := terraform.SynthesizedStacks(project.Path, project.Stacks)
+	if err != nil {
+		return err
+	}
+	for _, stack := range stacks {
+		if _, err := terraform.Init(stack.Dir, opts); err != nil {
+			return fmt.Errorf("stack %s: %w", stack.Name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package detector
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/terradrift-watcher/internal/config"
+)
+
+// fakeInit puts a terraform script first on the PATH that fails in project directories
+// holding a file named "broken", and returns the log of the directories it initialized
+func fakeInit(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake terraform script requires a POSIX shell")
+	}
+	bin := t.TempDir()
+	initLog := filepath.Join(bin, "init.log")
+	script := "#!/bin/sh\nif [ -f broken ]; then echo 'Error configuring the backend'; exit 1; fi\n" +
+		"basename \"$PWD\" >> " + initLog + "\necho 'Terraform has been successfully initialized!'\n"
+	if err := os.WriteFile(filepath.Join(bin, "terraform"), []byte(script), 0700); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return initLog
+}
+
+func TestWarm(t *testing.T) {
+	initLog := fakeInit(t)
+	run := newTestRun(t, "", "network", "storage", "dns", "app", "queue")
+	disabled := false
+	for i := range run.cfg.Projects {
+		switch run.cfg.Projects[i].Name {
+		case "storage":
+			if err := os.WriteFile(filepath.Join(run.cfg.Projects[i].Path, "broken"), nil, 0644); err != nil {
+				t.Fatal(err)
+			}
+		case "dns":
+			run.cfg.Projects[i].Enabled = &disabled
+		case "app":
+			run.cfg.Projects[i].Type = config.ProjectTypePulumi
+		}
+	}
+
+	// A failed project is reported without stopping the projects after it, disabled projects
+	// are left out and pulumi projects have nothing to initialize
+	results, err := Warm(run.cfg, "")
+	if err != nil {
+		t.Fatalf("Warm failed: %v", err)
+	}
+	expected := map[string]string{"network": WarmOK, "storage": WarmFailed, "app": WarmSkipped, "queue": WarmOK}
+	if len(results) != len(expected) {
+		t.Fatalf("Expected %d results, got %+v", len(expected), results)
+	}
+	for _, result := range results {
+		if result.Status != expected[result.Project] {
+			t.Errorf("Expected '%s' %s, got %s", result.Project, expected[result.Project], result.Status)
+		}
+		if (result.Status == WarmFailed) != (result.Err != nil) {
+			t.Errorf("Expected an error only for the failed project, got %v for '%s'", result.Err, result.Project)
+		}
+	}
+	if data, err := os.ReadFile(initLog); err != nil || string(data) != "network\nqueue\n" {
+		t.Errorf("Expected network and queue initialized, got %q, %v", data, err)
+	}
+
+	// A project named explicitly is initialized even when disabled
+	results, err = Warm(run.cfg, "dns")
+	if err != nil || len(results) != 1 || results[0].Project != "dns" || results[0].Status != WarmOK {
+		t.Errorf("Expected only dns initialized, got %+v, %v", results, err)
+	}
+	if _, err := Warm(run.cfg, "missing"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Expected an unknown project to be refused, got %v", err)
+	}
+}
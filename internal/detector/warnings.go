@@ -0,0 +1,80 @@
+package detector
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/notifier"
+	"github.com/terradrift-watcher/internal/redact"
+	"github.com/terradrift-watcher/internal/state"
+	"github.com/terradrift-watcher/internal/terraform"
+)
+
+// recordWarnings keeps the warnings of a plan in the result and the project's state, and
+// alerts the warnings notifiers about those the previous plan did not print
+func recordWarnings(cfg *config.Config, project config.Project, projectState *state.ProjectState, planOutput string, result *ProjectResult) {
+	warnings := terraform.ParseWarnings(planOutput)
+	for i := range warnings {
+		warnings[i].Detail = redact.String(warnings[i].Detail)
+	}
+
+	known := make(map[string]bool)
+	for _, w := range projectState.Warnings {
+		known[w.Key()] = true
+	}
+	var fresh []terraform.Warning
+	for _, w := range warnings {
+		if !known[w.Key()] {
+			fresh = append(fresh, w)
+		}
+	}
+	result.Warnings = warnings
+	projectState.Warnings = warnings
+
+	if len(warnings) > 0 {
+		log.Printf("INFO: Terraform printed %d warning(s) in '%s', %d new", len(warnings), project.Name, len(fresh))
+	}
+	for _, w := range fresh {
+		log.Printf("WARNING: New terraform warning in '%s': %s", project.Name, w)
+	}
+	if len(fresh) > 0 && cfg.Warnings != nil {
+		notifyWarnings(cfg, project, planOutput, fresh, result)
+	}
+}
+
+// notifyWarnings alerts the warnings notifiers about new terraform warnings. Like drift alerts
+// they respect suppression windows and business hours.
+func notifyWarnings(cfg *config.Config, project config.Project, output string, warnings []terraform.Warning, result *ProjectResult) {
+	if window, until := cfg.SuppressedBy(project, time.Now()); window != nil {
+		log.Printf("INFO: Warning alerts for '%s' suppressed by window '%s' until %s",
+			project.Name, window.Name, until.Format(time.RFC3339))
+		result.Suppressed = window.Name
+		return
+	}
+
+	lines := make([]string, len(warnings))
+	for i, w := range warnings {
+		lines[i] = "- " + w.String()
+	}
+	alert := notifier.DriftAlert{
+		Project:     project.Name,
+		Summary:     fmt.Sprintf("%d new terraform warning(s):\n%s", len(warnings), strings.Join(lines, "\n")),
+		PlanOutput:  output,
+		Tags:        project.Tags,
+		Description: project.Description,
+		RunbookURL:  project.RunbookURL,
+		Outputs:     result.Outputs,
+		Warnings:    warnings,
+	}.Redacted()
+
+	for _, notifierName := range routeByHours(cfg, cfg.Warnings.Notifiers, time.Now()) {
+		if err := deliver(cfg, notifierName, alert, result); err != nil {
+			log.Printf("ERROR: Failed to send warning alert via '%s' for project '%s': %v", notifierName, project.Name, err)
+		} else {
+			log.Printf("INFO: Warning alert sent via '%s' for project '%s'", notifierName, project.Name)
+		}
+	}
+}
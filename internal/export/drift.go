@@ -0,0 +1,136 @@
+package export
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/state"
+)
+
+// BundleRoot is the path of the drift document inside an OPA bundle, so policies read it as
+// data.terradrift
+const BundleRoot = "terradrift"
+
+// DriftData is the current drift state of the fleet, as published for policy engines
+type DriftData struct {
+	GeneratedAt time.Time               `json:"generated_at"`
+	Metadata    map[string]string       `json:"metadata,omitempty"`
+	Drifted     []string                `json:"drifted"` // Names of the currently drifted projects
+	Projects    map[string]ProjectDrift `json:"projects"`
+}
+
+// ProjectDrift is the drift state of one project
+type ProjectDrift struct {
+	// Drifted is set from the first drifted scan until a clean one, so a failed scan of a
+	// drifted project keeps it drifted
+	Drifted     bool       `json:"drifted"`
+	Status      string     `json:"status"` // Last scan status, "disabled" or "never_scanned"
+	Unconfirmed bool       `json:"unconfirmed,omitempty"`
+	Flapping    bool       `json:"flapping,omitempty"`
+	DriftSince  *time.Time `json:"drift_since,omitempty"`
+	LastScanned *time.Time `json:"last_scanned,omitempty"`
+	Fingerprint string     `json:"fingerprint,omitempty"`
+	Tags        []string   `json:"tags,omitempty"`
+}
+
+// BuildDriftData describes the drift state of every configured project from the stored state
+func BuildDriftData(cfg *config.Config, store *state.Store, now time.Time) DriftData {
+	data := DriftData{
+		GeneratedAt: now.UTC(),
+		Metadata:    cfg.Metadata,
+		Drifted:     []string{},
+		Projects:    make(map[string]ProjectDrift, len(cfg.Projects)),
+	}
+	for _, project := range cfg.Projects {
+		ps := store.Project(project.Name)
+		drift := ProjectDrift{
+			Drifted:     !ps.DriftSince.IsZero(),
+			Status:      ps.LastStatus,
+			Unconfirmed: ps.Unconfirmed(),
+			Flapping:    ps.Flapping,
+			DriftSince:  timestamp(ps.DriftSince),
+			LastScanned: timestamp(ps.LastScanned),
+			Fingerprint: ps.Fingerprint,
+			Tags:        project.Tags,
+		}
+		if project.Enabled != nil && !*project.Enabled {
+			drift.Status = "disabled"
+		} else if drift.Status == "" {
+			drift.Status = "never_scanned"
+		}
+		if drift.Drifted {
+			data.Drifted = append(data.Drifted, project.Name)
+		}
+		data.Projects[project.Name] = drift
+	}
+	sort.Strings(data.Drifted)
+	return data
+}
+
+// timestamp returns nil for the zero time, so unknown times are left out of the document
+func timestamp(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
+
+// Encode renders the drift data in the given format: the JSON document itself, or an OPA
+// bundle holding it as terradrift/data.json with a manifest claiming the terradrift root
+func Encode(data DriftData, format string) ([]byte, error) {
+	document, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode drift data: %w", err)
+	}
+	if format != config.ExportBundle {
+		return append(document, '\n'), nil
+	}
+
+	manifest, err := json.Marshal(map[string]interface{}{
+		"revision": data.GeneratedAt.Format(time.RFC3339),
+		"roots":    []string{BundleRoot},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode bundle manifest: %w", err)
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	files := []struct {
+		name string
+		data []byte
+	}{
+		{".manifest", manifest},
+		{BundleRoot + "/data.json", document},
+	}
+	for _, file := range files {
+		header := &tar.Header{
+			Name:     file.name,
+			Mode:     0644,
+			Size:     int64(len(file.data)),
+			ModTime:  data.GeneratedAt,
+			Typeflag: tar.TypeReg,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return nil, fmt.Errorf("failed to write bundle: %w", err)
+		}
+		if _, err := tw.Write(file.data); err != nil {
+			return nil, fmt.Errorf("failed to write bundle: %w", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write bundle: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write bundle: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,90 @@
+package export
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/state"
+)
+
+func TestBuildDriftData(t *testing.T) {
+	store, err := state.Load(state.NewFileStorage(t.TempDir()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	store.Project("network").RecordScan("clean", now)
+	db := store.Project("db")
+	db.RecordScan("error", now)
+	db.MarkDrifted(now.Add(-time.Hour))
+	db.Fingerprint = "abc123"
+
+	disabled := false
+	cfg := &config.Config{Projects: []config.Project{
+		{Name: "network", Tags: []string{"prod"}},
+		{Name: "db"},
+		{Name: "legacy", Enabled: &disabled},
+		{Name: "new"},
+	}}
+	data := BuildDriftData(cfg, store, now)
+
+	if len(data.Drifted) != 1 || data.Drifted[0] != "db" {
+		t.Fatalf("Expected only db to be drifted, got %v", data.Drifted)
+	}
+	// A failed scan keeps the project drifted until it scans clean
+	if p := data.Projects["db"]; !p.Drifted || p.Status != "error" || p.Fingerprint != "abc123" || p.DriftSince == nil {
+		t.Errorf("Unexpected drift state of db: %+v", p)
+	}
+	if p := data.Projects["network"]; p.Drifted || p.Status != "clean" || p.DriftSince != nil || len(p.Tags) != 1 {
+		t.Errorf("Unexpected drift state of network: %+v", p)
+	}
+	if data.Projects["legacy"].Status != "disabled" || data.Projects["new"].Status != "never_scanned" {
+		t.Errorf("Unexpected statuses: %+v", data.Projects)
+	}
+}
+
+func TestEncodeBundle(t *testing.T) {
+	data := DriftData{
+		GeneratedAt: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		Drifted:     []string{"db"},
+		Projects:    map[string]ProjectDrift{"db": {Drifted: true, Status: "drifted"}},
+	}
+	encoded, err := Encode(data, config.ExportBundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(encoded))
+	if err != nil {
+		t.Fatalf("Expected a gzipped bundle: %v", err)
+	}
+	files := make(map[string][]byte)
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[header.Name], _ = io.ReadAll(tr)
+	}
+
+	var manifest struct {
+		Roots []string `json:"roots"`
+	}
+	if err := json.Unmarshal(files[".manifest"], &manifest); err != nil || len(manifest.Roots) != 1 || manifest.Roots[0] != BundleRoot {
+		t.Errorf("Expected a manifest claiming the %s root, got %s", BundleRoot, files[".manifest"])
+	}
+	var document DriftData
+	if err := json.Unmarshal(files["terradrift/data.json"], &document); err != nil || !document.Projects["db"].Drifted {
+		t.Errorf("Expected the drift document in the bundle, got %s", files["terradrift/data.json"])
+	}
+}
@@ -0,0 +1,101 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/state"
+)
+
+// UploadTimeout bounds each HTTP upload
+const UploadTimeout = 30 * time.Second
+
+// IsLocal reports whether the destination is a file path rather than a URL or bucket
+func IsLocal(destination string) bool {
+	u, err := url.Parse(destination)
+	return err != nil || (u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "s3")
+}
+
+// Describe returns the destination without credentials or query parameters, for logs
+func Describe(destination string) string {
+	if IsLocal(destination) {
+		return destination
+	}
+	u, _ := url.Parse(destination)
+	return u.Scheme + "://" + u.Host + u.Path
+}
+
+// Publish writes the encoded drift data to the drift_export destination of the configuration:
+// a file (replaced atomically), an http(s) URL (HTTP PUT with the given client, or a default
+// one when nil) or an s3://bucket/key object
+func Publish(cfg *config.Config, client *http.Client, data []byte) error {
+	export := *cfg.DriftExport
+	if IsLocal(export.Destination) {
+		return writeFile(export.Destination, data)
+	}
+
+	u, _ := url.Parse(export.Destination)
+	if u.Scheme == "s3" {
+		// The key's directory stands in for the storage namespace, so the object lands at
+		// exactly the given key
+		key := strings.Trim(u.Path, "/")
+		storage := state.NewS3Storage(export.S3Storage(u.Host, cfg.Storage))
+		return storage.Put(path.Dir(key), path.Base(key), data)
+	}
+	return upload(export, client, data)
+}
+
+// writeFile replaces a file atomically, so readers never see a partial document
+func writeFile(filePath string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return fmt.Errorf("failed to create export directory: %w", err)
+	}
+	tmpPath := filePath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write drift export: %w", err)
+	}
+	if err := os.Rename(tmpPath, filePath); err != nil {
+		return fmt.Errorf("failed to replace drift export: %w", err)
+	}
+	return nil
+}
+
+// upload sends the document with an HTTP PUT, with the export's headers
+func upload(export config.DriftExport, client *http.Client, data []byte) error {
+	req, err := http.NewRequest(http.MethodPut, export.Destination, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create upload request: %w", err)
+	}
+	contentType := "application/json"
+	if export.Format == config.ExportBundle {
+		contentType = "application/gzip"
+	}
+	req.Header.Set("Content-Type", contentType)
+	for name, value := range export.Headers {
+		req.Header.Set(name, value)
+	}
+
+	if client == nil {
+		client = &http.Client{Timeout: UploadTimeout}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to upload drift export: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("failed to upload drift export: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package gitutil
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// worktreeMu serializes worktree operations, which take a lock on the repository
+var worktreeMu sync.Mutex
+
+// run executes a git command in dir and returns its trimmed stdout
+func run(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %s failed: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// TopLevel returns the root directory of the git repository containing path
+func TopLevel(path string) (string, error) {
+	return run(path, "rev-parse", "--show-toplevel")
+}
+
+// CheckoutRef checks out ref of the repository containing projectPath into a temporary
+// worktree and returns the project's directory inside it, plus a cleanup function that
+// removes the worktree again.
+func CheckoutRef(projectPath string, ref string) (string, func(), error) {
+	top, err := TopLevel(projectPath)
+	if err != nil {
+		return "", nil, fmt.Errorf("project is not in a git repository: %w", err)
+	}
+
+	absProject, err := filepath.Abs(projectPath)
+	if err != nil {
+		return "", nil, err
+	}
+	// Resolve symlinks on both sides so the relative path is computed correctly
+	if resolved, err := filepath.EvalSymlinks(absProject); err == nil {
+		absProject = resolved
+	}
+	if resolved, err := filepath.EvalSymlinks(top); err == nil {
+		top = resolved
+	}
+	rel, err := filepath.Rel(top, absProject)
+	if err != nil {
+		return "", nil, err
+	}
+
+	tmpDir, err := os.MkdirTemp("", "terradrift-ref-")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create worktree directory: %w", err)
+	}
+	worktree := filepath.Join(tmpDir, "worktree")
+
+	worktreeMu.Lock()
+	_, err = run(top, "worktree", "add", "--detach", worktree, ref)
+	worktreeMu.Unlock()
+	if err != nil {
+		os.RemoveAll(tmpDir)
+		return "", nil, fmt.Errorf("failed to check out %s: %w", ref, err)
+	}
+
+	cleanup := func() {
+		worktreeMu.Lock()
+		defer worktreeMu.Unlock()
+		if _, err := run(top, "worktree", "remove", "--force", worktree); err != nil {
+			fmt.Printf("WARNING: Failed to remove worktree %s: %v\n", worktree, err)
+		}
+		os.RemoveAll(tmpDir)
+	}
+
+	return filepath.Join(worktree, rel), cleanup, nil
+}
+
+// UncommittedTerraformFiles returns `git status --porcelain` lines for terraform source
+// files under path that differ from HEAD. It returns nil when path is not in a git repository.
+func UncommittedTerraformFiles(path string) ([]string, error) {
+	if _, err := TopLevel(path); err != nil {
+		return nil, nil
+	}
+
+	output, err := run(path, "status", "--porcelain", "--untracked-files=all", "--", ".")
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, line := range strings.Split(output, "\n") {
+		name := strings.TrimSpace(line)
+		if len(name) < 3 {
+			continue
+		}
+		name = strings.TrimSpace(name[2:])
+		if strings.HasSuffix(name, ".tf") || strings.HasSuffix(name, ".tf.json") ||
+			strings.HasSuffix(name, ".tfvars") || strings.HasSuffix(name, ".tfvars.json") {
+			files = append(files, strings.TrimSpace(line))
+		}
+	}
+	return files, nil
+}
+
+// ChangedFiles returns the absolute paths of files in the repository containing path that
+// changed since the branch point with ref, including uncommitted changes to tracked files.
+// It also returns the repository root the paths are under.
+func ChangedFiles(path string, ref string) (string, []string, error) {
+	top, err := TopLevel(path)
+	if err != nil {
+		return "", nil, fmt.Errorf("not in a git repository: %w", err)
+	}
+
+	// Compare against the merge base so changes made on ref after branching are not counted
+	base, err := run(top, "merge-base", ref, "HEAD")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to find merge base with %s: %w", ref, err)
+	}
+	output, err := run(top, "diff", "--name-only", "--no-renames", base)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var files []string
+	for _, name := range strings.Split(output, "\n") {
+		if name = strings.TrimSpace(name); name != "" {
+			files = append(files, filepath.Join(top, filepath.FromSlash(name)))
+		}
+	}
+	return top, files, nil
+}
@@ -0,0 +1,159 @@
+package gitutil
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newRepo creates a git repository with a project directory committed twice: main.tf holds
+// "v1" at the tag v1 and "v2" at HEAD
+func newRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	repo := t.TempDir()
+	git := func(args ...string) string {
+		t.Helper()
+		output, err := run(repo, args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return output
+	}
+	git("init", "--quiet")
+	git("config", "user.email", "watcher@example.com")
+	git("config", "user.name", "watcher")
+	git("config", "commit.gpgsign", "false")
+	for _, version := range []string{"v1", "v2"} {
+		writeFile(t, filepath.Join(repo, "network", "main.tf"), version)
+		git("add", "-A")
+		git("commit", "--quiet", "-m", version)
+		if version == "v1" {
+			git("tag", "v1")
+		}
+	}
+	return repo
+}
+
+// writeFile writes content to path, creating its directory
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCheckoutRef(t *testing.T) {
+	repo := newRepo(t)
+	project := filepath.Join(repo, "network")
+	head, err := run(repo, "rev-parse", "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		ref      string
+		expected string // Content of main.tf in the checkout, empty when the ref is invalid
+	}{
+		{"tag", "v1", "v1"},
+		{"commit", head, "v2"},
+		{"relative ref", "HEAD~1", "v1"},
+		{"invalid ref", "no-such-ref", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, cleanup, err := CheckoutRef(project, tt.ref)
+			if tt.expected == "" {
+				if err == nil {
+					cleanup()
+					t.Fatalf("Expected an error for %s, got a checkout in %s", tt.ref, dir)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("CheckoutRef failed: %v", err)
+				}
+				data, err := os.ReadFile(filepath.Join(dir, "main.tf"))
+				if err != nil || string(data) != tt.expected {
+					t.Errorf("Expected main.tf %q in the checkout, got %q, %v", tt.expected, data, err)
+				}
+				cleanup()
+				if _, err := os.Stat(dir); !os.IsNotExist(err) {
+					t.Errorf("Expected the worktree to be removed, got %v", err)
+				}
+			}
+
+			// The project's own checkout stays at HEAD with its files untouched
+			if current, err := run(repo, "rev-parse", "HEAD"); err != nil || current != head {
+				t.Errorf("Expected HEAD to stay at %s, got %s, %v", head, current, err)
+			}
+			if data, err := os.ReadFile(filepath.Join(project, "main.tf")); err != nil || string(data) != "v2" {
+				t.Errorf("Expected the project's main.tf untouched, got %q, %v", data, err)
+			}
+			if worktrees, err := run(repo, "worktree", "list", "--porcelain"); err != nil || countLines(worktrees, "worktree ") != 1 {
+				t.Errorf("Expected only the main worktree left, got:\n%s (%v)", worktrees, err)
+			}
+		})
+	}
+}
+
+// countLines counts the lines of output starting with prefix
+func countLines(output, prefix string) int {
+	count := 0
+	for _, line := range strings.Split(output, "\n") {
+		if strings.HasPrefix(line, prefix) {
+			count++
+		}
+	}
+	return count
+}
+
+func TestUncommittedTerraformFiles(t *testing.T) {
+	tests := []struct {
+		name     string
+		change   func(project string)
+		expected []string
+	}{
+		{"clean", func(string) {}, nil},
+		{"modified .tf", func(project string) {
+			writeFile(t, filepath.Join(project, "main.tf"), "v3")
+		}, []string{"M network/main.tf"}},
+		{"untracked .tf", func(project string) {
+			writeFile(t, filepath.Join(project, "modules", "vpc", "vpc.tf"), "new")
+		}, []string{"?? network/modules/vpc/vpc.tf"}},
+		{"untracked .tfvars", func(project string) {
+			writeFile(t, filepath.Join(project, "prod.tfvars"), "new")
+		}, []string{"?? network/prod.tfvars"}},
+		// Only terraform sources block a scan; notes, lock files and other projects do not
+		{"non-terraform changes", func(project string) {
+			writeFile(t, filepath.Join(project, "README.md"), "notes")
+			writeFile(t, filepath.Join(project, ".terraform.lock.hcl"), "lock")
+			writeFile(t, filepath.Join(filepath.Dir(project), "storage", "main.tf"), "other project")
+		}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			project := filepath.Join(newRepo(t), "network")
+			tt.change(project)
+			files, err := UncommittedTerraformFiles(project)
+			if err != nil {
+				t.Fatalf("UncommittedTerraformFiles failed: %v", err)
+			}
+			if strings.Join(files, "\n") != strings.Join(tt.expected, "\n") {
+				t.Errorf("Expected %q, got %q", tt.expected, files)
+			}
+		})
+	}
+
+	// Projects outside a git repository have nothing to compare against
+	if files, err := UncommittedTerraformFiles(t.TempDir()); err != nil || files != nil {
+		t.Errorf("Expected nothing outside a repository, got %q, %v", files, err)
+	}
+}
@@ -39,8 +39,8 @@ func (fl *FileLock) Acquire() error {
 		}
 	}
 
-	// Try to create the lock file exclusively
-	file, err := os.OpenFile(fl.lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	// Try to create the lock file exclusively; it records our PID, so keep it private
+	file, err := os.OpenFile(fl.lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
 	if err != nil {
 		if os.IsExist(err) {
 			return fmt.Errorf("another instance is already running (lock file: %s)", fl.lockPath)
@@ -57,6 +57,11 @@ func (fl *FileLock) Acquire() error {
 	return nil
 }
 
+// Dir returns the directory holding the lock file
+func (fl *FileLock) Dir() string {
+	return filepath.Dir(fl.lockPath)
+}
+
 // Release releases the lock
 func (fl *FileLock) Release() error {
 	if fl.file != nil {
@@ -0,0 +1,189 @@
+package metrics
+
+import (
+	"sort"
+
+	"github.com/terradrift-watcher/internal/detector"
+	"github.com/terradrift-watcher/internal/report"
+)
+
+// statusValues maps project statuses to the value of terradrift_project_status
+var statusValues = map[string]float64{
+	detector.StatusClean:   0,
+	detector.StatusDrifted: 1,
+	detector.StatusError:   2,
+
+	detector.StatusVersionMismatch: 3,
+	detector.StatusNoise:           4,
+}
+
+// RunFamilies builds the metrics for a finished run. The summary covers the recent history
+// window used for remediation figures.
+func RunFamilies(run *detector.Report, summary *report.Summary) []Family {
+	status := Family{
+		Name: "terradrift_project_status",
+		Help: "Result of the last scan of each project (0 = clean, 1 = drifted, 2 = error, 3 = version mismatch, 4 = noise).",
+	}
+	duration := Family{
+		Name: "terradrift_project_scan_duration_seconds",
+		Help: "Duration of the last scan of each project.",
+	}
+	versionMismatch := Family{
+		Name: "terradrift_project_version_mismatch",
+		Help: "Set to 1 for each project whose state was written by a newer Terraform, labeled with both versions.",
+	}
+	scanError := Family{
+		Name: "terradrift_project_scan_error",
+		Help: "Set to 1 for each project whose last scan failed, labeled with the cause (auth, backend, provider, syntax, timeout, lock, version, unknown).",
+	}
+	for _, result := range run.Results {
+		value, ok := statusValues[result.Status]
+		if !ok {
+			continue
+		}
+		labels := map[string]string{"project": result.Project}
+		status.Samples = append(status.Samples, Gauge{Labels: labels, Value: value})
+		duration.Samples = append(duration.Samples, Gauge{Labels: labels, Value: result.Duration.Seconds()})
+		if result.Failed() {
+			scanError.Samples = append(scanError.Samples, Gauge{
+				Labels: map[string]string{"project": result.Project, "category": result.ErrCategory},
+				Value:  1,
+			})
+		}
+		if result.Status == detector.StatusVersionMismatch {
+			versionMismatch.Samples = append(versionMismatch.Samples, Gauge{
+				Labels: map[string]string{
+					"project":       result.Project,
+					"state_version": result.StateVersion,
+					"local_version": result.LocalVersion,
+				},
+				Value: 1,
+			})
+		}
+	}
+
+	// Which resource classes drift most across the estate
+	driftResources := Family{
+		Name: "terradrift_drift_resources",
+		Help: "Number of drifted resources in the last run per resource type and planned action (create, update, delete, replace).",
+	}
+	counts := make(map[[2]string]int)
+	var keys [][2]string
+	for _, result := range run.Results {
+		if result.Status != detector.StatusDrifted {
+			continue
+		}
+		for _, resource := range result.Resources {
+			key := [2]string{resource.Type, resource.Action}
+			if counts[key] == 0 {
+				keys = append(keys, key)
+			}
+			counts[key]++
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] != keys[j][0] {
+			return keys[i][0] < keys[j][0]
+		}
+		return keys[i][1] < keys[j][1]
+	})
+	for _, key := range keys {
+		driftResources.Samples = append(driftResources.Samples, Gauge{
+			Labels: map[string]string{"type": key[0], "action": key[1]},
+			Value:  float64(counts[key]),
+		})
+	}
+
+	projectMTTR := Family{
+		Name: "terradrift_project_mttr_seconds",
+		Help: "Mean time to remediation of drift per project over the history window.",
+	}
+	for _, stats := range summary.Projects {
+		if stats.ResolvedDrifts > 0 {
+			projectMTTR.Samples = append(projectMTTR.Samples, Gauge{
+				Labels: map[string]string{"project": stats.Project},
+				Value:  stats.MTTR.Seconds(),
+			})
+		}
+	}
+
+	projectHealth := Family{
+		Name: "terradrift_project_health_score",
+		Help: "Health score (0-100) per project from drift and error rates, time since the last clean scan and MTTR over the history window.",
+	}
+	for _, stats := range summary.Projects {
+		projectHealth.Samples = append(projectHealth.Samples, Gauge{
+			Labels: map[string]string{"project": stats.Project},
+			Value:  float64(stats.Score),
+		})
+	}
+
+	// Share of the fleet drift-free, for leadership dashboards
+	fleetHealth := Family{
+		Name: "terradrift_fleet_drift_free_ratio",
+		Help: "Share of projects (0-1) whose latest scan in the history window was clean, among those clean or drifted.",
+	}
+	if summary.Health.Projects > 0 {
+		fleetHealth.Samples = []Gauge{{Value: summary.Health.Percent() / 100}}
+	}
+	tagHealth := Family{
+		Name: "terradrift_tag_drift_free_ratio",
+		Help: "Share of projects (0-1) with a tag whose latest scan in the history window was clean.",
+	}
+	for _, h := range summary.HealthByTag {
+		if h.Projects > 0 {
+			tagHealth.Samples = append(tagHealth.Samples, Gauge{
+				Labels: map[string]string{"tag": h.Name},
+				Value:  h.Percent() / 100,
+			})
+		}
+	}
+
+	sloCompliance := Family{
+		Name: "terradrift_slo_compliance_ratio",
+		Help: "Share of drift episodes (0-1) resolved within each SLO over the history window.",
+	}
+	sloBreaching := Family{
+		Name: "terradrift_slo_breaching_projects",
+		Help: "Number of projects whose open drift is older than each SLO allows.",
+	}
+	for _, slo := range summary.SLOs {
+		labels := map[string]string{"slo": slo.Name}
+		sloCompliance.Samples = append(sloCompliance.Samples, Gauge{Labels: labels, Value: slo.Percent() / 100})
+		sloBreaching.Samples = append(sloBreaching.Samples, Gauge{Labels: labels, Value: float64(len(slo.Breaching))})
+	}
+
+	return []Family{
+		status,
+		duration,
+		scanError,
+		versionMismatch,
+		driftResources,
+		projectMTTR,
+		projectHealth,
+		fleetHealth,
+		tagHealth,
+		sloCompliance,
+		sloBreaching,
+		{
+			Name:    "terradrift_fleet_mttr_seconds",
+			Help:    "Mean time to remediation of drift across all projects over the history window.",
+			Samples: []Gauge{{Value: summary.MTTR.Seconds()}},
+		},
+		{
+			Name:    "terradrift_drifted_projects",
+			Help:    "Number of projects drifted in the last run.",
+			Samples: []Gauge{{Value: float64(run.Count(detector.StatusDrifted))}},
+		},
+		{
+			Name:    "terradrift_run_duration_seconds",
+			Help:    "Duration of the last run.",
+			Samples: []Gauge{{Value: run.FinishedAt.Sub(run.StartedAt).Seconds()}},
+		},
+		{
+			Name:    "terradrift_last_run_timestamp_seconds",
+			Help:    "Unix time the last run finished.",
+			Samples: []Gauge{{Value: float64(run.FinishedAt.Unix())}},
+		},
+	}
+}
@@ -0,0 +1,46 @@
+package metrics
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/terradrift-watcher/internal/detector"
+	"github.com/terradrift-watcher/internal/report"
+	"github.com/terradrift-watcher/internal/terraform"
+)
+
+func TestRunFamiliesDriftResources(t *testing.T) {
+	run := &detector.Report{Results: []detector.ProjectResult{
+		{Project: "network", Status: detector.StatusDrifted, Resources: []terraform.ResourceChange{
+			{Address: "aws_security_group.web", Type: "aws_security_group", Action: terraform.ActionUpdate},
+			{Address: "aws_security_group.db", Type: "aws_security_group", Action: terraform.ActionUpdate},
+			{Address: "aws_instance.bastion", Type: "aws_instance", Action: terraform.ActionReplace},
+		}},
+		{Project: "database", Status: detector.StatusDrifted, Resources: []terraform.ResourceChange{
+			{Address: "aws_security_group.rds", Type: "aws_security_group", Action: terraform.ActionUpdate},
+		}},
+		// Only drifted projects count, whatever resources an earlier analysis left behind
+		{Project: "dns", Status: detector.StatusError, Resources: []terraform.ResourceChange{
+			{Address: "aws_route53_record.www", Type: "aws_route53_record", Action: terraform.ActionDelete},
+		}},
+	}}
+
+	var family *Family
+	families := RunFamilies(run, &report.Summary{})
+	for i := range families {
+		if families[i].Name == "terradrift_drift_resources" {
+			family = &families[i]
+		}
+	}
+	if family == nil {
+		t.Fatal("Expected the terradrift_drift_resources family")
+	}
+
+	expected := []Gauge{
+		{Labels: map[string]string{"type": "aws_instance", "action": "replace"}, Value: 1},
+		{Labels: map[string]string{"type": "aws_security_group", "action": "update"}, Value: 3},
+	}
+	if !reflect.DeepEqual(family.Samples, expected) {
+		t.Errorf("Expected counts per type and action sorted by type, got %+v", family.Samples)
+	}
+}
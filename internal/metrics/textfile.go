@@ -0,0 +1,87 @@
+package metrics
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Gauge is a single Prometheus gauge sample
+type Gauge struct {
+	Labels map[string]string
+	Value  float64
+}
+
+// Family groups samples of one metric with its help text
+type Family struct {
+	Name    string
+	Help    string
+	Samples []Gauge
+}
+
+// WriteTextfile writes metric families in the Prometheus text exposition format, suitable
+// for the node_exporter textfile collector. The file is replaced atomically.
+func WriteTextfile(path string, families []Family) error {
+	var b strings.Builder
+	for _, family := range families {
+		fmt.Fprintf(&b, "# HELP %s %s\n", family.Name, family.Help)
+		fmt.Fprintf(&b, "# TYPE %s gauge\n", family.Name)
+		for _, sample := range family.Samples {
+			fmt.Fprintf(&b, "%s%s %g\n", family.Name, formatLabels(sample.Labels), sample.Value)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create metrics directory: %w", err)
+	}
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, []byte(b.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write metrics file: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to replace metrics file: %w", err)
+	}
+	return nil
+}
+
+// WithLabels adds the labels to every sample, e.g. the watcher's metadata, so metrics from
+// several watchers can be told apart. Labels a sample already has are kept.
+func WithLabels(families []Family, labels map[string]string) []Family {
+	if len(labels) == 0 {
+		return families
+	}
+	for i := range families {
+		for j := range families[i].Samples {
+			merged := make(map[string]string, len(labels)+len(families[i].Samples[j].Labels))
+			for key, value := range labels {
+				merged[key] = value
+			}
+			for key, value := range families[i].Samples[j].Labels {
+				merged[key] = value
+			}
+			families[i].Samples[j].Labels = merged
+		}
+	}
+	return families
+}
+
+// formatLabels renders a label set in stable order
+func formatLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, fmt.Sprintf("%s=%q", key, labels[key]))
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
@@ -0,0 +1,162 @@
+package notifier
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/terradrift-watcher/internal/redact"
+	"github.com/terradrift-watcher/internal/terraform"
+)
+
+// DriftAlert carries everything a notifier needs to report drift in a project.
+// It is also persisted in the outbox when delivery fails.
+type DriftAlert struct {
+	Project    string `json:"project"`
+	Summary    string `json:"summary"`
+	PlanOutput string `json:"plan_output,omitempty"`
+
+	// Owners lists the teams owning the drifted resources
+	Owners []string `json:"owners,omitempty"`
+	Tags   []string `json:"tags,omitempty"`
+
+	// Changes is the changelog of out-of-band attribute changes
+	Changes []terraform.AttributeChange `json:"changes,omitempty"`
+
+	// Fingerprint identifies the drift so it can be deduplicated across notifiers and runs
+	Fingerprint string `json:"fingerprint,omitempty"`
+
+	// DriftSince is when the current continuous drift was first detected
+	DriftSince time.Time `json:"drift_since"`
+
+	// Incident is the stable ID of the continuous drift, shared by every alert about it
+	Incident string `json:"incident,omitempty"`
+
+	// Resolved marks the message sent when the incident's drift was resolved. Summary then
+	// says after how long.
+	Resolved bool `json:"resolved,omitempty"`
+
+	// Description and RunbookURL come from the project config and point responders at
+	// remediation instructions
+	Description string `json:"description,omitempty"`
+	RunbookURL  string `json:"runbook_url,omitempty"`
+
+	// Outputs are the project's captured terraform outputs, e.g. a cluster endpoint
+	Outputs map[string]string `json:"outputs,omitempty"`
+
+	// Escalation names the escalation rule that produced this alert, if any
+	Escalation string `json:"escalation,omitempty"`
+
+	// ErrorCategory is set on alerts about a failed scan instead of drift. Summary then holds
+	// the error and PlanOutput terraform's output.
+	ErrorCategory string `json:"error_category,omitempty"`
+
+	// Warnings is set on alerts about new terraform warnings instead of drift. Summary then
+	// lists them and PlanOutput holds terraform's output.
+	Warnings []terraform.Warning `json:"warnings,omitempty"`
+
+	// PolicyFindings is set on alerts about new policy findings instead of drift. Summary then
+	// lists them.
+	PolicyFindings []terraform.PolicyFinding `json:"policy_findings,omitempty"`
+
+	// Metadata identifies the watcher instance that sent the alert, e.g. its cluster
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	// DiffURL links to the color-coded HTML page of the drift, when one is published
+	DiffURL string `json:"diff_url,omitempty"`
+}
+
+// MetadataLine renders the watcher metadata in key order, e.g. "cluster=eu-1, environment=prod"
+func (a DriftAlert) MetadataLine() string {
+	keys := make([]string, 0, len(a.Metadata))
+	for key := range a.Metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for i, key := range keys {
+		keys[i] = key + "=" + a.Metadata[key]
+	}
+	return strings.Join(keys, ", ")
+}
+
+// OutputNames returns the names of the alert's outputs in order
+func (a DriftAlert) OutputNames() []string {
+	names := make([]string, 0, len(a.Outputs))
+	for name := range a.Outputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ResourceLink is a drifted resource and its page in the cloud console
+type ResourceLink struct {
+	Address string
+	URL     string
+}
+
+// maxResourceLinks bounds the resource links listed in chat messages
+const maxResourceLinks = 10
+
+// ResourceLinks returns the console links of the drifted resources, one per resource in
+// changelog order
+func (a DriftAlert) ResourceLinks() []ResourceLink {
+	var links []ResourceLink
+	seen := make(map[string]bool)
+	for _, c := range a.Changes {
+		if c.URL == "" || seen[c.Address] {
+			continue
+		}
+		seen[c.Address] = true
+		links = append(links, ResourceLink{Address: c.Address, URL: c.URL})
+	}
+	return links
+}
+
+// chatResourceLinks renders the first maxResourceLinks resource links, one per line, noting
+// how many more there are
+func chatResourceLinks(alert DriftAlert, link func(ResourceLink) string) string {
+	links := alert.ResourceLinks()
+	var lines []string
+	for i, l := range links {
+		if i == maxResourceLinks {
+			lines = append(lines, fmt.Sprintf("... (+%d)", len(links)-maxResourceLinks))
+			break
+		}
+		lines = append(lines, link(l))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Redacted returns a copy of the alert with known secrets removed from terraform's output
+func (a DriftAlert) Redacted() DriftAlert {
+	a.Summary = redact.String(a.Summary)
+	a.PlanOutput = redact.String(a.PlanOutput)
+	if len(a.Changes) > 0 {
+		changes := make([]terraform.AttributeChange, len(a.Changes))
+		for i, c := range a.Changes {
+			c.Before = redact.String(c.Before)
+			c.After = redact.String(c.After)
+			changes[i] = c
+		}
+		a.Changes = changes
+	}
+	if len(a.Warnings) > 0 {
+		warnings := make([]terraform.Warning, len(a.Warnings))
+		for i, w := range a.Warnings {
+			w.Detail = redact.String(w.Detail)
+			warnings[i] = w
+		}
+		a.Warnings = warnings
+	}
+	if len(a.PolicyFindings) > 0 {
+		findings := make([]terraform.PolicyFinding, len(a.PolicyFindings))
+		for i, f := range a.PolicyFindings {
+			f.Message = redact.String(f.Message)
+			findings[i] = f
+		}
+		a.PolicyFindings = findings
+	}
+	return a
+}
@@ -0,0 +1,100 @@
+package notifier
+
+import (
+	"bytes"
+	"html/template"
+	"strings"
+	"time"
+
+	"github.com/terradrift-watcher/internal/textutil"
+)
+
+// maxDiffPagePlanLength bounds the plan output rendered in a diff page
+const maxDiffPagePlanLength = 5 << 20
+
+// diffPageData is the data rendered by diffPageTemplate
+type diffPageData struct {
+	Alert     DriftAlert
+	Generated string
+	Summary   []string
+	Plan      []diffLine
+	Truncated bool
+	Legend    []diffLine
+}
+
+// diffLegend explains the colors of plan lines
+var diffLegend = []diffLine{
+	{Text: "+ create", Style: styleAdd},
+	{Text: "~ update", Style: styleChange},
+	{Text: "- destroy", Style: styleDestroy},
+	{Text: "-/+ replace", Style: styleReplace},
+}
+
+var diffPageTemplate = template.Must(template.New("diff").Funcs(template.FuncMap{
+	"style": func(s string) template.CSS { return template.CSS(s) },
+	"date":  func(t time.Time) string { return t.Local().Format(time.RFC1123) },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Drift in {{.Alert.Project}}</title>
+<style>
+body { font-family: -apple-system, Segoe UI, Helvetica, Arial, sans-serif; margin: 2em; color: #24292e; }
+table { border-collapse: collapse; margin-bottom: 1.5em; }
+th, td { border: 1px solid #e1e4e8; padding: 4px 10px; text-align: left; vertical-align: top; }
+th { background: #f6f8fa; }
+pre { font-family: Menlo, Consolas, monospace; font-size: 12px; background-color: #f6f8fa; padding: 8px; overflow-x: auto; }
+.legend span { padding: 0 6px; margin-right: 8px; }
+footer { color: #6a737d; font-size: 12px; }
+</style>
+</head>
+<body>
+<h1>Drift in {{.Alert.Project}}</h1>
+{{with .Alert.Description}}<p>{{.}}</p>{{end}}
+<table>
+{{if not .Alert.DriftSince.IsZero}}<tr><th>Drifted since</th><td>{{date .Alert.DriftSince}}</td></tr>{{end}}
+{{with .Alert.Owners}}<tr><th>Owner</th><td>{{range $i, $o := .}}{{if $i}}, {{end}}{{$o}}{{end}}</td></tr>{{end}}
+{{range $name, $value := .Alert.Outputs}}<tr><th>{{$name}}</th><td>{{$value}}</td></tr>
+{{end}}{{with .Alert.RunbookURL}}<tr><th>Runbook</th><td><a href="{{.}}">Remediation instructions</a></td></tr>{{end}}
+{{with .Alert.Fingerprint}}<tr><th>Fingerprint</th><td><code>{{.}}</code></td></tr>{{end}}
+{{with .Alert.MetadataLine}}<tr><th>Watcher</th><td>{{.}}</td></tr>{{end}}
+</table>
+<p>{{range .Summary}}{{.}}<br>{{end}}</p>
+{{with .Alert.Changes}}<h2>Changed Attributes</h2>
+<table>
+<tr><th>Resource</th><th>Attribute</th><th>Before</th><th>After</th></tr>
+{{range .}}<tr><td>{{if .URL}}<a href="{{.URL}}"><code>{{.Address}}</code></a>{{else}}<code>{{.Address}}</code>{{end}}</td><td>{{if .Attribute}}{{.Attribute}}{{else}}({{.Action}}){{end}}</td><td style="{{style "color:#b31d28;"}}"><code>{{.Before}}</code></td><td style="{{style "color:#22863a;"}}"><code>{{.After}}</code></td></tr>
+{{end}}</table>{{end}}
+{{with .Plan}}<h2>Plan</h2>
+<p class="legend">{{range $.Legend}}<span style="{{style .Style}}">{{.Text}}</span>{{end}}</p>
+<pre>{{range .}}<span style="{{style .Style}}">{{.Text}}</span>
+{{end}}</pre>{{if $.Truncated}}<p>... (truncated)</p>{{end}}{{end}}
+<footer>Generated by TerraDrift Watcher on {{.Generated}}</footer>
+</body>
+</html>
+`))
+
+// RenderDiffPage renders a drift alert as a standalone HTML page with the plan colored by
+// change type, for readers who do not use the terraform CLI
+func RenderDiffPage(alert DriftAlert, generated time.Time) ([]byte, error) {
+	data := diffPageData{
+		Alert:     alert,
+		Generated: generated.Local().Format(time.RFC1123),
+		Summary:   strings.Split(strings.TrimSpace(alert.Summary), "\n"),
+		Legend:    diffLegend,
+	}
+	plan := alert.PlanOutput
+	if len(plan) > maxDiffPagePlanLength {
+		plan = textutil.Truncate(plan, maxDiffPagePlanLength)
+		data.Truncated = true
+	}
+	if strings.TrimSpace(plan) != "" {
+		data.Plan = diffLines(plan)
+	}
+
+	var buf bytes.Buffer
+	if err := diffPageTemplate.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,31 @@
+package notifier
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRenderDiffPage(t *testing.T) {
+	alert := DriftAlert{
+		Project:     "network",
+		Summary:     "Plan: 0 to add, 1 to change, 0 to destroy.",
+		PlanOutput:  "  ~ resource \"aws_instance\" \"web\" {\n      ~ instance_type = \"t3.micro\" -> \"t3.large\"\n    }\n  + tags = \"<script>\"",
+		Fingerprint: "9f86d081884c7d65",
+	}
+	page, err := RenderDiffPage(alert, time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("RenderDiffPage failed: %v", err)
+	}
+	html := string(page)
+	for _, want := range []string{
+		"<title>Drift in network</title>",
+		`<span style="` + styleChange + `">  ~ resource`,
+		`<span style="` + styleAdd + `">  &#43; tags = &#34;&lt;script&gt;&#34;</span>`,
+		"<code>9f86d081884c7d65</code>",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("Expected the page to contain %q, got:\n%s", want, html)
+		}
+	}
+}
@@ -0,0 +1,285 @@
+package notifier
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"github.com/terradrift-watcher/internal/textutil"
+)
+
+// EmailConfig holds the SMTP settings of an email notifier
+type EmailConfig struct {
+	Host     string
+	Port     string // Default 587
+	From     string
+	To       []string
+	Username string // Optional; enables PLAIN auth
+	Password string
+	Locale   string // Language of the built-in message text (default English)
+}
+
+// OpenDrift is one drifted project listed in an email digest
+type OpenDrift struct {
+	Project    string
+	Summary    string
+	DriftSince time.Time
+	Owners     []string
+	RunbookURL string
+}
+
+// sendMail is replaced in tests
+var sendMail = smtp.SendMail
+
+// SendEmailAlert emails a drift alert to the configured recipients
+func SendEmailAlert(cfg EmailConfig, alert DriftAlert) error {
+	message, err := EmailAlertMessage(cfg, alert)
+	if err != nil {
+		return err
+	}
+	return sendEmail(cfg, cfg.To, message)
+}
+
+// EmailAlertMessage renders the complete email for a drift alert
+func EmailAlertMessage(cfg EmailConfig, alert DriftAlert) ([]byte, error) {
+	msgs, err := Locale(cfg.Locale)
+	if err != nil {
+		return nil, err
+	}
+
+	subject := fmt.Sprintf(msgs.EmailSubject, alert.Project)
+	intro := fmt.Sprintf(msgs.EmailIntro, alert.Project)
+	headline := intro
+	if alert.Escalation != "" {
+		subject = fmt.Sprintf(msgs.EscalationSubject, alert.Escalation, alert.Project)
+		headline = subject
+	}
+	if alert.ErrorCategory != "" {
+		subject = fmt.Sprintf(msgs.FailureSubject, alert.ErrorCategory, alert.Project)
+		headline, intro = subject, subject
+	}
+	if len(alert.Warnings) > 0 {
+		subject = fmt.Sprintf(msgs.WarningSubject, alert.Project)
+		headline, intro = subject, subject
+	}
+	if len(alert.PolicyFindings) > 0 {
+		subject = fmt.Sprintf(msgs.PolicySubject, alert.Project)
+		headline, intro = subject, subject
+	}
+	if alert.Resolved {
+		subject = fmt.Sprintf(msgs.ResolvedSubject, alert.Project)
+		headline, intro = subject, subject
+	}
+	// Threading mail clients group the messages of an incident by its ID in the subject
+	if alert.Incident != "" {
+		subject += " (" + alert.Incident + ")"
+	}
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "%s\n\n", intro)
+	if alert.Description != "" {
+		fmt.Fprintf(&body, "%s\n\n", alert.Description)
+	}
+	if !alert.DriftSince.IsZero() {
+		fmt.Fprintf(&body, "%s: %s\n", msgs.DriftedSince, alert.DriftSince.Local().Format(time.RFC1123))
+	}
+	if len(alert.Owners) > 0 {
+		fmt.Fprintf(&body, "%s: %s\n", msgs.Owner, strings.Join(alert.Owners, ", "))
+	}
+	for _, name := range alert.OutputNames() {
+		fmt.Fprintf(&body, "%s: %s\n", name, alert.Outputs[name])
+	}
+	if alert.RunbookURL != "" {
+		fmt.Fprintf(&body, "%s: %s\n", msgs.Runbook, alert.RunbookURL)
+	}
+	if alert.DiffURL != "" {
+		fmt.Fprintf(&body, "%s: %s\n", msgs.HTMLDiff, alert.DiffURL)
+	}
+	if alert.Incident != "" {
+		fmt.Fprintf(&body, "%s: %s\n", msgs.Incident, alert.Incident)
+	}
+	if alert.Fingerprint != "" {
+		fmt.Fprintf(&body, "%s: %s\n", msgs.Fingerprint, alert.Fingerprint)
+	}
+	if len(alert.Metadata) > 0 {
+		fmt.Fprintf(&body, "%s: %s\n", msgs.Watcher, alert.MetadataLine())
+	}
+	fmt.Fprintf(&body, "\n%s\n", alert.Summary)
+	for _, c := range alert.Changes {
+		if c.Attribute == "" {
+			fmt.Fprintf(&body, "  %s (%s)\n", c.Address, c.Action)
+			continue
+		}
+		fmt.Fprintf(&body, "  %s.%s: %s -> %s\n", c.Address, c.Attribute, c.Before, c.After)
+	}
+	if links := alert.ResourceLinks(); len(links) > 0 {
+		fmt.Fprintf(&body, "\n%s:\n", msgs.Resources)
+		for _, l := range links {
+			fmt.Fprintf(&body, "  %s: %s\n", l.Address, l.URL)
+		}
+	}
+
+	// The plain text part keeps the plan as-is; the HTML part colors it by change type
+	if plan := alert.PlanOutput; plan != "" {
+		if len(plan) > maxEmailPlanLength {
+			plan = textutil.Truncate(plan, maxEmailPlanLength) + "\n" + msgs.Truncated
+		}
+		fmt.Fprintf(&body, "\n%s:\n\n%s\n", msgs.PlanOutput, plan)
+	}
+
+	html, err := renderAlertHTML(msgs, headline, alert)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render HTML email: %w", err)
+	}
+
+	return buildMessage(cfg.From, cfg.To, subject, body.String(), html)
+}
+
+// SendEmailAlertWithRetry emails a drift alert with retry logic
+func SendEmailAlertWithRetry(cfg EmailConfig, alert DriftAlert, maxRetries int) error {
+	return withRetry("email notification", maxRetries, func() error {
+		return SendEmailAlert(cfg, alert)
+	})
+}
+
+// SendEmailDigest emails a summary of all open drift to the given recipients
+func SendEmailDigest(cfg EmailConfig, to []string, drifts []OpenDrift) error {
+	msgs, err := Locale(cfg.Locale)
+	if err != nil {
+		return err
+	}
+
+	subject := fmt.Sprintf(msgs.DigestSubject, len(drifts))
+
+	var body strings.Builder
+	if len(drifts) == 0 {
+		body.WriteString(msgs.DigestNone + "\n")
+	} else {
+		fmt.Fprintf(&body, msgs.DigestIntro+"\n\n", len(drifts))
+	}
+	for _, drift := range drifts {
+		fmt.Fprintf(&body, "- %s", drift.Project)
+		if !drift.DriftSince.IsZero() {
+			fmt.Fprintf(&body, " ("+msgs.DigestDriftedFor+")", time.Since(drift.DriftSince).Round(time.Hour))
+		}
+		body.WriteString("\n")
+		if len(drift.Owners) > 0 {
+			fmt.Fprintf(&body, "  %s: %s\n", msgs.Owner, strings.Join(drift.Owners, ", "))
+		}
+		if drift.Summary != "" {
+			fmt.Fprintf(&body, "  %s\n", firstLine(drift.Summary))
+		}
+		if drift.RunbookURL != "" {
+			fmt.Fprintf(&body, "  %s: %s\n", msgs.Runbook, drift.RunbookURL)
+		}
+	}
+
+	message, err := buildMessage(cfg.From, to, subject, body.String(), "")
+	if err != nil {
+		return err
+	}
+	return sendEmail(cfg, to, message)
+}
+
+// sendEmail delivers a rendered message over SMTP
+func sendEmail(cfg EmailConfig, to []string, message []byte) error {
+	if cfg.Host == "" {
+		return fmt.Errorf("SMTP host is empty")
+	}
+	if cfg.From == "" || len(to) == 0 {
+		return fmt.Errorf("email sender and recipients are required")
+	}
+
+	port := cfg.Port
+	if port == "" {
+		port = "587"
+	}
+
+	var auth smtp.Auth
+	if cfg.Username != "" {
+		auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+
+	if err := sendMail(net.JoinHostPort(cfg.Host, port), auth, cfg.From, to, message); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+// buildMessage renders the headers and body of an email, as multipart/alternative when an
+// HTML part is given. Parts are quoted-printable encoded so long plan lines survive SMTP.
+func buildMessage(from string, to []string, subject string, text string, html string) ([]byte, error) {
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", from)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("UTF-8", subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+
+	if html == "" {
+		msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+		msg.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+		if err := writeQuotedPrintable(&msg, text); err != nil {
+			return nil, err
+		}
+		return msg.Bytes(), nil
+	}
+
+	parts := multipart.NewWriter(&msg)
+	fmt.Fprintf(&msg, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", parts.Boundary())
+	for _, part := range []struct{ contentType, body string }{
+		{"text/plain; charset=UTF-8", text},
+		{"text/html; charset=UTF-8", html},
+	} {
+		w, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := writeQuotedPrintable(w, part.body); err != nil {
+			return nil, err
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+	return msg.Bytes(), nil
+}
+
+// writeQuotedPrintable writes body with CRLF line endings in quoted-printable encoding
+func writeQuotedPrintable(w io.Writer, body string) error {
+	qp := quotedprintable.NewWriter(w)
+	if _, err := qp.Write([]byte(strings.ReplaceAll(body, "\n", "\r\n"))); err != nil {
+		return err
+	}
+	return qp.Close()
+}
+
+// firstLine returns the first line of s
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
+
+// ParseRecipients splits a comma-separated recipient list
+func ParseRecipients(value string) []string {
+	var recipients []string
+	for _, r := range strings.Split(value, ",") {
+		if r = strings.TrimSpace(r); r != "" {
+			recipients = append(recipients, r)
+		}
+	}
+	return recipients
+}
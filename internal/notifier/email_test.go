@@ -0,0 +1,120 @@
+package notifier
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSendEmailDigest(t *testing.T) {
+	var gotAddr string
+	var gotTo []string
+	var gotMsg string
+	sendMail = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		gotAddr, gotTo, gotMsg = addr, to, string(msg)
+		return nil
+	}
+	defer func() { sendMail = smtp.SendMail }()
+
+	cfg := EmailConfig{Host: "smtp.example.com", From: "drift@example.com"}
+	drifts := []OpenDrift{{
+		Project:    "network",
+		Summary:    "Plan: 0 to add, 1 to change, 0 to destroy.\n\nmore detail",
+		DriftSince: time.Now().Add(-26 * time.Hour),
+		Owners:     []string{"platform"},
+		RunbookURL: "https://wiki.example.com/network",
+	}}
+	if err := SendEmailDigest(cfg, []string{"lead@example.com"}, drifts); err != nil {
+		t.Fatalf("SendEmailDigest failed: %v", err)
+	}
+
+	if gotAddr != "smtp.example.com:587" {
+		t.Errorf("Expected default submission port, got %s", gotAddr)
+	}
+	if len(gotTo) != 1 || gotTo[0] != "lead@example.com" {
+		t.Errorf("Expected digest recipients, got %v", gotTo)
+	}
+	for _, want := range []string{
+		"Subject: Drift digest: 1 project(s) drifted",
+		"- network (drifted for 26h0m0s)",
+		"Owner: platform",
+		"Plan: 0 to add, 1 to change, 0 to destroy.\r\n",
+		"Runbook: https://wiki.example.com/network",
+	} {
+		if !strings.Contains(gotMsg, want) {
+			t.Errorf("Expected digest to contain %q, got:\n%s", want, gotMsg)
+		}
+	}
+	if strings.Contains(gotMsg, "more detail") {
+		t.Errorf("Expected only the first summary line in the digest")
+	}
+}
+
+func TestParseRecipients(t *testing.T) {
+	got := ParseRecipients(" a@example.com, ,b@example.com ")
+	if len(got) != 2 || got[0] != "a@example.com" || got[1] != "b@example.com" {
+		t.Errorf("Unexpected recipients: %v", got)
+	}
+}
+
+func TestSendEmailAlert_HTMLWithFallback(t *testing.T) {
+	var gotMsg []byte
+	sendMail = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		gotMsg = msg
+		return nil
+	}
+	defer func() { sendMail = smtp.SendMail }()
+
+	alert := DriftAlert{
+		Project:    "network",
+		Summary:    "Plan: 1 to add, 0 to change, 1 to destroy.",
+		PlanOutput: "  # aws_vpc.main will be destroyed\n  - resource \"aws_vpc\" \"main\" {\n  + cidr = \"10.0.0.0/16\"\n  ~ tags = {}\n",
+		Outputs:    map[string]string{"environment": "staging"},
+	}
+	cfg := EmailConfig{Host: "smtp.example.com", From: "drift@example.com", To: []string{"ops@example.com"}}
+	if err := SendEmailAlert(cfg, alert); err != nil {
+		t.Fatalf("SendEmailAlert failed: %v", err)
+	}
+
+	msg, err := mail.ReadMessage(bytes.NewReader(gotMsg))
+	if err != nil {
+		t.Fatalf("Failed to parse message: %v", err)
+	}
+	mediaType, params, _ := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if mediaType != "multipart/alternative" {
+		t.Fatalf("Expected multipart/alternative, got %s", mediaType)
+	}
+
+	parts := make(map[string]string)
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Failed to read part: %v", err)
+		}
+		body, _ := io.ReadAll(part)
+		contentType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		parts[contentType] = string(body)
+	}
+
+	for _, want := range []string{`+ cidr = "10.0.0.0/16"`, "environment: staging"} {
+		if !strings.Contains(parts["text/plain"], want) {
+			t.Errorf("Expected the plain text fallback to contain %q, got:\n%s", want, parts["text/plain"])
+		}
+	}
+	html := parts["text/html"]
+	for _, want := range []string{styleAdd, styleDestroy, styleChange, "cidr = &#34;10.0.0.0/16&#34;", "environment</td><td>staging"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("Expected HTML part to contain %q, got:\n%s", want, html)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package notifier
+
+import (
+	"bytes"
+	"html/template"
+	"strings"
+	"time"
+
+	"github.com/terradrift-watcher/internal/textutil"
+)
+
+// maxEmailPlanLength bounds the plan output included in an email
+const maxEmailPlanLength = 100000
+
+// Inline styles for plan lines; mail clients drop <style> blocks, so each line carries its own
+const (
+	styleAdd     = "color:#22863a;background-color:#f0fff4;"
+	styleDestroy = "color:#b31d28;background-color:#ffeef0;"
+	styleChange  = "color:#b08800;background-color:#fffbdd;"
+	styleReplace = "color:#6f42c1;background-color:#f5f0ff;"
+	styleHeading = "font-weight:bold;"
+)
+
+// diffLine is one line of plan output with the style for its change type
+type diffLine struct {
+	Text  string
+	Style string
+}
+
+// classifyPlanLine picks the style of a plan output line from terraform's change markers
+func classifyPlanLine(line string) string {
+	trimmed := strings.TrimSpace(line)
+	switch {
+	case strings.HasPrefix(trimmed, "-/+"), strings.HasPrefix(trimmed, "+/-"):
+		return styleReplace
+	case strings.HasPrefix(trimmed, "+"):
+		return styleAdd
+	case strings.HasPrefix(trimmed, "-"):
+		return styleDestroy
+	case strings.HasPrefix(trimmed, "~"):
+		return styleChange
+	case strings.HasPrefix(trimmed, "#"):
+		return styleHeading
+	}
+	return ""
+}
+
+// diffLines splits plan output into styled lines
+func diffLines(planOutput string) []diffLine {
+	var lines []diffLine
+	for _, line := range strings.Split(strings.TrimRight(planOutput, "\n"), "\n") {
+		lines = append(lines, diffLine{Text: line, Style: classifyPlanLine(line)})
+	}
+	return lines
+}
+
+// alertEmailData is the data rendered by alertEmailTemplate
+type alertEmailData struct {
+	Msgs       Messages
+	Headline   string
+	Alert      DriftAlert
+	DriftSince string
+	Summary    []string
+	Plan       []diffLine
+	Truncated  bool
+}
+
+var alertEmailTemplate = template.Must(template.New("alert").Funcs(template.FuncMap{
+	"style": func(s string) template.CSS { return template.CSS(s) },
+}).Parse(`<!DOCTYPE html>
+<html><body style="font-family:-apple-system,Segoe UI,Helvetica,Arial,sans-serif;color:#24292e;">
+<h2 style="color:#b31d28;">{{.Headline}}</h2>
+{{with .Alert.Description}}<p>{{.}}</p>{{end}}
+<table style="border-collapse:collapse;">
+<tr><td style="padding:2px 12px 2px 0;font-weight:bold;">{{.Msgs.Project}}</td><td>{{.Alert.Project}}</td></tr>
+{{with .DriftSince}}<tr><td style="padding:2px 12px 2px 0;font-weight:bold;">{{$.Msgs.DriftedSince}}</td><td>{{.}}</td></tr>{{end}}
+{{with .Alert.Owners}}<tr><td style="padding:2px 12px 2px 0;font-weight:bold;">{{$.Msgs.Owner}}</td><td>{{range $i, $o := .}}{{if $i}}, {{end}}{{$o}}{{end}}</td></tr>{{end}}
+{{range $name, $value := .Alert.Outputs}}<tr><td style="padding:2px 12px 2px 0;font-weight:bold;">{{$name}}</td><td>{{$value}}</td></tr>
+{{end}}{{with .Alert.RunbookURL}}<tr><td style="padding:2px 12px 2px 0;font-weight:bold;">{{$.Msgs.Runbook}}</td><td><a href="{{.}}">{{$.Msgs.RunbookLink}}</a></td></tr>{{end}}
+{{with .Alert.DiffURL}}<tr><td style="padding:2px 12px 2px 0;font-weight:bold;">{{$.Msgs.HTMLDiff}}</td><td><a href="{{.}}">{{$.Alert.Project}}</a></td></tr>{{end}}
+{{with .Alert.Incident}}<tr><td style="padding:2px 12px 2px 0;font-weight:bold;">{{$.Msgs.Incident}}</td><td>{{.}}</td></tr>{{end}}
+{{with .Alert.Fingerprint}}<tr><td style="padding:2px 12px 2px 0;font-weight:bold;">{{$.Msgs.Fingerprint}}</td><td><code>{{.}}</code></td></tr>{{end}}
+{{with .Alert.MetadataLine}}<tr><td style="padding:2px 12px 2px 0;font-weight:bold;">{{$.Msgs.Watcher}}</td><td>{{.}}</td></tr>{{end}}
+</table>
+<p>{{range .Summary}}{{.}}<br>{{end}}</p>
+{{with .Alert.Changes}}<table style="border-collapse:collapse;font-size:13px;">
+{{range .}}<tr><td style="padding:2px 8px;border:1px solid #e1e4e8;">{{if .URL}}<a href="{{.URL}}"><code>{{.Address}}</code></a>{{else}}<code>{{.Address}}</code>{{end}}</td><td style="padding:2px 8px;border:1px solid #e1e4e8;">{{if .Attribute}}{{.Attribute}}{{else}}({{.Action}}){{end}}</td><td style="padding:2px 8px;border:1px solid #e1e4e8;{{style "color:#b31d28;"}}"><code>{{.Before}}</code></td><td style="padding:2px 8px;border:1px solid #e1e4e8;{{style "color:#22863a;"}}"><code>{{.After}}</code></td></tr>
+{{end}}</table>{{end}}
+{{with .Plan}}<h3>{{$.Msgs.PlanOutput}}</h3>
+<pre style="font-family:Menlo,Consolas,monospace;font-size:12px;background-color:#f6f8fa;padding:8px;">{{range .}}<span style="{{style .Style}}">{{.Text}}</span>
+{{end}}</pre>{{if $.Truncated}}<p>{{$.Msgs.Truncated}}</p>{{end}}{{end}}
+</body></html>
+`))
+
+// renderAlertHTML renders the HTML part of a drift alert email
+func renderAlertHTML(msgs Messages, headline string, alert DriftAlert) (string, error) {
+	data := alertEmailData{
+		Msgs:     msgs,
+		Headline: headline,
+		Alert:    alert,
+		Summary:  strings.Split(strings.TrimSpace(alert.Summary), "\n"),
+	}
+	if !alert.DriftSince.IsZero() {
+		data.DriftSince = alert.DriftSince.Local().Format(time.RFC1123)
+	}
+
+	plan := alert.PlanOutput
+	if len(plan) > maxEmailPlanLength {
+		plan = textutil.Truncate(plan, maxEmailPlanLength)
+		data.Truncated = true
+	}
+	if strings.TrimSpace(plan) != "" {
+		data.Plan = diffLines(plan)
+	}
+
+	var buf bytes.Buffer
+	if err := alertEmailTemplate.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,164 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+)
+
+// DefaultUserAgent is sent with outgoing webhook requests unless a notifier overrides it
+const DefaultUserAgent = "terradrift-watcher"
+
+// Headers set on signed webhook requests
+const (
+	SignatureHeader = "X-TerraDrift-Signature"
+	TimestampHeader = "X-TerraDrift-Timestamp"
+)
+
+// HTTPOptions customizes the webhook requests sent by a notifier
+type HTTPOptions struct {
+	UserAgent string
+	Headers   map[string]string // Static headers, e.g. an Authorization token
+
+	// SigningSecret enables HMAC-SHA256 signing of the payload so receivers can verify
+	// the request came from the watcher
+	SigningSecret string
+
+	// Locale selects the language of the built-in message text (default English)
+	Locale string
+
+	// PayloadFormat selects the Slack message format, see PayloadFormats
+	PayloadFormat string
+
+	// Channel overrides the channel of a legacy Slack incoming webhook (attachments and text
+	// formats)
+	Channel string
+
+	// Template is a custom text/template file rendering the message body instead of the
+	// built-in format (Slack, webhook and Zulip notifiers)
+	Template string
+
+	// TLS client certificate (mutual TLS) and an extra CA bundle for private endpoints
+	CertFile string
+	KeyFile  string
+	CAFile   string
+
+	// Connection settings
+	DialTimeout time.Duration // Default 30s
+	Network     string        // "tcp4" or "tcp6" to force an IP family
+	Resolver    string        // DNS server (host:port) used instead of the system resolver
+}
+
+// requestTimeout bounds each webhook request
+const requestTimeout = 10 * time.Second
+
+// NewHTTPClient builds the client used for webhook requests and other outgoing HTTP calls
+func NewHTTPClient(opts HTTPOptions) (*http.Client, error) {
+	client := &http.Client{Timeout: requestTimeout}
+	if opts.CertFile == "" && opts.CAFile == "" && opts.DialTimeout == 0 && opts.Network == "" && opts.Resolver == "" {
+		return client, nil
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	client.Transport = transport
+
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	if opts.DialTimeout > 0 {
+		dialer.Timeout = opts.DialTimeout
+	}
+	if opts.Resolver != "" {
+		dialer.Resolver = &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				return dialer.DialContext(ctx, network, opts.Resolver)
+			},
+		}
+	}
+	transport.DialContext = func(ctx context.Context, network, address string) (net.Conn, error) {
+		if opts.Network != "" {
+			network = opts.Network
+		}
+		return dialer.DialContext(ctx, network, address)
+	}
+
+	if opts.CertFile == "" && opts.CAFile == "" {
+		return client, nil
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if opts.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	if opts.CAFile != "" {
+		pem, err := os.ReadFile(opts.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA file %s", opts.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	transport.TLSClientConfig = tlsConfig
+	return client, nil
+}
+
+// Sign returns the signature header value for a payload sent at timestamp (Unix seconds).
+// The HMAC covers "<timestamp>.<body>" so a captured request cannot be replayed with a new timestamp.
+func Sign(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// newJSONRequest builds a POST request for a JSON payload with the notifier's headers applied
+func newJSONRequest(url string, body []byte, opts HTTPOptions) (*http.Request, error) {
+	return newRequest(url, "application/json", body, opts)
+}
+
+// newRequest builds a POST request with the notifier's user agent, headers and signature
+func newRequest(url string, contentType string, body []byte, opts HTTPOptions) (*http.Request, error) {
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", contentType)
+	userAgent := opts.UserAgent
+	if userAgent == "" {
+		userAgent = DefaultUserAgent
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	for name, value := range opts.Headers {
+		req.Header.Set(name, value)
+	}
+
+	if opts.SigningSecret != "" {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(TimestampHeader, timestamp)
+		req.Header.Set(SignatureHeader, Sign(opts.SigningSecret, timestamp, body))
+	}
+
+	return req, nil
+}
@@ -0,0 +1,109 @@
+package notifier
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSendSlackAlert_HeadersAndSignature(t *testing.T) {
+	var got *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	opts := HTTPOptions{
+		UserAgent:     "drift-bot/1.0",
+		Headers:       map[string]string{"Authorization": "Bearer token"},
+		SigningSecret: "s3cret",
+	}
+	if err := SendSlackAlert(server.URL, DriftAlert{Project: "network", Summary: "1 to change"}, opts); err != nil {
+		t.Fatalf("SendSlackAlert failed: %v", err)
+	}
+
+	if ua := got.Header.Get("User-Agent"); ua != "drift-bot/1.0" {
+		t.Errorf("Expected custom user agent, got %q", ua)
+	}
+	if auth := got.Header.Get("Authorization"); auth != "Bearer token" {
+		t.Errorf("Expected static Authorization header, got %q", auth)
+	}
+
+	timestamp := got.Header.Get(TimestampHeader)
+	if timestamp == "" {
+		t.Fatalf("Expected %s header to be set", TimestampHeader)
+	}
+	if signature := got.Header.Get(SignatureHeader); signature != Sign("s3cret", timestamp, body) {
+		t.Errorf("Signature %q does not match payload", signature)
+	}
+}
+
+func TestSendSlackAlert_DefaultUserAgentUnsigned(t *testing.T) {
+	var got *http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+	}))
+	defer server.Close()
+
+	if err := SendSlackAlert(server.URL, DriftAlert{Project: "network"}, HTTPOptions{}); err != nil {
+		t.Fatalf("SendSlackAlert failed: %v", err)
+	}
+	if ua := got.Header.Get("User-Agent"); ua != DefaultUserAgent {
+		t.Errorf("Expected default user agent, got %q", ua)
+	}
+	if got.Header.Get(SignatureHeader) != "" {
+		t.Errorf("Expected unsigned request without a signing secret")
+	}
+}
+
+func TestSendSlackAlert_MutualTLS(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.StartTLS()
+	defer server.Close()
+
+	// The test server's self-signed certificate doubles as CA and client certificate
+	cert := server.TLS.Certificates[0]
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "client.crt")
+	keyFile := filepath.Join(dir, "client.key")
+	key, err := x509.MarshalPKCS8PrivateKey(cert.PrivateKey)
+	if err != nil {
+		t.Fatalf("Failed to marshal key: %v", err)
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Certificate[0]})
+	os.WriteFile(certFile, certPEM, 0600)
+	os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: key}), 0600)
+
+	if err := SendSlackAlert(server.URL, DriftAlert{Project: "network"}, HTTPOptions{CAFile: certFile}); err == nil {
+		t.Errorf("Expected the request without a client certificate to be rejected")
+	}
+
+	opts := HTTPOptions{CertFile: certFile, KeyFile: keyFile, CAFile: certFile}
+	if err := SendSlackAlert(server.URL, DriftAlert{Project: "network"}, opts); err != nil {
+		t.Errorf("Expected mutual TLS request to succeed, got %v", err)
+	}
+}
+
+func TestSendSlackAlert_ForcedIPVersion(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	// httptest listens on 127.0.0.1, so IPv4 must work and IPv6 must not
+	if err := SendSlackAlert(server.URL, DriftAlert{Project: "network"}, HTTPOptions{Network: "tcp4", DialTimeout: time.Second}); err != nil {
+		t.Errorf("Expected IPv4 request to succeed, got %v", err)
+	}
+	if err := SendSlackAlert(server.URL, DriftAlert{Project: "network"}, HTTPOptions{Network: "tcp6", DialTimeout: time.Second}); err == nil {
+		t.Errorf("Expected IPv6-only request to an IPv4 address to fail")
+	}
+}
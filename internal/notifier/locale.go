@@ -0,0 +1,229 @@
+package notifier
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultLocale is used when a notifier does not select one
+const DefaultLocale = "en"
+
+// Messages is the built-in notification text of one locale. Entries containing
+// verbs are format strings; their arguments are noted alongside.
+type Messages struct {
+	AlertHeadline      string // project
+	EscalationHeadline string // escalation, project
+	FailureHeadline    string // error category, project
+	WarningHeadline    string // project
+	PolicyHeadline     string // project
+	ResolvedHeadline   string // project
+	AlertTitle         string
+	Project            string
+	Status             string
+	StatusDrifted      string
+	StatusFailed       string
+	StatusWarnings     string
+	StatusPolicy       string
+	StatusResolved     string
+	DriftedFor         string
+	DriftedSince       string
+	Owner              string
+	Runbook            string
+	RunbookLink        string
+	Fingerprint        string
+	Incident           string
+	Watcher            string
+	HTMLDiff           string
+	Resources          string
+	PlanOutput         string
+	Truncated          string
+
+	EmailSubject      string // project
+	EscalationSubject string // escalation, project
+	FailureSubject    string // error category, project
+	WarningSubject    string // project
+	PolicySubject     string // project
+	ResolvedSubject   string // project
+	EmailIntro        string // project
+
+	DigestSubject    string // number of drifted projects
+	DigestIntro      string // number of drifted projects
+	DigestNone       string
+	DigestDriftedFor string // duration
+}
+
+// bundles holds the built-in translations, keyed by language code
+var bundles = map[string]Messages{
+	"en": {
+		AlertHeadline:      ":rotating_light: *Drift Detected in Project: %s*",
+		EscalationHeadline: ":rotating_light: *Escalation (%s): Unresolved Drift in Project: %s*",
+		FailureHeadline:    ":x: *Drift Check Failed (%s) in Project: %s*",
+		WarningHeadline:    ":warning: *New Terraform Warnings in Project: %s*",
+		PolicyHeadline:     ":shield: *New Policy Findings in Project: %s*",
+		ResolvedHeadline:   ":white_check_mark: *Drift Resolved in Project: %s*",
+		AlertTitle:         "Configuration Drift Alert",
+		Project:            "Project",
+		Status:             "Status",
+		StatusDrifted:      "Drift Detected",
+		StatusFailed:       "Check Failed",
+		StatusWarnings:     "New Warnings",
+		StatusPolicy:       "New Policy Findings",
+		StatusResolved:     "Resolved",
+		DriftedFor:         "Drifted For",
+		DriftedSince:       "Drifted since",
+		Owner:              "Owner",
+		Runbook:            "Runbook",
+		RunbookLink:        "Remediation instructions",
+		Fingerprint:        "Fingerprint",
+		Incident:           "Incident",
+		Watcher:            "Watcher",
+		HTMLDiff:           "HTML diff",
+		Resources:          "Affected Resources",
+		PlanOutput:         "Plan Output",
+		Truncated:          "... (truncated)",
+		EmailSubject:       "Drift detected in %s",
+		EscalationSubject:  "[%s] Unresolved drift in %s",
+		FailureSubject:     "[%s] Drift check failed in %s",
+		WarningSubject:     "New terraform warnings in %s",
+		PolicySubject:      "New policy findings in %s",
+		ResolvedSubject:    "Drift resolved in %s",
+		EmailIntro:         "TerraDrift Watcher detected configuration drift in project %s.",
+		DigestSubject:      "Drift digest: %d project(s) drifted",
+		DigestIntro:        "%d project(s) currently have unresolved drift:",
+		DigestNone:         "No open drift. All monitored projects matched their Terraform configuration at their last scan.",
+		DigestDriftedFor:   "drifted for %s",
+	},
+	"de": {
+		AlertHeadline:      ":rotating_light: *Drift im Projekt erkannt: %s*",
+		EscalationHeadline: ":rotating_light: *Eskalation (%s): Ungelöster Drift im Projekt: %s*",
+		FailureHeadline:    ":x: *Drift-Prüfung fehlgeschlagen (%s) im Projekt: %s*",
+		WarningHeadline:    ":warning: *Neue Terraform-Warnungen im Projekt: %s*",
+		PolicyHeadline:     ":shield: *Neue Policy-Befunde im Projekt: %s*",
+		ResolvedHeadline:   ":white_check_mark: *Drift im Projekt behoben: %s*",
+		AlertTitle:         "Konfigurationsdrift",
+		Project:            "Projekt",
+		Status:             "Status",
+		StatusDrifted:      "Drift erkannt",
+		StatusFailed:       "Prüfung fehlgeschlagen",
+		StatusWarnings:     "Neue Warnungen",
+		StatusPolicy:       "Neue Policy-Befunde",
+		StatusResolved:     "Behoben",
+		DriftedFor:         "Drift seit",
+		DriftedSince:       "Drift seit",
+		Owner:              "Verantwortlich",
+		Runbook:            "Runbook",
+		RunbookLink:        "Anleitung zur Behebung",
+		Fingerprint:        "Fingerabdruck",
+		Incident:           "Vorfall",
+		Watcher:            "Watcher-Instanz",
+		HTMLDiff:           "HTML-Diff",
+		Resources:          "Betroffene Ressourcen",
+		PlanOutput:         "Plan-Ausgabe",
+		Truncated:          "... (gekürzt)",
+		EmailSubject:       "Drift erkannt in %s",
+		EscalationSubject:  "[%s] Ungelöster Drift in %s",
+		FailureSubject:     "[%s] Drift-Prüfung fehlgeschlagen in %s",
+		WarningSubject:     "Neue Terraform-Warnungen in %s",
+		PolicySubject:      "Neue Policy-Befunde in %s",
+		ResolvedSubject:    "Drift behoben in %s",
+		EmailIntro:         "TerraDrift Watcher hat einen Konfigurationsdrift im Projekt %s erkannt.",
+		DigestSubject:      "Drift-Übersicht: %d Projekt(e) mit Drift",
+		DigestIntro:        "%d Projekt(e) haben derzeit ungelösten Drift:",
+		DigestNone:         "Kein offener Drift. Alle überwachten Projekte entsprachen beim letzten Scan ihrer Terraform-Konfiguration.",
+		DigestDriftedFor:   "Drift seit %s",
+	},
+	"fr": {
+		AlertHeadline:      ":rotating_light: *Dérive détectée dans le projet : %s*",
+		EscalationHeadline: ":rotating_light: *Escalade (%s) : dérive non résolue dans le projet : %s*",
+		FailureHeadline:    ":x: *Échec de la vérification de dérive (%s) dans le projet : %s*",
+		WarningHeadline:    ":warning: *Nouveaux avertissements Terraform dans le projet : %s*",
+		PolicyHeadline:     ":shield: *Nouvelles non-conformités dans le projet : %s*",
+		ResolvedHeadline:   ":white_check_mark: *Dérive résolue dans le projet : %s*",
+		AlertTitle:         "Alerte de dérive de configuration",
+		Project:            "Projet",
+		Status:             "Statut",
+		StatusDrifted:      "Dérive détectée",
+		StatusFailed:       "Échec de la vérification",
+		StatusWarnings:     "Nouveaux avertissements",
+		StatusPolicy:       "Nouvelles non-conformités",
+		StatusResolved:     "Résolue",
+		DriftedFor:         "Dérive depuis",
+		DriftedSince:       "Dérive depuis",
+		Owner:              "Responsable",
+		Runbook:            "Runbook",
+		RunbookLink:        "Instructions de correction",
+		Fingerprint:        "Empreinte",
+		Incident:           "Incident",
+		Watcher:            "Instance du watcher",
+		HTMLDiff:           "Diff HTML",
+		Resources:          "Ressources concernées",
+		PlanOutput:         "Sortie du plan",
+		Truncated:          "... (tronqué)",
+		EmailSubject:       "Dérive détectée dans %s",
+		EscalationSubject:  "[%s] Dérive non résolue dans %s",
+		FailureSubject:     "[%s] Échec de la vérification de dérive dans %s",
+		WarningSubject:     "Nouveaux avertissements Terraform dans %s",
+		PolicySubject:      "Nouvelles non-conformités dans %s",
+		ResolvedSubject:    "Dérive résolue dans %s",
+		EmailIntro:         "TerraDrift Watcher a détecté une dérive de configuration dans le projet %s.",
+		DigestSubject:      "Synthèse des dérives : %d projet(s) concerné(s)",
+		DigestIntro:        "%d projet(s) présentent actuellement une dérive non résolue :",
+		DigestNone:         "Aucune dérive en cours. Tous les projets surveillés correspondaient à leur configuration Terraform lors du dernier scan.",
+		DigestDriftedFor:   "dérive depuis %s",
+	},
+	"es": {
+		AlertHeadline:      ":rotating_light: *Desviación detectada en el proyecto: %s*",
+		EscalationHeadline: ":rotating_light: *Escalado (%s): desviación sin resolver en el proyecto: %s*",
+		FailureHeadline:    ":x: *Falló la comprobación de desviaciones (%s) en el proyecto: %s*",
+		WarningHeadline:    ":warning: *Nuevas advertencias de Terraform en el proyecto: %s*",
+		PolicyHeadline:     ":shield: *Nuevos hallazgos de políticas en el proyecto: %s*",
+		ResolvedHeadline:   ":white_check_mark: *Desviación resuelta en el proyecto: %s*",
+		AlertTitle:         "Alerta de desviación de configuración",
+		Project:            "Proyecto",
+		Status:             "Estado",
+		StatusDrifted:      "Desviación detectada",
+		StatusFailed:       "Comprobación fallida",
+		StatusWarnings:     "Nuevas advertencias",
+		StatusPolicy:       "Nuevos hallazgos de políticas",
+		StatusResolved:     "Resuelta",
+		DriftedFor:         "Desviado desde hace",
+		DriftedSince:       "Desviado desde",
+		Owner:              "Responsable",
+		Runbook:            "Runbook",
+		RunbookLink:        "Instrucciones de corrección",
+		Fingerprint:        "Huella",
+		Incident:           "Incidente",
+		Watcher:            "Instancia del watcher",
+		HTMLDiff:           "Diff HTML",
+		Resources:          "Recursos afectados",
+		PlanOutput:         "Salida del plan",
+		Truncated:          "... (truncado)",
+		EmailSubject:       "Desviación detectada en %s",
+		EscalationSubject:  "[%s] Desviación sin resolver en %s",
+		FailureSubject:     "[%s] Falló la comprobación de desviaciones en %s",
+		WarningSubject:     "Nuevas advertencias de Terraform en %s",
+		PolicySubject:      "Nuevos hallazgos de políticas en %s",
+		ResolvedSubject:    "Desviación resuelta en %s",
+		EmailIntro:         "TerraDrift Watcher detectó una desviación de configuración en el proyecto %s.",
+		DigestSubject:      "Resumen de desviaciones: %d proyecto(s) afectado(s)",
+		DigestIntro:        "%d proyecto(s) tienen actualmente desviaciones sin resolver:",
+		DigestNone:         "No hay desviaciones abiertas. Todos los proyectos coincidían con su configuración de Terraform en el último análisis.",
+		DigestDriftedFor:   "desviado desde hace %s",
+	},
+}
+
+// Locale returns the messages for a locale such as "de" or "de-DE", defaulting to English
+func Locale(name string) (Messages, error) {
+	if name == "" {
+		return bundles[DefaultLocale], nil
+	}
+	lang := strings.ToLower(name)
+	if i := strings.IndexAny(lang, "-_"); i >= 0 {
+		lang = lang[:i]
+	}
+	messages, ok := bundles[lang]
+	if !ok {
+		return Messages{}, fmt.Errorf("unsupported locale '%s'", name)
+	}
+	return messages, nil
+}
@@ -0,0 +1,37 @@
+package notifier
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/terradrift-watcher/internal/config"
+)
+
+func TestLocaleBundlesComplete(t *testing.T) {
+	for _, locale := range config.Locales {
+		messages, err := Locale(locale)
+		if err != nil {
+			t.Errorf("Locale %s accepted by config has no bundle: %v", locale, err)
+			continue
+		}
+		value := reflect.ValueOf(messages)
+		for i := 0; i < value.NumField(); i++ {
+			if value.Field(i).String() == "" {
+				t.Errorf("Locale %s is missing %s", locale, value.Type().Field(i).Name)
+			}
+		}
+	}
+}
+
+func TestLocale(t *testing.T) {
+	messages, err := Locale("de-DE")
+	if err != nil || messages.Project != "Projekt" {
+		t.Errorf("Expected German bundle for de-DE, got %+v, %v", messages.Project, err)
+	}
+	if messages, _ := Locale(""); messages.Project != "Project" {
+		t.Errorf("Expected English by default, got %q", messages.Project)
+	}
+	if _, err := Locale("xx"); err == nil {
+		t.Errorf("Expected error for unsupported locale")
+	}
+}
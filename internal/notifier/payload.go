@@ -0,0 +1,167 @@
+package notifier
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/terradrift-watcher/internal/textutil"
+)
+
+// Slack payload formats
+const (
+	// PayloadAttachments is the default rich message with colored attachments and fields
+	PayloadAttachments = "attachments"
+	// PayloadText sends only a markdown "text" field, accepted by every Slack-compatible webhook
+	PayloadText = "text"
+	// PayloadWorkflow sends flat string variables for Slack Workflow Builder webhooks
+	PayloadWorkflow = "workflow"
+	// PayloadChatbot sends an AWS Chatbot custom notification
+	PayloadChatbot = "chatbot"
+)
+
+// PayloadFormats lists the supported Slack payload formats
+var PayloadFormats = []string{PayloadAttachments, PayloadText, PayloadWorkflow, PayloadChatbot}
+
+// maxCompactPlanLength bounds the plan output in the compact payload formats
+const maxCompactPlanLength = 1500
+
+// chatbotNotification is the AWS Chatbot custom notification schema
+type chatbotNotification struct {
+	Version string         `json:"version"`
+	Source  string         `json:"source"`
+	Content chatbotContent `json:"content"`
+}
+
+type chatbotContent struct {
+	TextType    string   `json:"textType"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	NextSteps   []string `json:"nextSteps,omitempty"`
+}
+
+// slackPayload builds the message for a Slack webhook in the notifier's payload format
+func slackPayload(alert DriftAlert, opts HTTPOptions) (interface{}, error) {
+	msgs, err := Locale(opts.Locale)
+	if err != nil {
+		return nil, err
+	}
+
+	headline := fmt.Sprintf(msgs.EmailSubject, alert.Project)
+	if alert.Escalation != "" {
+		headline = fmt.Sprintf(msgs.EscalationSubject, alert.Escalation, alert.Project)
+	}
+	if alert.ErrorCategory != "" {
+		headline = fmt.Sprintf(msgs.FailureSubject, alert.ErrorCategory, alert.Project)
+	}
+	if len(alert.Warnings) > 0 {
+		headline = fmt.Sprintf(msgs.WarningSubject, alert.Project)
+	}
+	if len(alert.PolicyFindings) > 0 {
+		headline = fmt.Sprintf(msgs.PolicySubject, alert.Project)
+	}
+	icon := ":warning: "
+	if alert.Resolved {
+		headline = fmt.Sprintf(msgs.ResolvedSubject, alert.Project)
+		icon = ":white_check_mark: "
+	}
+
+	switch opts.PayloadFormat {
+	case "", PayloadAttachments:
+		msg := attachmentMessage(msgs, alert)
+		msg.Channel = opts.Channel
+		return msg, nil
+
+	case PayloadText:
+		return SlackMessage{Channel: opts.Channel, Text: fmt.Sprintf("*%s*\n%s", headline, compactDetails(msgs, alert))}, nil
+
+	case PayloadWorkflow:
+		// Workflow Builder only accepts top-level string variables
+		driftedFor := ""
+		if !alert.DriftSince.IsZero() {
+			driftedFor = time.Since(alert.DriftSince).Round(time.Minute).String()
+		}
+		return map[string]string{
+			"headline":    headline,
+			"project":     alert.Project,
+			"description": alert.Description,
+			"summary":     alert.Summary,
+			"owners":      strings.Join(alert.Owners, ", "),
+			"drifted_for": driftedFor,
+			"escalation":  alert.Escalation,
+			"runbook_url": alert.RunbookURL,
+			"fingerprint": alert.Fingerprint,
+			"incident":    alert.Incident,
+			"watcher":     alert.MetadataLine(),
+			"diff_url":    alert.DiffURL,
+			"plan_output": truncatePlan(alert.PlanOutput, msgs),
+		}, nil
+
+	case PayloadChatbot:
+		notification := chatbotNotification{
+			Version: "1.0",
+			Source:  "custom",
+			Content: chatbotContent{
+				TextType:    "client-markdown",
+				Title:       icon + headline,
+				Description: compactDetails(msgs, alert),
+			},
+		}
+		if alert.RunbookURL != "" {
+			notification.Content.NextSteps = []string{fmt.Sprintf("%s: %s", msgs.RunbookLink, alert.RunbookURL)}
+		}
+		return notification, nil
+	}
+
+	return nil, fmt.Errorf("unknown payload format '%s'", opts.PayloadFormat)
+}
+
+// compactDetails renders the alert details as markdown lines for the text-only formats,
+// with the plan in a code block
+func compactDetails(msgs Messages, alert DriftAlert) string {
+	var b strings.Builder
+	if alert.Description != "" {
+		fmt.Fprintf(&b, "%s\n", alert.Description)
+	}
+	if !alert.DriftSince.IsZero() {
+		if age := time.Since(alert.DriftSince); age >= time.Minute {
+			fmt.Fprintf(&b, "*%s:* %s\n", msgs.DriftedFor, age.Round(time.Minute))
+		}
+	}
+	if len(alert.Owners) > 0 {
+		fmt.Fprintf(&b, "*%s:* %s\n", msgs.Owner, strings.Join(alert.Owners, ", "))
+	}
+	for _, name := range alert.OutputNames() {
+		fmt.Fprintf(&b, "*%s:* %s\n", name, alert.Outputs[name])
+	}
+	if alert.DiffURL != "" {
+		fmt.Fprintf(&b, "*%s:* %s\n", msgs.HTMLDiff, alert.DiffURL)
+	}
+	if alert.Incident != "" {
+		fmt.Fprintf(&b, "*%s:* %s\n", msgs.Incident, alert.Incident)
+	}
+	if alert.Fingerprint != "" {
+		fmt.Fprintf(&b, "*%s:* `%s`\n", msgs.Fingerprint, alert.Fingerprint)
+	}
+	if len(alert.Metadata) > 0 {
+		fmt.Fprintf(&b, "*%s:* %s\n", msgs.Watcher, alert.MetadataLine())
+	}
+	if links := chatResourceLinks(alert, func(l ResourceLink) string {
+		return fmt.Sprintf("• %s: %s", l.Address, l.URL)
+	}); links != "" {
+		fmt.Fprintf(&b, "*%s:*\n%s\n", msgs.Resources, links)
+	}
+	fmt.Fprintf(&b, "%s\n", alert.Summary)
+	if plan := truncatePlan(alert.PlanOutput, msgs); plan != "" {
+		fmt.Fprintf(&b, "```\n%s\n```\n", plan)
+	}
+	return b.String()
+}
+
+// truncatePlan shortens plan output for the compact payload formats
+func truncatePlan(plan string, msgs Messages) string {
+	if len(plan) > maxCompactPlanLength {
+		return textutil.Truncate(plan, maxCompactPlanLength) + "\n" + msgs.Truncated
+	}
+	return plan
+}
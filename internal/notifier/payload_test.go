@@ -0,0 +1,186 @@
+package notifier
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/terraform"
+)
+
+func TestPayloadFormatsMatchConfig(t *testing.T) {
+	if !reflect.DeepEqual(PayloadFormats, config.SlackPayloadFormats) {
+		t.Errorf("Payload formats %v do not match those accepted by config %v", PayloadFormats, config.SlackPayloadFormats)
+	}
+}
+
+func TestSlackPayloadChannel(t *testing.T) {
+	for _, format := range []string{PayloadAttachments, PayloadText} {
+		payload, err := slackPayload(DriftAlert{Project: "network"}, HTTPOptions{PayloadFormat: format, Channel: "#drift-network"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if msg, ok := payload.(SlackMessage); !ok || msg.Channel != "#drift-network" {
+			t.Errorf("Expected the %s message to override the channel, got %+v", format, payload)
+		}
+	}
+}
+
+func TestSlackPayload(t *testing.T) {
+	alert := DriftAlert{
+		Project:    "network",
+		Summary:    "Plan: 0 to add, 1 to change, 0 to destroy.",
+		PlanOutput: "  ~ tags = {}",
+		Owners:     []string{"platform"},
+		RunbookURL: "https://wiki.example.com/network",
+	}
+
+	for _, format := range PayloadFormats {
+		payload, err := slackPayload(alert, HTTPOptions{PayloadFormat: format})
+		if err != nil {
+			t.Fatalf("slackPayload(%s) returned error: %v", format, err)
+		}
+		data, _ := json.Marshal(payload)
+		var decoded map[string]interface{}
+		json.Unmarshal(data, &decoded)
+
+		switch format {
+		case PayloadText:
+			if len(decoded) != 1 || !strings.Contains(decoded["text"].(string), "~ tags = {}") {
+				t.Errorf("Expected a single text field with the plan, got %s", data)
+			}
+		case PayloadWorkflow:
+			for key, value := range decoded {
+				if _, ok := value.(string); !ok {
+					t.Errorf("Workflow variable %s is not a string: %v", key, value)
+				}
+			}
+			if decoded["owners"] != "platform" {
+				t.Errorf("Expected owners variable, got %s", data)
+			}
+		case PayloadChatbot:
+			content, _ := decoded["content"].(map[string]interface{})
+			if decoded["source"] != "custom" || content["textType"] != "client-markdown" {
+				t.Errorf("Expected an AWS Chatbot custom notification, got %s", data)
+			}
+		case PayloadAttachments:
+			if _, ok := decoded["attachments"]; !ok {
+				t.Errorf("Expected attachments in the default format, got %s", data)
+			}
+		}
+	}
+
+	if _, err := slackPayload(alert, HTTPOptions{PayloadFormat: "blocks"}); err == nil {
+		t.Errorf("Expected error for unknown payload format")
+	}
+}
+
+func TestFailureAlertPayload(t *testing.T) {
+	alert := DriftAlert{Project: "network", Summary: "terraform plan failed: exit status 1", ErrorCategory: "auth"}
+
+	payload, err := slackPayload(alert, HTTPOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := payload.(SlackMessage)
+	if !strings.Contains(msg.Text, "Failed (auth)") || msg.Attachments[0].Fields[1].Value != "Check Failed" {
+		t.Errorf("Expected a failure headline and status, got %+v", msg)
+	}
+
+	if ev := NewDriftEvent(alert); ev.Type != "scan.failed" || ev.ErrorCategory != "auth" {
+		t.Errorf("Expected a scan.failed event with the category, got %+v", ev)
+	}
+}
+
+func TestTruncatedPlanStaysValidUTF8(t *testing.T) {
+	// The cut of every format falls inside a multi-byte character
+	plan := "x" + strings.Repeat("ü", 3000)
+	alert := DriftAlert{Project: "network", Summary: "Plan: 0 to add, 1 to change, 0 to destroy.", PlanOutput: plan}
+
+	for _, format := range []string{PayloadAttachments, PayloadText} {
+		payload, err := slackPayload(alert, HTTPOptions{PayloadFormat: format})
+		if err != nil {
+			t.Fatal(err)
+		}
+		msg := payload.(SlackMessage)
+		text := msg.Text
+		if len(msg.Attachments) > 1 {
+			text = msg.Attachments[1].Text
+		}
+		if !utf8.ValidString(text) || !strings.Contains(text, "truncated") {
+			t.Errorf("Expected a valid truncated plan in the %s format, got %q", format, text[len(text)-40:])
+		}
+	}
+	if content := zulipContent(mustLocale(t), alert); !utf8.ValidString(content) {
+		t.Error("Expected valid UTF-8 in the Zulip message")
+	}
+}
+
+// mustLocale returns the default locale's messages
+func mustLocale(t *testing.T) Messages {
+	msgs, err := Locale("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return msgs
+}
+
+func TestResourceLinks(t *testing.T) {
+	bucket := "https://console.aws.amazon.com/go/view?arn=arn%3Aaws%3As3%3A%3A%3Alogs"
+	alert := DriftAlert{
+		Project: "network",
+		Summary: "Plan: 0 to add, 2 to change, 0 to destroy.",
+		Changes: []terraform.AttributeChange{
+			{Address: "aws_s3_bucket.logs", Action: "update", Attribute: "tags.Owner", URL: bucket},
+			{Address: "aws_s3_bucket.logs", Action: "update", Attribute: "versioning", URL: bucket},
+			{Address: "aws_route.default", Action: "update", Attribute: "gateway_id"},
+		},
+	}
+	if links := alert.ResourceLinks(); len(links) != 1 || links[0].Address != "aws_s3_bucket.logs" || links[0].URL != bucket {
+		t.Fatalf("Expected one link per linked resource, got %+v", links)
+	}
+
+	payload, err := slackPayload(alert, HTTPOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, field := range payload.(SlackMessage).Attachments[0].Fields {
+		found = found || (field.Title == "Affected Resources" && field.Value == "<"+bucket+"|aws_s3_bucket.logs>")
+	}
+	if !found {
+		t.Errorf("Expected a Slack field linking the bucket, got %+v", payload)
+	}
+
+	msgs, _ := Locale("")
+	if content := zulipContent(msgs, alert); !strings.Contains(content, "- [aws_s3_bucket.logs]("+bucket+")") {
+		t.Errorf("Expected a markdown link to the bucket, got:\n%s", content)
+	}
+}
+
+func TestSlackRunbook(t *testing.T) {
+	alert := DriftAlert{
+		Project:     "network",
+		Summary:     "Plan: 0 to add, 1 to change, 0 to destroy.",
+		Description: "Shared VPC of the production accounts",
+		RunbookURL:  "https://wiki.example.com/network",
+	}
+	payload, err := slackPayload(alert, HTTPOptions{PayloadFormat: PayloadAttachments})
+	if err != nil {
+		t.Fatal(err)
+	}
+	attachment := payload.(SlackMessage).Attachments[0]
+	if !strings.HasPrefix(attachment.Text, "Shared VPC of the production accounts\n\n") {
+		t.Errorf("Expected the description to lead the summary, got %q", attachment.Text)
+	}
+	found := false
+	for _, field := range attachment.Fields {
+		found = found || field.Value == "<https://wiki.example.com/network|Remediation instructions>"
+	}
+	if !found {
+		t.Errorf("Expected a runbook link, got %+v", attachment.Fields)
+	}
+}
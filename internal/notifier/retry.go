@@ -0,0 +1,90 @@
+package notifier
+
+import (
+	"fmt"
+	"log"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// DefaultRetryBudget is the number of retries all notifiers may make together in one run
+const DefaultRetryBudget = 20
+
+// Backoff bounds for retries
+const (
+	baseBackoff = 1 * time.Second
+	maxBackoff  = 30 * time.Second
+)
+
+// retryBudget caps the retries made across all notifiers, so a webhook outage while many
+// projects drift at once costs a bounded number of requests instead of a retry storm
+var retryBudget = struct {
+	sync.Mutex
+	remaining int
+	limited   bool
+}{}
+
+// SetRetryBudget limits the retries all notifiers may make from now on. A negative value
+// removes the limit.
+func SetRetryBudget(retries int) {
+	retryBudget.Lock()
+	defer retryBudget.Unlock()
+	retryBudget.remaining = retries
+	retryBudget.limited = retries >= 0
+}
+
+// takeRetry consumes one retry from the shared budget, reporting false once it is exhausted
+func takeRetry() bool {
+	retryBudget.Lock()
+	defer retryBudget.Unlock()
+	if !retryBudget.limited {
+		return true
+	}
+	if retryBudget.remaining <= 0 {
+		return false
+	}
+	retryBudget.remaining--
+	return true
+}
+
+// backoff returns the delay before a retry: exponential in the attempt number, capped, with
+// the upper half randomized so concurrent senders do not retry in lockstep
+func backoff(attempt int) time.Duration {
+	delay := maxBackoff
+	if attempt <= 5 {
+		delay = baseBackoff << uint(attempt-1)
+		if delay > maxBackoff {
+			delay = maxBackoff
+		}
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// withRetry calls send until it succeeds, maxRetries is reached, or the shared budget runs out
+func withRetry(name string, maxRetries int, send func() error) error {
+	var lastErr error
+
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		if attempt > 0 {
+			if !takeRetry() {
+				log.Printf("WARNING: Notification retry budget exhausted, not retrying %s", name)
+				return fmt.Errorf("failed after %d attempts (retry budget exhausted): %w", attempt, lastErr)
+			}
+			delay := backoff(attempt)
+			log.Printf("INFO: Retrying %s (attempt %d/%d) after %v", name, attempt, maxRetries, delay.Round(time.Millisecond))
+			time.Sleep(delay)
+		}
+
+		err := send()
+		if err == nil {
+			if attempt > 0 {
+				log.Printf("INFO: %s succeeded on attempt %d", name, attempt+1)
+			}
+			return nil
+		}
+		lastErr = err
+	}
+
+	return fmt.Errorf("failed after %d retries: %w", maxRetries+1, lastErr)
+}
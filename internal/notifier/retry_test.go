@@ -0,0 +1,35 @@
+package notifier
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	for attempt, base := range map[int]time.Duration{1: time.Second, 3: 4 * time.Second, 6: maxBackoff, 40: maxBackoff} {
+		for i := 0; i < 20; i++ {
+			delay := backoff(attempt)
+			if delay < base/2 || delay > base {
+				t.Errorf("backoff(%d) = %v, expected between %v and %v", attempt, delay, base/2, base)
+			}
+		}
+	}
+}
+
+func TestWithRetry_BudgetExhausted(t *testing.T) {
+	SetRetryBudget(0)
+	defer SetRetryBudget(-1)
+
+	calls := 0
+	err := withRetry("test notification", 3, func() error {
+		calls++
+		return errors.New("endpoint down")
+	})
+	if err == nil {
+		t.Fatalf("Expected an error when every attempt fails")
+	}
+	if calls != 1 {
+		t.Errorf("Expected no retries with an empty budget, got %d calls", calls)
+	}
+}
@@ -1,16 +1,18 @@
 package notifier
 
 import (
-	"bytes"
 	"encoding/json"
 	"fmt"
-	"log"
 	"net/http"
+	"strings"
 	"time"
+
+	"github.com/terradrift-watcher/internal/textutil"
 )
 
 // SlackMessage represents a basic Slack webhook message
 type SlackMessage struct {
+	Channel     string       `json:"channel,omitempty"`
 	Text        string       `json:"text"`
 	Username    string       `json:"username,omitempty"`
 	IconEmoji   string       `json:"icon_emoji,omitempty"`
@@ -59,18 +61,17 @@ func SendSlackNotification(webhookURL string, message string) error {
 	}
 
 	// Create HTTP client with timeout
-	client := &http.Client{
-		Timeout: 10 * time.Second,
+	client, err := NewHTTPClient(HTTPOptions{})
+	if err != nil {
+		return err
 	}
 
 	// Create the request
-	req, err := http.NewRequest("POST", webhookURL, bytes.NewBuffer(jsonData))
+	req, err := newJSONRequest(webhookURL, jsonData, HTTPOptions{})
 	if err != nil {
-		return fmt.Errorf("failed to create HTTP request: %w", err)
+		return err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-
 	// Send the request
 	resp, err := client.Do(req)
 	if err != nil {
@@ -88,35 +89,122 @@ func SendSlackNotification(webhookURL string, message string) error {
 
 // SendSlackRichNotification sends a rich formatted notification to Slack
 func SendSlackRichNotification(webhookURL string, projectName string, driftSummary string, planOutput string) error {
+	return SendSlackAlert(webhookURL, DriftAlert{Project: projectName, Summary: driftSummary, PlanOutput: planOutput}, HTTPOptions{})
+}
+
+// SlackAlertPayload renders the request body for a Slack drift alert, from the notifier's
+// custom template if it has one, otherwise in its payload format
+func SlackAlertPayload(alert DriftAlert, opts HTTPOptions) ([]byte, error) {
+	if opts.Template != "" {
+		return renderTemplate(opts.Template, alert)
+	}
+
+	payload, err := slackPayload(alert, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Marshal the message to JSON
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal Slack message: %w", err)
+	}
+	return jsonData, nil
+}
+
+// SendSlackAlert sends a rich formatted drift alert to Slack
+func SendSlackAlert(webhookURL string, alert DriftAlert, opts HTTPOptions) error {
 	if webhookURL == "" {
 		return fmt.Errorf("webhook URL is empty")
 	}
 
+	jsonData, err := SlackAlertPayload(alert, opts)
+	if err != nil {
+		return err
+	}
+
+	// Create HTTP client with timeout and the notifier's TLS settings
+	client, err := NewHTTPClient(opts)
+	if err != nil {
+		return err
+	}
+
+	// Create the request with the notifier's user agent, headers and signature
+	req, err := newJSONRequest(webhookURL, jsonData, opts)
+	if err != nil {
+		return err
+	}
+
+	// Send the request
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send Slack notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Check the response status
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Slack webhook returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// SendSlackNotificationWithRetry sends a Slack notification with retry logic
+func SendSlackNotificationWithRetry(webhookURL string, message string, maxRetries int) error {
+	return withRetry("Slack notification", maxRetries, func() error {
+		return SendSlackNotification(webhookURL, message)
+	})
+}
+
+// SendSlackRichNotificationWithRetry sends a rich Slack notification with retry logic
+func SendSlackRichNotificationWithRetry(webhookURL string, projectName string, driftSummary string, planOutput string, maxRetries int) error {
+	return SendSlackAlertWithRetry(webhookURL, DriftAlert{Project: projectName, Summary: driftSummary, PlanOutput: planOutput}, HTTPOptions{}, maxRetries)
+}
+
+// SendSlackAlertWithRetry sends a rich Slack drift alert with retry logic
+func SendSlackAlertWithRetry(webhookURL string, alert DriftAlert, opts HTTPOptions, maxRetries int) error {
+	return withRetry("Slack rich notification", maxRetries, func() error {
+		return SendSlackAlert(webhookURL, alert, opts)
+	})
+}
+
+// attachmentMessage builds the default rich Slack message with attachments
+func attachmentMessage(msgs Messages, alert DriftAlert) SlackMessage {
+	projectName := alert.Project
+	driftSummary := alert.Summary
+	planOutput := alert.PlanOutput
+
 	// Truncate plan output if it's too long
 	const maxPlanLength = 2000
 	if len(planOutput) > maxPlanLength {
-		planOutput = planOutput[:maxPlanLength] + "\n... (truncated)"
+		planOutput = textutil.Truncate(planOutput, maxPlanLength) + "\n" + msgs.Truncated
+	}
+
+	// Lead with the project description so responders know what is affected
+	if alert.Description != "" {
+		driftSummary = alert.Description + "\n\n" + driftSummary
 	}
 
 	// Create a rich Slack message with attachments
 	slackMsg := SlackMessage{
-		Text:      fmt.Sprintf(":rotating_light: *Drift Detected in Project: %s*", projectName),
+		Text:      fmt.Sprintf(msgs.AlertHeadline, projectName),
 		Username:  "TerraDrift Watcher",
 		IconEmoji: ":warning:",
 		Attachments: []Attachment{
 			{
 				Color: "danger",
-				Title: "Configuration Drift Alert",
+				Title: msgs.AlertTitle,
 				Text:  driftSummary,
 				Fields: []Field{
 					{
-						Title: "Project",
+						Title: msgs.Project,
 						Value: projectName,
 						Short: true,
 					},
 					{
-						Title: "Status",
-						Value: "Drift Detected",
+						Title: msgs.Status,
+						Value: msgs.StatusDrifted,
 						Short: true,
 					},
 				},
@@ -126,92 +214,125 @@ func SendSlackRichNotification(webhookURL string, projectName string, driftSumma
 			},
 			{
 				Color: "warning",
-				Title: "Plan Output",
+				Title: msgs.PlanOutput,
 				Text:  "```" + planOutput + "```",
 			},
 		},
 	}
 
-	// Marshal the message to JSON
-	jsonData, err := json.Marshal(slackMsg)
-	if err != nil {
-		return fmt.Errorf("failed to marshal Slack message: %w", err)
+	if alert.Escalation != "" {
+		slackMsg.Text = fmt.Sprintf(msgs.EscalationHeadline, alert.Escalation, projectName)
 	}
-
-	// Create HTTP client with timeout
-	client := &http.Client{
-		Timeout: 10 * time.Second,
+	if alert.ErrorCategory != "" {
+		slackMsg.Text = fmt.Sprintf(msgs.FailureHeadline, alert.ErrorCategory, projectName)
+		slackMsg.Attachments[0].Title = fmt.Sprintf(msgs.FailureSubject, alert.ErrorCategory, projectName)
+		slackMsg.Attachments[0].Fields[1].Value = msgs.StatusFailed
 	}
-
-	// Create the request
-	req, err := http.NewRequest("POST", webhookURL, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return fmt.Errorf("failed to create HTTP request: %w", err)
+	if len(alert.Warnings) > 0 {
+		slackMsg.Text = fmt.Sprintf(msgs.WarningHeadline, projectName)
+		slackMsg.Attachments[0].Title = fmt.Sprintf(msgs.WarningSubject, projectName)
+		slackMsg.Attachments[0].Color = "warning"
+		slackMsg.Attachments[0].Fields[1].Value = msgs.StatusWarnings
+	}
+	if len(alert.PolicyFindings) > 0 {
+		slackMsg.Text = fmt.Sprintf(msgs.PolicyHeadline, projectName)
+		slackMsg.Attachments[0].Title = fmt.Sprintf(msgs.PolicySubject, projectName)
+		slackMsg.Attachments[0].Color = "warning"
+		slackMsg.Attachments[0].Fields[1].Value = msgs.StatusPolicy
+	}
+	if alert.Resolved {
+		slackMsg.Text = fmt.Sprintf(msgs.ResolvedHeadline, projectName)
+		slackMsg.Attachments[0].Title = fmt.Sprintf(msgs.ResolvedSubject, projectName)
+		slackMsg.Attachments[0].Color = "good"
+		slackMsg.Attachments[0].Fields[1].Value = msgs.StatusResolved
+		// Nothing is planned any more, so there is no plan output to show
+		slackMsg.Attachments = slackMsg.Attachments[:1]
 	}
 
-	req.Header.Set("Content-Type", "application/json")
+	// Name the incident so every message about the same drift can be matched up
+	if alert.Incident != "" {
+		slackMsg.Attachments[0].Fields = append(slackMsg.Attachments[0].Fields, Field{
+			Title: msgs.Incident,
+			Value: alert.Incident,
+			Short: true,
+		})
+	}
 
-	// Send the request
-	resp, err := client.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to send Slack notification: %w", err)
+	// Show how long the drift has gone unresolved
+	if !alert.DriftSince.IsZero() {
+		if age := time.Since(alert.DriftSince); age >= time.Minute {
+			slackMsg.Attachments[0].Fields = append(slackMsg.Attachments[0].Fields, Field{
+				Title: msgs.DriftedFor,
+				Value: age.Round(time.Minute).String(),
+				Short: true,
+			})
+		}
 	}
-	defer resp.Body.Close()
 
-	// Check the response status
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("Slack webhook returned status %d", resp.StatusCode)
+	// Include the owning teams so the right people pick up the alert
+	if len(alert.Owners) > 0 {
+		slackMsg.Attachments[0].Fields = append(slackMsg.Attachments[0].Fields, Field{
+			Title: msgs.Owner,
+			Value: strings.Join(alert.Owners, ", "),
+			Short: true,
+		})
 	}
 
-	return nil
-}
+	// Show the project's captured outputs for context, e.g. which cluster it is
+	for _, name := range alert.OutputNames() {
+		slackMsg.Attachments[0].Fields = append(slackMsg.Attachments[0].Fields, Field{
+			Title: name,
+			Value: alert.Outputs[name],
+			Short: true,
+		})
+	}
 
-// SendSlackNotificationWithRetry sends a Slack notification with retry logic
-func SendSlackNotificationWithRetry(webhookURL string, message string, maxRetries int) error {
-	var lastErr error
-
-	for attempt := 0; attempt <= maxRetries; attempt++ {
-		if attempt > 0 {
-			// Exponential backoff: 1s, 2s, 4s, etc.
-			backoff := time.Duration(1<<uint(attempt-1)) * time.Second
-			log.Printf("INFO: Retrying Slack notification (attempt %d/%d) after %v", attempt, maxRetries, backoff)
-			time.Sleep(backoff)
-		}
+	// Link the drifted resources so responders can jump straight to them
+	if links := chatResourceLinks(alert, func(l ResourceLink) string {
+		return fmt.Sprintf("<%s|%s>", l.URL, l.Address)
+	}); links != "" {
+		slackMsg.Attachments[0].Fields = append(slackMsg.Attachments[0].Fields, Field{
+			Title: msgs.Resources,
+			Value: links,
+			Short: false,
+		})
+	}
 
-		err := SendSlackNotification(webhookURL, message)
-		if err == nil {
-			if attempt > 0 {
-				log.Printf("INFO: Slack notification succeeded on attempt %d", attempt+1)
-			}
-			return nil
-		}
-		lastErr = err
+	// Link to the project's remediation instructions
+	if alert.RunbookURL != "" {
+		slackMsg.Attachments[0].Fields = append(slackMsg.Attachments[0].Fields, Field{
+			Title: msgs.Runbook,
+			Value: fmt.Sprintf("<%s|%s>", alert.RunbookURL, msgs.RunbookLink),
+			Short: false,
+		})
 	}
 
-	return fmt.Errorf("failed after %d retries: %w", maxRetries+1, lastErr)
-}
+	// Link the readable diff for stakeholders who do not use the terraform CLI
+	if alert.DiffURL != "" {
+		slackMsg.Attachments[0].Fields = append(slackMsg.Attachments[0].Fields, Field{
+			Title: msgs.HTMLDiff,
+			Value: fmt.Sprintf("<%s|%s>", alert.DiffURL, alert.Project),
+			Short: false,
+		})
+	}
 
-// SendSlackRichNotificationWithRetry sends a rich Slack notification with retry logic
-func SendSlackRichNotificationWithRetry(webhookURL string, projectName string, driftSummary string, planOutput string, maxRetries int) error {
-	var lastErr error
-
-	for attempt := 0; attempt <= maxRetries; attempt++ {
-		if attempt > 0 {
-			// Exponential backoff: 1s, 2s, 4s, etc.
-			backoff := time.Duration(1<<uint(attempt-1)) * time.Second
-			log.Printf("INFO: Retrying Slack rich notification (attempt %d/%d) after %v", attempt, maxRetries, backoff)
-			time.Sleep(backoff)
-		}
+	// Name the watcher instance so alerts from several deployments can be told apart
+	if len(alert.Metadata) > 0 {
+		slackMsg.Attachments[0].Fields = append(slackMsg.Attachments[0].Fields, Field{
+			Title: msgs.Watcher,
+			Value: alert.MetadataLine(),
+			Short: false,
+		})
+	}
 
-		err := SendSlackRichNotification(webhookURL, projectName, driftSummary, planOutput)
-		if err == nil {
-			if attempt > 0 {
-				log.Printf("INFO: Slack rich notification succeeded on attempt %d", attempt+1)
-			}
-			return nil
-		}
-		lastErr = err
+	// Identify the drift so responders can match it to other channels and the history
+	if alert.Fingerprint != "" {
+		slackMsg.Attachments[0].Fields = append(slackMsg.Attachments[0].Fields, Field{
+			Title: msgs.Fingerprint,
+			Value: "`" + alert.Fingerprint + "`",
+			Short: true,
+		})
 	}
 
-	return fmt.Errorf("failed after %d retries: %w", maxRetries+1, lastErr)
+	return slackMsg
 }
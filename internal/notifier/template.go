@@ -0,0 +1,104 @@
+package notifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/terradrift-watcher/pkg/event"
+)
+
+// TemplateData is what a custom notifier template is rendered with: the DriftEvent sent to
+// webhook consumers plus the raw plan output
+type TemplateData struct {
+	event.DriftEvent
+	PlanOutput string
+}
+
+// templateFuncs are available in custom notifier templates
+var templateFuncs = template.FuncMap{
+	// json renders a value as JSON, so strings are safely quoted inside JSON payloads
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	"join": strings.Join,
+	"truncate": func(n int, s string) string {
+		if len(s) <= n {
+			return s
+		}
+		return s[:n] + "..."
+	},
+	"since": func(t *time.Time) string {
+		if t == nil {
+			return ""
+		}
+		return time.Since(*t).Round(time.Minute).String()
+	},
+}
+
+// LoadTemplate parses a custom notifier template file
+func LoadTemplate(path string) (*template.Template, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template: %w", err)
+	}
+	tmpl, err := template.New(path).Funcs(templateFuncs).Option("missingkey=error").Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template %s: %w", path, err)
+	}
+	return tmpl, nil
+}
+
+// renderTemplate renders an alert with the custom template at path
+func renderTemplate(path string, alert DriftAlert) ([]byte, error) {
+	tmpl, err := LoadTemplate(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	data := TemplateData{DriftEvent: NewDriftEvent(alert), PlanOutput: alert.PlanOutput}
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("failed to render template %s: %w", path, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// AlertFromEvent rebuilds an alert from a recorded DriftEvent, e.g. to preview templates
+func AlertFromEvent(ev event.DriftEvent) DriftAlert {
+	alert := DriftAlert{
+		Project:     ev.Project,
+		Summary:     ev.Summary,
+		Fingerprint: ev.Fingerprint,
+		Incident:    ev.Incident,
+		Resolved:    ev.Type == event.TypeDriftResolved,
+		Owners:      ev.Owners,
+		Tags:        ev.Tags,
+		Description: ev.Description,
+		RunbookURL:  ev.RunbookURL,
+		Outputs:     ev.Outputs,
+		Escalation:  ev.Escalation,
+
+		ErrorCategory: ev.ErrorCategory,
+		Metadata:      ev.Metadata,
+		DiffURL:       ev.DiffURL,
+	}
+	if ev.DriftSince != nil {
+		alert.DriftSince = *ev.DriftSince
+	}
+	for _, c := range ev.Changes {
+		alert.Changes = append(alert.Changes, changeFromEvent(c))
+	}
+	for _, w := range ev.Warnings {
+		alert.Warnings = append(alert.Warnings, warningFromEvent(w))
+	}
+	for _, f := range ev.PolicyFindings {
+		alert.PolicyFindings = append(alert.PolicyFindings, policyFindingFromEvent(f))
+	}
+	return alert
+}
@@ -0,0 +1,51 @@
+package notifier
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/terradrift-watcher/internal/terraform"
+)
+
+func TestWebhookPayloadTemplate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "alert.tmpl")
+	tmpl := `{"title": {{json .Project}}, "owners": {{json (join .Owners ", ")}}, "changes": {{len .Changes}}, "plan": {{json (truncate 4 .PlanOutput)}}}`
+	if err := os.WriteFile(path, []byte(tmpl), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	alert := DriftAlert{
+		Project:    `net "core"`,
+		Summary:    "Plan: 0 to add, 1 to change, 0 to destroy.",
+		PlanOutput: "~ aws_vpc.main",
+		Owners:     []string{"network", "sre"},
+		Changes:    []terraform.AttributeChange{{Address: "aws_vpc.main", Action: "update"}},
+	}
+	payload, err := WebhookPayload(alert, HTTPOptions{Template: path})
+	if err != nil {
+		t.Fatalf("WebhookPayload failed: %v", err)
+	}
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(payload, &got); err != nil {
+		t.Fatalf("Template output is not valid JSON: %v\n%s", err, payload)
+	}
+	if got["title"] != `net "core"` || got["owners"] != "network, sre" || got["changes"] != float64(1) || got["plan"] != "~ aw..." {
+		t.Errorf("Unexpected template output: %s", payload)
+	}
+}
+
+func TestTemplateMissingKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "alert.tmpl")
+	if err := os.WriteFile(path, []byte(`{{.Nope}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := WebhookPayload(DriftAlert{Project: "network"}, HTTPOptions{Template: path})
+	if err == nil || !strings.Contains(err.Error(), "alert.tmpl") {
+		t.Errorf("Expected a render error naming the template, got %v", err)
+	}
+}
@@ -0,0 +1,185 @@
+package notifier
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/terradrift-watcher/internal/terraform"
+	"github.com/terradrift-watcher/pkg/event"
+)
+
+// NewDriftEvent converts an alert into the versioned event sent to webhook and stdout notifiers
+func NewDriftEvent(alert DriftAlert) event.DriftEvent {
+	ev := event.DriftEvent{
+		SchemaVersion: event.SchemaVersion,
+		Type:          event.TypeDriftDetected,
+		Time:          time.Now().UTC(),
+		Project:       alert.Project,
+		Description:   alert.Description,
+		RunbookURL:    alert.RunbookURL,
+		Owners:        alert.Owners,
+		Tags:          alert.Tags,
+		Outputs:       alert.Outputs,
+		Summary:       alert.Summary,
+		Fingerprint:   alert.Fingerprint,
+		Incident:      alert.Incident,
+		Escalation:    alert.Escalation,
+		ErrorCategory: alert.ErrorCategory,
+		Metadata:      alert.Metadata,
+		DiffURL:       alert.DiffURL,
+	}
+	if alert.Escalation != "" {
+		ev.Type = event.TypeDriftEscalated
+	}
+	if alert.Resolved {
+		ev.Type = event.TypeDriftResolved
+	}
+	if alert.ErrorCategory != "" {
+		ev.Type = event.TypeScanFailed
+	}
+	if len(alert.Warnings) > 0 {
+		ev.Type = event.TypeScanWarnings
+	}
+	for _, w := range alert.Warnings {
+		ev.Warnings = append(ev.Warnings, event.Warning{Summary: w.Summary, Resource: w.Resource, Detail: w.Detail})
+	}
+	if len(alert.PolicyFindings) > 0 {
+		ev.Type = event.TypeScanPolicy
+	}
+	for _, f := range alert.PolicyFindings {
+		ev.PolicyFindings = append(ev.PolicyFindings, event.PolicyFinding{
+			Tool:     f.Tool,
+			Rule:     f.Rule,
+			Severity: f.Severity,
+			Resource: f.Resource,
+			File:     f.File,
+			Line:     f.Line,
+			Message:  f.Message,
+		})
+	}
+	if !alert.DriftSince.IsZero() {
+		since := alert.DriftSince.UTC()
+		ev.DriftSince = &since
+	}
+	for _, c := range alert.Changes {
+		ev.Changes = append(ev.Changes, event.Change{
+			Address:   c.Address,
+			Action:    c.Action,
+			Attribute: c.Attribute,
+			Before:    c.Before,
+			After:     c.After,
+			URL:       c.URL,
+		})
+	}
+	return ev
+}
+
+// changeFromEvent converts an event change back into a changelog entry
+func changeFromEvent(c event.Change) terraform.AttributeChange {
+	return terraform.AttributeChange{
+		Address:   c.Address,
+		Action:    c.Action,
+		Attribute: c.Attribute,
+		Before:    c.Before,
+		After:     c.After,
+		URL:       c.URL,
+	}
+}
+
+// warningFromEvent converts an event warning back into a terraform warning
+func warningFromEvent(w event.Warning) terraform.Warning {
+	return terraform.Warning{Summary: w.Summary, Resource: w.Resource, Detail: w.Detail}
+}
+
+// policyFindingFromEvent converts an event policy finding back into a terraform one
+func policyFindingFromEvent(f event.PolicyFinding) terraform.PolicyFinding {
+	return terraform.PolicyFinding{
+		Tool:     f.Tool,
+		Rule:     f.Rule,
+		Severity: f.Severity,
+		Resource: f.Resource,
+		File:     f.File,
+		Line:     f.Line,
+		Message:  f.Message,
+	}
+}
+
+// WebhookPayload renders the request body for a webhook notifier: the DriftEvent JSON, or the
+// notifier's custom template
+func WebhookPayload(alert DriftAlert, opts HTTPOptions) ([]byte, error) {
+	if opts.Template != "" {
+		return renderTemplate(opts.Template, alert)
+	}
+
+	payload, err := json.Marshal(NewDriftEvent(alert))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal drift event: %w", err)
+	}
+	return payload, nil
+}
+
+// SendWebhookEvent posts the alert as a DriftEvent to a generic JSON webhook
+func SendWebhookEvent(url string, alert DriftAlert, opts HTTPOptions) error {
+	if url == "" {
+		return fmt.Errorf("webhook URL is empty")
+	}
+
+	payload, err := WebhookPayload(alert, opts)
+	if err != nil {
+		return err
+	}
+	return postJSON(url, payload, opts)
+}
+
+// postJSON posts a JSON payload to a webhook, expecting a 2xx response
+func postJSON(url string, payload []byte, opts HTTPOptions) error {
+	client, err := NewHTTPClient(opts)
+	if err != nil {
+		return err
+	}
+	req, err := newJSONRequest(url, payload, opts)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send webhook event: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// SendWebhookEventWithRetry posts a DriftEvent with retry logic
+func SendWebhookEventWithRetry(url string, alert DriftAlert, opts HTTPOptions, maxRetries int) error {
+	return withRetry("webhook event", maxRetries, func() error {
+		return SendWebhookEvent(url, alert, opts)
+	})
+}
+
+// SendRunEvent posts a run lifecycle event to a run webhook
+func SendRunEvent(url string, ev event.RunEvent, opts HTTPOptions) error {
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		return fmt.Errorf("failed to marshal run event: %w", err)
+	}
+	return postJSON(url, payload, opts)
+}
+
+// SendRunEventWithRetry posts a run lifecycle event with retry logic
+func SendRunEventWithRetry(url string, ev event.RunEvent, opts HTTPOptions, maxRetries int) error {
+	return withRetry("run event", maxRetries, func() error {
+		return SendRunEvent(url, ev, opts)
+	})
+}
+
+// WriteEvent writes the alert as a single line of DriftEvent JSON, for stdout notifiers
+func WriteEvent(w io.Writer, alert DriftAlert) error {
+	return json.NewEncoder(w).Encode(NewDriftEvent(alert))
+}
@@ -0,0 +1,129 @@
+package notifier
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/terradrift-watcher/internal/terraform"
+	"github.com/terradrift-watcher/pkg/event"
+)
+
+func TestSendWebhookEvent(t *testing.T) {
+	var received event.DriftEvent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("Failed to decode event: %v", err)
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	alert := DriftAlert{
+		Project:    "network",
+		Summary:    "Plan: 0 to add, 1 to change, 0 to destroy.",
+		Escalation: "page-sre",
+		Changes:    []terraform.AttributeChange{{Address: "aws_vpc.main", Action: "update", Attribute: "tags.Env", Before: `"dev"`, After: `"prod"`}},
+	}
+	if err := SendWebhookEvent(server.URL, alert, HTTPOptions{}); err != nil {
+		t.Fatalf("SendWebhookEvent failed: %v", err)
+	}
+
+	if received.SchemaVersion != event.SchemaVersion {
+		t.Errorf("Expected schema version %s, got %q", event.SchemaVersion, received.SchemaVersion)
+	}
+	if received.Type != event.TypeDriftEscalated || received.Project != "network" {
+		t.Errorf("Unexpected event: %+v", received)
+	}
+	if len(received.Changes) != 1 || received.Changes[0].After != `"prod"` {
+		t.Errorf("Expected the changelog in the event, got %+v", received.Changes)
+	}
+	if received.DriftSince != nil {
+		t.Errorf("Expected no drift_since for an alert without one, got %v", received.DriftSince)
+	}
+}
+
+func TestWarningEvent(t *testing.T) {
+	alert := DriftAlert{
+		Project:  "network",
+		Summary:  "1 new terraform warning(s)",
+		Warnings: []terraform.Warning{{Summary: "Argument is deprecated", Resource: "aws_s3_bucket.logs"}},
+	}
+	ev := NewDriftEvent(alert)
+	if ev.Type != event.TypeScanWarnings || len(ev.Warnings) != 1 || ev.Warnings[0].Resource != "aws_s3_bucket.logs" {
+		t.Errorf("Expected a scan.warnings event with the warning, got %+v", ev)
+	}
+	if back := AlertFromEvent(ev); len(back.Warnings) != 1 || back.Warnings[0] != alert.Warnings[0] {
+		t.Errorf("Expected the warning back from the event, got %+v", back.Warnings)
+	}
+}
+
+func TestMetadataInEventAndMessages(t *testing.T) {
+	alert := DriftAlert{
+		Project:  "network",
+		Summary:  "Plan: 0 to add, 1 to change, 0 to destroy.",
+		Metadata: map[string]string{"environment": "prod", "cluster": "eu-1"},
+	}
+	ev := NewDriftEvent(alert)
+	if ev.Metadata["cluster"] != "eu-1" || AlertFromEvent(ev).Metadata["environment"] != "prod" {
+		t.Errorf("Expected the metadata in the event and back, got %v", ev.Metadata)
+	}
+	if got := alert.MetadataLine(); got != "cluster=eu-1, environment=prod" {
+		t.Errorf("Expected the metadata in key order, got %q", got)
+	}
+	if content := zulipContent(mustLocale(t), alert); !strings.Contains(content, "**Watcher:** cluster=eu-1, environment=prod") {
+		t.Errorf("Expected the watcher in the message, got:\n%s", content)
+	}
+}
+
+func TestSendRunEvent(t *testing.T) {
+	var received event.RunEvent
+	var signature, auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		signature = r.Header.Get(SignatureHeader)
+		auth = r.Header.Get("Authorization")
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("Failed to decode event: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	ev := event.RunEvent{
+		SchemaVersion: event.SchemaVersion,
+		Type:          event.TypeRunFinished,
+		Outcome:       event.RunSucceeded,
+		Counts:        map[string]int{"clean": 2, "drifted": 1},
+		Projects:      []event.RunProject{{Project: "network", Status: "drifted", Fingerprint: "abc123"}},
+	}
+	opts := HTTPOptions{SigningSecret: "s3cret", Headers: map[string]string{"Authorization": "Bearer t0ken"}}
+	if err := SendRunEvent(server.URL, ev, opts); err != nil {
+		t.Fatalf("SendRunEvent failed: %v", err)
+	}
+
+	if received.Type != event.TypeRunFinished || received.Counts["drifted"] != 1 || len(received.Projects) != 1 {
+		t.Errorf("Unexpected run event: %+v", received)
+	}
+	if signature == "" || auth != "Bearer t0ken" {
+		t.Errorf("Expected a signed request with the configured headers, got signature %q and auth %q", signature, auth)
+	}
+}
+
+func TestResolvedEvent(t *testing.T) {
+	alert := DriftAlert{Project: "network", Summary: "Drift resolved after 4h0m0s.", Incident: "DRIFT-1a2b3c4d", Resolved: true}
+	ev := NewDriftEvent(alert)
+	if ev.Type != event.TypeDriftResolved || ev.Incident != "DRIFT-1a2b3c4d" {
+		t.Errorf("Expected a drift.resolved event of the incident, got %+v", ev)
+	}
+	if back := AlertFromEvent(ev); !back.Resolved || back.Incident != alert.Incident {
+		t.Errorf("Expected the incident back from the event, got %+v", back)
+	}
+	payload, err := SlackAlertPayload(alert, HTTPOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(payload), "DRIFT-1a2b3c4d") || !strings.Contains(string(payload), `"good"`) {
+		t.Errorf("Expected a green Slack message naming the incident, got %s", payload)
+	}
+}
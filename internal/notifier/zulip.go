@@ -0,0 +1,169 @@
+package notifier
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/terradrift-watcher/internal/textutil"
+)
+
+// DefaultZulipTopic is the topic drift is posted under; {project} is replaced by the project name
+const DefaultZulipTopic = "drift: {project}"
+
+// maxZulipTopicLength is Zulip's limit on topic names
+const maxZulipTopicLength = 60
+
+// ZulipConfig holds the settings of a Zulip notifier
+type ZulipConfig struct {
+	Site     string // e.g. https://example.zulipchat.com
+	BotEmail string
+	APIKey   string
+	Stream   string
+	Topic    string // Default DefaultZulipTopic, giving each project its own thread
+}
+
+// zulipTopic returns the topic for a project, keeping each project's drift in one thread
+func zulipTopic(template string, project string) string {
+	if template == "" {
+		template = DefaultZulipTopic
+	}
+	topic := strings.ReplaceAll(template, "{project}", project)
+	if runes := []rune(topic); len(runes) > maxZulipTopicLength {
+		topic = string(runes[:maxZulipTopicLength-1]) + "…"
+	}
+	return topic
+}
+
+// zulipContent renders a drift alert as Zulip markdown
+func zulipContent(msgs Messages, alert DriftAlert) string {
+	var b strings.Builder
+	headline := fmt.Sprintf(msgs.EmailSubject, alert.Project)
+	if alert.Escalation != "" {
+		headline = fmt.Sprintf(msgs.EscalationSubject, alert.Escalation, alert.Project)
+	}
+	if alert.ErrorCategory != "" {
+		headline = fmt.Sprintf(msgs.FailureSubject, alert.ErrorCategory, alert.Project)
+	}
+	if len(alert.Warnings) > 0 {
+		headline = fmt.Sprintf(msgs.WarningSubject, alert.Project)
+	}
+	if len(alert.PolicyFindings) > 0 {
+		headline = fmt.Sprintf(msgs.PolicySubject, alert.Project)
+	}
+	icon := ":warning:"
+	if alert.Resolved {
+		headline = fmt.Sprintf(msgs.ResolvedSubject, alert.Project)
+		icon = ":check:"
+	}
+	fmt.Fprintf(&b, "%s **%s**\n\n", icon, headline)
+	if alert.Description != "" {
+		fmt.Fprintf(&b, "%s\n\n", alert.Description)
+	}
+	if !alert.DriftSince.IsZero() {
+		if age := time.Since(alert.DriftSince); age >= time.Minute {
+			fmt.Fprintf(&b, "**%s:** %s\n", msgs.DriftedFor, age.Round(time.Minute))
+		}
+	}
+	if len(alert.Owners) > 0 {
+		fmt.Fprintf(&b, "**%s:** %s\n", msgs.Owner, strings.Join(alert.Owners, ", "))
+	}
+	for _, name := range alert.OutputNames() {
+		fmt.Fprintf(&b, "**%s:** %s\n", name, alert.Outputs[name])
+	}
+	if alert.RunbookURL != "" {
+		fmt.Fprintf(&b, "**%s:** [%s](%s)\n", msgs.Runbook, msgs.RunbookLink, alert.RunbookURL)
+	}
+	if alert.DiffURL != "" {
+		fmt.Fprintf(&b, "**%s:** [%s](%s)\n", msgs.HTMLDiff, alert.Project, alert.DiffURL)
+	}
+	if alert.Incident != "" {
+		fmt.Fprintf(&b, "**%s:** %s\n", msgs.Incident, alert.Incident)
+	}
+	if alert.Fingerprint != "" {
+		fmt.Fprintf(&b, "**%s:** `%s`\n", msgs.Fingerprint, alert.Fingerprint)
+	}
+	if len(alert.Metadata) > 0 {
+		fmt.Fprintf(&b, "**%s:** %s\n", msgs.Watcher, alert.MetadataLine())
+	}
+	if links := chatResourceLinks(alert, func(l ResourceLink) string {
+		return fmt.Sprintf("- [%s](%s)", l.Address, l.URL)
+	}); links != "" {
+		fmt.Fprintf(&b, "**%s:**\n%s\n", msgs.Resources, links)
+	}
+	fmt.Fprintf(&b, "\n%s\n", alert.Summary)
+
+	if plan := alert.PlanOutput; plan != "" {
+		const maxPlanLength = 5000
+		if len(plan) > maxPlanLength {
+			plan = textutil.Truncate(plan, maxPlanLength) + "\n" + msgs.Truncated
+		}
+		// Collapse the plan under a spoiler so long diffs do not flood the topic
+		fmt.Fprintf(&b, "\n````spoiler %s\n```diff\n%s\n```\n````\n", msgs.PlanOutput, plan)
+	}
+	return b.String()
+}
+
+// ZulipContent renders the markdown message for a Zulip drift alert, from the notifier's
+// custom template if it has one
+func ZulipContent(alert DriftAlert, opts HTTPOptions) (string, error) {
+	if opts.Template != "" {
+		content, err := renderTemplate(opts.Template, alert)
+		return string(content), err
+	}
+
+	msgs, err := Locale(opts.Locale)
+	if err != nil {
+		return "", err
+	}
+	return zulipContent(msgs, alert), nil
+}
+
+// SendZulipAlert posts a drift alert to the project's topic in a Zulip stream
+func SendZulipAlert(cfg ZulipConfig, alert DriftAlert, opts HTTPOptions) error {
+	if cfg.Site == "" || cfg.BotEmail == "" || cfg.APIKey == "" || cfg.Stream == "" {
+		return fmt.Errorf("zulip site, bot_email, api_key and stream are required")
+	}
+
+	content, err := ZulipContent(alert, opts)
+	if err != nil {
+		return err
+	}
+
+	form := url.Values{
+		"type":    {"stream"},
+		"to":      {cfg.Stream},
+		"topic":   {zulipTopic(cfg.Topic, alert.Project)},
+		"content": {content},
+	}
+
+	client, err := NewHTTPClient(opts)
+	if err != nil {
+		return err
+	}
+	req, err := newRequest(strings.TrimRight(cfg.Site, "/")+"/api/v1/messages",
+		"application/x-www-form-urlencoded", []byte(form.Encode()), opts)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(cfg.BotEmail, cfg.APIKey)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send Zulip message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("Zulip API returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// SendZulipAlertWithRetry posts a drift alert to Zulip with retry logic
+func SendZulipAlertWithRetry(cfg ZulipConfig, alert DriftAlert, opts HTTPOptions, maxRetries int) error {
+	return withRetry("Zulip notification", maxRetries, func() error {
+		return SendZulipAlert(cfg, alert, opts)
+	})
+}
@@ -0,0 +1,45 @@
+package notifier
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSendZulipAlert(t *testing.T) {
+	var got *http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		got = r
+	}))
+	defer server.Close()
+
+	cfg := ZulipConfig{Site: server.URL + "/", BotEmail: "drift-bot@example.com", APIKey: "key", Stream: "infra"}
+	alert := DriftAlert{Project: "network", Summary: "Plan: 0 to add, 1 to change, 0 to destroy.", PlanOutput: "  ~ tags = {}"}
+	if err := SendZulipAlert(cfg, alert, HTTPOptions{}); err != nil {
+		t.Fatalf("SendZulipAlert failed: %v", err)
+	}
+
+	if got.URL.Path != "/api/v1/messages" {
+		t.Errorf("Expected messages API path, got %s", got.URL.Path)
+	}
+	if user, pass, ok := got.BasicAuth(); !ok || user != "drift-bot@example.com" || pass != "key" {
+		t.Errorf("Expected bot credentials as basic auth, got %q/%q", user, pass)
+	}
+	if got.PostForm.Get("to") != "infra" || got.PostForm.Get("topic") != "drift: network" {
+		t.Errorf("Expected stream infra and per-project topic, got %q/%q", got.PostForm.Get("to"), got.PostForm.Get("topic"))
+	}
+	if content := got.PostForm.Get("content"); !strings.Contains(content, "```diff\n  ~ tags = {}") {
+		t.Errorf("Expected plan in a diff block, got:\n%s", content)
+	}
+}
+
+func TestZulipTopic(t *testing.T) {
+	if topic := zulipTopic("terraform/{project}", "network"); topic != "terraform/network" {
+		t.Errorf("Unexpected topic %q", topic)
+	}
+	if topic := zulipTopic("", strings.Repeat("x", 100)); len([]rune(topic)) != maxZulipTopicLength {
+		t.Errorf("Expected topic truncated to %d characters, got %d", maxZulipTopicLength, len([]rune(topic)))
+	}
+}
@@ -0,0 +1,165 @@
+// Package redact removes known secrets from text before it is logged or sent in a
+// notification. Secrets are registered once, when the configuration is loaded, and
+// every log line passes through Writer.
+package redact
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Placeholder replaces every occurrence of a secret
+const Placeholder = "[REDACTED]"
+
+// maxLineBuffer bounds how much of a line without a newline LineWriter holds back
+const maxLineBuffer = 64 * 1024
+
+// minSecretLength keeps short values such as "1" or "yes" from being redacted everywhere
+const minSecretLength = 6
+
+// sensitiveEnvWords mark environment variables whose values are secrets
+var sensitiveEnvWords = []string{"SECRET", "TOKEN", "PASSWORD", "PASSWD", "API_KEY", "ACCESS_KEY", "PRIVATE_KEY", "CREDENTIALS"}
+
+var (
+	mu      sync.RWMutex
+	secrets []string // Longest first, so a secret containing another is replaced whole
+)
+
+// Add registers secret values to redact. Empty and very short values are ignored.
+func Add(values ...string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if len(value) < minSecretLength || contains(secrets, value) {
+			continue
+		}
+		secrets = append(secrets, value)
+	}
+	sort.SliceStable(secrets, func(i, j int) bool { return len(secrets[i]) > len(secrets[j]) })
+}
+
+// AddEnvironment registers the values of environment variables that look like credentials,
+// such as AWS_SECRET_ACCESS_KEY or TF_TOKEN_app_terraform_io. Values naming an existing file
+// (e.g. GOOGLE_APPLICATION_CREDENTIALS) are paths, not secrets, and are skipped.
+func AddEnvironment() {
+	for _, entry := range os.Environ() {
+		name, value, _ := strings.Cut(entry, "=")
+		if !SensitiveEnvName(name) {
+			continue
+		}
+		if _, err := os.Stat(value); err == nil {
+			continue
+		}
+		Add(value)
+	}
+}
+
+// String returns s with every registered secret replaced by Placeholder
+func String(s string) string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	for _, secret := range secrets {
+		if strings.Contains(s, secret) {
+			s = strings.ReplaceAll(s, secret, Placeholder)
+		}
+	}
+	return s
+}
+
+// Writer returns a writer that redacts secrets before writing to w. Each Write is redacted
+// on its own, which suits the log package as it writes one message per call.
+func Writer(w io.Writer) io.Writer {
+	return &writer{w: w}
+}
+
+type writer struct {
+	w io.Writer
+}
+
+func (rw *writer) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(rw.w, String(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// LineWriter returns a writer that redacts secrets before writing to w one whole line at a
+// time, so a secret split across two writes is still found, as when streaming command output.
+// Close writes out a last line that does not end with a newline.
+func LineWriter(w io.Writer) io.WriteCloser {
+	return &lineWriter{w: w}
+}
+
+type lineWriter struct {
+	mu  sync.Mutex // A command's stdout and stderr are copied concurrently
+	w   io.Writer
+	buf []byte
+}
+
+func (lw *lineWriter) Write(p []byte) (int, error) {
+	lw.mu.Lock()
+	defer lw.mu.Unlock()
+
+	lw.buf = append(lw.buf, p...)
+	end := bytes.LastIndexByte(lw.buf, '\n') + 1
+	if len(lw.buf) > maxLineBuffer {
+		end = len(lw.buf)
+	}
+	if end == 0 {
+		return len(p), nil
+	}
+	if _, err := io.WriteString(lw.w, String(string(lw.buf[:end]))); err != nil {
+		return 0, err
+	}
+	lw.buf = append(lw.buf[:0], lw.buf[end:]...)
+	return len(p), nil
+}
+
+func (lw *lineWriter) Close() error {
+	lw.mu.Lock()
+	defer lw.mu.Unlock()
+
+	if len(lw.buf) == 0 {
+		return nil
+	}
+	_, err := io.WriteString(lw.w, String(string(lw.buf)))
+	lw.buf = nil
+	return err
+}
+
+// SensitiveEnvName reports whether an environment variable name suggests a credential
+func SensitiveEnvName(name string) bool {
+	name = strings.ToUpper(name)
+	if strings.HasPrefix(name, "TF_TOKEN_") {
+		return true
+	}
+	for _, word := range sensitiveEnvWords {
+		if strings.Contains(name, word) {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// reset clears the registered secrets; used by tests
+func reset() {
+	mu.Lock()
+	defer mu.Unlock()
+	secrets = nil
+}
@@ -0,0 +1,73 @@
+package redact
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"testing"
+)
+
+func TestString(t *testing.T) {
+	defer reset()
+	Add("s3cr3t-token", "s3cr3t-token-extended", "", "abc")
+
+	got := String(`Error: invalid token "s3cr3t-token-extended" (was s3cr3t-token), abc`)
+	expected := `Error: invalid token "[REDACTED]" (was [REDACTED]), abc`
+	if got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+}
+
+func TestAddEnvironment(t *testing.T) {
+	defer reset()
+	t.Setenv("ARM_CLIENT_SECRET", "azure-client-secret")
+	t.Setenv("TF_TOKEN_app_terraform_io", "tfc-token-value")
+	t.Setenv("AWS_DEFAULT_REGION", "eu-central-1")
+	t.Setenv("GOOGLE_APPLICATION_CREDENTIALS", t.TempDir())
+	AddEnvironment()
+
+	got := String("azure-client-secret tfc-token-value eu-central-1")
+	if got != "[REDACTED] [REDACTED] eu-central-1" {
+		t.Errorf("Unexpected redaction: %q", got)
+	}
+	if strings.Contains(String("key file at "+t.TempDir()), Placeholder) {
+		t.Error("Expected credential file paths not to be redacted")
+	}
+}
+
+func TestWriter(t *testing.T) {
+	defer reset()
+	Add("hunter2-password")
+
+	var buf bytes.Buffer
+	logger := log.New(Writer(&buf), "", 0)
+	logger.Printf("ERROR: terraform: password hunter2-password rejected")
+
+	if got := buf.String(); got != "ERROR: terraform: password [REDACTED] rejected\n" {
+		t.Errorf("Unexpected log output: %q", got)
+	}
+}
+
+func TestLineWriter(t *testing.T) {
+	defer reset()
+	Add("hunter2-password")
+
+	// Streamed output arrives in arbitrary chunks, here with the secret split in two
+	var buf bytes.Buffer
+	w := LineWriter(&buf)
+	for _, chunk := range []string{"Refreshing state...\npassword = hun", "ter2-password\nlast line hunter2", "-password"} {
+		if _, err := w.Write([]byte(chunk)); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+	if got := buf.String(); got != "Refreshing state...\npassword = [REDACTED]\n" {
+		t.Errorf("Expected only whole lines before Close, got %q", got)
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if got := buf.String(); got != "Refreshing state...\npassword = [REDACTED]\nlast line [REDACTED]" {
+		t.Errorf("Expected the last line after Close, got %q", got)
+	}
+}
@@ -0,0 +1,68 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+)
+
+// BadgeLabel is the left-hand text of the fleet health badge
+const BadgeLabel = "drift-free"
+
+// Badge colors by drift-free share, as used by shields.io
+const (
+	badgeGreen  = "#4c1"
+	badgeYellow = "#dfb317"
+	badgeRed    = "#e05d44"
+	badgeGrey   = "#9f9f9f"
+)
+
+// badgeCharWidth approximates the width of a character of 11px Verdana
+const badgeCharWidth = 7
+
+// badgeMessage returns the right-hand text and color of the badge of a fleet or tag
+func badgeMessage(h Health) (string, string) {
+	if h.Projects == 0 {
+		return "unknown", badgeGrey
+	}
+	percent := h.Percent()
+	color := badgeRed
+	switch {
+	case percent >= 95:
+		color = badgeGreen
+	case percent >= 80:
+		color = badgeYellow
+	}
+	return fmt.Sprintf("%.0f%%", percent), color
+}
+
+// BadgeSVG renders a flat shields.io-style badge showing the drift-free share
+func BadgeSVG(h Health) []byte {
+	message, color := badgeMessage(h)
+	labelWidth := len(BadgeLabel)*badgeCharWidth + 10
+	messageWidth := len(message)*badgeCharWidth + 10
+	width := labelWidth + messageWidth
+
+	label, message := html.EscapeString(BadgeLabel), html.EscapeString(message)
+	return []byte(fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%[1]d" height="20" role="img" aria-label="%[4]s: %[5]s">
+<title>%[4]s: %[5]s</title>
+<linearGradient id="s" x2="0" y2="100%%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>
+<clipPath id="r"><rect width="%[1]d" height="20" rx="3" fill="#fff"/></clipPath>
+<g clip-path="url(#r)"><rect width="%[2]d" height="20" fill="#555"/><rect x="%[2]d" width="%[3]d" height="20" fill="%[6]s"/><rect width="%[1]d" height="20" fill="url(#s)"/></g>
+<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">
+<text x="%[7]d" y="14">%[4]s</text><text x="%[8]d" y="14">%[5]s</text>
+</g>
+</svg>
+`, width, labelWidth, messageWidth, label, message, color, labelWidth/2, labelWidth+messageWidth/2))
+}
+
+// BadgeJSON renders the badge for a shields.io endpoint badge
+func BadgeJSON(h Health) ([]byte, error) {
+	message, color := badgeMessage(h)
+	return json.MarshalIndent(map[string]interface{}{
+		"schemaVersion": 1,
+		"label":         BadgeLabel,
+		"message":       message,
+		"color":         color,
+	}, "", "  ")
+}
@@ -0,0 +1,372 @@
+package report
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// mostDriftingLimit bounds the "most drifting projects" table
+const mostDriftingLimit = 10
+
+// RenderMarkdown writes the summary as a Markdown document
+func RenderMarkdown(w io.Writer, s *Summary) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# Drift Report: %s – %s\n\n", s.From.Format("2006-01-02"), s.To.Format("2006-01-02"))
+	if len(s.Metadata) > 0 {
+		fmt.Fprintf(&b, "Watcher: %s\n\n", markdownCell(formatOutputs(s.Metadata)))
+	}
+
+	b.WriteString("## Overview\n\n")
+	b.WriteString("| Metric | Value |\n|--------|-------|\n")
+	fmt.Fprintf(&b, "| Projects scanned | %d |\n", s.ProjectCount)
+	fmt.Fprintf(&b, "| Scans | %d |\n", s.Scans)
+	fmt.Fprintf(&b, "| Scans with drift | %d |\n", s.DriftedScans)
+	fmt.Fprintf(&b, "| Scans with errors | %d |\n", s.ErrorScans)
+	fmt.Fprintf(&b, "| Drifts remediated | %d |\n", s.ResolvedDrifts)
+	fmt.Fprintf(&b, "| Mean time to remediation | %s |\n", formatDuration(s.MTTR))
+	fmt.Fprintf(&b, "| Projects currently drifted | %d |\n", len(s.OpenDrift))
+	fmt.Fprintf(&b, "| Drift-free | %s |\n\n", formatHealth(s.Health))
+
+	b.WriteString("## Most Drifting Projects\n\n")
+	if mostDrifting := s.MostDrifting(mostDriftingLimit); len(mostDrifting) > 0 {
+		b.WriteString("| Project | Scans with drift | Scans | Remediated | MTTR | Last status |\n")
+		b.WriteString("|---------|------------------|-------|------------|------|-------------|\n")
+		for _, p := range mostDrifting {
+			fmt.Fprintf(&b, "| %s | %d | %d | %d | %s | %s |\n",
+				p.Project, p.DriftedScans, p.Scans, p.ResolvedDrifts, formatDuration(p.MTTR), p.Status())
+		}
+	} else {
+		b.WriteString("No drift detected in this period.\n")
+	}
+	b.WriteString("\n")
+
+	if ranked := s.Ranked(); len(ranked) > 0 {
+		b.WriteString("## Project Health\n\n")
+		b.WriteString("| Project | Health score | Scans with drift | Failed scans | Last clean | MTTR |\n")
+		b.WriteString("|---------|--------------|------------------|--------------|------------|------|\n")
+		for _, p := range ranked {
+			fmt.Fprintf(&b, "| %s | %d | %d of %d | %d | %s | %s |\n",
+				p.Project, p.Score, p.DriftedScans, p.Scans, p.ErrorScans, formatTime(p.LastClean), formatDuration(p.MTTR))
+		}
+		b.WriteString("\n")
+	}
+
+	if len(s.OpenDrift) > 0 {
+		b.WriteString("## Currently Drifted\n\n")
+		for _, name := range s.OpenDrift {
+			line := name
+			if stats := s.Lookup(name); stats != nil {
+				if stats.Description != "" {
+					line += " — " + stats.Description
+				}
+				if stats.RunbookURL != "" {
+					line += fmt.Sprintf(" ([runbook](%s))", stats.RunbookURL)
+				}
+				if len(stats.Outputs) > 0 {
+					line += " [" + formatOutputs(stats.Outputs) + "]"
+				}
+				if stats.Fingerprint != "" {
+					line += fmt.Sprintf(" `%s`", stats.Fingerprint)
+				}
+			}
+			fmt.Fprintf(&b, "- %s\n", line)
+		}
+		b.WriteString("\n")
+	}
+
+	if len(s.SLOs) > 0 {
+		b.WriteString("## Drift SLOs\n\n")
+		b.WriteString("| SLO | Resolve within | Resolved in time | Target | Outcome | Breaching now |\n")
+		b.WriteString("|-----|----------------|------------------|--------|---------|---------------|\n")
+		for _, slo := range s.SLOs {
+			fmt.Fprintf(&b, "| %s | %s | %s | %.4g%% | %s | %s |\n", slo.Name, formatDuration(slo.ResolveWithin),
+				formatCompliance(slo), slo.Target, slo.Outcome(), formatList(slo.Breaching))
+		}
+		b.WriteString("\n")
+	}
+
+	if flapping := s.FlappingProjects(); len(flapping) > 0 {
+		b.WriteString("## Flapping Projects\n\n")
+		b.WriteString("These projects keep changing between drifted and clean; look for the root cause, such as\n")
+		b.WriteString("two tools managing the same resource or an attribute the provider reports inconsistently.\n\n")
+		b.WriteString("| Project | State changes | Scans | Last status |\n")
+		b.WriteString("|---------|---------------|-------|-------------|\n")
+		for _, p := range flapping {
+			fmt.Fprintf(&b, "| %s | %d | %d | %s |\n", p.Project, p.StateChanges, p.Scans, p.LastStatus)
+		}
+		b.WriteString("\n")
+	}
+
+	if len(s.Changes) > 0 {
+		b.WriteString("## Out-of-Band Changes\n\n")
+		for _, pc := range s.Changes {
+			fmt.Fprintf(&b, "### %s\n\n", pc.Project)
+			b.WriteString("| Resource | Attribute | Before | After |\n|----------|-----------|--------|-------|\n")
+			for _, c := range pc.Changes {
+				attribute := c.Attribute
+				if attribute == "" {
+					attribute = "(" + c.Action + ")"
+				}
+				if c.Type != "" {
+					attribute += " _(" + c.Type + ")_"
+				}
+				if c.Description != "" {
+					attribute += "<br>" + markdownCell(c.Description)
+				}
+				resource := c.Address
+				if c.URL != "" {
+					resource = fmt.Sprintf("[%s](%s)", c.Address, c.URL)
+				}
+				fmt.Fprintf(&b, "| %s | %s | `%s` | `%s` |\n",
+					resource, attribute, markdownCell(c.Before), markdownCell(c.After))
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	writeGroupsMarkdown(&b, "Drift by Team", s.ByOwner)
+	writeGroupsMarkdown(&b, "Drift by Tag", s.ByTag)
+
+	if len(s.ByError) > 0 {
+		b.WriteString("## Failed Scans by Cause\n\n")
+		b.WriteString("| Cause | Failed scans | Projects |\n|-------|--------------|----------|\n")
+		for _, e := range s.ByError {
+			fmt.Fprintf(&b, "| %s | %d | %s |\n", e.Category, e.Scans, markdownCell(strings.Join(e.Projects, ", ")))
+		}
+		b.WriteString("\n")
+	}
+
+	if mismatches := s.VersionMismatches(); len(mismatches) > 0 {
+		b.WriteString("## Terraform Version Mismatches\n\n")
+		b.WriteString("| Project | State written by | Local Terraform |\n|---------|------------------|-----------------|\n")
+		for _, p := range mismatches {
+			fmt.Fprintf(&b, "| %s | %s | %s |\n", p.Project, markdownCell(p.StateVersion), markdownCell(p.LocalVersion))
+		}
+		b.WriteString("\n")
+	}
+
+	if warnings := s.Warnings(); len(warnings) > 0 {
+		b.WriteString("## Terraform Warnings\n\n")
+		b.WriteString("| Project | Warning | Resource | Detail |\n|---------|---------|----------|--------|\n")
+		for _, p := range warnings {
+			for _, w := range p.Warnings {
+				fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", p.Project, markdownCell(w.Summary), markdownCell(w.Resource), markdownCell(w.Detail))
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	if projects := s.PolicyFindings(); len(projects) > 0 {
+		b.WriteString("## Policy Findings\n\n")
+		b.WriteString("| Project | Severity | Tool | Rule | Resource | Finding |\n|---------|----------|------|------|----------|---------|\n")
+		for _, p := range projects {
+			for _, f := range p.PolicyFindings {
+				fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s |\n", p.Project, f.Severity, f.Tool, markdownCell(f.Rule),
+					markdownCell(f.Location()), markdownCell(f.Message))
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	if len(s.HealthByTag) > 0 {
+		b.WriteString("## Health by Tag\n\n")
+		b.WriteString("| Tag | Drift-free | Failed scans |\n|-----|------------|--------------|\n")
+		for _, h := range s.HealthByTag {
+			fmt.Fprintf(&b, "| %s | %s | %d |\n", h.Name, formatHealth(h), h.Unknown)
+		}
+		b.WriteString("\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// markdownCell escapes a value for use inside a Markdown table cell
+func markdownCell(value string) string {
+	return strings.NewReplacer("|", "\\|", "\n", " ", "`", "'").Replace(value)
+}
+
+// writeGroupsMarkdown writes a team or tag breakdown table, if there is anything to show
+func writeGroupsMarkdown(b *strings.Builder, title string, groups []GroupStats) {
+	if len(groups) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "## %s\n\n", title)
+	b.WriteString("| Name | Drifted projects | Scans with drift |\n|------|------------------|------------------|\n")
+	for _, g := range groups {
+		fmt.Fprintf(b, "| %s | %d | %d |\n", g.Name, g.DriftedProjects, g.DriftedScans)
+	}
+	b.WriteString("\n")
+}
+
+// htmlTemplate renders the summary as a standalone HTML page
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"compliance": formatCompliance,
+	"date":       func(t time.Time) string { return t.Format("2006-01-02") },
+	"duration":   formatDuration,
+	"health":     formatHealth,
+	"join":       strings.Join,
+	"list":       formatList,
+	"outputs":    formatOutputs,
+	"time":       formatTime,
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Drift Report {{date .From}} – {{date .To}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin-bottom: 1.5em; }
+th, td { border: 1px solid #ccc; padding: 4px 10px; text-align: left; }
+th { background: #f3f3f3; }
+</style>
+</head>
+<body>
+<h1>Drift Report: {{date .From}} – {{date .To}}</h1>
+{{with .Metadata}}<p>Watcher: {{outputs .}}</p>{{end}}
+<h2>Overview</h2>
+<table>
+<tr><th>Projects scanned</th><td>{{.ProjectCount}}</td></tr>
+<tr><th>Scans</th><td>{{.Scans}}</td></tr>
+<tr><th>Scans with drift</th><td>{{.DriftedScans}}</td></tr>
+<tr><th>Scans with errors</th><td>{{.ErrorScans}}</td></tr>
+<tr><th>Drifts remediated</th><td>{{.ResolvedDrifts}}</td></tr>
+<tr><th>Mean time to remediation</th><td>{{duration .MTTR}}</td></tr>
+<tr><th>Projects currently drifted</th><td>{{len .OpenDrift}}</td></tr>
+<tr><th>Drift-free</th><td>{{health .Health}}</td></tr>
+</table>
+<h2>Most Drifting Projects</h2>
+{{with .MostDrifting 10}}<table>
+<tr><th>Project</th><th>Scans with drift</th><th>Scans</th><th>Remediated</th><th>MTTR</th><th>Last status</th></tr>
+{{range .}}<tr><td>{{.Project}}</td><td>{{.DriftedScans}}</td><td>{{.Scans}}</td><td>{{.ResolvedDrifts}}</td><td>{{duration .MTTR}}</td><td>{{.Status}}</td></tr>
+{{end}}</table>{{else}}<p>No drift detected in this period.</p>{{end}}
+{{with .Ranked}}<h2>Project Health</h2>
+<table>
+<tr><th>Project</th><th>Health score</th><th>Scans with drift</th><th>Failed scans</th><th>Last clean</th><th>MTTR</th></tr>
+{{range .}}<tr><td>{{.Project}}</td><td>{{.Score}}</td><td>{{.DriftedScans}} of {{.Scans}}</td><td>{{.ErrorScans}}</td><td>{{time .LastClean}}</td><td>{{duration .MTTR}}</td></tr>
+{{end}}</table>{{end}}
+{{with .OpenDrift}}<h2>Currently Drifted</h2>
+<ul>{{range .}}<li>{{.}}{{with $.Lookup .}}{{with .Description}} — {{.}}{{end}}{{with .RunbookURL}} (<a href="{{.}}">runbook</a>){{end}}{{with .Outputs}} [{{outputs .}}]{{end}}{{with .Fingerprint}} <code>{{.}}</code>{{end}}{{end}}</li>{{end}}</ul>{{end}}
+{{with .SLOs}}<h2>Drift SLOs</h2>
+<table>
+<tr><th>SLO</th><th>Resolve within</th><th>Resolved in time</th><th>Target</th><th>Outcome</th><th>Breaching now</th></tr>
+{{range .}}<tr><td>{{.Name}}</td><td>{{duration .ResolveWithin}}</td><td>{{compliance .}}</td><td>{{printf "%.4g" .Target}}%</td><td>{{.Outcome}}</td><td>{{list .Breaching}}</td></tr>
+{{end}}</table>{{end}}
+{{with .FlappingProjects}}<h2>Flapping Projects</h2>
+<p>These projects keep changing between drifted and clean; look for the root cause, such as
+two tools managing the same resource or an attribute the provider reports inconsistently.</p>
+<table>
+<tr><th>Project</th><th>State changes</th><th>Scans</th><th>Last status</th></tr>
+{{range .}}<tr><td>{{.Project}}</td><td>{{.StateChanges}}</td><td>{{.Scans}}</td><td>{{.LastStatus}}</td></tr>
+{{end}}</table>{{end}}
+{{with .Changes}}<h2>Out-of-Band Changes</h2>
+{{range .}}<h3>{{.Project}}</h3>
+<table>
+<tr><th>Resource</th><th>Attribute</th><th>Before</th><th>After</th></tr>
+{{range .Changes}}<tr><td>{{if .URL}}<a href="{{.URL}}">{{.Address}}</a>{{else}}{{.Address}}{{end}}</td><td>{{if .Attribute}}{{.Attribute}}{{else}}({{.Action}}){{end}}{{with .Type}} <em>({{.}})</em>{{end}}{{with .Description}}<br><small>{{.}}</small>{{end}}</td><td><code>{{.Before}}</code></td><td><code>{{.After}}</code></td></tr>
+{{end}}</table>
+{{end}}{{end}}
+{{with .ByOwner}}<h2>Drift by Team</h2>
+<table>
+<tr><th>Team</th><th>Drifted projects</th><th>Scans with drift</th></tr>
+{{range .}}<tr><td>{{.Name}}</td><td>{{.DriftedProjects}}</td><td>{{.DriftedScans}}</td></tr>
+{{end}}</table>{{end}}
+{{with .ByTag}}<h2>Drift by Tag</h2>
+<table>
+<tr><th>Tag</th><th>Drifted projects</th><th>Scans with drift</th></tr>
+{{range .}}<tr><td>{{.Name}}</td><td>{{.DriftedProjects}}</td><td>{{.DriftedScans}}</td></tr>
+{{end}}</table>{{end}}
+{{with .ByError}}<h2>Failed Scans by Cause</h2>
+<table>
+<tr><th>Cause</th><th>Failed scans</th><th>Projects</th></tr>
+{{range .}}<tr><td>{{.Category}}</td><td>{{.Scans}}</td><td>{{join .Projects ", "}}</td></tr>
+{{end}}</table>{{end}}
+{{with .VersionMismatches}}<h2>Terraform Version Mismatches</h2>
+<table>
+<tr><th>Project</th><th>State written by</th><th>Local Terraform</th></tr>
+{{range .}}<tr><td>{{.Project}}</td><td>{{.StateVersion}}</td><td>{{.LocalVersion}}</td></tr>
+{{end}}</table>{{end}}
+{{with .Warnings}}<h2>Terraform Warnings</h2>
+<table>
+<tr><th>Project</th><th>Warning</th><th>Resource</th><th>Detail</th></tr>
+{{range .}}{{$project := .Project}}{{range .Warnings}}<tr><td>{{$project}}</td><td>{{.Summary}}</td><td>{{.Resource}}</td><td>{{.Detail}}</td></tr>
+{{end}}{{end}}</table>{{end}}
+{{with .PolicyFindings}}<h2>Policy Findings</h2>
+<table>
+<tr><th>Project</th><th>Severity</th><th>Tool</th><th>Rule</th><th>Resource</th><th>Finding</th></tr>
+{{range .}}{{$project := .Project}}{{range .PolicyFindings}}<tr><td>{{$project}}</td><td>{{.Severity}}</td><td>{{.Tool}}</td><td>{{.Rule}}</td><td>{{.Location}}</td><td>{{.Message}}</td></tr>
+{{end}}{{end}}</table>{{end}}
+{{with .HealthByTag}}<h2>Health by Tag</h2>
+<table>
+<tr><th>Tag</th><th>Drift-free</th><th>Failed scans</th></tr>
+{{range .}}<tr><td>{{.Name}}</td><td>{{health .}}</td><td>{{.Unknown}}</td></tr>
+{{end}}</table>{{end}}
+</body>
+</html>
+`))
+
+// RenderHTML writes the summary as a standalone HTML page
+func RenderHTML(w io.Writer, s *Summary) error {
+	return htmlTemplate.Execute(w, s)
+}
+
+// formatHealth renders the drift-free share of a fleet or tag, e.g. "92% (23 of 25)"
+func formatHealth(h Health) string {
+	if h.Projects == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.0f%% (%d of %d)", h.Percent(), h.DriftFree, h.Projects)
+}
+
+// formatCompliance renders the share of drift resolved within an SLO, e.g. "90% (9 of 10)"
+func formatCompliance(c SLOCompliance) string {
+	if c.Episodes == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.0f%% (%d of %d)", c.Percent(), c.Met, c.Episodes)
+}
+
+// formatList joins names, or renders "-" when there are none
+func formatList(names []string) string {
+	if len(names) == 0 {
+		return "-"
+	}
+	return strings.Join(names, ", ")
+}
+
+// formatOutputs renders captured terraform outputs or watcher metadata in name order, e.g. "env: prod, region: eu-west-1"
+func formatOutputs(outputs map[string]string) string {
+	names := make([]string, 0, len(outputs))
+	for name := range outputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for i, name := range names {
+		names[i] = name + ": " + outputs[name]
+	}
+	return strings.Join(names, ", ")
+}
+
+// formatTime renders a time of the period to the minute, or "-" when there is none
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format("2006-01-02 15:04")
+}
+
+// formatDuration renders a duration for humans, using days for long durations
+func formatDuration(d time.Duration) string {
+	if d == 0 {
+		return "-"
+	}
+	if d >= 48*time.Hour {
+		return fmt.Sprintf("%.1fd", d.Hours()/24)
+	}
+	return d.Round(time.Minute).String()
+}
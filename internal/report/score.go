@@ -0,0 +1,60 @@
+package report
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Weights of the parts of the health score, summing to 1
+const (
+	scoreDriftWeight = 0.35 // Share of scans that found drift
+	scoreErrorWeight = 0.25 // Share of scans that failed
+	scoreStaleWeight = 0.25 // Time since the last clean scan
+	scoreMTTRWeight  = 0.15 // Mean time to remediation
+)
+
+// scoreHorizon is the time since the last clean scan, or the mean time to remediation, that
+// costs the full weight of its part
+const scoreHorizon = 7 * 24 * time.Hour
+
+// Orders of the projects in the project health table
+const (
+	SortDrift  = "drift"  // Most drifting first (default)
+	SortHealth = "health" // Lowest health score first
+)
+
+// healthScore rates a project from 0 to 100 (healthy) by how often it drifts and fails, how
+// long ago it was last clean and how long its drift takes to remediate. A project not clean
+// at all in the period has the full staleness cost.
+func healthScore(stats ProjectStats, now time.Time) int {
+	if stats.Scans == 0 {
+		return 0
+	}
+	stale := 1.0
+	if !stats.LastClean.IsZero() {
+		stale = horizonShare(now.Sub(stats.LastClean))
+	}
+	cost := scoreDriftWeight*float64(stats.DriftedScans)/float64(stats.Scans) +
+		scoreErrorWeight*float64(stats.ErrorScans)/float64(stats.Scans) +
+		scoreStaleWeight*stale +
+		scoreMTTRWeight*horizonShare(stats.MTTR)
+	return int(math.Round(100 * (1 - cost)))
+}
+
+// horizonShare returns the share of the score horizon d covers, at most 1
+func horizonShare(d time.Duration) float64 {
+	if d <= 0 {
+		return 0
+	}
+	return math.Min(1, float64(d)/float64(scoreHorizon))
+}
+
+// Ranked returns the projects in the order of the summary's Sort
+func (s *Summary) Ranked() []ProjectStats {
+	ranked := append([]ProjectStats(nil), s.Projects...)
+	if s.Sort == SortHealth {
+		sort.SliceStable(ranked, func(i, j int) bool { return ranked[i].Score < ranked[j].Score })
+	}
+	return ranked
+}
@@ -0,0 +1,45 @@
+package report
+
+import (
+	"testing"
+	"time"
+
+	"github.com/terradrift-watcher/internal/state"
+)
+
+func TestHealthScore(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(hours int) time.Time { return start.Add(time.Duration(hours) * time.Hour) }
+
+	records := []state.HistoryRecord{
+		{Time: at(0), Project: "steady", Status: "clean"},
+		{Time: at(168), Project: "steady", Status: "clean"},
+		// Drift remediated after 3.5 days, drifted again now
+		{Time: at(0), Project: "network", Status: "drifted"},
+		{Time: at(84), Project: "network", Status: "clean"},
+		{Time: at(168), Project: "network", Status: "drifted"},
+		// Never scanned successfully
+		{Time: at(0), Project: "database", Status: "error"},
+		{Time: at(1), Project: "database", Status: "error"},
+	}
+	summary := Build(records, start, at(168))
+
+	want := map[string]int{"steady": 100, "network": 57, "database": 50}
+	for name, score := range want {
+		if stats := summary.Lookup(name); stats == nil || stats.Score != score {
+			t.Errorf("Expected %s to score %d, got %+v", name, score, stats)
+		}
+	}
+	if stats := summary.Lookup("network"); !stats.LastClean.Equal(at(84)) {
+		t.Errorf("Expected network last clean at %v, got %v", at(84), stats.LastClean)
+	}
+
+	if ranked := summary.Ranked(); ranked[0].Project != "network" {
+		t.Errorf("Expected the most drifting project first by default, got %s", ranked[0].Project)
+	}
+	summary.Sort = SortHealth
+	ranked := summary.Ranked()
+	if ranked[0].Project != "database" || ranked[1].Project != "network" || ranked[2].Project != "steady" {
+		t.Errorf("Expected the least healthy project first, got %s, %s, %s", ranked[0].Project, ranked[1].Project, ranked[2].Project)
+	}
+}
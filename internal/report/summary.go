@@ -0,0 +1,497 @@
+package report
+
+import (
+	"sort"
+	"time"
+
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/detector"
+	"github.com/terradrift-watcher/internal/state"
+	"github.com/terradrift-watcher/internal/terraform"
+)
+
+// Summary aggregates scan history over a reporting period
+type Summary struct {
+	From time.Time
+	To   time.Time
+
+	Scans          int
+	DriftedScans   int
+	ErrorScans     int
+	ProjectCount   int
+	ResolvedDrifts int
+	MTTR           time.Duration // Mean time to remediation over drifts resolved in the period
+
+	Projects  []ProjectStats   // Sorted by drift count, most drifting first
+	OpenDrift []string         // Projects still drifted at their latest scan
+	Changes   []ProjectChanges // Latest changelog of each project in OpenDrift
+	ByOwner   []GroupStats
+	ByTag     []GroupStats
+	ByError   []ErrorStats // Failed scans by error category, most frequent first
+
+	// Health is the share of the fleet drift-free at its latest scan, also broken down by tag
+	Health      Health
+	HealthByTag []Health
+
+	// SLOs holds the compliance with each configured drift SLO, see ComputeSLOs
+	SLOs []SLOCompliance
+
+	// Metadata identifies the watcher instance the report comes from
+	Metadata map[string]string
+
+	// Sort orders the project health table, SortDrift or SortHealth (default SortDrift)
+	Sort string
+}
+
+// ProjectStats holds per-project figures for the period
+type ProjectStats struct {
+	Project        string
+	Scans          int
+	DriftedScans   int
+	ErrorScans     int
+	ResolvedDrifts int
+	MTTR           time.Duration
+	LastStatus     string
+	Description    string
+	RunbookURL     string
+	Fingerprint    string   // Fingerprint of the latest drift
+	Tags           []string // Tags of the latest scan
+	LastError      string   // Error category of the latest failed scan
+	StateVersion   string   // Terraform version that wrote the state, if the latest scan was a version mismatch
+	LocalVersion   string   // Terraform version that ran the latest scan, if it was a version mismatch
+	LastClean      time.Time
+	DriftSince     time.Time       // Start of the drift still open at the end of the period
+	Repairs        []time.Duration // Time to remediation of each drift resolved in the period
+	Score          int             // Health score from 0 to 100 (healthy), see healthScore
+
+	// StateChanges counts changes between drifted and clean over the latest scans, and
+	// Flapping is set when there are enough of them, see MarkFlapping
+	StateChanges int
+	Flapping     bool
+
+	// Outputs are the terraform outputs captured at the latest clean plan
+	Outputs map[string]string
+
+	// Warnings are the terraform warnings printed by the latest plan
+	Warnings []terraform.Warning
+
+	// PolicyFindings are the findings of the latest scan's policy stages, most severe first
+	PolicyFindings []terraform.PolicyFinding
+}
+
+// Status returns the project's latest status, or "flapping" while it flaps
+func (p ProjectStats) Status() string {
+	if p.Flapping {
+		return StatusFlapping
+	}
+	return p.LastStatus
+}
+
+// StatusFlapping is shown for projects alternating between drifted and clean
+const StatusFlapping = "flapping"
+
+// SLOCompliance measures a drift SLO over the period. Drift resolved in the period counts as
+// an episode, as does drift still open at its end once it is older than the objective.
+type SLOCompliance struct {
+	Name          string
+	ResolveWithin time.Duration
+	Target        float64 // Percent of episodes that must be resolved in time
+	Episodes      int
+	Met           int      // Episodes resolved within ResolveWithin
+	Breaching     []string // Projects whose open drift is older than ResolveWithin
+}
+
+// Percent returns the share of episodes resolved in time, or 100 without episodes
+func (c SLOCompliance) Percent() float64 {
+	if c.Episodes == 0 {
+		return 100
+	}
+	return 100 * float64(c.Met) / float64(c.Episodes)
+}
+
+// Compliant reports whether the SLO met its target
+func (c SLOCompliance) Compliant() bool {
+	return c.Percent() >= c.Target
+}
+
+// Outcome describes whether the SLO met its target, for reports
+func (c SLOCompliance) Outcome() string {
+	if c.Compliant() {
+		return "met"
+	}
+	return "missed"
+}
+
+// ErrorStats counts the failed scans of one error category
+type ErrorStats struct {
+	Category string
+	Scans    int
+	Projects []string
+}
+
+// ProjectChanges is the changelog recorded by a project's latest drifted scan
+type ProjectChanges struct {
+	Project string
+	Changes []terraform.AttributeChange
+}
+
+// Health counts the projects that were drift-free at their latest scan. Projects whose latest
+// scan failed are counted apart, since whether they drifted is unknown.
+type Health struct {
+	Name      string // Tag of a per-tag breakdown, empty for the whole fleet
+	Projects  int    // Projects whose latest scan was clean or drifted
+	DriftFree int
+	Unknown   int // Projects whose latest scan failed
+}
+
+// Percent returns the share of drift-free projects, or 0 when no project has a known status
+func (h Health) Percent() float64 {
+	if h.Projects == 0 {
+		return 0
+	}
+	return 100 * float64(h.DriftFree) / float64(h.Projects)
+}
+
+// GroupStats counts drift for a team or tag
+type GroupStats struct {
+	Name             string
+	DriftedProjects  int
+	DriftedScans     int
+	projectsRecorded map[string]bool
+}
+
+// Build aggregates the history records between from and to
+func Build(records []state.HistoryRecord, from time.Time, to time.Time) *Summary {
+	summary := &Summary{From: from, To: to}
+
+	perProject := make(map[string]*ProjectStats)
+	var order []string
+	driftStart := make(map[string]time.Time)
+	var totalRepair time.Duration
+	repairs := make(map[string]time.Duration)
+	latestChanges := make(map[string][]terraform.AttributeChange)
+	owners := make(map[string]*GroupStats)
+	tags := make(map[string]*GroupStats)
+	errorCategories := make(map[string]*ErrorStats)
+	statuses := make(map[string][]string)
+
+	sort.SliceStable(records, func(i, j int) bool { return records[i].Time.Before(records[j].Time) })
+
+	for _, record := range records {
+		if record.Time.Before(from) || record.Time.After(to) {
+			continue
+		}
+
+		stats, ok := perProject[record.Project]
+		if !ok {
+			stats = &ProjectStats{Project: record.Project}
+			perProject[record.Project] = stats
+			order = append(order, record.Project)
+		}
+
+		summary.Scans++
+		stats.Scans++
+		stats.LastStatus = record.Status
+		statuses[record.Project] = append(statuses[record.Project], record.Status)
+		stats.Description = record.Description
+		stats.RunbookURL = record.RunbookURL
+		stats.Tags = record.Tags
+		stats.StateVersion = record.StateVersion
+		stats.LocalVersion = record.LocalVersion
+		if len(record.Outputs) > 0 {
+			stats.Outputs = record.Outputs
+		}
+		stats.PolicyFindings = append([]terraform.PolicyFinding(nil), record.PolicyFindings...)
+		sort.SliceStable(stats.PolicyFindings, func(i, j int) bool {
+			return terraform.PolicySeverityRank(stats.PolicyFindings[i].Severity) > terraform.PolicySeverityRank(stats.PolicyFindings[j].Severity)
+		})
+		// Failed scans planned nothing, so the warnings of the plan before them still apply
+		if detector.DriftFree(record.Status) || record.Status == detector.StatusDrifted {
+			stats.Warnings = record.Warnings
+		}
+
+		switch record.Status {
+		case detector.StatusDrifted:
+			summary.DriftedScans++
+			stats.DriftedScans++
+			if _, open := driftStart[record.Project]; !open {
+				driftStart[record.Project] = record.Time
+				// The recorded start also covers drift that began before the period
+				if record.DriftSince != nil {
+					driftStart[record.Project] = *record.DriftSince
+				}
+			}
+			latestChanges[record.Project] = record.Changes
+			stats.Fingerprint = record.Fingerprint
+			for _, owner := range record.Owners {
+				countGroup(owners, owner, record.Project)
+			}
+			for _, tag := range record.Tags {
+				countGroup(tags, tag, record.Project)
+			}
+
+		case detector.StatusClean, detector.StatusNoise:
+			stats.LastClean = record.Time
+			// A clean scan after drift closes the drift episode. The recorded time to
+			// remediation is preferred as it also covers drift that began before the period.
+			start, open := driftStart[record.Project]
+			if open || record.TimeToRemediate > 0 {
+				repair := record.TimeToRemediate
+				if repair == 0 {
+					repair = record.Time.Sub(start)
+				}
+				delete(driftStart, record.Project)
+				stats.ResolvedDrifts++
+				summary.ResolvedDrifts++
+				repairs[record.Project] += repair
+				stats.Repairs = append(stats.Repairs, repair)
+				totalRepair += repair
+			}
+
+		case detector.StatusError, detector.StatusVersionMismatch:
+			summary.ErrorScans++
+			stats.ErrorScans++
+			// Records from before failures were classified count as unknown
+			category := record.ErrorCategory
+			if category == "" {
+				category = terraform.ErrorUnknown
+			}
+			// Timeouts are told apart by phase, as a hung init and a hung plan have different causes
+			if record.TimeoutPhase != "" {
+				category += " (" + record.TimeoutPhase + ")"
+			}
+			stats.LastError = category
+			group, ok := errorCategories[category]
+			if !ok {
+				group = &ErrorStats{Category: category}
+				errorCategories[category] = group
+			}
+			group.Scans++
+			if !containsString(group.Projects, record.Project) {
+				group.Projects = append(group.Projects, record.Project)
+			}
+		}
+	}
+
+	for _, name := range order {
+		stats := perProject[name]
+		if stats.ResolvedDrifts > 0 {
+			stats.MTTR = repairs[name] / time.Duration(stats.ResolvedDrifts)
+		}
+		stats.Score = healthScore(*stats, to)
+		stats.StateChanges = detector.StateChanges(statuses[name])
+		stats.DriftSince = driftStart[name]
+		if stats.LastStatus == detector.StatusDrifted {
+			summary.OpenDrift = append(summary.OpenDrift, name)
+			if changes := latestChanges[name]; len(changes) > 0 {
+				summary.Changes = append(summary.Changes, ProjectChanges{Project: name, Changes: changes})
+			}
+		}
+		summary.Projects = append(summary.Projects, *stats)
+	}
+	summary.ProjectCount = len(summary.Projects)
+	if summary.ResolvedDrifts > 0 {
+		summary.MTTR = totalRepair / time.Duration(summary.ResolvedDrifts)
+	}
+
+	sort.SliceStable(summary.Projects, func(i, j int) bool {
+		return summary.Projects[i].DriftedScans > summary.Projects[j].DriftedScans
+	})
+	summary.ByOwner = sortedGroups(owners)
+	summary.ByTag = sortedGroups(tags)
+	for _, group := range errorCategories {
+		summary.ByError = append(summary.ByError, *group)
+	}
+	sort.Slice(summary.ByError, func(i, j int) bool {
+		if summary.ByError[i].Scans != summary.ByError[j].Scans {
+			return summary.ByError[i].Scans > summary.ByError[j].Scans
+		}
+		return summary.ByError[i].Category < summary.ByError[j].Category
+	})
+	summary.ComputeHealth(nil)
+	summary.MarkFlapping(config.DefaultFlapThreshold)
+
+	return summary
+}
+
+// MarkFlapping flags the projects with at least threshold state changes over their latest scans
+func (s *Summary) MarkFlapping(threshold int) {
+	for i := range s.Projects {
+		s.Projects[i].Flapping = s.Projects[i].StateChanges >= threshold
+	}
+}
+
+// ComputeSLOs measures the compliance with each SLO over the projects it covers by their latest
+// tags
+func (s *Summary) ComputeSLOs(slos []config.SLO) {
+	s.SLOs = nil
+	for _, slo := range slos {
+		within, target := slo.Objective()
+		compliance := SLOCompliance{Name: slo.Name, ResolveWithin: within, Target: target}
+		for _, stats := range s.Projects {
+			if !slo.Applies(stats.Tags) {
+				continue
+			}
+			for _, repair := range stats.Repairs {
+				compliance.Episodes++
+				if repair <= within {
+					compliance.Met++
+				}
+			}
+			if !stats.DriftSince.IsZero() && s.To.Sub(stats.DriftSince) > within {
+				compliance.Episodes++
+				compliance.Breaching = append(compliance.Breaching, stats.Project)
+			}
+		}
+		sort.Strings(compliance.Breaching)
+		s.SLOs = append(s.SLOs, compliance)
+	}
+}
+
+// FlappingProjects returns the flapping projects, most state changes first
+func (s *Summary) FlappingProjects() []ProjectStats {
+	var flapping []ProjectStats
+	for _, p := range s.Projects {
+		if p.Flapping {
+			flapping = append(flapping, p)
+		}
+	}
+	sort.SliceStable(flapping, func(i, j int) bool { return flapping[i].StateChanges > flapping[j].StateChanges })
+	return flapping
+}
+
+// ComputeHealth sets the fleet health from the latest status of the given projects, mapped to
+// their tags. With nil it covers every project scanned in the period, with its latest tags.
+// Projects not scanned in the period are left out.
+func (s *Summary) ComputeHealth(projects map[string][]string) {
+	s.Health = Health{}
+	byTag := make(map[string]*Health)
+	for _, stats := range s.Projects {
+		tags := stats.Tags
+		if projects != nil {
+			var ok bool
+			if tags, ok = projects[stats.Project]; !ok {
+				continue
+			}
+		}
+
+		groups := []*Health{&s.Health}
+		for _, tag := range tags {
+			if byTag[tag] == nil {
+				byTag[tag] = &Health{Name: tag}
+			}
+			groups = append(groups, byTag[tag])
+		}
+		for _, h := range groups {
+			switch stats.LastStatus {
+			case detector.StatusClean, detector.StatusNoise:
+				h.Projects++
+				h.DriftFree++
+			case detector.StatusDrifted:
+				h.Projects++
+			case detector.StatusError, detector.StatusVersionMismatch:
+				h.Unknown++
+			}
+		}
+	}
+
+	s.HealthByTag = make([]Health, 0, len(byTag))
+	for _, h := range byTag {
+		s.HealthByTag = append(s.HealthByTag, *h)
+	}
+	sort.Slice(s.HealthByTag, func(i, j int) bool { return s.HealthByTag[i].Name < s.HealthByTag[j].Name })
+}
+
+// MostDrifting returns up to n projects that drifted at least once in the period
+func (s *Summary) MostDrifting(n int) []ProjectStats {
+	var result []ProjectStats
+	for _, stats := range s.Projects {
+		if stats.DriftedScans == 0 || len(result) == n {
+			break
+		}
+		result = append(result, stats)
+	}
+	return result
+}
+
+// VersionMismatches returns the projects whose latest scan found state written by a newer Terraform
+func (s *Summary) VersionMismatches() []ProjectStats {
+	var result []ProjectStats
+	for _, stats := range s.Projects {
+		if stats.LastStatus == detector.StatusVersionMismatch {
+			result = append(result, stats)
+		}
+	}
+	return result
+}
+
+// Warnings returns the projects whose latest plan printed terraform warnings
+func (s *Summary) Warnings() []ProjectStats {
+	var result []ProjectStats
+	for _, stats := range s.Projects {
+		if len(stats.Warnings) > 0 {
+			result = append(result, stats)
+		}
+	}
+	return result
+}
+
+// PolicyFindings returns the projects whose latest scan had policy findings
+func (s *Summary) PolicyFindings() []ProjectStats {
+	var result []ProjectStats
+	for _, stats := range s.Projects {
+		if len(stats.PolicyFindings) > 0 {
+			result = append(result, stats)
+		}
+	}
+	return result
+}
+
+// Lookup returns the figures for the named project, or nil if it was not scanned in the period
+func (s *Summary) Lookup(name string) *ProjectStats {
+	for i := range s.Projects {
+		if s.Projects[i].Project == name {
+			return &s.Projects[i]
+		}
+	}
+	return nil
+}
+
+// countGroup records a drifted scan for a team or tag
+func countGroup(groups map[string]*GroupStats, name string, project string) {
+	group, ok := groups[name]
+	if !ok {
+		group = &GroupStats{Name: name, projectsRecorded: make(map[string]bool)}
+		groups[name] = group
+	}
+	group.DriftedScans++
+	if !group.projectsRecorded[project] {
+		group.projectsRecorded[project] = true
+		group.DriftedProjects++
+	}
+}
+
+// sortedGroups returns the groups ordered by drifted scans, then name
+func sortedGroups(groups map[string]*GroupStats) []GroupStats {
+	result := make([]GroupStats, 0, len(groups))
+	for _, group := range groups {
+		result = append(result, *group)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].DriftedScans != result[j].DriftedScans {
+			return result[i].DriftedScans > result[j].DriftedScans
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// containsString reports whether list contains value
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,318 @@
+package report
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/terradrift-watcher/internal/config"
+	"github.com/terradrift-watcher/internal/state"
+	"github.com/terradrift-watcher/internal/terraform"
+)
+
+func TestBuild(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(hours int) time.Time { return start.Add(time.Duration(hours) * time.Hour) }
+
+	records := []state.HistoryRecord{
+		{Time: at(0), Project: "network", Status: "drifted", Owners: []string{"platform"}, Tags: []string{"prod"}, Fingerprint: "1a2b"},
+		{Time: at(4), Project: "network", Status: "drifted", Owners: []string{"platform"}, Tags: []string{"prod"}, Fingerprint: "3c4d"},
+		{Time: at(6), Project: "network", Status: "clean"},
+		{Time: at(1), Project: "database", Status: "clean"},
+		{Time: at(2), Project: "database", Status: "drifted", Tags: []string{"prod"}},
+		{Time: at(3), Project: "database", Status: "error"},
+		// Outside the reporting period
+		{Time: at(100), Project: "network", Status: "drifted"},
+	}
+
+	summary := Build(records, start, at(10))
+
+	if summary.Scans != 6 {
+		t.Errorf("Expected 6 scans, got %d", summary.Scans)
+	}
+	if summary.DriftedScans != 3 || summary.ErrorScans != 1 {
+		t.Errorf("Expected 3 drifted and 1 error scans, got %d and %d", summary.DriftedScans, summary.ErrorScans)
+	}
+	if summary.ResolvedDrifts != 1 || summary.MTTR != 6*time.Hour {
+		t.Errorf("Expected 1 remediation with MTTR 6h, got %d with %v", summary.ResolvedDrifts, summary.MTTR)
+	}
+	if stats := summary.Lookup("network"); stats == nil || stats.Fingerprint != "3c4d" {
+		t.Errorf("Expected the fingerprint of network's latest drift, got %+v", stats)
+	}
+	if len(summary.OpenDrift) != 0 {
+		t.Errorf("Expected no open drift (database last errored), got %v", summary.OpenDrift)
+	}
+
+	mostDrifting := summary.MostDrifting(10)
+	if len(mostDrifting) != 2 || mostDrifting[0].Project != "network" {
+		t.Errorf("Expected network to be the most drifting project, got %+v", mostDrifting)
+	}
+
+	if len(summary.ByTag) != 1 || summary.ByTag[0].Name != "prod" || summary.ByTag[0].DriftedProjects != 2 {
+		t.Errorf("Expected prod tag with 2 drifted projects, got %+v", summary.ByTag)
+	}
+	if len(summary.ByOwner) != 1 || summary.ByOwner[0].DriftedScans != 2 {
+		t.Errorf("Expected platform owner with 2 drifted scans, got %+v", summary.ByOwner)
+	}
+}
+
+func TestComputeHealth(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	records := []state.HistoryRecord{
+		{Time: start, Project: "network", Status: "drifted", Tags: []string{"prod"}},
+		{Time: start.Add(time.Hour), Project: "network", Status: "clean", Tags: []string{"prod"}},
+		{Time: start, Project: "database", Status: "drifted", Tags: []string{"prod"}},
+		{Time: start, Project: "sandbox", Status: "clean", Tags: []string{"dev"}},
+		{Time: start, Project: "legacy", Status: "error", Tags: []string{"dev"}},
+		{Time: start, Project: "removed", Status: "drifted"},
+	}
+
+	summary := Build(records, start, start.Add(2*time.Hour))
+	summary.ComputeHealth(map[string][]string{
+		"network":  {"prod"},
+		"database": {"prod"},
+		"sandbox":  {"dev"},
+		"legacy":   {"dev"},
+		"unused":   {"dev"},
+	})
+
+	if h := summary.Health; h.Projects != 3 || h.DriftFree != 2 || h.Unknown != 1 {
+		t.Errorf("Expected 2 of 3 projects drift-free and 1 unknown, got %+v", h)
+	}
+	if len(summary.HealthByTag) != 2 || summary.HealthByTag[0].Name != "dev" || summary.HealthByTag[1].Percent() != 50 {
+		t.Errorf("Expected dev and prod (50%%) health, got %+v", summary.HealthByTag)
+	}
+
+	if h := summary.HealthByTag[0]; h.Projects != 1 || h.Percent() != 100 {
+		t.Errorf("Expected dev to be 1 of 1 drift-free, got %+v", h)
+	}
+	if message, color := badgeMessage(Health{Unknown: 2}); message != "unknown" || color != badgeGrey {
+		t.Errorf("Expected a grey unknown badge, got %s %s", message, color)
+	}
+	if message, color := badgeMessage(summary.Health); message != "67%" || color != badgeRed {
+		t.Errorf("Expected a red 67%% badge, got %s %s", message, color)
+	}
+}
+
+func TestBuildErrorCategories(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	records := []state.HistoryRecord{
+		{Time: start, Project: "network", Status: "error", ErrorCategory: "auth"},
+		{Time: start.Add(time.Hour), Project: "network", Status: "error", ErrorCategory: "auth"},
+		{Time: start, Project: "database", Status: "error", ErrorCategory: "lock"},
+		{Time: start.Add(time.Hour), Project: "database", Status: "error"},
+	}
+
+	summary := Build(records, start, start.Add(2*time.Hour))
+	if len(summary.ByError) != 3 || summary.ByError[0].Category != "auth" || summary.ByError[0].Scans != 2 {
+		t.Fatalf("Expected auth to be the most frequent cause, got %+v", summary.ByError)
+	}
+	if got := summary.ByError[0].Projects; len(got) != 1 || got[0] != "network" {
+		t.Errorf("Expected auth failures in network only, got %v", got)
+	}
+	if stats := summary.Lookup("database"); stats == nil || stats.LastError != "unknown" {
+		t.Errorf("Expected an unclassified latest failure to count as unknown, got %+v", stats)
+	}
+}
+
+func TestBuildVersionMismatches(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	records := []state.HistoryRecord{
+		{Time: start, Project: "network", Status: "version_mismatch", ErrorCategory: "version",
+			StateVersion: "1.9.0", LocalVersion: "1.5.7"},
+		{Time: start, Project: "database", Status: "version_mismatch", ErrorCategory: "version",
+			StateVersion: "1.9.0", LocalVersion: "1.5.7"},
+		{Time: start.Add(time.Hour), Project: "database", Status: "clean"},
+	}
+
+	summary := Build(records, start, start.Add(2*time.Hour))
+	if summary.ErrorScans != 2 || len(summary.ByError) != 1 || summary.ByError[0].Category != "version" {
+		t.Fatalf("Expected version mismatches to count as failed scans, got %d, %+v", summary.ErrorScans, summary.ByError)
+	}
+	mismatches := summary.VersionMismatches()
+	if len(mismatches) != 1 || mismatches[0].Project != "network" || mismatches[0].StateVersion != "1.9.0" {
+		t.Errorf("Expected only network to be mismatched, got %+v", mismatches)
+	}
+	var b strings.Builder
+	if err := RenderMarkdown(&b, summary); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), "| network | 1.9.0 | 1.5.7 |") {
+		t.Errorf("Expected the mismatch in the markdown report, got:\n%s", b.String())
+	}
+}
+
+func TestBuildWarnings(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	deprecated := terraform.Warning{Summary: "Argument is deprecated", Resource: "aws_s3_bucket.logs", Detail: "Use aws_s3_bucket_acl"}
+	records := []state.HistoryRecord{
+		{Time: start, Project: "network", Status: "clean", Warnings: []terraform.Warning{deprecated}},
+		{Time: start.Add(time.Hour), Project: "network", Status: "error", ErrorCategory: "auth"},
+		{Time: start, Project: "database", Status: "drifted", Warnings: []terraform.Warning{deprecated}},
+		{Time: start.Add(time.Hour), Project: "database", Status: "clean"},
+	}
+
+	summary := Build(records, start, start.Add(2*time.Hour))
+	warnings := summary.Warnings()
+	if len(warnings) != 1 || warnings[0].Project != "network" {
+		t.Fatalf("Expected only network to have warnings after a failed scan, got %+v", warnings)
+	}
+	var b strings.Builder
+	if err := RenderMarkdown(&b, summary); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), "| network | Argument is deprecated | aws_s3_bucket.logs | Use aws_s3_bucket_acl |") {
+		t.Errorf("Expected the warning in the markdown report, got:\n%s", b.String())
+	}
+}
+
+func TestBuildOutputs(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	outputs := map[string]string{"region": "eu-west-1", "cluster_endpoint": "https://eks.example.com"}
+	records := []state.HistoryRecord{
+		{Time: start, Project: "eks", Status: "clean", Outputs: outputs},
+		{Time: start.Add(time.Hour), Project: "eks", Status: "drifted", Fingerprint: "1a2b", Outputs: outputs},
+	}
+
+	summary := Build(records, start, start.Add(2*time.Hour))
+	if stats := summary.Lookup("eks"); stats == nil || stats.Outputs["region"] != "eu-west-1" {
+		t.Fatalf("Expected the captured outputs of eks, got %+v", stats)
+	}
+	var b strings.Builder
+	if err := RenderMarkdown(&b, summary); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), "- eks [cluster_endpoint: https://eks.example.com, region: eu-west-1] `1a2b`") {
+		t.Errorf("Expected the outputs with the open drift, got:\n%s", b.String())
+	}
+}
+
+func TestBuildFlapping(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var records []state.HistoryRecord
+	add := func(project string, statuses ...string) {
+		for _, status := range statuses {
+			records = append(records, state.HistoryRecord{Time: start.Add(time.Duration(len(records)) * time.Hour), Project: project, Status: status})
+		}
+	}
+	// Failed scans in between do not count as changes
+	add("dns", "drifted", "clean", "error", "drifted", "noise", "drifted")
+	add("network", "drifted", "drifted", "clean", "clean")
+	// Changes older than the latest scans no longer count
+	add("legacy", "drifted", "clean", "drifted", "clean", "clean", "clean", "clean", "clean", "clean", "clean", "clean", "clean")
+
+	summary := Build(records, start, start.Add(100*time.Hour))
+	if stats := summary.Lookup("dns"); stats == nil || stats.StateChanges != 4 || !stats.Flapping || stats.Status() != StatusFlapping {
+		t.Errorf("Expected dns to be flapping with 4 changes, got %+v", stats)
+	}
+	if stats := summary.Lookup("network"); stats == nil || stats.StateChanges != 1 || stats.Flapping {
+		t.Errorf("Expected network not to be flapping, got %+v", stats)
+	}
+	if stats := summary.Lookup("legacy"); stats == nil || stats.StateChanges != 1 {
+		t.Errorf("Expected only legacy's latest 10 scans to count, got %+v", stats)
+	}
+	if flapping := summary.FlappingProjects(); len(flapping) != 1 || flapping[0].Project != "dns" {
+		t.Errorf("Expected only dns to be flapping, got %+v", flapping)
+	}
+
+	summary.MarkFlapping(5)
+	if len(summary.FlappingProjects()) != 0 {
+		t.Error("Expected no flapping projects at a threshold of 5")
+	}
+
+	var b strings.Builder
+	summary.MarkFlapping(4)
+	if err := RenderMarkdown(&b, summary); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), "## Flapping Projects") || !strings.Contains(b.String(), "| dns | 4 | 6 | drifted |") {
+		t.Errorf("Expected a flapping section, got:\n%s", b.String())
+	}
+}
+
+func TestComputeSLOs(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(hours int) time.Time { return start.Add(time.Duration(hours) * time.Hour) }
+	prod := []string{"prod"}
+	driftSince := at(-30)
+
+	records := []state.HistoryRecord{
+		// Resolved within 24h
+		{Time: at(0), Project: "network", Status: "drifted", Tags: prod},
+		{Time: at(10), Project: "network", Status: "clean", Tags: prod},
+		// Began before the period, so it took 40h to resolve
+		{Time: at(1), Project: "database", Status: "drifted", Tags: prod, DriftSince: &driftSince},
+		{Time: at(10), Project: "database", Status: "clean", Tags: prod},
+		// Open for 30h at the end of the period, past the objective
+		{Time: at(20), Project: "queue", Status: "drifted", Tags: prod},
+		{Time: at(40), Project: "queue", Status: "error", Tags: prod},
+		// Open, but not yet past the objective
+		{Time: at(45), Project: "cache", Status: "drifted", Tags: prod},
+		// Not covered by the SLO
+		{Time: at(0), Project: "sandbox", Status: "drifted", Tags: []string{"dev"}},
+	}
+
+	summary := Build(records, start, at(50))
+	summary.ComputeSLOs([]config.SLO{
+		{Name: "prod-24h", Tags: prod, ResolveWithin: "24h", Target: 50},
+		{Name: "all-3d", ResolveWithin: "3d"},
+	})
+
+	if len(summary.SLOs) != 2 {
+		t.Fatalf("Expected 2 SLOs, got %+v", summary.SLOs)
+	}
+	slo := summary.SLOs[0]
+	if slo.Episodes != 3 || slo.Met != 1 || len(slo.Breaching) != 1 || slo.Breaching[0] != "queue" {
+		t.Errorf("Expected 1 of 3 episodes in time with queue breaching, got %+v", slo)
+	}
+	if slo.Compliant() || slo.Outcome() != "missed" {
+		t.Errorf("Expected 33%% to miss a 50%% target, got %.0f%%", slo.Percent())
+	}
+	if all := summary.SLOs[1]; all.Episodes != 2 || all.Met != 2 || !all.Compliant() || all.Target != config.DefaultSLOTarget {
+		t.Errorf("Expected both resolved drifts to meet the 3d SLO, got %+v", all)
+	}
+
+	var b strings.Builder
+	if err := RenderMarkdown(&b, summary); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), "| prod-24h | 24h0m0s | 33% (1 of 3) | 50% | missed | queue |") {
+		t.Errorf("Expected the SLO table in the report, got:\n%s", b.String())
+	}
+}
+
+func TestBuildRecordedTimeToRemediate(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	driftSince := start.Add(-48 * time.Hour)
+
+	// Drift that began before the period is remediated after its recorded time, not the
+	// time since the period started
+	records := []state.HistoryRecord{
+		{Time: start.Add(2 * time.Hour), Project: "network", Status: "clean", DriftSince: &driftSince, TimeToRemediate: 50 * time.Hour},
+		{Time: start.Add(3 * time.Hour), Project: "database", Status: "drifted"},
+		{Time: start.Add(5 * time.Hour), Project: "database", Status: "clean"},
+	}
+
+	summary := Build(records, start, start.Add(10*time.Hour))
+	if summary.ResolvedDrifts != 2 || summary.MTTR != 26*time.Hour {
+		t.Errorf("Expected 2 remediations with MTTR 26h, got %d with %v", summary.ResolvedDrifts, summary.MTTR)
+	}
+	if stats := summary.Lookup("network"); stats == nil || stats.MTTR != 50*time.Hour {
+		t.Errorf("Expected network's MTTR of 50h, got %+v", stats)
+	}
+}
+
+func TestRenderMarkdownRunbook(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	records := []state.HistoryRecord{
+		{Time: start.Add(time.Hour), Project: "network", Status: "drifted", Description: "Shared VPC", RunbookURL: "https://wiki.example.com/network"},
+	}
+
+	var b strings.Builder
+	if err := RenderMarkdown(&b, Build(records, start, start.Add(10*time.Hour))); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), "- network — Shared VPC ([runbook](https://wiki.example.com/network))") {
+		t.Errorf("Expected the drifted project with its runbook, got:\n%s", b.String())
+	}
+}
@@ -0,0 +1,149 @@
+// Package signing signs the files the watcher writes with an Ed25519 key, so compliance tooling
+// can verify that reports and plan artifacts were not changed after the run
+package signing
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// SignatureExt is appended to the path of a signed file to name its detached signature. The
+// signature holds the raw 64 bytes, so it can also be checked with
+// `openssl pkeyutl -verify -pubin -inkey key.pub -rawin -in FILE -sigfile FILE.sig`.
+const SignatureExt = ".sig"
+
+// ErrInvalidSignature is returned for a file whose signature does not match its contents
+var ErrInvalidSignature = errors.New("signature does not match the file")
+
+// GenerateKey returns a new private key as PEM-encoded PKCS #8 and its public key as PEM-encoded
+// PKIX, the formats LoadPrivateKey and LoadPublicKey read
+func GenerateKey() ([]byte, []byte, error) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	privateDER, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}),
+		pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}), nil
+}
+
+// LoadPrivateKey reads a PEM-encoded PKCS #8 Ed25519 private key, as written by
+// `openssl genpkey -algorithm ed25519`
+func LoadPrivateKey(path string) (ed25519.PrivateKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key in %s: %w", path, err)
+	}
+	privateKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%s is not an Ed25519 private key", path)
+	}
+	return privateKey, nil
+}
+
+// LoadPublicKey reads a PEM-encoded PKIX Ed25519 public key. A private key file is accepted
+// too, its public key being derived from it.
+func LoadPublicKey(path string) (ed25519.PublicKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	if block.Type == "PRIVATE KEY" {
+		privateKey, err := LoadPrivateKey(path)
+		if err != nil {
+			return nil, err
+		}
+		return privateKey.Public().(ed25519.PublicKey), nil
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key in %s: %w", path, err)
+	}
+	publicKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("%s is not an Ed25519 public key", path)
+	}
+	return publicKey, nil
+}
+
+// readPEM reads the first PEM block of a key file
+func readPEM(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM-encoded key in %s", path)
+	}
+	return block, nil
+}
+
+// SignFile writes the detached signature of a file next to it
+func SignFile(key ed25519.PrivateKey, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s for signing: %w", path, err)
+	}
+	if err := os.WriteFile(path+SignatureExt, ed25519.Sign(key, data), 0644); err != nil {
+		return fmt.Errorf("failed to write signature: %w", err)
+	}
+	return nil
+}
+
+// Sign returns the detached signature of data with the private key in keyFile, for content
+// that is not kept in a file
+func Sign(keyFile string, data []byte) ([]byte, error) {
+	key, err := LoadPrivateKey(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	return ed25519.Sign(key, data), nil
+}
+
+// SignFiles signs each file with the private key in keyFile
+func SignFiles(keyFile string, paths ...string) error {
+	key, err := LoadPrivateKey(keyFile)
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		if err := SignFile(key, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// VerifyFile checks a file against its detached signature, returning ErrInvalidSignature
+// when it was changed after signing
+func VerifyFile(key ed25519.PublicKey, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	signature, err := os.ReadFile(path + SignatureExt)
+	if err != nil {
+		return fmt.Errorf("failed to read signature: %w", err)
+	}
+	if !ed25519.Verify(key, data, signature) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package signing
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeKeys writes a new key pair to a temporary directory and returns the key file paths
+func writeKeys(t *testing.T) (string, string) {
+	t.Helper()
+	privatePEM, publicPEM, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	privatePath, publicPath := filepath.Join(dir, "signing.key"), filepath.Join(dir, "signing.pub")
+	if err := os.WriteFile(privatePath, privatePEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(publicPath, publicPEM, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return privatePath, publicPath
+}
+
+func TestSignAndVerifyFile(t *testing.T) {
+	privatePath, publicPath := writeKeys(t)
+	report := filepath.Join(t.TempDir(), "report.html")
+	if err := os.WriteFile(report, []byte("<h1>Drift Report</h1>"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := SignFiles(privatePath, report); err != nil {
+		t.Fatalf("SignFiles failed: %v", err)
+	}
+
+	publicKey, err := LoadPublicKey(publicPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyFile(publicKey, report); err != nil {
+		t.Errorf("Expected a valid signature, got %v", err)
+	}
+	// The public key can also be derived from the private key file
+	if derived, err := LoadPublicKey(privatePath); err != nil || !derived.Equal(publicKey) {
+		t.Errorf("Expected the derived public key to match, got %v", err)
+	}
+
+	if err := os.WriteFile(report, []byte("<h1>Nothing drifted</h1>"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyFile(publicKey, report); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected a tampered file to fail verification, got %v", err)
+	}
+
+	_, otherPublic := writeKeys(t)
+	otherKey, err := LoadPublicKey(otherPublic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := SignFiles(privatePath, report); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyFile(otherKey, report); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected another key to fail verification, got %v", err)
+	}
+}
+
+func TestLoadPrivateKeyRejectsOtherKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "key.pem")
+	if err := os.WriteFile(path, []byte("not a key"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadPrivateKey(path); err == nil {
+		t.Error("Expected an error for a file without a PEM key")
+	}
+}
+
+func TestSignData(t *testing.T) {
+	privatePath, publicPath := writeKeys(t)
+	data := []byte("Apply complete! Resources: 0 added, 1 changed, 0 destroyed.")
+	signature, err := Sign(privatePath, data)
+	if err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+	publicKey, err := LoadPublicKey(publicPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ed25519.Verify(publicKey, data, signature) {
+		t.Error("Expected the signature to verify")
+	}
+	if _, err := Sign(filepath.Join(t.TempDir(), "missing.key"), data); err == nil {
+		t.Error("Expected an error for a missing key")
+	}
+}
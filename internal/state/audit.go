@@ -0,0 +1,139 @@
+package state
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// AuditFileName is the single log audit records were appended to before each record was kept
+// as its own object. It is still read, so records written then are not lost.
+const AuditFileName = "audit.jsonl"
+
+// auditKeyTime formats the time of an audit record at the start of its key, so keys sort and
+// compare in time order
+const auditKeyTime = "20060102T150405.000000000Z"
+
+// Audited actions. Triage decisions are recorded as "triage." followed by the decision's
+// action, e.g. triage.ack, or triage.clear when a decision is removed.
+const (
+	AuditPause          = "pause"
+	AuditResume         = "resume"
+	AuditProjectDisable = "project.disable"
+	AuditProjectEnable  = "project.enable"
+	AuditForceUnlock    = "force-unlock"
+	AuditTrigger        = "trigger"
+	AuditRemediate      = "remediate"
+	AuditVerify         = "verify"
+	AuditTriagePrefix   = "triage."
+)
+
+// Where audited actions were requested from
+const (
+	AuditViaCLI    = "cli"
+	AuditViaSocket = "control-socket"
+)
+
+// AuditRecord is one mutating action taken by an operator or a system acting for one
+type AuditRecord struct {
+	Time      time.Time `json:"time"`
+	Principal string    `json:"principal"`            // Who acted, e.g. the OS user or the --user given
+	ClaimedBy string    `json:"claimed_by,omitempty"` // Who a control socket client says it acts for, unverified
+	Action    string    `json:"action"`
+	Projects  []string  `json:"projects,omitempty"`
+	Reason    string    `json:"reason"`
+	Detail    string    `json:"detail,omitempty"` // e.g. the triage decision or pause duration
+	Via       string    `json:"via"`              // cli or control-socket
+}
+
+// AppendAudit records an audit record as an object of its own, named after its time and a
+// random suffix. Appending to a shared log would rewrite it on backends that cannot append,
+// so a CLI action racing the daemon could drop the other's record. The audit log is never
+// pruned.
+func AppendAudit(storage Storage, record AuditRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to write audit record: %w", err)
+	}
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return fmt.Errorf("failed to name audit record: %w", err)
+	}
+	key := record.Time.UTC().Format(auditKeyTime) + "-" + hex.EncodeToString(suffix) + ".json"
+	if err := storage.Put(NamespaceAudit, key, data); err != nil {
+		return fmt.Errorf("failed to write audit record: %w", err)
+	}
+	return nil
+}
+
+// LoadAudit reads audit records at or after since, oldest first
+func LoadAudit(storage Storage, since time.Time) ([]AuditRecord, error) {
+	records, err := loadLegacyAudit(storage, since)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := storage.List(NamespaceAudit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit records: %w", err)
+	}
+	// Keys start with the record's time, so older records are skipped without reading them
+	from := since.UTC().Format(auditKeyTime)
+	for _, key := range keys {
+		if !strings.HasSuffix(key, ".json") || key < from {
+			continue
+		}
+		data, err := storage.Get(NamespaceAudit, key)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read audit record: %w", err)
+		}
+		var record AuditRecord
+		if err := json.Unmarshal(data, &record); err != nil || record.Time.Before(since) {
+			continue
+		}
+		records = append(records, record)
+	}
+
+	sort.SliceStable(records, func(i, j int) bool { return records[i].Time.Before(records[j].Time) })
+	return records, nil
+}
+
+// loadLegacyAudit reads the records at or after since from the log of earlier versions
+func loadLegacyAudit(storage Storage, since time.Time) ([]AuditRecord, error) {
+	data, err := storage.Get(NamespaceAudit, AuditFileName)
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+
+	var records []AuditRecord
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		var record AuditRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			// Skip a corrupt line (e.g. from an interrupted write) rather than losing the log
+			continue
+		}
+		if record.Time.Before(since) {
+			continue
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	return records, nil
+}
@@ -0,0 +1,78 @@
+package state
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAudit(t *testing.T) {
+	storage := NewFileStorage(t.TempDir())
+	if records, err := LoadAudit(storage, time.Time{}); err != nil || len(records) != 0 {
+		t.Fatalf("Expected an empty audit log, got %v, %v", records, err)
+	}
+
+	base := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	records := []AuditRecord{
+		{Time: base, Principal: "alice", Action: AuditPause, Reason: "provider outage", Detail: "for 4h", Via: AuditViaCLI},
+		{Time: base.Add(time.Hour), Principal: "ci", Action: AuditTrigger, Projects: []string{"network"}, Reason: "post-merge", Via: AuditViaSocket},
+		{Time: base.Add(2 * time.Hour), Principal: "bob", Action: AuditTriagePrefix + TriageAck, Projects: []string{"db"}, Reason: "known", Via: AuditViaCLI},
+	}
+	for _, record := range records {
+		if err := AppendAudit(storage, record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	loaded, err := LoadAudit(storage, base.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded) != 2 || loaded[0].Action != AuditTrigger || loaded[0].Projects[0] != "network" || loaded[1].Principal != "bob" {
+		t.Errorf("Expected the records since the trigger, got %+v", loaded)
+	}
+}
+
+func TestAuditConcurrentAppends(t *testing.T) {
+	files := NewFileStorage(t.TempDir())
+	base := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	// Records of the single log kept by earlier versions are still read
+	legacy := fmt.Sprintf(`{"time":%q,"principal":"alice","action":"pause","reason":"outage","via":"cli"}`+"\n", base.Format(time.RFC3339))
+	if err := files.Put(NamespaceAudit, AuditFileName, []byte(legacy)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Encrypted storage cannot append, so a shared log would be rewritten by each writer
+	storage, err := NewEncryptedStorage(files, bytes.Repeat([]byte{7}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	const writers = 20
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// Records of the same instant must not replace each other
+			record := AuditRecord{Time: base.Add(time.Hour), Principal: fmt.Sprintf("user%d", i), Action: AuditTrigger, Via: AuditViaSocket}
+			if err := AppendAudit(storage, record); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	records, err := LoadAudit(storage, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != writers+1 || records[0].Action != AuditPause {
+		t.Fatalf("Expected the legacy record and every concurrent record, got %d: %+v", len(records), records)
+	}
+	if recent, err := LoadAudit(storage, base.Add(time.Minute)); err != nil || len(recent) != writers {
+		t.Errorf("Expected only the records since, got %d, %v", len(recent), err)
+	}
+}
@@ -0,0 +1,88 @@
+package state
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// BundleVersion is the format version of state bundles written by this build
+const BundleVersion = 1
+
+// Bundle is a portable copy of the watcher's state, used to move a deployment between hosts
+// or storage backends without losing drift tracking. It is always plaintext, whatever the
+// encryption of the storage it came from.
+type Bundle struct {
+	Version    int       `json:"version"`
+	ExportedAt time.Time `json:"exported_at"`
+
+	// State holds per-project drift tracking: drift start, fingerprint, fired escalations
+	// and scheduling, plus when digests were last sent
+	State *Store `json:"state"`
+
+	Outbox  []OutboxEntry   `json:"outbox,omitempty"`
+	History []HistoryRecord `json:"history,omitempty"`
+}
+
+// Export collects the state, the outbox and, if requested, the history into a bundle
+func Export(storage Storage, includeHistory bool) (*Bundle, error) {
+	store, err := Load(storage)
+	if err != nil {
+		return nil, err
+	}
+	outbox, err := LoadOutbox(storage)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := &Bundle{
+		Version:    BundleVersion,
+		ExportedAt: time.Now().UTC(),
+		State:      store,
+		Outbox:     outbox,
+	}
+	if includeHistory {
+		if bundle.History, err = LoadHistory(storage, time.Time{}); err != nil {
+			return nil, err
+		}
+	}
+	return bundle, nil
+}
+
+// Import replaces the state and outbox in storage with those of a bundle. The history is
+// only replaced when the bundle contains one.
+func Import(storage Storage, bundle *Bundle) error {
+	if bundle.Version != BundleVersion {
+		return fmt.Errorf("unsupported state bundle version %d (expected %d)", bundle.Version, BundleVersion)
+	}
+	if bundle.State == nil {
+		return fmt.Errorf("state bundle has no state")
+	}
+
+	store := bundle.State
+	store.storage = storage
+	if store.Projects == nil {
+		store.Projects = make(map[string]*ProjectState)
+	}
+	if err := store.Save(); err != nil {
+		return err
+	}
+	if err := SaveOutbox(storage, bundle.Outbox); err != nil {
+		return err
+	}
+
+	if len(bundle.History) > 0 {
+		var buf bytes.Buffer
+		encoder := json.NewEncoder(&buf)
+		for _, record := range bundle.History {
+			if err := encoder.Encode(record); err != nil {
+				return fmt.Errorf("failed to write history record: %w", err)
+			}
+		}
+		if err := storage.Put(NamespaceHistory, HistoryFileName, buf.Bytes()); err != nil {
+			return fmt.Errorf("failed to write history: %w", err)
+		}
+	}
+	return nil
+}
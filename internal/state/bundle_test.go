@@ -0,0 +1,60 @@
+package state
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestExportImport(t *testing.T) {
+	source := NewFileStorage(t.TempDir())
+	store, err := Load(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	driftSince := time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC)
+	network := store.Project("network")
+	network.MarkDrifted(driftSince)
+	network.Fingerprint = "9f86d081884c7d65"
+	network.Escalations = []string{"page-sre"}
+	if err := store.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if err := AppendOutbox(source, []OutboxEntry{{Notifier: "slack", Attempts: 1}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := AppendHistory(source, []HistoryRecord{{Time: driftSince, Project: "network", Status: "drifted"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	bundle, err := Export(source, false)
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	if len(bundle.History) != 0 {
+		t.Errorf("Expected no history without includeHistory, got %d records", len(bundle.History))
+	}
+
+	// Move the state to an encrypted backend on another "host"
+	target, _ := NewEncryptedStorage(NewFileStorage(t.TempDir()), bytes.Repeat([]byte{1}, 32))
+	if err := Import(target, bundle); err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+
+	imported, err := Load(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := imported.Project("network")
+	if !got.DriftSince.Equal(driftSince) || got.Fingerprint != "9f86d081884c7d65" || !got.HasEscalated("page-sre") {
+		t.Errorf("Expected drift tracking to survive the move, got %+v", got)
+	}
+	if outbox, _ := LoadOutbox(target); len(outbox) != 1 || outbox[0].Notifier != "slack" {
+		t.Errorf("Expected the outbox to be imported, got %+v", outbox)
+	}
+
+	bundle.Version = BundleVersion + 1
+	if err := Import(target, bundle); err == nil {
+		t.Error("Expected a bundle from a newer version to be rejected")
+	}
+}
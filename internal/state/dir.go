@@ -0,0 +1,40 @@
+package state
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// DefaultDir returns the state directory used when none is configured: terradrift-watcher in
+// the user's cache directory, or a directory per user in the temp directory when there is no
+// home directory. A shared path in the temp directory could be created first by another user.
+func DefaultDir() string {
+	if dir, err := os.UserCacheDir(); err == nil {
+		return filepath.Join(dir, "terradrift-watcher")
+	}
+	return filepath.Join(os.TempDir(), fmt.Sprintf("terradrift-watcher-%d", os.Getuid()))
+}
+
+// PrepareDefaultDir creates the default state directory owner-only, and refuses it when it
+// belongs to another user, who could read the plans kept in it or replace the state
+func PrepareDefaultDir() error {
+	dir := DefaultDir()
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	return checkDir(dir, os.Getuid())
+}
+
+// checkDir returns an error when dir is not owned by uid
+func checkDir(dir string, uid int) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("failed to check state directory: %w", err)
+	}
+	if owner, ok := dirOwner(info); ok && owner != uid {
+		return fmt.Errorf("refusing state directory %s: it belongs to uid %d, not to the current user; "+
+			"remove it or set state_dir", dir, owner)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package state
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+)
+
+// encryptedMagic prefixes every encrypted object so plaintext written before encryption
+// was enabled can still be read
+var encryptedMagic = []byte("TDWAESGCM1\n")
+
+// EncryptedStorage encrypts objects with AES-256-GCM before passing them to another backend.
+// Each object is bound to its namespace and key, so objects cannot be swapped undetected.
+type EncryptedStorage struct {
+	Storage
+	aead cipher.AEAD
+}
+
+// NewEncryptedStorage wraps storage with AES-256-GCM encryption using a 32 byte key
+func NewEncryptedStorage(storage Storage, key []byte) (*EncryptedStorage, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encryption key: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up encryption: %w", err)
+	}
+	return &EncryptedStorage{Storage: storage, aead: aead}, nil
+}
+
+// Get implements Storage
+func (s *EncryptedStorage) Get(namespace, key string) ([]byte, error) {
+	data, err := s.Storage.Get(namespace, key)
+	if err != nil {
+		return nil, err
+	}
+
+	// Objects written before encryption was enabled are returned as-is and encrypted on
+	// their next write
+	if !bytes.HasPrefix(data, encryptedMagic) {
+		return data, nil
+	}
+
+	data = data[len(encryptedMagic):]
+	nonceSize := s.aead.NonceSize()
+	if len(data) < nonceSize {
+		return nil, fmt.Errorf("encrypted %s/%s is truncated", namespace, key)
+	}
+	plaintext, err := s.aead.Open(nil, data[:nonceSize], data[nonceSize:], objectID(namespace, key))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt %s/%s (wrong key?): %w", namespace, key, err)
+	}
+	return plaintext, nil
+}
+
+// Put implements Storage
+func (s *EncryptedStorage) Put(namespace, key string, data []byte) error {
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := append([]byte{}, encryptedMagic...)
+	sealed = append(sealed, nonce...)
+	sealed = s.aead.Seal(sealed, nonce, data, objectID(namespace, key))
+	return s.Storage.Put(namespace, key, sealed)
+}
+
+// objectID is the additional authenticated data binding a ciphertext to its object
+func objectID(namespace, key string) []byte {
+	return []byte(namespace + "/" + key)
+}
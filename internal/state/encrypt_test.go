@@ -0,0 +1,53 @@
+package state
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestEncryptedStorage(t *testing.T) {
+	dir := t.TempDir()
+	files := NewFileStorage(dir)
+	key := bytes.Repeat([]byte{7}, 32)
+
+	// History written before encryption was enabled stays readable
+	if err := AppendHistory(files, []HistoryRecord{{Project: "network", Status: "clean", Summary: "plain"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	storage, err := NewEncryptedStorage(files, key)
+	if err != nil {
+		t.Fatalf("NewEncryptedStorage failed: %v", err)
+	}
+	if err := AppendHistory(storage, []HistoryRecord{{Project: "network", Status: "drifted", Summary: "db-password-rotated"}}); err != nil {
+		t.Fatalf("AppendHistory failed: %v", err)
+	}
+
+	raw, err := os.ReadFile(filepath.Join(dir, NamespaceHistory, HistoryFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(raw, []byte("db-password-rotated")) || bytes.Contains(raw, []byte("plain")) {
+		t.Error("Expected the history file to be encrypted")
+	}
+
+	records, err := LoadHistory(storage, time.Time{})
+	if err != nil || len(records) != 2 || records[1].Summary != "db-password-rotated" {
+		t.Fatalf("Expected both records after decryption, got %+v, %v", records, err)
+	}
+
+	// A different key, or an object moved to another key, must not decrypt
+	other, _ := NewEncryptedStorage(files, bytes.Repeat([]byte{8}, 32))
+	if _, err := other.Get(NamespaceHistory, HistoryFileName); err == nil {
+		t.Error("Expected decryption with the wrong key to fail")
+	}
+	if err := files.Put(NamespaceOutbox, OutboxFileName, raw); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := storage.Get(NamespaceOutbox, OutboxFileName); err == nil {
+		t.Error("Expected an object copied to another key to be rejected")
+	}
+}
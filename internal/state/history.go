@@ -0,0 +1,135 @@
+package state
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/terradrift-watcher/internal/terraform"
+)
+
+// HistoryFileName is the name of the scan history log inside the state directory
+const HistoryFileName = "history.jsonl"
+
+// HistoryRecord is one project scan as kept in the history log
+type HistoryRecord struct {
+	Time     time.Time     `json:"time"`
+	Project  string        `json:"project"`
+	Status   string        `json:"status"`
+	Summary  string        `json:"summary,omitempty"`
+	Owners   []string      `json:"owners,omitempty"`
+	Tags     []string      `json:"tags,omitempty"`
+	Duration time.Duration `json:"duration"`
+
+	Description string `json:"description,omitempty"`
+	RunbookURL  string `json:"runbook_url,omitempty"`
+
+	// DriftSince is when the drift being reported (or just remediated) first appeared
+	DriftSince *time.Time `json:"drift_since,omitempty"`
+
+	// Incident is the ID of the incident the scan belongs to, set from the scan that confirmed
+	// the drift to the clean scan that resolved it
+	Incident string `json:"incident,omitempty"`
+
+	// Verification describes the apply the scan verified, when it ran to verify one
+	Verification string `json:"verification,omitempty"`
+
+	// TimeToRemediate is set on the first clean scan after drift
+	TimeToRemediate time.Duration `json:"time_to_remediate,omitempty"`
+
+	// DirtyFiles lists uncommitted terraform files present when the project was scanned
+	DirtyFiles []string `json:"dirty_files,omitempty"`
+
+	// ChangedModules lists shared local modules that changed since the project's previous scan
+	ChangedModules []string `json:"changed_modules,omitempty"`
+
+	// Changes is the changelog of out-of-band changes found by a drifted scan
+	Changes []terraform.AttributeChange `json:"changes,omitempty"`
+
+	// Fingerprint identifies the drift found by a drifted scan
+	Fingerprint string `json:"fingerprint,omitempty"`
+
+	// ErrorCategory says why a failed scan failed, e.g. auth or backend
+	ErrorCategory string `json:"error_category,omitempty"`
+
+	// TimeoutPhase is the phase of a timed out scan that ran out of time, e.g. init or plan
+	TimeoutPhase string `json:"timeout_phase,omitempty"`
+
+	// StateVersion and LocalVersion are the Terraform versions of a version mismatch: the one
+	// that wrote the state and the one that scanned it
+	StateVersion string `json:"state_version,omitempty"`
+	LocalVersion string `json:"local_version,omitempty"`
+
+	// Outputs are the terraform outputs captured at the project's last clean plan
+	Outputs map[string]string `json:"outputs,omitempty"`
+
+	// Warnings are the terraform warnings printed by the scan's plan
+	Warnings []terraform.Warning `json:"warnings,omitempty"`
+
+	// PolicyFindings are the findings of the scan's policy stages
+	PolicyFindings []terraform.PolicyFinding `json:"policy_findings,omitempty"`
+}
+
+// AppendHistory appends records to the history log
+func AppendHistory(storage Storage, records []HistoryRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return fmt.Errorf("failed to write history record: %w", err)
+		}
+	}
+
+	if err := appendObject(storage, NamespaceHistory, HistoryFileName, buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+	return nil
+}
+
+// RenameHistory attributes records of renamed projects to their current names, given a map of
+// aliases to current names
+func RenameHistory(records []HistoryRecord, aliases map[string]string) {
+	for i := range records {
+		if name, ok := aliases[records[i].Project]; ok {
+			records[i].Project = name
+		}
+	}
+}
+
+// LoadHistory reads history records at or after since, oldest first
+func LoadHistory(storage Storage, since time.Time) ([]HistoryRecord, error) {
+	data, err := storage.Get(NamespaceHistory, HistoryFileName)
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+
+	var records []HistoryRecord
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		var record HistoryRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			// Skip a corrupt line (e.g. from an interrupted write) rather than losing all history
+			continue
+		}
+		if record.Time.Before(since) {
+			continue
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+
+	return records, nil
+}
@@ -0,0 +1,110 @@
+package state
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"time"
+)
+
+// Incident lifecycle states
+const (
+	IncidentOpen     = "open"
+	IncidentResolved = "resolved"
+)
+
+// Incident is one continuous drift of a project, from the scan that confirmed it to the clean
+// scan that resolved it. Every notification about the drift carries its ID.
+type Incident struct {
+	ID         string     `json:"id"`
+	Project    string     `json:"project"`
+	State      string     `json:"state"`     // open or resolved
+	OpenedAt   time.Time  `json:"opened_at"` // When the drift first appeared
+	LastSeen   time.Time  `json:"last_seen"` // The latest scan that found the drift
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+
+	// Verification describes the apply whose verification scan resolved the incident
+	Verification string `json:"verification,omitempty"`
+
+	// Scans counts the drifted scans in the history, and Fingerprint identifies the latest drift
+	Scans       int    `json:"scans"`
+	Fingerprint string `json:"fingerprint,omitempty"`
+
+	// Escalations lists the escalation rules and SLOs that fired for an open incident
+	Escalations []string `json:"escalations,omitempty"`
+}
+
+// IncidentID returns the stable ID of the continuous drift of a project that began at since
+func IncidentID(project string, since time.Time) string {
+	sum := sha256.Sum256([]byte(project + "\x00" + since.UTC().Format(time.RFC3339Nano)))
+	return "DRIFT-" + hex.EncodeToString(sum[:4])
+}
+
+// Incidents reconstructs the lifecycle of the incidents in the history records, completed with
+// the open incidents of the store whose drift began before the records. Open incidents come
+// first, then the most recently opened.
+func Incidents(records []HistoryRecord, store *Store) []Incident {
+	sorted := append([]HistoryRecord(nil), records...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Time.Before(sorted[j].Time) })
+
+	byID := make(map[string]*Incident)
+	var order []string
+	for _, record := range sorted {
+		if record.Incident == "" {
+			continue
+		}
+		incident, ok := byID[record.Incident]
+		if !ok {
+			incident = &Incident{ID: record.Incident, Project: record.Project, State: IncidentOpen, OpenedAt: record.Time}
+			if record.DriftSince != nil {
+				incident.OpenedAt = *record.DriftSince
+			}
+			byID[record.Incident] = incident
+			order = append(order, record.Incident)
+		}
+		switch {
+		case record.TimeToRemediate > 0:
+			// Only the clean scan that resolves drift records the time to remediation
+			resolvedAt := record.Time
+			incident.State = IncidentResolved
+			incident.ResolvedAt = &resolvedAt
+			incident.Verification = record.Verification
+		case record.Fingerprint != "":
+			incident.Scans++
+			incident.LastSeen = record.Time
+			incident.Fingerprint = record.Fingerprint
+		}
+	}
+
+	for name, ps := range store.Projects {
+		if ps.Incident == "" {
+			continue
+		}
+		incident, ok := byID[ps.Incident]
+		if !ok {
+			incident = &Incident{ID: ps.Incident, Project: name, OpenedAt: ps.DriftSince, LastSeen: ps.LastScanned, Fingerprint: ps.Fingerprint}
+			byID[ps.Incident] = incident
+			order = append(order, ps.Incident)
+		}
+		incident.State = IncidentOpen
+		incident.ResolvedAt = nil
+		incident.Escalations = ps.Escalations
+	}
+
+	incidents := make([]Incident, 0, len(order))
+	for _, id := range order {
+		incident := byID[id]
+		// The store is authoritative for what is still open, e.g. when the resolving scan was pruned
+		if ps, ok := store.Projects[incident.Project]; incident.State == IncidentOpen && (!ok || ps.Incident != id) {
+			incident.State = IncidentResolved
+		}
+		incidents = append(incidents, *incident)
+	}
+	sort.SliceStable(incidents, func(i, j int) bool {
+		if (incidents[i].State == IncidentOpen) != (incidents[j].State == IncidentOpen) {
+			return incidents[i].State == IncidentOpen
+		}
+		return incidents[i].OpenedAt.After(incidents[j].OpenedAt)
+	})
+	return incidents
+}
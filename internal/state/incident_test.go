@@ -0,0 +1,65 @@
+package state
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIncidentID(t *testing.T) {
+	since := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	id := IncidentID("network", since)
+	if id != IncidentID("network", since.In(time.FixedZone("CET", 3600))) {
+		t.Error("Expected the ID not to depend on the time zone")
+	}
+	if id == IncidentID("network", since.Add(time.Minute)) || id == IncidentID("db", since) {
+		t.Error("Expected another drift to get another ID")
+	}
+	if len(id) != len("DRIFT-")+8 {
+		t.Errorf("Unexpected ID %q", id)
+	}
+}
+
+func TestIncidents(t *testing.T) {
+	base := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	resolvedSince := base
+	openSince := base.Add(48 * time.Hour)
+	resolved := IncidentID("network", resolvedSince)
+	open := IncidentID("db", openSince)
+
+	records := []HistoryRecord{
+		{Time: base.Add(2 * time.Hour), Project: "network", Status: "drifted", Incident: resolved, DriftSince: &resolvedSince, Fingerprint: "abc"},
+		{Time: base, Project: "network", Status: "drifted", Incident: resolved, DriftSince: &resolvedSince, Fingerprint: "abc"},
+		{Time: base.Add(4 * time.Hour), Project: "network", Status: "clean", Incident: resolved, DriftSince: &resolvedSince, TimeToRemediate: 4 * time.Hour, Verification: "remediate --approved"},
+		{Time: base.Add(5 * time.Hour), Project: "network", Status: "clean"},
+		{Time: openSince, Project: "db", Status: "drifted", Incident: open, DriftSince: &openSince, Fingerprint: "def"},
+	}
+	store := &Store{Projects: map[string]*ProjectState{
+		"db":    {DriftSince: openSince, Incident: open, Fingerprint: "def", Escalations: []string{"slo:prod"}},
+		"cache": {DriftSince: base.Add(-time.Hour), LastScanned: base.Add(time.Hour), Incident: "DRIFT-cache", Fingerprint: "ghi"},
+	}}
+
+	incidents := Incidents(records, store)
+	if len(incidents) != 3 {
+		t.Fatalf("Expected 3 incidents, got %+v", incidents)
+	}
+	if incidents[0].ID != open || incidents[0].State != IncidentOpen || incidents[0].Scans != 1 || len(incidents[0].Escalations) != 1 {
+		t.Errorf("Expected the newest open incident first, got %+v", incidents[0])
+	}
+	// An incident whose drift began before the history is taken from the store
+	if incidents[1].ID != "DRIFT-cache" || incidents[1].State != IncidentOpen || !incidents[1].OpenedAt.Equal(base.Add(-time.Hour)) {
+		t.Errorf("Expected the open incident of the store second, got %+v", incidents[1])
+	}
+	last := incidents[2]
+	if last.ID != resolved || last.State != IncidentResolved || last.Scans != 2 || last.Fingerprint != "abc" {
+		t.Fatalf("Expected the resolved incident last, got %+v", last)
+	}
+	if !last.OpenedAt.Equal(resolvedSince) || !last.LastSeen.Equal(base.Add(2*time.Hour)) || last.ResolvedAt == nil || !last.ResolvedAt.Equal(base.Add(4*time.Hour)) || last.Verification != "remediate --approved" {
+		t.Errorf("Unexpected lifecycle %+v", last)
+	}
+
+	// An incident the store no longer has open was resolved, even without its resolving scan
+	delete(store.Projects, "db")
+	if incidents := Incidents(records[:2], store); len(incidents) != 2 || incidents[1].State != IncidentResolved {
+		t.Errorf("Expected the incident to be resolved, got %+v", incidents)
+	}
+}
@@ -0,0 +1,123 @@
+package state
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/terradrift-watcher/internal/notifier"
+)
+
+// OutboxFileName is the log of undelivered notifications inside the state directory
+const OutboxFileName = "outbox.jsonl"
+
+// OutboxEntry is a notification that could not be delivered, kept for notify-replay
+type OutboxEntry struct {
+	Time     time.Time           `json:"time"`
+	Notifier string              `json:"notifier"`
+	Alert    notifier.DriftAlert `json:"alert"`
+	Error    string              `json:"error"`
+	Attempts int                 `json:"attempts"` // Delivery attempts, including replays
+}
+
+// AppendOutbox adds undelivered notifications to the outbox, replacing older entries of the
+// same notifier and project (see CoalesceOutbox)
+func AppendOutbox(storage Storage, entries []OutboxEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	existing, err := LoadOutbox(storage)
+	if err != nil {
+		return err
+	}
+	return SaveOutbox(storage, CoalesceOutbox(append(existing, entries...)))
+}
+
+// CoalesceOutbox keeps only the latest of the given entries, oldest first, for each notifier
+// and project, as a newer notification about a project supersedes an undelivered older one.
+// The delivery attempts of replaced entries about the same drift, by fingerprint, are added to
+// the latest one.
+func CoalesceOutbox(entries []OutboxEntry) []OutboxEntry {
+	type outboxKey struct{ notifier, project string }
+	latest := make(map[outboxKey]int)
+	attempts := make([]int, len(entries))
+	for i, entry := range entries {
+		key := outboxKey{entry.Notifier, entry.Alert.Project}
+		attempts[i] = entry.Attempts
+		if previous, ok := latest[key]; ok && entries[previous].Alert.Fingerprint == entry.Alert.Fingerprint {
+			attempts[i] += attempts[previous]
+		}
+		latest[key] = i
+	}
+
+	var coalesced []OutboxEntry
+	for i, entry := range entries {
+		if latest[outboxKey{entry.Notifier, entry.Alert.Project}] == i {
+			entry.Attempts = attempts[i]
+			coalesced = append(coalesced, entry)
+		}
+	}
+	return coalesced
+}
+
+// LoadOutbox reads all undelivered notifications, oldest first
+func LoadOutbox(storage Storage) ([]OutboxEntry, error) {
+	data, err := storage.Get(NamespaceOutbox, OutboxFileName)
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read outbox: %w", err)
+	}
+
+	var entries []OutboxEntry
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var entry OutboxEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("failed to parse outbox entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read outbox: %w", err)
+	}
+
+	return entries, nil
+}
+
+// SaveOutbox replaces the outbox with the given entries, removing it when none remain
+func SaveOutbox(storage Storage, entries []OutboxEntry) error {
+	if len(entries) == 0 {
+		if err := storage.Delete(NamespaceOutbox, OutboxFileName); err != nil {
+			return fmt.Errorf("failed to remove outbox: %w", err)
+		}
+		return nil
+	}
+
+	data, err := encodeOutbox(entries)
+	if err != nil {
+		return err
+	}
+	if err := storage.Put(NamespaceOutbox, OutboxFileName, data); err != nil {
+		return fmt.Errorf("failed to write outbox: %w", err)
+	}
+	return nil
+}
+
+// encodeOutbox renders outbox entries as JSON lines
+func encodeOutbox(entries []OutboxEntry) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, entry := range entries {
+		if err := encoder.Encode(entry); err != nil {
+			return nil, fmt.Errorf("failed to write outbox entry: %w", err)
+		}
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,70 @@
+package state
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/terradrift-watcher/internal/notifier"
+)
+
+func TestAppendOutboxCoalesces(t *testing.T) {
+	storage := NewFileStorage(t.TempDir())
+	now := time.Now()
+	entry := func(minutes int, notifierName, project, fingerprint string) OutboxEntry {
+		return OutboxEntry{Time: now.Add(time.Duration(minutes) * time.Minute), Notifier: notifierName, Attempts: 1,
+			Alert: notifier.DriftAlert{Project: project, Fingerprint: fingerprint}}
+	}
+
+	if err := AppendOutbox(storage, []OutboxEntry{
+		entry(0, "slack", "network", "aaa"),
+		entry(0, "pagerduty", "network", "aaa"),
+		entry(0, "slack", "storage", "bbb"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	// The same drift again, and new drift of another project
+	if err := AppendOutbox(storage, []OutboxEntry{
+		entry(1, "slack", "network", "aaa"),
+		entry(1, "slack", "storage", "ccc"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := LoadOutbox(storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("Expected one entry per notifier and project, got %+v", entries)
+	}
+	if entries[0].Notifier != "pagerduty" || entries[1].Alert.Project != "network" || entries[2].Alert.Project != "storage" {
+		t.Errorf("Expected the latest entries oldest first, got %+v", entries)
+	}
+	if !entries[1].Time.Equal(now.Add(time.Minute)) || entries[1].Attempts != 2 {
+		t.Errorf("Expected the latest network entry with the attempts of the same drift added, got %+v", entries[1])
+	}
+	if entries[2].Alert.Fingerprint != "ccc" || entries[2].Attempts != 1 {
+		t.Errorf("Expected the superseded storage drift replaced without its attempts, got %+v", entries[2])
+	}
+
+	if err := AppendOutbox(storage, nil); err != nil {
+		t.Fatal(err)
+	}
+	if entries, _ := LoadOutbox(storage); len(entries) != 3 {
+		t.Errorf("Expected appending nothing to keep the outbox, got %+v", entries)
+	}
+}
+
+func TestSaveOutboxRemovesEmpty(t *testing.T) {
+	storage := NewFileStorage(t.TempDir())
+	if err := AppendOutbox(storage, []OutboxEntry{{Notifier: "slack"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveOutbox(storage, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := storage.Get(NamespaceOutbox, OutboxFileName); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected the empty outbox to be removed, got %v", err)
+	}
+}
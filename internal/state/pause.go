@@ -0,0 +1,74 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// PauseFileName records that scanning is paused. It is kept apart from the state file so a
+// run in progress, which rewrites the state when it finishes, cannot undo a pause.
+const PauseFileName = "pause.json"
+
+// Pause holds scanning until it is resumed or Until has passed
+type Pause struct {
+	Since  time.Time `json:"since"`
+	Until  time.Time `json:"until,omitempty"` // Zero pauses until resumed
+	Reason string    `json:"reason,omitempty"`
+}
+
+// Active reports whether the pause is still in effect at now
+func (p *Pause) Active(now time.Time) bool {
+	return p != nil && (p.Until.IsZero() || now.Before(p.Until))
+}
+
+// String describes the pause for logs and status output
+func (p *Pause) String() string {
+	s := "paused since " + p.Since.Format(time.RFC3339)
+	if !p.Until.IsZero() {
+		s += " until " + p.Until.Format(time.RFC3339)
+	}
+	if p.Reason != "" {
+		s += ": " + p.Reason
+	}
+	return s
+}
+
+// LoadPause returns the recorded pause, or nil when scanning is not paused. An expired pause
+// is returned as well; check it with Active.
+func LoadPause(storage Storage) (*Pause, error) {
+	data, err := storage.Get(NamespaceState, PauseFileName)
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pause: %w", err)
+	}
+
+	var pause Pause
+	if err := json.Unmarshal(data, &pause); err != nil {
+		return nil, fmt.Errorf("failed to parse pause: %w", err)
+	}
+	return &pause, nil
+}
+
+// SavePause pauses scanning, replacing any earlier pause
+func SavePause(storage Storage, pause Pause) error {
+	data, err := json.MarshalIndent(pause, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode pause: %w", err)
+	}
+	if err := storage.Put(NamespaceState, PauseFileName, data); err != nil {
+		return fmt.Errorf("failed to write pause: %w", err)
+	}
+	return nil
+}
+
+// ClearPause resumes scanning
+func ClearPause(storage Storage) error {
+	if err := storage.Delete(NamespaceState, PauseFileName); err != nil {
+		return fmt.Errorf("failed to remove pause: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package state
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPause(t *testing.T) {
+	storage := NewFileStorage(t.TempDir())
+	if pause, err := LoadPause(storage); err != nil || pause != nil {
+		t.Fatalf("Expected no pause, got %+v (%v)", pause, err)
+	}
+
+	since := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	if err := SavePause(storage, Pause{Since: since, Until: since.Add(time.Hour), Reason: "provider outage"}); err != nil {
+		t.Fatalf("SavePause failed: %v", err)
+	}
+	pause, err := LoadPause(storage)
+	if err != nil || pause == nil {
+		t.Fatalf("Expected the pause, got %+v (%v)", pause, err)
+	}
+	if expected := "paused since 2024-03-01T09:00:00Z until 2024-03-01T10:00:00Z: provider outage"; pause.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, pause.String())
+	}
+
+	// The pause ends at Until; without Until it lasts until resumed
+	if !pause.Active(since.Add(time.Hour-time.Second)) || pause.Active(since.Add(time.Hour)) {
+		t.Error("Expected the pause to be active until, not at, its end")
+	}
+	if !(&Pause{Since: since}).Active(since.Add(365 * 24 * time.Hour)) {
+		t.Error("Expected a pause without end to stay active")
+	}
+	var none *Pause
+	if none.Active(since) {
+		t.Error("Expected no pause to be inactive")
+	}
+
+	if err := ClearPause(storage); err != nil {
+		t.Fatalf("ClearPause failed: %v", err)
+	}
+	if pause, err := LoadPause(storage); err != nil || pause != nil {
+		t.Errorf("Expected the pause to be cleared, got %+v (%v)", pause, err)
+	}
+}
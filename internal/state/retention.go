@@ -0,0 +1,146 @@
+package state
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// logKeyTime formats the time a saved log was written at the end of its key
+const logKeyTime = "20060102T150405Z"
+
+// LogKey returns the key of a log saved for a project, such as its plan lines past the
+// verbose log limit or its apply output, named after the project and the time it was saved
+func LogKey(project string, t time.Time) string {
+	return project + "-" + t.UTC().Format(logKeyTime) + ".log"
+}
+
+// parseLogKey returns the project and time of a key made by LogKey
+func parseLogKey(key string) (string, time.Time, bool) {
+	name, ok := strings.CutSuffix(key, ".log")
+	if !ok || len(name) < len(logKeyTime)+2 || name[len(name)-len(logKeyTime)-1] != '-' {
+		return "", time.Time{}, false
+	}
+	t, err := time.Parse(logKeyTime, name[len(name)-len(logKeyTime):])
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	return name[:len(name)-len(logKeyTime)-1], t, true
+}
+
+// PruneHistory removes the oldest history records until the history is within the limits:
+// records older than maxAge, beyond maxRecords, or past maxBytes in total. Zero limits are
+// ignored. It returns the number of records removed.
+func PruneHistory(storage Storage, maxAge time.Duration, maxRecords int, maxBytes int64, now time.Time) (int, error) {
+	data, err := storage.Get(NamespaceHistory, HistoryFileName)
+	if errors.Is(err, ErrNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read history: %w", err)
+	}
+
+	var lines [][]byte
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) > 0 {
+			lines = append(lines, line)
+		}
+	}
+	total := len(lines)
+
+	// Records are appended in time order, so pruning always removes from the front
+	if maxAge > 0 {
+		cutoff := now.Add(-maxAge)
+		for len(lines) > 0 {
+			var record struct {
+				Time time.Time `json:"time"`
+			}
+			// Corrupt lines are skipped by LoadHistory anyway, so they go with the old records
+			if err := json.Unmarshal(lines[0], &record); err == nil && !record.Time.Before(cutoff) {
+				break
+			}
+			lines = lines[1:]
+		}
+	}
+	if maxRecords > 0 && len(lines) > maxRecords {
+		lines = lines[len(lines)-maxRecords:]
+	}
+	if maxBytes > 0 {
+		var size int64
+		for _, line := range lines {
+			size += int64(len(line)) + 1
+		}
+		for len(lines) > 0 && size > maxBytes {
+			size -= int64(len(lines[0])) + 1
+			lines = lines[1:]
+		}
+	}
+
+	removed := total - len(lines)
+	if removed == 0 {
+		return 0, nil
+	}
+
+	var pruned bytes.Buffer
+	for _, line := range lines {
+		pruned.Write(line)
+		pruned.WriteByte('\n')
+	}
+	if err := storage.Put(NamespaceHistory, HistoryFileName, pruned.Bytes()); err != nil {
+		return 0, fmt.Errorf("failed to write pruned history: %w", err)
+	}
+	return removed, nil
+}
+
+// PruneLogs removes the logs saved in a namespace under keys made by LogKey that are older
+// than maxAge or beyond the newest maxPerProject of their project, along with their
+// signatures. Zero limits are ignored. It returns the number of logs removed.
+func PruneLogs(storage Storage, namespace string, maxAge time.Duration, maxPerProject int, now time.Time) (int, error) {
+	keys, err := storage.List(namespace)
+	if err != nil {
+		return 0, err
+	}
+
+	type savedLog struct {
+		key  string
+		time time.Time
+	}
+	byProject := make(map[string][]savedLog)
+	for _, key := range keys {
+		if project, t, ok := parseLogKey(key); ok {
+			byProject[project] = append(byProject[project], savedLog{key, t})
+		}
+	}
+
+	pruned := make(map[string]bool)
+	for _, logs := range byProject {
+		sort.Slice(logs, func(i, j int) bool { return logs[i].time.After(logs[j].time) })
+		for i, saved := range logs {
+			if (maxPerProject > 0 && i >= maxPerProject) || (maxAge > 0 && now.Sub(saved.time) > maxAge) {
+				pruned[saved.key] = true
+			}
+		}
+	}
+	if len(pruned) == 0 {
+		return 0, nil
+	}
+
+	// Signatures are kept under the key of their log followed by the signature extension
+	for _, key := range keys {
+		logKey := key
+		if i := strings.Index(key, ".log."); i >= 0 {
+			logKey = key[:i+len(".log")]
+		}
+		if !pruned[logKey] {
+			continue
+		}
+		if err := storage.Delete(namespace, key); err != nil {
+			return 0, fmt.Errorf("failed to remove %s/%s: %w", namespace, key, err)
+		}
+	}
+	return len(pruned), nil
+}
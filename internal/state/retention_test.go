@@ -0,0 +1,94 @@
+package state
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPruneHistory(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	storage := NewFileStorage(t.TempDir())
+
+	var records []HistoryRecord
+	for days := 10; days > 0; days-- {
+		records = append(records, HistoryRecord{Time: now.AddDate(0, 0, -days), Project: "network", Status: "clean"})
+	}
+	if err := AppendHistory(storage, records); err != nil {
+		t.Fatal(err)
+	}
+
+	removed, err := PruneHistory(storage, 7*24*time.Hour, 0, 0, now)
+	if err != nil || removed != 3 {
+		t.Fatalf("Expected 3 records older than 7 days removed, got %d, %v", removed, err)
+	}
+
+	removed, err = PruneHistory(storage, 0, 5, 0, now)
+	if err != nil || removed != 2 {
+		t.Fatalf("Expected 2 records past max_records removed, got %d, %v", removed, err)
+	}
+
+	kept, _ := LoadHistory(storage, time.Time{})
+	if len(kept) != 5 || !kept[0].Time.Equal(now.AddDate(0, 0, -5)) {
+		t.Fatalf("Expected the 5 newest records to be kept, got %+v", kept)
+	}
+
+	data, _ := storage.Get(NamespaceHistory, HistoryFileName)
+	recordSize := int64(len(data) / len(kept))
+	removed, err = PruneHistory(storage, 0, 0, 2*recordSize, now)
+	if err != nil || removed != 3 {
+		t.Fatalf("Expected 3 records past max_size removed, got %d, %v", removed, err)
+	}
+
+	if removed, _ := PruneHistory(storage, 30*24*time.Hour, 100, 0, now); removed != 0 {
+		t.Errorf("Expected nothing removed within the limits, got %d", removed)
+	}
+}
+
+func TestPruneLogs(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	storage := NewFileStorage(t.TempDir())
+	for days := 5; days > 0; days-- {
+		for _, project := range []string{"network", "prod-network"} {
+			key := LogKey(project, now.AddDate(0, 0, -days))
+			if err := storage.Put(NamespacePlanLogs, key, []byte("plan")); err != nil {
+				t.Fatal(err)
+			}
+			if err := storage.Put(NamespacePlanLogs, key+".sig", []byte("signature")); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := storage.Put(NamespacePlanLogs, "notes.txt", []byte("kept")); err != nil {
+		t.Fatal(err)
+	}
+
+	removed, err := PruneLogs(storage, NamespacePlanLogs, 4*24*time.Hour, 0, now)
+	if err != nil || removed != 2 {
+		t.Fatalf("Expected the log of each project older than 4 days removed, got %d, %v", removed, err)
+	}
+	removed, err = PruneLogs(storage, NamespacePlanLogs, 0, 2, now)
+	if err != nil || removed != 4 {
+		t.Fatalf("Expected all but the 2 newest logs of each project removed, got %d, %v", removed, err)
+	}
+
+	keys, _ := storage.List(NamespacePlanLogs)
+	expected := map[string]bool{"notes.txt": true}
+	for _, project := range []string{"network", "prod-network"} {
+		for days := 2; days > 0; days-- {
+			key := LogKey(project, now.AddDate(0, 0, -days))
+			expected[key], expected[key+".sig"] = true, true
+		}
+	}
+	if len(keys) != len(expected) {
+		t.Fatalf("Expected %d objects kept, got %v", len(expected), keys)
+	}
+	for _, key := range keys {
+		if !expected[key] {
+			t.Errorf("Expected %s to be pruned with its signature", key)
+		}
+	}
+
+	if removed, _ := PruneLogs(storage, NamespacePlanLogs, 30*24*time.Hour, 10, now); removed != 0 {
+		t.Errorf("Expected nothing removed within the limits, got %d", removed)
+	}
+}
@@ -0,0 +1,225 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/terradrift-watcher/internal/config"
+)
+
+// Namespaces group the objects kept by the watcher
+const (
+	NamespaceState   = "state"
+	NamespaceHistory = "history"
+	NamespaceOutbox  = "outbox"
+	NamespaceAudit   = "audit"
+
+	// NamespaceRemediation holds the plans kept for remediation and their descriptions
+	NamespaceRemediation = "remediation"
+	// NamespaceApplyLogs holds the output of remediation applies
+	NamespaceApplyLogs = "apply-logs"
+	// NamespacePlanLogs holds the plan lines verbose logging left out
+	NamespacePlanLogs = "plan-logs"
+)
+
+// ErrNotFound is returned by Storage.Get for objects that do not exist
+var ErrNotFound = errors.New("object not found")
+
+// Storage keeps the watcher's objects between runs. Keys are unique within a namespace.
+type Storage interface {
+	// Get returns the object's content, or ErrNotFound
+	Get(namespace, key string) ([]byte, error)
+	// Put creates or replaces an object
+	Put(namespace, key string, data []byte) error
+	// Delete removes an object; deleting a missing object is not an error
+	Delete(namespace, key string) error
+	// List returns the keys of the objects in a namespace, in no particular order
+	List(namespace string) ([]string, error)
+}
+
+// Appender is implemented by backends that can append to an object without rewriting it
+type Appender interface {
+	Append(namespace, key string, data []byte) error
+}
+
+// appendObject appends data to an object, rewriting it on backends that cannot append
+func appendObject(storage Storage, namespace, key string, data []byte) error {
+	if appender, ok := storage.(Appender); ok {
+		return appender.Append(namespace, key, data)
+	}
+
+	existing, err := storage.Get(namespace, key)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	return storage.Put(namespace, key, append(existing, data...))
+}
+
+// Open returns the storage backend selected by the configuration, encrypted if a key is set
+func Open(cfg *config.Config) (Storage, error) {
+	if cfg.Storage == nil {
+		return openFileStorage(cfg.StateDir)
+	}
+
+	var storage Storage
+	switch cfg.Storage.Type {
+	case "", config.StorageFS:
+		fs, err := openFileStorage(cfg.StateDir)
+		if err != nil {
+			return nil, err
+		}
+		storage = fs
+	case config.StorageS3:
+		storage = NewS3Storage(*cfg.Storage)
+	default:
+		return nil, fmt.Errorf("unknown storage type '%s'", cfg.Storage.Type)
+	}
+
+	key, err := cfg.Storage.Key()
+	if err != nil {
+		return nil, err
+	}
+	if key == nil {
+		return storage, nil
+	}
+	return NewEncryptedStorage(storage, key)
+}
+
+// FileStorage keeps objects as files in a directory: one file per key, in a subdirectory
+// per namespace
+type FileStorage struct {
+	Dir string
+}
+
+// NewFileStorage returns a file storage in dir, or in DefaultDir when dir is empty
+func NewFileStorage(dir string) *FileStorage {
+	if dir == "" {
+		dir = DefaultDir()
+	}
+	return &FileStorage{Dir: dir}
+}
+
+// openFileStorage returns a file storage in dir, first making sure the default directory
+// belongs to the user when dir is empty
+func openFileStorage(dir string) (*FileStorage, error) {
+	if dir == "" {
+		if err := PrepareDefaultDir(); err != nil {
+			return nil, err
+		}
+	}
+	return NewFileStorage(dir), nil
+}
+
+// path returns the file holding an object
+func (s *FileStorage) path(namespace, key string) string {
+	return filepath.Join(s.Dir, namespace, key)
+}
+
+// legacyPath is where versions before namespaces kept an object, directly in the directory
+func (s *FileStorage) legacyPath(key string) string {
+	return filepath.Join(s.Dir, key)
+}
+
+// Get implements Storage
+func (s *FileStorage) Get(namespace, key string) ([]byte, error) {
+	data, err := os.ReadFile(s.path(namespace, key))
+	if os.IsNotExist(err) {
+		// Fall back to the layout used before namespaces so upgrades keep their state
+		data, err = os.ReadFile(s.legacyPath(key))
+	}
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s/%s: %w", namespace, key, err)
+	}
+	return data, nil
+}
+
+// Put implements Storage
+func (s *FileStorage) Put(namespace, key string, data []byte) error {
+	path := s.path(namespace, key)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	// Write to a temporary file first so a crash never leaves a truncated object
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write %s/%s: %w", namespace, key, err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to replace %s/%s: %w", namespace, key, err)
+	}
+	return s.removeLegacy(key)
+}
+
+// Append implements Appender
+func (s *FileStorage) Append(namespace, key string, data []byte) error {
+	path := s.path(namespace, key)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	// Carry an object in the legacy layout over before appending to it
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		if err := os.Rename(s.legacyPath(key), path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to move %s into %s: %w", key, namespace, err)
+		}
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open %s/%s: %w", namespace, key, err)
+	}
+	defer file.Close()
+
+	// Files created by older versions were readable by everyone; history holds plan details
+	if info, err := file.Stat(); err == nil && info.Mode().Perm()&0077 != 0 {
+		file.Chmod(0600)
+	}
+
+	if _, err := file.Write(data); err != nil {
+		return fmt.Errorf("failed to append to %s/%s: %w", namespace, key, err)
+	}
+	return nil
+}
+
+// List implements Storage. Objects in the legacy layout are not listed.
+func (s *FileStorage) List(namespace string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(s.Dir, namespace))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", namespace, err)
+	}
+	var keys []string
+	for _, entry := range entries {
+		// Skip the temporary file of a Put in progress
+		if entry.IsDir() || strings.HasSuffix(entry.Name(), ".tmp") {
+			continue
+		}
+		keys = append(keys, entry.Name())
+	}
+	return keys, nil
+}
+
+// Delete implements Storage
+func (s *FileStorage) Delete(namespace, key string) error {
+	if err := os.Remove(s.path(namespace, key)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove %s/%s: %w", namespace, key, err)
+	}
+	return s.removeLegacy(key)
+}
+
+// removeLegacy removes an object's file in the legacy layout once it has been replaced
+func (s *FileStorage) removeLegacy(key string) error {
+	if err := os.Remove(s.legacyPath(key)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove %s: %w", s.legacyPath(key), err)
+	}
+	return nil
+}
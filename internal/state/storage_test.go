@@ -0,0 +1,102 @@
+package state
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestFileStorageLegacyLayout(t *testing.T) {
+	dir := t.TempDir()
+	storage := NewFileStorage(dir)
+
+	// Files written before namespaces were introduced sit directly in the state directory
+	if err := os.WriteFile(filepath.Join(dir, HistoryFileName), []byte("old\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := storage.Get(NamespaceHistory, HistoryFileName)
+	if err != nil || string(data) != "old\n" {
+		t.Fatalf("Expected the legacy file to be read, got %q, %v", data, err)
+	}
+
+	if err := appendObject(storage, NamespaceHistory, HistoryFileName, []byte("new\n")); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+	data, err = storage.Get(NamespaceHistory, HistoryFileName)
+	if err != nil || string(data) != "old\nnew\n" {
+		t.Errorf("Expected appended history, got %q, %v", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, HistoryFileName)); !os.IsNotExist(err) {
+		t.Errorf("Expected the legacy file to be moved into its namespace, got %v", err)
+	}
+
+	if err := storage.Delete(NamespaceHistory, HistoryFileName); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := storage.Get(NamespaceHistory, HistoryFileName); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound after delete, got %v", err)
+	}
+}
+
+func TestFileStoragePermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Unix permission bits only")
+	}
+	dir := t.TempDir()
+	storage := NewFileStorage(dir)
+
+	// A history file left readable by everyone by an older version is tightened on append
+	legacy := filepath.Join(dir, NamespaceHistory, HistoryFileName)
+	if err := os.MkdirAll(filepath.Dir(legacy), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(legacy, []byte("old\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := appendObject(storage, NamespaceHistory, HistoryFileName, []byte("new\n")); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+	if err := storage.Put(NamespaceState, StateFileName, []byte("{}")); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	for _, path := range []string{legacy, filepath.Join(dir, NamespaceState, StateFileName)} {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if mode := info.Mode().Perm(); mode != 0600 {
+			t.Errorf("Expected %s to be 0600, got %04o", filepath.Base(path), mode)
+		}
+	}
+
+	info, err := os.Stat(filepath.Join(dir, NamespaceState))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != 0700 {
+		t.Errorf("Expected the namespace directory to be 0700, got %04o", mode)
+	}
+}
+
+func TestDefaultDirOwner(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Unix owners only")
+	}
+	if strings.HasPrefix(DefaultDir(), os.TempDir()) && os.Getenv("HOME") != "" {
+		t.Errorf("Expected the default state directory outside the shared temp directory, got %s", DefaultDir())
+	}
+
+	dir := t.TempDir()
+	if err := checkDir(dir, os.Getuid()); err != nil {
+		t.Errorf("Expected the user's own directory to be accepted, got %v", err)
+	}
+	// A directory created first by another user is refused rather than written to
+	if err := checkDir(dir, os.Getuid()+1); err == nil {
+		t.Error("Expected a directory owned by another user to be refused")
+	}
+}
@@ -0,0 +1,225 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/terradrift-watcher/internal/terraform"
+)
+
+// StateFileName is the name of the state file inside the state directory
+const StateFileName = "state.json"
+
+// maxRecentStatuses bounds how many past scan statuses are kept per project
+const maxRecentStatuses = 10
+
+// Store holds watcher state that must survive between runs
+type Store struct {
+	Projects map[string]*ProjectState `json:"projects"`
+
+	// DigestsSent records when each scheduled digest was last sent
+	DigestsSent map[string]time.Time `json:"digests_sent,omitempty"`
+
+	// CredentialChecks holds the last read-only check of each auth profile's credentials
+	CredentialChecks map[string]*CredentialCheck `json:"credential_checks,omitempty"`
+
+	storage Storage
+}
+
+// ProjectState is the persisted state of a single project
+type ProjectState struct {
+	LastScanned time.Time `json:"last_scanned"`
+	LastStatus  string    `json:"last_status,omitempty"`
+
+	// Pending marks a project that was not scanned in the previous run
+	// and should be scanned first in the next one
+	Pending bool `json:"pending,omitempty"`
+
+	// RecentStatuses holds the statuses of the most recent scans, oldest first
+	RecentStatuses []string `json:"recent_statuses,omitempty"`
+
+	// NextScan is the earliest time the project is due again under adaptive scheduling
+	NextScan time.Time `json:"next_scan"`
+
+	// DriftSince is when the current continuous drift was first detected (zero when clean)
+	DriftSince time.Time `json:"drift_since"`
+
+	// DriftScans counts the consecutive drifted scans of the current drift, and DriftConfirmed
+	// is set once it persisted long enough to be alerted
+	DriftScans     int  `json:"drift_scans,omitempty"`
+	DriftConfirmed bool `json:"drift_confirmed,omitempty"`
+
+	// Incident is the ID of the open incident of the current drift, set once the drift is
+	// confirmed (empty when clean)
+	Incident string `json:"incident,omitempty"`
+
+	// Flapping is set while the project alternates between drifted and clean, and
+	// FlapAlertedAt is when its drift was last alerted while flapping
+	Flapping      bool      `json:"flapping,omitempty"`
+	FlapAlertedAt time.Time `json:"flap_alerted_at"`
+
+	// Escalations lists the escalation rules already fired for the current drift
+	Escalations []string `json:"escalations,omitempty"`
+
+	// Fingerprint identifies the current drift (empty when clean)
+	Fingerprint string `json:"fingerprint,omitempty"`
+
+	// CleanKey identifies the state serial and code of the project's last clean plan, made at
+	// CleanAt. It is cleared whenever a plan is not clean.
+	CleanKey string    `json:"clean_key,omitempty"`
+	CleanAt  time.Time `json:"clean_at"`
+
+	// ModuleHashes holds the contents hash of each local module the project used at its last scan
+	ModuleHashes map[string]string `json:"module_hashes,omitempty"`
+
+	// Outputs holds the captured terraform outputs of the last clean plan
+	Outputs map[string]string `json:"outputs,omitempty"`
+
+	// Warnings holds the terraform warnings of the last plan, to tell which warnings are new
+	Warnings []terraform.Warning `json:"warnings,omitempty"`
+
+	// PolicyFindings holds the findings of the last policy stages, to tell which are new
+	PolicyFindings []terraform.PolicyFinding `json:"policy_findings,omitempty"`
+}
+
+// CredentialCheck is the outcome of the last read-only check of an auth profile
+type CredentialCheck struct {
+	CheckedAt time.Time `json:"checked_at"`
+
+	// Writable lists the write permissions the credentials held (empty when read-only)
+	Writable []string `json:"writable,omitempty"`
+}
+
+// MarkDrifted records that the project is drifted and returns how long it has been drifted
+func (ps *ProjectState) MarkDrifted(now time.Time) time.Duration {
+	if ps.DriftSince.IsZero() {
+		ps.DriftSince = now
+	}
+	ps.DriftScans++
+	return now.Sub(ps.DriftSince)
+}
+
+// Unconfirmed reports whether the current drift has not been alerted yet because it has not
+// persisted long enough. Drift recorded before confirmation was tracked counts as confirmed.
+func (ps *ProjectState) Unconfirmed() bool {
+	return ps.DriftScans > 0 && !ps.DriftConfirmed
+}
+
+// ResolveDrift clears drift aging once the project scans clean
+func (ps *ProjectState) ResolveDrift() {
+	ps.DriftSince = time.Time{}
+	ps.DriftScans = 0
+	ps.DriftConfirmed = false
+	ps.Escalations = nil
+	ps.Fingerprint = ""
+	ps.Incident = ""
+}
+
+// CachedClean reports whether a clean plan made with the same key within maxAge can stand in
+// for a new plan
+func (ps *ProjectState) CachedClean(key string, maxAge time.Duration, now time.Time) bool {
+	return ps.CleanKey != "" && ps.CleanKey == key && now.Sub(ps.CleanAt) < maxAge
+}
+
+// HasEscalated reports whether the named escalation already fired for the current drift
+func (ps *ProjectState) HasEscalated(name string) bool {
+	for _, escalation := range ps.Escalations {
+		if escalation == name {
+			return true
+		}
+	}
+	return false
+}
+
+// RecordScan stores the outcome of a completed scan
+func (ps *ProjectState) RecordScan(status string, at time.Time) {
+	ps.LastScanned = at
+	ps.LastStatus = status
+	ps.Pending = false
+
+	ps.RecentStatuses = append(ps.RecentStatuses, status)
+	if len(ps.RecentStatuses) > maxRecentStatuses {
+		ps.RecentStatuses = ps.RecentStatuses[len(ps.RecentStatuses)-maxRecentStatuses:]
+	}
+}
+
+// Load reads the state store from storage, returning an empty store if none exists yet
+func Load(storage Storage) (*Store, error) {
+	store := &Store{
+		Projects: make(map[string]*ProjectState),
+		storage:  storage,
+	}
+
+	data, err := storage.Get(NamespaceState, StateFileName)
+	if errors.Is(err, ErrNotFound) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state: %w", err)
+	}
+
+	if err := json.Unmarshal(data, store); err != nil {
+		return nil, fmt.Errorf("failed to parse state: %w", err)
+	}
+	if store.Projects == nil {
+		store.Projects = make(map[string]*ProjectState)
+	}
+
+	return store, nil
+}
+
+// Storage returns the storage the store was loaded from
+func (s *Store) Storage() Storage {
+	return s.storage
+}
+
+// Project returns the state for the named project, creating it if needed
+func (s *Store) Project(name string) *ProjectState {
+	ps, ok := s.Projects[name]
+	if !ok {
+		ps = &ProjectState{}
+		s.Projects[name] = ps
+	}
+	return ps
+}
+
+// AdoptAliases moves the state kept under an alias, i.e. an earlier project name, to the
+// project's current name unless that already has state. It returns the aliases moved.
+func (s *Store) AdoptAliases(aliases map[string]string) []string {
+	names := make([]string, 0, len(aliases))
+	for alias := range aliases {
+		names = append(names, alias)
+	}
+	sort.Strings(names)
+
+	var moved []string
+	for _, alias := range names {
+		ps, ok := s.Projects[alias]
+		if !ok {
+			continue
+		}
+		if _, exists := s.Projects[aliases[alias]]; exists {
+			continue
+		}
+		s.Projects[aliases[alias]] = ps
+		delete(s.Projects, alias)
+		moved = append(moved, alias)
+	}
+	return moved
+}
+
+// Save writes the state store back to storage
+func (s *Store) Save() error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal state: %w", err)
+	}
+
+	if err := s.storage.Put(NamespaceState, StateFileName, data); err != nil {
+		return fmt.Errorf("failed to write state: %w", err)
+	}
+	return nil
+}
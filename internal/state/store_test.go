@@ -0,0 +1,63 @@
+package state
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAdoptAliases(t *testing.T) {
+	store, err := Load(NewFileStorage(t.TempDir()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	driftSince := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	store.Project("network").DriftSince = driftSince
+	store.Project("network").Fingerprint = "abc123"
+	store.Project("legacy-db").LastStatus = "clean"
+	store.Project("db").LastStatus = "drifted"
+
+	moved := store.AdoptAliases(map[string]string{"network": "core-network", "legacy-db": "db", "gone": "other"})
+	if len(moved) != 1 || moved[0] != "network" {
+		t.Fatalf("Expected only network to be moved, got %v", moved)
+	}
+	if ps := store.Projects["core-network"]; ps == nil || ps.Fingerprint != "abc123" || !ps.DriftSince.Equal(driftSince) {
+		t.Errorf("Expected the drift of network to carry over, got %+v", ps)
+	}
+	if _, ok := store.Projects["network"]; ok {
+		t.Error("Expected the old name to be removed")
+	}
+	// A project that already has state under its new name keeps it
+	if store.Projects["db"].LastStatus != "drifted" {
+		t.Errorf("Expected the state of db to be kept, got %+v", store.Projects["db"])
+	}
+
+	records := []HistoryRecord{{Project: "network"}, {Project: "db"}}
+	RenameHistory(records, map[string]string{"network": "core-network"})
+	if records[0].Project != "core-network" || records[1].Project != "db" {
+		t.Errorf("Expected network to be renamed, got %+v", records)
+	}
+}
+
+func TestDriftConfirmationTracking(t *testing.T) {
+	var ps ProjectState
+	now := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	ps.MarkDrifted(now)
+	ps.MarkDrifted(now.Add(time.Hour))
+	if ps.DriftScans != 2 || !ps.Unconfirmed() {
+		t.Fatalf("Expected 2 unconfirmed drifted scans, got %+v", ps)
+	}
+	ps.DriftConfirmed = true
+	if ps.Unconfirmed() {
+		t.Error("Expected confirmed drift not to be unconfirmed")
+	}
+	ps.ResolveDrift()
+	if ps.DriftScans != 0 || ps.DriftConfirmed || ps.Unconfirmed() {
+		t.Errorf("Expected resolving to reset confirmation, got %+v", ps)
+	}
+
+	// Drift recorded before confirmation was tracked counts as confirmed
+	legacy := ProjectState{DriftSince: now}
+	if legacy.Unconfirmed() {
+		t.Error("Expected drift without a scan count to count as confirmed")
+	}
+}
@@ -0,0 +1,127 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// TriageFileName holds the operators' triage decisions. Like the pause, it is kept apart from
+// the state file so a run in progress, which rewrites the state when it finishes, cannot undo
+// a decision made meanwhile.
+const TriageFileName = "triage.json"
+
+// Triage actions
+const (
+	// TriageAck acknowledges the drift: someone is on it, so it is not alerted again
+	TriageAck = "ack"
+
+	// TriageSnooze holds the drift's alerts until a given time
+	TriageSnooze = "snooze"
+
+	// TriageTicket records the ticket tracking the drift, which is not alerted again
+	TriageTicket = "ticket"
+
+	// TriageRemediate marks the drift for remediation; it is still alerted until fixed
+	TriageRemediate = "remediate"
+)
+
+// TriageDecision is an operator's decision about the current drift of a project. It applies to
+// that drift only: once the drift is resolved or changes, the decision lapses.
+type TriageDecision struct {
+	Action      string    `json:"action"`
+	Incident    string    `json:"incident,omitempty"`
+	Fingerprint string    `json:"fingerprint,omitempty"`
+	Until       time.Time `json:"until,omitempty"`  // snooze
+	Ticket      string    `json:"ticket,omitempty"` // ticket, e.g. OPS-123 or a URL
+	Note        string    `json:"note,omitempty"`
+	By          string    `json:"by,omitempty"`
+	At          time.Time `json:"at"`
+}
+
+// Applies reports whether the decision was made about the project's current drift
+func (d *TriageDecision) Applies(ps *ProjectState) bool {
+	return d != nil && ps != nil && ps.Fingerprint != "" && d.Incident == ps.Incident && d.Fingerprint == ps.Fingerprint
+}
+
+// HoldsAlerts reports whether the decision holds back drift alerts at now
+func (d *TriageDecision) HoldsAlerts(now time.Time) bool {
+	switch d.Action {
+	case TriageAck, TriageTicket:
+		return true
+	case TriageSnooze:
+		return now.Before(d.Until)
+	}
+	return false
+}
+
+// String describes the decision for logs and listings
+func (d *TriageDecision) String() string {
+	s := d.Action
+	switch d.Action {
+	case TriageSnooze:
+		s += " until " + d.Until.Local().Format("2006-01-02 15:04")
+	case TriageTicket:
+		s += " " + d.Ticket
+	}
+	if d.By != "" {
+		s += " by " + d.By
+	}
+	if d.Note != "" {
+		s += ": " + d.Note
+	}
+	return s
+}
+
+// Triage holds the current triage decision of each project
+type Triage struct {
+	Decisions map[string]*TriageDecision `json:"decisions"`
+}
+
+// Current returns the decision about the project's current drift, or nil
+func (t *Triage) Current(project string, ps *ProjectState) *TriageDecision {
+	if t == nil {
+		return nil
+	}
+	if decision := t.Decisions[project]; decision.Applies(ps) {
+		return decision
+	}
+	return nil
+}
+
+// LoadTriage returns the recorded triage decisions, empty when there are none
+func LoadTriage(storage Storage) (*Triage, error) {
+	triage := &Triage{Decisions: make(map[string]*TriageDecision)}
+	data, err := storage.Get(NamespaceState, TriageFileName)
+	if errors.Is(err, ErrNotFound) {
+		return triage, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read triage decisions: %w", err)
+	}
+	if err := json.Unmarshal(data, triage); err != nil {
+		return nil, fmt.Errorf("failed to parse triage decisions: %w", err)
+	}
+	if triage.Decisions == nil {
+		triage.Decisions = make(map[string]*TriageDecision)
+	}
+	return triage, nil
+}
+
+// SaveTriage writes the triage decisions, dropping those about drift that is gone
+func SaveTriage(storage Storage, triage *Triage, store *Store) error {
+	for project, decision := range triage.Decisions {
+		if !decision.Applies(store.Projects[project]) {
+			delete(triage.Decisions, project)
+		}
+	}
+	data, err := json.MarshalIndent(triage, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode triage decisions: %w", err)
+	}
+	if err := storage.Put(NamespaceState, TriageFileName, data); err != nil {
+		return fmt.Errorf("failed to write triage decisions: %w", err)
+	}
+	return nil
+}
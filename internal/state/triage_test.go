@@ -0,0 +1,55 @@
+package state
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTriageDecisions(t *testing.T) {
+	storage := NewFileStorage(t.TempDir())
+	store, err := Load(storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	network := store.Project("network")
+	network.Incident, network.Fingerprint = "DRIFT-1", "abc"
+	db := store.Project("db")
+	db.Incident, db.Fingerprint = "DRIFT-2", "def"
+
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	triage, err := LoadTriage(storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	triage.Decisions["network"] = &TriageDecision{Action: TriageSnooze, Incident: "DRIFT-1", Fingerprint: "abc", Until: now.Add(time.Hour), By: "ops", At: now}
+	triage.Decisions["db"] = &TriageDecision{Action: TriageRemediate, Incident: "DRIFT-2", Fingerprint: "def", At: now}
+	triage.Decisions["gone"] = &TriageDecision{Action: TriageAck, Incident: "DRIFT-3", Fingerprint: "ghi", At: now}
+	if err := SaveTriage(storage, triage, store); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadTriage(storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.Decisions) != 2 {
+		t.Errorf("Expected the decision about drift that is gone to be dropped, got %v", loaded.Decisions)
+	}
+	snooze := loaded.Current("network", network)
+	if snooze == nil || !snooze.HoldsAlerts(now) || snooze.HoldsAlerts(now.Add(2*time.Hour)) {
+		t.Errorf("Expected the snooze to hold alerts for an hour, got %+v", snooze)
+	}
+	if remediate := loaded.Current("db", db); remediate == nil || remediate.HoldsAlerts(now) {
+		t.Errorf("Expected drift marked for remediation to still be alerted, got %+v", remediate)
+	}
+
+	// A decision lapses once the drift changes
+	network.Fingerprint = "xyz"
+	if loaded.Current("network", network) != nil {
+		t.Error("Expected the decision not to apply to changed drift")
+	}
+	var none *Triage
+	if none.Current("network", network) != nil {
+		t.Error("Expected no decision without triage")
+	}
+}
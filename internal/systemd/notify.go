@@ -0,0 +1,103 @@
+package systemd
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Environment variables systemd sets for services
+const (
+	// NotifySocketEnv names the datagram socket of a Type=notify service
+	NotifySocketEnv = "NOTIFY_SOCKET"
+
+	// WatchdogUsecEnv holds the WatchdogSec of the service in microseconds
+	WatchdogUsecEnv = "WATCHDOG_USEC"
+
+	// WatchdogPIDEnv holds the process the watchdog applies to
+	WatchdogPIDEnv = "WATCHDOG_PID"
+)
+
+// States sent to systemd
+const (
+	Ready    = "READY=1"
+	Stopping = "STOPPING=1"
+	Watchdog = "WATCHDOG=1"
+)
+
+// Notify sends state lines such as Ready or "STATUS=..." to systemd. It reports false without
+// an error when the process was not started by systemd as a Type=notify service.
+func Notify(state ...string) (bool, error) {
+	socket := os.Getenv(NotifySocketEnv)
+	if socket == "" {
+		return false, nil
+	}
+	// A leading @ names a socket in the abstract namespace
+	if socket[0] == '@' {
+		socket = "\x00" + socket[1:]
+	}
+
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		return false, fmt.Errorf("failed to connect to the systemd notify socket: %w", err)
+	}
+	defer conn.Close()
+
+	var msg []byte
+	for _, line := range state {
+		msg = append(msg, line...)
+		msg = append(msg, '\n')
+	}
+	if _, err := conn.Write(msg); err != nil {
+		return false, fmt.Errorf("failed to notify systemd: %w", err)
+	}
+	return true, nil
+}
+
+// Status returns the state line shown by `systemctl status`
+func Status(format string, args ...interface{}) string {
+	return "STATUS=" + fmt.Sprintf(format, args...)
+}
+
+// WatchdogInterval returns the WatchdogSec of the service, or zero when the watchdog is not
+// enabled for this process
+func WatchdogInterval() (time.Duration, error) {
+	usec := os.Getenv(WatchdogUsecEnv)
+	if usec == "" {
+		return 0, nil
+	}
+	if pid := os.Getenv(WatchdogPIDEnv); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0, nil
+	}
+	n, err := strconv.ParseInt(usec, 10, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid %s: %s", WatchdogUsecEnv, usec)
+	}
+	return time.Duration(n) * time.Microsecond, nil
+}
+
+// StartWatchdog sends keepalives at half the watchdog interval until the returned function is
+// called. It does nothing when the watchdog is not enabled.
+func StartWatchdog() (func(), error) {
+	interval, err := WatchdogInterval()
+	if err != nil || interval == 0 {
+		return func() {}, err
+	}
+
+	done := make(chan struct{})
+	ticker := time.NewTicker(interval / 2)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				Notify(Watchdog)
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() { close(done) }, nil
+}
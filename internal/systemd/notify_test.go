@@ -0,0 +1,67 @@
+package systemd
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestNotify(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("systemd only runs on Linux")
+	}
+	t.Setenv(NotifySocketEnv, "")
+	if sent, err := Notify(Ready); sent || err != nil {
+		t.Fatalf("Expected nothing to be sent outside systemd, got %v, %v", sent, err)
+	}
+
+	path := filepath.Join(t.TempDir(), "notify.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	t.Setenv(NotifySocketEnv, path)
+
+	if sent, err := Notify(Ready, Status("Idle, next run at %s", "10:00")); !sent || err != nil {
+		t.Fatalf("Expected the notification to be sent, got %v, %v", sent, err)
+	}
+	buf := make([]byte, 1024)
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(buf[:n]), "READY=1\nSTATUS=Idle, next run at 10:00\n"; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
+
+func TestWatchdogInterval(t *testing.T) {
+	t.Setenv(WatchdogUsecEnv, "")
+	if interval, err := WatchdogInterval(); interval != 0 || err != nil {
+		t.Errorf("Expected no watchdog, got %v, %v", interval, err)
+	}
+
+	t.Setenv(WatchdogUsecEnv, "120000000")
+	t.Setenv(WatchdogPIDEnv, strconv.Itoa(os.Getpid()))
+	if interval, err := WatchdogInterval(); interval != 2*time.Minute || err != nil {
+		t.Errorf("Expected a 2m watchdog, got %v, %v", interval, err)
+	}
+
+	// The watchdog of another process, e.g. a parent shell, does not apply
+	t.Setenv(WatchdogPIDEnv, strconv.Itoa(os.Getpid()+1))
+	if interval, err := WatchdogInterval(); interval != 0 || err != nil {
+		t.Errorf("Expected no watchdog for another process, got %v, %v", interval, err)
+	}
+
+	t.Setenv(WatchdogPIDEnv, "")
+	t.Setenv(WatchdogUsecEnv, "soon")
+	if _, err := WatchdogInterval(); err == nil {
+		t.Error("Expected an error for an invalid WATCHDOG_USEC")
+	}
+}
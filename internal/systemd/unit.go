@@ -0,0 +1,78 @@
+package systemd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultUnitPath is where the generated unit is installed by default
+const DefaultUnitPath = "/etc/systemd/system/terradrift-watcher.service"
+
+// DefaultWatchdog is the WatchdogSec of the generated unit
+const DefaultWatchdog = 2 * time.Minute
+
+// Unit describes the service running the daemon
+type Unit struct {
+	Executable string // Absolute path of the terradrift-watcher binary
+	ConfigFile string // Absolute path of the configuration
+	WorkingDir string
+	User       string // Runs as root when empty
+	Watchdog   time.Duration
+	Shard      string // The daemon's --shard, if any
+
+	MaxDuration time.Duration // The daemon's --max-duration, if any
+}
+
+// Render returns the unit file of a Type=notify service that is restarted when it fails or
+// stops answering the watchdog
+func (u Unit) Render() string {
+	config := quote(u.ConfigFile)
+	exec := quote(u.Executable)
+
+	var b strings.Builder
+	b.WriteString("[Unit]\n")
+	b.WriteString("Description=terradrift-watcher drift detection daemon\n")
+	b.WriteString("Wants=network-online.target\n")
+	b.WriteString("After=network-online.target\n\n")
+
+	b.WriteString("[Service]\n")
+	b.WriteString("Type=notify\n")
+	b.WriteString("NotifyAccess=main\n")
+	fmt.Fprintf(&b, "ExecStart=%s daemon --config %s", exec, config)
+	if u.Shard != "" {
+		fmt.Fprintf(&b, " --shard %s", u.Shard)
+	}
+	if u.MaxDuration > 0 {
+		fmt.Fprintf(&b, " --max-duration %s", u.MaxDuration)
+	}
+	b.WriteString("\n")
+	fmt.Fprintf(&b, "ExecReload=%s reload --config %s\n", exec, config)
+	if u.WorkingDir != "" {
+		fmt.Fprintf(&b, "WorkingDirectory=%s\n", quote(u.WorkingDir))
+	}
+	if u.User != "" {
+		fmt.Fprintf(&b, "User=%s\n", u.User)
+	}
+	if u.Watchdog > 0 {
+		fmt.Fprintf(&b, "WatchdogSec=%d\n", int(u.Watchdog.Seconds()))
+	}
+	b.WriteString("Restart=on-failure\n")
+	// A stop during a run exits with the interrupted status
+	b.WriteString("SuccessExitStatus=130\n")
+	b.WriteString("RestartSec=30\n")
+	// Plans in progress are given time to release their state locks
+	b.WriteString("TimeoutStopSec=5min\n\n")
+
+	b.WriteString("[Install]\n")
+	b.WriteString("WantedBy=multi-user.target\n")
+	return b.String()
+}
+
+// quote quotes a unit file argument containing spaces
+func quote(s string) string {
+	if strings.ContainsAny(s, " \t\"\\") {
+		return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+	}
+	return s
+}
@@ -0,0 +1,45 @@
+package systemd
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUnitRender(t *testing.T) {
+	unit := Unit{
+		Executable: "/usr/local/bin/terradrift-watcher",
+		ConfigFile: "/etc/terradrift/my config.yml",
+		WorkingDir: "/etc/terradrift",
+		User:       "terradrift",
+		Watchdog:   2 * time.Minute,
+	}.Render()
+
+	for _, line := range []string{
+		"Type=notify",
+		`ExecStart=/usr/local/bin/terradrift-watcher daemon --config "/etc/terradrift/my config.yml"`,
+		`ExecReload=/usr/local/bin/terradrift-watcher reload --config "/etc/terradrift/my config.yml"`,
+		"User=terradrift",
+		"WatchdogSec=120",
+		"WantedBy=multi-user.target",
+	} {
+		if !strings.Contains(unit, line+"\n") {
+			t.Errorf("Expected the unit to contain %q, got:\n%s", line, unit)
+		}
+	}
+
+	if unit := (Unit{Executable: "/bin/tdw", ConfigFile: "/c.yml"}).Render(); strings.Contains(unit, "User=") ||
+		strings.Contains(unit, "WatchdogSec=") {
+		t.Errorf("Expected no user or watchdog, got:\n%s", unit)
+	}
+
+	unit = Unit{Executable: "/bin/tdw", ConfigFile: "/c.yml", Shard: "2/5"}.Render()
+	if !strings.Contains(unit, "ExecStart=/bin/tdw daemon --config /c.yml --shard 2/5\n") {
+		t.Errorf("Expected the unit to start the daemon's shard, got:\n%s", unit)
+	}
+
+	unit = Unit{Executable: "/bin/tdw", ConfigFile: "/c.yml", Shard: "2/5", MaxDuration: 45 * time.Minute}.Render()
+	if !strings.Contains(unit, "ExecStart=/bin/tdw daemon --config /c.yml --shard 2/5 --max-duration 45m0s\n") {
+		t.Errorf("Expected the unit to pass the run budget, got:\n%s", unit)
+	}
+}
@@ -0,0 +1,265 @@
+package terraform
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// accessCheckTimeout bounds each cloud CLI call of a read-only check
+const accessCheckTimeout = 2 * time.Minute
+
+// AWSWriteProbes are the write actions simulated against AWS credentials. A read-only
+// principal is denied all of them; scoped grants, e.g. to the state bucket, are not matched
+// as the simulation runs against every resource.
+var AWSWriteProbes = []string{
+	"ec2:RunInstances",
+	"ec2:TerminateInstances",
+	"ec2:AuthorizeSecurityGroupIngress",
+	"s3:PutObject",
+	"s3:DeleteBucket",
+	"iam:CreateUser",
+	"iam:AttachRolePolicy",
+	"iam:PassRole",
+	"rds:DeleteDBInstance",
+	"lambda:UpdateFunctionCode",
+	"dynamodb:DeleteTable",
+	"kms:ScheduleKeyDeletion",
+}
+
+// WriteAccess checks whether the credentials in env can change cloud resources and returns
+// the write permissions they hold, ignoring those in allow. AWS credentials are simulated
+// with the IAM policy simulator; the Azure and GCP role assignments of the principal are
+// read and every role other than a reader or viewer counts as write access. The aws, az or
+// gcloud CLI must be installed, and az and gcloud must be logged in with permission to read
+// role assignments.
+func WriteAccess(provider string, env map[string]string, allow []string) ([]string, error) {
+	var writable []string
+	var err error
+	switch provider {
+	case "aws":
+		writable, err = awsWriteAccess(env)
+	case "azure":
+		writable, err = azureWriteAccess(env)
+	case "gcp":
+		writable, err = gcpWriteAccess(env)
+	default:
+		return nil, fmt.Errorf("read-only checks are not supported for provider '%s'", provider)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var kept []string
+	for _, permission := range writable {
+		if !allowed(permission, allow) {
+			kept = append(kept, permission)
+		}
+	}
+	sort.Strings(kept)
+	return kept, nil
+}
+
+// allowed reports whether a write permission is accepted. Role permissions are recorded as
+// "role on scope" and match an allowed role by name.
+func allowed(permission string, allow []string) bool {
+	name, _, _ := strings.Cut(permission, " on ")
+	for _, a := range allow {
+		if strings.EqualFold(a, permission) || strings.EqualFold(a, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// runCLI runs a cloud CLI with the credentials in env and returns its standard output
+func runCLI(env map[string]string, name string, args ...string) ([]byte, error) {
+	cmd := newCommand("", Options{Env: env}, name, args...)
+	var stdout, stderr strings.Builder
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := waitWithTimeout(cmd, "credentials", accessCheckTimeout); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s %s failed: %w: %s", name, args[0], err, msg)
+		}
+		return nil, fmt.Errorf("%s %s failed: %w", name, args[0], err)
+	}
+	return []byte(stdout.String()), nil
+}
+
+// awsWriteAccess simulates the write probes for the principal behind the AWS credentials
+func awsWriteAccess(env map[string]string) ([]string, error) {
+	out, err := runCLI(env, "aws", "sts", "get-caller-identity", "--output", "json")
+	if err != nil {
+		return nil, err
+	}
+	var identity struct {
+		Arn string `json:"Arn"`
+	}
+	if err := json.Unmarshal(out, &identity); err != nil {
+		return nil, fmt.Errorf("failed to parse caller identity: %w", err)
+	}
+	principal, err := AWSPrincipalARN(identity.Arn)
+	if err != nil {
+		return nil, err
+	}
+
+	args := append([]string{"iam", "simulate-principal-policy", "--policy-source-arn", principal,
+		"--output", "json", "--action-names"}, AWSWriteProbes...)
+	out, err = runCLI(env, "aws", args...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseAWSSimulation(out)
+}
+
+// AWSPrincipalARN returns the IAM principal to simulate for a caller identity ARN. An
+// assumed role session is simulated as its role; roles with a path cannot be recovered
+// from the session ARN and are reported as an error.
+func AWSPrincipalARN(arn string) (string, error) {
+	parts := strings.SplitN(arn, ":", 6)
+	if len(parts) != 6 || parts[0] != "arn" {
+		return "", fmt.Errorf("unexpected caller identity ARN '%s'", arn)
+	}
+	resource := parts[5]
+	switch {
+	case parts[2] == "iam" && strings.HasPrefix(resource, "user/"):
+		return arn, nil
+	case parts[2] == "iam" && strings.HasPrefix(resource, "role/"):
+		return arn, nil
+	case parts[2] == "sts" && strings.HasPrefix(resource, "assumed-role/"):
+		role := strings.Split(strings.TrimPrefix(resource, "assumed-role/"), "/")[0]
+		return fmt.Sprintf("arn:%s:iam::%s:role/%s", parts[1], parts[4], role), nil
+	}
+	return "", fmt.Errorf("cannot simulate the policies of '%s'", arn)
+}
+
+// ParseAWSSimulation returns the actions allowed by `aws iam simulate-principal-policy`
+func ParseAWSSimulation(data []byte) ([]string, error) {
+	var out struct {
+		EvaluationResults []struct {
+			EvalActionName string `json:"EvalActionName"`
+			EvalDecision   string `json:"EvalDecision"`
+		} `json:"EvaluationResults"`
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("failed to parse policy simulation: %w", err)
+	}
+	var allowed []string
+	for _, r := range out.EvaluationResults {
+		if r.EvalDecision == "allowed" {
+			allowed = append(allowed, r.EvalActionName)
+		}
+	}
+	return allowed, nil
+}
+
+// azureWriteAccess lists the role assignments of the service principal in ARM_CLIENT_ID
+func azureWriteAccess(env map[string]string) ([]string, error) {
+	clientID := lookupEnv(env, "ARM_CLIENT_ID")
+	if clientID == "" {
+		return nil, fmt.Errorf("no client ID to check; set client_id in the auth profile")
+	}
+	out, err := runCLI(env, "az", "role", "assignment", "list", "--assignee", clientID,
+		"--all", "--include-inherited", "--output", "json")
+	if err != nil {
+		return nil, err
+	}
+	return ParseAzureAssignments(out)
+}
+
+// ParseAzureAssignments returns the roles of `az role assignment list` that are not
+// read-only, as "role on scope"
+func ParseAzureAssignments(data []byte) ([]string, error) {
+	var assignments []struct {
+		RoleDefinitionName string `json:"roleDefinitionName"`
+		Scope              string `json:"scope"`
+	}
+	if err := json.Unmarshal(data, &assignments); err != nil {
+		return nil, fmt.Errorf("failed to parse role assignments: %w", err)
+	}
+	var writable []string
+	for _, a := range assignments {
+		if a.RoleDefinitionName == "Reader" || strings.HasSuffix(a.RoleDefinitionName, " Reader") {
+			continue
+		}
+		writable = append(writable, a.RoleDefinitionName+" on "+a.Scope)
+	}
+	return writable, nil
+}
+
+// gcpWriteAccess reads the project IAM policy for the service account in the credentials file
+func gcpWriteAccess(env map[string]string) ([]string, error) {
+	keyFile := lookupEnv(env, "GOOGLE_APPLICATION_CREDENTIALS")
+	if keyFile == "" {
+		return nil, fmt.Errorf("no service account key to check; set GOOGLE_APPLICATION_CREDENTIALS")
+	}
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	var key struct {
+		ClientEmail string `json:"client_email"`
+		ProjectID   string `json:"project_id"`
+	}
+	if err := json.Unmarshal(data, &key); err != nil || key.ClientEmail == "" {
+		return nil, fmt.Errorf("%s is not a service account key", keyFile)
+	}
+	project := lookupEnv(env, "GOOGLE_PROJECT")
+	if project == "" {
+		project = key.ProjectID
+	}
+
+	out, err := runCLI(env, "gcloud", "projects", "get-iam-policy", project, "--format", "json")
+	if err != nil {
+		return nil, err
+	}
+	return ParseGCPPolicy(out, "serviceAccount:"+key.ClientEmail, project)
+}
+
+// ParseGCPPolicy returns the roles bound to member in a project IAM policy that are not
+// read-only, as "role on projects/<project>"
+func ParseGCPPolicy(data []byte, member, project string) ([]string, error) {
+	var policy struct {
+		Bindings []struct {
+			Role    string   `json:"role"`
+			Members []string `json:"members"`
+		} `json:"bindings"`
+	}
+	if err := json.Unmarshal(data, &policy); err != nil {
+		return nil, fmt.Errorf("failed to parse IAM policy: %w", err)
+	}
+	var writable []string
+	for _, b := range policy.Bindings {
+		if readOnlyGCPRole(b.Role) {
+			continue
+		}
+		for _, m := range b.Members {
+			if strings.EqualFold(m, member) {
+				writable = append(writable, b.Role+" on projects/"+project)
+				break
+			}
+		}
+	}
+	return writable, nil
+}
+
+// readOnlyGCPRole reports whether a predefined role only grants read access
+func readOnlyGCPRole(role string) bool {
+	switch role {
+	case "roles/viewer", "roles/browser", "roles/iam.securityReviewer":
+		return true
+	}
+	return strings.HasSuffix(strings.ToLower(role), "viewer")
+}
+
+// lookupEnv returns a variable from the extra environment, or else the process environment
+func lookupEnv(env map[string]string, name string) string {
+	if value, ok := env[name]; ok {
+		return value
+	}
+	return os.Getenv(name)
+}
@@ -0,0 +1,75 @@
+package terraform
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAWSPrincipalARN(t *testing.T) {
+	tests := map[string]string{
+		"arn:aws:iam::123456789012:user/drift":                          "arn:aws:iam::123456789012:user/drift",
+		"arn:aws:sts::123456789012:assumed-role/DriftReader/watcher":    "arn:aws:iam::123456789012:role/DriftReader",
+		"arn:aws-us-gov:sts::123456789012:assumed-role/DriftReader/w-1": "arn:aws-us-gov:iam::123456789012:role/DriftReader",
+	}
+	for arn, want := range tests {
+		got, err := AWSPrincipalARN(arn)
+		if err != nil || got != want {
+			t.Errorf("AWSPrincipalARN(%s) = %s, %v, want %s", arn, got, err, want)
+		}
+	}
+	if _, err := AWSPrincipalARN("arn:aws:sts::123456789012:federated-user/bob"); err == nil {
+		t.Error("Expected an error for a federated user")
+	}
+}
+
+func TestParseAWSSimulation(t *testing.T) {
+	output := `{"EvaluationResults":[{"EvalActionName":"ec2:RunInstances","EvalDecision":"implicitDeny"},
+{"EvalActionName":"s3:PutObject","EvalDecision":"allowed"},{"EvalActionName":"iam:CreateUser","EvalDecision":"explicitDeny"}]}`
+	allowed, err := ParseAWSSimulation([]byte(output))
+	if err != nil {
+		t.Fatalf("ParseAWSSimulation failed: %v", err)
+	}
+	if !reflect.DeepEqual(allowed, []string{"s3:PutObject"}) {
+		t.Errorf("Expected only s3:PutObject to be allowed, got %v", allowed)
+	}
+}
+
+func TestParseAzureAssignments(t *testing.T) {
+	output := `[{"roleDefinitionName":"Reader","scope":"/subscriptions/abc"},
+{"roleDefinitionName":"Storage Blob Data Reader","scope":"/subscriptions/abc"},
+{"roleDefinitionName":"Contributor","scope":"/subscriptions/abc/resourceGroups/app"}]`
+	writable, err := ParseAzureAssignments([]byte(output))
+	if err != nil {
+		t.Fatalf("ParseAzureAssignments failed: %v", err)
+	}
+	if !reflect.DeepEqual(writable, []string{"Contributor on /subscriptions/abc/resourceGroups/app"}) {
+		t.Errorf("Expected only Contributor, got %v", writable)
+	}
+}
+
+func TestParseGCPPolicy(t *testing.T) {
+	output := `{"bindings":[{"role":"roles/viewer","members":["serviceAccount:drift@app.iam.gserviceaccount.com"]},
+{"role":"roles/storage.objectViewer","members":["serviceAccount:drift@app.iam.gserviceaccount.com"]},
+{"role":"roles/editor","members":["user:alice@example.com","serviceAccount:drift@app.iam.gserviceaccount.com"]},
+{"role":"roles/owner","members":["user:alice@example.com"]}]}`
+	writable, err := ParseGCPPolicy([]byte(output), "serviceAccount:drift@app.iam.gserviceaccount.com", "app")
+	if err != nil {
+		t.Fatalf("ParseGCPPolicy failed: %v", err)
+	}
+	if !reflect.DeepEqual(writable, []string{"roles/editor on projects/app"}) {
+		t.Errorf("Expected only roles/editor, got %v", writable)
+	}
+}
+
+func TestAllowed(t *testing.T) {
+	allow := []string{"s3:PutObject", "Storage Blob Data Contributor"}
+	if !allowed("s3:PutObject", allow) {
+		t.Error("Expected an allowed action to match")
+	}
+	if !allowed("Storage Blob Data Contributor on /subscriptions/abc", allow) {
+		t.Error("Expected an allowed role to match on any scope")
+	}
+	if allowed("Contributor on /subscriptions/abc", allow) {
+		t.Error("Expected Contributor not to match")
+	}
+}
@@ -0,0 +1,13 @@
+package terraform
+
+import (
+	"time"
+)
+
+// ApplyPlan applies a saved plan in the project directory and returns terraform's output. Terraform
+// refuses a plan made against state that changed since, so only the reviewed changes are made.
+func ApplyPlan(projectPath string, opts Options, planFile string, timeout time.Duration) (string, error) {
+	opts.phase("apply")
+	cmd := newTerraformCommand(projectPath, opts, "apply", "-input=false", "-no-color", planFile)
+	return runCommandWithTimeout(cmd, opts.Stream, "apply", timeout)
+}
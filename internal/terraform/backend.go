@@ -0,0 +1,92 @@
+package terraform
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	// backendPattern matches the start of a backend block
+	backendPattern = regexp.MustCompile(`^\s*backend\s+"([^"]+)"\s*\{`)
+
+	// backendAttributePattern matches a string attribute of a backend block
+	backendAttributePattern = regexp.MustCompile(`^\s*(\w+)\s*=\s*"([^"]*)"`)
+
+	// backendBlockPattern matches a nested block such as the workspaces block of the remote backend
+	backendBlockPattern = regexp.MustCompile(`^\s*(\w+)\s*\{`)
+)
+
+// BackendKey identifies where the state of a terraform configuration is stored, from the
+// backend type and string settings of its backend block. Projects with the same key share a
+// state and its lock. A backend configured partially, for example with an empty block, yields
+// the same key for every project of that backend type, erring on the side of treating them as
+// shared. It returns "" for configurations using the default local backend.
+func BackendKey(dir string) (string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.tf"))
+	if err != nil {
+		return "", err
+	}
+	sort.Strings(files)
+
+	for _, name := range files {
+		file, err := os.Open(name)
+		if err != nil {
+			return "", err
+		}
+		key, found := readBackendKey(bufio.NewScanner(file))
+		err = file.Close()
+		if found {
+			return key, nil
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return "", nil
+}
+
+// readBackendKey reads the first backend block of a file
+func readBackendKey(scanner *bufio.Scanner) (string, bool) {
+	var backendType string
+	var settings []string
+	var path []string // Names of the nested blocks the scanner is in
+	depth := 0
+	for scanner.Scan() {
+		line := scanner.Text()
+		if depth == 0 {
+			if match := backendPattern.FindStringSubmatch(line); match != nil {
+				backendType = match[1]
+				depth = 1
+				// A block may be written on one line, e.g. backend "s3" {}
+				if strings.Contains(line, "}") {
+					break
+				}
+			}
+			continue
+		}
+
+		if match := backendAttributePattern.FindStringSubmatch(line); match != nil {
+			settings = append(settings, strings.Join(append(path, match[1]), ".")+"="+match[2])
+		} else if match := backendBlockPattern.FindStringSubmatch(line); match != nil {
+			path = append(path, match[1])
+			depth++
+		}
+		if strings.TrimSpace(line) == "}" {
+			depth--
+			if depth == 0 {
+				break
+			}
+			path = path[:len(path)-1]
+		}
+	}
+	if backendType == "" {
+		return "", false
+	}
+
+	sort.Strings(settings)
+	return backendType + ":" + strings.Join(settings, ","), true
+}
@@ -0,0 +1,49 @@
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBackendKey(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		want   string
+	}{
+		{"local", `resource "null_resource" "a" {}`, ""},
+		{"s3", `terraform {
+  required_version = ">= 1.5"
+  backend "s3" {
+    key    = "network/terraform.tfstate"
+    bucket = "tf-state"
+    region = "us-east-1"
+  }
+}`, "s3:bucket=tf-state,key=network/terraform.tfstate,region=us-east-1"},
+		{"nested block", `terraform {
+  backend "remote" {
+    organization = "acme"
+    workspaces {
+      name = "prod"
+    }
+  }
+}`, "remote:organization=acme,workspaces.name=prod"},
+		{"partial", `terraform {
+  backend "s3" {}
+}`, "s3:"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, "main.tf"), []byte(tt.config), 0644); err != nil {
+				t.Fatal(err)
+			}
+			got, err := BackendKey(dir)
+			if err != nil || got != tt.want {
+				t.Errorf("Expected %q, got %q, %v", tt.want, got, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package terraform
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// cdktfDefaultOutput is where cdktf synth writes its output unless cdktf.json sets "output"
+const cdktfDefaultOutput = "cdktf.out"
+
+// Stack is a synthesized CDK for Terraform stack
+type Stack struct {
+	Name string
+	Dir  string // Directory holding the stack's cdk.tf.json, planned like any terraform project
+}
+
+// Synthesize runs cdktf synth in a CDK for Terraform project
+func Synthesize(projectPath string, opts Options) (string, error) {
+	opts.phase("synth")
+	cmd := newCommand(projectPath, opts, "cdktf", "synth")
+	output, err := runCommand(cmd, opts.Stream)
+	if err != nil {
+		return output, fmt.Errorf("cdktf synth failed: %w: %s", err, output)
+	}
+	return output, nil
+}
+
+// SynthesizedStacks returns the stacks of a synthesized project sorted by name, limited to the
+// given names when there are any
+func SynthesizedStacks(projectPath string, names []string) ([]Stack, error) {
+	stacksDir := filepath.Join(projectPath, cdktfOutput(projectPath), "stacks")
+	entries, err := os.ReadDir(stacksDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read synthesized stacks: %w", err)
+	}
+
+	found := make(map[string]bool)
+	var stacks []Stack
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		found[entry.Name()] = true
+		if len(names) > 0 && !contains(names, entry.Name()) {
+			continue
+		}
+		stacks = append(stacks, Stack{Name: entry.Name(), Dir: filepath.Join(stacksDir, entry.Name())})
+	}
+	for _, name := range names {
+		if !found[name] {
+			return nil, fmt.Errorf("stack %s was not synthesized", name)
+		}
+	}
+	if len(stacks) == 0 {
+		return nil, fmt.Errorf("no stacks found in %s", stacksDir)
+	}
+
+	sort.Slice(stacks, func(i, j int) bool { return stacks[i].Name < stacks[j].Name })
+	return stacks, nil
+}
+
+// cdktfOutput returns the synth output directory configured in the project's cdktf.json
+func cdktfOutput(projectPath string) string {
+	data, err := os.ReadFile(filepath.Join(projectPath, "cdktf.json"))
+	if err != nil {
+		return cdktfDefaultOutput
+	}
+	var settings struct {
+		Output string `json:"output"`
+	}
+	if err := json.Unmarshal(data, &settings); err != nil || settings.Output == "" {
+		return cdktfDefaultOutput
+	}
+	return settings.Output
+}
+
+// contains reports whether values contains value
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
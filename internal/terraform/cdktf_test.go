@@ -0,0 +1,36 @@
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSynthesizedStacks(t *testing.T) {
+	project := t.TempDir()
+	if err := os.WriteFile(filepath.Join(project, "cdktf.json"), []byte(`{"language":"typescript","output":"out"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, stack := range []string{"prod", "dev"} {
+		if err := os.MkdirAll(filepath.Join(project, "out", "stacks", stack), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stacks, err := SynthesizedStacks(project, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stacks) != 2 || stacks[0].Name != "dev" || stacks[1].Dir != filepath.Join(project, "out", "stacks", "prod") {
+		t.Errorf("Expected the dev and prod stacks, got %+v", stacks)
+	}
+
+	stacks, err = SynthesizedStacks(project, []string{"prod"})
+	if err != nil || len(stacks) != 1 || stacks[0].Name != "prod" {
+		t.Errorf("Expected only the prod stack, got %+v, %v", stacks, err)
+	}
+
+	if _, err := SynthesizedStacks(project, []string{"staging"}); err == nil {
+		t.Error("Expected an error for a stack that was not synthesized")
+	}
+}
@@ -0,0 +1,83 @@
+package terraform
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Resource change actions
+const (
+	ActionCreate  = "create"
+	ActionUpdate  = "update"
+	ActionDelete  = "delete"
+	ActionReplace = "replace"
+	ActionRead    = "read"
+)
+
+// ResourceChange describes a single resource that terraform plans to change
+type ResourceChange struct {
+	Address string // e.g. module.network.aws_vpc.main
+	Type    string // e.g. aws_vpc
+	Action  string
+}
+
+// resourceChangeLine matches plan headers like "# aws_instance.web will be updated in-place"
+var resourceChangeLine = regexp.MustCompile(`^#\s+(\S+)\s+(?:will be|must be)\s+(.+)$`)
+
+// ParseResourceChanges extracts the changed resources from human-readable plan output
+func ParseResourceChanges(planOutput string) []ResourceChange {
+	var changes []ResourceChange
+
+	for _, line := range strings.Split(planOutput, "\n") {
+		match := resourceChangeLine.FindStringSubmatch(strings.TrimSpace(line))
+		if match == nil {
+			continue
+		}
+
+		action := changeAction(match[2])
+		if action == "" {
+			continue
+		}
+
+		changes = append(changes, ResourceChange{
+			Address: match[1],
+			Type:    ResourceType(match[1]),
+			Action:  action,
+		})
+	}
+
+	return changes
+}
+
+// changeAction maps the plan's wording to a change action
+func changeAction(description string) string {
+	switch {
+	case strings.HasPrefix(description, "created"):
+		return ActionCreate
+	case strings.HasPrefix(description, "updated"):
+		return ActionUpdate
+	case strings.HasPrefix(description, "destroyed"):
+		return ActionDelete
+	case strings.HasPrefix(description, "replaced"):
+		return ActionReplace
+	case strings.HasPrefix(description, "read"):
+		return ActionRead
+	}
+	return ""
+}
+
+// ResourceType returns the resource type from an address, skipping module and data prefixes
+func ResourceType(address string) string {
+	parts := strings.Split(address, ".")
+	for i := 0; i < len(parts); i++ {
+		switch parts[i] {
+		case "module":
+			i++ // Skip the module name
+			continue
+		case "data":
+			continue
+		}
+		return parts[i]
+	}
+	return address
+}
@@ -0,0 +1,42 @@
+package terraform
+
+import "testing"
+
+func TestParseResourceChanges(t *testing.T) {
+	planOutput := `
+Terraform will perform the following actions:
+
+  # aws_instance.web will be updated in-place
+  ~ resource "aws_instance" "web" {
+    }
+
+  # module.network.aws_vpc.main must be replaced
+-/+ resource "aws_vpc" "main" {
+    }
+
+  # data.aws_iam_policy_document.assume will be read during apply
+ <= data "aws_iam_policy_document" "assume" {
+    }
+
+  # aws_s3_bucket.logs will be destroyed
+
+Plan: 0 to add, 1 to change, 1 to destroy.
+`
+
+	changes := ParseResourceChanges(planOutput)
+	expected := []ResourceChange{
+		{Address: "aws_instance.web", Type: "aws_instance", Action: ActionUpdate},
+		{Address: "module.network.aws_vpc.main", Type: "aws_vpc", Action: ActionReplace},
+		{Address: "data.aws_iam_policy_document.assume", Type: "aws_iam_policy_document", Action: ActionRead},
+		{Address: "aws_s3_bucket.logs", Type: "aws_s3_bucket", Action: ActionDelete},
+	}
+
+	if len(changes) != len(expected) {
+		t.Fatalf("Expected %d changes, got %d: %+v", len(expected), len(changes), changes)
+	}
+	for i := range expected {
+		if changes[i] != expected[i] {
+			t.Errorf("Change %d: expected %+v, got %+v", i, expected[i], changes[i])
+		}
+	}
+}
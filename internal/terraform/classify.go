@@ -0,0 +1,83 @@
+package terraform
+
+import (
+	"strings"
+)
+
+// Categories of failed drift checks
+const (
+	ErrorAuth     = "auth"     // Missing, expired or insufficient credentials
+	ErrorBackend  = "backend"  // The state backend could not be configured or read
+	ErrorProvider = "provider" // Providers could not be installed, loaded or configured
+	ErrorSyntax   = "syntax"   // The configuration or its variables are invalid
+	ErrorTimeout  = "timeout"  // A command or API call ran out of time
+	ErrorLock     = "lock"     // The state is locked by another operation
+	ErrorVersion  = "version"  // The state was written by a newer Terraform
+	ErrorUnknown  = "unknown"
+)
+
+// ErrorCategories lists the categories ClassifyError returns
+var ErrorCategories = []string{ErrorAuth, ErrorBackend, ErrorProvider, ErrorSyntax, ErrorTimeout, ErrorLock, ErrorVersion, ErrorUnknown}
+
+// errorPatterns maps lower-case fragments of terraform and cloud SDK errors to categories. They
+// are tried in order, so a lock or timeout hit while reading the backend is reported as such.
+var errorPatterns = []struct {
+	category  string
+	fragments []string
+}{
+	{ErrorVersion, []string{
+		"which is newer than current", "written by a future terraform version",
+		"version requirements for remote workspace", "does not match local terraform version",
+	}},
+	{ErrorLock, []string{
+		"error acquiring the state lock", "state lock", "lock info:", "conditionalcheckfailedexception",
+		"resource temporarily unavailable", "state blob is already locked",
+	}},
+	{ErrorTimeout, []string{
+		"deadline exceeded", "timed out", "timeout", "signal: killed",
+	}},
+	{ErrorAuth, []string{
+		"nocredentialproviders", "no valid credential sources", "unable to locate credentials",
+		"expiredtoken", "invalidclienttokenid", "signaturedoesnotmatch", "unrecognizedclientexception",
+		"accessdenied", "access denied", "unauthorizedoperation", "authorizationfailed", "authorization failed",
+		"invalid_grant", "could not find default credentials", "aadsts", "failed to get credentials",
+		"401 unauthorized", "403 forbidden", "statuscode: 403", "statuscode: 401", "error: unauthorized",
+		"permission denied on resource",
+	}},
+	{ErrorBackend, []string{
+		"error loading state", "failed to load state", "error refreshing state", "failed to get existing workspaces",
+		"backend initialization required", "error configuring the backend", "backend configuration changed",
+		"error inspecting states", "failed to read state", "nosuchbucket", "error: initialization required",
+	}},
+	{ErrorProvider, []string{
+		"failed to query available provider packages", "failed to install provider", "incompatible provider version",
+		"could not load plugin", "plugin did not respond", "plugin crashed", "missing required provider",
+		"error: invalid provider configuration", "inconsistent dependency lock file", "provider produced",
+		"failed to instantiate provider", "unavailable provider",
+	}},
+	{ErrorSyntax, []string{
+		"argument or block definition required", "unsupported argument", "unsupported block type",
+		"unsupported attribute", "invalid expression", "reference to undeclared", "missing required argument",
+		"invalid reference", "unclosed configuration block", "no value for required variable",
+		"variables not allowed", "invalid value for input variable", "module not installed",
+		"duplicate resource", "invalid function argument", "call to unknown function", "invalid character",
+		"error: invalid", "unterminated template string",
+	}},
+}
+
+// ClassifyError works out why a drift check failed from the command's output and error,
+// so alerts and reports can say more than "exit code 1"
+func ClassifyError(output string, err error) string {
+	text := strings.ToLower(output)
+	if err != nil {
+		text += "\n" + strings.ToLower(err.Error())
+	}
+	for _, pattern := range errorPatterns {
+		for _, fragment := range pattern.fragments {
+			if strings.Contains(text, fragment) {
+				return pattern.category
+			}
+		}
+	}
+	return ErrorUnknown
+}
@@ -0,0 +1,32 @@
+package terraform
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		output   string
+		err      error
+		expected string
+	}{
+		{"Error: Error acquiring the state lock\n\nLock Info:\n  ID: 1234", nil, ErrorLock},
+		{"Error: error configuring S3 Backend: NoCredentialProviders: no valid providers in chain", nil, ErrorAuth},
+		{"Error: reading EC2 Instance: operation error EC2: DescribeInstances, https response error StatusCode: 403", nil, ErrorAuth},
+		{"Error: Failed to get existing workspaces: S3 bucket does not exist.\n\nNoSuchBucket", nil, ErrorBackend},
+		{"Error: Backend initialization required, please run \"terraform init\"", nil, ErrorBackend},
+		{"Error: Failed to query available provider packages\n\nCould not retrieve the list of available versions", nil, ErrorProvider},
+		{"Error: Unsupported argument\n\n  on main.tf line 12, in resource \"aws_instance\" \"web\":", nil, ErrorSyntax},
+		{"Error: No value for required variable", nil, ErrorSyntax},
+		{"Error: Get \"https://ec2.us-east-1.amazonaws.com\": dial tcp: i/o timeout", nil, ErrorTimeout},
+		{"", errors.New("terraform plan failed: signal: killed"), ErrorTimeout},
+		{"", errors.New("failed to read credentials file: open creds.json: no such file"), ErrorUnknown},
+		{"something unexpected", errors.New("exit status 1"), ErrorUnknown},
+	}
+	for _, tt := range tests {
+		if got := ClassifyError(tt.output, tt.err); got != tt.expected {
+			t.Errorf("ClassifyError(%q, %v) = %s, expected %s", tt.output, tt.err, got, tt.expected)
+		}
+	}
+}
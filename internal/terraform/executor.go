@@ -3,12 +3,65 @@ package terraform
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
+	"time"
+
+	"github.com/terradrift-watcher/internal/textutil"
 )
 
+// Options controls how terraform commands are executed for a project
+type Options struct {
+	// Env holds additional environment variables passed to terraform
+	Env map[string]string
+
+	// Remote runs terraform on a remote host over SSH instead of locally
+	Remote *RemoteHost
+
+	// Stream, when set, receives command output as it is produced
+	Stream io.Writer
+
+	// Fixture, when set, is a directory of canned plan results returned instead of running terraform
+	Fixture string
+
+	// Vars and VarFiles are input variables passed to terraform plan with -var and -var-file
+	Vars     map[string]string
+	VarFiles []string
+
+	// PlanLock, when set, is held while terraform plan runs, serializing plans of projects that
+	// share a state backend
+	PlanLock sync.Locker
+
+	// Phase, when set, is called with the name of each step of a drift check as it starts
+	Phase func(phase string)
+
+	// Timings, when set, receives how long terraform init and plan took
+	Timings *Timings
+
+	// InitTimeout and PlanTimeout kill terraform init and plan (or pulumi preview) when they
+	// run longer (0 = unlimited), failing the check with a TimeoutError
+	InitTimeout time.Duration
+	PlanTimeout time.Duration
+}
+
+// Timings holds the durations of the terraform commands of one drift check
+type Timings struct {
+	Init time.Duration
+	Plan time.Duration
+}
+
+// phase reports the start of a step of a drift check
+func (o Options) phase(name string) {
+	if o.Phase != nil {
+		o.Phase(name)
+	}
+}
+
 // CheckDrift runs terraform plan to detect configuration drift
 // Returns the plan output, exit code, and any error
 // Exit codes:
@@ -16,35 +69,69 @@ import (
 //   - 1: Error occurred
 //   - 2: Changes detected (drift present)
 func CheckDrift(projectPath string) (string, int, error) {
-	// Validate that the project path exists
-	if _, err := os.Stat(projectPath); os.IsNotExist(err) {
-		return "", 1, fmt.Errorf("project path does not exist: %s", projectPath)
+	return CheckDriftWithOptions(projectPath, Options{})
+}
+
+// CheckDriftWithOptions runs terraform plan like CheckDrift, using the given execution options
+func CheckDriftWithOptions(projectPath string, opts Options) (string, int, error) {
+	if opts.Fixture != "" {
+		return fixtureDrift(opts.Fixture)
+	}
+
+	// Validate that the project path exists (remote paths are checked by the shell on the runner)
+	if opts.Remote == nil {
+		if _, err := os.Stat(projectPath); os.IsNotExist(err) {
+			return "", 1, fmt.Errorf("project path does not exist: %s", projectPath)
+		}
 	}
 
 	// Set up cleanup function for lock files on error
 	cleanupLockFiles := func() {
 		// Clean up Terraform lock files on failure
-		tfLockFile := filepath.Join(projectPath, ".terraform.lock.hcl")
-		if err := os.Remove(tfLockFile); err != nil && !os.IsNotExist(err) {
+		if err := removeProjectFile(projectPath, opts, ".terraform.lock.hcl"); err != nil {
 			fmt.Printf("WARNING: Failed to clean up .terraform.lock.hcl: %v\n", err)
 		}
 
 		// Also try to clean up any .terraform.tfstate.lock.info files
-		tfStateLock := filepath.Join(projectPath, ".terraform.tfstate.lock.info")
-		if err := os.Remove(tfStateLock); err != nil && !os.IsNotExist(err) {
+		if err := removeProjectFile(projectPath, opts, ".terraform.tfstate.lock.info"); err != nil {
 			fmt.Printf("WARNING: Failed to clean up .terraform.tfstate.lock.info: %v\n", err)
 		}
 	}
 
 	// Run terraform init
-	initOutput, err := runTerraformInit(projectPath)
+	opts.phase("init")
+	start := time.Now()
+	initOutput, err := runTerraformInit(projectPath, opts)
+	if opts.Timings != nil {
+		opts.Timings.Init = time.Since(start)
+	}
 	if err != nil {
 		cleanupLockFiles()
 		return initOutput, 1, fmt.Errorf("terraform init failed: %w", err)
 	}
 
 	// Run terraform plan with detailed exit code
-	planOutput, exitCode, err := runTerraformPlan(projectPath)
+	if opts.PlanLock != nil {
+		opts.phase("waiting for the shared backend")
+		opts.PlanLock.Lock()
+	}
+	opts.phase("plan")
+	start = time.Now()
+	planOutput, exitCode, err := runTerraformPlan(projectPath, opts)
+	if opts.PlanLock != nil {
+		opts.PlanLock.Unlock()
+	}
+	if opts.Timings != nil {
+		opts.Timings.Plan = time.Since(start)
+	}
+
+	// The saved plan is only needed to inspect drift
+	if exitCode != 2 {
+		if removeErr := RemovePlanFile(projectPath, opts); removeErr != nil {
+			fmt.Printf("WARNING: Failed to clean up %s: %v\n", PlanFileName, removeErr)
+		}
+	}
+
 	if err != nil && exitCode != 2 {
 		// Exit code 2 is expected when drift is detected, so we don't treat it as an error
 		cleanupLockFiles()
@@ -54,39 +141,101 @@ func CheckDrift(projectPath string) (string, int, error) {
 	return planOutput, exitCode, nil
 }
 
+// Init runs terraform init in the project without planning, downloading providers and modules
+// into .terraform (and the plugin cache when TF_PLUGIN_CACHE_DIR is set)
+func Init(projectPath string, opts Options) (string, error) {
+	if opts.Remote == nil {
+		if _, err := os.Stat(projectPath); os.IsNotExist(err) {
+			return "", fmt.Errorf("project path does not exist: %s", projectPath)
+		}
+	}
+	return runTerraformInit(projectPath, opts)
+}
+
 // buildEnv returns the environment to use for terraform commands
-func buildEnv() []string {
+func buildEnv(extra map[string]string) []string {
 	env := os.Environ()
 	// Ensure automation-friendly output
 	if os.Getenv("TF_IN_AUTOMATION") == "" {
 		env = append(env, "TF_IN_AUTOMATION=true")
 	}
+	for _, key := range sortedKeys(extra) {
+		env = append(env, key+"="+extra[key])
+	}
 	return env
 }
 
-// runTerraformInit executes terraform init command
-func runTerraformInit(projectPath string) (string, error) {
-	// Clean up any existing lock files first
-	lockFile := filepath.Join(projectPath, ".terraform.lock.hcl")
-	if _, err := os.Stat(lockFile); err == nil {
-		// Lock file exists, try to remove it
-		if err := os.Remove(lockFile); err != nil {
-			// Log warning but continue
-			fmt.Printf("WARNING: Could not remove existing lock file: %v\n", err)
-		}
+// sortedKeys returns the keys of an environment map in a stable order
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
 	}
+	sort.Strings(keys)
+	return keys
+}
+
+// newTerraformCommand builds a terraform command for the project, locally or on the remote runner
+func newTerraformCommand(projectPath string, opts Options, args ...string) *exec.Cmd {
+	return newCommand(projectPath, opts, "terraform", args...)
+}
 
-	cmd := exec.Command("terraform", "init", "-input=false", "-no-color", "-upgrade=false")
+// newCommand builds a command run in the project directory with the project's environment
+func newCommand(projectPath string, opts Options, name string, args ...string) *exec.Cmd {
+	if opts.Remote != nil {
+		return opts.Remote.command(projectPath, opts.Env, name, args...)
+	}
+
+	cmd := exec.Command(name, args...)
 	cmd.Dir = projectPath
-	cmd.Env = buildEnv()
+	cmd.Env = buildEnv(opts.Env)
+	return cmd
+}
 
+// runCommand runs a command and returns its combined stdout and stderr output
+func runCommand(cmd *exec.Cmd, stream io.Writer) (string, error) {
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
+	if stream != nil {
+		cmd.Stdout = io.MultiWriter(&stdout, stream)
+		cmd.Stderr = io.MultiWriter(&stderr, stream)
+	}
 
 	err := cmd.Run()
-	output := stdout.String() + stderr.String()
+	return stdout.String() + stderr.String(), err
+}
 
+// removeProjectFile deletes a file from the project directory, ignoring missing files
+func removeProjectFile(projectPath string, opts Options, name string) error {
+	// Simulated checks never write to the project directory
+	if opts.Fixture != "" {
+		return nil
+	}
+	if opts.Remote != nil {
+		return opts.Remote.removeFile(projectPath, name)
+	}
+
+	if err := os.Remove(filepath.Join(projectPath, name)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// runTerraformInit executes terraform init command
+func runTerraformInit(projectPath string, opts Options) (string, error) {
+	// Clean up any existing lock files first
+	if err := removeProjectFile(projectPath, opts, ".terraform.lock.hcl"); err != nil {
+		// Log warning but continue
+		fmt.Printf("WARNING: Could not remove existing lock file: %v\n", err)
+	}
+
+	cmd := newTerraformCommand(projectPath, opts, "init", "-input=false", "-no-color", "-upgrade=false")
+	output, err := runCommandWithTimeout(cmd, opts.Stream, "init", opts.InitTimeout)
+
+	if TimeoutPhase(err) != "" {
+		return output, err
+	}
 	if err != nil {
 		// Check for common backend initialization errors
 		if strings.Contains(output, "Error loading backend config") ||
@@ -105,27 +254,34 @@ func runTerraformInit(projectPath string) (string, error) {
 }
 
 // runTerraformPlan executes terraform plan command with detailed exit code
-func runTerraformPlan(projectPath string) (string, int, error) {
-	cmd := exec.Command("terraform", "plan", "-input=false", "-no-color", "-detailed-exitcode")
-	cmd.Dir = projectPath
-	cmd.Env = buildEnv()
-
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	err := cmd.Run()
-	output := stdout.String() + stderr.String()
+func runTerraformPlan(projectPath string, opts Options) (string, int, error) {
+	args := []string{"plan", "-input=false", "-no-color", "-detailed-exitcode", "-out=" + PlanFileName}
+	// Variables set with -var follow the var files so they take precedence
+	for _, file := range opts.VarFiles {
+		args = append(args, "-var-file="+file)
+	}
+	for _, name := range sortedKeys(opts.Vars) {
+		args = append(args, "-var="+name+"="+opts.Vars[name])
+	}
+	cmd := newTerraformCommand(projectPath, opts, args...)
+	output, err := runCommandWithTimeout(cmd, opts.Stream, "plan", opts.PlanTimeout)
 
 	// Get the exit code
 	exitCode := 0
-	if exitErr, ok := err.(*exec.ExitError); ok {
+	if TimeoutPhase(err) != "" {
+		return output, 1, err
+	} else if exitErr, ok := err.(*exec.ExitError); ok {
 		exitCode = exitErr.ExitCode()
 	} else if err != nil {
 		// If there's an error but it's not an ExitError, something went wrong
 		return output, 1, fmt.Errorf("failed to execute terraform plan: %w", err)
 	}
 
+	// ssh reserves exit code 255 for its own connection failures
+	if opts.Remote != nil && exitCode == sshFailureExitCode {
+		return output, 1, fmt.Errorf("ssh connection to %s failed: %s", opts.Remote.Host, output)
+	}
+
 	// Exit code 2 means changes were detected (drift), which is not an error condition
 	if exitCode == 2 {
 		return output, exitCode, nil
@@ -139,6 +295,9 @@ func runTerraformPlan(projectPath string) (string, int, error) {
 	return output, exitCode, nil
 }
 
+// maxSummaryLineLength bounds each line of a plan summary
+const maxSummaryLineLength = 300
+
 // ExtractPlanSummary extracts a summary from the terraform plan output
 func ExtractPlanSummary(planOutput string) string {
 	lines := strings.Split(planOutput, "\n")
@@ -155,7 +314,12 @@ func ExtractPlanSummary(planOutput string) string {
 			strings.Contains(line, "No changes") ||
 			strings.Contains(line, "to add") ||
 			strings.Contains(line, "to change") ||
-			strings.Contains(line, "to destroy") {
+			strings.Contains(line, "to destroy") ||
+			// Resource counts of a pulumi preview
+			strings.Contains(line, "to create") ||
+			strings.Contains(line, "to update") ||
+			strings.Contains(line, "to delete") ||
+			strings.Contains(line, "to replace") {
 			summary = append(summary, trimmedLine)
 		}
 
@@ -195,7 +359,7 @@ func ExtractPlanSummary(planOutput string) string {
 	var result strings.Builder
 
 	if len(summary) > 0 {
-		result.WriteString(strings.Join(summary, "\n"))
+		result.WriteString(textutil.ShortenLines(strings.Join(summary, "\n"), maxSummaryLineLength))
 	} else {
 		result.WriteString("Drift detected in Terraform configuration")
 	}
@@ -203,7 +367,7 @@ func ExtractPlanSummary(planOutput string) string {
 	if len(resourceChanges) > 0 {
 		result.WriteString("\n\nResource Changes Detected:")
 		for _, change := range resourceChanges {
-			result.WriteString("\n  " + change)
+			result.WriteString("\n  " + textutil.ShortenLines(change, maxSummaryLineLength))
 		}
 		if len(resourceChanges) == 10 {
 			result.WriteString("\n  ... (more changes, see full plan for details)")
@@ -0,0 +1,34 @@
+package terraform
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+)
+
+// Fingerprint identifies a drift by what changed, so the same drift reported by several
+// scans or notifiers can be correlated and deduplicated. It is derived from the attribute
+// changelog when available, otherwise from the changed resources in the plan output, and
+// does not depend on the time or order in which changes were found.
+func Fingerprint(project string, changes []AttributeChange, planOutput string) string {
+	var lines []string
+	if len(changes) > 0 {
+		for _, c := range changes {
+			lines = append(lines, fmt.Sprintf("%s\x00%s\x00%s\x00%s\x00%s", c.Address, c.Action, c.Attribute, c.Before, c.After))
+		}
+	} else {
+		for _, c := range ParseResourceChanges(planOutput) {
+			lines = append(lines, c.Address+"\x00"+c.Action)
+		}
+	}
+	sort.Strings(lines)
+
+	h := sha256.New()
+	h.Write([]byte(project))
+	for _, line := range lines {
+		h.Write([]byte{'\n'})
+		h.Write([]byte(line))
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
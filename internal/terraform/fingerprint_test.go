@@ -0,0 +1,28 @@
+package terraform
+
+import "testing"
+
+func TestFingerprint(t *testing.T) {
+	a := AttributeChange{Address: "aws_vpc.main", Action: ActionUpdate, Attribute: "tags.Env", Before: `"dev"`, After: `"prod"`}
+	b := AttributeChange{Address: "aws_instance.web", Action: ActionUpdate, Attribute: "instance_type", Before: `"t3.micro"`, After: `"t3.large"`}
+
+	fp := Fingerprint("network", []AttributeChange{a, b}, "")
+	if len(fp) != 16 {
+		t.Fatalf("Expected a 16 character fingerprint, got %q", fp)
+	}
+	if got := Fingerprint("network", []AttributeChange{b, a}, ""); got != fp {
+		t.Errorf("Expected the fingerprint to ignore change order, got %s and %s", fp, got)
+	}
+	if got := Fingerprint("compute", []AttributeChange{a, b}, ""); got == fp {
+		t.Error("Expected different projects to have different fingerprints")
+	}
+	a.After = `"staging"`
+	if got := Fingerprint("network", []AttributeChange{a, b}, ""); got == fp {
+		t.Error("Expected a different value to change the fingerprint")
+	}
+
+	plan := "  # aws_instance.web will be updated in-place\n"
+	if Fingerprint("network", nil, plan) == Fingerprint("network", nil, "  # aws_instance.web will be destroyed\n") {
+		t.Error("Expected the plan fallback to distinguish actions")
+	}
+}
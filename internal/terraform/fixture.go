@@ -0,0 +1,105 @@
+package terraform
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/terradrift-watcher/internal/redact"
+)
+
+// FakeEnvVar names the environment variable that points drift checks at a fixtures directory
+// instead of the terraform binary
+const FakeEnvVar = "TERRADRIFT_FAKE_TF"
+
+// Files of a project fixture directory
+const (
+	// FixtureOutputFile holds the plan output
+	FixtureOutputFile = "plan.txt"
+
+	// FixtureExitCodeFile holds the plan exit code; without it the code is derived from the output
+	FixtureExitCodeFile = "exit_code"
+
+	// FixturePlanFile optionally holds the `terraform show -json` output used for the changelog
+	FixturePlanFile = "plan.json"
+)
+
+// fixtureDrift returns the canned plan output and exit code of a fixture directory
+func fixtureDrift(dir string) (string, int, error) {
+	data, err := os.ReadFile(filepath.Join(dir, FixtureOutputFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", 1, fmt.Errorf("no fixture found at %s", dir)
+		}
+		return "", 1, fmt.Errorf("failed to read fixture: %w", err)
+	}
+	output := string(data)
+
+	exitCode := 2
+	if strings.Contains(output, "No changes") {
+		exitCode = 0
+	}
+	if raw, err := os.ReadFile(filepath.Join(dir, FixtureExitCodeFile)); err == nil {
+		exitCode, err = strconv.Atoi(strings.TrimSpace(string(raw)))
+		if err != nil {
+			return "", 1, fmt.Errorf("invalid fixture exit code in %s: %w", dir, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return "", 1, fmt.Errorf("failed to read fixture exit code: %w", err)
+	}
+
+	if exitCode != 0 && exitCode != 2 {
+		return output, exitCode, fmt.Errorf("terraform plan failed with exit code %d: %s", exitCode, output)
+	}
+	return output, exitCode, nil
+}
+
+// fixturePlan parses the JSON plan of a fixture directory. A fixture without one has no changelog.
+func fixturePlan(dir string) (*Plan, error) {
+	data, err := os.ReadFile(filepath.Join(dir, FixturePlanFile))
+	if os.IsNotExist(err) {
+		return &Plan{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture plan: %w", err)
+	}
+
+	var plan Plan
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return nil, fmt.Errorf("failed to parse plan JSON: %w", err)
+	}
+	return &plan, nil
+}
+
+// RecordFixture stores the result of a real drift check in a fixture directory for replaying.
+// Registered secrets are redacted and values terraform marks as sensitive are masked. The plan
+// may be nil, in which case any earlier plan.json is removed.
+func RecordFixture(dir string, output string, exitCode int, plan *Plan) error {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create fixture directory: %w", err)
+	}
+
+	files := map[string]string{
+		FixtureOutputFile:   redact.String(output),
+		FixtureExitCodeFile: strconv.Itoa(exitCode) + "\n",
+	}
+	if plan != nil {
+		data, err := json.MarshalIndent(plan.Sanitized(), "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode fixture plan: %w", err)
+		}
+		files[FixturePlanFile] = redact.String(string(data)) + "\n"
+	} else if err := os.Remove(filepath.Join(dir, FixturePlanFile)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove stale fixture plan: %w", err)
+	}
+
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			return fmt.Errorf("failed to write fixture: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package terraform
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/terradrift-watcher/internal/redact"
+)
+
+func TestCheckDriftFromFixture(t *testing.T) {
+	dir := t.TempDir()
+	output := "  # aws_instance.web will be updated in-place\nPlan: 0 to add, 1 to change, 0 to destroy.\n"
+	if err := os.WriteFile(filepath.Join(dir, FixtureOutputFile), []byte(output), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	got, exitCode, err := CheckDriftWithOptions("/does/not/exist", Options{Fixture: dir})
+	if err != nil || exitCode != 2 || got != output {
+		t.Fatalf("Expected the canned drift, got exit code %d, err %v, output %q", exitCode, err, got)
+	}
+
+	// An explicit exit code simulates a failing plan
+	if err := os.WriteFile(filepath.Join(dir, FixtureExitCodeFile), []byte("1\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, exitCode, err := CheckDriftWithOptions("/does/not/exist", Options{Fixture: dir}); err == nil || exitCode != 1 {
+		t.Errorf("Expected a failed plan, got exit code %d, err %v", exitCode, err)
+	}
+
+	if _, _, err := CheckDriftWithOptions("", Options{Fixture: filepath.Join(dir, "missing")}); err == nil {
+		t.Error("Expected an error for a missing fixture")
+	}
+}
+
+func TestShowPlanFromFixture(t *testing.T) {
+	dir := t.TempDir()
+	plan, err := ShowPlan("", Options{Fixture: dir})
+	if err != nil || len(plan.ResourceChanges) != 0 {
+		t.Fatalf("Expected an empty plan without plan.json, got %+v, %v", plan, err)
+	}
+
+	planJSON := `{"resource_changes":[{"address":"aws_vpc.main","mode":"managed","type":"aws_vpc",` +
+		`"change":{"actions":["update"],"before":{"cidr_block":"10.0.0.0/16"},"after":{"cidr_block":"10.1.0.0/16"}}}]}`
+	if err := os.WriteFile(filepath.Join(dir, FixturePlanFile), []byte(planJSON), 0600); err != nil {
+		t.Fatal(err)
+	}
+	plan, err = ShowPlan("", Options{Fixture: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	changes := plan.AttributeChanges(nil)
+	if len(changes) != 1 || changes[0].Attribute != "cidr_block" {
+		t.Errorf("Expected the cidr_block change, got %+v", changes)
+	}
+}
+
+func TestRecordFixtureRoundTrip(t *testing.T) {
+	planJSON := `{"resource_changes":[{"address":"aws_db_instance.main","mode":"managed","type":"aws_db_instance",` +
+		`"change":{"actions":["update"],"before":{"password":"old-db-pass","tags":{"Env":"dev"}},` +
+		`"after":{"password":"new-db-pass","tags":{"Env":"prod"}},` +
+		`"before_sensitive":{"password":true},"after_sensitive":{"password":true}}}]}`
+	var plan Plan
+	if err := json.Unmarshal([]byte(planJSON), &plan); err != nil {
+		t.Fatal(err)
+	}
+
+	redact.Add("s3cr3t-token-value")
+	output := "Plan: 0 to add, 1 to change, 0 to destroy.\ntoken = s3cr3t-token-value\n"
+	dir := filepath.Join(t.TempDir(), "db")
+	if err := RecordFixture(dir, output, 2, &plan); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{FixtureOutputFile, FixturePlanFile} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, secret := range []string{"s3cr3t-token-value", "old-db-pass", "new-db-pass"} {
+			if strings.Contains(string(data), secret) {
+				t.Errorf("Expected %s to be sanitized, found %q", name, secret)
+			}
+		}
+	}
+
+	replayed, exitCode, err := CheckDriftWithOptions("", Options{Fixture: dir})
+	if err != nil || exitCode != 2 || !strings.Contains(replayed, "1 to change") {
+		t.Fatalf("Expected the recorded drift, got exit code %d, err %v, output %q", exitCode, err, replayed)
+	}
+
+	replayedPlan, err := ShowPlan("", Options{Fixture: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, want := replayedPlan.AttributeChanges(nil), plan.AttributeChanges(nil)
+	if len(got) != len(want) {
+		t.Fatalf("Expected the changelog %+v to survive recording, got %+v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Change %d: expected %+v, got %+v", i, want[i], got[i])
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package terraform
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// LinkedResource is a drifted resource as link resolvers see it
+type LinkedResource struct {
+	Address string // e.g. module.network.aws_vpc.main
+	Type    string // e.g. aws_vpc
+
+	// Attributes are the resource's attributes in state, nil for a resource not yet created
+	Attributes map[string]interface{}
+}
+
+// LinkResolver maps a resource to a human-facing page, such as its cloud console or an
+// internal CMDB entry, returning "" when it does not know the resource
+type LinkResolver interface {
+	ResolveLink(resource LinkedResource) string
+}
+
+// LinkResolverFunc adapts a function to a LinkResolver
+type LinkResolverFunc func(resource LinkedResource) string
+
+// ResolveLink calls f
+func (f LinkResolverFunc) ResolveLink(resource LinkedResource) string {
+	return f(resource)
+}
+
+// LinkResolvers tries each resolver in turn; the first link found wins
+type LinkResolvers []LinkResolver
+
+// ResolveLink returns the link of the first resolver that knows the resource
+func (r LinkResolvers) ResolveLink(resource LinkedResource) string {
+	for _, resolver := range r {
+		if link := resolver.ResolveLink(resource); link != "" {
+			return link
+		}
+	}
+	return ""
+}
+
+// Built-in resolvers. AWS resources are linked by their ARN, which carries the region, and
+// Azure resources by their resource ID.
+var (
+	AWSConsole  LinkResolver = LinkResolverFunc(awsConsoleLink)
+	AzurePortal LinkResolver = LinkResolverFunc(azurePortalLink)
+
+	BuiltinLinkResolvers = LinkResolvers{AWSConsole, AzurePortal}
+)
+
+// awsConsoleLink links an AWS resource through the console's ARN resolver
+func awsConsoleLink(resource LinkedResource) string {
+	if !strings.HasPrefix(resource.Type, "aws_") {
+		return ""
+	}
+	arn, _ := resource.Attributes["arn"].(string)
+	// Only the commercial partition has the console's ARN resolver
+	if strings.HasPrefix(arn, "arn:aws:") {
+		return "https://console.aws.amazon.com/go/view?arn=" + url.QueryEscape(arn)
+	}
+	return ""
+}
+
+// azurePortalLink links an Azure resource by its resource ID
+func azurePortalLink(resource LinkedResource) string {
+	if !strings.HasPrefix(resource.Type, "azurerm_") {
+		return ""
+	}
+	id, _ := resource.Attributes["id"].(string)
+	if strings.HasPrefix(strings.ToLower(id), "/subscriptions/") {
+		return "https://portal.azure.com/#resource" + id
+	}
+	return ""
+}
+
+// ConsoleURL returns a link to a resource in its cloud console, built from the resource's
+// attributes in state, or "" when the mapping is not known
+func ConsoleURL(resourceType string, values interface{}) string {
+	attributes, _ := values.(map[string]interface{})
+	return BuiltinLinkResolvers.ResolveLink(LinkedResource{Type: resourceType, Attributes: attributes})
+}
+
+// linkPlaceholder matches the placeholders of a link template, e.g. {attributes.id}
+var linkPlaceholder = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// LinkTemplate is a resolver that fills a URL template with the resource's address ({address}),
+// type ({type}) and attributes in state ({attributes.<path>}, e.g. {attributes.tags.Name}).
+// Values are escaped, and a resource lacking an attribute the template uses, or whose attribute
+// is sensitive, is not linked.
+type LinkTemplate string
+
+// Validate checks that the template is an http(s) URL using only known placeholders
+func (t LinkTemplate) Validate() error {
+	for _, match := range linkPlaceholder.FindAllStringSubmatch(string(t), -1) {
+		if name := match[1]; name != "address" && name != "type" && !strings.HasPrefix(name, "attributes.") {
+			return fmt.Errorf("unknown placeholder {%s} (supported: {address}, {type}, {attributes.<name>})", name)
+		}
+	}
+	u, err := url.Parse(linkPlaceholder.ReplaceAllString(string(t), "x"))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("must be an http:// or https:// URL")
+	}
+	return nil
+}
+
+// ResolveLink fills the template for the resource
+func (t LinkTemplate) ResolveLink(resource LinkedResource) string {
+	attributes := flatten(resource.Attributes)
+	missing := false
+	link := linkPlaceholder.ReplaceAllStringFunc(string(t), func(placeholder string) string {
+		var value string
+		switch name := placeholder[1 : len(placeholder)-1]; name {
+		case "address":
+			value = resource.Address
+		case "type":
+			value = resource.Type
+		default:
+			attribute, ok := attributes[strings.TrimPrefix(name, "attributes.")]
+			if !ok || attribute == nil || attribute == SensitiveValue {
+				missing = true
+				return ""
+			}
+			if s, isString := attribute.(string); isString {
+				value = s
+			} else {
+				value = formatValue(attribute)
+			}
+		}
+		if value == "" {
+			missing = true
+		}
+		return url.PathEscape(value)
+	})
+	if missing {
+		return ""
+	}
+	return link
+}
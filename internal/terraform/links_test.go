@@ -0,0 +1,129 @@
+package terraform
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestConsoleURL(t *testing.T) {
+	tests := []struct {
+		name         string
+		resourceType string
+		values       interface{}
+		want         string
+	}{
+		{
+			name:         "aws arn",
+			resourceType: "aws_security_group",
+			values:       map[string]interface{}{"arn": "arn:aws:ec2:eu-west-1:123456789012:security-group/sg-0abc"},
+			want:         "https://console.aws.amazon.com/go/view?arn=arn%3Aaws%3Aec2%3Aeu-west-1%3A123456789012%3Asecurity-group%2Fsg-0abc",
+		},
+		{
+			name:         "azure resource id",
+			resourceType: "azurerm_storage_account",
+			values:       map[string]interface{}{"id": "/subscriptions/0000/resourceGroups/rg/providers/Microsoft.Storage/storageAccounts/logs"},
+			want:         "https://portal.azure.com/#resource/subscriptions/0000/resourceGroups/rg/providers/Microsoft.Storage/storageAccounts/logs",
+		},
+		{
+			name:         "aws govcloud partition",
+			resourceType: "aws_s3_bucket",
+			values:       map[string]interface{}{"arn": "arn:aws-us-gov:s3:::logs"},
+		},
+		{
+			name:         "aws resource without arn",
+			resourceType: "aws_route",
+			values:       map[string]interface{}{"id": "r-rtb-123"},
+		},
+		{
+			name:         "resource not in state",
+			resourceType: "aws_s3_bucket",
+			values:       nil,
+		},
+		{
+			name:         "unmapped provider",
+			resourceType: "google_storage_bucket",
+			values:       map[string]interface{}{"id": "logs", "self_link": "https://www.googleapis.com/storage/v1/b/logs"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ConsoleURL(tt.resourceType, tt.values); got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestPlanAttributeChangesLinks(t *testing.T) {
+	planJSON := `{
+  "resource_changes": [
+    {
+      "address": "aws_s3_bucket.logs",
+      "mode": "managed",
+      "type": "aws_s3_bucket",
+      "change": {
+        "actions": ["update"],
+        "before": {"arn": "arn:aws:s3:::logs", "tags": {"Owner": "alice"}},
+        "after": {"arn": "arn:aws:s3:::logs", "tags": {"Owner": "bob"}}
+      }
+    },
+    {
+      "address": "aws_s3_bucket.new",
+      "mode": "managed",
+      "type": "aws_s3_bucket",
+      "change": {"actions": ["create"], "before": null, "after": {"bucket": "new"}, "after_unknown": {"arn": true}}
+    }
+  ]
+}`
+
+	var plan Plan
+	if err := json.Unmarshal([]byte(planJSON), &plan); err != nil {
+		t.Fatalf("Failed to parse plan: %v", err)
+	}
+	changes := plan.AttributeChanges(nil)
+	if len(changes) != 2 {
+		t.Fatalf("Expected 2 changes, got %+v", changes)
+	}
+	if changes[0].URL != "https://console.aws.amazon.com/go/view?arn=arn%3Aaws%3As3%3A%3A%3Alogs" {
+		t.Errorf("Expected a console link for the drifted bucket, got %q", changes[0].URL)
+	}
+	if changes[1].URL != "" {
+		t.Errorf("Expected no link for a resource that does not exist yet, got %q", changes[1].URL)
+	}
+}
+
+func TestLinkTemplate(t *testing.T) {
+	template := LinkTemplate("https://cmdb.example.com/ci/{attributes.tags.Name}?type={type}&address={address}")
+	if err := template.Validate(); err != nil {
+		t.Fatalf("Expected a valid template, got %v", err)
+	}
+	resource := LinkedResource{
+		Address:    "module.web.aws_instance.app[0]",
+		Type:       "aws_instance",
+		Attributes: map[string]interface{}{"tags": map[string]interface{}{"Name": "web server"}, "password": SensitiveValue},
+	}
+	want := "https://cmdb.example.com/ci/web%20server?type=aws_instance&address=module.web.aws_instance.app%5B0%5D"
+	if got := template.ResolveLink(resource); got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+
+	// A resource lacking the attribute, or whose attribute is sensitive, is left to other resolvers
+	if got := LinkTemplate("https://cmdb.example.com/{attributes.id}").ResolveLink(resource); got != "" {
+		t.Errorf("Expected no link without the attribute, got %q", got)
+	}
+	if got := LinkTemplate("https://cmdb.example.com/{attributes.password}").ResolveLink(resource); got != "" {
+		t.Errorf("Expected no link from a sensitive attribute, got %q", got)
+	}
+	resolvers := LinkResolvers{LinkTemplate("https://cmdb.example.com/{attributes.id}"), AWSConsole,
+		LinkResolverFunc(func(r LinkedResource) string { return "https://fallback.example.com/" + r.Address })}
+	if got := resolvers.ResolveLink(resource); got != "https://fallback.example.com/module.web.aws_instance.app[0]" {
+		t.Errorf("Expected the first resolver that knows the resource to win, got %q", got)
+	}
+
+	for _, invalid := range []LinkTemplate{"https://cmdb.example.com/{id}", "ftp://cmdb.example.com/{address}", "/ci/{address}"} {
+		if invalid.Validate() == nil {
+			t.Errorf("Expected %q to be invalid", invalid)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package terraform
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// localSourcePattern matches a local module source; terraform only treats paths starting with
+// ./ or ../ as local
+var localSourcePattern = regexp.MustCompile(`^\s*source\s*=\s*"(\.\.?/[^"]*)"`)
+
+// LocalModuleDirs returns the absolute directories of the local modules a terraform
+// configuration uses, directly or through other local modules. Registry and git sources are
+// not followed.
+func LocalModuleDirs(dir string) ([]string, error) {
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{root: true}
+	queue := []string{root}
+	var modules []string
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		sources, err := localSources(current)
+		if err != nil {
+			return nil, err
+		}
+		for _, source := range sources {
+			moduleDir := filepath.Clean(filepath.Join(current, source))
+			if seen[moduleDir] {
+				continue
+			}
+			seen[moduleDir] = true
+			// Other blocks such as file provisioners also have a source; only directories are modules
+			if info, err := os.Stat(moduleDir); err != nil || !info.IsDir() {
+				continue
+			}
+			modules = append(modules, moduleDir)
+			queue = append(queue, moduleDir)
+		}
+	}
+
+	sort.Strings(modules)
+	return modules, nil
+}
+
+// localSources returns the local source paths referenced by the .tf files of a directory
+func localSources(dir string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.tf"))
+	if err != nil {
+		return nil, err
+	}
+
+	var sources []string
+	for _, name := range files {
+		file, err := os.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			if match := localSourcePattern.FindStringSubmatch(scanner.Text()); match != nil {
+				sources = append(sources, strings.TrimSuffix(match[1], "/"))
+			}
+		}
+		err = scanner.Err()
+		file.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return sources, nil
+}
+
+// ModuleHash identifies the current contents of the .tf files directly in a module directory
+func ModuleHash(dir string) (string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.tf"))
+	if err != nil {
+		return "", err
+	}
+	sort.Strings(files)
+
+	hash := sha256.New()
+	for _, name := range files {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(hash, "%s\x00%d\x00", filepath.Base(name), len(data))
+		hash.Write(data)
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:16], nil
+}
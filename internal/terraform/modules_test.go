@@ -0,0 +1,55 @@
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLocalModuleDirs(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"live/prod/main.tf": `module "network" {
+  source = "../../modules/network"
+}
+module "vpc" {
+  source  = "terraform-aws-modules/vpc/aws"
+  version = "5.0.0"
+}
+resource "null_resource" "copy" {
+  provisioner "file" {
+    source = "./files/app.conf"
+  }
+}`,
+		"modules/network/main.tf": `module "subnets" {
+  source = "../subnets/"
+}`,
+		"modules/subnets/main.tf": `module "network" {
+  source = "../network"
+}`,
+		"live/prod/files/app.conf": "",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dirs, err := LocalModuleDirs(filepath.Join(root, "live/prod"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{filepath.Join(root, "modules/network"), filepath.Join(root, "modules/subnets")}
+	if len(dirs) != len(expected) {
+		t.Fatalf("Expected modules %v, got %v", expected, dirs)
+	}
+	for i := range expected {
+		if dirs[i] != expected[i] {
+			t.Errorf("Module %d: expected %s, got %s", i, expected[i], dirs[i])
+		}
+	}
+}
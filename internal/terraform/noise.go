@@ -0,0 +1,56 @@
+package terraform
+
+import (
+	"regexp"
+	"strings"
+)
+
+// planCountsLine matches the plan summary, e.g. "Plan: 1 to import, 0 to add, 2 to change, 0 to destroy."
+var planCountsLine = regexp.MustCompile(`^Plan:\s+(.*)$`)
+
+// planCount matches a single count of the plan summary
+var planCount = regexp.MustCompile(`(\d+)\s+to\s+\w+`)
+
+// NoisePlan reports whether a plan with changes would modify nothing real: every change is a
+// data source read or an output value. The JSON plan is used when available, otherwise the
+// human-readable plan output. A plan that cannot be classified is not noise.
+func NoisePlan(planOutput string, plan *Plan) bool {
+	if plan != nil {
+		for _, rc := range plan.ResourceChanges {
+			if rc.Mode == "data" {
+				continue
+			}
+			if action := planAction(rc.Change.Actions); action != "" && action != ActionRead {
+				return false
+			}
+		}
+	}
+
+	changes := ParseResourceChanges(planOutput)
+	for _, change := range changes {
+		if change.Action != ActionRead {
+			return false
+		}
+	}
+
+	summary := false
+	for _, line := range strings.Split(planOutput, "\n") {
+		trimmed := strings.TrimSpace(line)
+		// Moved resources change the state without being counted in the plan summary
+		if strings.HasPrefix(trimmed, "#") && strings.Contains(trimmed, " has moved to ") {
+			return false
+		}
+		match := planCountsLine.FindStringSubmatch(trimmed)
+		if match == nil {
+			continue
+		}
+		for _, count := range planCount.FindAllStringSubmatch(match[1], -1) {
+			if count[1] != "0" {
+				return false
+			}
+		}
+		summary = true
+	}
+
+	return (plan != nil && len(plan.ResourceChanges) > 0) || summary || len(changes) > 0 || strings.Contains(planOutput, "Changes to Outputs:")
+}
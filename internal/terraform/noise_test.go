@@ -0,0 +1,85 @@
+package terraform
+
+import "testing"
+
+func TestNoisePlan(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		plan   *Plan
+		noise  bool
+	}{
+		{
+			name: "data source read",
+			output: `
+  # data.aws_iam_policy_document.assume will be read during apply
+ <= data "aws_iam_policy_document" "assume" {
+    }
+
+Plan: 0 to add, 0 to change, 0 to destroy.
+`,
+			noise: true,
+		},
+		{
+			name: "output change",
+			output: `
+Changes to Outputs:
+  ~ vpc_id = "vpc-123" -> (known after apply)
+
+You can apply this plan to save these new output values to the Terraform state, without changing any real infrastructure.
+`,
+			noise: true,
+		},
+		{
+			name: "managed resource update",
+			output: `
+  # data.aws_iam_policy_document.assume will be read during apply
+  # aws_instance.web will be updated in-place
+
+Plan: 0 to add, 1 to change, 0 to destroy.
+`,
+		},
+		{
+			name:   "import",
+			output: "Plan: 1 to import, 0 to add, 0 to change, 0 to destroy.",
+		},
+		{
+			name:   "moved resource",
+			output: "  # aws_instance.old has moved to aws_instance.new\n\nPlan: 0 to add, 0 to change, 0 to destroy.",
+		},
+		{
+			name:   "unrecognised output",
+			output: "Error: something went wrong",
+		},
+		{
+			name:   "JSON plan with a managed change",
+			output: "Changes to Outputs:",
+			plan: &Plan{ResourceChanges: []PlanResourceChange{
+				planChange("aws_instance.web", "managed", "update"),
+			}},
+		},
+		{
+			name: "JSON plan with reads only",
+			plan: &Plan{ResourceChanges: []PlanResourceChange{
+				planChange("data.aws_ami.ubuntu", "data", "read"),
+				planChange("aws_instance.web", "managed", "no-op"),
+			}},
+			noise: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NoisePlan(tt.output, tt.plan); got != tt.noise {
+				t.Errorf("Expected noise %v, got %v", tt.noise, got)
+			}
+		})
+	}
+}
+
+// planChange builds a JSON plan resource entry with a single action
+func planChange(address, mode, action string) PlanResourceChange {
+	rc := PlanResourceChange{Address: address, Mode: mode}
+	rc.Change.Actions = []string{action}
+	return rc
+}
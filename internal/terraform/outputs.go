@@ -0,0 +1,55 @@
+package terraform
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// output is one value of `terraform output -json`
+type output struct {
+	Sensitive bool            `json:"sensitive"`
+	Value     json.RawMessage `json:"value"`
+}
+
+// Outputs returns the named root module outputs of the project, read from its state with
+// `terraform output -json`. Strings are returned as is and other values as JSON. Sensitive and
+// missing outputs are left out.
+func Outputs(projectPath string, opts Options, names []string) (map[string]string, error) {
+	cmd := newTerraformCommand(projectPath, opts, "output", "-json")
+	var stdout, stderr strings.Builder
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("terraform output failed: %w: %s", err, stderr.String())
+	}
+	return ParseOutputs([]byte(stdout.String()), names)
+}
+
+// ParseOutputs reads the named outputs from the JSON of `terraform output -json`
+func ParseOutputs(data []byte, names []string) (map[string]string, error) {
+	var outputs map[string]output
+	if err := json.Unmarshal(data, &outputs); err != nil {
+		return nil, fmt.Errorf("failed to parse terraform output: %w", err)
+	}
+
+	values := make(map[string]string, len(names))
+	for _, name := range names {
+		out, ok := outputs[name]
+		if !ok || out.Sensitive {
+			continue
+		}
+		var s string
+		if err := json.Unmarshal(out.Value, &s); err == nil {
+			values[name] = s
+			continue
+		}
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, out.Value); err != nil {
+			return nil, fmt.Errorf("failed to parse output %s: %w", name, err)
+		}
+		values[name] = compact.String()
+	}
+	return values, nil
+}
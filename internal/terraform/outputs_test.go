@@ -0,0 +1,33 @@
+package terraform
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseOutputs(t *testing.T) {
+	data := []byte(`{
+  "cluster_endpoint": {"sensitive": false, "type": "string", "value": "https://eks.example.com"},
+  "azs": {"sensitive": false, "type": ["list", "string"], "value": ["eu-west-1a", "eu-west-1b"]},
+  "replicas": {"sensitive": false, "type": "number", "value": 3},
+  "db_password": {"sensitive": true, "type": "string", "value": "hunter2"},
+  "unused": {"sensitive": false, "type": "string", "value": "x"}
+}`)
+
+	got, err := ParseOutputs(data, []string{"cluster_endpoint", "azs", "replicas", "db_password", "missing"})
+	if err != nil {
+		t.Fatalf("Failed to parse outputs: %v", err)
+	}
+	want := map[string]string{
+		"cluster_endpoint": "https://eks.example.com",
+		"azs":              `["eu-west-1a","eu-west-1b"]`,
+		"replicas":         "3",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+
+	if _, err := ParseOutputs([]byte("not json"), []string{"x"}); err == nil {
+		t.Error("Expected an error for invalid output")
+	}
+}
@@ -0,0 +1,354 @@
+package terraform
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/terradrift-watcher/internal/textutil"
+)
+
+// PlanFileName is the saved plan written to the project directory during a drift check
+const PlanFileName = "terradrift.tfplan"
+
+// Placeholders used in attribute changes
+const (
+	SensitiveValue = "(sensitive)"
+	UnknownValue   = "(known after apply)"
+)
+
+// maxValueLength bounds rendered attribute values in the changelog
+const maxValueLength = 200
+
+// Plan is the subset of `terraform show -json` output the watcher uses
+type Plan struct {
+	ResourceChanges []PlanResourceChange `json:"resource_changes"`
+}
+
+// PlanResourceChange is a resource entry of the JSON plan
+type PlanResourceChange struct {
+	Address string `json:"address"`
+	Mode    string `json:"mode"`
+	Type    string `json:"type"`
+	Change  struct {
+		Actions         []string    `json:"actions"`
+		Before          interface{} `json:"before"`
+		After           interface{} `json:"after"`
+		AfterUnknown    interface{} `json:"after_unknown"`
+		BeforeSensitive interface{} `json:"before_sensitive"`
+		AfterSensitive  interface{} `json:"after_sensitive"`
+	} `json:"change"`
+}
+
+// AttributeChange is one changelog entry: an attribute whose value differs between state and code
+type AttributeChange struct {
+	Address   string `json:"address"`
+	Action    string `json:"action"`
+	Attribute string `json:"attribute"`
+	Before    string `json:"before"`
+	After     string `json:"after"`
+
+	// Type and Description come from the provider schema when attribute descriptions are enabled
+	Type        string `json:"type,omitempty"`
+	Description string `json:"description,omitempty"`
+
+	// URL links to the resource in its cloud console, when known
+	URL string `json:"url,omitempty"`
+}
+
+// ShowPlan renders the saved plan of the last drift check as JSON and parses it
+func ShowPlan(projectPath string, opts Options) (*Plan, error) {
+	if opts.Fixture != "" {
+		return fixturePlan(opts.Fixture)
+	}
+
+	opts.phase("show")
+	cmd := newTerraformCommand(projectPath, opts, "show", "-json", "-no-color", PlanFileName)
+	var stdout, stderr strings.Builder
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("terraform show failed: %w: %s", err, stderr.String())
+	}
+
+	var plan Plan
+	if err := json.Unmarshal([]byte(stdout.String()), &plan); err != nil {
+		return nil, fmt.Errorf("failed to parse plan JSON: %w", err)
+	}
+	return &plan, nil
+}
+
+// RemovePlanFile deletes the saved plan from the project directory
+func RemovePlanFile(projectPath string, opts Options) error {
+	return removeProjectFile(projectPath, opts, PlanFileName)
+}
+
+// planAction reduces the JSON plan action list to a single change action
+func planAction(actions []string) string {
+	switch {
+	case len(actions) == 2:
+		return ActionReplace
+	case len(actions) == 1:
+		switch actions[0] {
+		case "create":
+			return ActionCreate
+		case "update":
+			return ActionUpdate
+		case "delete":
+			return ActionDelete
+		case "read":
+			return ActionRead
+		}
+	}
+	return ""
+}
+
+// AttributeChanges returns the changed attributes of every managed resource, with
+// sensitive values redacted. Created and deleted resources are listed as a single entry.
+// Resources are linked by the resolver, or the built-in resolvers when it is nil.
+func (p *Plan) AttributeChanges(resolver LinkResolver) []AttributeChange {
+	if resolver == nil {
+		resolver = BuiltinLinkResolvers
+	}
+	var changes []AttributeChange
+
+	for _, rc := range p.ResourceChanges {
+		action := planAction(rc.Change.Actions)
+		if action == "" || action == ActionRead || rc.Mode == "data" {
+			continue
+		}
+
+		// A resource in state is linked by its attributes there, sensitive ones masked; one not
+		// yet created has none
+		masked, _ := maskSensitive(rc.Change.Before, nil, rc.Change.BeforeSensitive, nil)
+		attributes, _ := masked.(map[string]interface{})
+		link := resolver.ResolveLink(LinkedResource{Address: rc.Address, Type: rc.Type, Attributes: attributes})
+
+		if action == ActionCreate || action == ActionDelete {
+			changes = append(changes, AttributeChange{Address: rc.Address, Action: action, URL: link})
+			continue
+		}
+
+		before := flatten(rc.Change.Before)
+		after := flatten(rc.Change.After)
+		unknown := flatten(rc.Change.AfterUnknown)
+		sensitive := flatten(rc.Change.BeforeSensitive)
+		for path, value := range flatten(rc.Change.AfterSensitive) {
+			sensitive[path] = value
+		}
+
+		paths := make(map[string]bool)
+		for path := range before {
+			paths[path] = true
+		}
+		for path := range after {
+			paths[path] = true
+		}
+		for path, value := range unknown {
+			if value == true {
+				paths[path] = true
+			}
+		}
+
+		sorted := make([]string, 0, len(paths))
+		for path := range paths {
+			sorted = append(sorted, path)
+		}
+		sort.Strings(sorted)
+
+		for _, path := range sorted {
+			beforeValue, hasBefore := before[path]
+			afterValue, hasAfter := after[path]
+			isUnknown := unknown[path] == true
+			if !isUnknown && hasBefore && hasAfter && formatValue(beforeValue) == formatValue(afterValue) {
+				continue
+			}
+
+			change := AttributeChange{
+				Address:   rc.Address,
+				Action:    action,
+				Attribute: path,
+				Before:    formatValue(beforeValue),
+				After:     formatValue(afterValue),
+				URL:       link,
+			}
+			if !hasBefore {
+				change.Before = "null"
+			}
+			if isUnknown {
+				change.After = UnknownValue
+			} else if !hasAfter {
+				change.After = "null"
+			}
+			if hasSensitivePrefix(sensitive, path) {
+				change.Before = SensitiveValue
+				change.After = SensitiveValue
+			}
+			changes = append(changes, change)
+		}
+	}
+
+	return changes
+}
+
+// flatten converts nested plan values into a map of dotted attribute paths to leaf values
+func flatten(value interface{}) map[string]interface{} {
+	out := make(map[string]interface{})
+	flattenInto(out, "", value)
+	return out
+}
+
+func flattenInto(out map[string]interface{}, prefix string, value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 && prefix != "" {
+			out[prefix] = v
+		}
+		for key, child := range v {
+			flattenInto(out, joinPath(prefix, key), child)
+		}
+	case []interface{}:
+		if len(v) == 0 && prefix != "" {
+			out[prefix] = v
+		}
+		for i, child := range v {
+			flattenInto(out, joinPath(prefix, strconv.Itoa(i)), child)
+		}
+	default:
+		if prefix != "" {
+			out[prefix] = v
+		}
+	}
+}
+
+func joinPath(prefix string, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// hasSensitivePrefix reports whether the path or any of its parents is marked sensitive
+func hasSensitivePrefix(sensitive map[string]interface{}, path string) bool {
+	for {
+		if sensitive[path] == true {
+			return true
+		}
+		i := strings.LastIndex(path, ".")
+		if i < 0 {
+			return false
+		}
+		path = path[:i]
+	}
+}
+
+// formatValue renders a leaf value as compact JSON, truncated for readability
+func formatValue(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	s := string(data)
+	if len(s) > maxValueLength {
+		s = textutil.Truncate(s, maxValueLength) + "..."
+	}
+	return s
+}
+
+// Sanitized returns a copy of the plan with every value terraform marks as sensitive replaced,
+// so it can be stored as a fixture. Masked values that differ still differ, keeping the change.
+func (p *Plan) Sanitized() *Plan {
+	out := &Plan{ResourceChanges: make([]PlanResourceChange, len(p.ResourceChanges))}
+	for i, rc := range p.ResourceChanges {
+		rc.Change.Before, rc.Change.After = maskSensitive(rc.Change.Before, rc.Change.After,
+			rc.Change.BeforeSensitive, rc.Change.AfterSensitive)
+		out.ResourceChanges[i] = rc
+	}
+	return out
+}
+
+// maskSensitive masks the sensitive values of a before/after pair, walking both trees together.
+// A value is masked on both sides when either side marks it sensitive.
+func maskSensitive(before, after, beforeSensitive, afterSensitive interface{}) (interface{}, interface{}) {
+	if beforeSensitive == true || afterSensitive == true {
+		var maskedBefore, maskedAfter interface{}
+		if before != nil {
+			maskedBefore = SensitiveValue
+		}
+		if after != nil {
+			maskedAfter = SensitiveValue
+			if before != nil && !reflect.DeepEqual(before, after) {
+				maskedAfter = SensitiveValue + " (changed)"
+			}
+		}
+		return maskedBefore, maskedAfter
+	}
+
+	beforeMap, beforeIsMap := before.(map[string]interface{})
+	afterMap, afterIsMap := after.(map[string]interface{})
+	if beforeIsMap || afterIsMap {
+		beforeMarks, _ := beforeSensitive.(map[string]interface{})
+		afterMarks, _ := afterSensitive.(map[string]interface{})
+		outBefore := make(map[string]interface{}, len(beforeMap))
+		outAfter := make(map[string]interface{}, len(afterMap))
+		keys := make(map[string]bool)
+		for key := range beforeMap {
+			keys[key] = true
+		}
+		for key := range afterMap {
+			keys[key] = true
+		}
+		for key := range keys {
+			b, a := maskSensitive(beforeMap[key], afterMap[key], beforeMarks[key], afterMarks[key])
+			if _, ok := beforeMap[key]; ok {
+				outBefore[key] = b
+			}
+			if _, ok := afterMap[key]; ok {
+				outAfter[key] = a
+			}
+		}
+		if beforeIsMap {
+			before = outBefore
+		}
+		if afterIsMap {
+			after = outAfter
+		}
+		return before, after
+	}
+
+	beforeList, beforeIsList := before.([]interface{})
+	afterList, afterIsList := after.([]interface{})
+	if beforeIsList || afterIsList {
+		beforeMarks, _ := beforeSensitive.([]interface{})
+		afterMarks, _ := afterSensitive.([]interface{})
+		outBefore := make([]interface{}, len(beforeList))
+		outAfter := make([]interface{}, len(afterList))
+		for i := 0; i < len(beforeList) || i < len(afterList); i++ {
+			b, a := maskSensitive(listItem(beforeList, i), listItem(afterList, i), listItem(beforeMarks, i), listItem(afterMarks, i))
+			if i < len(beforeList) {
+				outBefore[i] = b
+			}
+			if i < len(afterList) {
+				outAfter[i] = a
+			}
+		}
+		if beforeIsList {
+			before = outBefore
+		}
+		if afterIsList {
+			after = outAfter
+		}
+	}
+	return before, after
+}
+
+// listItem returns the i-th element of a list, or nil past its end
+func listItem(list []interface{}, i int) interface{} {
+	if i < len(list) {
+		return list[i]
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package terraform
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestPlanAttributeChanges(t *testing.T) {
+	planJSON := `{
+  "resource_changes": [
+    {
+      "address": "aws_security_group.web",
+      "mode": "managed",
+      "type": "aws_security_group",
+      "change": {
+        "actions": ["update"],
+        "before": {"name": "web", "tags": {"Owner": "alice"}, "ingress": [{"port": 22}], "secret": "old"},
+        "after": {"name": "web", "tags": {"Owner": "bob"}, "ingress": [{"port": 22}], "secret": "new", "arn": null},
+        "after_unknown": {"arn": true},
+        "before_sensitive": {"secret": true},
+        "after_sensitive": {"secret": true}
+      }
+    },
+    {
+      "address": "aws_s3_bucket.logs",
+      "mode": "managed",
+      "type": "aws_s3_bucket",
+      "change": {"actions": ["create"], "before": null, "after": {"bucket": "logs"}}
+    },
+    {
+      "address": "data.aws_caller_identity.current",
+      "mode": "data",
+      "type": "aws_caller_identity",
+      "change": {"actions": ["read"], "before": null, "after": {}}
+    }
+  ]
+}`
+
+	var plan Plan
+	if err := json.Unmarshal([]byte(planJSON), &plan); err != nil {
+		t.Fatalf("Failed to parse plan: %v", err)
+	}
+
+	changes := plan.AttributeChanges(nil)
+	expected := []AttributeChange{
+		{Address: "aws_security_group.web", Action: ActionUpdate, Attribute: "arn", Before: "null", After: UnknownValue},
+		{Address: "aws_security_group.web", Action: ActionUpdate, Attribute: "secret", Before: SensitiveValue, After: SensitiveValue},
+		{Address: "aws_security_group.web", Action: ActionUpdate, Attribute: "tags.Owner", Before: `"alice"`, After: `"bob"`},
+		{Address: "aws_s3_bucket.logs", Action: ActionCreate},
+	}
+
+	if len(changes) != len(expected) {
+		t.Fatalf("Expected %d changes, got %d: %+v", len(expected), len(changes), changes)
+	}
+	for i := range expected {
+		if changes[i] != expected[i] {
+			t.Errorf("Change %d: expected %+v, got %+v", i, expected[i], changes[i])
+		}
+	}
+}
@@ -0,0 +1,288 @@
+package terraform
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/terradrift-watcher/internal/textutil"
+)
+
+// Static analysis tools run as policy stages
+const (
+	PolicyTFLint  = "tflint"
+	PolicyCheckov = "checkov"
+	PolicyTrivy   = "trivy" // trivy config
+)
+
+// PolicyTools are the supported static analysis tools
+var PolicyTools = []string{PolicyTFLint, PolicyCheckov, PolicyTrivy}
+
+// PolicySeverities are the severities of policy findings, lowest first
+var PolicySeverities = []string{"low", "medium", "high", "critical"}
+
+// maxPolicyMessage bounds the recorded message of a policy finding
+const maxPolicyMessage = 300
+
+// PolicyFinding is a problem a static analysis tool found in a project's configuration, e.g.
+// an unencrypted bucket or an unused variable
+type PolicyFinding struct {
+	Tool     string `json:"tool"`
+	Rule     string `json:"rule"`     // e.g. CKV_AWS_18 or terraform_unused_declarations
+	Severity string `json:"severity"` // One of PolicySeverities
+	Resource string `json:"resource,omitempty"`
+	File     string `json:"file,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Message  string `json:"message,omitempty"`
+}
+
+// String renders the finding on one line
+func (f PolicyFinding) String() string {
+	s := fmt.Sprintf("[%s] %s %s", f.Severity, f.Tool, f.Rule)
+	if location := f.Location(); location != "" {
+		s += " (" + location + ")"
+	}
+	if f.Message != "" {
+		s += ": " + f.Message
+	}
+	return s
+}
+
+// Location returns where the finding is: its resource, or else its file and line
+func (f PolicyFinding) Location() string {
+	switch {
+	case f.Resource != "":
+		return f.Resource
+	case f.File != "" && f.Line > 0:
+		return fmt.Sprintf("%s:%d", f.File, f.Line)
+	}
+	return f.File
+}
+
+// Key identifies the finding across scans. The line is left out so an edit moving the
+// offending block does not make the finding new.
+func (f PolicyFinding) Key() string {
+	return f.Tool + "|" + f.Rule + "|" + f.Resource + "|" + f.File
+}
+
+// PolicySeverityRank orders severities from 0 (low), or returns -1 for an unknown severity
+func PolicySeverityRank(severity string) int {
+	for i, s := range PolicySeverities {
+		if s == severity {
+			return i
+		}
+	}
+	return -1
+}
+
+// RunPolicyTool runs a static analysis tool over the project's configuration with extra args
+// and returns its findings. The tools exit non-zero when they find problems, so only output
+// that cannot be parsed is an error. A run longer than timeout (0 = unlimited) is killed.
+func RunPolicyTool(projectPath string, opts Options, tool string, args []string, timeout time.Duration) ([]PolicyFinding, error) {
+	var name string
+	var toolArgs []string
+	var parse func([]byte) ([]PolicyFinding, error)
+	switch tool {
+	case PolicyTFLint:
+		name, parse = "tflint", ParseTFLint
+		toolArgs = append([]string{"--format=json", "--no-color"}, args...)
+	case PolicyCheckov:
+		name, parse = "checkov", ParseCheckov
+		toolArgs = append([]string{"--directory", ".", "--output", "json", "--quiet", "--compact"}, args...)
+	case PolicyTrivy:
+		name, parse = "trivy", ParseTrivy
+		toolArgs = append(append([]string{"config", "--format", "json", "--quiet"}, args...), ".")
+	default:
+		return nil, fmt.Errorf("unknown policy tool '%s'", tool)
+	}
+
+	cmd := newCommand(projectPath, opts, name, toolArgs...)
+	var stdout, stderr strings.Builder
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	runErr := waitWithTimeout(cmd, "policy", timeout)
+	if TimeoutPhase(runErr) != "" {
+		return nil, runErr
+	}
+	findings, err := parse([]byte(stdout.String()))
+	if err != nil {
+		if runErr != nil {
+			if msg := strings.TrimSpace(stderr.String()); msg != "" {
+				return nil, fmt.Errorf("%s failed: %w: %s", tool, runErr, msg)
+			}
+			return nil, fmt.Errorf("%s failed: %w", tool, runErr)
+		}
+		return nil, err
+	}
+	for i := range findings {
+		findings[i].Tool = tool
+		findings[i].Message = strings.Join(strings.Fields(findings[i].Message), " ")
+		if len(findings[i].Message) > maxPolicyMessage {
+			findings[i].Message = textutil.Truncate(findings[i].Message, maxPolicyMessage) + "..."
+		}
+	}
+	return findings, nil
+}
+
+// tflintOutput is the output of `tflint --format=json`
+type tflintOutput struct {
+	Issues []struct {
+		Rule struct {
+			Name     string `json:"name"`
+			Severity string `json:"severity"`
+		} `json:"rule"`
+		Message string `json:"message"`
+		Range   struct {
+			Filename string `json:"filename"`
+			Start    struct {
+				Line int `json:"line"`
+			} `json:"start"`
+		} `json:"range"`
+	} `json:"issues"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// ParseTFLint reads the findings of `tflint --format=json`. Its errors and warnings are high
+// and medium findings, and notices low.
+func ParseTFLint(data []byte) ([]PolicyFinding, error) {
+	var out tflintOutput
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("failed to parse tflint output: %w", err)
+	}
+	if len(out.Errors) > 0 {
+		messages := make([]string, len(out.Errors))
+		for i, e := range out.Errors {
+			messages[i] = e.Message
+		}
+		return nil, fmt.Errorf("tflint failed: %s", strings.Join(messages, "; "))
+	}
+
+	findings := make([]PolicyFinding, 0, len(out.Issues))
+	for _, issue := range out.Issues {
+		severity := "low"
+		switch strings.ToLower(issue.Rule.Severity) {
+		case "error":
+			severity = "high"
+		case "warning":
+			severity = "medium"
+		}
+		findings = append(findings, PolicyFinding{
+			Rule:     issue.Rule.Name,
+			Severity: severity,
+			File:     issue.Range.Filename,
+			Line:     issue.Range.Start.Line,
+			Message:  issue.Message,
+		})
+	}
+	return findings, nil
+}
+
+// checkovReport is the output of `checkov --output json` for one framework
+type checkovReport struct {
+	Results struct {
+		FailedChecks []struct {
+			CheckID   string  `json:"check_id"`
+			CheckName string  `json:"check_name"`
+			Resource  string  `json:"resource"`
+			FilePath  string  `json:"file_path"`
+			LineRange []int   `json:"file_line_range"`
+			Severity  *string `json:"severity"`
+		} `json:"failed_checks"`
+	} `json:"results"`
+}
+
+// ParseCheckov reads the failed checks of `checkov --output json`, a report or a list of
+// reports when several frameworks ran. Checks without a severity, which checkov only has with
+// a Prisma Cloud API key, are medium findings.
+func ParseCheckov(data []byte) ([]PolicyFinding, error) {
+	var reports []checkovReport
+	trimmed := strings.TrimSpace(string(data))
+	if strings.HasPrefix(trimmed, "[") {
+		if err := json.Unmarshal([]byte(trimmed), &reports); err != nil {
+			return nil, fmt.Errorf("failed to parse checkov output: %w", err)
+		}
+	} else {
+		var report checkovReport
+		if err := json.Unmarshal([]byte(trimmed), &report); err != nil {
+			return nil, fmt.Errorf("failed to parse checkov output: %w", err)
+		}
+		reports = []checkovReport{report}
+	}
+
+	var findings []PolicyFinding
+	for _, report := range reports {
+		for _, check := range report.Results.FailedChecks {
+			severity := "medium"
+			if check.Severity != nil && PolicySeverityRank(strings.ToLower(*check.Severity)) >= 0 {
+				severity = strings.ToLower(*check.Severity)
+			}
+			finding := PolicyFinding{
+				Rule:     check.CheckID,
+				Severity: severity,
+				Resource: check.Resource,
+				File:     strings.TrimPrefix(check.FilePath, "/"),
+				Message:  check.CheckName,
+			}
+			if len(check.LineRange) > 0 {
+				finding.Line = check.LineRange[0]
+			}
+			findings = append(findings, finding)
+		}
+	}
+	return findings, nil
+}
+
+// trivyOutput is the output of `trivy config --format json`
+type trivyOutput struct {
+	Results []struct {
+		Target            string `json:"Target"`
+		Misconfigurations []struct {
+			ID            string `json:"ID"`
+			Title         string `json:"Title"`
+			Message       string `json:"Message"`
+			Severity      string `json:"Severity"`
+			Status        string `json:"Status"`
+			CauseMetadata struct {
+				Resource  string `json:"Resource"`
+				StartLine int    `json:"StartLine"`
+			} `json:"CauseMetadata"`
+		} `json:"Misconfigurations"`
+	} `json:"Results"`
+}
+
+// ParseTrivy reads the failed checks of `trivy config --format json`
+func ParseTrivy(data []byte) ([]PolicyFinding, error) {
+	var out trivyOutput
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("failed to parse trivy output: %w", err)
+	}
+
+	var findings []PolicyFinding
+	for _, result := range out.Results {
+		for _, m := range result.Misconfigurations {
+			if m.Status != "" && m.Status != "FAIL" {
+				continue
+			}
+			severity := strings.ToLower(m.Severity)
+			if PolicySeverityRank(severity) < 0 {
+				severity = "medium"
+			}
+			message := m.Title
+			if m.Message != "" {
+				message += ": " + m.Message
+			}
+			findings = append(findings, PolicyFinding{
+				Rule:     m.ID,
+				Severity: severity,
+				Resource: m.CauseMetadata.Resource,
+				File:     result.Target,
+				Line:     m.CauseMetadata.StartLine,
+				Message:  message,
+			})
+		}
+	}
+	return findings, nil
+}
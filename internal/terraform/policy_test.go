@@ -0,0 +1,93 @@
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestParseTFLint(t *testing.T) {
+	output := `{"issues":[{"rule":{"name":"terraform_unused_declarations","severity":"warning","link":""},
+"message":"variable \"region\" is declared but not used","range":{"filename":"variables.tf","start":{"line":3,"column":1}}},
+{"rule":{"name":"aws_instance_invalid_type","severity":"error"},"message":"\"t9.micro\" is an invalid value",
+"range":{"filename":"main.tf","start":{"line":12,"column":3}}}],"errors":[]}`
+	findings, err := ParseTFLint([]byte(output))
+	if err != nil {
+		t.Fatalf("ParseTFLint failed: %v", err)
+	}
+	if len(findings) != 2 {
+		t.Fatalf("Expected 2 findings, got %+v", findings)
+	}
+	if f := findings[0]; f.Rule != "terraform_unused_declarations" || f.Severity != "medium" || f.File != "variables.tf" || f.Line != 3 {
+		t.Errorf("Unexpected finding %+v", f)
+	}
+	if findings[1].Severity != "high" {
+		t.Errorf("Expected a tflint error to be high, got %s", findings[1].Severity)
+	}
+
+	if _, err := ParseTFLint([]byte(`{"issues":[],"errors":[{"message":"Failed to load configurations"}]}`)); err == nil {
+		t.Error("Expected tflint errors to fail the stage")
+	}
+}
+
+func TestParseCheckov(t *testing.T) {
+	report := `{"check_type":"terraform","results":{"failed_checks":[{"check_id":"CKV_AWS_18",
+"check_name":"Ensure the S3 bucket has access logging enabled","resource":"aws_s3_bucket.logs",
+"file_path":"/main.tf","file_line_range":[1,5],"severity":null}]}}`
+	findings, err := ParseCheckov([]byte(report))
+	if err != nil {
+		t.Fatalf("ParseCheckov failed: %v", err)
+	}
+	if len(findings) != 1 || findings[0].Rule != "CKV_AWS_18" || findings[0].Severity != "medium" ||
+		findings[0].Resource != "aws_s3_bucket.logs" || findings[0].File != "main.tf" || findings[0].Line != 1 {
+		t.Errorf("Unexpected findings %+v", findings)
+	}
+
+	// Several frameworks give a list of reports; a directory without resources only a summary
+	list := `[` + report + `,{"check_type":"secrets","results":{"failed_checks":[{"check_id":"CKV_SECRET_2","severity":"HIGH"}]}}]`
+	if findings, err := ParseCheckov([]byte(list)); err != nil || len(findings) != 2 || findings[1].Severity != "high" {
+		t.Errorf("Expected findings of both reports, got %+v, %v", findings, err)
+	}
+	if findings, err := ParseCheckov([]byte(`{"passed":0,"failed":0,"resource_count":0}`)); err != nil || len(findings) != 0 {
+		t.Errorf("Expected no findings, got %+v, %v", findings, err)
+	}
+}
+
+func TestParseTrivy(t *testing.T) {
+	output := `{"SchemaVersion":2,"Results":[{"Target":"main.tf","Class":"config","Misconfigurations":[
+{"ID":"AVD-AWS-0086","Title":"S3 Access block should block public ACL","Message":"No public access block",
+"Severity":"HIGH","Status":"FAIL","CauseMetadata":{"Resource":"aws_s3_bucket.logs","StartLine":1}},
+{"ID":"AVD-AWS-0088","Title":"Bucket encrypted","Severity":"HIGH","Status":"PASS"}]}]}`
+	findings, err := ParseTrivy([]byte(output))
+	if err != nil {
+		t.Fatalf("ParseTrivy failed: %v", err)
+	}
+	if len(findings) != 1 || findings[0].Severity != "high" || findings[0].Message != "S3 Access block should block public ACL: No public access block" {
+		t.Errorf("Expected only the failed check, got %+v", findings)
+	}
+}
+
+func TestRunPolicyTool(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake tflint script requires a POSIX shell")
+	}
+	bin := t.TempDir()
+	// tflint exits 2 when it finds issues
+	script := "#!/bin/sh\necho '{\"issues\":[{\"rule\":{\"name\":\"r\",\"severity\":\"notice\"},\"message\":\"m\",\"range\":{\"filename\":\"main.tf\"}}],\"errors\":[]}'\nexit 2\n"
+	if err := os.WriteFile(filepath.Join(bin, "tflint"), []byte(script), 0700); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	findings, err := RunPolicyTool(t.TempDir(), Options{}, PolicyTFLint, nil, 0)
+	if err != nil {
+		t.Fatalf("RunPolicyTool failed: %v", err)
+	}
+	if len(findings) != 1 || findings[0].Tool != PolicyTFLint || findings[0].Severity != "low" {
+		t.Errorf("Unexpected findings %+v", findings)
+	}
+	if got := findings[0].String(); got != "[low] tflint r (main.tf): m" {
+		t.Errorf("Unexpected rendering %q", got)
+	}
+}
@@ -0,0 +1,44 @@
+package terraform
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// pulumiChangesProposed is how pulumi preview --expect-no-changes reports changes
+const pulumiChangesProposed = "no changes were expected but changes were proposed"
+
+// CheckPulumiDrift previews a Pulumi stack against refreshed live state and maps the result to
+// the drift exit codes of CheckDrift: 0 for no changes, 2 for drift and 1 for errors. An empty
+// stack previews the currently selected one.
+func CheckPulumiDrift(projectPath string, stack string, opts Options) (string, int, error) {
+	args := []string{"preview", "--diff", "--refresh", "--expect-no-changes", "--non-interactive", "--color", "never"}
+	if stack != "" {
+		args = append(args, "--stack", stack)
+	}
+	opts.phase("preview")
+	cmd := newCommand(projectPath, opts, "pulumi", args...)
+	output, err := runCommandWithTimeout(cmd, opts.Stream, "preview", opts.PlanTimeout)
+
+	exitCode := 0
+	if TimeoutPhase(err) != "" {
+		return output, 1, err
+	} else if exitErr, ok := err.(*exec.ExitError); ok {
+		exitCode = exitErr.ExitCode()
+	} else if err != nil {
+		return output, 1, fmt.Errorf("failed to execute pulumi preview: %w", err)
+	}
+
+	if exitCode == 0 {
+		return output, 0, nil
+	}
+	// pulumi exits with the same code for every failure, so drift is told apart by its message
+	if strings.Contains(output, pulumiChangesProposed) {
+		return output, 2, nil
+	}
+	if opts.Remote != nil && exitCode == sshFailureExitCode {
+		return output, 1, fmt.Errorf("ssh connection to %s failed: %s", opts.Remote.Host, output)
+	}
+	return output, 1, fmt.Errorf("pulumi preview failed with exit code %d: %s", exitCode, output)
+}
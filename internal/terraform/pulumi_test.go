@@ -0,0 +1,53 @@
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// fakePulumi puts a pulumi script printing output and exiting with exitCode first on the PATH
+func fakePulumi(t *testing.T, output string, exitCode string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake pulumi script requires a POSIX shell")
+	}
+	bin := t.TempDir()
+	script := "#!/bin/sh\nprintf '%s\\n' '" + output + "'\nexit " + exitCode + "\n"
+	if err := os.WriteFile(filepath.Join(bin, "pulumi"), []byte(script), 0700); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestCheckPulumiDrift(t *testing.T) {
+	tests := []struct {
+		name         string
+		output       string
+		exitCode     string
+		wantExitCode int
+		wantErr      bool
+	}{
+		{"no changes", "Resources:\n    3 unchanged", "0", 0, false},
+		{"drift", "    ~ 1 to update\nerror: no changes were expected but changes were proposed", "255", 2, false},
+		{"failure", "error: failed to load language plugin", "255", 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakePulumi(t, tt.output, tt.exitCode)
+			_, exitCode, err := CheckPulumiDrift(t.TempDir(), "dev", Options{})
+			if exitCode != tt.wantExitCode || (err != nil) != tt.wantErr {
+				t.Errorf("Expected exit code %d (error %v), got %d, %v", tt.wantExitCode, tt.wantErr, exitCode, err)
+			}
+		})
+	}
+}
+
+func TestExtractPlanSummaryPulumi(t *testing.T) {
+	summary := ExtractPlanSummary("Resources:\n    ~ 1 to update\n    2 unchanged\n")
+	if summary == "" || summary == "Drift detected in Terraform configuration" {
+		t.Errorf("Expected the pulumi resource counts in the summary, got %q", summary)
+	}
+}
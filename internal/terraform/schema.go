@@ -0,0 +1,138 @@
+package terraform
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/terradrift-watcher/internal/textutil"
+)
+
+// maxDescriptionLength bounds attribute descriptions shown next to changes
+const maxDescriptionLength = 200
+
+// ProviderSchemas holds the resource schemas of the providers a project uses, as reported by
+// `terraform providers schema -json`
+type ProviderSchemas struct {
+	resources map[string]schemaBlock
+}
+
+type schemaBlock struct {
+	Attributes  map[string]schemaAttribute `json:"attributes"`
+	BlockTypes  map[string]schemaBlockType `json:"block_types"`
+	Description string                     `json:"description"`
+}
+
+type schemaBlockType struct {
+	NestingMode string      `json:"nesting_mode"`
+	Block       schemaBlock `json:"block"`
+}
+
+type schemaAttribute struct {
+	Type        interface{} `json:"type"`
+	Description string      `json:"description"`
+}
+
+// ShowProviderSchemas renders the provider schemas of an initialized project and parses them
+func ShowProviderSchemas(projectPath string, opts Options) (*ProviderSchemas, error) {
+	if opts.Fixture != "" {
+		return nil, fmt.Errorf("provider schemas are not available when simulating terraform")
+	}
+
+	cmd := newTerraformCommand(projectPath, opts, "providers", "schema", "-json")
+	var stdout, stderr strings.Builder
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("terraform providers schema failed: %w: %s", err, stderr.String())
+	}
+	return ParseProviderSchemas([]byte(stdout.String()))
+}
+
+// ParseProviderSchemas parses the output of `terraform providers schema -json`
+func ParseProviderSchemas(data []byte) (*ProviderSchemas, error) {
+	var raw struct {
+		ProviderSchemas map[string]struct {
+			ResourceSchemas map[string]struct {
+				Block schemaBlock `json:"block"`
+			} `json:"resource_schemas"`
+		} `json:"provider_schemas"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse provider schemas: %w", err)
+	}
+
+	schemas := &ProviderSchemas{resources: make(map[string]schemaBlock)}
+	for _, provider := range raw.ProviderSchemas {
+		for resourceType, resource := range provider.ResourceSchemas {
+			schemas.resources[resourceType] = resource.Block
+		}
+	}
+	return schemas, nil
+}
+
+// Annotate fills in the type and description of each changed attribute found in the schemas
+func (s *ProviderSchemas) Annotate(changes []AttributeChange) {
+	for i := range changes {
+		if changes[i].Attribute == "" {
+			continue
+		}
+		block, ok := s.resources[ResourceType(changes[i].Address)]
+		if !ok {
+			continue
+		}
+		if typ, description, ok := describeAttribute(block, changes[i].Attribute); ok {
+			changes[i].Type = typ
+			changes[i].Description = shortDescription(description)
+		}
+	}
+}
+
+// describeAttribute walks a dotted attribute path through nested blocks to the attribute it
+// names. Path segments past the attribute, such as map keys, belong to its value.
+func describeAttribute(block schemaBlock, path string) (string, string, bool) {
+	for _, part := range strings.Split(path, ".") {
+		if strings.Trim(part, "0123456789") == "" {
+			continue // List and set indexes of nested blocks
+		}
+		if attribute, ok := block.Attributes[part]; ok {
+			return typeName(attribute.Type), attribute.Description, true
+		}
+		nested, ok := block.BlockTypes[part]
+		if !ok {
+			return "", "", false
+		}
+		block = nested.Block
+	}
+	return "block", block.Description, true
+}
+
+// typeName renders a schema type, e.g. ["map","string"] as "map of string"
+func typeName(typ interface{}) string {
+	switch t := typ.(type) {
+	case string:
+		return t
+	case []interface{}:
+		if len(t) == 0 {
+			return ""
+		}
+		kind, _ := t[0].(string)
+		if (kind == "list" || kind == "set" || kind == "map") && len(t) > 1 {
+			return kind + " of " + typeName(t[1])
+		}
+		return kind
+	}
+	return ""
+}
+
+// shortDescription keeps the first line of a description, truncated for readability
+func shortDescription(description string) string {
+	description = strings.TrimSpace(description)
+	if i := strings.IndexByte(description, '\n'); i >= 0 {
+		description = strings.TrimSpace(description[:i])
+	}
+	if len(description) > maxDescriptionLength {
+		description = textutil.Truncate(description, maxDescriptionLength) + "..."
+	}
+	return description
+}
@@ -0,0 +1,59 @@
+package terraform
+
+import "testing"
+
+func TestProviderSchemasAnnotate(t *testing.T) {
+	schemasJSON := `{
+  "format_version": "1.0",
+  "provider_schemas": {
+    "registry.terraform.io/hashicorp/aws": {
+      "resource_schemas": {
+        "aws_security_group": {
+          "block": {
+            "attributes": {
+              "tags": {"type": ["map", "string"], "description": "Map of tags to assign to the resource.\nMore details follow."},
+              "name": {"type": "string"}
+            },
+            "block_types": {
+              "ingress": {
+                "nesting_mode": "set",
+                "block": {
+                  "attributes": {
+                    "cidr_blocks": {"type": ["list", "string"], "description": "List of CIDR blocks."}
+                  }
+                }
+              }
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+	schemas, err := ParseProviderSchemas([]byte(schemasJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changes := []AttributeChange{
+		{Address: "module.net.aws_security_group.web", Action: ActionUpdate, Attribute: "tags.Owner"},
+		{Address: "aws_security_group.web", Action: ActionUpdate, Attribute: "ingress.0.cidr_blocks.1"},
+		{Address: "aws_security_group.web", Action: ActionUpdate, Attribute: "unknown"},
+		{Address: "aws_s3_bucket.logs", Action: ActionUpdate, Attribute: "acl"},
+	}
+	schemas.Annotate(changes)
+
+	expected := []struct{ typ, description string }{
+		{"map of string", "Map of tags to assign to the resource."},
+		{"list of string", "List of CIDR blocks."},
+		{"", ""},
+		{"", ""},
+	}
+	for i, want := range expected {
+		if changes[i].Type != want.typ || changes[i].Description != want.description {
+			t.Errorf("Change %d: expected (%q, %q), got (%q, %q)", i, want.typ, want.description,
+				changes[i].Type, changes[i].Description)
+		}
+	}
+}
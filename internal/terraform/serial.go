@@ -0,0 +1,46 @@
+package terraform
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// StateSerial identifies the current version of the project's state in its backend by its
+// lineage and serial, which terraform changes on every state write. The project is
+// initialized first.
+func StateSerial(projectPath string, opts Options) (string, error) {
+	opts.phase("reading state serial")
+	if _, err := runTerraformInit(projectPath, opts); err != nil {
+		return "", err
+	}
+
+	cmd := newTerraformCommand(projectPath, opts, "state", "pull")
+	var stdout, stderr strings.Builder
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("terraform state pull failed: %w: %s", err, stderr.String())
+	}
+	return parseStateSerial(stdout.String())
+}
+
+// parseStateSerial reads the lineage and serial of `terraform state pull` output
+func parseStateSerial(output string) (string, error) {
+	// A project without state yet pulls nothing
+	if strings.TrimSpace(output) == "" {
+		return "empty", nil
+	}
+
+	var state struct {
+		Lineage string `json:"lineage"`
+		Serial  *int64 `json:"serial"`
+	}
+	if err := json.Unmarshal([]byte(output), &state); err != nil {
+		return "", fmt.Errorf("failed to parse state: %w", err)
+	}
+	if state.Serial == nil {
+		return "", fmt.Errorf("state has no serial")
+	}
+	return fmt.Sprintf("%s/%d", state.Lineage, *state.Serial), nil
+}
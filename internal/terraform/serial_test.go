@@ -0,0 +1,20 @@
+package terraform
+
+import "testing"
+
+func TestParseStateSerial(t *testing.T) {
+	serial, err := parseStateSerial(`{"version":4,"terraform_version":"1.6.0","serial":42,"lineage":"3f2a","resources":[]}`)
+	if err != nil || serial != "3f2a/42" {
+		t.Errorf("Expected 3f2a/42, got %q, %v", serial, err)
+	}
+
+	if serial, err := parseStateSerial("\n"); err != nil || serial != "empty" {
+		t.Errorf("Expected an empty state, got %q, %v", serial, err)
+	}
+
+	for _, output := range []string{"not json", `{"lineage":"3f2a"}`} {
+		if _, err := parseStateSerial(output); err == nil {
+			t.Errorf("Expected an error for %q", output)
+		}
+	}
+}
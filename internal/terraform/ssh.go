@@ -0,0 +1,89 @@
+package terraform
+
+import (
+	"fmt"
+	"os/exec"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// sshFailureExitCode is the exit code ssh uses when the connection itself fails
+const sshFailureExitCode = 255
+
+// RemoteHost describes an SSH-reachable runner that executes terraform for a project
+type RemoteHost struct {
+	Host         string
+	User         string
+	Port         int
+	IdentityFile string
+	WorkDir      string
+	SSHOptions   []string
+}
+
+// target returns the ssh destination in user@host form
+func (r *RemoteHost) target() string {
+	if r.User != "" {
+		return r.User + "@" + r.Host
+	}
+	return r.Host
+}
+
+// sshArgs returns the ssh flags for connecting to the runner
+func (r *RemoteHost) sshArgs() []string {
+	// BatchMode prevents ssh from hanging on password or host key prompts
+	args := []string{"-T", "-o", "BatchMode=yes"}
+	if r.Port != 0 {
+		args = append(args, "-p", strconv.Itoa(r.Port))
+	}
+	if r.IdentityFile != "" {
+		args = append(args, "-i", r.IdentityFile)
+	}
+	for _, opt := range r.SSHOptions {
+		args = append(args, "-o", opt)
+	}
+	return append(args, r.target(), "sh", "-s")
+}
+
+// resolvePath resolves a project path against the runner work directory
+func (r *RemoteHost) resolvePath(projectPath string) string {
+	if path.IsAbs(projectPath) || r.WorkDir == "" {
+		return projectPath
+	}
+	return path.Join(r.WorkDir, projectPath)
+}
+
+// command builds an ssh command that runs the given program in the remote project directory.
+// The script is passed on stdin so credentials never appear in remote process listings.
+func (r *RemoteHost) command(projectPath string, env map[string]string, name string, args ...string) *exec.Cmd {
+	var script strings.Builder
+	script.WriteString("cd " + shellQuote(r.resolvePath(projectPath)) + " || exit 1\n")
+	script.WriteString("export TF_IN_AUTOMATION=true\n")
+	for _, key := range sortedKeys(env) {
+		script.WriteString(fmt.Sprintf("export %s=%s\n", key, shellQuote(env[key])))
+	}
+
+	quoted := []string{shellQuote(name)}
+	for _, arg := range args {
+		quoted = append(quoted, shellQuote(arg))
+	}
+	script.WriteString("exec " + strings.Join(quoted, " ") + "\n")
+
+	cmd := exec.Command("ssh", r.sshArgs()...)
+	cmd.Stdin = strings.NewReader(script.String())
+	return cmd
+}
+
+// removeFile deletes a file from the remote project directory
+func (r *RemoteHost) removeFile(projectPath string, name string) error {
+	cmd := r.command(projectPath, nil, "rm", "-f", name)
+	if output, err := runCommand(cmd, nil); err != nil {
+		return fmt.Errorf("failed to remove %s on %s: %w: %s", name, r.Host, err, output)
+	}
+	return nil
+}
+
+// shellQuote quotes a value for safe use in a POSIX shell script
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
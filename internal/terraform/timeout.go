@@ -0,0 +1,78 @@
+package terraform
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"sync/atomic"
+	"time"
+)
+
+// killWaitDelay bounds how long output is still read after a timed out command is killed, as
+// provider plugins it started may hold its output open
+const killWaitDelay = 10 * time.Second
+
+// TimeoutError reports a command killed for running longer than its phase allows
+type TimeoutError struct {
+	Phase   string // init, plan, preview, policy or credentials
+	Timeout time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("%s timed out after %s", e.Phase, e.Timeout)
+}
+
+// TimeoutPhase returns the phase that ran out of time when err is or wraps a TimeoutError,
+// or an empty string otherwise
+func TimeoutPhase(err error) string {
+	var timeoutErr *TimeoutError
+	if errors.As(err, &timeoutErr) {
+		return timeoutErr.Phase
+	}
+	return ""
+}
+
+// runCommandWithTimeout runs a command like runCommand, killing it when it runs longer than
+// the timeout (0 = unlimited). The error is a TimeoutError for the phase when it was killed.
+func runCommandWithTimeout(cmd *exec.Cmd, stream io.Writer, phase string, timeout time.Duration) (string, error) {
+	if timeout <= 0 {
+		return runCommand(cmd, stream)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if stream != nil {
+		cmd.Stdout = io.MultiWriter(&stdout, stream)
+		cmd.Stderr = io.MultiWriter(&stderr, stream)
+	}
+	err := waitWithTimeout(cmd, phase, timeout)
+	return stdout.String() + stderr.String(), err
+}
+
+// waitWithTimeout runs a command whose output is already set up, killing it when it runs
+// longer than the timeout (0 = unlimited) with a TimeoutError for the phase
+func waitWithTimeout(cmd *exec.Cmd, phase string, timeout time.Duration) error {
+	if timeout <= 0 {
+		return cmd.Run()
+	}
+	cmd.WaitDelay = killWaitDelay
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	var killed atomic.Bool
+	timer := time.AfterFunc(timeout, func() {
+		killed.Store(true)
+		_ = cmd.Process.Kill()
+	})
+	err := cmd.Wait()
+	timer.Stop()
+
+	if killed.Load() {
+		return &TimeoutError{Phase: phase, Timeout: timeout}
+	}
+	return err
+}
@@ -0,0 +1,58 @@
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// fakeTerraform puts a terraform script first on the PATH that hangs in the given command and
+// finds no changes otherwise
+func fakeTerraform(t *testing.T, hangIn string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake terraform script requires a POSIX shell")
+	}
+	bin := t.TempDir()
+	script := "#!/bin/sh\nif [ \"$1\" = \"" + hangIn + "\" ]; then exec sleep 30; fi\necho 'No changes.'\n"
+	if err := os.WriteFile(filepath.Join(bin, "terraform"), []byte(script), 0700); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestCheckDriftPhaseTimeouts(t *testing.T) {
+	for _, phase := range []string{"init", "plan"} {
+		t.Run(phase, func(t *testing.T) {
+			fakeTerraform(t, phase)
+			opts := Options{InitTimeout: time.Hour, PlanTimeout: time.Hour}
+			if phase == "init" {
+				opts.InitTimeout = 100 * time.Millisecond
+			} else {
+				opts.PlanTimeout = 100 * time.Millisecond
+			}
+
+			start := time.Now()
+			_, exitCode, err := CheckDriftWithOptions(t.TempDir(), opts)
+			if elapsed := time.Since(start); elapsed > 10*time.Second {
+				t.Fatalf("Expected the hung %s to be killed, took %v", phase, elapsed)
+			}
+			if exitCode != 1 || TimeoutPhase(err) != phase {
+				t.Fatalf("Expected a %s timeout, got exit code %d, %v", phase, exitCode, err)
+			}
+			if got := ClassifyError("", err); got != ErrorTimeout {
+				t.Errorf("Expected the failure to be classified as %s, got %s", ErrorTimeout, got)
+			}
+		})
+	}
+}
+
+func TestCheckDriftWithinTimeouts(t *testing.T) {
+	fakeTerraform(t, "none")
+	opts := Options{InitTimeout: time.Minute, PlanTimeout: time.Minute}
+	if _, exitCode, err := CheckDriftWithOptions(t.TempDir(), opts); exitCode != 0 || err != nil {
+		t.Errorf("Expected a clean check, got exit code %d, %v", exitCode, err)
+	}
+}
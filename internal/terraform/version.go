@@ -0,0 +1,168 @@
+package terraform
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// VersionMismatch is a drift check that failed because the project's state was written by a
+// newer Terraform than the one running the check. Either version is empty when unknown.
+type VersionMismatch struct {
+	StateVersion string
+	LocalVersion string
+}
+
+func (m *VersionMismatch) Error() string {
+	state, local := m.StateVersion, m.LocalVersion
+	if state == "" {
+		state = "a newer version"
+	}
+	if local == "" {
+		local = "unknown"
+	}
+	return fmt.Sprintf("state was written by Terraform %s, the local Terraform is %s", state, local)
+}
+
+// versionPatterns match terraform's errors about state or workspaces pinned to a newer
+// version. The first group is the state's version and the second, if any, the local one.
+var versionPatterns = []*regexp.Regexp{
+	// Terraform 0.12 and later
+	regexp.MustCompile(`created by Terraform v([0-9][^\s,;]*), which is newer than current v([0-9][^\s,;]*)`),
+	// Terraform 0.11 and earlier
+	regexp.MustCompile(`it is written by Terraform '([^']+)'`),
+	// Terraform Cloud and Enterprise workspaces pinned to a version
+	regexp.MustCompile(`version requirements for remote workspace \S+ \(([^)]+)\)`),
+	regexp.MustCompile(`(?i)remote workspace Terraform version "([^"]+)" does not match local Terraform version "([^"]+)"`),
+}
+
+// localVersionPattern finds the local version in the Terraform Cloud requirements error
+var localVersionPattern = regexp.MustCompile(`local Terraform version \(([^)]+)\)`)
+
+// ParseVersionMismatch reads the versions from terraform output reporting state written by a
+// newer Terraform. It returns nil if the output reports no such mismatch.
+func ParseVersionMismatch(output string) *VersionMismatch {
+	for _, pattern := range versionPatterns {
+		match := pattern.FindStringSubmatch(output)
+		if match == nil {
+			continue
+		}
+		mismatch := &VersionMismatch{StateVersion: strings.TrimSuffix(match[1], ".")}
+		if len(match) > 2 {
+			mismatch.LocalVersion = strings.TrimSuffix(match[2], ".")
+		} else if local := localVersionPattern.FindStringSubmatch(output); local != nil {
+			mismatch.LocalVersion = local[1]
+		}
+		return mismatch
+	}
+	return nil
+}
+
+// DetectVersionMismatch works out whether a failed drift check failed because the project's
+// state was written by a newer Terraform. Terraform's own error is used when the output has
+// one; otherwise the terraform_version recorded in a local state file is compared with the
+// local binary. It returns nil if no mismatch is found.
+func DetectVersionMismatch(projectPath string, opts Options, output string) *VersionMismatch {
+	if mismatch := ParseVersionMismatch(output); mismatch != nil {
+		if mismatch.LocalVersion == "" && opts.Fixture == "" {
+			mismatch.LocalVersion, _ = LocalVersion(projectPath, opts)
+		}
+		return mismatch
+	}
+
+	// State metadata is only readable for local state on this machine
+	if opts.Fixture != "" || opts.Remote != nil {
+		return nil
+	}
+	stateVersion, err := StateFileVersion(projectPath)
+	if err != nil || stateVersion == "" {
+		return nil
+	}
+	localVersion, err := LocalVersion(projectPath, opts)
+	if err != nil || CompareVersions(stateVersion, localVersion) <= 0 {
+		return nil
+	}
+	return &VersionMismatch{StateVersion: stateVersion, LocalVersion: localVersion}
+}
+
+// StateFileVersion returns the Terraform version that last wrote the project's local
+// terraform.tfstate, or "" if the project has no local state
+func StateFileVersion(projectPath string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(projectPath, "terraform.tfstate"))
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read state: %w", err)
+	}
+
+	var state struct {
+		TerraformVersion string `json:"terraform_version"`
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return "", fmt.Errorf("failed to parse state: %w", err)
+	}
+	return state.TerraformVersion, nil
+}
+
+// LocalVersion returns the version of the terraform binary drift checks of the project run
+func LocalVersion(projectPath string, opts Options) (string, error) {
+	cmd := newTerraformCommand(projectPath, opts, "version", "-json")
+	var stdout, stderr strings.Builder
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("terraform version failed: %w: %s", err, stderr.String())
+	}
+
+	var version struct {
+		TerraformVersion string `json:"terraform_version"`
+	}
+	if err := json.Unmarshal([]byte(stdout.String()), &version); err != nil {
+		return "", fmt.Errorf("failed to parse terraform version: %w", err)
+	}
+	return version.TerraformVersion, nil
+}
+
+// CompareVersions compares two dotted version numbers such as 1.5.7, returning -1, 0 or 1.
+// A leading v and pre-release or build suffixes are ignored.
+func CompareVersions(a, b string) int {
+	partsA, partsB := versionParts(a), versionParts(b)
+	for i := 0; i < len(partsA) || i < len(partsB); i++ {
+		var x, y int
+		if i < len(partsA) {
+			x = partsA[i]
+		}
+		if i < len(partsB) {
+			y = partsB[i]
+		}
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+	}
+	return 0
+}
+
+// versionParts returns the numeric components of a version
+func versionParts(version string) []int {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if i := strings.IndexAny(version, "-+"); i >= 0 {
+		version = version[:i]
+	}
+	var parts []int
+	for _, field := range strings.Split(version, ".") {
+		n, err := strconv.Atoi(field)
+		if err != nil {
+			break
+		}
+		parts = append(parts, n)
+	}
+	return parts
+}
@@ -0,0 +1,92 @@
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseVersionMismatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		state  string
+		local  string
+	}{
+		{
+			name: "state snapshot",
+			output: "Error: Error loading state: state snapshot was created by Terraform v1.9.0, which is newer " +
+				"than current v1.5.7; upgrade to Terraform v1.9.0 or greater to work with this state",
+			state: "1.9.0",
+			local: "1.5.7",
+		},
+		{
+			name: "legacy state",
+			output: "Terraform doesn't allow running any operations against a state that was written by a future " +
+				"Terraform version. The state is reporting it is written by Terraform '0.12.0'.",
+			state: "0.12.0",
+		},
+		{
+			name: "remote workspace",
+			output: "Error: Incompatible Terraform version\n\nThe local Terraform version (1.5.7) does not meet the " +
+				"version requirements for remote workspace acme/network (>= 1.6.0).",
+			state: ">= 1.6.0",
+			local: "1.5.7",
+		},
+		{
+			name:   "unrelated failure",
+			output: "Error: Unsupported argument\n\nAn argument named \"foo\" is not expected here.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mismatch := ParseVersionMismatch(tt.output)
+			if tt.state == "" {
+				if mismatch != nil {
+					t.Fatalf("Expected no mismatch, got %+v", mismatch)
+				}
+				return
+			}
+			if mismatch == nil || mismatch.StateVersion != tt.state || mismatch.LocalVersion != tt.local {
+				t.Errorf("Expected state %q and local %q, got %+v", tt.state, tt.local, mismatch)
+			}
+			if got := ClassifyError(tt.output, nil); got != ErrorVersion {
+				t.Errorf("Expected the failure to be classified as %s, got %s", ErrorVersion, got)
+			}
+		})
+	}
+}
+
+func TestStateFileVersion(t *testing.T) {
+	dir := t.TempDir()
+	if version, err := StateFileVersion(dir); err != nil || version != "" {
+		t.Fatalf("Expected no version without local state, got %q, %v", version, err)
+	}
+
+	state := `{"version": 4, "terraform_version": "1.9.2", "serial": 3, "lineage": "abc"}`
+	if err := os.WriteFile(filepath.Join(dir, "terraform.tfstate"), []byte(state), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if version, err := StateFileVersion(dir); err != nil || version != "1.9.2" {
+		t.Errorf("Expected 1.9.2, got %q, %v", version, err)
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.9.0", "1.5.7", 1},
+		{"1.5.7", "1.10.0", -1},
+		{"v1.6.0", "1.6", 0},
+		{"1.7.0-beta1", "1.7.0", 0},
+		{"0.12.31", "1.0.0", -1},
+	}
+	for _, tt := range tests {
+		if got := CompareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package terraform
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/terradrift-watcher/internal/textutil"
+)
+
+// maxWarningDetail bounds the recorded detail of a warning
+const maxWarningDetail = 300
+
+// Warning is a diagnostic terraform printed without failing, e.g. a deprecated argument
+type Warning struct {
+	Summary  string `json:"summary"`            // e.g. "Argument is deprecated"
+	Resource string `json:"resource,omitempty"` // Address of the resource it concerns, if any
+	Detail   string `json:"detail,omitempty"`
+}
+
+// String renders the warning on one line
+func (w Warning) String() string {
+	s := w.Summary
+	if w.Resource != "" {
+		s += " (" + w.Resource + ")"
+	}
+	if w.Detail != "" {
+		s += ": " + w.Detail
+	}
+	return s
+}
+
+// Key identifies the warning across scans. The detail and source location are left out so an
+// edit moving the offending line does not make the warning new.
+func (w Warning) Key() string {
+	return w.Summary + "|" + w.Resource
+}
+
+// warningResourceLine matches the resource of a diagnostic, e.g. "with aws_s3_bucket.logs,"
+var warningResourceLine = regexp.MustCompile(`^with (\S+),$`)
+
+// warningSourceLine matches the source location and snippet lines of a diagnostic
+var warningSourceLine = regexp.MustCompile(`^(on \S+ line \d+|\d+:|\(and \d+ more similar)`)
+
+// ParseWarnings extracts the warnings from terraform output, in both the boxed diagnostics of
+// Terraform 0.15 and later and the plain format of earlier versions. Repeated warnings are
+// returned once.
+func ParseWarnings(output string) []Warning {
+	var warnings []Warning
+	var current *Warning
+	var detail []string
+	seen := make(map[string]bool)
+
+	finish := func() {
+		if current == nil {
+			return
+		}
+		current.Detail = strings.Join(detail, " ")
+		if len(current.Detail) > maxWarningDetail {
+			current.Detail = textutil.Truncate(current.Detail, maxWarningDetail) + "..."
+		}
+		if !seen[current.Key()] {
+			seen[current.Key()] = true
+			warnings = append(warnings, *current)
+		}
+		current, detail = nil, nil
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "╵") {
+			finish()
+			continue
+		}
+		trimmed = strings.TrimSpace(strings.TrimPrefix(trimmed, "│"))
+
+		switch {
+		case strings.HasPrefix(trimmed, "Warning: "):
+			finish()
+			current = &Warning{Summary: strings.TrimSpace(strings.TrimPrefix(trimmed, "Warning: "))}
+		case current == nil:
+		case strings.HasPrefix(trimmed, "Error: "):
+			finish()
+		case trimmed == "":
+			// Plain diagnostics end at the first blank line after their detail
+			if len(detail) > 0 && !strings.HasPrefix(strings.TrimSpace(line), "│") {
+				finish()
+			}
+		case warningResourceLine.MatchString(trimmed):
+			current.Resource = warningResourceLine.FindStringSubmatch(trimmed)[1]
+		case warningSourceLine.MatchString(trimmed):
+		default:
+			detail = append(detail, trimmed)
+		}
+	}
+	finish()
+
+	return warnings
+}
@@ -0,0 +1,49 @@
+package terraform
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseWarnings(t *testing.T) {
+	output := `
+╷
+│ Warning: Argument is deprecated
+│ 
+│   with aws_s3_bucket.logs,
+│   on main.tf line 12, in resource "aws_s3_bucket" "logs":
+│   12:   acl = "private"
+│ 
+│ Use the aws_s3_bucket_acl resource instead
+│ 
+│ (and 2 more similar warnings elsewhere)
+╵
+╷
+│ Warning: Argument is deprecated
+│ 
+│   with aws_s3_bucket.logs,
+│   on main.tf line 14, in resource "aws_s3_bucket" "logs":
+│   14:   versioning {
+│ 
+│ Use the aws_s3_bucket_versioning resource instead
+╵
+
+Warning: Provider development overrides are in effect
+
+The following provider development overrides are set in the CLI configuration.
+
+No changes. Your infrastructure matches the configuration.
+`
+
+	expected := []Warning{
+		{Summary: "Argument is deprecated", Resource: "aws_s3_bucket.logs", Detail: "Use the aws_s3_bucket_acl resource instead"},
+		{Summary: "Provider development overrides are in effect", Detail: "The following provider development overrides are set in the CLI configuration."},
+	}
+	if got := ParseWarnings(output); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, got)
+	}
+
+	if got := ParseWarnings("No changes. Your infrastructure matches the configuration."); got != nil {
+		t.Errorf("Expected no warnings, got %+v", got)
+	}
+}
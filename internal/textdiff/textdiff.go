@@ -0,0 +1,131 @@
+// Package textdiff compares texts line by line.
+package textdiff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// op is one line of a diff: kept (' '), removed ('-') or added ('+')
+type op struct {
+	kind byte
+	line string
+}
+
+// Unified returns the differences between the lines of from and to in unified diff format,
+// with context unchanged lines around each change, or "" when the texts have the same lines
+func Unified(fromName, toName, from, to string, context int) string {
+	ops := diff(splitLines(from), splitLines(to))
+
+	// Lines of from and to before each op, for the hunk headers
+	fromLines := make([]int, len(ops)+1)
+	toLines := make([]int, len(ops)+1)
+	for k, o := range ops {
+		fromLines[k+1], toLines[k+1] = fromLines[k], toLines[k]
+		if o.kind != '+' {
+			fromLines[k+1]++
+		}
+		if o.kind != '-' {
+			toLines[k+1]++
+		}
+	}
+
+	var out strings.Builder
+	for start := 0; start < len(ops); {
+		first := start
+		for first < len(ops) && ops[first].kind == ' ' {
+			first++
+		}
+		if first == len(ops) {
+			break
+		}
+		// Changes separated by few unchanged lines share a hunk
+		last := first
+		for k := first + 1; k < len(ops) && k <= last+2*context+1; k++ {
+			if ops[k].kind != ' ' {
+				last = k
+			}
+		}
+		hunkStart := max(first-context, start)
+		hunkEnd := min(last+context+1, len(ops))
+
+		if out.Len() == 0 {
+			fmt.Fprintf(&out, "--- %s\n+++ %s\n", fromName, toName)
+		}
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n",
+			hunkRange(fromLines[hunkStart], fromLines[hunkEnd]-fromLines[hunkStart]),
+			hunkRange(toLines[hunkStart], toLines[hunkEnd]-toLines[hunkStart]))
+		for _, o := range ops[hunkStart:hunkEnd] {
+			out.WriteByte(o.kind)
+			out.WriteString(o.line)
+			out.WriteByte('\n')
+		}
+		start = hunkEnd
+	}
+	return out.String()
+}
+
+// hunkRange formats the lines of a hunk on one side, following the first count lines
+func hunkRange(before, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", before)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", before+1)
+	}
+	return fmt.Sprintf("%d,%d", before+1, count)
+}
+
+// diff returns the edit from a to b keeping their longest common subsequence of lines
+func diff(a, b []string) []op {
+	// common[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	common := make([][]int, len(a)+1)
+	for i := range common {
+		common[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else {
+				common[i][j] = max(common[i+1][j], common[i][j+1])
+			}
+		}
+	}
+
+	var ops []op
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, op{' ', a[i]})
+			i++
+			j++
+		case common[i+1][j] >= common[i][j+1]:
+			ops = append(ops, op{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, op{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = append(ops, op{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, op{'+', b[j]})
+	}
+	return ops
+}
+
+// splitLines splits text into lines without their line endings
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSuffix(line, "\r")
+	}
+	return lines
+}
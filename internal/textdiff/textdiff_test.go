@@ -0,0 +1,29 @@
+package textdiff
+
+import "testing"
+
+func TestUnified(t *testing.T) {
+	from := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\n"
+	to := "a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nk\n"
+	want := "--- loaded\n+++ disk\n" +
+		"@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n" +
+		"@@ -10 +10,2 @@\n j\n+k\n"
+	if got := Unified("loaded", "disk", from, to, 1); got != want {
+		t.Errorf("Unexpected diff:\n%s\nwant:\n%s", got, want)
+	}
+
+	// Changes close together share a hunk
+	want = "--- loaded\n+++ disk\n@@ -1,3 +1 @@\n-a\n b\n-c\n"
+	if got := Unified("loaded", "disk", "a\nb\nc\n", "b\n", 1); got != want {
+		t.Errorf("Expected one hunk for nearby changes, got:\n%s", got)
+	}
+
+	want = "--- loaded\n+++ disk\n@@ -0,0 +1 @@\n+a\n"
+	if got := Unified("loaded", "disk", "", "a\n", 3); got != want {
+		t.Errorf("Unexpected diff from an empty text:\n%s", got)
+	}
+
+	if got := Unified("loaded", "disk", "a\r\nb\r\n", "a\nb", 3); got != "" {
+		t.Errorf("Expected no differences across line endings, got:\n%s", got)
+	}
+}
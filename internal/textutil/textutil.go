@@ -0,0 +1,60 @@
+// Package textutil makes command output safe to summarize and send: it normalizes the encoding
+// of terraform's output and shortens text without splitting multi-byte characters.
+package textutil
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Ellipsis marks text shortened by ShortenLines
+const Ellipsis = "..."
+
+// ansiEscape matches terminal color and cursor sequences, which tools print despite -no-color
+// when a wrapper forces a terminal
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;?]*[A-Za-z]`)
+
+// Normalize returns output as clean UTF-8 text with LF line endings. Invalid byte sequences,
+// e.g. from a provider printing Latin-1, become U+FFFD; a byte order mark, escape sequences,
+// carriage returns and control characters other than tabs and newlines are removed.
+func Normalize(output string) string {
+	output = strings.ToValidUTF8(output, "\ufffd")
+	output = strings.TrimPrefix(output, "\ufeff")
+	output = ansiEscape.ReplaceAllString(output, "")
+	output = strings.ReplaceAll(output, "\r\n", "\n")
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 && r != '\n' && r != '\t' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, output)
+}
+
+// Truncate returns at most max bytes of s, cut before the character that would cross the
+// limit so the result stays valid UTF-8
+func Truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	if max <= 0 {
+		return ""
+	}
+	cut := max
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut]
+}
+
+// ShortenLines truncates every line of s longer than max bytes, marking it with Ellipsis, so
+// a single huge line such as an inline policy document cannot crowd out the rest
+func ShortenLines(s string, max int) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if len(line) > max {
+			lines[i] = Truncate(line, max) + Ellipsis
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,37 @@
+package textutil
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestNormalize(t *testing.T) {
+	input := "\ufeff\x1b[1mPlan:\x1b[0m 1 to change\r\nname = \"caf\xe9\"\x00\n\tdone"
+	expected := "Plan: 1 to change\nname = \"caf\ufffd\"\n\tdone"
+	if got := Normalize(input); got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	s := strings.Repeat("é", 10) // 2 bytes each
+	for max := 0; max <= len(s)+1; max++ {
+		got := Truncate(s, max)
+		if !utf8.ValidString(got) || len(got) > max || len(got) < max-1 {
+			t.Errorf("Truncate to %d gave %q (%d bytes)", max, got, len(got))
+		}
+	}
+	if got := Truncate("short", 10); got != "short" {
+		t.Errorf("Expected short text unchanged, got %q", got)
+	}
+}
+
+func TestShortenLines(t *testing.T) {
+	input := "Plan: 0 to add\n" + strings.Repeat("ü", 50) + "\nend"
+	got := ShortenLines(input, 21)
+	expected := "Plan: 0 to add\n" + strings.Repeat("ü", 10) + Ellipsis + "\nend"
+	if got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+}
@@ -0,0 +1,92 @@
+// Package winsvc runs the daemon as a native Windows service and manages its installation.
+// On other platforms every operation fails with ErrUnsupported.
+package winsvc
+
+import (
+	"errors"
+	"log"
+	"time"
+)
+
+// DefaultName is the service name used unless another is given
+const DefaultName = "terradrift-watcher"
+
+// stopTimeout is how long a stop waits for the service to exit, e.g. for a run to notice
+const stopTimeout = 30 * time.Second
+
+// ErrUnsupported is returned for service operations outside Windows
+var ErrUnsupported = errors.New("Windows services are only supported on Windows; on Linux use 'daemon --install-systemd-unit'")
+
+// Service describes an installed service
+type Service struct {
+	Name        string
+	DisplayName string
+	Description string
+	Executable  string   // Absolute path of the terradrift-watcher binary
+	Args        []string // Arguments the service is started with
+}
+
+// Runner runs the service until stop is closed
+type Runner func(stop <-chan struct{}) error
+
+// request is a control request of the service control manager the service acts on
+type request int
+
+const (
+	requestInterrogate request = iota // Report the current state again
+	requestStop                       // Stop or shutdown
+)
+
+// state is a service state reported to the service control manager
+type state int
+
+const (
+	stateStartPending state = iota
+	stateRunning
+	stateStopPending
+)
+
+// status is a state change reported to the service control manager
+type status struct {
+	state    state
+	waitHint time.Duration // Expected time until the next state change, while pending
+}
+
+// serve runs the service's control loop, independent of the service control manager so it can
+// be tested on every platform: run is started and reported running, and a stop request closes
+// its stop channel and waits up to timeout for it to return. It returns the service's exit
+// code, 1 when run failed.
+func serve(run Runner, requests <-chan request, report func(status), timeout time.Duration) uint32 {
+	report(status{state: stateStartPending})
+
+	stop := make(chan struct{})
+	done := make(chan error, 1)
+	go func() { done <- run(stop) }()
+	current := status{state: stateRunning}
+	report(current)
+
+	for {
+		select {
+		case err := <-done:
+			if err != nil {
+				log.Printf("ERROR: %v", err)
+				return 1
+			}
+			return 0
+		case req := <-requests:
+			switch req {
+			case requestInterrogate:
+				report(current)
+			case requestStop:
+				report(status{state: stateStopPending, waitHint: timeout})
+				close(stop)
+				select {
+				case <-done:
+				case <-time.After(timeout):
+					log.Printf("WARNING: Service did not stop within %v, interrupting the run in progress", timeout)
+				}
+				return 0
+			}
+		}
+	}
+}
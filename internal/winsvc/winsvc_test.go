@@ -0,0 +1,63 @@
+package winsvc
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestServe(t *testing.T) {
+	const timeout = 100 * time.Millisecond
+	started := []status{{state: stateStartPending}, {state: stateRunning}}
+	stopping := status{state: stateStopPending, waitHint: timeout}
+
+	// untilStopped runs until the service is stopped
+	untilStopped := func(stop <-chan struct{}) error {
+		<-stop
+		return nil
+	}
+	tests := []struct {
+		name     string
+		run      Runner
+		requests []request
+		expected []status
+		code     uint32
+	}{
+		{"stop", untilStopped, []request{requestStop}, append(started, stopping), 0},
+		{"interrogate", untilStopped, []request{requestInterrogate, requestStop},
+			append(started, status{state: stateRunning}, stopping), 0},
+		{"run fails", func(<-chan struct{}) error { return errors.New("bad configuration") }, nil, started, 1},
+		{"run returns", func(<-chan struct{}) error { return nil }, nil, started, 0},
+		// A run that does not notice the stop is abandoned after the timeout
+		{"stop timeout", func(<-chan struct{}) error {
+			time.Sleep(time.Hour)
+			return nil
+		}, []request{requestStop}, append(started, stopping), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := make(chan request, len(tt.requests))
+			for _, req := range tt.requests {
+				requests <- req
+			}
+			var reported []status
+			start := time.Now()
+			code := serve(tt.run, requests, func(s status) { reported = append(reported, s) }, timeout)
+
+			if code != tt.code {
+				t.Errorf("Expected exit code %d, got %d", tt.code, code)
+			}
+			if elapsed := time.Since(start); elapsed > 5*timeout {
+				t.Errorf("Expected the service to stop within the timeout, took %v", elapsed)
+			}
+			if len(reported) != len(tt.expected) {
+				t.Fatalf("Expected states %+v, got %+v", tt.expected, reported)
+			}
+			for i := range reported {
+				if reported[i] != tt.expected[i] {
+					t.Errorf("Expected state %+v at %d, got %+v", tt.expected[i], i, reported[i])
+				}
+			}
+		})
+	}
+}
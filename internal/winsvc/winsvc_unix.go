@@ -0,0 +1,33 @@
+//go:build !windows
+
+package winsvc
+
+// IsService reports whether the process was started by the service control manager
+func IsService() (bool, error) {
+	return false, nil
+}
+
+// Run runs the daemon as a Windows service
+func Run(name string, run Runner) error {
+	return ErrUnsupported
+}
+
+// Install registers the service with Windows
+func Install(s Service) error {
+	return ErrUnsupported
+}
+
+// Uninstall removes the service
+func Uninstall(name string) error {
+	return ErrUnsupported
+}
+
+// Start starts the installed service
+func Start(name string) error {
+	return ErrUnsupported
+}
+
+// Stop stops the service
+func Stop(name string) error {
+	return ErrUnsupported
+}
@@ -0,0 +1,151 @@
+//go:build windows
+
+package winsvc
+
+import (
+	"fmt"
+	"time"
+
+	"golang.org/x/sys/windows/svc"
+	"golang.org/x/sys/windows/svc/mgr"
+)
+
+// IsService reports whether the process was started by the service control manager
+func IsService() (bool, error) {
+	return svc.IsWindowsService()
+}
+
+// Run reports to the service control manager while run executes, and closes its stop channel
+// when the service is stopped. A run still busy after stopTimeout is abandoned.
+func Run(name string, run Runner) error {
+	return svc.Run(name, handler(run))
+}
+
+// handler adapts a Runner to the service control manager
+type handler Runner
+
+// Execute implements svc.Handler, translating between the service control manager and serve
+func (h handler) Execute(args []string, requests <-chan svc.ChangeRequest, changes chan<- svc.Status) (bool, uint32) {
+	served := make(chan struct{})
+	defer close(served)
+	translated := make(chan request)
+	go func() {
+		for req := range requests {
+			var r request
+			switch req.Cmd {
+			case svc.Interrogate:
+				r = requestInterrogate
+			case svc.Stop, svc.Shutdown:
+				r = requestStop
+			default:
+				continue
+			}
+			select {
+			case translated <- r:
+			case <-served:
+				return
+			}
+		}
+	}()
+
+	report := func(s status) {
+		switch s.state {
+		case stateStartPending:
+			changes <- svc.Status{State: svc.StartPending}
+		case stateRunning:
+			changes <- svc.Status{State: svc.Running, Accepts: svc.AcceptStop | svc.AcceptShutdown}
+		case stateStopPending:
+			changes <- svc.Status{State: svc.StopPending, WaitHint: uint32(s.waitHint / time.Millisecond)}
+		}
+	}
+	code := serve(Runner(h), translated, report, stopTimeout)
+	return code != 0, code
+}
+
+// Install registers the service to start automatically with Windows and to be restarted
+// when it fails
+func Install(s Service) error {
+	m, err := mgr.Connect()
+	if err != nil {
+		return fmt.Errorf("failed to connect to the service control manager: %w", err)
+	}
+	defer m.Disconnect()
+
+	if existing, err := m.OpenService(s.Name); err == nil {
+		existing.Close()
+		return fmt.Errorf("service %s is already installed", s.Name)
+	}
+
+	service, err := m.CreateService(s.Name, s.Executable, mgr.Config{
+		DisplayName: s.DisplayName,
+		Description: s.Description,
+		StartType:   mgr.StartAutomatic,
+	}, s.Args...)
+	if err != nil {
+		return fmt.Errorf("failed to create service %s: %w", s.Name, err)
+	}
+	defer service.Close()
+
+	restart := []mgr.RecoveryAction{{Type: mgr.ServiceRestart, Delay: 30 * time.Second}}
+	if err := service.SetRecoveryActions(restart, uint32((24 * time.Hour).Seconds())); err != nil {
+		return fmt.Errorf("failed to set recovery actions of service %s: %w", s.Name, err)
+	}
+	return nil
+}
+
+// Uninstall removes the service. A running service is removed once it stops.
+func Uninstall(name string) error {
+	return withService(name, func(service *mgr.Service) error {
+		if err := service.Delete(); err != nil {
+			return fmt.Errorf("failed to remove service %s: %w", name, err)
+		}
+		return nil
+	})
+}
+
+// Start starts the installed service
+func Start(name string) error {
+	return withService(name, func(service *mgr.Service) error {
+		if err := service.Start(); err != nil {
+			return fmt.Errorf("failed to start service %s: %w", name, err)
+		}
+		return nil
+	})
+}
+
+// Stop stops the service and waits for it to exit
+func Stop(name string) error {
+	return withService(name, func(service *mgr.Service) error {
+		status, err := service.Control(svc.Stop)
+		if err != nil {
+			return fmt.Errorf("failed to stop service %s: %w", name, err)
+		}
+		deadline := time.Now().Add(stopTimeout + 10*time.Second)
+		for status.State != svc.Stopped {
+			if time.Now().After(deadline) {
+				return fmt.Errorf("service %s did not stop in time", name)
+			}
+			time.Sleep(500 * time.Millisecond)
+			if status, err = service.Query(); err != nil {
+				return fmt.Errorf("failed to query service %s: %w", name, err)
+			}
+		}
+		return nil
+	})
+}
+
+// withService opens the named service for f
+func withService(name string, f func(*mgr.Service) error) error {
+	m, err := mgr.Connect()
+	if err != nil {
+		return fmt.Errorf("failed to connect to the service control manager: %w", err)
+	}
+	defer m.Disconnect()
+
+	service, err := m.OpenService(name)
+	if err != nil {
+		return fmt.Errorf("service %s is not installed: %w", name, err)
+	}
+	defer service.Close()
+	return f(service)
+}
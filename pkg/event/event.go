@@ -0,0 +1,156 @@
+// Package event defines the JSON events TerraDrift Watcher sends to webhook and stdout
+// notifiers and to run webhooks. Consumers should check SchemaVersion before decoding:
+// fields are only ever added within a major version, and a breaking change bumps it.
+package event
+
+import "time"
+
+// SchemaVersion is the version of the DriftEvent format produced by this build
+const SchemaVersion = "1.0"
+
+// Event types
+const (
+	TypeDriftDetected  = "drift.detected"
+	TypeDriftEscalated = "drift.escalated"
+	TypeDriftResolved  = "drift.resolved"
+	TypeScanFailed     = "scan.failed"
+	TypeScanWarnings   = "scan.warnings"
+	TypeScanPolicy     = "scan.policy"
+)
+
+// DriftEvent reports drift detected in one project or its resolution, a failed scan of it,
+// new terraform warnings printed by its plan, or new policy findings in its configuration
+type DriftEvent struct {
+	SchemaVersion string    `json:"schema_version"`
+	Type          string    `json:"type"`
+	Time          time.Time `json:"time"`
+
+	Project     string   `json:"project"`
+	Description string   `json:"description,omitempty"`
+	RunbookURL  string   `json:"runbook_url,omitempty"`
+	Owners      []string `json:"owners,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+
+	// Outputs are the terraform outputs captured at the project's last clean plan
+	Outputs map[string]string `json:"outputs,omitempty"`
+
+	// Summary is terraform's plan summary, e.g. "Plan: 0 to add, 1 to change, 0 to destroy."
+	Summary string `json:"summary"`
+
+	// Fingerprint identifies the drift: it stays the same while the same changes are found,
+	// so consumers can correlate and deduplicate events across runs and channels
+	Fingerprint string `json:"fingerprint,omitempty"`
+
+	// DriftSince is when the current continuous drift was first detected
+	DriftSince *time.Time `json:"drift_since,omitempty"`
+
+	// Incident is the stable ID of the continuous drift, shared by its drift.detected,
+	// drift.escalated and drift.resolved events
+	Incident string `json:"incident,omitempty"`
+
+	// Escalation names the escalation rule that produced the event (type drift.escalated)
+	Escalation string `json:"escalation,omitempty"`
+
+	// ErrorCategory says why the scan failed (type scan.failed): auth, backend, provider,
+	// syntax, timeout, lock, version or unknown. Summary then holds the error.
+	ErrorCategory string `json:"error_category,omitempty"`
+
+	// Warnings lists the terraform warnings new since the project's previous plan (type
+	// scan.warnings). Summary then lists them too.
+	Warnings []Warning `json:"warnings,omitempty"`
+
+	// PolicyFindings lists the static analysis findings new since the project's previous scan
+	// (type scan.policy). Summary then lists them too.
+	PolicyFindings []PolicyFinding `json:"policy_findings,omitempty"`
+
+	// Changes lists the changed attributes; sensitive values are redacted
+	Changes []Change `json:"changes,omitempty"`
+
+	// DiffURL links to a color-coded HTML page of the plan, when html_diffs has a base_url
+	DiffURL string `json:"diff_url,omitempty"`
+
+	// Metadata identifies the watcher instance that produced the event, e.g.
+	// {"environment": "prod", "cluster": "eu-1"}
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// Change is one attribute changed outside of terraform
+type Change struct {
+	Address   string `json:"address"`
+	Action    string `json:"action"`
+	Attribute string `json:"attribute,omitempty"`
+	Before    string `json:"before,omitempty"`
+	After     string `json:"after,omitempty"`
+	URL       string `json:"url,omitempty"` // The resource in its cloud console, when known
+}
+
+// Warning is a warning terraform printed without failing the plan, e.g. a deprecated argument
+type Warning struct {
+	Summary  string `json:"summary"`
+	Resource string `json:"resource,omitempty"`
+	Detail   string `json:"detail,omitempty"`
+}
+
+// PolicyFinding is a problem a static analysis tool such as tflint or checkov found in a
+// project's configuration
+type PolicyFinding struct {
+	Tool     string `json:"tool"`
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"` // low, medium, high or critical
+	Resource string `json:"resource,omitempty"`
+	File     string `json:"file,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Message  string `json:"message,omitempty"`
+}
+
+// Run event types
+const (
+	TypeRunStarted  = "run.started"
+	TypeRunFinished = "run.finished"
+)
+
+// Outcomes of a finished run
+const (
+	RunSucceeded = "succeeded"
+	RunFailed    = "failed" // A scan or notification failed, or the run could not start
+	RunPaused    = "paused" // Scanning was paused, so nothing was scanned
+)
+
+// RunEvent reports the start or end of a run to run webhooks, so schedulers can track the
+// watcher's health and chain jobs on it. Both events of a run carry the same StartedAt.
+type RunEvent struct {
+	SchemaVersion string    `json:"schema_version"`
+	Type          string    `json:"type"`
+	Time          time.Time `json:"time"`
+	StartedAt     time.Time `json:"started_at"`
+
+	// Requested lists the projects the run was limited to, e.g. by a trigger
+	Requested []string `json:"requested,omitempty"`
+
+	// Metadata identifies the watcher instance that ran, as on DriftEvent
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	// The remaining fields are set on run.finished
+	FinishedAt      *time.Time `json:"finished_at,omitempty"`
+	DurationSeconds float64    `json:"duration_seconds,omitempty"`
+	Outcome         string     `json:"outcome,omitempty"` // succeeded, failed or paused
+	Error           string     `json:"error,omitempty"`
+	DriftFound      bool       `json:"drift_found,omitempty"`
+
+	// Counts holds the number of projects per status, e.g. {"clean": 12, "drifted": 1}
+	Counts   map[string]int `json:"counts,omitempty"`
+	Projects []RunProject   `json:"projects,omitempty"`
+}
+
+// RunProject is the result of one project in a finished run
+type RunProject struct {
+	Project string `json:"project"`
+
+	// Status is clean, noise, drifted, error, version_mismatch, skipped, not_scanned or not_due
+	Status          string  `json:"status"`
+	Summary         string  `json:"summary,omitempty"`
+	Fingerprint     string  `json:"fingerprint,omitempty"`
+	ErrorCategory   string  `json:"error_category,omitempty"`
+	TimeoutPhase    string  `json:"timeout_phase,omitempty"` // init or plan, on a timeout
+	DurationSeconds float64 `json:"duration_seconds,omitempty"`
+}